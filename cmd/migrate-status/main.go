@@ -0,0 +1,38 @@
+// migrate-status は支払い状態と提供の進行を1つのステータスで管理していた注文を、新しい形式で保存し直すコマンドです。
+// 読み取り時の移行は保存された値を変更しないため、デプロイ後に一度実行してステータスでの検索に旧形式の注文が含まれるようにします。
+// 移行済みの注文は変更しないため、繰り返し実行できます。
+//
+// 使い方:
+//
+//	APP_ENV=production PROJECT_ID=... go run ./cmd/migrate-status
+package main
+
+import (
+	"backend/repositories"
+	"backend/usecases"
+	"context"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/firestore"
+)
+
+func main() {
+	if err := run(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	repositories.LoadConfig()
+	db, err := firestore.NewClient(ctx, os.Getenv("PROJECT_ID"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrated, err := usecases.New(db).MigrateLegacyOrderStatuses(ctx)
+	fmt.Printf("migrated %d orders\n", migrated)
+	return err
+}
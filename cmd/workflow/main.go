@@ -0,0 +1,56 @@
+// workflow は組み込みの注文のワークフロー（状態遷移の定義）を図またはJSONで書き出すコマンドです。
+//
+// 使い方:
+//
+//	go run ./cmd/workflow -list
+//	go run ./cmd/workflow -name counter -format mermaid
+//	go run ./cmd/workflow -format dot -o workflow.dot
+//
+// 店舗が選択しているワークフローは、管理APIの GET /store/workflow?store_id=... で取得できます。
+package main
+
+import (
+	"backend/models"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	name := flag.String("name", "", "ワークフローの名前（空の場合は標準: "+models.WorkflowDefault+"）")
+	format := flag.String("format", string(models.WorkflowFormatJSON), "書き出す形式（json, mermaid, dot）")
+	output := flag.String("o", "", "書き出すファイル（空の場合は標準出力）")
+	list := flag.Bool("list", false, "選択できるワークフローの名前を表示する")
+	flag.Parse()
+
+	if err := run(*name, models.WorkflowFormat(*format), *output, *list); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(name string, format models.WorkflowFormat, output string, list bool) error {
+	if list {
+		fmt.Println(strings.Join(models.WorkflowNames(), "\n"))
+		return nil
+	}
+
+	workflow, err := models.LookupWorkflow(name)
+	if err != nil {
+		return err
+	}
+	body, err := workflow.Export(format)
+	if err != nil {
+		return err
+	}
+	if format == "" || format == models.WorkflowFormatJSON {
+		body = append(body, '\n')
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	return os.WriteFile(output, body, 0o644)
+}
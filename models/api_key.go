@@ -0,0 +1,186 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIKeyIDPrefix はAPIキーエンティティのIDを生成する際のプレフィックスです。
+const APIKeyIDPrefix = "apikey_"
+
+// APIKeyTokenPrefix は発行するAPIキー文字列の先頭に付与するプレフィックスです。
+// Authorization ヘッダーのBearerトークンがAPIキーかマネージャーJWTかの判別に使用します。
+const APIKeyTokenPrefix = "posk_"
+
+// apiKeyDisplayLength は一覧表示でキーを識別するために保持する先頭の文字数です。
+const apiKeyDisplayLength = len(APIKeyTokenPrefix) + 8
+
+// APIKeyScope はAPIキーが操作できる範囲を表します。
+type APIKeyScope string
+
+const (
+	// APIKeyScopeStore は単一店舗のみ操作できるキーです。
+	APIKeyScopeStore APIKeyScope = "store"
+	// APIKeyScopeOrganization は組織内の全店舗（Store.OrganizationID が一致する店舗）を操作できるキーです。
+	APIKeyScopeOrganization APIKeyScope = "organization"
+)
+
+var (
+	ErrInvalidAPIKey       = errors.New("APIキーが不正です")
+	ErrAPIKeyNotFound      = errors.New("APIキーが見つかりません")
+	ErrAPIKeyIDRequired    = errors.New("APIキーのIDは必須です")
+	ErrAPIKeyExpired       = errors.New("APIキーの有効期限が切れています")
+	ErrAPIKeyRevoked       = errors.New("APIキーは無効化されています")
+	ErrInvalidAPIKeyScope  = errors.New("APIキーのスコープが不正です")
+	ErrAPIKeyNameRequired  = errors.New("APIキーの名前は必須です")
+	ErrPermissionsRequired = errors.New("APIキーには1つ以上の権限が必要です")
+	ErrPermissionDenied    = errors.New("この操作を行う権限がありません")
+)
+
+// APIKey は外部連携（会計エクスポート、デリバリー連携、BI等）用のAPIキーを表します。
+// キー本体は保存せず、SHA-256ハッシュと識別用の先頭文字列（Prefix）のみを保持します。
+// OrganizationID は発行したマネージャーのメールアドレスです。
+// OrganizationStoreIDs は組織スコープのキーが操作できる店舗（組織に属する店舗）のIDで、認証時に設定し保存しません。
+type APIKey struct {
+	ID             string
+	Name           string
+	Prefix         string
+	HashedKey      string
+	Scope          APIKeyScope
+	StoreID        string
+	OrganizationID string
+	Permissions    []Permission
+	ExpiresAt      time.Time
+	LastUsedAt     time.Time
+	RevokedAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	OrganizationStoreIDs []string
+}
+
+// NewAPIKey は新しいAPIキーを作成し、キー本体を返します。
+// キー本体は作成時にのみ参照でき、以降は復元できません。
+// expiresAt がゼロ値の場合は無期限です。
+func NewAPIKey(name string, scope APIKeyScope, storeID, organizationID string, permissions []Permission, expiresAt time.Time) (*APIKey, string, error) {
+	key := &APIKey{
+		ID:             GenerateUniqueID(APIKeyIDPrefix),
+		Name:           strings.TrimSpace(name),
+		Scope:          scope,
+		StoreID:        storeID,
+		OrganizationID: organizationID,
+		Permissions:    permissions,
+		ExpiresAt:      expiresAt,
+	}
+	if err := key.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	raw := APIKeyTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	now := time.Now().UTC()
+	key.Prefix = raw[:apiKeyDisplayLength]
+	key.HashedKey = HashAPIKey(raw)
+	key.CreatedAt = now
+	key.UpdatedAt = now
+	return key, raw, nil
+}
+
+// Validate はAPIキーの設定を検証します。
+func (k *APIKey) Validate() error {
+	if k.Name == "" {
+		return ErrAPIKeyNameRequired
+	}
+	switch k.Scope {
+	case APIKeyScopeStore:
+		if k.StoreID == "" {
+			return ErrStoreIDRequired
+		}
+	case APIKeyScopeOrganization:
+		if k.StoreID != "" {
+			return ErrInvalidAPIKeyScope
+		}
+	default:
+		return ErrInvalidAPIKeyScope
+	}
+	if len(k.Permissions) == 0 {
+		return ErrPermissionsRequired
+	}
+	for _, permission := range k.Permissions {
+		if !permission.IsValid() {
+			return ErrInvalidPermission
+		}
+	}
+	return nil
+}
+
+// HashAPIKey はAPIキー本体のSHA-256ハッシュを16進文字列で返します。
+// キー本体は十分なエントロピーを持つため、パスワードのようなストレッチングは行いません。
+func HashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKeyToken はBearerトークンがAPIキーの形式かどうかを返します。
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, APIKeyTokenPrefix)
+}
+
+// IsRevoked は無効化済みかどうかを返します。
+func (k *APIKey) IsRevoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Verify は now 時点でAPIキーが使用可能かどうかを確認します。
+func (k *APIKey) Verify(now time.Time) error {
+	if k.IsRevoked() {
+		return ErrAPIKeyRevoked
+	}
+	if !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// Revoke はAPIキーを無効化します。
+func (k *APIKey) Revoke(now time.Time) {
+	if k.IsRevoked() {
+		return
+	}
+	k.RevokedAt = now
+	k.UpdatedAt = now
+}
+
+// HasPermission は指定された権限を持つかどうかを返します。
+func (k *APIKey) HasPermission(permission Permission) bool {
+	return HasPermission(k.Permissions, permission)
+}
+
+// AllowsStore は指定された店舗を操作できるかどうかを返します。
+// 組織スコープのキーは、キーを発行した組織に属する店舗のみ操作できます。
+func (k *APIKey) AllowsStore(storeID string) bool {
+	if k.Scope == APIKeyScopeOrganization {
+		return slices.Contains(k.OrganizationStoreIDs, storeID)
+	}
+	return k.StoreID == storeID
+}
+
+// MarkUsed は最終使用日時を記録します。
+// 書き込み回数を抑えるため、前回の記録から interval 以上経過した場合のみ更新し、更新した場合に true を返します。
+func (k *APIKey) MarkUsed(now time.Time, interval time.Duration) bool {
+	if !k.LastUsedAt.IsZero() && now.Sub(k.LastUsedAt) < interval {
+		return false
+	}
+	k.LastUsedAt = now
+	return true
+}
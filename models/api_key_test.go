@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, raw, err := NewAPIKey("Accounting export", APIKeyScopeStore, "store_123", "manager@example.com", []Permission{PermissionOrdersRead}, time.Time{})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key.ID, APIKeyIDPrefix))
+	assert.True(t, IsAPIKeyToken(raw))
+	assert.True(t, strings.HasPrefix(raw, key.Prefix), "識別用の先頭文字列はキー本体と一致する")
+	assert.Len(t, key.Prefix, apiKeyDisplayLength)
+	assert.Equal(t, HashAPIKey(raw), key.HashedKey)
+	assert.NotContains(t, key.HashedKey, raw, "キー本体は保持しない")
+
+	_, other, err := NewAPIKey("Accounting export", APIKeyScopeStore, "store_123", "manager@example.com", []Permission{PermissionOrdersRead}, time.Time{})
+	require.NoError(t, err)
+	assert.NotEqual(t, raw, other)
+}
+
+func TestAPIKey_Validate(t *testing.T) {
+	read := []Permission{PermissionOrdersRead}
+	testCases := []struct {
+		name        string
+		keyName     string
+		scope       APIKeyScope
+		storeID     string
+		permissions []Permission
+		expected    error
+	}{
+		{name: "店舗スコープ", keyName: "BI", scope: APIKeyScopeStore, storeID: "store_1", permissions: read},
+		{name: "組織スコープ", keyName: "BI", scope: APIKeyScopeOrganization, permissions: read},
+		{name: "名前なし", keyName: " ", scope: APIKeyScopeOrganization, permissions: read, expected: ErrAPIKeyNameRequired},
+		{name: "店舗IDなしの店舗スコープ", keyName: "BI", scope: APIKeyScopeStore, permissions: read, expected: ErrStoreIDRequired},
+		{name: "店舗IDありの組織スコープ", keyName: "BI", scope: APIKeyScopeOrganization, storeID: "store_1", permissions: read, expected: ErrInvalidAPIKeyScope},
+		{name: "不明なスコープ", keyName: "BI", scope: "global", permissions: read, expected: ErrInvalidAPIKeyScope},
+		{name: "権限なし", keyName: "BI", scope: APIKeyScopeOrganization, expected: ErrPermissionsRequired},
+		{name: "不明な権限", keyName: "BI", scope: APIKeyScopeOrganization, permissions: []Permission{"orders:delete"}, expected: ErrInvalidPermission},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := NewAPIKey(tc.keyName, tc.scope, tc.storeID, "manager@example.com", tc.permissions, time.Time{})
+			if tc.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestAPIKey_Verify(t *testing.T) {
+	now := time.Now().UTC()
+
+	key := &APIKey{}
+	assert.NoError(t, key.Verify(now), "有効期限なし")
+
+	key.ExpiresAt = now.Add(time.Hour)
+	assert.NoError(t, key.Verify(now))
+	assert.ErrorIs(t, key.Verify(now.Add(time.Hour)), ErrAPIKeyExpired)
+
+	key.Revoke(now)
+	assert.ErrorIs(t, key.Verify(now), ErrAPIKeyRevoked)
+}
+
+func TestAPIKey_Access(t *testing.T) {
+	storeKey := &APIKey{Scope: APIKeyScopeStore, StoreID: "store_1", Permissions: []Permission{PermissionOrdersRead}}
+	assert.True(t, storeKey.AllowsStore("store_1"))
+	assert.False(t, storeKey.AllowsStore("store_2"))
+	assert.True(t, storeKey.HasPermission(PermissionOrdersRead))
+	assert.False(t, storeKey.HasPermission(PermissionStoresWrite))
+
+	orgKey := &APIKey{Scope: APIKeyScopeOrganization, OrganizationStoreIDs: []string{"store_1", "store_2"}}
+	assert.True(t, orgKey.AllowsStore("store_2"))
+	assert.False(t, orgKey.AllowsStore("store_3"), "他の組織の店舗は操作できない")
+}
+
+func TestAPIKey_MarkUsed(t *testing.T) {
+	now := time.Now().UTC()
+	key := &APIKey{}
+
+	assert.True(t, key.MarkUsed(now, time.Minute))
+	assert.False(t, key.MarkUsed(now.Add(30*time.Second), time.Minute), "間隔内は更新しない")
+	assert.Equal(t, now, key.LastUsedAt)
+	assert.True(t, key.MarkUsed(now.Add(time.Minute), time.Minute))
+}
@@ -0,0 +1,535 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// BillSplitPrefix は割り勘（会計の分割）のIDのプレフィックスです。
+	BillSplitPrefix = "split_"
+	// SubBillPrefix は分割した個別の伝票のIDのプレフィックスです。
+	SubBillPrefix = "subbill_"
+)
+
+// SplitMethod は会計の分割方法です。
+type SplitMethod string
+
+const (
+	// SplitEven は合計金額を人数で均等に分割します。
+	SplitEven SplitMethod = "even"
+	// SplitByLines は支払う人ごとに注文の明細（商品、チャージ）を選択して分割します。
+	SplitByLines SplitMethod = "lines"
+	// SplitByAmounts は支払う人ごとに任意の金額を指定して分割します。
+	SplitByAmounts SplitMethod = "amounts"
+)
+
+// BillSplitStatus は割り勘の状態です。
+type BillSplitStatus string
+
+const (
+	// BillSplitOpen は支払いが完了していない伝票、または未割り当ての残額がある状態です。
+	BillSplitOpen BillSplitStatus = "open"
+	// BillSplitSettled は全ての伝票の支払いが完了し、会計全体が精算済みの状態です。
+	BillSplitSettled BillSplitStatus = "settled"
+	// BillSplitVoided は支払い前に分割をやり直したため無効になった状態です。
+	BillSplitVoided BillSplitStatus = "voided"
+)
+
+var (
+	ErrCheckEmpty              = errors.New("会計の対象となる注文がありません")
+	ErrInvalidSplitMethod      = errors.New("会計の分割方法が不正です")
+	ErrInvalidSplitParts       = errors.New("均等に分割する人数は2以上で指定してください")
+	ErrSplitPayersRequired     = errors.New("支払う人を指定してください")
+	ErrSplitLineNotFound       = errors.New("分割する明細が会計に含まれていません")
+	ErrSplitLineAssigned       = errors.New("同じ明細を複数の人に割り当てることはできません")
+	ErrInvalidSplitAmount      = errors.New("分割する金額は0より大きい値を指定してください")
+	ErrSplitAmountExceedsTotal = errors.New("分割する金額の合計が会計の合計金額を超えています")
+	ErrBillSplitNotFound       = errors.New("割り勘が見つかりません")
+	ErrBillSplitInProgress     = errors.New("支払い済みの伝票がある割り勘は、やり直しできません")
+	ErrBillSplitClosed         = errors.New("割り勘はすでに精算済みまたは無効です")
+	ErrSubBillNotFound         = errors.New("伝票が見つかりません")
+	ErrSubBillAlreadyPaid      = errors.New("伝票はすでに支払い済みです")
+	ErrNoRemainder             = errors.New("未割り当ての残額がありません")
+	ErrBillSplitStale          = errors.New("割り勘の作成後に会計の内容が変更されました。分割をやり直してください")
+)
+
+// --- 会計（来店ごとの注文の合計） ---
+
+// CheckLine は会計の明細です。商品の明細行は割引後の金額、チャージは免除されていないもののみを含みます。
+// Amount は税額計算の対象額（税込価格の店舗では税込、税抜価格の店舗では税抜）です。
+type CheckLine struct {
+	SessionID string  `json:"session_id"`
+	LineID    string  `json:"line_id"`
+	Name      string  `json:"name"`
+	Amount    Money   `json:"amount"`
+	TaxRate   TaxRate `json:"tax_rate"`
+}
+
+// Check は座席の1回の来店の注文をまとめた会計です。
+// 税率ごとの内訳（Taxes）は各注文で計算済みの値の合計で、分割時の税額の按分の基準になります。
+type Check struct {
+	StoreID    string      `json:"store_id"`
+	SeatID     string      `json:"seat_id"`
+	VisitID    string      `json:"visit_id"`
+	SessionIDs []string    `json:"session_ids"`
+	Lines      []CheckLine `json:"lines"`
+	Taxes      []TaxLine   `json:"taxes"`
+	Total      Money       `json:"total"`
+}
+
+// isBillable は注文を会計の対象に含めるかどうかを返します。キャンセル・返金済みの注文は含めません。
+func (s *Session) isBillable() bool {
+	if s.paymentStatus() == PaymentStatusRefunded {
+		return false
+	}
+	switch s.Status {
+	case StatusCancelled, StatusDeclined, StatusFailed:
+		return false
+	default:
+		return true
+	}
+}
+
+// NewCheck は来店の注文から会計を作成します。
+func NewCheck(visitID string, sessions []*Session) (*Check, error) {
+	check := &Check{VisitID: visitID}
+	for _, s := range sessions {
+		if !s.isBillable() {
+			continue
+		}
+		if len(check.SessionIDs) == 0 {
+			check.StoreID, check.SeatID = s.StoreID, s.SeatID
+			check.Total = Zero(s.Currency())
+		} else if s.Currency() != check.Total.currency() {
+			return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, check.Total.currency(), s.Currency())
+		}
+		check.SessionIDs = append(check.SessionIDs, s.ID)
+		check.Total.Amount += s.TotalAmount.Amount
+		check.Taxes = mergeTaxLines(check.Taxes, s.Taxes)
+
+		at := s.taxPoint()
+		for _, item := range s.Items {
+			if item.IsVoided() {
+				continue
+			}
+			amount, err := s.LineTotal(item.LineID)
+			if err != nil {
+				return nil, err
+			}
+			rate, err := TaxRateFor(item.TaxCategory, s.DiningOption, at)
+			if err != nil {
+				return nil, err
+			}
+			check.Lines = append(check.Lines, CheckLine{SessionID: s.ID, LineID: item.LineID, Name: item.ProductID, Amount: amount, TaxRate: rate})
+		}
+		for _, c := range s.Charges {
+			if c.Waived {
+				continue
+			}
+			rate, err := TaxRateFor(c.TaxCategory, s.DiningOption, at)
+			if err != nil {
+				return nil, err
+			}
+			check.Lines = append(check.Lines, CheckLine{SessionID: s.ID, LineID: c.ID, Name: c.Name, Amount: c.Amount, TaxRate: rate})
+		}
+	}
+	if len(check.SessionIDs) == 0 {
+		return nil, ErrCheckEmpty
+	}
+	return check, nil
+}
+
+// mergeTaxLines は税率ごとの内訳を税率（種類と税率）ごとに合算します。
+func mergeTaxLines(lines []TaxLine, add []TaxLine) []TaxLine {
+	for _, line := range add {
+		i := slices.IndexFunc(lines, func(l TaxLine) bool { return l.Rate == line.Rate })
+		if i < 0 {
+			lines = append(lines, line)
+			continue
+		}
+		lines[i].Net.Amount += line.Net.Amount
+		lines[i].Tax.Amount += line.Tax.Amount
+		lines[i].Gross.Amount += line.Gross.Amount
+	}
+	return lines
+}
+
+// --- 割り勘 ---
+
+// SplitPayer は支払う人ごとの分割の指定です。
+// SplitByLines の場合は LineIDs（商品の明細行ID、チャージID）、SplitByAmounts の場合は Amount を指定します。
+type SplitPayer struct {
+	Label   string   `json:"label"`
+	LineIDs []string `json:"line_ids,omitempty"`
+	Amount  Money    `json:"amount"`
+}
+
+// SplitRequest は会計の分割方法です。SplitEven の場合は Parts に人数を指定します。
+type SplitRequest struct {
+	Method SplitMethod  `json:"method"`
+	Parts  int          `json:"parts,omitempty"`
+	Payers []SplitPayer `json:"payers,omitempty"`
+}
+
+// SubBill は分割した個別の伝票です。伝票ごとに独立して支払います。
+// Taxes は会計全体の税額を伝票の金額の比率で按分したもので、全ての伝票と残額の税額の合計は会計の税額と一致します。
+type SubBill struct {
+	ID         string       `json:"id"`
+	Label      string       `json:"label"`
+	LineIDs    []string     `json:"line_ids,omitempty"`
+	Amount     Money        `json:"amount"`
+	Taxes      []TaxLine    `json:"taxes"`
+	Paid       bool         `json:"paid"`
+	Method     TenderMethod `json:"method,omitempty"`
+	PaymentRef string       `json:"payment_ref,omitempty"`
+	PaidAt     time.Time    `json:"paid_at,omitempty"`
+}
+
+// BillSplit は来店の会計を複数の伝票に分割した割り勘です。
+// 明細の選択や金額の指定で割り当てられなかった分は Remainder として残り、
+// 伝票として割り当てて支払うまで会計全体は精算済みになりません。
+type BillSplit struct {
+	ID         string
+	StoreID    string
+	SeatID     string
+	VisitID    string
+	SessionIDs []string
+	Method     SplitMethod
+	Status     BillSplitStatus
+
+	Total    Money
+	Taxes    []TaxLine
+	SubBills []SubBill
+
+	// 未割り当ての残額と、その税率ごとの内訳
+	Remainder      Money
+	RemainderTaxes []TaxLine
+
+	SettledAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewBillSplit は会計を指定された方法で分割します。
+func NewBillSplit(check *Check, req SplitRequest, now time.Time) (*BillSplit, error) {
+	currency := check.Total.currency()
+
+	var labels []string
+	var lineIDs [][]string
+	var rows [][]int64 // 伝票ごと（最後の行は残額）の税率ごとの税込金額
+	switch req.Method {
+	case SplitEven:
+		if req.Parts < 2 {
+			return nil, ErrInvalidSplitParts
+		}
+		// 重みが全て0の場合は均等に按分される
+		shares, err := AllocateMoney(check.Total, make([]int64, req.Parts))
+		if err != nil {
+			return nil, err
+		}
+		amounts := make([]int64, 0, req.Parts+1)
+		for i, share := range shares {
+			amounts = append(amounts, share.Amount)
+			labels = append(labels, fmt.Sprintf("%d/%d", i+1, req.Parts))
+			lineIDs = append(lineIDs, nil)
+		}
+		rows = fillTaxBuckets(check.Taxes, append(amounts, 0))
+
+	case SplitByLines:
+		if len(req.Payers) == 0 {
+			return nil, ErrSplitPayersRequired
+		}
+		assigned := map[string]bool{}
+		basis := make([][]int64, len(req.Payers)+1)
+		for i, payer := range req.Payers {
+			basis[i] = make([]int64, len(check.Taxes))
+			for _, id := range payer.LineIDs {
+				j := slices.IndexFunc(check.Lines, func(l CheckLine) bool { return l.LineID == id })
+				if j < 0 {
+					return nil, fmt.Errorf("%w: %s", ErrSplitLineNotFound, id)
+				}
+				if assigned[id] {
+					return nil, fmt.Errorf("%w: %s", ErrSplitLineAssigned, id)
+				}
+				assigned[id] = true
+				basis[i][taxBucketIndex(check.Taxes, check.Lines[j].TaxRate)] += check.Lines[j].Amount.Amount
+			}
+			labels = append(labels, payer.Label)
+			lineIDs = append(lineIDs, payer.LineIDs)
+		}
+		basis[len(req.Payers)] = make([]int64, len(check.Taxes))
+		for _, line := range check.Lines {
+			if !assigned[line.LineID] {
+				basis[len(req.Payers)][taxBucketIndex(check.Taxes, line.TaxRate)] += line.Amount.Amount
+			}
+		}
+		var err error
+		if rows, err = apportionTaxBuckets(check.Taxes, basis); err != nil {
+			return nil, err
+		}
+
+	case SplitByAmounts:
+		if len(req.Payers) == 0 {
+			return nil, ErrSplitPayersRequired
+		}
+		amounts := make([]int64, 0, len(req.Payers)+1)
+		var sum int64
+		for _, payer := range req.Payers {
+			if payer.Amount.Amount <= 0 {
+				return nil, ErrInvalidSplitAmount
+			}
+			if payer.Amount.currency() != currency {
+				return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, currency, payer.Amount.currency())
+			}
+			sum += payer.Amount.Amount
+			amounts = append(amounts, payer.Amount.Amount)
+			labels = append(labels, payer.Label)
+			lineIDs = append(lineIDs, nil)
+		}
+		if sum > check.Total.Amount {
+			return nil, ErrSplitAmountExceedsTotal
+		}
+		rows = fillTaxBuckets(check.Taxes, append(amounts, check.Total.Amount-sum))
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSplitMethod, req.Method)
+	}
+
+	taxes, err := apportionTaxAmounts(check.Taxes, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	split := &BillSplit{
+		ID:         GenerateUniqueID(BillSplitPrefix),
+		StoreID:    check.StoreID,
+		SeatID:     check.SeatID,
+		VisitID:    check.VisitID,
+		SessionIDs: check.SessionIDs,
+		Method:     req.Method,
+		Status:     BillSplitOpen,
+		Total:      check.Total,
+		Taxes:      check.Taxes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	for i, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			label = fmt.Sprintf("%d", i+1)
+		}
+		split.SubBills = append(split.SubBills, SubBill{
+			ID:      GenerateUniqueID(SubBillPrefix),
+			Label:   label,
+			LineIDs: lineIDs[i],
+			Amount:  NewMoney(sumRow(rows[i]), currency),
+			Taxes:   taxes[i],
+		})
+	}
+	last := len(rows) - 1
+	split.Remainder = NewMoney(sumRow(rows[last]), currency)
+	split.RemainderTaxes = taxes[last]
+	return split, nil
+}
+
+// taxBucketIndex は税率ごとの内訳のうち、指定した税率の位置を返します。
+func taxBucketIndex(taxes []TaxLine, rate TaxRate) int {
+	return slices.IndexFunc(taxes, func(l TaxLine) bool { return l.Rate == rate })
+}
+
+func sumRow(row []int64) int64 {
+	var sum int64
+	for _, v := range row {
+		sum += v
+	}
+	return sum
+}
+
+// fillTaxBuckets は伝票ごとの金額（amounts）を税率ごとの税込金額に振り分けます。
+// 伝票の順に、残っている税率ごとの金額の比率で按分するため、伝票の合計と税率ごとの合計の両方が一致します。
+// amounts の合計は会計の合計金額と一致している必要があります。
+func fillTaxBuckets(taxes []TaxLine, amounts []int64) [][]int64 {
+	capacity := make([]int64, len(taxes))
+	for b, line := range taxes {
+		capacity[b] = line.Gross.Amount
+	}
+
+	rows := make([][]int64, len(amounts))
+	for i, amount := range amounts {
+		shares, _ := AllocateMoney(NewMoney(amount, DefaultCurrency), slices.Clone(capacity))
+		rows[i] = make([]int64, len(taxes))
+		for b, share := range shares {
+			rows[i][b] = share.Amount
+			capacity[b] -= share.Amount
+		}
+	}
+	return rows
+}
+
+// apportionTaxBuckets は税率ごとの税込金額を、伝票ごとの明細の金額（basis）の比率で按分します。
+func apportionTaxBuckets(taxes []TaxLine, basis [][]int64) ([][]int64, error) {
+	rows := make([][]int64, len(basis))
+	for i := range rows {
+		rows[i] = make([]int64, len(taxes))
+	}
+	for b, line := range taxes {
+		weights := make([]int64, len(basis))
+		for i := range basis {
+			weights[i] = basis[i][b]
+		}
+		shares, err := AllocateMoney(line.Gross, weights)
+		if err != nil {
+			return nil, err
+		}
+		for i, share := range shares {
+			rows[i][b] = share.Amount
+		}
+	}
+	return rows, nil
+}
+
+// apportionTaxAmounts は税率ごとの税額を、伝票ごとの税込金額の比率で按分し、伝票ごとの税率ごとの内訳を返します。
+func apportionTaxAmounts(taxes []TaxLine, rows [][]int64) ([][]TaxLine, error) {
+	result := make([][]TaxLine, len(rows))
+	for i := range result {
+		result[i] = []TaxLine{}
+	}
+	for b, line := range taxes {
+		weights := make([]int64, len(rows))
+		for i := range rows {
+			weights[i] = rows[i][b]
+		}
+		shares, err := AllocateMoney(line.Tax, weights)
+		if err != nil {
+			return nil, err
+		}
+		currency := line.Gross.currency()
+		for i, share := range shares {
+			if rows[i][b] == 0 {
+				continue
+			}
+			result[i] = append(result[i], TaxLine{
+				Rate:  line.Rate,
+				Gross: NewMoney(rows[i][b], currency),
+				Tax:   share,
+				Net:   NewMoney(rows[i][b]-share.Amount, currency),
+			})
+		}
+	}
+	return result, nil
+}
+
+// AssignRemainder は未割り当ての残額を新しい伝票として割り当て、支払えるようにします。
+func (b *BillSplit) AssignRemainder(label string, now time.Time) (*SubBill, error) {
+	if b.Status != BillSplitOpen {
+		return nil, ErrBillSplitClosed
+	}
+	if b.Remainder.IsZero() {
+		return nil, ErrNoRemainder
+	}
+
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = "残額"
+	}
+	b.SubBills = append(b.SubBills, SubBill{
+		ID:     GenerateUniqueID(SubBillPrefix),
+		Label:  label,
+		Amount: b.Remainder,
+		Taxes:  b.RemainderTaxes,
+	})
+	b.Remainder = Zero(b.Remainder.currency())
+	b.RemainderTaxes = []TaxLine{}
+	b.UpdatedAt = now.UTC()
+	return &b.SubBills[len(b.SubBills)-1], nil
+}
+
+// MarkSubBillPaid は伝票の支払いを支払い方法とともに記録します。支払い方法はレジ締めの集計に使用します。
+// 全ての伝票の支払いが完了し、未割り当ての残額がない場合は会計全体を精算済みにします。
+func (b *BillSplit) MarkSubBillPaid(subBillID string, method TenderMethod, paymentRef string, now time.Time) (*SubBill, error) {
+	if b.Status != BillSplitOpen {
+		return nil, ErrBillSplitClosed
+	}
+	if !method.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTenderMethod, method)
+	}
+	i := slices.IndexFunc(b.SubBills, func(s SubBill) bool { return s.ID == subBillID })
+	if i < 0 {
+		return nil, ErrSubBillNotFound
+	}
+	if b.SubBills[i].Paid {
+		return nil, ErrSubBillAlreadyPaid
+	}
+
+	now = now.UTC()
+	b.SubBills[i].Paid = true
+	b.SubBills[i].Method = method
+	b.SubBills[i].PaymentRef = paymentRef
+	b.SubBills[i].PaidAt = now
+	if b.Outstanding().IsZero() {
+		b.Status = BillSplitSettled
+		b.SettledAt = now
+	}
+	b.UpdatedAt = now
+	return &b.SubBills[i], nil
+}
+
+// Outstanding は未払いの伝票と未割り当ての残額の合計（会計全体の未精算額）を返します。
+func (b *BillSplit) Outstanding() Money {
+	outstanding := b.Remainder
+	for _, s := range b.SubBills {
+		if !s.Paid {
+			outstanding.Amount += s.Amount.Amount
+		}
+	}
+	return outstanding
+}
+
+// Matches は割り勘が現在の会計（対象の注文と合計金額）から作成されたものかどうかを返します。
+// 割り勘の作成後に追加注文や取り消しがあった場合は false を返します。
+func (b *BillSplit) Matches(check *Check) bool {
+	if b.Total != check.Total || len(b.SessionIDs) != len(check.SessionIDs) {
+		return false
+	}
+	for _, id := range check.SessionIDs {
+		if !slices.Contains(b.SessionIDs, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasPayments は支払い済みの伝票があるかどうかを返します。
+func (b *BillSplit) HasPayments() bool {
+	return slices.ContainsFunc(b.SubBills, func(s SubBill) bool { return s.Paid })
+}
+
+// Void は支払い前の割り勘を無効にします。分割をやり直す場合に使用します。
+func (b *BillSplit) Void(now time.Time) error {
+	if b.Status != BillSplitOpen {
+		return ErrBillSplitClosed
+	}
+	if b.HasPayments() {
+		return ErrBillSplitInProgress
+	}
+	b.Status = BillSplitVoided
+	b.UpdatedAt = now.UTC()
+	return nil
+}
+
+// PayByBillSplit は割り勘の全ての伝票の支払いにより注文を支払い済みにします。
+// 会計の対象外の注文（キャンセル・返金済みなど）と支払い済みの注文は変更せず false を返します。
+func (s *Session) PayByBillSplit(actor string) (bool, error) {
+	if !s.isBillable() || s.paymentStatus().IsPaid() {
+		return false, nil
+	}
+	if err := s.UpdatePaymentStatus(PaymentStatusPaid, actor, "割り勘での精算"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
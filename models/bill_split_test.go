@@ -0,0 +1,234 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSplitTestSessions は同じ来店の2件の注文（持ち帰りの軽減税率の商品を含む）を作成します。
+func newSplitTestSessions(t *testing.T) []*Session {
+	t.Helper()
+	first, err := NewSession("store_1", "seat_1", []Order{
+		*NewOrder("bento", 2, Yen(540)).WithTaxCategory(TaxCategoryFood),
+		*NewOrder("beer", 1, Yen(550)),
+	})
+	require.NoError(t, err)
+	require.NoError(t, first.SetTaxPolicy(DefaultTaxPolicy(), DiningTakeout))
+
+	second, err := NewSession("store_1", "seat_1", []Order{*NewOrder("highball", 2, Yen(480))})
+	require.NoError(t, err)
+	require.NoError(t, second.ApplyChargeRules([]ChargeRule{{ID: "chrule_1", Name: "お通し", Kind: ChargePerPerson, Value: 300, Active: true}}, nil, second.IssuedAt))
+
+	cancelled, err := NewSession("store_1", "seat_1", []Order{*NewOrder("sake", 1, Yen(900))})
+	require.NoError(t, err)
+	cancelled.Status = StatusCancelled
+
+	return []*Session{first, second, cancelled}
+}
+
+// assertSplitBalanced は伝票と残額の金額・税額の合計が会計と一致することを確認します。
+func assertSplitBalanced(t *testing.T, split *BillSplit) {
+	t.Helper()
+	total := split.Remainder.Amount
+	gross := map[TaxRate]int64{}
+	tax := map[TaxRate]int64{}
+	collect := func(lines []TaxLine) {
+		for _, line := range lines {
+			gross[line.Rate] += line.Gross.Amount
+			tax[line.Rate] += line.Tax.Amount
+			assert.Equal(t, line.Gross.Amount-line.Tax.Amount, line.Net.Amount)
+		}
+	}
+	for _, bill := range split.SubBills {
+		total += bill.Amount.Amount
+		collect(bill.Taxes)
+	}
+	collect(split.RemainderTaxes)
+
+	assert.Equal(t, split.Total.Amount, total)
+	for _, line := range split.Taxes {
+		assert.Equal(t, line.Gross.Amount, gross[line.Rate], "税率ごとの税込金額の合計")
+		assert.Equal(t, line.Tax.Amount, tax[line.Rate], "税率ごとの税額の合計")
+	}
+}
+
+func TestNewCheck(t *testing.T) {
+	sessions := newSplitTestSessions(t)
+	check, err := NewCheck("visit_1", sessions)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{sessions[0].ID, sessions[1].ID}, check.SessionIDs, "キャンセルした注文は含めない")
+	assert.Equal(t, Yen(1630+960+300), check.Total)
+	require.Len(t, check.Lines, 4)
+	assert.Equal(t, "お通し", check.Lines[3].Name)
+	require.Len(t, check.Taxes, 2)
+	assert.Equal(t, TaxRateStandard, check.Taxes[0].Rate.Code)
+	assert.Equal(t, Yen(550+960+300), check.Taxes[0].Gross)
+
+	_, err = NewCheck("visit_1", sessions[2:])
+	assert.ErrorIs(t, err, ErrCheckEmpty)
+}
+
+func TestNewBillSplit(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	newCheck := func(t *testing.T) *Check {
+		check, err := NewCheck("visit_1", newSplitTestSessions(t))
+		require.NoError(t, err)
+		return check
+	}
+
+	t.Run("均等に分割", func(t *testing.T) {
+		split, err := NewBillSplit(newCheck(t), SplitRequest{Method: SplitEven, Parts: 3}, now)
+		require.NoError(t, err)
+		require.Len(t, split.SubBills, 3)
+		assert.Equal(t, Yen(964), split.SubBills[0].Amount)
+		assert.Equal(t, Yen(963), split.SubBills[1].Amount)
+		assert.Equal(t, "1/3", split.SubBills[0].Label)
+		assert.True(t, split.Remainder.IsZero())
+		assertSplitBalanced(t, split)
+	})
+
+	t.Run("明細を選択して分割し、残りは残額として残す", func(t *testing.T) {
+		check := newCheck(t)
+		split, err := NewBillSplit(check, SplitRequest{Method: SplitByLines, Payers: []SplitPayer{
+			{Label: "田中", LineIDs: []string{check.Lines[0].LineID}},
+			{Label: "鈴木", LineIDs: []string{check.Lines[1].LineID, check.Lines[3].LineID}},
+		}}, now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(1080), split.SubBills[0].Amount)
+		require.Len(t, split.SubBills[0].Taxes, 1)
+		assert.Equal(t, TaxRateReduced, split.SubBills[0].Taxes[0].Rate.Code, "軽減税率の商品のみ")
+		assert.Equal(t, Yen(80), split.SubBills[0].Taxes[0].Tax)
+		assert.Equal(t, Yen(850), split.SubBills[1].Amount)
+		assert.Equal(t, Yen(960), split.Remainder)
+		assertSplitBalanced(t, split)
+	})
+
+	t.Run("金額を指定して分割", func(t *testing.T) {
+		split, err := NewBillSplit(newCheck(t), SplitRequest{Method: SplitByAmounts, Payers: []SplitPayer{
+			{Label: "幹事", Amount: Yen(2000)},
+		}}, now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(2000), split.SubBills[0].Amount)
+		assert.Equal(t, Yen(890), split.Remainder)
+		assertSplitBalanced(t, split)
+	})
+
+	t.Run("不正な指定", func(t *testing.T) {
+		check := newCheck(t)
+		testCases := []struct {
+			name     string
+			req      SplitRequest
+			expected error
+		}{
+			{"分割方法が不正", SplitRequest{Method: "random"}, ErrInvalidSplitMethod},
+			{"人数が1", SplitRequest{Method: SplitEven, Parts: 1}, ErrInvalidSplitParts},
+			{"支払う人がいない", SplitRequest{Method: SplitByLines}, ErrSplitPayersRequired},
+			{"存在しない明細", SplitRequest{Method: SplitByLines, Payers: []SplitPayer{{LineIDs: []string{"line_unknown"}}}}, ErrSplitLineNotFound},
+			{"同じ明細を重複して選択", SplitRequest{Method: SplitByLines, Payers: []SplitPayer{
+				{LineIDs: []string{check.Lines[0].LineID}}, {LineIDs: []string{check.Lines[0].LineID}},
+			}}, ErrSplitLineAssigned},
+			{"金額が0", SplitRequest{Method: SplitByAmounts, Payers: []SplitPayer{{Amount: Yen(0)}}}, ErrInvalidSplitAmount},
+			{"合計金額を超える", SplitRequest{Method: SplitByAmounts, Payers: []SplitPayer{{Amount: Yen(2000)}, {Amount: Yen(1000)}}}, ErrSplitAmountExceedsTotal},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := NewBillSplit(check, tc.req, now)
+				assert.ErrorIs(t, err, tc.expected)
+			})
+		}
+	})
+}
+
+func TestBillSplit_Settlement(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	check, err := NewCheck("visit_1", newSplitTestSessions(t))
+	require.NoError(t, err)
+	split, err := NewBillSplit(check, SplitRequest{Method: SplitByAmounts, Payers: []SplitPayer{{Label: "幹事", Amount: Yen(2000)}}}, now)
+	require.NoError(t, err)
+
+	_, err = split.MarkSubBillPaid(split.SubBills[0].ID, "", "pay_1", now)
+	assert.ErrorIs(t, err, ErrInvalidTenderMethod)
+	_, err = split.MarkSubBillPaid(split.SubBills[0].ID, TenderCard, "pay_1", now)
+	require.NoError(t, err)
+	assert.Equal(t, TenderCard, split.SubBills[0].Method)
+	assert.Equal(t, BillSplitOpen, split.Status, "残額があるため精算済みにならない")
+	assert.Equal(t, Yen(890), split.Outstanding())
+	assert.ErrorIs(t, split.Void(now), ErrBillSplitInProgress)
+
+	_, err = split.MarkSubBillPaid(split.SubBills[0].ID, TenderCard, "pay_1", now)
+	assert.ErrorIs(t, err, ErrSubBillAlreadyPaid)
+	_, err = split.MarkSubBillPaid("subbill_unknown", TenderCard, "pay_2", now)
+	assert.ErrorIs(t, err, ErrSubBillNotFound)
+
+	remainder, err := split.AssignRemainder("", now)
+	require.NoError(t, err)
+	assert.Equal(t, "残額", remainder.Label)
+	assert.Equal(t, Yen(890), remainder.Amount)
+	assert.True(t, split.Remainder.IsZero())
+	_, err = split.AssignRemainder("", now)
+	assert.ErrorIs(t, err, ErrNoRemainder)
+	assertSplitBalanced(t, split)
+
+	_, err = split.MarkSubBillPaid(remainder.ID, TenderCard, "pay_2", now)
+	require.NoError(t, err)
+	assert.Equal(t, BillSplitSettled, split.Status)
+	assert.Equal(t, now, split.SettledAt)
+	assert.True(t, split.Outstanding().IsZero())
+
+	_, err = split.MarkSubBillPaid(remainder.ID, TenderCard, "pay_3", now)
+	assert.ErrorIs(t, err, ErrBillSplitClosed)
+}
+
+func TestBillSplit_Void(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	check, err := NewCheck("visit_1", newSplitTestSessions(t))
+	require.NoError(t, err)
+	split, err := NewBillSplit(check, SplitRequest{Method: SplitEven, Parts: 2}, now)
+	require.NoError(t, err)
+
+	require.NoError(t, split.Void(now))
+	assert.Equal(t, BillSplitVoided, split.Status)
+	assert.ErrorIs(t, split.Void(now), ErrBillSplitClosed)
+}
+
+func TestBillSplit_Matches(t *testing.T) {
+	sessions := newSplitTestSessions(t)
+	check, err := NewCheck("visit_1", sessions[:2])
+	require.NoError(t, err)
+	split, err := NewBillSplit(check, SplitRequest{Method: SplitEven, Parts: 2}, time.Now())
+	require.NoError(t, err)
+	assert.True(t, split.Matches(check))
+
+	added, err := NewSession("store_1", "seat_1", []Order{*NewOrder("beer", 1, Yen(550))})
+	require.NoError(t, err)
+	changed, err := NewCheck("visit_1", append(sessions[:2:2], added))
+	require.NoError(t, err)
+	assert.False(t, split.Matches(changed), "追加注文")
+
+	_, err = sessions[1].WaiveCharge(sessions[1].Charges[0].ID, "苦手な食材", "manager:a@example.com", time.Now())
+	require.NoError(t, err)
+	changed, err = NewCheck("visit_1", sessions[:2])
+	require.NoError(t, err)
+	assert.False(t, split.Matches(changed), "合計金額の変更")
+}
+
+func TestSession_PayByBillSplit(t *testing.T) {
+	sessions := newSplitTestSessions(t)
+
+	paid, err := sessions[0].PayByBillSplit("manager:a@example.com")
+	require.NoError(t, err)
+	assert.True(t, paid)
+	assert.Equal(t, PaymentStatusPaid, sessions[0].PaymentStatus)
+
+	paid, err = sessions[0].PayByBillSplit("manager:a@example.com")
+	require.NoError(t, err)
+	assert.False(t, paid, "支払い済みの注文は変更しない")
+
+	paid, err = sessions[2].PayByBillSplit("manager:a@example.com")
+	require.NoError(t, err)
+	assert.False(t, paid, "キャンセルした注文は変更しない")
+}
@@ -0,0 +1,312 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// ChargeRulePrefix は店舗のチャージ設定のIDのプレフィックスです。
+	ChargeRulePrefix = "chrule_"
+	// ChargePrefix は注文に適用したチャージのIDのプレフィックスです。
+	ChargePrefix = "charge_"
+)
+
+// ChargeKind はチャージ（お通し代、席料、サービス料）の計算方法です。
+type ChargeKind string
+
+const (
+	// ChargePerPerson は人数分の固定額です（お通し代など）。来店ごとに1回のみ請求します。
+	ChargePerPerson ChargeKind = "per_person"
+	// ChargePerSeat は座席ごとの固定額です（席料など）。来店ごとに1回のみ請求します。
+	ChargePerSeat ChargeKind = "per_seat"
+	// ChargePercentage は割引後の商品の金額に対する割合（%）です（サービス料など）。注文ごとに請求します。
+	ChargePercentage ChargeKind = "percentage"
+)
+
+var (
+	ErrChargeNameRequired        = errors.New("チャージの名称を指定してください")
+	ErrInvalidChargeKind         = errors.New("チャージの種類が不正です")
+	ErrInvalidChargeValue        = errors.New("チャージの値が不正です")
+	ErrInvalidChargeTime         = errors.New("チャージの適用時間帯は HH:MM 形式で開始と終了の両方を指定してください")
+	ErrChargeNotFound            = errors.New("チャージが見つかりません")
+	ErrChargeWaiveReasonRequired = errors.New("チャージを免除する理由を指定してください")
+	ErrChargeAlreadyWaived       = errors.New("チャージはすでに免除されています")
+	ErrChargeNotAllowed          = errors.New("現在のステータスではチャージを変更できません")
+	ErrInvalidPartySize          = errors.New("人数は1以上で指定してください")
+)
+
+// ChargeRule は店舗が設定するチャージの条件です。
+// Value は ChargePercentage の場合は1〜100の百分率、それ以外は補助単位の金額です。
+// StartTime、EndTime は店舗の現地時間（日本時間）の "HH:MM" で、省略した場合は終日適用します。
+// 終了が開始より前の場合は日をまたぐ時間帯（例: 18:00〜02:00）として扱います。
+type ChargeRule struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Kind        ChargeKind  `json:"kind"`
+	Value       int64       `json:"value"`
+	Currency    Currency    `json:"currency,omitempty"`
+	TaxCategory TaxCategory `json:"tax_category,omitempty"`
+	StartTime   string      `json:"start_time,omitempty"`
+	EndTime     string      `json:"end_time,omitempty"`
+	Active      bool        `json:"active"`
+}
+
+// Validate はチャージの設定を検証します。
+func (r ChargeRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrChargeNameRequired
+	}
+	switch r.Kind {
+	case ChargePercentage:
+		if r.Value <= 0 || r.Value > 100 {
+			return fmt.Errorf("%w: 割合は1〜100で指定してください: %d", ErrInvalidChargeValue, r.Value)
+		}
+	case ChargePerPerson, ChargePerSeat:
+		if r.Value <= 0 {
+			return fmt.Errorf("%w: 金額は0より大きい値を指定してください: %d", ErrInvalidChargeValue, r.Value)
+		}
+		if r.Currency != "" && !r.Currency.IsValid() {
+			return fmt.Errorf("%w: %s", ErrUnsupportedCurrency, r.Currency)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidChargeKind, r.Kind)
+	}
+	if !r.TaxCategory.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidTaxCategory, r.TaxCategory)
+	}
+
+	if (r.StartTime == "") != (r.EndTime == "") {
+		return ErrInvalidChargeTime
+	}
+	if r.StartTime != "" {
+		start, err := parseClockMinutes(r.StartTime)
+		if err != nil {
+			return err
+		}
+		end, err := parseClockMinutes(r.EndTime)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return ErrInvalidChargeTime
+		}
+	}
+	return nil
+}
+
+// AppliesAt はチャージが at の時点（店舗の現地時間）で適用されるかどうかを返します。
+func (r ChargeRule) AppliesAt(at time.Time) bool {
+	if !r.Active {
+		return false
+	}
+	if r.StartTime == "" {
+		return true
+	}
+	start, err := parseClockMinutes(r.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := parseClockMinutes(r.EndTime)
+	if err != nil {
+		return false
+	}
+
+	local := at.In(jst)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return start <= minute && minute < end
+	}
+	// 日をまたぐ時間帯
+	return minute >= start || minute < end
+}
+
+// parseClockMinutes は "HH:MM" を0時からの経過分に変換します。
+func parseClockMinutes(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidChargeTime, value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// NormalizeChargeRules はチャージの設定を検証し、IDのない設定にIDを割り当てます。
+func NormalizeChargeRules(rules []ChargeRule) ([]ChargeRule, error) {
+	normalized := make([]ChargeRule, len(rules))
+	for i, rule := range rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%w (rules[%d])", err, i)
+		}
+		if rule.ID == "" {
+			rule.ID = GenerateUniqueID(ChargeRulePrefix)
+		}
+		normalized[i] = rule
+	}
+	return normalized, nil
+}
+
+// Charge は注文に適用したチャージです。
+// 免除した場合も記録を残し、合計金額からのみ除外します。
+type Charge struct {
+	ID          string      `json:"id"`
+	RuleID      string      `json:"rule_id"`
+	Name        string      `json:"name"`
+	Kind        ChargeKind  `json:"kind"`
+	Value       int64       `json:"value"`
+	Quantity    int         `json:"quantity"`
+	TaxCategory TaxCategory `json:"tax_category,omitempty"`
+	// Amount はルールから再計算される値で、サービス料の場合は商品の追加や割引で変わります。
+	Amount Money `json:"amount"`
+
+	Waived      bool      `json:"waived"`
+	WaiveReason string    `json:"waive_reason,omitempty"`
+	WaivedBy    string    `json:"waived_by,omitempty"`
+	WaivedAt    time.Time `json:"waived_at,omitempty"`
+
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// --- Session のチャージ ---
+
+// ApplyChargeRules は店舗のチャージ設定のうち、now の時点で適用されるものを注文に追加します。
+// 人数・座席ごとのチャージは来店ごとに1回のみ請求するため、同じ来店の注文（visitOrders）で
+// すでに請求済み（免除済みを含む）のものは追加しません。
+func (s *Session) ApplyChargeRules(rules []ChargeRule, visitOrders []*Session, now time.Time) error {
+	billed := map[string]bool{}
+	for _, order := range visitOrders {
+		if order.ID == s.ID {
+			continue
+		}
+		for _, charge := range order.Charges {
+			billed[charge.RuleID] = true
+		}
+	}
+
+	added := 0
+	for _, rule := range rules {
+		if !rule.AppliesAt(now) {
+			continue
+		}
+		if rule.Kind != ChargePercentage {
+			if billed[rule.ID] {
+				continue
+			}
+			if rule.Currency != "" && rule.Currency != s.Currency() {
+				return fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, s.Currency(), rule.Currency)
+			}
+		}
+		s.Charges = append(s.Charges, Charge{
+			ID:          GenerateUniqueID(ChargePrefix),
+			RuleID:      rule.ID,
+			Name:        rule.Name,
+			Kind:        rule.Kind,
+			Value:       rule.Value,
+			TaxCategory: rule.TaxCategory,
+			AppliedAt:   now.UTC(),
+		})
+		added++
+	}
+	if added == 0 {
+		return nil
+	}
+	return s.RecalculateTotalAmount()
+}
+
+// SetPartySize は来店人数を設定し、人数分のチャージを再計算します。
+// 支払い手続き中・支払い済みの注文の金額は変更できません。
+func (s *Session) SetPartySize(size int) error {
+	if size < 1 {
+		return ErrInvalidPartySize
+	}
+	if !s.paymentStatus().AcceptsChanges() {
+		return fmt.Errorf("%w: 現在の支払い状態は '%s'", ErrChargeNotAllowed, s.paymentStatus())
+	}
+	s.PartySize = size
+	return s.RecalculateTotalAmount()
+}
+
+// WaiveCharge はスタッフが理由を添えてチャージを免除します。
+// actor は免除したスタッフ（"manager:<email>" など）で、理由とあわせて記録します。
+// 提供後の会計時にも免除できるよう、提供の進行に関わらず支払いが済むまで免除できます。
+func (s *Session) WaiveCharge(chargeID, reason, actor string, now time.Time) (*Charge, error) {
+	if !s.paymentStatus().AcceptsChanges() {
+		return nil, fmt.Errorf("%w: 現在の支払い状態は '%s'", ErrChargeNotAllowed, s.paymentStatus())
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrChargeWaiveReasonRequired
+	}
+
+	i := slices.IndexFunc(s.Charges, func(c Charge) bool { return c.ID == chargeID })
+	if i < 0 {
+		return nil, ErrChargeNotFound
+	}
+	charge := &s.Charges[i]
+	if charge.Waived {
+		return nil, ErrChargeAlreadyWaived
+	}
+	charge.Waived = true
+	charge.WaiveReason = reason
+	charge.WaivedBy = actor
+	charge.WaivedAt = now.UTC()
+
+	if err := s.RecalculateTotalAmount(); err != nil {
+		return nil, err
+	}
+	waived := s.Charges[i]
+	return &waived, nil
+}
+
+// ChargeTotal は免除されていないチャージの合計を返します。
+func (s *Session) ChargeTotal() Money {
+	total := Zero(s.Currency())
+	for _, c := range s.Charges {
+		if !c.Waived {
+			total.Amount += c.Amount.Amount
+		}
+	}
+	return total
+}
+
+// applyCharges は割引後の商品の金額（itemAmounts）からチャージ額を計算し、税額計算の対象に追加する項目を返します。
+// 人数が未設定の場合は1名として計算します。
+func (s *Session) applyCharges(itemAmounts []Money) ([]TaxItem, error) {
+	currency := s.Currency()
+	base := Zero(currency)
+	for _, amount := range itemAmounts {
+		base.Amount += amount.Amount
+	}
+	partySize := max(s.PartySize, 1)
+
+	items := make([]TaxItem, 0, len(s.Charges))
+	for i := range s.Charges {
+		c := &s.Charges[i]
+		switch c.Kind {
+		case ChargePerPerson:
+			c.Quantity = partySize
+			amount, err := NewMoney(c.Value, currency).Multiply(int64(partySize))
+			if err != nil {
+				return nil, err
+			}
+			c.Amount = amount
+		case ChargePerSeat:
+			c.Quantity = 1
+			c.Amount = NewMoney(c.Value, currency)
+		case ChargePercentage:
+			c.Quantity = 1
+			amount, err := base.MulRatio(c.Value, 100, RoundDown)
+			if err != nil {
+				return nil, err
+			}
+			c.Amount = amount
+		}
+		if !c.Waived {
+			items = append(items, TaxItem{Category: c.TaxCategory, Amount: c.Amount})
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargeRule_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     ChargeRule
+		expected error
+	}{
+		{"お通し代", ChargeRule{Name: "お通し", Kind: ChargePerPerson, Value: 300}, nil},
+		{"夜間のサービス料", ChargeRule{Name: "サービス料", Kind: ChargePercentage, Value: 10, StartTime: "18:00", EndTime: "02:00"}, nil},
+		{"名称がない", ChargeRule{Name: " ", Kind: ChargePerSeat, Value: 500}, ErrChargeNameRequired},
+		{"種類が不正", ChargeRule{Name: "席料", Kind: "per_table", Value: 500}, ErrInvalidChargeKind},
+		{"金額が0", ChargeRule{Name: "席料", Kind: ChargePerSeat, Value: 0}, ErrInvalidChargeValue},
+		{"割合が100を超える", ChargeRule{Name: "サービス料", Kind: ChargePercentage, Value: 101}, ErrInvalidChargeValue},
+		{"通貨が不正", ChargeRule{Name: "席料", Kind: ChargePerSeat, Value: 500, Currency: "XXX"}, ErrUnsupportedCurrency},
+		{"税区分が不正", ChargeRule{Name: "席料", Kind: ChargePerSeat, Value: 500, TaxCategory: "luxury"}, ErrInvalidTaxCategory},
+		{"終了時刻がない", ChargeRule{Name: "お通し", Kind: ChargePerPerson, Value: 300, StartTime: "18:00"}, ErrInvalidChargeTime},
+		{"時刻の形式が不正", ChargeRule{Name: "お通し", Kind: ChargePerPerson, Value: 300, StartTime: "6pm", EndTime: "23:00"}, ErrInvalidChargeTime},
+		{"開始と終了が同じ", ChargeRule{Name: "お通し", Kind: ChargePerPerson, Value: 300, StartTime: "18:00", EndTime: "18:00"}, ErrInvalidChargeTime},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestChargeRule_AppliesAt(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 4, 1, hour, minute, 0, 0, jst)
+	}
+	allDay := ChargeRule{Active: true}
+	evening := ChargeRule{Active: true, StartTime: "17:00", EndTime: "23:00"}
+	lateNight := ChargeRule{Active: true, StartTime: "22:00", EndTime: "05:00"}
+
+	assert.True(t, allDay.AppliesAt(at(12, 0)))
+	assert.False(t, ChargeRule{}.AppliesAt(at(12, 0)), "停止中のチャージは適用しない")
+
+	assert.False(t, evening.AppliesAt(at(16, 59)))
+	assert.True(t, evening.AppliesAt(at(17, 0)))
+	assert.False(t, evening.AppliesAt(at(23, 0)), "終了時刻は含まない")
+
+	assert.True(t, lateNight.AppliesAt(at(23, 30)))
+	assert.True(t, lateNight.AppliesAt(at(4, 59)))
+	assert.False(t, lateNight.AppliesAt(at(12, 0)))
+	// UTCの時刻も日本時間で判定する
+	assert.True(t, lateNight.AppliesAt(time.Date(2024, 4, 1, 14, 0, 0, 0, time.UTC)))
+}
+
+func TestNormalizeChargeRules(t *testing.T) {
+	rules, err := NormalizeChargeRules([]ChargeRule{
+		{ID: "chrule_1", Name: "お通し", Kind: ChargePerPerson, Value: 300},
+		{Name: " サービス料 ", Kind: ChargePercentage, Value: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "chrule_1", rules[0].ID)
+	assert.Contains(t, rules[1].ID, ChargeRulePrefix)
+	assert.Equal(t, "サービス料", rules[1].Name)
+
+	_, err = NormalizeChargeRules([]ChargeRule{{Name: "席料", Kind: ChargePerSeat}})
+	assert.ErrorIs(t, err, ErrInvalidChargeValue)
+}
+
+// newChargeTestSession は税込価格の店内飲食の注文（小計2,000円）を作成します。
+func newChargeTestSession(t *testing.T) *Session {
+	t.Helper()
+	session, err := NewSession("store_1", "seat_1", []Order{
+		*NewOrder("karaage", 2, Yen(600)).WithTaxCategory(TaxCategoryFood),
+		*NewOrder("beer", 1, Yen(800)),
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func TestSession_ApplyChargeRules(t *testing.T) {
+	night := time.Date(2024, 4, 1, 20, 0, 0, 0, jst)
+	rules := []ChargeRule{
+		{ID: "chrule_otoshi", Name: "お通し", Kind: ChargePerPerson, Value: 300, Active: true},
+		{ID: "chrule_seat", Name: "席料", Kind: ChargePerSeat, Value: 500, Active: true, StartTime: "22:00", EndTime: "05:00"},
+		{ID: "chrule_service", Name: "サービス料", Kind: ChargePercentage, Value: 10, Active: true, StartTime: "18:00", EndTime: "05:00"},
+	}
+
+	t.Run("人数分のお通し代と割引後の金額に対するサービス料", func(t *testing.T) {
+		session := newChargeTestSession(t)
+		session.PartySize = 3
+		discount, err := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 200, Scope: DiscountScopeOrder}, "提供遅れ", "manager:a@example.com", night)
+		require.NoError(t, err)
+		require.NoError(t, session.ApplyDiscount(discount))
+
+		require.NoError(t, session.ApplyChargeRules(rules, nil, night))
+		require.Len(t, session.Charges, 2, "時間帯外の席料は適用しない")
+
+		assert.Equal(t, 3, session.Charges[0].Quantity)
+		assert.Equal(t, Yen(900), session.Charges[0].Amount)
+		assert.Equal(t, Yen(180), session.Charges[1].Amount, "割引後の1,800円の10%")
+		assert.Equal(t, Yen(1080), session.ChargeTotal())
+		assert.Equal(t, Yen(2880), session.TotalAmount)
+
+		// 店内飲食のため、チャージも含めて全て標準税率で課税される
+		require.Len(t, session.Taxes, 1)
+		assert.Equal(t, Yen(2880), session.Taxes[0].Gross)
+		assert.Equal(t, Yen(261), session.TaxTotal)
+	})
+
+	t.Run("人数・座席ごとのチャージは来店ごとに1回のみ", func(t *testing.T) {
+		first := newChargeTestSession(t)
+		require.NoError(t, first.ApplyChargeRules(rules, nil, night))
+
+		second := newChargeTestSession(t)
+		require.NoError(t, second.ApplyChargeRules(rules, []*Session{first}, night))
+		require.Len(t, second.Charges, 1)
+		assert.Equal(t, ChargePercentage, second.Charges[0].Kind)
+	})
+
+	t.Run("人数が未設定の場合は1名", func(t *testing.T) {
+		session := newChargeTestSession(t)
+		require.NoError(t, session.ApplyChargeRules(rules[:1], nil, night))
+		assert.Equal(t, Yen(300), session.ChargeTotal())
+
+		require.NoError(t, session.SetPartySize(4))
+		assert.Equal(t, Yen(1200), session.ChargeTotal())
+		assert.Equal(t, Yen(3200), session.TotalAmount)
+		assert.ErrorIs(t, session.SetPartySize(0), ErrInvalidPartySize)
+
+		session.PaymentStatus = PaymentStatusPaid
+		assert.ErrorIs(t, session.SetPartySize(2), ErrChargeNotAllowed, "支払い済みの注文の金額は変更しない")
+	})
+
+	t.Run("通貨が異なる固定額のチャージはエラー", func(t *testing.T) {
+		session := newChargeTestSession(t)
+		usd := []ChargeRule{{ID: "chrule_usd", Name: "Cover", Kind: ChargePerPerson, Value: 300, Currency: CurrencyUSD, Active: true}}
+		assert.ErrorIs(t, session.ApplyChargeRules(usd, nil, night), ErrCurrencyMismatch)
+	})
+}
+
+func TestSession_WaiveCharge(t *testing.T) {
+	now := time.Date(2024, 4, 1, 20, 0, 0, 0, jst)
+	rules := []ChargeRule{{ID: "chrule_otoshi", Name: "お通し", Kind: ChargePerPerson, Value: 300, Active: true}}
+
+	session := newChargeTestSession(t)
+	require.NoError(t, session.ApplyChargeRules(rules, nil, now))
+	chargeID := session.Charges[0].ID
+
+	_, err := session.WaiveCharge(chargeID, " ", "manager:a@example.com", now)
+	assert.ErrorIs(t, err, ErrChargeWaiveReasonRequired)
+	_, err = session.WaiveCharge("charge_unknown", "苦手な食材", "manager:a@example.com", now)
+	assert.ErrorIs(t, err, ErrChargeNotFound)
+
+	waived, err := session.WaiveCharge(chargeID, "苦手な食材", "manager:a@example.com", now)
+	require.NoError(t, err)
+	assert.True(t, waived.Waived)
+	assert.Equal(t, "苦手な食材", waived.WaiveReason)
+	assert.Equal(t, "manager:a@example.com", waived.WaivedBy)
+	assert.Equal(t, Yen(300), waived.Amount, "免除した金額も記録に残す")
+	assert.True(t, session.ChargeTotal().IsZero())
+	assert.Equal(t, Yen(2000), session.TotalAmount)
+
+	_, err = session.WaiveCharge(chargeID, "苦手な食材", "manager:a@example.com", now)
+	assert.ErrorIs(t, err, ErrChargeAlreadyWaived)
+
+	session.PaymentStatus = PaymentStatusPaid
+	_, err = session.WaiveCharge(chargeID, "苦手な食材", "manager:a@example.com", now)
+	assert.ErrorIs(t, err, ErrChargeNotAllowed, "支払い済みの注文は免除できない")
+}
+
+func TestSession_WaiveCharge_AfterServed(t *testing.T) {
+	now := time.Date(2024, 4, 1, 20, 0, 0, 0, jst)
+	rules := []ChargeRule{{ID: "chrule_otoshi", Name: "お通し", Kind: ChargePerPerson, Value: 300, Active: true}}
+
+	session := newChargeTestSession(t)
+	require.NoError(t, session.ApplyChargeRules(rules, nil, now))
+	session.Status = StatusCompleted
+
+	_, err := session.WaiveCharge(session.Charges[0].ID, "苦手な食材", "manager:a@example.com", now)
+	require.NoError(t, err, "提供後も会計前なら免除できる")
+	assert.Equal(t, Yen(2000), session.TotalAmount)
+}
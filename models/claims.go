@@ -42,10 +42,12 @@ func (p *Claims) ToJwtToken() (string, error) {
 
 // SessionClaims はセッションのクレームを表す構造体です。
 // 注文を行うセッションの有効期限と基礎情報を格納するためのものです。
+// RegisteredClaims.ID (jti) は SessionToken のIDで、会計後の失効確認に使用します。
 type SessionClaims struct {
 	StoreID string
 	SeatID  string
 	Name    string
+	VisitID string
 	jwt.RegisteredClaims
 }
 
@@ -0,0 +1,300 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DiscountPrefix は注文に適用した割引のIDのプレフィックスです。
+const DiscountPrefix = "disc_"
+
+// DiscountKind は割引額の計算方法です。
+type DiscountKind string
+
+const (
+	// DiscountPercentage は対象額に対する割合（%）の割引です。
+	DiscountPercentage DiscountKind = "percentage"
+	// DiscountFixed は固定額（補助単位）の割引です。対象額を超える分は割り引きません。
+	DiscountFixed DiscountKind = "fixed"
+)
+
+// DiscountScope は割引の対象範囲です。
+type DiscountScope string
+
+const (
+	// DiscountScopeOrder は注文全体が対象です。
+	DiscountScopeOrder DiscountScope = "order"
+	// DiscountScopeItem は指定した商品（または明細行）が対象です。
+	DiscountScopeItem DiscountScope = "item"
+	// DiscountScopeCategory は指定したカテゴリの商品が対象です。
+	DiscountScopeCategory DiscountScope = "category"
+)
+
+// DiscountSource は割引の適用元です。
+type DiscountSource string
+
+const (
+	// DiscountSourcePromotion はお客様がプロモーションコードを入力して適用した割引です。
+	DiscountSourcePromotion DiscountSource = "promotion"
+	// DiscountSourceManual はスタッフが理由を添えて手動で適用した割引です。
+	DiscountSourceManual DiscountSource = "manual"
+)
+
+var (
+	ErrInvalidDiscountKind    = errors.New("割引の種類が不正です")
+	ErrInvalidDiscountValue   = errors.New("割引の値が不正です")
+	ErrInvalidDiscountScope   = errors.New("割引の対象範囲が不正です")
+	ErrDiscountTargetRequired = errors.New("割引の対象となる商品またはカテゴリを指定してください")
+	ErrDiscountReasonRequired = errors.New("手動割引には理由を指定してください")
+	ErrDiscountNotApplicable  = errors.New("割引の対象となる商品がありません")
+	ErrDiscountNotAllowed     = errors.New("現在のステータスでは割引を変更できません")
+	ErrDiscountNotFound       = errors.New("割引が見つかりません")
+	ErrOrderLineNotFound      = errors.New("注文の明細が見つかりません")
+)
+
+// DiscountRule は割引額の計算方法と対象範囲です。
+// Value は DiscountPercentage の場合は1〜100の百分率、DiscountFixed の場合は補助単位の金額です。
+type DiscountRule struct {
+	Kind       DiscountKind  `json:"kind"`
+	Value      int64         `json:"value"`
+	Scope      DiscountScope `json:"scope"`
+	ProductIDs []string      `json:"product_ids,omitempty"`
+	LineIDs    []string      `json:"line_ids,omitempty"`
+	Categories []string      `json:"categories,omitempty"`
+}
+
+// Validate は割引の設定を検証します。
+func (r DiscountRule) Validate() error {
+	switch r.Kind {
+	case DiscountPercentage:
+		if r.Value <= 0 || r.Value > 100 {
+			return fmt.Errorf("%w: 割合は1〜100で指定してください: %d", ErrInvalidDiscountValue, r.Value)
+		}
+	case DiscountFixed:
+		if r.Value <= 0 {
+			return fmt.Errorf("%w: 金額は0より大きい値を指定してください: %d", ErrInvalidDiscountValue, r.Value)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidDiscountKind, r.Kind)
+	}
+
+	switch r.Scope {
+	case DiscountScopeOrder:
+	case DiscountScopeItem:
+		if len(r.ProductIDs) == 0 && len(r.LineIDs) == 0 {
+			return ErrDiscountTargetRequired
+		}
+	case DiscountScopeCategory:
+		if len(r.Categories) == 0 {
+			return ErrDiscountTargetRequired
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidDiscountScope, r.Scope)
+	}
+	return nil
+}
+
+// Matches は注文アイテムが割引の対象かどうかを返します。
+func (r DiscountRule) Matches(item Order) bool {
+	switch r.Scope {
+	case DiscountScopeOrder:
+		return true
+	case DiscountScopeItem:
+		return slices.Contains(r.ProductIDs, item.ProductID) || slices.Contains(r.LineIDs, item.LineID)
+	case DiscountScopeCategory:
+		return item.Category != "" && slices.Contains(r.Categories, item.Category)
+	default:
+		return false
+	}
+}
+
+// DiscountAllocation は割引額のうち、明細行に按分した金額です。
+type DiscountAllocation struct {
+	LineID string `json:"line_id"`
+	Amount Money  `json:"amount"`
+}
+
+// Discount は注文に適用した割引です。
+// Amount と Allocations は Rule から再計算される値で、商品の追加などで対象額が変わると更新されます。
+// 明細行ごとに按分することで、割引後の金額が正しい税率で課税されます。
+type Discount struct {
+	ID          string         `json:"id"`
+	Source      DiscountSource `json:"source"`
+	PromotionID string         `json:"promotion_id,omitempty"`
+	Code        string         `json:"code,omitempty"`
+	Rule        DiscountRule   `json:"rule"`
+	// Reason と AppliedBy は手動割引の理由と適用したスタッフ（"manager:<email>" など）です。
+	Reason    string `json:"reason,omitempty"`
+	AppliedBy string `json:"applied_by,omitempty"`
+
+	Amount      Money                `json:"amount"`
+	Allocations []DiscountAllocation `json:"allocations"`
+	AppliedAt   time.Time            `json:"applied_at"`
+}
+
+// NewManualDiscount はスタッフが手動で適用する割引を作成します。
+func NewManualDiscount(rule DiscountRule, reason, appliedBy string, now time.Time) (*Discount, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrDiscountReasonRequired
+	}
+	return &Discount{
+		ID:        GenerateUniqueID(DiscountPrefix),
+		Source:    DiscountSourceManual,
+		Rule:      rule,
+		Reason:    reason,
+		AppliedBy: appliedBy,
+		AppliedAt: now.UTC(),
+	}, nil
+}
+
+// --- Session の割引 ---
+
+// Subtotal は割引前の商品の小計の合計を返します。
+func (s *Session) Subtotal() (Money, error) {
+	total := Zero(s.Currency())
+	for _, item := range s.Items {
+		subtotal, err := item.Subtotal()
+		if err != nil {
+			return Money{}, err
+		}
+		if total, err = total.Add(subtotal); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// DiscountTotal は適用中の割引額の合計を返します。
+func (s *Session) DiscountTotal() Money {
+	total := Zero(s.Currency())
+	for _, d := range s.Discounts {
+		total.Amount += d.Amount.Amount
+	}
+	return total
+}
+
+// ApplyDiscount は注文に割引を追加し、合計金額と税額を再計算します。
+// 対象となる商品がなく割引額が0になる場合は追加しません。
+func (s *Session) ApplyDiscount(discount *Discount) error {
+	if !s.canChangeItems() {
+		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrDiscountNotAllowed, s.Status)
+	}
+	if err := discount.Rule.Validate(); err != nil {
+		return err
+	}
+
+	s.Discounts = append(s.Discounts, *discount)
+	if err := s.RecalculateTotalAmount(); err != nil {
+		s.Discounts = s.Discounts[:len(s.Discounts)-1]
+		return err
+	}
+	applied := s.Discounts[len(s.Discounts)-1]
+	if applied.Amount.IsZero() {
+		s.Discounts = s.Discounts[:len(s.Discounts)-1]
+		if err := s.RecalculateTotalAmount(); err != nil {
+			return err
+		}
+		return ErrDiscountNotApplicable
+	}
+
+	*discount = applied
+	return nil
+}
+
+// RemoveDiscount は適用中の割引を取り消し、合計金額と税額を再計算します。
+func (s *Session) RemoveDiscount(discountID string) (*Discount, error) {
+	if !s.canChangeItems() {
+		return nil, fmt.Errorf("%w: 現在のステータスは '%s'", ErrDiscountNotAllowed, s.Status)
+	}
+
+	i := slices.IndexFunc(s.Discounts, func(d Discount) bool { return d.ID == discountID })
+	if i < 0 {
+		return nil, ErrDiscountNotFound
+	}
+	removed := s.Discounts[i]
+	s.Discounts = slices.Delete(s.Discounts, i, i+1)
+	if err := s.RecalculateTotalAmount(); err != nil {
+		return nil, err
+	}
+	return &removed, nil
+}
+
+// LineTotal は明細行の割引後の金額を返します。返金額の上限の計算に使用します。
+func (s *Session) LineTotal(lineID string) (Money, error) {
+	i := slices.IndexFunc(s.Items, func(item Order) bool { return item.LineID == lineID })
+	if i < 0 {
+		return Money{}, ErrOrderLineNotFound
+	}
+
+	total, err := s.Items[i].Subtotal()
+	if err != nil {
+		return Money{}, err
+	}
+	for _, d := range s.Discounts {
+		for _, a := range d.Allocations {
+			if a.LineID == lineID {
+				total.Amount -= a.Amount.Amount
+			}
+		}
+	}
+	return total, nil
+}
+
+// applyDiscounts は適用順に割引額を計算し、明細行ごとの割引後の金額を返します。
+// 後から適用した割引は、先に適用した割引の後の金額に対して計算します。
+func (s *Session) applyDiscounts() ([]Money, error) {
+	currency := s.Currency()
+	remaining := make([]Money, len(s.Items))
+	for i, item := range s.Items {
+		subtotal, err := item.Subtotal()
+		if err != nil {
+			return nil, err
+		}
+		remaining[i] = subtotal
+	}
+
+	for i := range s.Discounts {
+		d := &s.Discounts[i]
+
+		var targets []int
+		var weights []int64
+		base := Zero(currency)
+		for j, item := range s.Items {
+			if d.Rule.Matches(item) && remaining[j].Amount > 0 {
+				targets = append(targets, j)
+				weights = append(weights, remaining[j].Amount)
+				base.Amount += remaining[j].Amount
+			}
+		}
+
+		amount := Zero(currency)
+		switch d.Rule.Kind {
+		case DiscountPercentage:
+			var err error
+			if amount, err = base.MulRatio(d.Rule.Value, 100, RoundDown); err != nil {
+				return nil, err
+			}
+		case DiscountFixed:
+			amount = NewMoney(min(d.Rule.Value, base.Amount), currency)
+		}
+
+		shares, err := AllocateMoney(amount, weights)
+		if err != nil {
+			return nil, err
+		}
+		d.Amount = amount
+		d.Allocations = make([]DiscountAllocation, len(targets))
+		for k, j := range targets {
+			d.Allocations[k] = DiscountAllocation{LineID: s.Items[j].LineID, Amount: shares[k]}
+			remaining[j].Amount -= shares[k].Amount
+		}
+	}
+	return remaining, nil
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountRule_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     DiscountRule
+		expected error
+	}{
+		{"注文全体の割合割引", DiscountRule{Kind: DiscountPercentage, Value: 10, Scope: DiscountScopeOrder}, nil},
+		{"商品の固定額割引", DiscountRule{Kind: DiscountFixed, Value: 100, Scope: DiscountScopeItem, ProductIDs: []string{"beer"}}, nil},
+		{"カテゴリ割引", DiscountRule{Kind: DiscountPercentage, Value: 50, Scope: DiscountScopeCategory, Categories: []string{"drink"}}, nil},
+		{"割合が100を超える", DiscountRule{Kind: DiscountPercentage, Value: 101, Scope: DiscountScopeOrder}, ErrInvalidDiscountValue},
+		{"固定額が0", DiscountRule{Kind: DiscountFixed, Value: 0, Scope: DiscountScopeOrder}, ErrInvalidDiscountValue},
+		{"種類が不正", DiscountRule{Kind: "bogo", Value: 1, Scope: DiscountScopeOrder}, ErrInvalidDiscountKind},
+		{"範囲が不正", DiscountRule{Kind: DiscountFixed, Value: 1, Scope: "seat"}, ErrInvalidDiscountScope},
+		{"商品の指定がない", DiscountRule{Kind: DiscountFixed, Value: 1, Scope: DiscountScopeItem}, ErrDiscountTargetRequired},
+		{"カテゴリの指定がない", DiscountRule{Kind: DiscountFixed, Value: 1, Scope: DiscountScopeCategory}, ErrDiscountTargetRequired},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+// newDiscountTestSession は標準税率と軽減税率の商品を含む持ち帰りの注文を作成します。
+func newDiscountTestSession(t *testing.T) *Session {
+	t.Helper()
+	session, err := NewSession("store_1", "seat_1", []Order{
+		*NewOrder("bento", 1, Yen(1080)).WithTaxCategory(TaxCategoryFood).WithCategory("food"),
+		*NewOrder("beer", 2, Yen(550)).WithCategory("drink"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, session.SetTaxPolicy(DefaultTaxPolicy(), DiningTakeout))
+	return session
+}
+
+func TestSession_ApplyDiscount(t *testing.T) {
+	now := time.Now()
+
+	t.Run("注文全体の割引は税率ごとに按分される", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		discount, err := NewManualDiscount(DiscountRule{Kind: DiscountPercentage, Value: 10, Scope: DiscountScopeOrder}, "常連のお客様", "manager:a@example.com", now)
+		require.NoError(t, err)
+		require.NoError(t, session.ApplyDiscount(discount))
+
+		assert.Equal(t, Yen(218), discount.Amount)
+		require.Len(t, discount.Allocations, 2)
+		assert.Equal(t, Yen(108), discount.Allocations[0].Amount)
+		assert.Equal(t, Yen(110), discount.Allocations[1].Amount)
+
+		subtotal, err := session.Subtotal()
+		require.NoError(t, err)
+		assert.Equal(t, Yen(2180), subtotal)
+		assert.Equal(t, Yen(218), session.DiscountTotal())
+		assert.Equal(t, Yen(1962), session.TotalAmount)
+
+		// 標準税率 990円（内税90円）、軽減税率 972円（内税72円）
+		require.Len(t, session.Taxes, 2)
+		assert.Equal(t, Yen(990), session.Taxes[0].Gross)
+		assert.Equal(t, Yen(90), session.Taxes[0].Tax)
+		assert.Equal(t, Yen(972), session.Taxes[1].Gross)
+		assert.Equal(t, Yen(72), session.Taxes[1].Tax)
+	})
+
+	t.Run("カテゴリ割引は対象の税率のみ減額", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		discount, err := NewManualDiscount(DiscountRule{Kind: DiscountPercentage, Value: 50, Scope: DiscountScopeCategory, Categories: []string{"drink"}}, "ハッピーアワー", "", now)
+		require.NoError(t, err)
+		require.NoError(t, session.ApplyDiscount(discount))
+
+		assert.Equal(t, Yen(550), discount.Amount)
+		assert.Equal(t, Yen(550), session.Taxes[0].Gross)
+		assert.Equal(t, Yen(1080), session.Taxes[1].Gross)
+	})
+
+	t.Run("固定額は対象額を超えない", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		discount, err := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 5000, Scope: DiscountScopeItem, ProductIDs: []string{"bento"}}, "作り直し", "", now)
+		require.NoError(t, err)
+		require.NoError(t, session.ApplyDiscount(discount))
+
+		assert.Equal(t, Yen(1080), discount.Amount)
+		assert.Equal(t, Yen(1100), session.TotalAmount)
+	})
+
+	t.Run("割引は後から適用したものほど割引後の金額に対して計算", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		first, _ := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 180, Scope: DiscountScopeOrder}, "a", "", now)
+		second, _ := NewManualDiscount(DiscountRule{Kind: DiscountPercentage, Value: 10, Scope: DiscountScopeOrder}, "b", "", now)
+		require.NoError(t, session.ApplyDiscount(first))
+		require.NoError(t, session.ApplyDiscount(second))
+
+		assert.Equal(t, Yen(200), session.Discounts[1].Amount)
+		assert.Equal(t, Yen(1800), session.TotalAmount)
+	})
+
+	t.Run("対象商品がない場合は適用しない", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		total := session.TotalAmount
+		discount, _ := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 100, Scope: DiscountScopeCategory, Categories: []string{"dessert"}}, "a", "", now)
+
+		assert.ErrorIs(t, session.ApplyDiscount(discount), ErrDiscountNotApplicable)
+		assert.Empty(t, session.Discounts)
+		assert.Equal(t, total, session.TotalAmount)
+	})
+
+	t.Run("商品を追加すると割合の割引は再計算される", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		discount, _ := NewManualDiscount(DiscountRule{Kind: DiscountPercentage, Value: 10, Scope: DiscountScopeOrder}, "a", "", now)
+		require.NoError(t, session.ApplyDiscount(discount))
+		require.NoError(t, session.AddItem(*NewOrder("beer", 1, Yen(550))))
+
+		assert.Equal(t, Yen(273), session.Discounts[0].Amount)
+	})
+
+	t.Run("確定後の注文には適用できない", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		session.Status = StatusCompleted
+		discount, _ := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 100, Scope: DiscountScopeOrder}, "a", "", now)
+		assert.ErrorIs(t, session.ApplyDiscount(discount), ErrDiscountNotAllowed)
+	})
+
+	t.Run("手動割引は理由が必須", func(t *testing.T) {
+		_, err := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 100, Scope: DiscountScopeOrder}, " ", "", now)
+		assert.ErrorIs(t, err, ErrDiscountReasonRequired)
+	})
+}
+
+func TestSession_RemoveDiscount(t *testing.T) {
+	session := newDiscountTestSession(t)
+	original := session.TotalAmount
+	discount, _ := NewManualDiscount(DiscountRule{Kind: DiscountFixed, Value: 100, Scope: DiscountScopeOrder}, "a", "", time.Now())
+	require.NoError(t, session.ApplyDiscount(discount))
+
+	removed, err := session.RemoveDiscount(discount.ID)
+	require.NoError(t, err)
+	assert.Equal(t, discount.ID, removed.ID)
+	assert.Equal(t, original, session.TotalAmount)
+
+	_, err = session.RemoveDiscount(discount.ID)
+	assert.ErrorIs(t, err, ErrDiscountNotFound)
+}
+
+func TestSession_LineTotal(t *testing.T) {
+	session := newDiscountTestSession(t)
+	discount, _ := NewManualDiscount(DiscountRule{Kind: DiscountPercentage, Value: 10, Scope: DiscountScopeOrder}, "a", "", time.Now())
+	require.NoError(t, session.ApplyDiscount(discount))
+
+	total, err := session.LineTotal(session.Items[1].LineID)
+	require.NoError(t, err)
+	assert.Equal(t, Yen(990), total, "割引後の金額が返金の上限になる")
+
+	_, err = session.LineTotal("line_unknown")
+	assert.ErrorIs(t, err, ErrOrderLineNotFound)
+}
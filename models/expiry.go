@@ -0,0 +1,221 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// --- 時間経過による自動のステータス遷移 ---
+
+// SweeperActor は自動のステータス遷移を履歴に記録する際の操作者です。
+const SweeperActor = "system:expiry_sweeper"
+
+// ExpiryAction は店舗に確認されないまま有効期限を過ぎた注文の扱いです。
+type ExpiryAction string
+
+const (
+	// ExpiryCancel は期限切れの注文をキャンセルします（既定）。
+	ExpiryCancel ExpiryAction = "cancel"
+	// ExpiryDecline は期限切れの注文を店舗が受け付けなかった注文（Declined）として締めます。
+	ExpiryDecline ExpiryAction = "decline"
+	// ExpiryKeep は期限切れの注文をそのまま残し、スタッフの判断に任せます。
+	ExpiryKeep ExpiryAction = "keep"
+)
+
+const (
+	DefaultAutoCompleteAfter = 30 * time.Minute
+	DefaultVisitIdleTimeout  = 3 * time.Hour
+
+	// 自動完了・来店の自動終了までの時間の上限
+	MaxExpiryDelay = 24 * time.Hour
+)
+
+var ErrInvalidExpiryPolicy = errors.New("自動のステータス遷移の設定が不正です")
+
+// IsValid は定義済みの扱いかどうかを返します。
+func (a ExpiryAction) IsValid() bool {
+	switch a {
+	case ExpiryCancel, ExpiryDecline, ExpiryKeep:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExpiryPolicy は店舗ごとの自動のステータス遷移の設定です。
+// 未設定（ゼロ値）の項目は WithDefaults で既定値になります。
+type ExpiryPolicy struct {
+	// 店舗に確認されないまま有効期限（Session.ExpiresAt）を過ぎた注文の扱い
+	UnconfirmedAction ExpiryAction
+	// 提供済みで支払い済みの注文を完了にするまでの時間
+	AutoCompleteAfter time.Duration
+	// 未払いの注文がなく、操作のない来店を終了するまでの時間
+	VisitIdleTimeout time.Duration
+}
+
+// DefaultExpiryPolicy は既定の設定（キャンセル、30分後に自動完了、3時間で来店を終了）を返します。
+func DefaultExpiryPolicy() ExpiryPolicy {
+	return ExpiryPolicy{
+		UnconfirmedAction: ExpiryCancel,
+		AutoCompleteAfter: DefaultAutoCompleteAfter,
+		VisitIdleTimeout:  DefaultVisitIdleTimeout,
+	}
+}
+
+// WithDefaults は未設定の項目を既定値で補った設定を返します。
+func (p ExpiryPolicy) WithDefaults() ExpiryPolicy {
+	d := DefaultExpiryPolicy()
+	if p.UnconfirmedAction == "" {
+		p.UnconfirmedAction = d.UnconfirmedAction
+	}
+	if p.AutoCompleteAfter == 0 {
+		p.AutoCompleteAfter = d.AutoCompleteAfter
+	}
+	if p.VisitIdleTimeout == 0 {
+		p.VisitIdleTimeout = d.VisitIdleTimeout
+	}
+	return p
+}
+
+// Validate は設定を検証します。時間は0（既定値）または1分以上24時間以下である必要があります。
+func (p ExpiryPolicy) Validate() error {
+	if p.UnconfirmedAction != "" && !p.UnconfirmedAction.IsValid() {
+		return fmt.Errorf("%w: 期限切れの注文の扱い %q", ErrInvalidExpiryPolicy, p.UnconfirmedAction)
+	}
+	delays := []struct {
+		label string
+		value time.Duration
+	}{
+		{"自動完了までの時間", p.AutoCompleteAfter},
+		{"来店を終了するまでの時間", p.VisitIdleTimeout},
+	}
+	for _, d := range delays {
+		if d.value != 0 && (d.value < time.Minute || d.value > MaxExpiryDelay) {
+			return fmt.Errorf("%w: %s %s", ErrInvalidExpiryPolicy, d.label, d.value)
+		}
+	}
+	return nil
+}
+
+// ExpiryPolicy は店舗の自動のステータス遷移の設定を返します。未設定の項目は既定値です。
+func (s *Store) ExpiryPolicy() ExpiryPolicy {
+	return ExpiryPolicy{
+		UnconfirmedAction: s.UnconfirmedAction,
+		AutoCompleteAfter: s.AutoCompleteAfter,
+		VisitIdleTimeout:  s.VisitIdleTimeout,
+	}.WithDefaults()
+}
+
+// --- Session の自動のステータス遷移 ---
+
+// IsAwaitingConfirmation は店舗の確認（先払いの店舗では支払い）を待っている注文かどうかを返します。
+func (s *Session) IsAwaitingConfirmation() bool {
+	return s.Status == s.Workflow().Initial() || s.Status == StatusPendingConfirmation
+}
+
+// ShouldExpireUnconfirmed は店舗に確認されないまま有効期限を過ぎ、ExpireUnconfirmed でキャンセル・辞退にする注文かどうかを返します。
+// 支払い手続き中・支払い済みの注文は返金などの判断が必要なため対象外です。
+func (s *Session) ShouldExpireUnconfirmed(policy ExpiryPolicy, now time.Time) bool {
+	policy = policy.WithDefaults()
+	if policy.UnconfirmedAction == ExpiryKeep || !s.IsAwaitingConfirmation() {
+		return false
+	}
+	return now.After(s.ExpiresAt) && s.paymentStatus().AcceptsChanges()
+}
+
+// ExpireUnconfirmed は店舗に確認されないまま有効期限を過ぎた注文を、設定に従ってキャンセル・辞退にします。
+// ShouldExpireUnconfirmed の対象外の注文は変更しません。遷移した場合は true を返します。
+func (s *Session) ExpireUnconfirmed(policy ExpiryPolicy, now time.Time) (bool, error) {
+	if !s.ShouldExpireUnconfirmed(policy, now) {
+		return false, nil
+	}
+	policy = policy.WithDefaults()
+
+	target := StatusCancelled
+	if policy.UnconfirmedAction == ExpiryDecline && s.Workflow().CanTransition(s.Status, StatusDeclined) {
+		target = StatusDeclined
+	}
+	reason := ReasonCode{Code: ReasonCodeExpired, Label: "確認されないまま有効期限を過ぎたため自動で締め"}
+	if err := s.UpdateStatusWithReason(target, SweeperActor, reason, ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// fulfilledAt は注文が現在のステータス（提供済みなど）になった日時を返します。
+func (s *Session) fulfilledAt() time.Time {
+	for i := len(s.StatusHistory) - 1; i >= 0; i-- {
+		if s.StatusHistory[i].To == s.Status {
+			return s.StatusHistory[i].At
+		}
+	}
+	return s.UpdatedAt
+}
+
+// ShouldAutoComplete は提供済みで支払い済みの注文のうち、設定した時間が経過し AutoComplete で完了にする注文かどうかを返します。
+// 未払いの注文はレジでの精算で完了にするため対象外です。
+func (s *Session) ShouldAutoComplete(policy ExpiryPolicy, now time.Time) bool {
+	policy = policy.WithDefaults()
+	if !s.Status.IsFulfilled() || !s.paymentStatus().IsPaid() {
+		return false
+	}
+	if !s.Workflow().CanTransition(s.Status, StatusCompleted) {
+		return false
+	}
+	return now.Sub(s.fulfilledAt()) >= policy.AutoCompleteAfter
+}
+
+// AutoComplete は提供済みで支払い済みの注文を、設定した時間の経過後に完了にします。
+// ShouldAutoComplete の対象外の注文は変更しません。遷移した場合は true を返します。
+func (s *Session) AutoComplete(policy ExpiryPolicy, now time.Time) (bool, error) {
+	if !s.ShouldAutoComplete(policy, now) {
+		return false, nil
+	}
+	if err := s.UpdateStatusBy(StatusCompleted, SweeperActor, "提供から一定時間が経過したため自動で完了"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// --- Visit の自動終了 ---
+
+// IsAbandoned は操作がないまま設定した時間が経過し、終了してよい来店かどうかを判定します。
+// 注文が全て最終状態で、未払いの注文がない場合のみ終了できます（未払いの注文はレジでの精算を待ちます）。
+func (v *Visit) IsAbandoned(sessions []*Session, policy ExpiryPolicy, now time.Time) bool {
+	policy = policy.WithDefaults()
+	if !v.IsOpen() {
+		return false
+	}
+	lastActivity := v.UpdatedAt
+	for _, s := range sessions {
+		if !s.Workflow().IsFinal(s.Status) {
+			return false
+		}
+		if s.isBillable() && !s.paymentStatus().IsPaid() {
+			return false
+		}
+		if s.UpdatedAt.After(lastActivity) {
+			lastActivity = s.UpdatedAt
+		}
+	}
+	return now.Sub(lastActivity) >= policy.VisitIdleTimeout
+}
+
+// Abandon は操作のない来店を終了します。終了済みの場合は false を返します。
+func (v *Visit) Abandon(now time.Time) bool {
+	if !v.IsOpen() {
+		return false
+	}
+	v.Status = VisitAbandoned
+	v.ClosedAt = now.UTC()
+	v.UpdatedAt = now.UTC()
+	return true
+}
+
+// SweepResult は自動のステータス遷移の結果です。
+type SweepResult struct {
+	Expired      int
+	Completed    int
+	VisitsClosed int
+}
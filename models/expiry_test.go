@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryPolicy_Validate(t *testing.T) {
+	assert.NoError(t, ExpiryPolicy{}.Validate(), "未設定は既定値")
+	assert.NoError(t, ExpiryPolicy{UnconfirmedAction: ExpiryDecline, AutoCompleteAfter: time.Hour}.Validate())
+	assert.ErrorIs(t, ExpiryPolicy{UnconfirmedAction: "delete"}.Validate(), ErrInvalidExpiryPolicy)
+	assert.ErrorIs(t, ExpiryPolicy{AutoCompleteAfter: time.Second}.Validate(), ErrInvalidExpiryPolicy)
+	assert.ErrorIs(t, ExpiryPolicy{VisitIdleTimeout: 48 * time.Hour}.Validate(), ErrInvalidExpiryPolicy)
+
+	assert.Equal(t, DefaultExpiryPolicy(), (&Store{}).ExpiryPolicy())
+	assert.Equal(t, ExpiryDecline, (&Store{UnconfirmedAction: ExpiryDecline}).ExpiryPolicy().UnconfirmedAction)
+}
+
+func TestSession_ExpireUnconfirmed(t *testing.T) {
+	t.Run("有効期限を過ぎた未確認の注文をキャンセル", func(t *testing.T) {
+		s := newTestSession(t)
+		changed, err := s.ExpireUnconfirmed(ExpiryPolicy{}, s.ExpiresAt)
+		require.NoError(t, err)
+		assert.False(t, changed, "有効期限ちょうどは期限内")
+
+		changed, err = s.ExpireUnconfirmed(ExpiryPolicy{}, s.ExpiresAt.Add(time.Second))
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, StatusCancelled, s.Status)
+		last := s.StatusHistory[len(s.StatusHistory)-1]
+		assert.Equal(t, SweeperActor, last.Actor)
+	})
+
+	t.Run("店舗の設定に従って辞退・そのまま残す", func(t *testing.T) {
+		s := newTestSession(t)
+		after := s.ExpiresAt.Add(time.Minute)
+		changed, err := s.ExpireUnconfirmed(ExpiryPolicy{UnconfirmedAction: ExpiryKeep}, after)
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		_, err = s.ExpireUnconfirmed(ExpiryPolicy{UnconfirmedAction: ExpiryDecline}, after)
+		require.NoError(t, err)
+		assert.Equal(t, StatusDeclined, s.Status)
+	})
+
+	t.Run("確認済み・支払い済みの注文は変更しない", func(t *testing.T) {
+		confirmed := newTestSession(t)
+		require.NoError(t, confirmed.UpdateStatus(StatusConfirmed))
+		changed, err := confirmed.ExpireUnconfirmed(ExpiryPolicy{}, confirmed.ExpiresAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		paid := newTestSession(t)
+		require.NoError(t, paid.UpdatePaymentStatus(PaymentStatusPaid, "", "レジでの精算"))
+		changed, err = paid.ExpireUnconfirmed(ExpiryPolicy{}, paid.ExpiresAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, StatusCreated, paid.Status)
+	})
+}
+
+func TestSession_AutoComplete(t *testing.T) {
+	serve := func(t *testing.T) *Session {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+		for _, line := range s.Items {
+			require.NoError(t, s.UpdateLineStatus(line.LineID, LineCooking, "", time.Now()))
+			require.NoError(t, s.UpdateLineStatus(line.LineID, LineServed, "", time.Now()))
+		}
+		require.Equal(t, StatusServed, s.Status)
+		return s
+	}
+
+	t.Run("提供から一定時間が経過した支払い済みの注文を完了", func(t *testing.T) {
+		s := serve(t)
+		require.NoError(t, s.UpdatePaymentStatus(PaymentStatusPaid, "", "オンライン決済"))
+		servedAt := s.fulfilledAt()
+
+		changed, err := s.AutoComplete(ExpiryPolicy{}, servedAt.Add(DefaultAutoCompleteAfter-time.Second))
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		changed, err = s.AutoComplete(ExpiryPolicy{}, servedAt.Add(DefaultAutoCompleteAfter))
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, StatusCompleted, s.Status)
+	})
+
+	t.Run("未払いの注文はレジでの精算を待つ", func(t *testing.T) {
+		s := serve(t)
+		changed, err := s.AutoComplete(ExpiryPolicy{}, time.Now().Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, StatusServed, s.Status)
+	})
+}
+
+func TestVisit_IsAbandoned(t *testing.T) {
+	now := time.Now()
+	visit := NewVisit(&Seat{ID: "seat_456", StoreID: "store_123", CurrentVisitID: "visit_1"}, now.Add(-4*time.Hour))
+
+	assert.True(t, visit.IsAbandoned(nil, ExpiryPolicy{}, now), "注文のないまま操作がない")
+	assert.False(t, visit.IsAbandoned(nil, ExpiryPolicy{VisitIdleTimeout: 5 * time.Hour}, now))
+
+	unpaid := newTestSession(t)
+	unpaid.UpdatedAt = now.Add(-4 * time.Hour)
+	assert.False(t, visit.IsAbandoned([]*Session{unpaid}, ExpiryPolicy{}, now), "未確定の注文がある")
+
+	require.NoError(t, unpaid.UpdateStatus(StatusCancelled))
+	unpaid.UpdatedAt = now.Add(-time.Hour)
+	assert.False(t, visit.IsAbandoned([]*Session{unpaid}, ExpiryPolicy{}, now), "最後の操作から時間が経っていない")
+	assert.True(t, visit.IsAbandoned([]*Session{unpaid}, ExpiryPolicy{VisitIdleTimeout: time.Hour}, now))
+
+	assert.True(t, visit.Abandon(now))
+	assert.Equal(t, VisitAbandoned, visit.Status)
+	assert.False(t, visit.IsOpen())
+	assert.False(t, visit.Abandon(now))
+	assert.ErrorIs(t, visit.AddRound(unpaid), ErrVisitClosed)
+}
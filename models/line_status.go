@@ -0,0 +1,291 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- 明細行ごとの提供状況 ---
+
+// LineStatus は注文の明細行（料理1品）ごとの提供状況です。
+// 厨房は料理を1品ずつ提供するため、注文全体のステータスは明細行の状況から導出します。
+type LineStatus string
+
+const (
+	// LineQueued は厨房で調理を待っている明細です。未設定の場合もこの状況として扱います。
+	LineQueued LineStatus = "queued"
+	// LineCooking は調理中の明細です。
+	LineCooking LineStatus = "cooking"
+	// LineServed は提供済みの明細です。
+	LineServed LineStatus = "served"
+	// LineVoided は取り消した明細です。金額は0として合計金額から除きます。
+	LineVoided LineStatus = "voided"
+)
+
+var (
+	ErrInvalidLineStatus              = errors.New("明細のステータスが不正です")
+	ErrLineAlreadyStarted             = errors.New("調理が始まった明細は取り消し・数量の変更ができません")
+	ErrLineVoidReasonRequired         = errors.New("明細の取り消し・数量の変更の理由を指定してください")
+	ErrInvalidLineQuantity            = errors.New("明細の数量は1以上を指定してください（0にする場合は取り消してください）")
+	ErrLineChangeNotAllowed           = errors.New("支払い手続き中・支払い済み、または最終状態の注文の明細は変更できません")
+	ErrLineQuantityUnchanged          = errors.New("明細の数量が変わっていません")
+	ErrLineQuantityIncreaseNotAllowed = errors.New("現在のステータスでは明細の数量を増やせません")
+	ErrOrderNotConfirmed              = errors.New("店舗が確認する前の注文の明細は調理を始められません")
+)
+
+// InvalidLineStatusTransitionError は許可されていない明細のステータスの遷移を表します。
+type InvalidLineStatusTransitionError struct {
+	LineID string
+	From   LineStatus
+	To     LineStatus
+}
+
+func (e *InvalidLineStatusTransitionError) Error() string {
+	return fmt.Sprintf("明細 %s のステータスを '%s' から '%s' に遷移させることはできません", e.LineID, e.From, e.To)
+}
+
+// IsValid は定義済みのステータスかどうかを返します。
+func (l LineStatus) IsValid() bool {
+	switch l {
+	case LineQueued, LineCooking, LineServed, LineVoided:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo は明細のステータスを newStatus に遷移できるかどうかを判定します。
+// 取り消しは調理前の明細のみで、VoidLine を使用します。
+func (l LineStatus) CanTransitionTo(newStatus LineStatus) bool {
+	switch l {
+	case "", LineQueued:
+		return newStatus == LineCooking || newStatus == LineVoided
+	case LineCooking:
+		return newStatus == LineServed
+	default:
+		return false
+	}
+}
+
+// QuantityAdjustment は明細の数量の変更の記録です。
+type QuantityAdjustment struct {
+	From   int       `json:"from"`
+	To     int       `json:"to"`
+	Reason string    `json:"reason"`
+	Actor  string    `json:"actor,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// LineStatus は明細のステータスを返します。未設定の場合は調理待ちです。
+func (oi *Order) LineStatus() LineStatus {
+	if oi.Status == "" {
+		return LineQueued
+	}
+	return oi.Status
+}
+
+// IsVoided は取り消した明細かどうかを返します。
+func (oi *Order) IsVoided() bool {
+	return oi.Status == LineVoided
+}
+
+// --- Session の明細の操作 ---
+
+// findLine は明細行IDから明細のインデックスを返します。
+func (s *Session) findLine(lineID string) (int, error) {
+	for i := range s.Items {
+		if s.Items[i].LineID == lineID {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: %s", ErrOrderLineNotFound, lineID)
+}
+
+// canChangeLines は明細の取り消し・数量の変更ができるかどうかを判定します。
+// 金額が変わるため、支払い手続き中・支払い済みの注文と、最終状態の注文は変更できません。
+func (s *Session) canChangeLines() bool {
+	return !s.Workflow().IsFinal(s.Status) && s.paymentStatus().AcceptsChanges()
+}
+
+// awaitsConfirmation は店舗の確認を待っている注文かどうかを返します。
+// 確認済み（`Confirmed`）の状態を持つワークフローでは、スタッフが注文を確認するまで調理を始めません。
+func (s *Session) awaitsConfirmation() bool {
+	workflow := s.Workflow()
+	if !workflow.HasState(StatusConfirmed) {
+		return false
+	}
+	return s.Status == workflow.Initial() || s.Status == StatusPendingConfirmation
+}
+
+// UpdateLineStatus は厨房での調理の開始・提供など、明細のステータスを更新します。
+// 注文全体のステータスは明細の状況から導出して更新します。店舗の確認前の注文は調理を始められません。
+func (s *Session) UpdateLineStatus(lineID string, status LineStatus, actor string, now time.Time) error {
+	if !status.IsValid() || status == LineVoided {
+		return fmt.Errorf("%w: %q", ErrInvalidLineStatus, status)
+	}
+	if s.Workflow().IsFinal(s.Status) {
+		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrOrderAlreadyFinal, s.Status)
+	}
+	i, err := s.findLine(lineID)
+	if err != nil {
+		return err
+	}
+	line := &s.Items[i]
+	if !line.LineStatus().CanTransitionTo(status) {
+		return &InvalidLineStatusTransitionError{LineID: lineID, From: line.LineStatus(), To: status}
+	}
+	if status == LineCooking && s.awaitsConfirmation() {
+		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrOrderNotConfirmed, s.Status)
+	}
+	// 先払いの店舗では、支払いが完了するまで調理を始めない
+	if status == LineCooking && s.Workflow().RequiresPayment(StatusPreparing) && !s.paymentStatus().IsPaid() {
+		return fmt.Errorf("%w: 支払い状態は '%s'", ErrPaymentRequired, s.paymentStatus())
+	}
+
+	line.Status = status
+	line.UpdatedAt = now.UTC()
+	s.setUpdatedAt()
+	s.syncStatusFromLines(actor)
+	return nil
+}
+
+// VoidLine は調理前の明細を理由を添えて取り消し、合計金額を再計算します。
+// 全ての明細を取り消した場合は、注文をキャンセルします。
+func (s *Session) VoidLine(lineID, reason, actor string, now time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrLineVoidReasonRequired
+	}
+	if !s.canChangeLines() {
+		return ErrLineChangeNotAllowed
+	}
+	i, err := s.findLine(lineID)
+	if err != nil {
+		return err
+	}
+	line := &s.Items[i]
+	if line.LineStatus() != LineQueued {
+		if line.IsVoided() {
+			return &InvalidLineStatusTransitionError{LineID: lineID, From: LineVoided, To: LineVoided}
+		}
+		return fmt.Errorf("%w: %s", ErrLineAlreadyStarted, lineID)
+	}
+
+	previous := *line
+	line.Status = LineVoided
+	line.VoidReason = reason
+	line.VoidedBy = actor
+	line.VoidedAt = now.UTC()
+	line.UpdatedAt = now.UTC()
+	if err := s.RecalculateTotalAmount(); err != nil {
+		s.Items[i] = previous
+		return err
+	}
+	s.syncStatusFromLines(actor)
+	return nil
+}
+
+// AdjustLineQuantity は調理前の明細の数量を理由を添えて変更し、合計金額を再計算します。
+// 数量を増やす場合は、商品の追加と同じく追加を受け付けるステータスである必要があります。
+func (s *Session) AdjustLineQuantity(lineID string, quantity int, reason, actor string, now time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrLineVoidReasonRequired
+	}
+	if quantity <= 0 {
+		return ErrInvalidLineQuantity
+	}
+	if err := validateQuantity(quantity); err != nil {
+		return err
+	}
+	if !s.canChangeLines() {
+		return ErrLineChangeNotAllowed
+	}
+	i, err := s.findLine(lineID)
+	if err != nil {
+		return err
+	}
+	line := &s.Items[i]
+	if line.LineStatus() != LineQueued {
+		return fmt.Errorf("%w: %s", ErrLineAlreadyStarted, lineID)
+	}
+	if quantity == line.Quantity {
+		return ErrLineQuantityUnchanged
+	}
+	if quantity > line.Quantity && !s.canChangeItems() {
+		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrLineQuantityIncreaseNotAllowed, s.Status)
+	}
+
+	previous := *line
+	line.Adjustments = append(line.Adjustments, QuantityAdjustment{
+		From:   line.Quantity,
+		To:     quantity,
+		Reason: reason,
+		Actor:  actor,
+		At:     now.UTC(),
+	})
+	line.Quantity = quantity
+	line.UpdatedAt = now.UTC()
+	if err := s.RecalculateTotalAmount(); err != nil {
+		s.Items[i] = previous
+		return err
+	}
+	return nil
+}
+
+// DerivedStatus は明細の状況から導出した注文のステータスを返します。
+// 全ての明細を取り消した場合は `Cancelled`、取り消していない明細が全て提供済みの場合は店内飲食なら `Served`、
+// 持ち帰りなら `ReadyForPickup`、調理中・提供済みの明細がある場合は `Preparing` で、全て調理待ちの場合は空を返します。
+func (s *Session) DerivedStatus() Status {
+	if statuses := s.derivedStatuses(); len(statuses) > 0 {
+		return statuses[0]
+	}
+	return ""
+}
+
+// derivedStatuses は明細の状況から導出した注文のステータスの候補を、優先する順に返します。
+// 全ての明細を提供した場合は、ワークフローに優先する状態がない場合に備えて、もう一方の引き渡しの状態も候補にします。
+func (s *Session) derivedStatuses() []Status {
+	active, cooking, served := 0, 0, 0
+	for i := range s.Items {
+		switch s.Items[i].LineStatus() {
+		case LineVoided:
+			continue
+		case LineCooking:
+			cooking++
+		case LineServed:
+			served++
+		}
+		active++
+	}
+	switch {
+	case active == 0:
+		return []Status{StatusCancelled}
+	case served == active && s.DiningOption == DiningTakeout:
+		return []Status{StatusReadyForPickup, StatusServed}
+	case served == active:
+		return []Status{StatusServed, StatusReadyForPickup}
+	case cooking > 0 || served > 0:
+		return []Status{StatusPreparing}
+	default:
+		return nil
+	}
+}
+
+// syncStatusFromLines は明細の状況から導出したステータスに、ワークフローで直接遷移できる場合のみ注文のステータスを進めます。
+// 途中のステータス（確認済み、提供準備完了など）を自動で経由することはせず、直接遷移できない場合はスタッフの操作に任せます。
+func (s *Session) syncStatusFromLines(actor string) {
+	const reason = "明細の提供状況から更新"
+	workflow := s.Workflow()
+	for _, target := range s.derivedStatuses() {
+		if target == s.Status {
+			return
+		}
+		if workflow.CanTransition(s.Status, target) {
+			_ = s.UpdateStatusBy(target, actor, reason)
+			return
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineStatus_CanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		from     LineStatus
+		to       LineStatus
+		expected bool
+	}{
+		{"", LineCooking, true},
+		{LineQueued, LineCooking, true},
+		{LineQueued, LineVoided, true},
+		{LineQueued, LineServed, false},
+		{LineCooking, LineServed, true},
+		{LineCooking, LineVoided, false},
+		{LineServed, LineCooking, false},
+		{LineVoided, LineQueued, false},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+"_to_"+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+
+	assert.False(t, LineStatus("plated").IsValid())
+	assert.Equal(t, LineQueued, (&Order{}).LineStatus())
+}
+
+func TestSession_UpdateLineStatus(t *testing.T) {
+	now := time.Now()
+
+	t.Run("明細の状況から注文のステータスを導出", func(t *testing.T) {
+		s := newTestSession(t)
+		first, second := s.Items[0].LineID, s.Items[1].LineID
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+
+		require.NoError(t, s.UpdateLineStatus(first, LineCooking, "kitchen", now))
+		assert.Equal(t, StatusPreparing, s.Status)
+		assert.Equal(t, StatusPreparing, s.StatusHistory[2].To)
+		assert.Equal(t, "kitchen", s.StatusHistory[2].Actor)
+
+		require.NoError(t, s.UpdateLineStatus(first, LineServed, "kitchen", now))
+		assert.Equal(t, StatusPreparing, s.Status, "調理待ちの明細が残っている")
+
+		require.NoError(t, s.UpdateLineStatus(second, LineCooking, "kitchen", now))
+		require.NoError(t, s.UpdateLineStatus(second, LineServed, "kitchen", now))
+		assert.Equal(t, StatusServed, s.Status)
+		assert.Len(t, s.StatusHistory, 4, "提供準備完了を経由しない")
+	})
+
+	t.Run("持ち帰りは全て提供すると受け取り待ち", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.ChangeDiningOption(DiningTakeout))
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+		for _, line := range s.Items {
+			require.NoError(t, s.UpdateLineStatus(line.LineID, LineCooking, "", now))
+			require.NoError(t, s.UpdateLineStatus(line.LineID, LineServed, "", now))
+		}
+		assert.Equal(t, StatusReadyForPickup, s.Status)
+	})
+
+	t.Run("店舗の確認前は調理を始めない", func(t *testing.T) {
+		s := newTestSession(t)
+		assert.ErrorIs(t, s.UpdateLineStatus(s.Items[0].LineID, LineCooking, "", now), ErrOrderNotConfirmed)
+		assert.Equal(t, StatusCreated, s.Status)
+
+		require.NoError(t, s.UpdateStatus(StatusPendingConfirmation))
+		assert.ErrorIs(t, s.UpdateLineStatus(s.Items[0].LineID, LineCooking, "", now), ErrOrderNotConfirmed)
+
+		counter := newTestSession(t)
+		require.NoError(t, counter.SetWorkflow(WorkflowCounter))
+		require.NoError(t, counter.UpdateLineStatus(counter.Items[0].LineID, LineCooking, "", now), "確認の状態がないワークフローはすぐに調理できる")
+		assert.Equal(t, StatusPreparing, counter.Status)
+	})
+
+	t.Run("許可されていない遷移", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+		err := s.UpdateLineStatus(s.Items[0].LineID, LineServed, "", now)
+		var transitionErr *InvalidLineStatusTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.ErrorIs(t, s.UpdateLineStatus(s.Items[0].LineID, LineVoided, "", now), ErrInvalidLineStatus)
+		assert.ErrorIs(t, s.UpdateLineStatus("line_unknown", LineCooking, "", now), ErrOrderLineNotFound)
+	})
+
+	t.Run("先払いの店舗では支払い前に調理を始めない", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.SetWorkflow(WorkflowPaymentFirst))
+		assert.ErrorIs(t, s.UpdateLineStatus(s.Items[0].LineID, LineCooking, "", now), ErrPaymentRequired)
+
+		require.NoError(t, s.UpdatePaymentStatus(PaymentStatusPaid, "", "レジでの精算"))
+		require.NoError(t, s.UpdateLineStatus(s.Items[0].LineID, LineCooking, "", now))
+		assert.Equal(t, StatusPreparing, s.Status)
+	})
+}
+
+func TestSession_VoidLine(t *testing.T) {
+	now := time.Now()
+
+	t.Run("取り消した明細を合計金額から除く", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.VoidLine(s.Items[1].LineID, " 品切れ ", "manager:a@example.com", now))
+
+		line := s.Items[1]
+		assert.True(t, line.IsVoided())
+		assert.Equal(t, "品切れ", line.VoidReason)
+		assert.Equal(t, "manager:a@example.com", line.VoidedBy)
+		subtotal, err := line.Subtotal()
+		require.NoError(t, err)
+		assert.True(t, subtotal.IsZero())
+		assert.Equal(t, Yen(200), s.TotalAmount)
+		assert.Equal(t, StatusCreated, s.Status)
+	})
+
+	t.Run("全ての明細を取り消すと注文をキャンセル", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.VoidLine(s.Items[0].LineID, "品切れ", "", now))
+		require.NoError(t, s.VoidLine(s.Items[1].LineID, "品切れ", "", now))
+		assert.Equal(t, StatusCancelled, s.Status)
+		assert.True(t, s.TotalAmount.IsZero())
+	})
+
+	t.Run("調理が始まった明細は取り消せない", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+		require.NoError(t, s.UpdateLineStatus(s.Items[0].LineID, LineCooking, "", now))
+		assert.ErrorIs(t, s.VoidLine(s.Items[0].LineID, "品切れ", "", now), ErrLineAlreadyStarted)
+		assert.ErrorIs(t, s.VoidLine(s.Items[1].LineID, "", "", now), ErrLineVoidReasonRequired)
+	})
+
+	t.Run("支払い済みの注文は取り消せない", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdatePaymentStatus(PaymentStatusPaid, "", "レジでの精算"))
+		assert.ErrorIs(t, s.VoidLine(s.Items[0].LineID, "品切れ", "", now), ErrLineChangeNotAllowed)
+	})
+}
+
+func TestSession_AdjustLineQuantity(t *testing.T) {
+	now := time.Now()
+
+	t.Run("数量を減らして合計金額を再計算", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.AdjustLineQuantity(s.Items[0].LineID, 1, "注文間違い", "manager:a@example.com", now))
+
+		assert.Equal(t, 1, s.Items[0].Quantity)
+		require.Len(t, s.Items[0].Adjustments, 1)
+		assert.Equal(t, QuantityAdjustment{From: 2, To: 1, Reason: "注文間違い", Actor: "manager:a@example.com", At: now.UTC()}, s.Items[0].Adjustments[0])
+		assert.Equal(t, Yen(150), s.TotalAmount)
+	})
+
+	t.Run("不正な数量", func(t *testing.T) {
+		s := newTestSession(t)
+		assert.ErrorIs(t, s.AdjustLineQuantity(s.Items[0].LineID, 0, "注文間違い", "", now), ErrInvalidLineQuantity)
+		assert.ErrorIs(t, s.AdjustLineQuantity(s.Items[0].LineID, 2, "注文間違い", "", now), ErrLineQuantityUnchanged)
+		assert.ErrorIs(t, s.AdjustLineQuantity(s.Items[0].LineID, MaxItemQuantity+1, "注文間違い", "", now), ErrInvalidItemQuantity)
+	})
+
+	t.Run("追加を受け付けないステータスでは数量を増やせない", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+		require.NoError(t, s.UpdateLineStatus(s.Items[0].LineID, LineCooking, "", now))
+		assert.Equal(t, StatusPreparing, s.Status)
+		assert.ErrorIs(t, s.AdjustLineQuantity(s.Items[1].LineID, 3, "追加", "", now), ErrLineQuantityIncreaseNotAllowed)
+	})
+}
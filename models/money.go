@@ -0,0 +1,355 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency は ISO 4217 の通貨コードです。
+type Currency string
+
+const (
+	CurrencyJPY Currency = "JPY"
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+)
+
+// DefaultCurrency は通貨が指定されていない場合に使用する通貨です。
+const DefaultCurrency = CurrencyJPY
+
+var (
+	ErrCurrencyMismatch    = errors.New("通貨が一致しません")
+	ErrUnsupportedCurrency = errors.New("対応していない通貨です")
+	ErrInvalidRoundingMode = errors.New("端数処理の方法が不正です")
+	ErrNegativeAmount      = errors.New("金額に負の値は指定できません")
+	ErrAmountOverflow      = errors.New("金額が計算できる範囲を超えています")
+	ErrInvalidDenominator  = errors.New("除数は0より大きい値を指定してください")
+)
+
+// MinorUnitDigits は補助単位の桁数を返します（JPYは0、USD・EURは2）。
+func (c Currency) MinorUnitDigits() int {
+	switch c {
+	case CurrencyUSD, CurrencyEUR:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// IsValid は対応している通貨かどうかを返します。
+func (c Currency) IsValid() bool {
+	switch c {
+	case CurrencyJPY, CurrencyUSD, CurrencyEUR:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseCurrency は通貨コードを解析します。空文字の場合は DefaultCurrency を返します。
+func ParseCurrency(value string) (Currency, error) {
+	if value == "" {
+		return DefaultCurrency, nil
+	}
+	currency := Currency(strings.ToUpper(value))
+	if !currency.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedCurrency, value)
+	}
+	return currency, nil
+}
+
+// RoundingMode は割合の計算などで生じた補助単位未満の端数の処理方法です。
+type RoundingMode string
+
+const (
+	// RoundHalfUp は四捨五入します（0から遠い方へ）。
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundDown は切り捨てます（0に近い方へ）。
+	RoundDown RoundingMode = "down"
+	// RoundUp は切り上げます（0から遠い方へ）。
+	RoundUp RoundingMode = "up"
+	// RoundHalfEven は偶数丸め（銀行丸め）します。
+	RoundHalfEven RoundingMode = "half_even"
+)
+
+// IsValid は定義済みの端数処理かどうかを返します。
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case RoundHalfUp, RoundDown, RoundUp, RoundHalfEven:
+		return true
+	default:
+		return false
+	}
+}
+
+// DivRound は numerator / denominator を mode に従って整数に丸めます。
+// denominator が0以下の場合は ErrInvalidDenominator を返します。
+func DivRound(numerator, denominator int64, mode RoundingMode) (int64, error) {
+	if denominator <= 0 {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidDenominator, denominator)
+	}
+	return divRound(big.NewInt(numerator), big.NewInt(denominator), mode)
+}
+
+// divRound は DivRound の big.Int 版です。商が int64 に収まらない場合は ErrAmountOverflow を返します。
+func divRound(n, d *big.Int, mode RoundingMode) (int64, error) {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() != 0 {
+		away := int64(r.Sign()) // 0から遠ざかる方向
+		twice := new(big.Int).Abs(r)
+		twice.Lsh(twice, 1)
+		switch mode {
+		case RoundDown:
+		case RoundUp:
+			q.Add(q, big.NewInt(away))
+		case RoundHalfEven:
+			switch twice.Cmp(d) {
+			case 1:
+				q.Add(q, big.NewInt(away))
+			case 0:
+				if q.Bit(0) == 1 {
+					q.Add(q, big.NewInt(away))
+				}
+			}
+		default: // RoundHalfUp
+			if twice.Cmp(d) >= 0 {
+				q.Add(q, big.NewInt(away))
+			}
+		}
+	}
+	if !q.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return q.Int64(), nil
+}
+
+// Money は補助単位（円、セント等）の整数と通貨で表す金額です。
+// 浮動小数点の誤差を避けるため、金額の計算は全てこの型で行います。
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// NewMoney は補助単位の金額から Money を作成します。
+func NewMoney(amount int64, currency Currency) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Yen は円建ての金額を作成します。
+func Yen(amount int64) Money {
+	return NewMoney(amount, CurrencyJPY)
+}
+
+// Zero は指定通貨の0円（0セント）を返します。
+func Zero(currency Currency) Money {
+	return NewMoney(0, currency)
+}
+
+// IsZero は金額が0かどうかを返します。
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative は金額が負かどうかを返します。
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// SameCurrency は通貨が一致するかどうかを返します。
+func (m Money) SameCurrency(other Money) bool {
+	return m.currency() == other.currency()
+}
+
+func (m Money) currency() Currency {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+func (m Money) checkCurrency(other Money) error {
+	if !m.SameCurrency(other) {
+		return fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, m.currency(), other.currency())
+	}
+	return nil
+}
+
+// Add は金額を加算します。通貨が異なる場合はエラーを返します。
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(m.Amount+other.Amount, m.currency()), nil
+}
+
+// Sub は金額を減算します。通貨が異なる場合はエラーを返します。
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(m.Amount-other.Amount, m.currency()), nil
+}
+
+// Cmp は金額を比較し、m < other なら -1、等しければ 0、m > other なら 1 を返します。
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Multiply は金額を整数倍します（単価×数量など）。結果が int64 に収まらない場合は ErrAmountOverflow を返します。
+func (m Money) Multiply(n int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(n))
+	if !product.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s × %d", ErrAmountOverflow, m, n)
+	}
+	return NewMoney(product.Int64(), m.currency()), nil
+}
+
+// MulRatio は金額に numerator / denominator を掛け、mode に従って補助単位に丸めます。
+// 税額（10/100）や割引率の計算に使用します。大きな金額と税率の積でも桁あふれしないよう big.Int で計算し、
+// 結果が int64 に収まらない場合は ErrAmountOverflow を返します。
+func (m Money) MulRatio(numerator, denominator int64, mode RoundingMode) (Money, error) {
+	if denominator <= 0 {
+		return Money{}, fmt.Errorf("%w: %d", ErrInvalidDenominator, denominator)
+	}
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator))
+	amount, err := divRound(product, big.NewInt(denominator), mode)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %s × %d / %d", err, m, numerator, denominator)
+	}
+	return NewMoney(amount, m.currency()), nil
+}
+
+// Neg は符号を反転した金額を返します。
+func (m Money) Neg() Money {
+	return NewMoney(-m.Amount, m.currency())
+}
+
+// String は "1000 JPY" や "12.34 USD" の形式で金額を返します。
+func (m Money) String() string {
+	digits := m.currency().MinorUnitDigits()
+	if digits == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.currency())
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(1)
+	for i := 0; i < digits; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/unit, digits, amount%unit, m.currency())
+}
+
+// Format は3桁区切りで通貨記号付きの表示用文字列を返します（レシート印字用）。
+func (m Money) Format() string {
+	symbol := map[Currency]string{CurrencyJPY: "¥", CurrencyUSD: "$", CurrencyEUR: "€"}[m.currency()]
+
+	digits := m.currency().MinorUnitDigits()
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(1)
+	for i := 0; i < digits; i++ {
+		unit *= 10
+	}
+
+	major := strconv.FormatInt(amount/unit, 10)
+	var b strings.Builder
+	for i, r := range major {
+		if i > 0 && (len(major)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if digits > 0 {
+		fmt.Fprintf(&b, ".%0*d", digits, amount%unit)
+	}
+	return sign + symbol + b.String()
+}
+
+// SumMoney は金額の合計を返します。空の場合は指定通貨の0を返します。
+func SumMoney(currency Currency, amounts ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, amount := range amounts {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// AllocateMoney は total を weights の比率で按分します。
+// 各配分は切り捨てで計算し、残った端数は切り捨てた端数の大きい順に1単位ずつ配分するため（最大剰余方式）、配分の合計は必ず total と一致します。
+// 端数が同じ場合は先頭に近い方を優先します。weights が全て0の場合は均等に按分します。
+func AllocateMoney(total Money, weights []int64) ([]Money, error) {
+	if len(weights) == 0 {
+		return nil, nil
+	}
+	if total.IsNegative() {
+		return nil, ErrNegativeAmount
+	}
+
+	var sum int64
+	for _, w := range weights {
+		if w < 0 {
+			return nil, ErrNegativeAmount
+		}
+		sum += w
+	}
+	if sum == 0 {
+		weights = make([]int64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		sum = int64(len(weights))
+	}
+
+	currency := total.currency()
+	shares := make([]Money, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		// total * w が int64 を超えても正しく計算できるよう big.Int を使用する
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(total.Amount), big.NewInt(w)), big.NewInt(sum), new(big.Int))
+		shares[i] = NewMoney(q.Int64(), currency)
+		remainders[i] = r
+		allocated += q.Int64()
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := int64(0); i < total.Amount-allocated; i++ {
+		shares[order[i]].Amount++
+	}
+	return shares, nil
+}
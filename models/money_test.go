@@ -0,0 +1,172 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDivRound(t *testing.T) {
+	testCases := []struct {
+		name        string
+		numerator   int64
+		denominator int64
+		mode        RoundingMode
+		expected    int64
+	}{
+		{"割り切れる", 1000, 10, RoundHalfUp, 100},
+		{"四捨五入: 切り上げ", 105, 10, RoundHalfUp, 11},
+		{"四捨五入: 切り捨て", 104, 10, RoundHalfUp, 10},
+		{"四捨五入: 負の値", -105, 10, RoundHalfUp, -11},
+		{"切り捨て", 109, 10, RoundDown, 10},
+		{"切り捨て: 負の値", -109, 10, RoundDown, -10},
+		{"切り上げ", 101, 10, RoundUp, 11},
+		{"切り上げ: 負の値", -101, 10, RoundUp, -11},
+		{"偶数丸め: 偶数へ", 125, 10, RoundHalfEven, 12},
+		{"偶数丸め: 奇数から", 135, 10, RoundHalfEven, 14},
+		{"偶数丸め: 半分超", 126, 10, RoundHalfEven, 13},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DivRound(tc.numerator, tc.denominator, tc.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("除数が0以下はエラー", func(t *testing.T) {
+		_, err := DivRound(100, 0, RoundHalfUp)
+		assert.ErrorIs(t, err, ErrInvalidDenominator)
+	})
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	total, err := Yen(1000).Add(Yen(500))
+	require.NoError(t, err)
+	assert.Equal(t, Yen(1500), total)
+
+	rest, err := total.Sub(Yen(2000))
+	require.NoError(t, err)
+	assert.True(t, rest.IsNegative())
+
+	_, err = Yen(1000).Add(NewMoney(500, CurrencyUSD))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	cmp, err := Yen(100).Cmp(Yen(200))
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	product, err := Yen(1000).Multiply(3)
+	require.NoError(t, err)
+	assert.Equal(t, Yen(3000), product)
+
+	_, err = Yen(math.MaxInt64 / 2).Multiply(3)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	assert.Equal(t, Yen(-1000), Yen(1000).Neg())
+	assert.True(t, Money{}.SameCurrency(Yen(0)), "通貨未設定はJPYとして扱う")
+}
+
+func TestMoney_MulRatio(t *testing.T) {
+	// 1,234円の8%は98.72円
+	for mode, expected := range map[RoundingMode]Money{RoundDown: Yen(98), RoundHalfUp: Yen(99), RoundUp: Yen(99)} {
+		got, err := Yen(1234).MulRatio(8, 100, mode)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got, mode)
+	}
+
+	// 途中の積が int64 を超えても、結果が収まれば計算できる
+	got, err := Yen(math.MaxInt64/10).MulRatio(10, 100, RoundDown)
+	require.NoError(t, err)
+	assert.Equal(t, Yen(math.MaxInt64/100), got)
+
+	_, err = Yen(math.MaxInt64/2).MulRatio(3, 1, RoundDown)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = Yen(100).MulRatio(1, 0, RoundDown)
+	assert.ErrorIs(t, err, ErrInvalidDenominator)
+
+	// 多数の明細を浮動小数点で合計すると誤差が出るが、整数では一致する
+	total := Zero(CurrencyUSD)
+	for i := 0; i < 10; i++ {
+		var err error
+		total, err = total.Add(NewMoney(10, CurrencyUSD))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, NewMoney(100, CurrencyUSD), total)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "1000 JPY", Yen(1000).String())
+	assert.Equal(t, "12.05 USD", NewMoney(1205, CurrencyUSD).String())
+	assert.Equal(t, "-0.50 EUR", NewMoney(-50, CurrencyEUR).String())
+
+	assert.Equal(t, "¥1,234,567", Yen(1234567).Format())
+	assert.Equal(t, "-¥100", Yen(-100).Format())
+	assert.Equal(t, "$1,234.50", NewMoney(123450, CurrencyUSD).Format())
+}
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := ParseCurrency("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCurrency, currency)
+
+	currency, err = ParseCurrency("usd")
+	require.NoError(t, err)
+	assert.Equal(t, CurrencyUSD, currency)
+
+	_, err = ParseCurrency("XYZ")
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+}
+
+func TestSumMoney(t *testing.T) {
+	total, err := SumMoney(CurrencyJPY, Yen(100), Yen(200))
+	require.NoError(t, err)
+	assert.Equal(t, Yen(300), total)
+
+	empty, err := SumMoney(CurrencyUSD)
+	require.NoError(t, err)
+	assert.Equal(t, Zero(CurrencyUSD), empty)
+}
+
+func TestAllocateMoney(t *testing.T) {
+	testCases := []struct {
+		name     string
+		total    int64
+		weights  []int64
+		expected []int64
+	}{
+		{"割り切れる", 300, []int64{1, 1, 1}, []int64{100, 100, 100}},
+		{"端数は剰余の大きい順", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"比率で按分", 100, []int64{500, 300, 200}, []int64{50, 30, 20}},
+		{"剰余の大きい行に端数を配分", 10, []int64{333, 667}, []int64{3, 7}},
+		{"重みが全て0なら均等", 5, []int64{0, 0}, []int64{3, 2}},
+		{"重み0の行には配分しない", 99, []int64{0, 1}, []int64{0, 99}},
+		{"0円", 0, []int64{1, 2}, []int64{0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shares, err := AllocateMoney(Yen(tc.total), tc.weights)
+			require.NoError(t, err)
+
+			got := make([]int64, len(shares))
+			sum := int64(0)
+			for i, s := range shares {
+				got[i] = s.Amount
+				sum += s.Amount
+				assert.Equal(t, CurrencyJPY, s.Currency)
+			}
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.total, sum, "配分の合計は元の金額と一致する")
+		})
+	}
+
+	t.Run("負の値はエラー", func(t *testing.T) {
+		_, err := AllocateMoney(Yen(-1), []int64{1})
+		assert.ErrorIs(t, err, ErrNegativeAmount)
+		_, err = AllocateMoney(Yen(1), []int64{-1, 2})
+		assert.ErrorIs(t, err, ErrNegativeAmount)
+	})
+}
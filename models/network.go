@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// NetworkRestriction は店舗ネットワーク外からの注文をどう扱うかを表します。
+type NetworkRestriction string
+
+const (
+	// NetworkRestrictionOff は送信元ネットワークを確認しません。
+	NetworkRestrictionOff NetworkRestriction = "off"
+	// NetworkRestrictionEnforce は許可されたネットワーク以外からの注文を拒否します。
+	NetworkRestrictionEnforce NetworkRestriction = "enforce"
+	// NetworkRestrictionSoft は注文を受け付けた上で、スタッフの確認対象としてフラグを立てます。
+	NetworkRestrictionSoft NetworkRestriction = "soft"
+)
+
+var (
+	ErrInvalidNetworkRestriction = errors.New("ネットワーク制限のモードが不正です")
+	ErrInvalidCIDR               = errors.New("CIDR表記が不正です")
+	ErrAllowedCIDRsRequired      = errors.New("ネットワーク制限を有効にするには許可するCIDRが必要です")
+)
+
+// ReviewReasonOutsideNetwork は店舗ネットワーク外から注文された場合の確認理由です。
+const ReviewReasonOutsideNetwork = "outside_store_network"
+
+// IsValid は定義済みのモードかどうかを返します。
+func (m NetworkRestriction) IsValid() bool {
+	switch m {
+	case NetworkRestrictionOff, NetworkRestrictionEnforce, NetworkRestrictionSoft:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsEnabled は送信元ネットワークの確認が必要かどうかを返します。
+// 未設定の場合は確認しません。
+func (m NetworkRestriction) IsEnabled() bool {
+	return m == NetworkRestrictionEnforce || m == NetworkRestrictionSoft
+}
+
+// ParseCIDRs はCIDR表記の一覧を解析します。
+// IPアドレス単体が指定された場合は、そのアドレスのみを許可するプレフィックスとして扱います。
+func ParseCIDRs(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidCIDR, value)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCIDR, value)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ValidateNetworkRestriction は店舗のネットワーク制限設定を検証します。
+func (s *Store) ValidateNetworkRestriction() error {
+	if s.NetworkRestriction == "" {
+		return nil
+	}
+	if !s.NetworkRestriction.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidNetworkRestriction, s.NetworkRestriction)
+	}
+
+	prefixes, err := ParseCIDRs(s.AllowedCIDRs)
+	if err != nil {
+		return err
+	}
+	if s.NetworkRestriction.IsEnabled() && len(prefixes) == 0 {
+		return ErrAllowedCIDRsRequired
+	}
+	return nil
+}
+
+// IsAllowedIP は送信元IPが店舗の許可ネットワークに含まれるかどうかを判定します。
+// ネットワーク制限が無効な店舗では常に true を返します。
+func (s *Store) IsAllowedIP(ip string) (bool, error) {
+	if !s.NetworkRestriction.IsEnabled() {
+		return true, nil
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false, nil
+	}
+	addr = addr.Unmap()
+
+	prefixes, err := ParseCIDRs(s.AllowedCIDRs)
+	if err != nil {
+		return false, err
+	}
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCIDRs(t *testing.T) {
+	prefixes, err := ParseCIDRs([]string{"192.168.1.0/24", " 10.0.0.5 ", "", "2001:db8::/32"})
+	require.NoError(t, err)
+	require.Len(t, prefixes, 3)
+	assert.Equal(t, "192.168.1.0/24", prefixes[0].String())
+	assert.Equal(t, "10.0.0.5/32", prefixes[1].String(), "単体のIPは/32として扱う")
+	assert.Equal(t, "2001:db8::/32", prefixes[2].String())
+
+	_, err = ParseCIDRs([]string{"192.168.1.0/33"})
+	assert.ErrorIs(t, err, ErrInvalidCIDR)
+
+	_, err = ParseCIDRs([]string{"not-an-ip"})
+	assert.ErrorIs(t, err, ErrInvalidCIDR)
+}
+
+func TestStore_ValidateNetworkRestriction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mode     NetworkRestriction
+		cidrs    []string
+		expected error
+	}{
+		{name: "未設定", mode: "", expected: nil},
+		{name: "無効", mode: NetworkRestrictionOff, expected: nil},
+		{name: "拒否モード", mode: NetworkRestrictionEnforce, cidrs: []string{"192.168.1.0/24"}, expected: nil},
+		{name: "ソフトモード", mode: NetworkRestrictionSoft, cidrs: []string{"192.168.1.0/24"}, expected: nil},
+		{name: "不明なモード", mode: "strict", cidrs: []string{"192.168.1.0/24"}, expected: ErrInvalidNetworkRestriction},
+		{name: "CIDRなしで有効化", mode: NetworkRestrictionEnforce, expected: ErrAllowedCIDRsRequired},
+		{name: "不正なCIDR", mode: NetworkRestrictionSoft, cidrs: []string{"abc"}, expected: ErrInvalidCIDR},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &Store{NetworkRestriction: tc.mode, AllowedCIDRs: tc.cidrs}
+			err := store.ValidateNetworkRestriction()
+			if tc.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestStore_IsAllowedIP(t *testing.T) {
+	store := &Store{
+		NetworkRestriction: NetworkRestrictionEnforce,
+		AllowedCIDRs:       []string{"192.168.1.0/24", "203.0.113.10"},
+	}
+
+	testCases := []struct {
+		ip       string
+		expected bool
+	}{
+		{"192.168.1.15", true},
+		{"::ffff:192.168.1.15", true},
+		{"203.0.113.10", true},
+		{"203.0.113.11", false},
+		{"10.0.0.1", false},
+		{"invalid", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ip, func(t *testing.T) {
+			allowed, err := store.IsAllowedIP(tc.ip)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, allowed)
+		})
+	}
+
+	t.Run("制限なしの店舗は常に許可", func(t *testing.T) {
+		allowed, err := (&Store{}).IsAllowedIP("10.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+}
+
+func TestSession_FlagForReview(t *testing.T) {
+	session := newTestSession(t)
+	session.FlagForReview(ReviewReasonOutsideNetwork)
+
+	assert.True(t, session.NeedsReview)
+	assert.Equal(t, ReviewReasonOutsideNetwork, session.ReviewReason)
+	assert.Equal(t, StatusCreated, session.Status, "ステータスは変わらない")
+}
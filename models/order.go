@@ -1,38 +1,120 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 // OrderPrefix はOrderエンティティのIDを生成する際のプレフィックスです。
 const OrderPrefix = "order_" // 実際のプレフィックス文字列に置き換えてください
 
+// OrderLinePrefix は注文内の明細行を識別するIDのプレフィックスです。
+const OrderLinePrefix = "line_"
+
+// MaxItemInstructionsLength は商品への特別な指示（「ネギ抜き」など）の最大文字数です。
+const MaxItemInstructionsLength = 200
+
+// MaxItemQuantity は1つの明細で注文できる商品の最大数量です。
+const MaxItemQuantity = 999
+
+var (
+	ErrItemInstructionsTooLong = errors.New("商品への特別な指示は200文字以内で指定してください")
+	ErrInvalidItemQuantity     = errors.New("商品の数量は1〜999で指定してください")
+)
+
 // --- OrderItem プレースホルダー ---
 
 // Order は注文内の個々の商品を表します。
 // この構造体は外部で定義されていることを想定しています。
+// TaxCategory は商品の消費税区分で、未設定の場合は標準税率として扱います。
+// LineID は注文内の明細行を識別するIDで、割引の按分や返金の対象行の指定に使用します。
+// Category は商品のカテゴリ（"drink" など）で、カテゴリ単位の割引の対象判定に使用します。
+// Status は明細の提供状況で、取り消した明細（Voided）の金額は合計金額に含めません。
+// Instructions はお客様から厨房への特別な指示（「ネギ抜き」など）です。
 type Order struct {
-	OrderID   string
-	ProductID string
-	Quantity  int
-	Price     float64
+	OrderID      string
+	LineID       string
+	ProductID    string
+	Category     string
+	Quantity     int
+	Price        Money
+	TaxCategory  TaxCategory
+	Instructions string
+
+	Status         LineStatus
+	Adjustments    []QuantityAdjustment
+	VoidReason     string
+	VoidReasonCode string
+	VoidedBy       string
+	VoidedAt       time.Time
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
 // NewOrder は新しい注文アイテムを作成します。
-func NewOrder(productID string, quantity int, price float64) *Order {
+// price は単価で、補助単位の整数で指定します。
+func NewOrder(productID string, quantity int, price Money) *Order {
 	uid := GenerateUniqueID(OrderPrefix)
 	now := time.Now().UTC()
 	return &Order{
 		OrderID:   uid,
+		LineID:    GenerateUniqueID(OrderLinePrefix),
 		ProductID: productID,
 		Quantity:  quantity,
 		Price:     price,
+		Status:    LineQueued,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
 
-// Subtotal はこの注文アイテムの小計を計算します。
-func (oi *Order) Subtotal() float64 {
-	return oi.Price * float64(oi.Quantity)
+// WithTaxCategory は消費税区分を設定した注文アイテムを返します。
+func (oi *Order) WithTaxCategory(category TaxCategory) *Order {
+	oi.TaxCategory = category
+	return oi
+}
+
+// WithCategory は商品のカテゴリを設定した注文アイテムを返します。
+func (oi *Order) WithCategory(category string) *Order {
+	oi.Category = category
+	return oi
+}
+
+// WithInstructions は特別な指示を設定した注文アイテムを返します。
+func (oi *Order) WithInstructions(instructions string) *Order {
+	oi.Instructions = strings.TrimSpace(instructions)
+	return oi
+}
+
+// validateQuantity は商品の数量が1以上 MaxItemQuantity 以下であることを検証します。
+func validateQuantity(quantity int) error {
+	if quantity < 1 || quantity > MaxItemQuantity {
+		return fmt.Errorf("%w: %d", ErrInvalidItemQuantity, quantity)
+	}
+	return nil
+}
+
+// validateInstructions は特別な指示の文字数を検証します。
+func validateInstructions(instructions string) error {
+	if utf8.RuneCountInString(instructions) > MaxItemInstructionsLength {
+		return ErrItemInstructionsTooLong
+	}
+	return nil
+}
+
+// Subtotal はこの注文アイテムの小計（単価×数量）を計算します。取り消した明細は0です。
+func (oi *Order) Subtotal() (Money, error) {
+	if oi.IsVoided() {
+		return Zero(oi.Price.currency()), nil
+	}
+	return oi.Gross()
+}
+
+// Gross は取り消しの有無によらない、この注文アイテムの単価×数量です。取り消した明細の集計に使用します。
+func (oi *Order) Gross() (Money, error) {
+	return oi.Price.Multiply(int64(oi.Quantity))
 }
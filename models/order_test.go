@@ -6,12 +6,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewOrder(t *testing.T) {
 	productID := "prod_123"
 	quantity := 2
-	price := 1500.50
+	price := Yen(1500)
 
 	order := NewOrder(productID, quantity, price)
 
@@ -34,44 +35,44 @@ func TestOrder_Subtotal(t *testing.T) {
 	testCases := []struct {
 		name     string
 		quantity int
-		price    float64
-		expected float64
+		price    Money
+		expected Money
 	}{
 		{
 			name:     "通常のケース",
 			quantity: 2,
-			price:    10.5,
-			expected: 21.0,
+			price:    Yen(1050),
+			expected: Yen(2100),
 		},
 		{
 			name:     "数量が0のケース",
 			quantity: 0,
-			price:    100.0,
-			expected: 0.0,
+			price:    Yen(100),
+			expected: Yen(0),
 		},
 		{
 			name:     "価格が0のケース",
 			quantity: 5,
-			price:    0.0,
-			expected: 0.0,
+			price:    Yen(0),
+			expected: Yen(0),
 		},
 		{
 			name:     "数量と価格が両方0のケース",
 			quantity: 0,
-			price:    0.0,
-			expected: 0.0,
+			price:    Yen(0),
+			expected: Yen(0),
 		},
 		{
-			name:     "数量が1のケース",
-			quantity: 1,
-			price:    99.99,
-			expected: 99.99,
+			name:     "補助単位のある通貨（セント）",
+			quantity: 3,
+			price:    NewMoney(1999, CurrencyUSD),
+			expected: NewMoney(5997, CurrencyUSD),
 		},
 		{
 			name:     "大きい数量と価格",
 			quantity: 1000,
-			price:    12345.67,
-			expected: 12345670.0,
+			price:    Yen(1234567),
+			expected: Yen(1234567000),
 		},
 	}
 
@@ -83,16 +84,17 @@ func TestOrder_Subtotal(t *testing.T) {
 				Price:     tc.price,
 			}
 
-			subtotal := order.Subtotal()
-			assert.InDelta(t, tc.expected, subtotal, 0.001, "小計が正しく計算されていません")
+			subtotal, err := order.Subtotal()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, subtotal, "小計が正しく計算されていません")
 		})
 	}
 }
 
 func TestOrder_IDGeneration(t *testing.T) {
 	// 連続して生成してもIDがユニークであることを確認
-	order1 := NewOrder("p1", 1, 100)
-	order2 := NewOrder("p2", 2, 200)
+	order1 := NewOrder("p1", 1, Yen(100))
+	order2 := NewOrder("p2", 2, Yen(200))
 
 	assert.NotNil(t, order1)
 	assert.NotNil(t, order2)
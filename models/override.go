@@ -0,0 +1,201 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// --- 責任者による注文のステータスの強制変更（オーバーライド） ---
+
+// SupervisorActorPrefix は責任者のPINで承認した場合の承認者の接頭辞です（"supervisor:<名前>"）。
+const SupervisorActorPrefix = "supervisor:"
+
+var (
+	ErrOverrideReasonRequired  = errors.New("ステータスの強制変更の理由を指定してください")
+	ErrOverrideNotApproved     = errors.New("ステータスの強制変更には責任者の承認が必要です")
+	ErrOverrideStatusUnchanged = errors.New("現在と同じステータスには強制変更できません")
+	ErrInvalidOverrideStatus   = errors.New("注文のワークフローにないステータスには強制変更できません")
+	ErrInvalidSupervisor       = errors.New("責任者の設定が不正です")
+	ErrInvalidSupervisorPIN    = errors.New("責任者のPINが正しくありません")
+	ErrInvalidOverrideManager  = errors.New("ステータスの強制変更を許可するマネージャーの指定が不正です")
+)
+
+// SupervisorPINLockedError は責任者のPINの入力の失敗が続いたため、店舗でのPINの入力がロックされていることを表します。
+type SupervisorPINLockedError struct {
+	Until time.Time
+}
+
+func (e *SupervisorPINLockedError) Error() string {
+	return fmt.Sprintf("責任者のPINの入力の失敗が続いたため %s までPINを入力できません", e.Until.Format(time.RFC3339))
+}
+
+// RetryAfter は再試行が可能になるまでの残り時間を返します。
+func (e *SupervisorPINLockedError) RetryAfter(now time.Time) time.Duration {
+	if d := e.Until.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// SupervisorPINAttemptKey は店舗の責任者のPINの入力の失敗履歴のキーです。
+func SupervisorPINAttemptKey(storeID string) string {
+	return "supervisor_pin:" + storeID
+}
+
+// supervisorPINPattern は責任者のPINの書式（6〜12桁の数字）です。
+var supervisorPINPattern = regexp.MustCompile(`^[0-9]{6,12}$`)
+
+// Supervisor はスタッフの端末でPINを入力して、ステータスの強制変更を承認できる店舗の責任者です。
+// PINはハッシュ化して保存します。
+type Supervisor struct {
+	Name    string
+	PINHash string
+}
+
+// SupervisorPIN は責任者の登録の入力です。
+type SupervisorPIN struct {
+	Name string `json:"name"`
+	PIN  string `json:"pin"`
+}
+
+// NewSupervisors は責任者の名前とPINを検証し、PINをハッシュ化した責任者の一覧を返します。
+// 承認者を特定できるよう、名前とPINはいずれも店舗内で重複できません。
+func NewSupervisors(entries []SupervisorPIN) ([]Supervisor, error) {
+	supervisors := make([]Supervisor, len(entries))
+	names := make(map[string]bool, len(entries))
+	pins := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("%w: 名前を指定してください (supervisors[%d])", ErrInvalidSupervisor, i)
+		case !supervisorPINPattern.MatchString(entry.PIN):
+			return nil, fmt.Errorf("%w: PINは6〜12桁の数字です (supervisors[%d])", ErrInvalidSupervisor, i)
+		case names[name]:
+			return nil, fmt.Errorf("%w: 名前 %q が重複しています (supervisors[%d])", ErrInvalidSupervisor, name, i)
+		case pins[entry.PIN]:
+			return nil, fmt.Errorf("%w: PINが重複しています (supervisors[%d])", ErrInvalidSupervisor, i)
+		}
+		names[name] = true
+		pins[entry.PIN] = true
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(entry.PIN), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash supervisor pin: %w", err)
+		}
+		supervisors[i] = Supervisor{Name: name, PINHash: string(hash)}
+	}
+	return supervisors, nil
+}
+
+// VerifySupervisorPIN はPINに一致する店舗の責任者を返します。
+func (s *Store) VerifySupervisorPIN(pin string) (Supervisor, error) {
+	if supervisorPINPattern.MatchString(pin) {
+		for _, supervisor := range s.Supervisors {
+			if bcrypt.CompareHashAndPassword([]byte(supervisor.PINHash), []byte(pin)) == nil {
+				return supervisor, nil
+			}
+		}
+	}
+	return Supervisor{}, ErrInvalidSupervisorPIN
+}
+
+// NewOverrideManagers はステータスの強制変更を許可するマネージャーのメールアドレスを検証し、重複を取り除いた一覧を返します。
+// 空の一覧を指定した場合は全ての許可を取り消します。
+func NewOverrideManagers(emails []string) ([]string, error) {
+	managers := make([]string, 0, len(emails))
+	for i, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" || !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("%w: メールアドレスを指定してください (override_managers[%d])", ErrInvalidOverrideManager, i)
+		}
+		if !slices.Contains(managers, email) {
+			managers = append(managers, email)
+		}
+	}
+	return managers, nil
+}
+
+// AllowsManagerOverride はマネージャー（email）に店舗での強制変更が許可されているかどうかを返します。
+func (s *Store) AllowsManagerOverride(email string) bool {
+	return email != "" && slices.Contains(s.OverrideManagers, email)
+}
+
+// Approver は遷移の履歴に記録する承認者です。
+func (s Supervisor) Approver() string {
+	return SupervisorActorPrefix + s.Name
+}
+
+// StatusOverride はステータスの強制変更の操作者・承認者・理由です。
+// 強制変更の権限を持つ操作者が自ら行う場合、ApprovedBy は Actor と同じです。
+// キャンセル・辞退・保留への強制変更には、通常の遷移と同じく店舗の理由コード（ReasonCode）が必要です。
+type StatusOverride struct {
+	Actor      string
+	ApprovedBy string
+	Reason     string
+	ReasonCode ReasonCode
+}
+
+// OverrideStatus は責任者の承認のもとで、ワークフローの遷移ルールによらず注文のステータスを変更します。
+// 最終状態の注文も変更できます。変更は例外として承認者・理由（理由コード）とあわせて遷移の履歴に記録します。
+func (s *Session) OverrideStatus(newStatus Status, override StatusOverride) error {
+	reason := strings.TrimSpace(override.Reason)
+	kind, needsCode := ReasonKindFor(newStatus)
+	switch {
+	case override.ApprovedBy == "":
+		return ErrOverrideNotApproved
+	case reason == "":
+		return ErrOverrideReasonRequired
+	case !s.Workflow().HasState(newStatus):
+		return fmt.Errorf("%w: %q", ErrInvalidOverrideStatus, newStatus)
+	case newStatus == s.Status:
+		return fmt.Errorf("%w: %q", ErrOverrideStatusUnchanged, newStatus)
+	case needsCode && (override.ReasonCode.Code == "" || override.ReasonCode.Kind != kind):
+		return fmt.Errorf("%w: %s", ErrReasonCodeRequired, kind)
+	}
+
+	s.exceptionUpdateStatus(newStatus, override.Actor, reason)
+	last := &s.StatusHistory[len(s.StatusHistory)-1]
+	last.ApprovedBy = override.ApprovedBy
+	if needsCode {
+		last.ReasonCode = override.ReasonCode.Code
+	}
+	return nil
+}
+
+// IsOverride は責任者の承認によるステータスの強制変更の記録かどうかを返します。
+func (c StatusChange) IsOverride() bool {
+	return c.Exception && c.ApprovedBy != ""
+}
+
+// StatusOverrideRecord は監査のための、ステータスの強制変更の記録です。
+type StatusOverrideRecord struct {
+	OrderID string       `json:"order_id"`
+	SeatID  string       `json:"seat_id"`
+	Change  StatusChange `json:"change"`
+}
+
+// StatusOverrides は店舗の注文の遷移の履歴から、期間中（終了日時は含まない）のステータスの強制変更を新しい順に返します。
+func StatusOverrides(storeID string, sessions []*Session, start, end time.Time) []StatusOverrideRecord {
+	records := []StatusOverrideRecord{}
+	for _, s := range sessions {
+		if s.StoreID != storeID {
+			continue
+		}
+		for _, change := range s.StatusHistory {
+			if change.IsOverride() && !change.At.Before(start) && change.At.Before(end) {
+				records = append(records, StatusOverrideRecord{OrderID: s.ID, SeatID: s.SeatID, Change: change})
+			}
+		}
+	}
+	slices.SortFunc(records, func(a, b StatusOverrideRecord) int {
+		return b.Change.At.Compare(a.Change.At)
+	})
+	return records
+}
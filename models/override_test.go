@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSupervisors(t *testing.T) {
+	supervisors, err := NewSupervisors([]SupervisorPIN{{Name: " 佐藤 ", PIN: "246810"}})
+	require.NoError(t, err)
+	require.Len(t, supervisors, 1)
+	assert.Equal(t, "佐藤", supervisors[0].Name)
+	assert.NotEqual(t, "246810", supervisors[0].PINHash, "PINはハッシュ化して保存")
+
+	invalid := [][]SupervisorPIN{
+		{{Name: "", PIN: "246810"}},
+		{{Name: "佐藤", PIN: "1234"}},
+		{{Name: "佐藤", PIN: "24681a"}},
+		{{Name: "佐藤", PIN: "246810"}, {Name: "佐藤", PIN: "135790"}},
+		{{Name: "佐藤", PIN: "246810"}, {Name: "鈴木", PIN: "246810"}},
+	}
+	for _, entries := range invalid {
+		_, err := NewSupervisors(entries)
+		assert.ErrorIs(t, err, ErrInvalidSupervisor)
+	}
+}
+
+func TestStore_VerifySupervisorPIN(t *testing.T) {
+	supervisors, err := NewSupervisors([]SupervisorPIN{{Name: "佐藤", PIN: "246810"}, {Name: "鈴木", PIN: "135790"}})
+	require.NoError(t, err)
+	store := &Store{Supervisors: supervisors}
+
+	supervisor, err := store.VerifySupervisorPIN("135790")
+	require.NoError(t, err)
+	assert.Equal(t, "supervisor:鈴木", supervisor.Approver())
+
+	_, err = store.VerifySupervisorPIN("000000")
+	assert.ErrorIs(t, err, ErrInvalidSupervisorPIN)
+	_, err = (&Store{}).VerifySupervisorPIN("246810")
+	assert.ErrorIs(t, err, ErrInvalidSupervisorPIN, "責任者が未設定")
+}
+
+func TestSession_OverrideStatus(t *testing.T) {
+	approved := StatusOverride{Actor: "apikey:key_1", ApprovedBy: "supervisor:佐藤", Reason: "会計済みの注文を誤ってキャンセルしたため"}
+
+	t.Run("最終状態の注文も強制変更できる", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatusBy(StatusCancelled, "", "お客様の都合"))
+
+		require.NoError(t, s.OverrideStatus(StatusServed, approved))
+		assert.Equal(t, StatusServed, s.Status)
+		last := s.StatusHistory[len(s.StatusHistory)-1]
+		assert.True(t, last.IsOverride())
+		assert.Equal(t, StatusCancelled, last.From)
+		assert.Equal(t, "apikey:key_1", last.Actor)
+		assert.Equal(t, "supervisor:佐藤", last.ApprovedBy)
+	})
+
+	t.Run("承認・理由のない強制変更は記録しない", func(t *testing.T) {
+		s := newTestSession(t)
+		recorded := len(s.StatusHistory)
+
+		assert.ErrorIs(t, s.OverrideStatus(StatusServed, StatusOverride{Actor: "apikey:key_1", Reason: "提供済み"}), ErrOverrideNotApproved)
+		assert.ErrorIs(t, s.OverrideStatus(StatusServed, StatusOverride{ApprovedBy: "manager:a@example.com", Reason: " "}), ErrOverrideReasonRequired)
+		assert.ErrorIs(t, s.OverrideStatus(StatusCreated, approved), ErrOverrideStatusUnchanged)
+		assert.ErrorIs(t, s.OverrideStatus("unknown", approved), ErrInvalidOverrideStatus)
+		assert.Len(t, s.StatusHistory, recorded)
+		assert.Equal(t, StatusCreated, s.Status)
+	})
+
+	t.Run("キャンセルへの強制変更には理由コードが必要", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatusBy(StatusConfirmed, "", ""))
+		recorded := len(s.StatusHistory)
+
+		assert.ErrorIs(t, s.OverrideStatus(StatusCancelled, approved), ErrReasonCodeRequired)
+		hold := ReasonCode{Kind: ReasonHold, Code: "kitchen_busy", Label: "厨房の混雑", Active: true}
+		assert.ErrorIs(t, s.OverrideStatus(StatusCancelled, StatusOverride{ApprovedBy: approved.ApprovedBy, Reason: approved.Reason, ReasonCode: hold}), ErrReasonCodeRequired, "種類の異なる理由コード")
+		assert.Len(t, s.StatusHistory, recorded)
+
+		withCode := approved
+		withCode.ReasonCode = ReasonCode{Kind: ReasonCancel, Code: "duplicate_order", Label: "重複した注文", Active: true}
+		require.NoError(t, s.OverrideStatus(StatusCancelled, withCode))
+		last := s.StatusHistory[len(s.StatusHistory)-1]
+		assert.Equal(t, "duplicate_order", last.ReasonCode)
+		assert.Equal(t, approved.Reason, last.Reason)
+	})
+}
+
+func TestStatusOverrides(t *testing.T) {
+	start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	first := newTestSession(t)
+	require.NoError(t, first.OverrideStatus(StatusServed, StatusOverride{ApprovedBy: "manager:a@example.com", Reason: "提供済み"}))
+	first.StatusHistory[len(first.StatusHistory)-1].At = start.Add(time.Hour)
+
+	second := newTestSession(t)
+	duplicate := ReasonCode{Kind: ReasonCancel, Code: "duplicate_order", Label: "重複した注文", Active: true}
+	require.NoError(t, second.OverrideStatus(StatusCancelled, StatusOverride{ApprovedBy: "supervisor:佐藤", Reason: "重複した注文", ReasonCode: duplicate}))
+	second.StatusHistory[len(second.StatusHistory)-1].At = start.Add(2 * time.Hour)
+
+	settled := newTestSession(t)
+	completed, err := settled.CompleteBySettlement("manager:a@example.com")
+	require.NoError(t, err)
+	require.True(t, completed, "レジでの精算の例外は強制変更に含めない")
+	require.True(t, settled.StatusHistory[len(settled.StatusHistory)-1].Exception)
+	settled.StatusHistory[len(settled.StatusHistory)-1].At = start.Add(3 * time.Hour)
+
+	outside := newTestSession(t)
+	require.NoError(t, outside.OverrideStatus(StatusServed, StatusOverride{ApprovedBy: "manager:a@example.com", Reason: "提供済み"}))
+	outside.StatusHistory[len(outside.StatusHistory)-1].At = end
+
+	records := StatusOverrides("store_123", []*Session{first, second, settled, outside}, start, end)
+	require.Len(t, records, 2)
+	assert.Equal(t, second.ID, records[0].OrderID, "新しい順")
+	assert.Equal(t, "supervisor:佐藤", records[0].Change.ApprovedBy)
+	assert.Equal(t, first.ID, records[1].OrderID)
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PaymentPrefix は決済（決済代行会社の PaymentIntent に対応する支払い）のIDのプレフィックスです。
+const PaymentPrefix = "pay_"
+
+// PaymentIntentStatus は決済代行会社での支払いの状態です。注文のステータスとは別に管理します。
+type PaymentIntentStatus string
+
+const (
+	// PaymentIntentRequiresPayment はお客様の支払い手続き（カード情報の入力など）を待っている状態です。
+	PaymentIntentRequiresPayment PaymentIntentStatus = "requires_payment"
+	// PaymentIntentRequiresCapture はオーソリ（与信）済みで、売上の確定（キャプチャ）を待っている状態です。
+	PaymentIntentRequiresCapture PaymentIntentStatus = "requires_capture"
+	// PaymentIntentSucceeded は支払いが完了した状態です。
+	PaymentIntentSucceeded PaymentIntentStatus = "succeeded"
+	// PaymentIntentFailed は支払いに失敗した状態です。同じ決済で再試行して成功する場合があります。
+	PaymentIntentFailed PaymentIntentStatus = "failed"
+	// PaymentIntentCanceled は支払いが取り消された状態です。
+	PaymentIntentCanceled PaymentIntentStatus = "canceled"
+)
+
+// PaymentEventType は決済代行会社から Webhook で通知されるイベントの種類です。
+type PaymentEventType string
+
+const (
+	PaymentEventAuthorized PaymentEventType = "payment.authorized"
+	PaymentEventSucceeded  PaymentEventType = "payment.succeeded"
+	PaymentEventFailed     PaymentEventType = "payment.failed"
+	PaymentEventCanceled   PaymentEventType = "payment.canceled"
+	// PaymentEventIgnored は処理の対象外のイベントです。受信済みとして記録のみ行います。
+	PaymentEventIgnored PaymentEventType = "ignored"
+)
+
+var (
+	ErrPaymentNotFound              = errors.New("決済が見つかりません")
+	ErrPaymentNotAllowed            = errors.New("現在のステータスでは支払いを開始できません")
+	ErrPaymentAmountRequired        = errors.New("支払い金額が0のため決済できません")
+	ErrPaymentNotCapturable         = errors.New("オーソリ済みでない決済は売上を確定できません")
+	ErrPaymentInProgress            = errors.New("オンライン決済の支払い手続き中のため精算できません")
+	ErrPaymentProviderNotConfigured = errors.New("決済代行会社が設定されていません")
+	ErrPaymentProviderFailed        = errors.New("決済代行会社での処理に失敗しました")
+	ErrInvalidWebhookSignature      = errors.New("Webhook の署名が不正です")
+	ErrWebhookTimestampExpired      = errors.New("Webhook の署名の有効期限が切れています")
+	ErrInvalidWebhookPayload        = errors.New("Webhook の内容が不正です")
+)
+
+// Payment は注文の支払いを決済代行会社で処理した記録です。
+// IntentID は決済代行会社での支払いのID（Stripe の PaymentIntent ID など）で、Webhook との照合に使用します。
+type Payment struct {
+	ID            string
+	StoreID       string
+	SessionID     string
+	Provider      string
+	IntentID      string
+	Amount        Money
+	Status        PaymentIntentStatus
+	FailureReason string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PaymentEvent は受信した Webhook のイベントです。
+// ID は決済代行会社のイベントIDで、同じイベントが重複して配信された場合に2回目以降を無視するために記録します。
+type PaymentEvent struct {
+	ID            string
+	Provider      string
+	Type          PaymentEventType
+	IntentID      string
+	Amount        Money
+	FailureReason string
+	ReceivedAt    time.Time
+}
+
+// IntentStatus はイベントの種類に対応する決済の状態を返します。
+func (e *PaymentEvent) IntentStatus() (PaymentIntentStatus, bool) {
+	switch e.Type {
+	case PaymentEventAuthorized:
+		return PaymentIntentRequiresCapture, true
+	case PaymentEventSucceeded:
+		return PaymentIntentSucceeded, true
+	case PaymentEventFailed:
+		return PaymentIntentFailed, true
+	case PaymentEventCanceled:
+		return PaymentIntentCanceled, true
+	default:
+		return "", false
+	}
+}
+
+// NewPayment は注文の支払いを開始します。
+// 注文の支払い状態は `Unpaid`、`Failed` または `Pending` である必要があり、`Pending` に更新されます。
+func NewPayment(session *Session, provider, intentID string, now time.Time) (*Payment, error) {
+	if err := session.StartPayment(); err != nil {
+		return nil, err
+	}
+	now = now.UTC()
+	return &Payment{
+		ID:        GenerateUniqueID(PaymentPrefix),
+		StoreID:   session.StoreID,
+		SessionID: session.ID,
+		Provider:  provider,
+		IntentID:  intentID,
+		Amount:    session.TotalAmount,
+		Status:    PaymentIntentRequiresPayment,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsActive は支払いの手続き中（完了・取り消し前）かどうかを返します。
+func (p *Payment) IsActive() bool {
+	return p.Status == PaymentIntentRequiresPayment || p.Status == PaymentIntentRequiresCapture
+}
+
+// ApplyStatus は決済代行会社から通知された状態を反映し、状態が変わったかどうかを返します。
+// Webhook は順不同・重複して配信されるため、完了・取り消し済みの決済の状態は変更しません。
+// 失敗した決済は、お客様が別のカードで再試行して成功する場合があるため、成功のみ受け付けます。
+func (p *Payment) ApplyStatus(status PaymentIntentStatus, failureReason string, now time.Time) bool {
+	if status == p.Status {
+		return false
+	}
+	switch p.Status {
+	case PaymentIntentSucceeded, PaymentIntentCanceled:
+		return false
+	case PaymentIntentFailed:
+		if status != PaymentIntentSucceeded && status != PaymentIntentCanceled {
+			return false
+		}
+	case PaymentIntentRequiresCapture:
+		if status == PaymentIntentRequiresPayment {
+			return false
+		}
+	}
+
+	p.Status = status
+	if status == PaymentIntentFailed {
+		p.FailureReason = strings.TrimSpace(failureReason)
+	}
+	p.UpdatedAt = now.UTC()
+	return true
+}
+
+// --- Session の支払い ---
+
+// StartPayment は注文の支払い状態を支払い手続き中にします。
+// 提供の進行によらず、未払い・支払い失敗の注文の支払いを開始できます（提供済みで未払いの注文の後払いなど）。
+// キャンセルなどで最終状態になった注文は支払えません。
+func (s *Session) StartPayment() error {
+	if s.TotalAmount.Amount <= 0 {
+		return ErrPaymentAmountRequired
+	}
+	if s.Workflow().IsFinal(s.Status) {
+		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrPaymentNotAllowed, s.Status)
+	}
+	switch s.paymentStatus() {
+	case PaymentStatusPending:
+		return nil
+	case PaymentStatusUnpaid, PaymentStatusFailed:
+		return s.UpdatePaymentStatus(PaymentStatusPending, "", "")
+	default:
+		return fmt.Errorf("%w: 支払い状態は '%s'", ErrPaymentNotAllowed, s.paymentStatus())
+	}
+}
+
+// ApplyPaymentStatus は決済の状態を注文の支払い状態に反映し、支払い状態が変わったかどうかを返します。
+// 支払い完了で `Paid`、失敗で `Failed`、取り消しで `Unpaid` に更新します。提供の進行（Status）は変更しません。
+// 支払い手続き中以外の注文（重複した通知など）は変更しません。
+func (s *Session) ApplyPaymentStatus(status PaymentIntentStatus) (bool, error) {
+	const reason = "オンライン決済"
+	current := s.paymentStatus()
+	switch status {
+	case PaymentIntentSucceeded:
+		// 失敗の通知の後に再試行で支払いが完了した場合は、回収済みの支払いを優先する
+		if current == PaymentStatusPending || current == PaymentStatusFailed {
+			return true, s.UpdatePaymentStatus(PaymentStatusPaid, "", reason)
+		}
+	case PaymentIntentFailed:
+		if current == PaymentStatusPending {
+			return true, s.UpdatePaymentStatus(PaymentStatusFailed, "", reason)
+		}
+	case PaymentIntentCanceled:
+		if current == PaymentStatusPending {
+			return true, s.UpdatePaymentStatus(PaymentStatusUnpaid, "", reason)
+		}
+	}
+	return false, nil
+}
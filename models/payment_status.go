@@ -0,0 +1,184 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// --- 支払い状態（提供の進行とは独立した状態） ---
+
+// PaymentStatus は注文の支払いの状態です。
+// 提供の進行（Status）とは独立しており、「提供済みで未払い」「支払い済みで調理前」の注文を表せます。
+type PaymentStatus string
+
+const (
+	PaymentStatusUnpaid            PaymentStatus = "unpaid"
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusPaid              PaymentStatus = "paid"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+)
+
+var ErrPaymentRequired = errors.New("支払いが完了していないため、このステータスには進めません")
+
+// InvalidPaymentStatusTransitionError は許可されていない支払い状態の遷移を表します。
+type InvalidPaymentStatusTransitionError struct {
+	From PaymentStatus
+	To   PaymentStatus
+}
+
+func (e *InvalidPaymentStatusTransitionError) Error() string {
+	return fmt.Sprintf("支払い状態を '%s' から '%s' に遷移させることはできません", e.From, e.To)
+}
+
+// paymentTransitions は支払い状態の遷移表です。
+// 部分返金は返金のたびに記録するため、部分返金から部分返金への遷移も許可します。
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusUnpaid:            {PaymentStatusPending, PaymentStatusPaid},
+	PaymentStatusPending:           {PaymentStatusPaid, PaymentStatusFailed, PaymentStatusUnpaid},
+	PaymentStatusFailed:            {PaymentStatusPending, PaymentStatusPaid},
+	PaymentStatusPaid:              {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+	PaymentStatusPartiallyRefunded: {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+}
+
+// IsValid は定義済みの支払い状態かどうかを返します。
+func (p PaymentStatus) IsValid() bool {
+	switch p {
+	case PaymentStatusUnpaid, PaymentStatusPending, PaymentStatusPaid, PaymentStatusFailed,
+		PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo は支払い状態を newStatus に遷移できるかどうかを判定します。
+func (p PaymentStatus) CanTransitionTo(newStatus PaymentStatus) bool {
+	for _, to := range paymentTransitions[p] {
+		if to == newStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPaid は支払いを受けた状態（部分返金を含む）かどうかを判定します。
+func (p PaymentStatus) IsPaid() bool {
+	return p == PaymentStatusPaid || p == PaymentStatusPartiallyRefunded
+}
+
+// CanRefund は返金が可能な支払い状態かどうかを判定します。
+func (p PaymentStatus) CanRefund() bool {
+	return p.IsPaid()
+}
+
+// AcceptsChanges は注文の内容（商品・割引・チャージ）の変更で金額が変わってもよい支払い状態かどうかを判定します。
+// 支払い手続き中・支払い済みの注文の金額は変更できません。
+func (p PaymentStatus) AcceptsChanges() bool {
+	return p == PaymentStatusUnpaid || p == PaymentStatusFailed
+}
+
+// PaymentStatusChange は支払い状態の遷移の記録です。
+type PaymentStatusChange struct {
+	From   PaymentStatus `json:"from,omitempty"`
+	To     PaymentStatus `json:"to"`
+	At     time.Time     `json:"at"`
+	Actor  string        `json:"actor,omitempty"`
+	Reason string        `json:"reason,omitempty"`
+}
+
+// --- Session の支払い状態 ---
+
+// UpdatePaymentStatus は操作者と理由を指定して注文の支払い状態を更新し、遷移を履歴に記録します。
+func (s *Session) UpdatePaymentStatus(newStatus PaymentStatus, actor, reason string) error {
+	from := s.paymentStatus()
+	if !from.CanTransitionTo(newStatus) {
+		return &InvalidPaymentStatusTransitionError{From: from, To: newStatus}
+	}
+
+	s.PaymentStatus = newStatus
+	s.setUpdatedAt()
+	s.PaymentHistory = append(s.PaymentHistory, PaymentStatusChange{
+		From:   from,
+		To:     newStatus,
+		At:     s.UpdatedAt,
+		Actor:  actor,
+		Reason: reason,
+	})
+	return nil
+}
+
+// PaymentTimeline は支払い状態の遷移の履歴を返します。
+// 履歴がない場合（移行前の注文など）は、現在の支払い状態を作成日時の記録として返します。
+func (s *Session) PaymentTimeline() []PaymentStatusChange {
+	if len(s.PaymentHistory) == 0 {
+		return []PaymentStatusChange{{To: s.paymentStatus(), At: s.CreatedAt}}
+	}
+	return s.PaymentHistory
+}
+
+// paymentStatus は支払い状態を返します。未設定の場合は未払いです。
+func (s *Session) paymentStatus() PaymentStatus {
+	if s.PaymentStatus == "" {
+		return PaymentStatusUnpaid
+	}
+	return s.PaymentStatus
+}
+
+// canChangeItems は注文の内容（商品・割引・チャージ）を変更できるかどうかを判定します。
+// 提供の進行はワークフローで、金額の変更は支払い状態で判定します。
+func (s *Session) canChangeItems() bool {
+	return s.Workflow().CanAddItem(s.Status) && s.paymentStatus().AcceptsChanges()
+}
+
+// --- 既存データの移行 ---
+
+// MigrateLegacyStatus は支払い状態と提供の進行を1つのステータスで管理していた注文を、2つの状態に分けます。
+// 支払い状態が記録済みの注文は変更せず false を返します。
+// 返金済み・部分返金の注文は、返金前の提供の状態を履歴から復元します（履歴がない場合は完了）。
+func (s *Session) MigrateLegacyStatus() bool {
+	if s.PaymentStatus != "" {
+		return false
+	}
+
+	switch s.Status {
+	case StatusPendingPayment:
+		s.Status, s.PaymentStatus = StatusCreated, PaymentStatusPending
+	case StatusPaymentFailed:
+		s.Status, s.PaymentStatus = StatusCreated, PaymentStatusFailed
+	case StatusPaymentReceived:
+		// 旧フローでは支払い後に店舗の確認を待っていた
+		s.Status, s.PaymentStatus = StatusPendingConfirmation, PaymentStatusPaid
+	case StatusRefunded, StatusPartiallyRefunded:
+		s.PaymentStatus = PaymentStatus(s.Status)
+		s.Status = s.lastFulfillmentStatus()
+	case StatusCompleted:
+		// 完了した注文はレジで精算済み
+		s.PaymentStatus = PaymentStatusPaid
+	default:
+		s.PaymentStatus = PaymentStatusUnpaid
+	}
+	return true
+}
+
+// lastFulfillmentStatus は履歴から、支払いに関するステータスを除いた最後のステータスを返します。
+func (s *Session) lastFulfillmentStatus() Status {
+	for i := len(s.StatusHistory) - 1; i >= 0; i-- {
+		if to := s.StatusHistory[i].To; !to.isLegacyPaymentStatus() {
+			return to
+		}
+	}
+	return StatusCompleted
+}
+
+// isLegacyPaymentStatus は支払い状態を表していた旧ステータスかどうかを返します。
+func (s Status) isLegacyPaymentStatus() bool {
+	switch s {
+	case StatusPendingPayment, StatusPaymentReceived, StatusPaymentFailed, StatusRefunded, StatusPartiallyRefunded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentStatus_CanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		from     PaymentStatus
+		to       PaymentStatus
+		expected bool
+	}{
+		{PaymentStatusUnpaid, PaymentStatusPending, true},
+		{PaymentStatusUnpaid, PaymentStatusPaid, true},
+		{PaymentStatusUnpaid, PaymentStatusRefunded, false},
+		{PaymentStatusPending, PaymentStatusPaid, true},
+		{PaymentStatusPending, PaymentStatusFailed, true},
+		{PaymentStatusPending, PaymentStatusUnpaid, true},
+		{PaymentStatusFailed, PaymentStatusPending, true},
+		{PaymentStatusFailed, PaymentStatusPaid, true},
+		{PaymentStatusPaid, PaymentStatusPartiallyRefunded, true},
+		{PaymentStatusPaid, PaymentStatusRefunded, true},
+		{PaymentStatusPaid, PaymentStatusUnpaid, false},
+		{PaymentStatusPartiallyRefunded, PaymentStatusPartiallyRefunded, true},
+		{PaymentStatusPartiallyRefunded, PaymentStatusRefunded, true},
+		{PaymentStatusRefunded, PaymentStatusPaid, false},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+"_to_"+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+
+	assert.True(t, PaymentStatusPartiallyRefunded.IsPaid())
+	assert.False(t, PaymentStatusRefunded.IsPaid())
+	assert.True(t, PaymentStatusFailed.AcceptsChanges())
+	assert.False(t, PaymentStatusPending.AcceptsChanges())
+	assert.False(t, PaymentStatus("settled").IsValid())
+}
+
+func TestSession_PaymentStatusIsIndependentOfFulfillment(t *testing.T) {
+	t.Run("提供済みで未払い", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdateStatus(StatusConfirmed))
+		require.NoError(t, s.UpdateStatus(StatusPreparing))
+		require.NoError(t, s.UpdateStatus(StatusReadyForPickup))
+		require.NoError(t, s.UpdateStatus(StatusServed))
+		assert.Equal(t, PaymentStatusUnpaid, s.PaymentStatus)
+	})
+
+	t.Run("支払い済みで調理前", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.UpdatePaymentStatus(PaymentStatusPaid, "manager:a@example.com", "レジでの精算"))
+		assert.Equal(t, StatusCreated, s.Status)
+		require.Len(t, s.PaymentHistory, 2)
+		assert.Equal(t, PaymentStatusUnpaid, s.PaymentHistory[1].From)
+		assert.Equal(t, "manager:a@example.com", s.PaymentHistory[1].Actor)
+
+		// 支払い済みの注文の金額は変更できない
+		assert.ErrorAs(t, s.AddItem(*NewOrder("p2", 1, Yen(100))), new(*CannotAddItemError))
+	})
+
+	t.Run("不正な支払い状態の遷移", func(t *testing.T) {
+		s := newTestSession(t)
+		err := s.UpdatePaymentStatus(PaymentStatusRefunded, "", "")
+		assert.ErrorAs(t, err, new(*InvalidPaymentStatusTransitionError))
+		assert.Equal(t, PaymentStatusUnpaid, s.PaymentStatus)
+	})
+}
+
+func TestSession_MigrateLegacyStatus(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      Status
+		history     []Status
+		wantStatus  Status
+		wantPayment PaymentStatus
+	}{
+		{"支払い待ち", StatusPendingPayment, nil, StatusCreated, PaymentStatusPending},
+		{"支払い失敗", StatusPaymentFailed, nil, StatusCreated, PaymentStatusFailed},
+		{"支払い済みで確認待ち", StatusPaymentReceived, nil, StatusPendingConfirmation, PaymentStatusPaid},
+		{"提供後に部分返金", StatusPartiallyRefunded, []Status{StatusCreated, StatusServed, StatusPartiallyRefunded}, StatusServed, PaymentStatusPartiallyRefunded},
+		{"履歴のない返金済み", StatusRefunded, nil, StatusCompleted, PaymentStatusRefunded},
+		{"完了", StatusCompleted, nil, StatusCompleted, PaymentStatusPaid},
+		{"調理中", StatusPreparing, nil, StatusPreparing, PaymentStatusUnpaid},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Session{Status: tc.status}
+			for _, to := range tc.history {
+				s.StatusHistory = append(s.StatusHistory, StatusChange{To: to})
+			}
+			assert.True(t, s.MigrateLegacyStatus())
+			assert.Equal(t, tc.wantStatus, s.Status)
+			assert.Equal(t, tc.wantPayment, s.PaymentStatus)
+		})
+	}
+
+	s := &Session{Status: StatusServed, PaymentStatus: PaymentStatusPaid}
+	assert.False(t, s.MigrateLegacyStatus(), "移行済みの注文は変更しない")
+}
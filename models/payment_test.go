@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPayment(t *testing.T) {
+	now := time.Now()
+
+	t.Run("注文を支払い待ちにする", func(t *testing.T) {
+		s := newTestSession(t)
+		payment, err := NewPayment(s, "stripe", "pi_1", now)
+		require.NoError(t, err)
+		assert.Equal(t, s.TotalAmount, payment.Amount)
+		assert.Equal(t, PaymentIntentRequiresPayment, payment.Status)
+		assert.Equal(t, PaymentStatusPending, s.PaymentStatus)
+		assert.Equal(t, StatusCreated, s.Status, "提供の進行は変更しない")
+
+		_, err = NewPayment(s, "stripe", "pi_2", now)
+		assert.NoError(t, err, "支払い待ちの注文は再度支払いを開始できる")
+	})
+
+	t.Run("提供済みで未払いの注文は支払いを開始できる", func(t *testing.T) {
+		s := newTestSession(t)
+		s.Status = StatusServed
+		_, err := NewPayment(s, "stripe", "pi_1", now)
+		require.NoError(t, err)
+		assert.Equal(t, PaymentStatusPending, s.PaymentStatus)
+		assert.Equal(t, StatusServed, s.Status)
+	})
+
+	t.Run("キャンセルした注文・支払い済みの注文は支払いを開始できない", func(t *testing.T) {
+		s := newTestSession(t)
+		s.Status = StatusCancelled
+		_, err := NewPayment(s, "stripe", "pi_1", now)
+		assert.ErrorIs(t, err, ErrPaymentNotAllowed)
+
+		s = newTestSession(t)
+		s.PaymentStatus = PaymentStatusPaid
+		_, err = NewPayment(s, "stripe", "pi_1", now)
+		assert.ErrorIs(t, err, ErrPaymentNotAllowed)
+	})
+}
+
+func TestPayment_ApplyStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		from    PaymentIntentStatus
+		to      PaymentIntentStatus
+		changed bool
+	}{
+		{"オーソリ", PaymentIntentRequiresPayment, PaymentIntentRequiresCapture, true},
+		{"支払い完了", PaymentIntentRequiresPayment, PaymentIntentSucceeded, true},
+		{"売上確定", PaymentIntentRequiresCapture, PaymentIntentSucceeded, true},
+		{"重複した通知", PaymentIntentSucceeded, PaymentIntentSucceeded, false},
+		{"完了後の失敗の通知は無視する", PaymentIntentSucceeded, PaymentIntentFailed, false},
+		{"失敗後の再試行で成功", PaymentIntentFailed, PaymentIntentSucceeded, true},
+		{"オーソリ済みは支払い手続き待ちに戻らない", PaymentIntentRequiresCapture, PaymentIntentRequiresPayment, false},
+		{"取り消し後は変更しない", PaymentIntentCanceled, PaymentIntentSucceeded, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Payment{Status: tt.from}
+			assert.Equal(t, tt.changed, p.ApplyStatus(tt.to, "", now))
+		})
+	}
+
+	p := &Payment{Status: PaymentIntentRequiresPayment}
+	p.ApplyStatus(PaymentIntentFailed, " card_declined ", now)
+	assert.Equal(t, "card_declined", p.FailureReason)
+}
+
+func TestSession_ApplyPaymentStatus(t *testing.T) {
+	t.Run("支払い完了", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.StartPayment())
+		changed, err := s.ApplyPaymentStatus(PaymentIntentSucceeded)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, PaymentStatusPaid, s.PaymentStatus)
+		assert.Equal(t, StatusCreated, s.Status)
+
+		changed, err = s.ApplyPaymentStatus(PaymentIntentSucceeded)
+		require.NoError(t, err)
+		assert.False(t, changed, "重複した通知では変更しない")
+	})
+
+	t.Run("失敗の後に再試行で成功", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.StartPayment())
+		_, err := s.ApplyPaymentStatus(PaymentIntentFailed)
+		require.NoError(t, err)
+		assert.Equal(t, PaymentStatusFailed, s.PaymentStatus)
+
+		_, err = s.ApplyPaymentStatus(PaymentIntentSucceeded)
+		require.NoError(t, err)
+		assert.Equal(t, PaymentStatusPaid, s.PaymentStatus)
+	})
+
+	t.Run("取り消しで未払いに戻る", func(t *testing.T) {
+		s := newTestSession(t)
+		require.NoError(t, s.StartPayment())
+		changed, err := s.ApplyPaymentStatus(PaymentIntentCanceled)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, PaymentStatusUnpaid, s.PaymentStatus)
+	})
+}
+
+func TestPaymentEvent_IntentStatus(t *testing.T) {
+	status, ok := (&PaymentEvent{Type: PaymentEventAuthorized}).IntentStatus()
+	assert.True(t, ok)
+	assert.Equal(t, PaymentIntentRequiresCapture, status)
+
+	_, ok = (&PaymentEvent{Type: PaymentEventIgnored}).IntentStatus()
+	assert.False(t, ok)
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Permission は管理APIの操作権限を表します。
+// "<リソース>:<操作>" の形式で、APIキーのスコープとして付与します。
+type Permission string
+
+const (
+	PermissionStoresRead  Permission = "stores:read"
+	PermissionStoresWrite Permission = "stores:write"
+	PermissionSeatsRead   Permission = "seats:read"
+	PermissionSeatsWrite  Permission = "seats:write"
+	PermissionOrdersRead  Permission = "orders:read"
+	PermissionOrdersWrite Permission = "orders:write"
+	// PermissionOrdersOverride は遷移ルールによらず注文のステータスを強制変更する権限です。
+	PermissionOrdersOverride Permission = "orders:override"
+)
+
+var ErrInvalidPermission = errors.New("権限の指定が不正です")
+
+// AllPermissions は定義済みの全ての権限を返します。
+func AllPermissions() []Permission {
+	return []Permission{
+		PermissionStoresRead,
+		PermissionStoresWrite,
+		PermissionSeatsRead,
+		PermissionSeatsWrite,
+		PermissionOrdersRead,
+		PermissionOrdersWrite,
+		PermissionOrdersOverride,
+	}
+}
+
+// ManagerPermissions はマネージャーJWTでログインしたユーザーの権限を返します。
+// ステータスの強制変更（PermissionOrdersOverride）は含みません。マネージャーには店舗ごとに明示的に付与します（Store.OverrideManagers）。
+func ManagerPermissions() []Permission {
+	permissions := []Permission{}
+	for _, permission := range AllPermissions() {
+		if permission != PermissionOrdersOverride {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
+// IsValid は定義済みの権限かどうかを返します。
+func (p Permission) IsValid() bool {
+	for _, permission := range AllPermissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePermissions は文字列の一覧を権限の一覧に変換します。
+// 重複は取り除き、未定義の権限が含まれる場合はエラーを返します。
+func ParsePermissions(values []string) ([]Permission, error) {
+	permissions := make([]Permission, 0, len(values))
+	seen := make(map[Permission]bool, len(values))
+	for _, value := range values {
+		permission := Permission(value)
+		if !permission.IsValid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPermission, value)
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+// HasPermission は granted に required が含まれるかどうかを返します。
+func HasPermission(granted []Permission, required Permission) bool {
+	for _, permission := range granted {
+		if permission == required {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePermissions(t *testing.T) {
+	permissions, err := ParsePermissions([]string{"orders:read", "stores:read", "orders:read"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Permission{PermissionOrdersRead, PermissionStoresRead}, permissions, "重複は取り除く")
+
+	_, err = ParsePermissions([]string{"orders:delete"})
+	assert.ErrorIs(t, err, ErrInvalidPermission)
+}
+
+func TestHasPermission(t *testing.T) {
+	granted := []Permission{PermissionOrdersRead}
+	assert.True(t, HasPermission(granted, PermissionOrdersRead))
+	assert.False(t, HasPermission(granted, PermissionOrdersWrite))
+
+	for _, permission := range AllPermissions() {
+		assert.True(t, permission.IsValid())
+		if permission != PermissionOrdersOverride {
+			assert.True(t, HasPermission(ManagerPermissions(), permission), "マネージャーは強制変更以外の権限を持つ")
+		}
+	}
+	assert.False(t, HasPermission(ManagerPermissions(), PermissionOrdersOverride), "強制変更の権限は明示的に付与する")
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProductPrefix は店舗のメニューの商品IDのプレフィックスです。
+const ProductPrefix = "prod_"
+
+var (
+	ErrInvalidProduct = errors.New("メニューの商品の設定が不正です")
+	ErrUnknownProduct = errors.New("メニューにない商品です")
+)
+
+// Product は店舗のメニュー（商品カタログ）の商品です。
+// 注文の単価・通貨・カテゴリ・消費税区分はお客様の入力ではなく、この設定から決定します。
+// Price は補助単位（円、セント等）の整数で、Currency を省略した場合は JPY として扱います。
+// TaxCategory を省略した場合は標準税率です。
+// 提供を終了した商品は、過去の注文と対応できるよう削除せず Active を false にします。
+type Product struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Price       int64       `json:"price"`
+	Currency    Currency    `json:"currency,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	TaxCategory TaxCategory `json:"tax_category,omitempty"`
+	Active      bool        `json:"active"`
+}
+
+// UnitPrice は商品の単価です。
+func (p Product) UnitPrice() Money {
+	return NewMoney(p.Price, p.Currency)
+}
+
+// NormalizeProducts はメニューの商品を検証し、IDのない商品にIDを割り当てます。
+func NormalizeProducts(products []Product) ([]Product, error) {
+	normalized := make([]Product, len(products))
+	ids := make(map[string]bool, len(products))
+	for i, product := range products {
+		product.ID = strings.TrimSpace(product.ID)
+		product.Name = strings.TrimSpace(product.Name)
+		product.Category = strings.TrimSpace(product.Category)
+		switch {
+		case product.Name == "":
+			return nil, fmt.Errorf("%w: 名前を指定してください (products[%d])", ErrInvalidProduct, i)
+		case product.Price < 0:
+			return nil, fmt.Errorf("%w: 価格は0以上で指定してください (products[%d])", ErrInvalidProduct, i)
+		case product.Currency != "" && !product.Currency.IsValid():
+			return nil, fmt.Errorf("%w: %s (products[%d])", ErrUnsupportedCurrency, product.Currency, i)
+		case !product.TaxCategory.IsValid():
+			return nil, fmt.Errorf("%w: %q (products[%d])", ErrInvalidTaxCategory, product.TaxCategory, i)
+		}
+		if product.ID == "" {
+			product.ID = GenerateUniqueID(ProductPrefix)
+		}
+		if ids[product.ID] {
+			return nil, fmt.Errorf("%w: ID %q が重複しています (products[%d])", ErrInvalidProduct, product.ID, i)
+		}
+		ids[product.ID] = true
+		normalized[i] = product
+	}
+	return normalized, nil
+}
+
+// LookupProduct は提供中のメニューの商品を返します。
+func (s *Store) LookupProduct(id string) (Product, error) {
+	for _, product := range s.Products {
+		if product.ID == id && product.Active {
+			return product, nil
+		}
+	}
+	return Product{}, fmt.Errorf("%w: %q", ErrUnknownProduct, id)
+}
+
+// ActiveProducts は提供中のメニューの商品を返します。
+func (s *Store) ActiveProducts() []Product {
+	products := []Product{}
+	for _, product := range s.Products {
+		if product.Active {
+			products = append(products, product)
+		}
+	}
+	return products
+}
+
+// PriceItems は注文の商品の単価・カテゴリ・消費税区分をメニューの設定で置き換えた注文アイテムを返します。
+// お客様の端末から送られた金額は使用しません。
+func (s *Store) PriceItems(items []Order) ([]Order, error) {
+	priced := make([]Order, len(items))
+	for i, item := range items {
+		product, err := s.LookupProduct(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("%w (items[%d])", err, i)
+		}
+		item.Price = product.UnitPrice()
+		item.Category = product.Category
+		item.TaxCategory = product.TaxCategory
+		priced[i] = item
+	}
+	return priced, nil
+}
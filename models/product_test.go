@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeProducts(t *testing.T) {
+	products, err := NormalizeProducts([]Product{
+		{ID: "ramen", Name: " ラーメン ", Price: 900, Active: true},
+		{Name: "ビール", Price: 600, Category: "drink", Active: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ラーメン", products[0].Name)
+	assert.Contains(t, products[1].ID, ProductPrefix)
+
+	invalid := [][]Product{
+		{{ID: "ramen", Name: " ", Price: 900}},
+		{{ID: "ramen", Name: "ラーメン", Price: -1}},
+		{{ID: "ramen", Name: "ラーメン", Price: 900}, {ID: "ramen", Name: "味噌ラーメン", Price: 950}},
+	}
+	for _, p := range invalid {
+		_, err := NormalizeProducts(p)
+		assert.ErrorIs(t, err, ErrInvalidProduct)
+	}
+	_, err = NormalizeProducts([]Product{{Name: "ラーメン", Price: 900, Currency: "XXX"}})
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	_, err = NormalizeProducts([]Product{{Name: "ラーメン", Price: 900, TaxCategory: "luxury"}})
+	assert.ErrorIs(t, err, ErrInvalidTaxCategory)
+}
+
+func TestStore_PriceItems(t *testing.T) {
+	store := &Store{Products: []Product{
+		{ID: "ramen", Name: "ラーメン", Price: 900, Category: "food", TaxCategory: TaxCategoryFood, Active: true},
+		{ID: "beer", Name: "ビール", Price: 600, Currency: CurrencyUSD, Active: false},
+	}}
+
+	items, err := store.PriceItems([]Order{*NewOrder("ramen", 2, Yen(1)).WithCategory("drink").WithTaxCategory(TaxCategoryExempt)})
+	require.NoError(t, err)
+	assert.Equal(t, Yen(900), items[0].Price)
+	assert.Equal(t, "food", items[0].Category)
+	assert.Equal(t, TaxCategoryFood, items[0].TaxCategory)
+
+	_, err = store.PriceItems([]Order{*NewOrder("beer", 1, Money{})})
+	assert.ErrorIs(t, err, ErrUnknownProduct, "提供を終了した商品")
+	assert.Len(t, store.ActiveProducts(), 1)
+}
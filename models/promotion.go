@@ -0,0 +1,231 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// PromotionPrefix はプロモーションIDのプレフィックスです。
+	PromotionPrefix = "promo_"
+	// PromotionRedemptionPrefix はプロモーションコードの利用記録IDのプレフィックスです。
+	PromotionRedemptionPrefix = "redeem_"
+)
+
+var (
+	ErrPromotionNotFound          = errors.New("プロモーションコードが見つかりません")
+	ErrPromotionCodeRequired      = errors.New("プロモーションコードを指定してください")
+	ErrPromotionNameRequired      = errors.New("プロモーション名を指定してください")
+	ErrPromotionCodeExists        = errors.New("このプロモーションコードはすでに使用されています")
+	ErrInvalidPromotionPeriod     = errors.New("プロモーションの終了日時は開始日時より後にしてください")
+	ErrInvalidPromotionLimit      = errors.New("利用回数の上限は0以上で指定してください")
+	ErrPromotionInactive          = errors.New("このプロモーションコードは現在利用できません")
+	ErrPromotionNotStarted        = errors.New("このプロモーションコードはまだ利用できません")
+	ErrPromotionExpired           = errors.New("このプロモーションコードの有効期限が切れています")
+	ErrPromotionUsageLimitReached = errors.New("このプロモーションコードは利用回数の上限に達しました")
+	ErrPromotionVisitLimitReached = errors.New("このご来店でのプロモーションコードの利用回数の上限に達しました")
+	ErrPromotionMinSpendNotMet    = errors.New("プロモーションコードの利用条件の金額に達していません")
+	ErrPromotionAlreadyApplied    = errors.New("このプロモーションコードはすでに注文に適用されています")
+)
+
+// NormalizePromotionCode はプロモーションコードを比較用に正規化します（前後の空白を除去し、大文字に変換）。
+func NormalizePromotionCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Promotion は店舗が発行するプロモーションコードです。
+// StartsAt、EndsAt がゼロ値の場合は期間の制限なし、MaxRedemptions、MaxPerVisit が0の場合は回数の制限なしです。
+type Promotion struct {
+	ID      string
+	StoreID string
+	Code    string
+	Name    string
+	Rule    DiscountRule
+
+	// Currency は固定額の割引と MinSpend の通貨です。
+	Currency Currency
+	MinSpend Money
+
+	StartsAt time.Time
+	EndsAt   time.Time
+
+	// MaxRedemptions はコード全体での利用回数の上限、MaxPerVisit は座席の1回の来店あたりの上限です。
+	MaxRedemptions int
+	MaxPerVisit    int
+
+	Active bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewPromotion は新しいプロモーションを作成します。
+// 利用条件（最低利用金額、期間、回数の上限）は作成後に設定し、Validate で検証してください。
+func NewPromotion(storeID, code, name string, rule DiscountRule, currency Currency) *Promotion {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	now := time.Now().UTC()
+	return &Promotion{
+		ID:        GenerateUniqueID(PromotionPrefix),
+		StoreID:   storeID,
+		Code:      NormalizePromotionCode(code),
+		Name:      strings.TrimSpace(name),
+		Rule:      rule,
+		Currency:  currency,
+		MinSpend:  Zero(currency),
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate はプロモーションの設定を検証します。
+func (p *Promotion) Validate() error {
+	if p.StoreID == "" {
+		return ErrStoreIDRequired
+	}
+	if p.Code == "" {
+		return ErrPromotionCodeRequired
+	}
+	if p.Name == "" {
+		return ErrPromotionNameRequired
+	}
+	if err := p.Rule.Validate(); err != nil {
+		return err
+	}
+	if !p.Currency.IsValid() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCurrency, p.Currency)
+	}
+	if p.MinSpend.IsNegative() {
+		return ErrNegativeAmount
+	}
+	if !p.MinSpend.IsZero() && p.MinSpend.currency() != p.Currency {
+		return fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, p.Currency, p.MinSpend.currency())
+	}
+	if !p.StartsAt.IsZero() && !p.EndsAt.IsZero() && !p.EndsAt.After(p.StartsAt) {
+		return ErrInvalidPromotionPeriod
+	}
+	if p.MaxRedemptions < 0 || p.MaxPerVisit < 0 {
+		return ErrInvalidPromotionLimit
+	}
+	return nil
+}
+
+// CheckAvailable はプロモーションが now の時点で利用可能かどうかを確認します。
+func (p *Promotion) CheckAvailable(now time.Time) error {
+	switch {
+	case !p.Active:
+		return ErrPromotionInactive
+	case !p.StartsAt.IsZero() && now.Before(p.StartsAt):
+		return ErrPromotionNotStarted
+	case !p.EndsAt.IsZero() && !now.Before(p.EndsAt):
+		return ErrPromotionExpired
+	}
+	return nil
+}
+
+// CheckUsage はこれまでの利用記録から、利用回数の上限を確認します。
+// 同じ注文への二重適用、コード全体の上限、座席の来店（visitID）ごとの上限の順に確認します。
+func (p *Promotion) CheckUsage(redemptions []*PromotionRedemption, sessionID, visitID string) error {
+	perVisit := 0
+	for _, r := range redemptions {
+		if r.SessionID == sessionID {
+			return ErrPromotionAlreadyApplied
+		}
+		if visitID != "" && r.VisitID == visitID {
+			perVisit++
+		}
+	}
+	if p.MaxRedemptions > 0 && len(redemptions) >= p.MaxRedemptions {
+		return ErrPromotionUsageLimitReached
+	}
+	if p.MaxPerVisit > 0 && perVisit >= p.MaxPerVisit {
+		return ErrPromotionVisitLimitReached
+	}
+	return nil
+}
+
+// Deactivate はプロモーションを停止します。適用済みの割引は取り消されません。
+func (p *Promotion) Deactivate() {
+	p.Active = false
+	p.UpdatedAt = time.Now().UTC()
+}
+
+// CheckConditions は注文がプロモーションの利用条件（通貨、最低利用金額）を満たしているかを確認します。
+// 最低利用金額は割引前の小計で判定します。適用後にお客様が商品を変更した場合も、この条件で割引を残すかどうかを判定します。
+func (p *Promotion) CheckConditions(session *Session) error {
+	// 固定額の割引と最低利用金額は、注文と同じ通貨のプロモーションのみ適用できる
+	if (p.Rule.Kind == DiscountFixed || !p.MinSpend.IsZero()) && p.Currency != session.Currency() {
+		return fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, session.Currency(), p.Currency)
+	}
+	if p.MinSpend.IsZero() {
+		return nil
+	}
+	subtotal, err := session.Subtotal()
+	if err != nil {
+		return err
+	}
+	if subtotal.Amount < p.MinSpend.Amount {
+		return fmt.Errorf("%w: %s以上のご注文が必要です", ErrPromotionMinSpendNotMet, p.MinSpend.Format())
+	}
+	return nil
+}
+
+// NewDiscount は注文に適用するプロモーションの割引を作成します。
+func (p *Promotion) NewDiscount(session *Session, now time.Time) (*Discount, error) {
+	if err := p.CheckAvailable(now); err != nil {
+		return nil, err
+	}
+	for _, d := range session.Discounts {
+		if d.PromotionID == p.ID {
+			return nil, ErrPromotionAlreadyApplied
+		}
+	}
+
+	if err := p.CheckConditions(session); err != nil {
+		return nil, err
+	}
+
+	return &Discount{
+		ID:          GenerateUniqueID(DiscountPrefix),
+		Source:      DiscountSourcePromotion,
+		PromotionID: p.ID,
+		Code:        p.Code,
+		Rule:        p.Rule,
+		AppliedAt:   now.UTC(),
+	}, nil
+}
+
+// PromotionRedemption はプロモーションコードの利用記録です。
+// 利用回数の上限の判定に使用し、割引を取り消した場合は削除します。
+type PromotionRedemption struct {
+	ID          string
+	PromotionID string
+	Code        string
+	StoreID     string
+	SessionID   string
+	SeatID      string
+	VisitID     string
+	DiscountID  string
+	Amount      Money
+	RedeemedAt  time.Time
+}
+
+// NewPromotionRedemption は注文に適用した割引の利用記録を作成します。
+func NewPromotionRedemption(session *Session, discount *Discount, visitID string, now time.Time) *PromotionRedemption {
+	return &PromotionRedemption{
+		ID:          GenerateUniqueID(PromotionRedemptionPrefix),
+		PromotionID: discount.PromotionID,
+		Code:        discount.Code,
+		StoreID:     session.StoreID,
+		SessionID:   session.ID,
+		SeatID:      session.SeatID,
+		VisitID:     visitID,
+		DiscountID:  discount.ID,
+		Amount:      discount.Amount,
+		RedeemedAt:  now.UTC(),
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPromotion() *Promotion {
+	return NewPromotion("store_1", " spring10 ", "春の10%オフ", DiscountRule{Kind: DiscountPercentage, Value: 10, Scope: DiscountScopeOrder}, CurrencyJPY)
+}
+
+func TestNewPromotion(t *testing.T) {
+	promo := newTestPromotion()
+	assert.Contains(t, promo.ID, PromotionPrefix)
+	assert.Equal(t, "SPRING10", promo.Code)
+	assert.True(t, promo.Active)
+	assert.NoError(t, promo.Validate())
+}
+
+func TestPromotion_Validate(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name     string
+		modify   func(p *Promotion)
+		expected error
+	}{
+		{"コードなし", func(p *Promotion) { p.Code = "" }, ErrPromotionCodeRequired},
+		{"名前なし", func(p *Promotion) { p.Name = "" }, ErrPromotionNameRequired},
+		{"割引の設定が不正", func(p *Promotion) { p.Rule.Value = 0 }, ErrInvalidDiscountValue},
+		{"終了が開始より前", func(p *Promotion) { p.StartsAt = now; p.EndsAt = now.Add(-time.Hour) }, ErrInvalidPromotionPeriod},
+		{"回数の上限が負", func(p *Promotion) { p.MaxPerVisit = -1 }, ErrInvalidPromotionLimit},
+		{"最低利用金額の通貨が異なる", func(p *Promotion) { p.MinSpend = NewMoney(100, CurrencyUSD) }, ErrCurrencyMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			promo := newTestPromotion()
+			tc.modify(promo)
+			assert.ErrorIs(t, promo.Validate(), tc.expected)
+		})
+	}
+}
+
+func TestPromotion_CheckAvailable(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	promo := newTestPromotion()
+	promo.StartsAt = now.Add(-time.Hour)
+	promo.EndsAt = now.Add(time.Hour)
+	assert.NoError(t, promo.CheckAvailable(now))
+	assert.ErrorIs(t, promo.CheckAvailable(now.Add(-2*time.Hour)), ErrPromotionNotStarted)
+	assert.ErrorIs(t, promo.CheckAvailable(now.Add(time.Hour)), ErrPromotionExpired)
+
+	promo.Deactivate()
+	assert.ErrorIs(t, promo.CheckAvailable(now), ErrPromotionInactive)
+}
+
+func TestPromotion_CheckUsage(t *testing.T) {
+	redemptions := []*PromotionRedemption{
+		{SessionID: "s1", VisitID: "visit_1"},
+		{SessionID: "s2", VisitID: "visit_2"},
+	}
+
+	t.Run("制限なし", func(t *testing.T) {
+		assert.NoError(t, newTestPromotion().CheckUsage(redemptions, "s3", "visit_1"))
+	})
+
+	t.Run("同じ注文には1回のみ", func(t *testing.T) {
+		assert.ErrorIs(t, newTestPromotion().CheckUsage(redemptions, "s1", "visit_1"), ErrPromotionAlreadyApplied)
+	})
+
+	t.Run("コード全体の上限", func(t *testing.T) {
+		promo := newTestPromotion()
+		promo.MaxRedemptions = 2
+		assert.ErrorIs(t, promo.CheckUsage(redemptions, "s3", "visit_3"), ErrPromotionUsageLimitReached)
+	})
+
+	t.Run("来店ごとの上限", func(t *testing.T) {
+		promo := newTestPromotion()
+		promo.MaxPerVisit = 1
+		assert.ErrorIs(t, promo.CheckUsage(redemptions, "s3", "visit_1"), ErrPromotionVisitLimitReached)
+		assert.NoError(t, promo.CheckUsage(redemptions, "s3", "visit_3"))
+	})
+}
+
+func TestPromotion_NewDiscount(t *testing.T) {
+	now := time.Now()
+
+	t.Run("プロモーションの割引を適用", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		promo := newTestPromotion()
+
+		discount, err := promo.NewDiscount(session, now)
+		require.NoError(t, err)
+		require.NoError(t, session.ApplyDiscount(discount))
+		assert.Equal(t, DiscountSourcePromotion, discount.Source)
+		assert.Equal(t, "SPRING10", discount.Code)
+		assert.Equal(t, Yen(218), discount.Amount)
+
+		_, err = promo.NewDiscount(session, now)
+		assert.ErrorIs(t, err, ErrPromotionAlreadyApplied)
+
+		redemption := NewPromotionRedemption(session, discount, "visit_1", now)
+		assert.Equal(t, promo.ID, redemption.PromotionID)
+		assert.Equal(t, Yen(218), redemption.Amount)
+	})
+
+	t.Run("最低利用金額は割引前の小計で判定", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		promo := newTestPromotion()
+		promo.MinSpend = Yen(3000)
+
+		_, err := promo.NewDiscount(session, now)
+		assert.ErrorIs(t, err, ErrPromotionMinSpendNotMet)
+
+		promo.MinSpend = Yen(2180)
+		_, err = promo.NewDiscount(session, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("固定額は同じ通貨の注文のみ", func(t *testing.T) {
+		session := newDiscountTestSession(t)
+		promo := NewPromotion("store_1", "USD5", "5ドル引き", DiscountRule{Kind: DiscountFixed, Value: 500, Scope: DiscountScopeOrder}, CurrencyUSD)
+
+		_, err := promo.NewDiscount(session, now)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+}
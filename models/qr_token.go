@@ -0,0 +1,142 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// QRTokenVersion は座席QRトークンの署名形式のバージョンです。
+// 形式を変更する場合はバージョンを上げ、ParseSeatQRToken で旧バージョンの扱いを決めます。
+const QRTokenVersion = 1
+
+var (
+	ErrInvalidQRToken    = errors.New("QRコードが不正です")
+	ErrQRTokenExpired    = errors.New("QRコードの有効期限が切れています")
+	ErrQRTokenRotated    = errors.New("QRコードは再発行により無効になっています")
+	ErrSeatStoreMismatch = errors.New("座席が指定された店舗に属していません")
+)
+
+// SeatQRToken は座席QRコードに埋め込む署名付きペイロードです。
+// Rotation が座席の QRVersion と一致しない場合、印刷済みの古いQRコードとして拒否されます。
+// ExpiresAt が設定されている場合は、タブレット表示用の短命な動的QRコードとして扱います。
+type SeatQRToken struct {
+	Version   int    `json:"v"`
+	StoreID   string `json:"store_id"`
+	SeatID    string `json:"seat_id"`
+	Rotation  int    `json:"rot"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// NewSeatQRToken は印刷用の静的なQRトークンを作成します。
+func NewSeatQRToken(seat *Seat) *SeatQRToken {
+	return &SeatQRToken{
+		Version:  QRTokenVersion,
+		StoreID:  seat.StoreID,
+		SeatID:   seat.ID,
+		Rotation: seat.QRVersion,
+		IssuedAt: time.Now().UTC().Unix(),
+	}
+}
+
+// NewDynamicSeatQRToken は ttl 経過後に失効する動的なQRトークンを作成します。
+func NewDynamicSeatQRToken(seat *Seat, ttl time.Duration) *SeatQRToken {
+	token := NewSeatQRToken(seat)
+	token.ExpiresAt = time.Unix(token.IssuedAt, 0).Add(ttl).Unix()
+	return token
+}
+
+// IsDynamic は有効期限付きの動的QRトークンかどうかを返します。
+func (t *SeatQRToken) IsDynamic() bool {
+	return t.ExpiresAt > 0
+}
+
+// Sign はトークンを "v<version>.<payload>.<signature>" 形式の文字列に署名します。
+func (t *SeatQRToken) Sign() (string, error) {
+	secret, err := qrSecret()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := fmt.Sprintf("v%d.%s", t.Version, base64.RawURLEncoding.EncodeToString(payload))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signQR(secret, signingInput)), nil
+}
+
+// ParseSeatQRToken は署名を検証してトークンを復元します。
+// 動的トークンの場合は now 時点で有効期限が切れていないことも確認します。
+func ParseSeatQRToken(token string, now time.Time) (*SeatQRToken, error) {
+	secret, err := qrSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != fmt.Sprintf("v%d", QRTokenVersion) {
+		return nil, ErrInvalidQRToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, signQR(secret, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidQRToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidQRToken
+	}
+
+	parsed := &SeatQRToken{}
+	if err := json.Unmarshal(payload, parsed); err != nil {
+		return nil, ErrInvalidQRToken
+	}
+	if parsed.Version != QRTokenVersion || parsed.StoreID == "" || parsed.SeatID == "" {
+		return nil, ErrInvalidQRToken
+	}
+	if parsed.IsDynamic() && !now.Before(time.Unix(parsed.ExpiresAt, 0)) {
+		return nil, ErrQRTokenExpired
+	}
+
+	return parsed, nil
+}
+
+// VerifySeat はトークンが現在の座席情報に対して有効かどうかを確認します。
+func (t *SeatQRToken) VerifySeat(seat *Seat) error {
+	if seat.ID != t.SeatID || seat.StoreID != t.StoreID {
+		return ErrSeatStoreMismatch
+	}
+	if seat.QRVersion != t.Rotation {
+		return ErrQRTokenRotated
+	}
+	return nil
+}
+
+// qrSecret は署名用の秘密鍵を返します。
+// QR_SECRET が未設定の場合は JWT_SECRET を使用します。
+func qrSecret() ([]byte, error) {
+	secret := os.Getenv("QR_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("QR_SECRET is not set")
+	}
+	return []byte(secret), nil
+}
+
+func signQR(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQRSeat() *Seat {
+	seat := NewSeat("Table 1")
+	seat.StoreID = "store_123"
+	return seat
+}
+
+func TestSeatQRToken_SignAndParse(t *testing.T) {
+	seat := newTestQRSeat()
+
+	signed, err := NewSeatQRToken(seat).Sign()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "v1."), "トークンにはバージョンが含まれる必要があります")
+
+	parsed, err := ParseSeatQRToken(signed, time.Now().UTC())
+	require.NoError(t, err)
+	assert.Equal(t, seat.StoreID, parsed.StoreID)
+	assert.Equal(t, seat.ID, parsed.SeatID)
+	assert.Equal(t, seat.QRVersion, parsed.Rotation)
+	assert.False(t, parsed.IsDynamic())
+	assert.NoError(t, parsed.VerifySeat(seat))
+}
+
+func TestParseSeatQRToken_Invalid(t *testing.T) {
+	seat := newTestQRSeat()
+	signed, err := NewSeatQRToken(seat).Sign()
+	require.NoError(t, err)
+	parts := strings.Split(signed, ".")
+
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{name: "空文字", token: ""},
+		{name: "区切りが不足", token: "v1.abc"},
+		{name: "未知のバージョン", token: "v9." + parts[1] + "." + parts[2]},
+		{name: "ペイロード改ざん", token: "v1." + parts[1] + "x." + parts[2]},
+		{name: "署名改ざん", token: "v1." + parts[1] + "." + parts[2][:len(parts[2])-2] + "AA"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSeatQRToken(tc.token, time.Now().UTC())
+			assert.ErrorIs(t, err, ErrInvalidQRToken)
+		})
+	}
+
+	t.Run("別の秘密鍵で署名", func(t *testing.T) {
+		t.Setenv("QR_SECRET", "another_secret")
+		_, err := ParseSeatQRToken(signed, time.Now().UTC())
+		assert.ErrorIs(t, err, ErrInvalidQRToken)
+	})
+}
+
+func TestSeatQRToken_Dynamic(t *testing.T) {
+	seat := newTestQRSeat()
+	token := NewDynamicSeatQRToken(seat, time.Minute)
+	assert.True(t, token.IsDynamic())
+
+	signed, err := token.Sign()
+	require.NoError(t, err)
+
+	_, err = ParseSeatQRToken(signed, time.Now().UTC())
+	assert.NoError(t, err)
+
+	_, err = ParseSeatQRToken(signed, time.Now().UTC().Add(2*time.Minute))
+	assert.ErrorIs(t, err, ErrQRTokenExpired)
+}
+
+func TestSeatQRToken_VerifySeat(t *testing.T) {
+	seat := newTestQRSeat()
+	token := NewSeatQRToken(seat)
+
+	t.Run("再発行後は無効", func(t *testing.T) {
+		rotated := *seat
+		rotated.RotateQR()
+		assert.ErrorIs(t, token.VerifySeat(&rotated), ErrQRTokenRotated)
+	})
+
+	t.Run("別店舗の座席", func(t *testing.T) {
+		other := *seat
+		other.StoreID = "store_other"
+		assert.ErrorIs(t, token.VerifySeat(&other), ErrSeatStoreMismatch)
+	})
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials    = errors.New("メールアドレスまたはパスワードが正しくありません")
+	ErrInvalidRateLimitValue = errors.New("レート制限の設定値が不正です")
+)
+
+// AccountLockedError はサインイン失敗が続いたためにアカウントがロックされていることを表します。
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("サインイン失敗が続いたためアカウントは %s までロックされています", e.Until.Format(time.RFC3339))
+}
+
+// RetryAfter は再試行が可能になるまでの残り時間を返します。
+func (e *AccountLockedError) RetryAfter(now time.Time) time.Duration {
+	if d := e.Until.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// RateLimitPolicy は一定期間内に許可するリクエスト数を表します。
+// Limit が0以下の場合は制限しません。
+type RateLimitPolicy struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ParseRateLimitPolicy は "20/1m" のような "回数/期間" 形式の文字列をポリシーに変換します。
+// "off" または空文字は制限なしとして扱います。
+func ParseRateLimitPolicy(value string) (RateLimitPolicy, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" {
+		return RateLimitPolicy{}, nil
+	}
+
+	count, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimitPolicy{}, fmt.Errorf("%w: %q", ErrInvalidRateLimitValue, value)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || limit < 0 {
+		return RateLimitPolicy{}, fmt.Errorf("%w: %q", ErrInvalidRateLimitValue, value)
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || duration <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("%w: %q", ErrInvalidRateLimitValue, value)
+	}
+
+	return RateLimitPolicy{Limit: limit, Window: duration}, nil
+}
+
+// IsEnabled はポリシーが有効（制限あり）かどうかを返します。
+func (p RateLimitPolicy) IsEnabled() bool {
+	return p.Limit > 0 && p.Window > 0
+}
+
+// LockoutPolicy はサインイン失敗時の段階的なロックアウト設定です。
+// MaxFailures 回失敗するとロックされ、以降は失敗するたびにロック時間が倍になります（上限 MaxDuration）。
+// 最後の失敗から ResetAfter が経過した失敗回数は数え直します（0の場合は数え直しません）。
+type LockoutPolicy struct {
+	MaxFailures  int
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+	ResetAfter   time.Duration
+}
+
+// DefaultLockoutPolicy は既定のロックアウト設定を返します。
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		MaxFailures:  5,
+		BaseDuration: time.Minute,
+		MaxDuration:  time.Hour,
+		ResetAfter:   24 * time.Hour,
+	}
+}
+
+// LockDuration は累計失敗回数に応じたロック時間を返します。
+func (p LockoutPolicy) LockDuration(failures int) time.Duration {
+	if p.MaxFailures <= 0 || failures < p.MaxFailures {
+		return 0
+	}
+
+	duration := p.BaseDuration
+	for i := p.MaxFailures; i < failures; i++ {
+		duration *= 2
+		if p.MaxDuration > 0 && duration >= p.MaxDuration {
+			return p.MaxDuration
+		}
+	}
+	if p.MaxDuration > 0 && duration > p.MaxDuration {
+		return p.MaxDuration
+	}
+	return duration
+}
+
+// LoginAttempt はアカウント単位のサインイン失敗状況を表します。
+// ExpiresAt を過ぎた失敗履歴は不要のため、ストアから削除できます。
+type LoginAttempt struct {
+	Key          string
+	Failures     int
+	LockedUntil  time.Time
+	LastFailedAt time.Time
+	ExpiresAt    time.Time
+}
+
+// NewLoginAttempt は失敗履歴のない LoginAttempt を作成します。
+func NewLoginAttempt(key string) *LoginAttempt {
+	return &LoginAttempt{Key: key}
+}
+
+// IsLocked は指定時刻にロック中かどうかを返します。
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return now.Before(a.LockedUntil)
+}
+
+// RegisterFailure はサインイン失敗を記録し、必要に応じてロック期限を延長します。
+// ロック中でなく、最後の失敗から policy.ResetAfter が経過している場合は失敗回数を数え直します。
+func (a *LoginAttempt) RegisterFailure(now time.Time, policy LockoutPolicy) {
+	if policy.ResetAfter > 0 && !a.IsLocked(now) && !a.LastFailedAt.IsZero() && now.Sub(a.LastFailedAt) >= policy.ResetAfter {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailedAt = now
+	if d := policy.LockDuration(a.Failures); d > 0 {
+		a.LockedUntil = now.Add(d)
+	}
+
+	// ロックが解除され、失敗回数を数え直すまでは履歴を残す
+	a.ExpiresAt = a.LockedUntil
+	if policy.ResetAfter > 0 && now.Add(policy.ResetAfter).After(a.ExpiresAt) {
+		a.ExpiresAt = now.Add(policy.ResetAfter)
+	}
+}
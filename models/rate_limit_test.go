@@ -0,0 +1,96 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimitPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected RateLimitPolicy
+		hasError bool
+	}{
+		{name: "回数と期間", value: "20/1m", expected: RateLimitPolicy{Limit: 20, Window: time.Minute}},
+		{name: "空白を含む", value: " 5 / 30s ", expected: RateLimitPolicy{Limit: 5, Window: 30 * time.Second}},
+		{name: "空文字は無効化", value: "", expected: RateLimitPolicy{}},
+		{name: "offは無効化", value: "off", expected: RateLimitPolicy{}},
+		{name: "区切りなし", value: "20", hasError: true},
+		{name: "回数が数値でない", value: "x/1m", hasError: true},
+		{name: "期間が不正", value: "20/abc", hasError: true},
+		{name: "期間が0", value: "20/0s", hasError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := ParseRateLimitPolicy(tc.value)
+			if tc.hasError {
+				assert.ErrorIs(t, err, ErrInvalidRateLimitValue)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, policy)
+		})
+	}
+}
+
+func TestRateLimitPolicy_IsEnabled(t *testing.T) {
+	assert.True(t, RateLimitPolicy{Limit: 1, Window: time.Second}.IsEnabled())
+	assert.False(t, RateLimitPolicy{}.IsEnabled())
+	assert.False(t, RateLimitPolicy{Limit: 1}.IsEnabled())
+}
+
+func TestLockoutPolicy_LockDuration(t *testing.T) {
+	policy := LockoutPolicy{MaxFailures: 3, BaseDuration: time.Minute, MaxDuration: 5 * time.Minute}
+
+	assert.Equal(t, time.Duration(0), policy.LockDuration(2), "閾値未満ではロックしない")
+	assert.Equal(t, time.Minute, policy.LockDuration(3))
+	assert.Equal(t, 2*time.Minute, policy.LockDuration(4))
+	assert.Equal(t, 4*time.Minute, policy.LockDuration(5))
+	assert.Equal(t, 5*time.Minute, policy.LockDuration(6), "上限で頭打ちになる")
+	assert.Equal(t, 5*time.Minute, policy.LockDuration(100))
+}
+
+func TestLoginAttempt_RegisterFailure(t *testing.T) {
+	policy := LockoutPolicy{MaxFailures: 2, BaseDuration: time.Minute, MaxDuration: time.Hour}
+	now := time.Now().UTC()
+	attempt := NewLoginAttempt("manager@example.com")
+
+	attempt.RegisterFailure(now, policy)
+	assert.Equal(t, 1, attempt.Failures)
+	assert.False(t, attempt.IsLocked(now))
+
+	attempt.RegisterFailure(now, policy)
+	assert.True(t, attempt.IsLocked(now))
+	assert.Equal(t, now.Add(time.Minute), attempt.LockedUntil)
+	assert.False(t, attempt.IsLocked(now.Add(time.Minute)), "ロック期限を過ぎれば解除される")
+
+	attempt.RegisterFailure(now, policy)
+	assert.Equal(t, now.Add(2*time.Minute), attempt.LockedUntil, "失敗が続くとロック時間が延びる")
+}
+
+func TestLoginAttempt_RegisterFailureResetAfter(t *testing.T) {
+	policy := LockoutPolicy{MaxFailures: 2, BaseDuration: time.Minute, MaxDuration: time.Hour, ResetAfter: time.Hour}
+	now := time.Now().UTC()
+	attempt := NewLoginAttempt("manager@example.com")
+
+	attempt.RegisterFailure(now, policy)
+	assert.Equal(t, now.Add(time.Hour), attempt.ExpiresAt)
+
+	later := now.Add(2 * time.Hour)
+	attempt.RegisterFailure(later, policy)
+	assert.Equal(t, 1, attempt.Failures, "期間を過ぎた失敗は数え直す")
+	assert.False(t, attempt.IsLocked(later))
+}
+
+func TestAccountLockedError_RetryAfter(t *testing.T) {
+	now := time.Now().UTC()
+	err := &AccountLockedError{Until: now.Add(90 * time.Second)}
+
+	assert.Equal(t, 90*time.Second, err.RetryAfter(now))
+	assert.Equal(t, time.Duration(0), err.RetryAfter(now.Add(time.Hour)))
+	assert.Contains(t, err.Error(), "ロック")
+}
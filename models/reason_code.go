@@ -0,0 +1,283 @@
+package models
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// --- キャンセル・辞退・保留・明細の取り消しの理由コード ---
+
+// ReasonKind は理由コードを指定する操作の種類です。
+type ReasonKind string
+
+const (
+	// ReasonCancel は注文のキャンセル（`Cancelled` への遷移）です。
+	ReasonCancel ReasonKind = "cancel"
+	// ReasonDecline は店舗による注文の辞退（`Declined` への遷移）です。
+	ReasonDecline ReasonKind = "decline"
+	// ReasonHold は注文の保留（`OnHold` への遷移）です。
+	ReasonHold ReasonKind = "hold"
+	// ReasonVoid は調理前の明細の取り消しです。
+	ReasonVoid ReasonKind = "void"
+)
+
+// ReasonCodeExpired は有効期限切れによる自動のキャンセル・辞退の理由コードです。
+// システムが記録する理由のため、店舗の理由コードの一覧には含めず、スタッフは指定できません。
+const ReasonCodeExpired = "expired"
+
+var (
+	ErrReasonCodeRequired = errors.New("理由コードを指定してください")
+	ErrUnknownReasonCode  = errors.New("理由コードが登録されていません")
+	ErrInvalidReasonCode  = errors.New("理由コードの設定が不正です")
+)
+
+// reasonKinds は理由コードの種類を表示する順に並べたものです。
+var reasonKinds = []ReasonKind{ReasonCancel, ReasonDecline, ReasonHold, ReasonVoid}
+
+// reasonCodePattern は理由コードの書式（英小文字・数字・アンダースコア、64文字以内）です。
+var reasonCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// IsValid は定義済みの種類かどうかを返します。
+func (k ReasonKind) IsValid() bool {
+	switch k {
+	case ReasonCancel, ReasonDecline, ReasonHold, ReasonVoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReasonKindFor はステータスへの遷移に理由コードが必要な場合、その種類を返します。
+func ReasonKindFor(status Status) (ReasonKind, bool) {
+	switch status {
+	case StatusCancelled:
+		return ReasonCancel, true
+	case StatusDeclined:
+		return ReasonDecline, true
+	case StatusOnHold:
+		return ReasonHold, true
+	default:
+		return "", false
+	}
+}
+
+// ReasonCode は店舗が設定する理由コードです。同じコードを複数の種類で使用できます。
+// 使用しなくなったコードは、過去の記録を集計できるよう削除せず Active を false にします。
+type ReasonCode struct {
+	Kind   ReasonKind `json:"kind"`
+	Code   string     `json:"code"`
+	Label  string     `json:"label"`
+	Active bool       `json:"active"`
+}
+
+// DefaultReasonCodes は店舗が理由コードを設定していない場合の既定の一覧を返します。
+func DefaultReasonCodes() []ReasonCode {
+	return []ReasonCode{
+		{Kind: ReasonCancel, Code: "customer_request", Label: "お客様の都合", Active: true},
+		{Kind: ReasonCancel, Code: "out_of_stock", Label: "品切れ", Active: true},
+		{Kind: ReasonCancel, Code: "duplicate_order", Label: "重複した注文", Active: true},
+		{Kind: ReasonCancel, Code: "long_wait", Label: "提供の遅れ", Active: true},
+		{Kind: ReasonDecline, Code: "out_of_stock", Label: "品切れ", Active: true},
+		{Kind: ReasonDecline, Code: "last_order_passed", Label: "ラストオーダー後", Active: true},
+		{Kind: ReasonDecline, Code: "kitchen_busy", Label: "厨房の混雑", Active: true},
+		{Kind: ReasonHold, Code: "awaiting_customer", Label: "お客様の確認待ち", Active: true},
+		{Kind: ReasonHold, Code: "kitchen_busy", Label: "厨房の混雑", Active: true},
+		{Kind: ReasonVoid, Code: "customer_request", Label: "お客様の都合", Active: true},
+		{Kind: ReasonVoid, Code: "out_of_stock", Label: "品切れ", Active: true},
+		{Kind: ReasonVoid, Code: "order_mistake", Label: "注文間違い", Active: true},
+	}
+}
+
+// NormalizeReasonCodes は理由コードの一覧を検証し、ラベルの前後の空白を取り除きます。
+func NormalizeReasonCodes(codes []ReasonCode) ([]ReasonCode, error) {
+	normalized := make([]ReasonCode, len(codes))
+	seen := make(map[ReasonKind]map[string]bool)
+	for i, code := range codes {
+		code.Code = strings.TrimSpace(code.Code)
+		code.Label = strings.TrimSpace(code.Label)
+		switch {
+		case !code.Kind.IsValid():
+			return nil, fmt.Errorf("%w: 種類 %q (codes[%d])", ErrInvalidReasonCode, code.Kind, i)
+		case !reasonCodePattern.MatchString(code.Code) || code.Code == ReasonCodeExpired:
+			return nil, fmt.Errorf("%w: コード %q (codes[%d])", ErrInvalidReasonCode, code.Code, i)
+		case code.Label == "":
+			return nil, fmt.Errorf("%w: ラベルを指定してください (codes[%d])", ErrInvalidReasonCode, i)
+		case seen[code.Kind][code.Code]:
+			return nil, fmt.Errorf("%w: %s のコード %q が重複しています (codes[%d])", ErrInvalidReasonCode, code.Kind, code.Code, i)
+		}
+		if seen[code.Kind] == nil {
+			seen[code.Kind] = make(map[string]bool)
+		}
+		seen[code.Kind][code.Code] = true
+		normalized[i] = code
+	}
+	return normalized, nil
+}
+
+// ReasonCodeCatalog は店舗の理由コードの一覧を返します。未設定の場合は既定の一覧です。
+func (s *Store) ReasonCodeCatalog() []ReasonCode {
+	if len(s.ReasonCodes) == 0 {
+		return DefaultReasonCodes()
+	}
+	return s.ReasonCodes
+}
+
+// LookupReasonCode は店舗の理由コードの一覧から、種類とコードに一致する有効な理由コードを返します。
+func (s *Store) LookupReasonCode(kind ReasonKind, code string) (ReasonCode, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ReasonCode{}, fmt.Errorf("%w: %s", ErrReasonCodeRequired, kind)
+	}
+	for _, r := range s.ReasonCodeCatalog() {
+		if r.Kind == kind && r.Code == code && r.Active {
+			return r, nil
+		}
+	}
+	return ReasonCode{}, fmt.Errorf("%w: %s/%s", ErrUnknownReasonCode, kind, code)
+}
+
+// reasonText は履歴に記録する理由の文章です。補足（自由記述）がない場合は理由コードのラベルを使用します。
+func reasonText(reason ReasonCode, note string) string {
+	if note = strings.TrimSpace(note); note != "" {
+		return note
+	}
+	return reason.Label
+}
+
+// --- Session の理由コード付きの操作 ---
+
+// UpdateStatusWithReason は理由コードと補足を指定して注文のステータスを更新し、遷移の履歴に記録します。
+func (s *Session) UpdateStatusWithReason(newStatus Status, actor string, reason ReasonCode, note string) error {
+	if err := s.UpdateStatusBy(newStatus, actor, reasonText(reason, note)); err != nil {
+		return err
+	}
+	s.StatusHistory[len(s.StatusHistory)-1].ReasonCode = reason.Code
+	return nil
+}
+
+// VoidLineWithReason は理由コードと補足を指定して調理前の明細を取り消します。
+func (s *Session) VoidLineWithReason(lineID string, reason ReasonCode, note, actor string, now time.Time) error {
+	recorded := len(s.StatusHistory)
+	if err := s.VoidLine(lineID, reasonText(reason, note), actor, now); err != nil {
+		return err
+	}
+	i, _ := s.findLine(lineID)
+	s.Items[i].VoidReasonCode = reason.Code
+	// 全ての明細を取り消して注文をキャンセルした場合は、キャンセルの記録にも理由コードを残す
+	for j := recorded; j < len(s.StatusHistory); j++ {
+		if s.StatusHistory[j].To == StatusCancelled {
+			s.StatusHistory[j].ReasonCode = reason.Code
+		}
+	}
+	return nil
+}
+
+// --- 理由の集計 ---
+
+// ReasonTotal は理由コードごとの件数と金額です。
+// 金額は、キャンセル・辞退は注文の合計金額、明細の取り消しは取り消した明細の金額で、保留は0です。
+type ReasonTotal struct {
+	Kind   ReasonKind `json:"kind"`
+	Code   string     `json:"code"`
+	Label  string     `json:"label"`
+	Count  int        `json:"count"`
+	Amount Money      `json:"amount"`
+}
+
+// ReasonReport は期間中のキャンセル・辞退・保留・明細の取り消しの理由の集計です。
+// 理由コードのない記録（理由コードの導入前の記録など）は Code が空の行に集計します。
+type ReasonReport struct {
+	StoreID     string        `json:"store_id"`
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	Totals      []ReasonTotal `json:"totals"`
+}
+
+// NewReasonReport は店舗の注文の遷移の履歴と取り消した明細から、期間中（終了日時は含まない）の理由を集計します。
+func NewReasonReport(store *Store, sessions []*Session, start, end time.Time) (*ReasonReport, error) {
+	within := func(t time.Time) bool { return !t.Before(start) && t.Before(end) }
+	labels := map[ReasonKind]map[string]string{}
+	for _, r := range store.ReasonCodeCatalog() {
+		if labels[r.Kind] == nil {
+			labels[r.Kind] = map[string]string{}
+		}
+		labels[r.Kind][r.Code] = r.Label
+	}
+
+	type key struct {
+		kind ReasonKind
+		code string
+	}
+	totals := map[key]*ReasonTotal{}
+	add := func(kind ReasonKind, code string, amount Money) {
+		k := key{kind, code}
+		if totals[k] == nil {
+			label, ok := labels[kind][code]
+			if !ok {
+				// 全ての明細の取り消しによるキャンセルなど、他の種類の理由コードで記録した場合
+				for _, k := range reasonKinds {
+					if l, ok := labels[k][code]; ok {
+						label = l
+						break
+					}
+				}
+			}
+			switch {
+			case code == ReasonCodeExpired:
+				label = "有効期限切れ"
+			case code == "":
+				label = "理由コードなし"
+			}
+			totals[k] = &ReasonTotal{Kind: kind, Code: code, Label: label, Amount: Zero(amount.currency())}
+		}
+		totals[k].Count++
+		totals[k].Amount.Amount += amount.Amount
+	}
+
+	for _, s := range sessions {
+		if s.StoreID != store.ID {
+			continue
+		}
+		for _, change := range s.Timeline() {
+			kind, ok := ReasonKindFor(change.To)
+			if !ok || !within(change.At) {
+				continue
+			}
+			amount := s.TotalAmount
+			if kind == ReasonHold {
+				amount = Zero(s.Currency())
+			}
+			add(kind, change.ReasonCode, amount)
+		}
+		for _, line := range s.Items {
+			if !line.IsVoided() || !within(line.VoidedAt) {
+				continue
+			}
+			gross, err := line.Gross()
+			if err != nil {
+				return nil, err
+			}
+			add(ReasonVoid, line.VoidReasonCode, gross)
+		}
+	}
+
+	report := &ReasonReport{StoreID: store.ID, PeriodStart: start, PeriodEnd: end, Totals: []ReasonTotal{}}
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	slices.SortFunc(report.Totals, func(a, b ReasonTotal) int {
+		if c := cmp.Compare(slices.Index(reasonKinds, a.Kind), slices.Index(reasonKinds, b.Kind)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Code, b.Code)
+	})
+	return report, nil
+}
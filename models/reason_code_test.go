@@ -0,0 +1,107 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeReasonCodes(t *testing.T) {
+	codes, err := NormalizeReasonCodes([]ReasonCode{
+		{Kind: ReasonCancel, Code: "out_of_stock", Label: " 品切れ ", Active: true},
+		{Kind: ReasonVoid, Code: "out_of_stock", Label: "品切れ", Active: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "品切れ", codes[0].Label)
+
+	invalid := [][]ReasonCode{
+		{{Kind: "refund", Code: "other", Label: "その他"}},
+		{{Kind: ReasonCancel, Code: "Out Of Stock", Label: "品切れ"}},
+		{{Kind: ReasonCancel, Code: ReasonCodeExpired, Label: "期限切れ"}},
+		{{Kind: ReasonCancel, Code: "other", Label: " "}},
+		{{Kind: ReasonHold, Code: "busy", Label: "混雑"}, {Kind: ReasonHold, Code: "busy", Label: "厨房の混雑"}},
+	}
+	for _, c := range invalid {
+		_, err := NormalizeReasonCodes(c)
+		assert.ErrorIs(t, err, ErrInvalidReasonCode)
+	}
+}
+
+func TestStore_LookupReasonCode(t *testing.T) {
+	store := &Store{}
+	reason, err := store.LookupReasonCode(ReasonDecline, "kitchen_busy")
+	require.NoError(t, err, "未設定の店舗は既定の一覧を使用")
+	assert.Equal(t, "厨房の混雑", reason.Label)
+
+	_, err = store.LookupReasonCode(ReasonDecline, "")
+	assert.ErrorIs(t, err, ErrReasonCodeRequired)
+	_, err = store.LookupReasonCode(ReasonDecline, "customer_request")
+	assert.ErrorIs(t, err, ErrUnknownReasonCode, "他の種類のコード")
+
+	store.ReasonCodes = []ReasonCode{{Kind: ReasonCancel, Code: "closing", Label: "閉店", Active: false}}
+	_, err = store.LookupReasonCode(ReasonCancel, "closing")
+	assert.ErrorIs(t, err, ErrUnknownReasonCode, "無効にしたコード")
+}
+
+func TestSession_UpdateStatusWithReason(t *testing.T) {
+	s := newTestSession(t)
+	reason := ReasonCode{Kind: ReasonCancel, Code: "customer_request", Label: "お客様の都合", Active: true}
+
+	require.NoError(t, s.UpdateStatusWithReason(StatusCancelled, "manager:a@example.com", reason, ""))
+	last := s.StatusHistory[len(s.StatusHistory)-1]
+	assert.Equal(t, "customer_request", last.ReasonCode)
+	assert.Equal(t, "お客様の都合", last.Reason, "補足がない場合はラベル")
+
+	s = newTestSession(t)
+	require.NoError(t, s.UpdateStatusWithReason(StatusCancelled, "manager:a@example.com", reason, "電話でキャンセル"))
+	assert.Equal(t, "電話でキャンセル", s.StatusHistory[len(s.StatusHistory)-1].Reason)
+}
+
+func TestSession_VoidLineWithReason(t *testing.T) {
+	s := newTestSession(t)
+	reason := ReasonCode{Kind: ReasonVoid, Code: "out_of_stock", Label: "品切れ", Active: true}
+	now := s.CreatedAt.Add(time.Minute)
+
+	require.NoError(t, s.VoidLineWithReason(s.Items[1].LineID, reason, "", "manager:a@example.com", now))
+	assert.Equal(t, "out_of_stock", s.Items[1].VoidReasonCode)
+	assert.Equal(t, "品切れ", s.Items[1].VoidReason)
+	assert.Equal(t, StatusCreated, s.Status)
+
+	require.NoError(t, s.VoidLineWithReason(s.Items[0].LineID, reason, "", "manager:a@example.com", now))
+	assert.Equal(t, StatusCancelled, s.Status, "全ての明細の取り消しでキャンセル")
+	assert.Equal(t, "out_of_stock", s.StatusHistory[len(s.StatusHistory)-1].ReasonCode)
+}
+
+func TestNewReasonReport(t *testing.T) {
+	start, end, err := BusinessDayRange("2025-04-01")
+	require.NoError(t, err)
+	store := &Store{ID: "store_123"}
+
+	cancelled := newTestSession(t)
+	reason, err := store.LookupReasonCode(ReasonCancel, "customer_request")
+	require.NoError(t, err)
+	require.NoError(t, cancelled.UpdateStatusWithReason(StatusCancelled, "", reason, ""))
+	cancelled.StatusHistory[len(cancelled.StatusHistory)-1].At = start.Add(time.Hour)
+
+	voided := newTestSession(t)
+	reason, err = store.LookupReasonCode(ReasonVoid, "out_of_stock")
+	require.NoError(t, err)
+	require.NoError(t, voided.VoidLineWithReason(voided.Items[0].LineID, reason, "", "", start.Add(2*time.Hour)))
+
+	legacy := newTestSession(t)
+	require.NoError(t, legacy.UpdateStatusBy(StatusCancelled, "", "理由の記録なし"))
+	legacy.StatusHistory[len(legacy.StatusHistory)-1].At = start.Add(3 * time.Hour)
+
+	outside := newTestSession(t)
+	require.NoError(t, outside.UpdateStatusWithReason(StatusCancelled, "", ReasonCode{Code: "customer_request"}, ""))
+	outside.StatusHistory[len(outside.StatusHistory)-1].At = end
+
+	report, err := NewReasonReport(store, []*Session{cancelled, voided, legacy, outside}, start, end)
+	require.NoError(t, err)
+	require.Len(t, report.Totals, 3)
+	assert.Equal(t, ReasonTotal{Kind: ReasonCancel, Code: "", Label: "理由コードなし", Count: 1, Amount: Yen(250)}, report.Totals[0])
+	assert.Equal(t, ReasonTotal{Kind: ReasonCancel, Code: "customer_request", Label: "お客様の都合", Count: 1, Amount: Yen(250)}, report.Totals[1])
+	assert.Equal(t, ReasonTotal{Kind: ReasonVoid, Code: "out_of_stock", Label: "品切れ", Count: 1, Amount: Yen(200)}, report.Totals[2])
+}
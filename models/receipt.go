@@ -0,0 +1,222 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReceiptPrefix は領収書IDのプレフィックスです。
+const ReceiptPrefix = "receipt_"
+
+// invoiceRegistrationDigits は適格請求書発行事業者の登録番号の「T」に続く桁数です。
+const invoiceRegistrationDigits = 13
+
+var (
+	ErrInvoiceRegistrationNumberRequired = errors.New("適格請求書発行事業者の登録番号が設定されていません")
+	ErrInvalidInvoiceRegistrationNumber  = errors.New("登録番号は「T」と13桁の数字で指定してください")
+	ErrRecipientNameRequired             = errors.New("宛名を指定してください")
+	ErrSessionNotCompleted               = errors.New("会計が完了していない注文の領収書は発行できません")
+	ErrReceiptNotFound                   = errors.New("領収書が見つかりません")
+	ErrReceiptAlreadyIssued              = errors.New("この注文の領収書はすでに発行されています。再発行してください")
+)
+
+// NormalizeInvoiceRegistrationNumber は登録番号の表記ゆれ（小文字の t、ハイフンや空白による区切り）を取り除きます。
+func NormalizeInvoiceRegistrationNumber(number string) string {
+	number = strings.TrimSpace(number)
+	number = strings.NewReplacer("-", "", " ", "", "　", "").Replace(number)
+	if strings.HasPrefix(number, "t") {
+		number = "T" + number[1:]
+	}
+	return number
+}
+
+// ValidateInvoiceRegistrationNumber は登録番号が「T」と13桁の数字であることを検証します。
+func ValidateInvoiceRegistrationNumber(number string) error {
+	if number == "" {
+		return ErrInvoiceRegistrationNumberRequired
+	}
+	if len(number) != 1+invoiceRegistrationDigits || number[0] != 'T' {
+		return fmt.Errorf("%w: %q", ErrInvalidInvoiceRegistrationNumber, number)
+	}
+	for _, r := range number[1:] {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("%w: %q", ErrInvalidInvoiceRegistrationNumber, number)
+		}
+	}
+	return nil
+}
+
+// ReceiptIssuer は領収書に記載する発行事業者の情報です。
+type ReceiptIssuer struct {
+	StoreID            string `json:"store_id"`
+	Name               string `json:"name"`
+	RegistrationNumber string `json:"registration_number"`
+	Address            string `json:"address"`
+	Phone              string `json:"phone"`
+}
+
+// ReceiptLine は領収書の明細行です。
+// Reduced が true の行は軽減税率の対象で、印字時に「※」を付けます。
+// チャージ（お通し代、サービス料など）の行は ProductID が空で、Name にチャージの名称を設定します。
+type ReceiptLine struct {
+	ProductID string  `json:"product_id,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice Money   `json:"unit_price"`
+	Amount    Money   `json:"amount"`
+	TaxRate   TaxRate `json:"tax_rate"`
+	Reduced   bool    `json:"reduced"`
+}
+
+// Receipt は適格請求書（インボイス）の記載事項を満たす領収書です。
+// 発行後は内容を変更せず、再発行した場合は ReissueCount と LastReissuedAt のみを更新します。
+type Receipt struct {
+	ID string `json:"id"`
+	// Number は店舗ごとの連番です。
+	Number    int64  `json:"number"`
+	StoreID   string `json:"store_id"`
+	SessionID string `json:"session_id"`
+
+	Issuer        ReceiptIssuer `json:"issuer"`
+	RecipientName string        `json:"recipient_name"`
+
+	// TransactionAt は取引年月日（注文日時）です。
+	TransactionAt time.Time     `json:"transaction_at"`
+	DiningOption  DiningOption  `json:"dining_option"`
+	PriceMode     PriceMode     `json:"price_mode"`
+	Lines         []ReceiptLine `json:"lines"`
+	Taxes         []TaxLine     `json:"taxes"`
+	TaxTotal      Money         `json:"tax_total"`
+	Total         Money         `json:"total"`
+
+	ReissueCount   int       `json:"reissue_count"`
+	LastReissuedAt time.Time `json:"last_reissued_at,omitempty"`
+
+	IssuedAt  time.Time `json:"issued_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReceiptIDFor は注文の領収書のIDを返します。
+// 1つの注文につき領収書は1件のため、注文IDから決まるIDで作成し、同時に発行しても重複しないようにします。
+func ReceiptIDFor(sessionID string) string {
+	return ReceiptPrefix + sessionID
+}
+
+// NewReceipt は会計が完了した注文から領収書を作成します。
+// 明細と税率ごとの内訳は注文時点で計算済みの値をそのまま使用し、再計算はしません。
+func NewReceipt(store *Store, session *Session, number int64, recipientName string, now time.Time) (*Receipt, error) {
+	if session.Status != StatusCompleted {
+		return nil, fmt.Errorf("%w: 現在のステータスは '%s'", ErrSessionNotCompleted, session.Status)
+	}
+	if err := ValidateInvoiceRegistrationNumber(store.InvoiceRegistrationNumber); err != nil {
+		return nil, err
+	}
+	recipientName = strings.TrimSpace(recipientName)
+	if recipientName == "" {
+		return nil, ErrRecipientNameRequired
+	}
+
+	lines := make([]ReceiptLine, 0, len(session.Items)+len(session.Charges))
+	for _, item := range session.Items {
+		// 取り消した明細は請求していないため印字しない
+		if item.IsVoided() {
+			continue
+		}
+		rate, err := TaxRateFor(item.TaxCategory, session.DiningOption, session.taxPoint())
+		if err != nil {
+			return nil, err
+		}
+		subtotal, err := item.Subtotal()
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, ReceiptLine{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			Amount:    subtotal,
+			TaxRate:   rate,
+			Reduced:   rate.Code == TaxRateReduced,
+		})
+	}
+	// 免除したチャージは請求していないため印字しない
+	for _, charge := range session.Charges {
+		if charge.Waived {
+			continue
+		}
+		rate, err := TaxRateFor(charge.TaxCategory, session.DiningOption, session.taxPoint())
+		if err != nil {
+			return nil, err
+		}
+		unitPrice := charge.Amount
+		if charge.Quantity > 1 {
+			unitPrice = NewMoney(charge.Value, charge.Amount.currency())
+		}
+		lines = append(lines, ReceiptLine{
+			Name:      charge.Name,
+			Quantity:  charge.Quantity,
+			UnitPrice: unitPrice,
+			Amount:    charge.Amount,
+			TaxRate:   rate,
+			Reduced:   rate.Code == TaxRateReduced,
+		})
+	}
+
+	now = now.UTC()
+	return &Receipt{
+		ID:        ReceiptIDFor(session.ID),
+		Number:    number,
+		StoreID:   store.ID,
+		SessionID: session.ID,
+		Issuer: ReceiptIssuer{
+			StoreID:            store.ID,
+			Name:               store.Name,
+			RegistrationNumber: store.InvoiceRegistrationNumber,
+			Address:            store.Address,
+			Phone:              store.Phone,
+		},
+		RecipientName: recipientName,
+		TransactionAt: session.taxPoint(),
+		DiningOption:  session.DiningOption,
+		PriceMode:     session.TaxPolicy.WithDefaults().PriceMode,
+		Lines:         lines,
+		Taxes:         session.Taxes,
+		TaxTotal:      session.TaxTotal,
+		Total:         session.TotalAmount,
+		IssuedAt:      now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Reissue は領収書を再発行したことを記録します。
+// 再発行した領収書には「再発行」と印字し、二重計上を防ぎます。
+func (r *Receipt) Reissue(now time.Time) {
+	now = now.UTC()
+	r.ReissueCount++
+	r.LastReissuedAt = now
+	r.UpdatedAt = now
+}
+
+// IsReissue は再発行された領収書かどうかを返します。
+func (r *Receipt) IsReissue() bool {
+	return r.ReissueCount > 0
+}
+
+// DisplayNumber は印字用の領収書番号を返します。
+func (r *Receipt) DisplayNumber() string {
+	return fmt.Sprintf("%08d", r.Number)
+}
+
+// HasReducedRate は軽減税率の対象となる明細を含むかどうかを返します。
+func (r *Receipt) HasReducedRate() bool {
+	for _, line := range r.Lines {
+		if line.Reduced {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// ReceiptTextWidth はサーマルプリンタ（58mm幅）の1行あたりの半角文字数です。
+const ReceiptTextWidth = 32
+
+// minReceiptTextWidth より狭い幅は金額が収まらないため、この幅に切り上げます。
+const minReceiptTextWidth = 24
+
+// receiptTimeLayout は領収書に印字する日時の書式です。
+const receiptTimeLayout = "2006/01/02 15:04"
+
+// RenderText はサーマルプリンタ向けのプレーンテキストの領収書を返します。
+// width は半角換算の1行の文字数で、全角文字は2文字として数えます。0以下の場合は ReceiptTextWidth を使用します。
+func (r *Receipt) RenderText(width int) string {
+	if width <= 0 {
+		width = ReceiptTextWidth
+	}
+	width = max(width, minReceiptTextWidth)
+	rule := strings.Repeat("-", width)
+
+	var lines []string
+	add := func(s ...string) { lines = append(lines, s...) }
+
+	add(centerText("領収書", width))
+	if r.IsReissue() {
+		add(centerText("【再発行】", width))
+	}
+	add("", r.RecipientName+" 様", "")
+
+	add(rule, r.Issuer.Name)
+	if r.Issuer.Address != "" {
+		add(r.Issuer.Address)
+	}
+	if r.Issuer.Phone != "" {
+		add("TEL " + r.Issuer.Phone)
+	}
+	add("登録番号 " + r.Issuer.RegistrationNumber)
+
+	add(rule,
+		"No. "+r.DisplayNumber(),
+		"取引日 "+r.TransactionAt.In(jst).Format(receiptTimeLayout),
+	)
+	if r.IsReissue() {
+		add("再発行日 " + r.LastReissuedAt.In(jst).Format(receiptTimeLayout))
+	} else {
+		add("発行日 " + r.IssuedAt.In(jst).Format(receiptTimeLayout))
+	}
+	add(diningOptionLabel(r.DiningOption))
+
+	add(rule)
+	for _, line := range r.Lines {
+		name := line.ProductID
+		if line.Name != "" {
+			name = line.Name
+		}
+		if line.Reduced {
+			name += " ※"
+		}
+		add(name, justifyText(fmt.Sprintf("  %s x %d", line.UnitPrice.Format(), line.Quantity), line.Amount.Format(), width))
+	}
+
+	add(rule, justifyText("合計", r.Total.Format(), width))
+	taxLabel := "内消費税"
+	if r.PriceMode == PriceModeExclusive {
+		taxLabel = "消費税"
+	}
+	for _, tax := range r.Taxes {
+		if tax.Rate.Code == TaxRateExempt {
+			add(justifyText("非課税対象", tax.Gross.Format(), width))
+			continue
+		}
+		add(
+			justifyText(fmt.Sprintf("%d%%対象", tax.Rate.Percent), tax.Gross.Format(), width),
+			justifyText("  "+taxLabel, tax.Tax.Format(), width),
+		)
+	}
+
+	add(rule)
+	if r.HasReducedRate() {
+		add("※は軽減税率対象商品です")
+	}
+	add("上記正に領収いたしました")
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// diningOptionLabel は店内飲食・持ち帰りの印字用ラベルを返します。
+func diningOptionLabel(dining DiningOption) string {
+	if dining == DiningTakeout {
+		return "持ち帰り"
+	}
+	return "店内飲食"
+}
+
+// displayWidth は文字列の表示幅を半角換算で返します。
+// 全角文字（CJK、かな、全角記号）を2、それ以外を1として数えます。
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if isWideRune(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+func isWideRune(r rune) bool {
+	switch {
+	case r == '※':
+		// 東アジアの曖昧幅の記号だが、日本語のプリンタでは全角で印字される
+		return true
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6:
+		return true
+	default:
+		return false
+	}
+}
+
+// centerText は文字列を中央寄せします。
+func centerText(s string, width int) string {
+	pad := (width - displayWidth(s)) / 2
+	if pad <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", pad) + s
+}
+
+// justifyText は left を左寄せ、right を右寄せにして1行にします。収まらない場合も最低1文字の空白を空けます。
+func justifyText(left, right string, width int) string {
+	pad := max(width-displayWidth(left)-displayWidth(right), 1)
+	return left + strings.Repeat(" ", pad) + right
+}
+
+// PDFの印字設定（単位はポイント）
+const (
+	receiptPDFFontSize = 10.0
+	receiptPDFLeading  = 14.0
+	receiptPDFMargin   = 24.0
+)
+
+// RenderPDF は領収書をPDFで返します。
+// 印字内容はサーマルプリンタ向けのテキストと同じで、等幅の1ページに描画します。
+// 日本語の表示にはPDFビューアが標準で持つ Adobe-Japan1 のゴシック体（HeiseiKakuGo-W5）を使用し、フォントは埋め込みません。
+func (r *Receipt) RenderPDF() []byte {
+	text := strings.TrimRight(r.RenderText(ReceiptTextWidth), "\n")
+	lines := strings.Split(text, "\n")
+
+	pageWidth := float64(ReceiptTextWidth)*receiptPDFFontSize/2 + 2*receiptPDFMargin
+	pageHeight := float64(len(lines))*receiptPDFLeading + 2*receiptPDFMargin
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 %.0f Tf\n%.0f TL\n%.1f %.1f Td\n",
+		receiptPDFFontSize, receiptPDFLeading, receiptPDFMargin, pageHeight-receiptPDFMargin-receiptPDFFontSize)
+	for _, line := range lines {
+		fmt.Fprintf(&content, "<%s> Tj T*\n", pdfHexString(line))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.1f %.1f] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>", pageWidth, pageHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /HeiseiKakuGo-W5 /Encoding /UniJIS-UCS2-HW-H /DescendantFonts [6 0 R] >>",
+		// 半角英数字（CID 231-325）は全角の半分の幅
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /HeiseiKakuGo-W5 /CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> /FontDescriptor 7 0 R /DW 1000 /W [231 325 500] >>",
+		"<< /Type /FontDescriptor /FontName /HeiseiKakuGo-W5 /Flags 4 /FontBBox [-92 -250 1010 922] /ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>",
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+// pdfHexString は文字列を UCS-2（ビッグエンディアン）の16進文字列に変換します。
+// UniJIS-UCS2 は基本多言語面のみ対応のため、それ以外の文字は「?」に置き換えます。
+func pdfHexString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReceipt(t *testing.T) *Receipt {
+	t.Helper()
+	session := newCompletedSession(t)
+	session.IssuedAt = time.Date(2024, 4, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, session.RecalculateTotalAmount())
+
+	receipt, err := NewReceipt(newInvoiceStore(), session, 42, "株式会社テスト", time.Date(2024, 4, 1, 3, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	return receipt
+}
+
+func TestReceipt_RenderText(t *testing.T) {
+	t.Run("適格請求書の記載事項を含む", func(t *testing.T) {
+		text := newTestReceipt(t).RenderText(0)
+
+		for _, want := range []string{
+			"株式会社テスト 様",
+			"テスト食堂",
+			"登録番号 T1234567890123",
+			"No. 00000042",
+			"取引日 2024/04/01 12:00",
+			"発行日 2024/04/01 12:30",
+			"持ち帰り",
+			"bento ※",
+			"¥540 x 2",
+			"※は軽減税率対象商品です",
+		} {
+			assert.Contains(t, text, want)
+		}
+		assert.NotContains(t, text, "再発行")
+
+		lines := strings.Split(text, "\n")
+		assert.Contains(t, lines, justifyText("合計", "¥1,630", ReceiptTextWidth))
+		assert.Contains(t, lines, justifyText("10%対象", "¥550", ReceiptTextWidth))
+		assert.Contains(t, lines, justifyText("  内消費税", "¥50", ReceiptTextWidth))
+		assert.Contains(t, lines, justifyText("8%対象", "¥1,080", ReceiptTextWidth))
+		assert.Contains(t, lines, justifyText("  内消費税", "¥80", ReceiptTextWidth))
+	})
+
+	t.Run("行は指定した幅に収まる", func(t *testing.T) {
+		text := newTestReceipt(t).RenderText(ReceiptTextWidth)
+		for _, line := range strings.Split(text, "\n") {
+			assert.LessOrEqual(t, displayWidth(line), ReceiptTextWidth, line)
+		}
+	})
+
+	t.Run("再発行の表示", func(t *testing.T) {
+		receipt := newTestReceipt(t)
+		receipt.Reissue(time.Date(2024, 4, 2, 1, 0, 0, 0, time.UTC))
+
+		text := receipt.RenderText(0)
+		assert.Contains(t, text, "【再発行】")
+		assert.Contains(t, text, "再発行日 2024/04/02 10:00")
+	})
+
+	t.Run("税抜価格は消費税と表示", func(t *testing.T) {
+		receipt := newTestReceipt(t)
+		receipt.PriceMode = PriceModeExclusive
+		assert.Contains(t, receipt.RenderText(0), "  消費税")
+	})
+}
+
+func TestDisplayWidth(t *testing.T) {
+	assert.Equal(t, 5, displayWidth("abc ¥"))
+	assert.Equal(t, 6, displayWidth("領収書"))
+	assert.Equal(t, 4, displayWidth("※a "))
+	assert.Equal(t, 4, displayWidth("ｱｲｳｴ"), "半角カナは1文字")
+}
+
+func TestReceipt_RenderPDF(t *testing.T) {
+	pdf := newTestReceipt(t).RenderPDF()
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "/HeiseiKakuGo-W5")
+	// 「領収書」が UCS-2 の16進で含まれる
+	assert.Contains(t, string(pdf), pdfHexString("領収書"))
+
+	// startxref が xref テーブルの位置を指している
+	idx := bytes.LastIndex(pdf, []byte("startxref\n"))
+	require.NotEqual(t, -1, idx)
+	var offset int
+	_, err := fmt.Sscan(string(pdf[idx+len("startxref\n"):]), &offset)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf[offset:], []byte("xref\n")))
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInvoiceRegistrationNumber(t *testing.T) {
+	testCases := []struct {
+		name     string
+		number   string
+		expected error
+	}{
+		{"正常", "T1234567890123", nil},
+		{"未設定", "", ErrInvoiceRegistrationNumberRequired},
+		{"Tがない", "1234567890123", ErrInvalidInvoiceRegistrationNumber},
+		{"桁数不足", "T123456789012", ErrInvalidInvoiceRegistrationNumber},
+		{"桁数超過", "T12345678901234", ErrInvalidInvoiceRegistrationNumber},
+		{"数字以外", "T12345678901a3", ErrInvalidInvoiceRegistrationNumber},
+		{"全角数字", "T１234567890123", ErrInvalidInvoiceRegistrationNumber},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateInvoiceRegistrationNumber(tc.number)
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestNormalizeInvoiceRegistrationNumber(t *testing.T) {
+	assert.Equal(t, "T1234567890123", NormalizeInvoiceRegistrationNumber(" t1234-5678-90123 "))
+	assert.Equal(t, "T1234567890123", NormalizeInvoiceRegistrationNumber("T 1234 5678 90123"))
+}
+
+// newCompletedSession は会計済みの注文を作成するテスト用のヘルパーです。
+func newCompletedSession(t *testing.T) *Session {
+	t.Helper()
+	session, err := NewSession("store_1", "seat_1", []Order{
+		*NewOrder("bento", 2, Yen(540)).WithTaxCategory(TaxCategoryFood),
+		*NewOrder("beer", 1, Yen(550)),
+	})
+	require.NoError(t, err)
+	require.NoError(t, session.SetTaxPolicy(DefaultTaxPolicy(), DiningTakeout))
+	session.Status = StatusCompleted
+	return session
+}
+
+func newInvoiceStore() *Store {
+	return &Store{
+		ID:                        "store_1",
+		Name:                      "テスト食堂",
+		Address:                   "東京都千代田区1-1",
+		Phone:                     "03-0000-0000",
+		InvoiceRegistrationNumber: "T1234567890123",
+	}
+}
+
+func TestNewReceipt(t *testing.T) {
+	now := time.Date(2024, 4, 1, 3, 0, 0, 0, time.UTC)
+
+	t.Run("会計済みの注文から作成", func(t *testing.T) {
+		session := newCompletedSession(t)
+		receipt, err := NewReceipt(newInvoiceStore(), session, 7, " 株式会社テスト ", now)
+		require.NoError(t, err)
+
+		assert.Contains(t, receipt.ID, ReceiptPrefix)
+		assert.Equal(t, int64(7), receipt.Number)
+		assert.Equal(t, "00000007", receipt.DisplayNumber())
+		assert.Equal(t, session.ID, receipt.SessionID)
+		assert.Equal(t, "株式会社テスト", receipt.RecipientName)
+		assert.Equal(t, "T1234567890123", receipt.Issuer.RegistrationNumber)
+		assert.Equal(t, session.IssuedAt, receipt.TransactionAt)
+		assert.Equal(t, now, receipt.IssuedAt)
+		assert.False(t, receipt.IsReissue())
+
+		require.Len(t, receipt.Lines, 2)
+		assert.True(t, receipt.Lines[0].Reduced)
+		assert.Equal(t, Yen(1080), receipt.Lines[0].Amount)
+		assert.False(t, receipt.Lines[1].Reduced)
+		assert.True(t, receipt.HasReducedRate())
+
+		assert.Equal(t, session.Taxes, receipt.Taxes)
+		assert.Equal(t, Yen(1630), receipt.Total)
+		assert.Equal(t, session.TaxTotal, receipt.TaxTotal)
+	})
+
+	t.Run("チャージは明細に含め、免除したものは除く", func(t *testing.T) {
+		session := newCompletedSession(t)
+		session.Status = StatusCreated
+		session.PartySize = 2
+		rules := []ChargeRule{
+			{ID: "chrule_1", Name: "お通し", Kind: ChargePerPerson, Value: 300, Active: true},
+			{ID: "chrule_2", Name: "席料", Kind: ChargePerSeat, Value: 500, Active: true},
+		}
+		require.NoError(t, session.ApplyChargeRules(rules, nil, now))
+		_, err := session.WaiveCharge(session.Charges[1].ID, "予約特典", "manager:a@example.com", now)
+		require.NoError(t, err)
+		session.Status = StatusCompleted
+
+		receipt, err := NewReceipt(newInvoiceStore(), session, 1, "株式会社テスト", now)
+		require.NoError(t, err)
+		require.Len(t, receipt.Lines, 3)
+		assert.Equal(t, "お通し", receipt.Lines[2].Name)
+		assert.Equal(t, Yen(300), receipt.Lines[2].UnitPrice)
+		assert.Equal(t, Yen(600), receipt.Lines[2].Amount)
+		assert.Equal(t, Yen(2230), receipt.Total)
+		assert.Contains(t, receipt.RenderText(ReceiptTextWidth), "お通し")
+	})
+
+	t.Run("会計が完了していない注文はエラー", func(t *testing.T) {
+		session := newCompletedSession(t)
+		session.Status = StatusServed
+		_, err := NewReceipt(newInvoiceStore(), session, 1, "株式会社テスト", now)
+		assert.ErrorIs(t, err, ErrSessionNotCompleted)
+	})
+
+	t.Run("登録番号が未設定の店舗はエラー", func(t *testing.T) {
+		store := newInvoiceStore()
+		store.InvoiceRegistrationNumber = ""
+		_, err := NewReceipt(store, newCompletedSession(t), 1, "株式会社テスト", now)
+		assert.ErrorIs(t, err, ErrInvoiceRegistrationNumberRequired)
+	})
+
+	t.Run("宛名がない場合はエラー", func(t *testing.T) {
+		_, err := NewReceipt(newInvoiceStore(), newCompletedSession(t), 1, "  ", now)
+		assert.ErrorIs(t, err, ErrRecipientNameRequired)
+	})
+}
+
+func TestReceipt_Reissue(t *testing.T) {
+	now := time.Date(2024, 4, 1, 3, 0, 0, 0, time.UTC)
+	receipt, err := NewReceipt(newInvoiceStore(), newCompletedSession(t), 1, "株式会社テスト", now)
+	require.NoError(t, err)
+
+	later := now.Add(24 * time.Hour)
+	receipt.Reissue(later)
+	receipt.Reissue(later.Add(time.Hour))
+
+	assert.True(t, receipt.IsReissue())
+	assert.Equal(t, 2, receipt.ReissueCount)
+	assert.Equal(t, later.Add(time.Hour), receipt.LastReissuedAt)
+	assert.Equal(t, now, receipt.IssuedAt, "最初の発行日時は変わらない")
+	assert.Equal(t, int64(1), receipt.Number, "再発行しても番号は変わらない")
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RefundPrefix は返金記録のIDのプレフィックスです。
+const RefundPrefix = "refund_"
+
+var (
+	ErrRefundReasonRequired      = errors.New("返金の理由を指定してください")
+	ErrInvalidRefundAmount       = errors.New("返金額は0より大きい値を指定してください")
+	ErrRefundNotAllowed          = errors.New("現在のステータスでは返金できません")
+	ErrRefundLineAlreadyRefunded = errors.New("明細はすでに返金済みです")
+	ErrRefundAmountLinesMismatch = errors.New("明細を指定した返金では金額を指定できません")
+	ErrOrderAlreadyFullyRefunded = errors.New("注文はすでに全額返金済みです")
+	ErrRefundNotFound            = errors.New("返金記録が見つかりません")
+)
+
+// RefundRequest は返金の指定です。
+// LineIDs を指定した場合は、明細行の割引後の金額（税抜価格の店舗では税込に換算した金額）を返金します。
+// Amount を指定した場合は、明細によらない金額を返金します。
+type RefundRequest struct {
+	Amount     Money
+	LineIDs    []string
+	Reason     string
+	Actor      string
+	PaymentRef string
+}
+
+// Refund は注文の返金記録（返金台帳の1行）です。
+// 返金のたびに追加し、返金済みの合計は記録の合計から求めます。
+// Pending はオンライン決済の返金で、決済代行会社での返金が完了していない記録です。返金済みの合計には含めます。
+type Refund struct {
+	ID         string    `json:"id"`
+	Amount     Money     `json:"amount"`
+	LineIDs    []string  `json:"line_ids,omitempty"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor,omitempty"`
+	PaymentRef string    `json:"payment_ref,omitempty"`
+	Pending    bool      `json:"pending,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RefundedAmount は返金済みの合計を返します。
+func (s *Session) RefundedAmount() Money {
+	total := Zero(s.Currency())
+	for _, r := range s.Refunds {
+		total.Amount += r.Amount.Amount
+	}
+	return total
+}
+
+// RefundableAmount は返金可能な残りの金額（支払額から返金済みの合計を引いた額）を返します。
+func (s *Session) RefundableAmount() Money {
+	return NewMoney(max(s.TotalAmount.Amount-s.RefundedAmount().Amount, 0), s.Currency())
+}
+
+// MarkRefundPartially は返金を記録します。
+// 返金済みの合計が支払額に達した場合は支払い状態を `Refunded`、それ以外は `PartiallyRefunded` に更新します。
+// 支払い済みであれば、提供の進行によらず（完了後も）返金が可能です。
+func (s *Session) MarkRefundPartially(req RefundRequest, now time.Time) (*Refund, error) {
+	if req.Amount.IsNegative() {
+		return nil, ErrNegativeAmount
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrRefundReasonRequired
+	}
+	if !s.paymentStatus().CanRefund() {
+		if s.paymentStatus() == PaymentStatusRefunded {
+			return nil, ErrOrderAlreadyFullyRefunded
+		}
+		return nil, fmt.Errorf("%w: 支払い状態は '%s'", ErrRefundNotAllowed, s.paymentStatus())
+	}
+
+	amount := req.Amount
+	if len(req.LineIDs) > 0 {
+		if !amount.IsZero() {
+			return nil, ErrRefundAmountLinesMismatch
+		}
+		var err error
+		if amount, err = s.refundLinesAmount(req.LineIDs); err != nil {
+			return nil, err
+		}
+	} else if amount.currency() != s.Currency() {
+		return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, s.Currency(), amount.currency())
+	}
+	if amount.Amount <= 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+	if amount.Amount > s.RefundableAmount().Amount {
+		return nil, fmt.Errorf("%w: 返金可能な残額は %d", ErrRefundAmountExceedsTotal, s.RefundableAmount().Amount)
+	}
+
+	refund := Refund{
+		ID:         GenerateUniqueID(RefundPrefix),
+		Amount:     amount,
+		LineIDs:    req.LineIDs,
+		Reason:     reason,
+		Actor:      req.Actor,
+		PaymentRef: req.PaymentRef,
+		CreatedAt:  now.UTC(),
+	}
+	s.Refunds = append(s.Refunds, refund)
+
+	// 返金は支払い状態のみを更新し、提供の進行（完了など）は変更しない
+	status := PaymentStatusPartiallyRefunded
+	if s.RefundableAmount().IsZero() {
+		status = PaymentStatusRefunded
+	}
+	if err := s.UpdatePaymentStatus(status, req.Actor, reason); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// MarkRefundFully は返金可能な残りの金額を全て返金し、注文を全額返金済みにします。
+// req の Amount と LineIDs は使用しません。
+func (s *Session) MarkRefundFully(req RefundRequest, now time.Time) (*Refund, error) {
+	req.Amount = s.RefundableAmount()
+	req.LineIDs = nil
+	if req.Amount.IsZero() && s.paymentStatus().CanRefund() {
+		return nil, ErrOrderAlreadyFullyRefunded
+	}
+	return s.MarkRefundPartially(req, now)
+}
+
+// PendingRefund は決済代行会社での返金が完了していない返金記録を返します。ない場合は nil を返します。
+func (s *Session) PendingRefund() *Refund {
+	i := slices.IndexFunc(s.Refunds, func(r Refund) bool { return r.Pending })
+	if i < 0 {
+		return nil
+	}
+	refund := s.Refunds[i]
+	return &refund
+}
+
+// MarkRefundPending は返金記録を決済代行会社での返金待ちにします。
+func (s *Session) MarkRefundPending(refundID string) (*Refund, error) {
+	i := slices.IndexFunc(s.Refunds, func(r Refund) bool { return r.ID == refundID })
+	if i < 0 {
+		return nil, ErrRefundNotFound
+	}
+	s.Refunds[i].Pending = true
+	refund := s.Refunds[i]
+	return &refund, nil
+}
+
+// CompleteRefund は決済代行会社での返金が完了した返金記録に、決済代行会社での返金のIDを記録します。
+func (s *Session) CompleteRefund(refundID, paymentRef string) (*Refund, error) {
+	i := slices.IndexFunc(s.Refunds, func(r Refund) bool { return r.ID == refundID })
+	if i < 0 {
+		return nil, ErrRefundNotFound
+	}
+	s.Refunds[i].Pending = false
+	s.Refunds[i].PaymentRef = paymentRef
+	refund := s.Refunds[i]
+	return &refund, nil
+}
+
+// refundLinesAmount は返金する明細行の金額を、税込の金額で返します。
+// 同じ明細行を2回返金することはできません。
+func (s *Session) refundLinesAmount(lineIDs []string) (Money, error) {
+	items := make([]TaxItem, 0, len(lineIDs))
+	for i, id := range lineIDs {
+		if slices.Contains(lineIDs[:i], id) || slices.ContainsFunc(s.Refunds, func(r Refund) bool { return slices.Contains(r.LineIDs, id) }) {
+			return Money{}, fmt.Errorf("%w: %s", ErrRefundLineAlreadyRefunded, id)
+		}
+		amount, err := s.LineTotal(id)
+		if err != nil {
+			return Money{}, fmt.Errorf("%w: %s", err, id)
+		}
+		j := slices.IndexFunc(s.Items, func(item Order) bool { return item.LineID == id })
+		items = append(items, TaxItem{Category: s.Items[j].TaxCategory, Amount: amount})
+	}
+
+	lines, err := CalculateTax(s.Currency(), items, s.DiningOption, s.TaxPolicy, s.taxPoint())
+	if err != nil {
+		return Money{}, err
+	}
+	total := Zero(s.Currency())
+	for _, line := range lines {
+		total.Amount += line.Gross.Amount
+	}
+	return total, nil
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRefundTestSession(t *testing.T) *Session {
+	t.Helper()
+	session := newDiscountTestSession(t) // 1080円 + 550円 × 2 = 2180円
+	session.Status = StatusCompleted
+	session.PaymentStatus = PaymentStatusPaid
+	return session
+}
+
+func TestSession_RefundLedger(t *testing.T) {
+	now := time.Now()
+
+	t.Run("部分返金を記録し、全額に達したら返金済みになる", func(t *testing.T) {
+		s := newRefundTestSession(t)
+
+		first, err := s.MarkRefundPartially(RefundRequest{Amount: Yen(1000), Reason: "提供遅れ", Actor: "manager:a@example.com", PaymentRef: "re_1"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, "manager:a@example.com", first.Actor)
+		assert.Equal(t, PaymentStatusPartiallyRefunded, s.PaymentStatus)
+		assert.Equal(t, StatusCompleted, s.Status, "返金は提供の進行を変更しない")
+		assert.Equal(t, Yen(1180), s.RefundableAmount())
+
+		_, err = s.MarkRefundPartially(RefundRequest{Amount: Yen(1181), Reason: "追加"}, now)
+		assert.ErrorIs(t, err, ErrRefundAmountExceedsTotal, "返金済みの合計は支払額を超えられない")
+
+		_, err = s.MarkRefundPartially(RefundRequest{Amount: Yen(1180), Reason: "追加"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, PaymentStatusRefunded, s.PaymentStatus)
+		last := s.PaymentHistory[len(s.PaymentHistory)-1]
+		assert.Equal(t, PaymentStatusPartiallyRefunded, last.From)
+		assert.Equal(t, "追加", last.Reason)
+		assert.Len(t, s.Refunds, 2)
+		assert.Equal(t, Yen(2180), s.RefundedAmount())
+
+		_, err = s.MarkRefundPartially(RefundRequest{Amount: Yen(1), Reason: "追加"}, now)
+		assert.ErrorIs(t, err, ErrOrderAlreadyFullyRefunded)
+	})
+
+	t.Run("明細を指定した返金", func(t *testing.T) {
+		s := newRefundTestSession(t)
+		line := s.Items[1].LineID
+
+		refund, err := s.MarkRefundPartially(RefundRequest{LineIDs: []string{line}, Reason: "品切れ"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(1100), refund.Amount)
+
+		_, err = s.MarkRefundPartially(RefundRequest{LineIDs: []string{line}, Reason: "品切れ"}, now)
+		assert.ErrorIs(t, err, ErrRefundLineAlreadyRefunded)
+
+		_, err = s.MarkRefundPartially(RefundRequest{LineIDs: []string{"line_unknown"}, Reason: "品切れ"}, now)
+		assert.ErrorIs(t, err, ErrOrderLineNotFound)
+	})
+
+	t.Run("税抜価格の店舗では明細の税込の金額を返金する", func(t *testing.T) {
+		s := newRefundTestSession(t)
+		require.NoError(t, s.SetTaxPolicy(TaxPolicy{PriceMode: PriceModeExclusive, Rounding: RoundDown}, DiningTakeout))
+
+		refund, err := s.MarkRefundPartially(RefundRequest{LineIDs: []string{s.Items[0].LineID}, Reason: "品切れ"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(1166), refund.Amount, "1080円 + 軽減税率8%")
+	})
+
+	t.Run("全額返金は残額を返金する", func(t *testing.T) {
+		s := newRefundTestSession(t)
+		_, err := s.MarkRefundPartially(RefundRequest{Amount: Yen(180), Reason: "提供遅れ"}, now)
+		require.NoError(t, err)
+
+		refund, err := s.MarkRefundFully(RefundRequest{Reason: "注文の取り消し"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(2000), refund.Amount)
+		assert.Equal(t, PaymentStatusRefunded, s.PaymentStatus)
+	})
+
+	t.Run("入力の検証", func(t *testing.T) {
+		s := newRefundTestSession(t)
+
+		_, err := s.MarkRefundPartially(RefundRequest{Amount: Yen(100)}, now)
+		assert.ErrorIs(t, err, ErrRefundReasonRequired)
+		_, err = s.MarkRefundPartially(RefundRequest{Reason: "提供遅れ"}, now)
+		assert.ErrorIs(t, err, ErrInvalidRefundAmount)
+		_, err = s.MarkRefundPartially(RefundRequest{Amount: Yen(100), LineIDs: []string{s.Items[0].LineID}, Reason: "提供遅れ"}, now)
+		assert.ErrorIs(t, err, ErrRefundAmountLinesMismatch)
+
+		s.PaymentStatus = PaymentStatusUnpaid
+		_, err = s.MarkRefundPartially(RefundRequest{Amount: Yen(100), Reason: "提供遅れ"}, now)
+		assert.ErrorIs(t, err, ErrRefundNotAllowed)
+		assert.Empty(t, s.Refunds)
+	})
+}
+
+func TestSession_PendingRefund(t *testing.T) {
+	s := newRefundTestSession(t)
+	refund, err := s.MarkRefundPartially(RefundRequest{Amount: Yen(1000), Reason: "提供遅れ"}, time.Now())
+	require.NoError(t, err)
+
+	assert.Nil(t, s.PendingRefund())
+	pending, err := s.MarkRefundPending(refund.ID)
+	require.NoError(t, err)
+	assert.True(t, pending.Pending)
+	assert.Equal(t, refund.ID, s.PendingRefund().ID)
+	assert.Equal(t, Yen(1180), s.RefundableAmount(), "返金待ちの金額も返金済みの合計に含める")
+
+	completed, err := s.CompleteRefund(refund.ID, "re_1")
+	require.NoError(t, err)
+	assert.False(t, completed.Pending)
+	assert.Equal(t, "re_1", s.Refunds[0].PaymentRef)
+	assert.Nil(t, s.PendingRefund())
+
+	_, err = s.CompleteRefund("refund_unknown", "re_2")
+	assert.ErrorIs(t, err, ErrRefundNotFound)
+}
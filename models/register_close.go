@@ -0,0 +1,405 @@
+package models
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BusinessDayLayout は営業日の書式です。
+const BusinessDayLayout = "2006-01-02"
+
+// businessDayCutoffHour は営業日が切り替わる時刻（日本時間）です。
+// 深夜営業の売上を前日の営業日に含めるため、午前4時で切り替えます。
+const businessDayCutoffHour = 4
+
+// PaymentMethodOnline はオンライン決済（決済代行会社）の集計上の支払い方法です。レジでの支払いには使用できません。
+const PaymentMethodOnline TenderMethod = "online"
+
+var (
+	ErrInvalidBusinessDay    = errors.New("営業日の指定が不正です（YYYY-MM-DD）")
+	ErrBusinessDayNotStarted = errors.New("まだ始まっていない営業日は締められません")
+	ErrBusinessDayClosed     = errors.New("営業日はすでにレジ締め済みです")
+	ErrInvalidCountedCash    = errors.New("現金の実査額は0以上で指定してください")
+	ErrRegisterCloseNotFound = errors.New("レジ締めの記録が見つかりません")
+)
+
+// BusinessDayOf は日時が属する営業日を返します。
+func BusinessDayOf(t time.Time) string {
+	return t.In(jst).Add(-businessDayCutoffHour * time.Hour).Format(BusinessDayLayout)
+}
+
+// BusinessDayRange は営業日の開始日時と終了日時（終了日時は含まない）を返します。
+func BusinessDayRange(day string) (start, end time.Time, err error) {
+	date, err := time.ParseInLocation(BusinessDayLayout, day, jst)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", ErrInvalidBusinessDay, day)
+	}
+	start = date.Add(businessDayCutoffHour * time.Hour)
+	return start, start.AddDate(0, 0, 1), nil
+}
+
+// RegisterCloseID は店舗の営業日のレジ締めの記録のIDを返します。
+// 1つの営業日につき1回のみ締められるよう、店舗IDと営業日から決まるIDを使用します。
+func RegisterCloseID(storeID, day string) string {
+	return storeID + "_" + day
+}
+
+// PaymentMethodTotal は支払い方法ごとの件数と金額です。
+// 現金の金額はお釣りを差し引いた受取額です。
+type PaymentMethodTotal struct {
+	Method TenderMethod `json:"method"`
+	Count  int          `json:"count"`
+	Amount Money        `json:"amount"`
+}
+
+// ZReport は営業日の売上の集計（Zレポート）です。
+// 売上は営業日中に精算（レジでの精算、割り勘の精算またはオンライン決済）された注文を対象とし、
+// 返金は営業日中に記録したもの、取消は営業日中に作成されキャンセルされた注文と営業日中に取り消した明細を対象とします。
+type ZReport struct {
+	StoreID     string    `json:"store_id"`
+	StoreName   string    `json:"store_name"`
+	BusinessDay string    `json:"business_day"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+
+	OrderCount int `json:"order_count"`
+	GuestCount int `json:"guest_count"`
+
+	// GrossSales は割引後の税込売上で、Taxes の Gross の合計と一致します。
+	GrossSales Money     `json:"gross_sales"`
+	Taxes      []TaxLine `json:"taxes"`
+
+	DiscountCount int   `json:"discount_count"`
+	Discounts     Money `json:"discounts"`
+	RefundCount   int   `json:"refund_count"`
+	Refunds       Money `json:"refunds"`
+	// CashRefunds は返金のうち決済代行会社を通さない（PaymentRef のない）現金での返金の合計です。
+	CashRefunds Money `json:"cash_refunds"`
+	// NetSales は売上から返金を差し引いた純売上です。
+	NetSales Money `json:"net_sales"`
+
+	VoidCount int   `json:"void_count"`
+	Voids     Money `json:"voids"`
+
+	Payments []PaymentMethodTotal `json:"payments"`
+
+	// OpenOrderCount は営業日中に作成され、まだ精算されていない注文の数です。
+	OpenOrderCount int `json:"open_order_count"`
+}
+
+// ZReportInput はZレポートの集計対象となる店舗のデータです。営業日外のデータは集計時に除外します。
+type ZReportInput struct {
+	Sessions    []*Session
+	Settlements []*Settlement
+	BillSplits  []*BillSplit
+	Payments    []*Payment
+}
+
+// NewZReport は店舗の営業日のZレポートを集計します。
+func NewZReport(store *Store, day string, in ZReportInput) (*ZReport, error) {
+	start, end, err := BusinessDayRange(day)
+	if err != nil {
+		return nil, err
+	}
+	within := func(t time.Time) bool { return !t.Before(start) && t.Before(end) }
+
+	// 精算された注文と、支払い方法ごとの受取額
+	paid := map[string]bool{}
+	methods := map[TenderMethod]*PaymentMethodTotal{}
+	addPayment := func(method TenderMethod, amount Money) {
+		if methods[method] == nil {
+			methods[method] = &PaymentMethodTotal{Method: method, Amount: Zero(amount.currency())}
+		}
+		methods[method].Count++
+		methods[method].Amount.Amount += amount.Amount
+	}
+	for _, settlement := range in.Settlements {
+		if settlement.StoreID != store.ID || !within(settlement.CreatedAt) {
+			continue
+		}
+		for _, id := range settlement.SessionIDs {
+			paid[id] = true
+		}
+		for _, tender := range settlement.Tenders {
+			amount := tender.Amount
+			if tender.Method == TenderCash {
+				// お釣りは現金で返すため、現金の受取額から差し引く
+				amount.Amount -= settlement.Change.Amount
+			}
+			addPayment(tender.Method, amount)
+		}
+	}
+	for _, split := range in.BillSplits {
+		if split.StoreID != store.ID {
+			continue
+		}
+		// 伝票の支払いは支払った営業日に、注文の売上は割り勘が精算された営業日に計上する
+		for _, sub := range split.SubBills {
+			if sub.Paid && within(sub.PaidAt) {
+				addPayment(sub.Method, sub.Amount)
+			}
+		}
+		if split.Status == BillSplitSettled && within(split.SettledAt) {
+			for _, id := range split.SessionIDs {
+				paid[id] = true
+			}
+		}
+	}
+	for _, payment := range in.Payments {
+		if payment.StoreID != store.ID || payment.Status != PaymentIntentSucceeded || !within(payment.UpdatedAt) {
+			continue
+		}
+		paid[payment.SessionID] = true
+		addPayment(PaymentMethodOnline, payment.Amount)
+	}
+
+	sessions := make([]*Session, 0, len(in.Sessions))
+	for _, s := range in.Sessions {
+		if s.StoreID == store.ID {
+			sessions = append(sessions, s)
+		}
+	}
+	currency := DefaultCurrency
+	for _, s := range sessions {
+		if paid[s.ID] {
+			currency = s.Currency()
+			break
+		}
+	}
+
+	report := &ZReport{
+		StoreID:     store.ID,
+		StoreName:   store.Name,
+		BusinessDay: day,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		GrossSales:  Zero(currency),
+		Discounts:   Zero(currency),
+		Refunds:     Zero(currency),
+		CashRefunds: Zero(currency),
+		Voids:       Zero(currency),
+	}
+	guests := map[string]int{}
+	for _, s := range sessions {
+		if s.Currency() != currency && (paid[s.ID] || len(s.Refunds) > 0) {
+			return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, currency, s.Currency())
+		}
+
+		switch {
+		case paid[s.ID]:
+			report.OrderCount++
+			report.GrossSales.Amount += s.TotalAmount.Amount
+			report.Taxes = mergeTaxLines(report.Taxes, s.Taxes)
+			report.DiscountCount += len(s.Discounts)
+			report.Discounts.Amount += s.DiscountTotal().Amount
+			// 来店ごとに人数を数え、同じ来店の追加注文で重複しないようにする
+			visit := s.VisitID
+			if visit == "" {
+				visit = s.ID
+			}
+			guests[visit] = max(guests[visit], s.PartySize, 1)
+		case !within(s.IssuedAt):
+			// 営業日外に作成された未精算の注文は集計しない
+		case s.Status == StatusCancelled || s.Status == StatusDeclined:
+			// 全ての明細を取り消してキャンセルされた注文は、明細の取消として数える
+			if s.DerivedStatus() != StatusCancelled {
+				report.VoidCount++
+			}
+			report.Voids.Amount += s.TotalAmount.Amount
+		case s.isBillable() && !s.Workflow().IsFinal(s.Status) && !s.paymentStatus().IsPaid():
+			report.OpenOrderCount++
+		}
+
+		// 取り消した明細は合計金額に含まれないため、取り消す前の金額で数える
+		for _, item := range s.Items {
+			if item.IsVoided() && within(item.VoidedAt) {
+				gross, err := item.Gross()
+				if err != nil {
+					return nil, err
+				}
+				report.VoidCount++
+				report.Voids.Amount += gross.Amount
+			}
+		}
+
+		for _, refund := range s.Refunds {
+			if !within(refund.CreatedAt) {
+				continue
+			}
+			report.RefundCount++
+			report.Refunds.Amount += refund.Amount.Amount
+			if refund.PaymentRef == "" {
+				report.CashRefunds.Amount += refund.Amount.Amount
+			}
+		}
+	}
+	for _, n := range guests {
+		report.GuestCount += n
+	}
+	report.NetSales = NewMoney(report.GrossSales.Amount-report.Refunds.Amount, currency)
+
+	for _, method := range []TenderMethod{TenderCash, TenderCard, TenderQRPay, TenderGiftCard, PaymentMethodOnline} {
+		if total := methods[method]; total != nil {
+			report.Payments = append(report.Payments, *total)
+		}
+	}
+	slices.SortStableFunc(report.Taxes, func(a, b TaxLine) int { return cmp.Compare(b.Rate.Percent, a.Rate.Percent) })
+	return report, nil
+}
+
+// PaymentTotal は支払い方法の集計を返します。集計がない場合は金額0を返します。
+func (r *ZReport) PaymentTotal(method TenderMethod) PaymentMethodTotal {
+	for _, total := range r.Payments {
+		if total.Method == method {
+			return total
+		}
+	}
+	return PaymentMethodTotal{Method: method, Amount: Zero(r.GrossSales.currency())}
+}
+
+// RegisterClose は営業日のレジ締めの記録です。
+// 締めた営業日はロックされ、以降その営業日の精算・返金は記録できません。
+// 現金の理論在高（釣銭準備金 + 現金売上 - 現金返金）とスタッフが数えた実査額を比較し、過不足を記録します。
+type RegisterClose struct {
+	ID          string
+	StoreID     string
+	BusinessDay string
+
+	OpeningFloat Money
+	CashSales    Money
+	CashRefunds  Money
+	ExpectedCash Money
+	CountedCash  Money
+	// OverShort は実査額と理論在高の差額で、正の値は過剰、負の値は不足です。
+	OverShort Money
+
+	Report ZReport
+	Note   string
+	Actor  string
+
+	ClosedAt time.Time
+}
+
+// NewRegisterClose はZレポートと現金の実査額からレジ締めの記録を作成します。
+func NewRegisterClose(report *ZReport, openingFloat, countedCash Money, actor, note string, now time.Time) (*RegisterClose, error) {
+	currency := report.GrossSales.currency()
+	if openingFloat.IsNegative() || countedCash.IsNegative() {
+		return nil, ErrInvalidCountedCash
+	}
+	for _, m := range []Money{openingFloat, countedCash} {
+		if m.currency() != currency {
+			return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, currency, m.currency())
+		}
+	}
+
+	cashSales := report.PaymentTotal(TenderCash).Amount
+	expected := NewMoney(openingFloat.Amount+cashSales.Amount-report.CashRefunds.Amount, currency)
+	return &RegisterClose{
+		ID:           RegisterCloseID(report.StoreID, report.BusinessDay),
+		StoreID:      report.StoreID,
+		BusinessDay:  report.BusinessDay,
+		OpeningFloat: NewMoney(openingFloat.Amount, currency),
+		CashSales:    cashSales,
+		CashRefunds:  report.CashRefunds,
+		ExpectedCash: expected,
+		CountedCash:  NewMoney(countedCash.Amount, currency),
+		OverShort:    NewMoney(countedCash.Amount-expected.Amount, currency),
+		Report:       *report,
+		Note:         note,
+		Actor:        actor,
+		ClosedAt:     now,
+	}, nil
+}
+
+// tenderMethodLabel は支払い方法の印字用ラベルを返します。
+func tenderMethodLabel(method TenderMethod) string {
+	switch method {
+	case TenderCash:
+		return "現金"
+	case TenderCard:
+		return "カード"
+	case TenderQRPay:
+		return "QR決済"
+	case TenderGiftCard:
+		return "ギフトカード"
+	case PaymentMethodOnline:
+		return "オンライン決済"
+	default:
+		return string(method)
+	}
+}
+
+// signedFormat は過不足の印字用に、正の値に "+" を付けて金額を返します。
+func signedFormat(m Money) string {
+	if m.Amount > 0 {
+		return "+" + m.Format()
+	}
+	return m.Format()
+}
+
+// RenderText はサーマルプリンタ向けのプレーンテキストのZレポートを返します。
+// width の扱いは領収書（Receipt.RenderText）と同じです。
+func (c *RegisterClose) RenderText(width int) string {
+	if width <= 0 {
+		width = ReceiptTextWidth
+	}
+	width = max(width, minReceiptTextWidth)
+	rule := strings.Repeat("-", width)
+	r := c.Report
+
+	var lines []string
+	add := func(s ...string) { lines = append(lines, s...) }
+	count := func(label string, n int) string { return justifyText(label, fmt.Sprintf("%d", n), width) }
+
+	add(centerText("Zレポート（レジ締め）", width), "")
+	add(r.StoreName, "営業日 "+c.BusinessDay, "締め日時 "+c.ClosedAt.In(jst).Format(receiptTimeLayout))
+	if c.Actor != "" {
+		add("担当 " + c.Actor)
+	}
+
+	add(rule,
+		count("注文数", r.OrderCount),
+		count("客数", r.GuestCount),
+		justifyText("総売上", r.GrossSales.Format(), width),
+	)
+	for _, tax := range r.Taxes {
+		if tax.Rate.Code == TaxRateExempt {
+			add(justifyText("  非課税対象", tax.Gross.Format(), width))
+			continue
+		}
+		add(
+			justifyText(fmt.Sprintf("  %d%%対象", tax.Rate.Percent), tax.Gross.Format(), width),
+			justifyText("    消費税", tax.Tax.Format(), width),
+		)
+	}
+	add(
+		justifyText(fmt.Sprintf("割引(%d件)", r.DiscountCount), r.Discounts.Neg().Format(), width),
+		justifyText(fmt.Sprintf("返金(%d件)", r.RefundCount), r.Refunds.Neg().Format(), width),
+		justifyText("純売上", r.NetSales.Format(), width),
+		justifyText(fmt.Sprintf("取消(%d件)", r.VoidCount), r.Voids.Format(), width),
+		count("未精算の注文", r.OpenOrderCount),
+	)
+
+	add(rule, "支払方法別")
+	for _, total := range r.Payments {
+		add(justifyText(fmt.Sprintf("  %s(%d件)", tenderMethodLabel(total.Method), total.Count), total.Amount.Format(), width))
+	}
+
+	add(rule,
+		justifyText("釣銭準備金", c.OpeningFloat.Format(), width),
+		justifyText("現金売上", c.CashSales.Format(), width),
+		justifyText("現金返金", c.CashRefunds.Neg().Format(), width),
+		justifyText("理論在高", c.ExpectedCash.Format(), width),
+		justifyText("実査額", c.CountedCash.Format(), width),
+		justifyText("過不足", signedFormat(c.OverShort), width),
+	)
+	if c.Note != "" {
+		add(rule, c.Note)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
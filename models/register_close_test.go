@@ -0,0 +1,203 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessDay(t *testing.T) {
+	t.Run("午前4時より前は前日の営業日", func(t *testing.T) {
+		assert.Equal(t, "2026-10-17", BusinessDayOf(time.Date(2026, 10, 18, 3, 59, 0, 0, jst)))
+		assert.Equal(t, "2026-10-18", BusinessDayOf(time.Date(2026, 10, 18, 4, 0, 0, 0, jst)))
+		assert.Equal(t, "2026-10-18", BusinessDayOf(time.Date(2026, 10, 17, 19, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("営業日の範囲", func(t *testing.T) {
+		start, end, err := BusinessDayRange("2026-10-17")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 10, 17, 4, 0, 0, 0, jst), start)
+		assert.Equal(t, time.Date(2026, 10, 18, 4, 0, 0, 0, jst), end)
+
+		_, _, err = BusinessDayRange("2026/10/17")
+		assert.ErrorIs(t, err, ErrInvalidBusinessDay)
+	})
+}
+
+// newZReportTestInput は営業日 2026-10-17 の注文・精算・オンライン決済を作成します。
+func newZReportTestInput(t *testing.T) (ZReportInput, map[string]*Session) {
+	t.Helper()
+	at := func(day, hour int) time.Time { return time.Date(2026, 10, day, hour, 0, 0, 0, jst) }
+	newSession := func(price int64, visitID string, partySize int, issuedAt time.Time) *Session {
+		s, err := NewSession("store_1", "seat_1", []Order{*NewOrder("beer", 1, Yen(price))})
+		require.NoError(t, err)
+		s.VisitID, s.PartySize, s.IssuedAt = visitID, partySize, issuedAt
+		return s
+	}
+
+	sessions := map[string]*Session{
+		"cash":      newSession(1100, "visit_1", 2, at(17, 19)),
+		"extra":     newSession(550, "visit_1", 0, at(17, 21)),
+		"online":    newSession(880, "visit_2", 3, at(17, 20)),
+		"cancelled": newSession(550, "visit_3", 1, at(17, 20)),
+		"open":      newSession(660, "visit_4", 1, at(17, 23)),
+		"yesterday": newSession(770, "visit_5", 1, at(16, 23)),
+	}
+	sessions["cancelled"].Status = StatusCancelled
+	sessions["cash"].Refunds = []Refund{{ID: "refund_1", Amount: Yen(550), Reason: "提供ミス", CreatedAt: at(17, 22)}}
+	sessions["online"].Refunds = []Refund{{ID: "refund_2", Amount: Yen(100), Reason: "値引き", PaymentRef: "re_1", CreatedAt: at(17, 22)}}
+	sessions["online"].Status = StatusCompleted
+
+	settled := &Settlement{
+		ID: "settle_1", StoreID: "store_1", VisitID: "visit_1",
+		SessionIDs: []string{sessions["cash"].ID, sessions["extra"].ID},
+		Total:      Yen(1650),
+		Tenders:    []Tender{{Method: TenderCard, Amount: Yen(1000)}, {Method: TenderCash, Amount: Yen(1000)}},
+		Tendered:   Yen(2000),
+		Change:     Yen(350),
+		CreatedAt:  at(18, 1), // 深夜の精算は前日の営業日に含める
+	}
+	nextDay := &Settlement{ID: "settle_2", StoreID: "store_1", SessionIDs: []string{sessions["open"].ID}, Tenders: []Tender{{Method: TenderCash, Amount: Yen(660)}}, CreatedAt: at(18, 5)}
+	payment := &Payment{ID: "pay_1", StoreID: "store_1", SessionID: sessions["online"].ID, Amount: Yen(880), Status: PaymentIntentSucceeded, UpdatedAt: at(17, 20)}
+
+	all := make([]*Session, 0, len(sessions))
+	for _, s := range sessions {
+		all = append(all, s)
+	}
+	return ZReportInput{Sessions: all, Settlements: []*Settlement{settled, nextDay}, Payments: []*Payment{payment}}, sessions
+}
+
+func TestNewZReport(t *testing.T) {
+	in, sessions := newZReportTestInput(t)
+	store := &Store{ID: "store_1", Name: "居酒屋テスト"}
+
+	report, err := NewZReport(store, "2026-10-17", in)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.OrderCount)
+	assert.Equal(t, 5, report.GuestCount, "同じ来店の追加注文は人数を重複して数えない")
+	gross := sessions["cash"].TotalAmount.Amount + sessions["extra"].TotalAmount.Amount + sessions["online"].TotalAmount.Amount
+	assert.Equal(t, Yen(gross), report.GrossSales)
+	var taxGross int64
+	for _, line := range report.Taxes {
+		taxGross += line.Gross.Amount
+	}
+	assert.Equal(t, gross, taxGross)
+
+	assert.Equal(t, 2, report.RefundCount)
+	assert.Equal(t, Yen(650), report.Refunds)
+	assert.Equal(t, Yen(550), report.CashRefunds)
+	assert.Equal(t, Yen(gross-650), report.NetSales)
+	assert.Equal(t, 1, report.VoidCount)
+	assert.Equal(t, sessions["cancelled"].TotalAmount, report.Voids)
+	assert.Equal(t, 1, report.OpenOrderCount, "前日の未精算の注文は含めない")
+
+	assert.Equal(t, []PaymentMethodTotal{
+		{Method: TenderCash, Count: 1, Amount: Yen(650)},
+		{Method: TenderCard, Count: 1, Amount: Yen(1000)},
+		{Method: PaymentMethodOnline, Count: 1, Amount: Yen(880)},
+	}, report.Payments)
+	assert.Equal(t, PaymentMethodTotal{Method: TenderQRPay, Amount: Yen(0)}, report.PaymentTotal(TenderQRPay))
+
+	_, err = NewZReport(store, "yesterday", in)
+	assert.ErrorIs(t, err, ErrInvalidBusinessDay)
+}
+
+func TestNewZReport_LineVoids(t *testing.T) {
+	in, sessions := newZReportTestInput(t)
+	store := &Store{ID: "store_1", Name: "居酒屋テスト"}
+	at := time.Date(2026, 10, 17, 20, 30, 0, 0, jst)
+
+	voided := *NewOrder("gyoza", 2, Yen(300))
+	voided.Status, voided.VoidedAt = LineVoided, at
+	sessions["cash"].Items = append(sessions["cash"].Items, voided)
+
+	// 全ての明細を取り消してキャンセルされた注文
+	allVoided, err := NewSession("store_1", "seat_2", []Order{*NewOrder("beer", 1, Yen(500))})
+	require.NoError(t, err)
+	allVoided.IssuedAt = at
+	require.NoError(t, allVoided.VoidLine(allVoided.Items[0].LineID, "品切れ", "", at))
+	require.Equal(t, StatusCancelled, allVoided.Status)
+	in.Sessions = append(in.Sessions, allVoided)
+
+	report, err := NewZReport(store, "2026-10-17", in)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.VoidCount, "キャンセルされた注文1件と取り消した明細2件")
+	assert.Equal(t, Yen(sessions["cancelled"].TotalAmount.Amount+600+500), report.Voids)
+}
+
+func TestNewZReport_BillSplit(t *testing.T) {
+	in, sessions := newZReportTestInput(t)
+	store := &Store{ID: "store_1", Name: "居酒屋テスト"}
+	at := time.Date(2026, 10, 17, 23, 30, 0, 0, jst)
+	in.BillSplits = []*BillSplit{{
+		ID: "split_1", StoreID: "store_1", VisitID: "visit_4",
+		SessionIDs: []string{sessions["open"].ID},
+		Status:     BillSplitSettled,
+		Total:      Yen(660),
+		SubBills: []SubBill{
+			{ID: "sub_1", Amount: Yen(300), Paid: true, Method: TenderCash, PaidAt: at},
+			{ID: "sub_2", Amount: Yen(360), Paid: true, Method: TenderCard, PaidAt: at},
+		},
+		SettledAt: at,
+	}}
+
+	report, err := NewZReport(store, "2026-10-17", in)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.OrderCount)
+	assert.Equal(t, 0, report.OpenOrderCount, "割り勘で精算した注文は未精算に含めない")
+	assert.Equal(t, []PaymentMethodTotal{
+		{Method: TenderCash, Count: 2, Amount: Yen(950)},
+		{Method: TenderCard, Count: 2, Amount: Yen(1360)},
+		{Method: PaymentMethodOnline, Count: 1, Amount: Yen(880)},
+	}, report.Payments)
+
+	closed, err := NewRegisterClose(report, Yen(10000), Yen(10400), "manager:a@example.com", "", at)
+	require.NoError(t, err)
+	assert.Equal(t, Yen(10000+950-550), closed.ExpectedCash, "割り勘の現金の支払いを理論在高に含める")
+}
+
+func TestNewRegisterClose(t *testing.T) {
+	in, _ := newZReportTestInput(t)
+	report, err := NewZReport(&Store{ID: "store_1", Name: "居酒屋テスト"}, "2026-10-17", in)
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 18, 2, 0, 0, 0, jst)
+
+	t.Run("成功: 理論在高と実査額の過不足を記録する", func(t *testing.T) {
+		closed, err := NewRegisterClose(report, Yen(10000), Yen(10050), "manager:a@example.com", "", now)
+		require.NoError(t, err)
+		assert.Equal(t, "store_1_2026-10-17", closed.ID)
+		assert.Equal(t, Yen(650), closed.CashSales)
+		assert.Equal(t, Yen(10100), closed.ExpectedCash)
+		assert.Equal(t, Yen(-50), closed.OverShort)
+	})
+
+	t.Run("失敗: 負の実査額と異なる通貨", func(t *testing.T) {
+		_, err := NewRegisterClose(report, Yen(0), Yen(-1), "", "", now)
+		assert.ErrorIs(t, err, ErrInvalidCountedCash)
+		_, err = NewRegisterClose(report, Yen(0), NewMoney(100, CurrencyUSD), "", "", now)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+
+	t.Run("テキストのZレポート", func(t *testing.T) {
+		closed, err := NewRegisterClose(report, Yen(10000), Yen(10150), "manager:a@example.com", "両替 1,000円", now)
+		require.NoError(t, err)
+
+		text := closed.RenderText(0)
+		assert.Contains(t, text, "Zレポート（レジ締め）")
+		assert.Contains(t, text, "営業日 2026-10-17")
+		assert.Contains(t, text, "締め日時 2026/10/18 02:00")
+		assert.Contains(t, text, "返金(2件)")
+		assert.Contains(t, text, "オンライン決済(1件)")
+		assert.Contains(t, text, "両替 1,000円")
+		for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			if strings.HasPrefix(line, "過不足") {
+				assert.True(t, strings.HasSuffix(line, "+¥50"), line)
+			}
+			assert.LessOrEqual(t, displayWidth(line), ReceiptTextWidth, line)
+		}
+	})
+}
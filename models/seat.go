@@ -14,11 +14,16 @@ import (
 const UserSeatPrefix = "seat_" // 実際のプレフィックス文字列に置き換えてください
 
 // Seat は座席エンティティを表します。
+// QRVersion はQRコードの再発行回数で、座席QRトークンの Rotation と一致する場合のみ有効です。
+// CurrentVisitID は着座中の来店IDで、空の場合は空席です。
 type Seat struct {
-	ID        string
-	Name      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             string
+	StoreID        string
+	Name           string
+	QRVersion      int
+	CurrentVisitID string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // NewSeat は新しいSeatインスタンスを作成します。
@@ -45,3 +50,26 @@ func NewSeat(name string) *Seat {
 		UpdatedAt: now,
 	}
 }
+
+// RotateQR は座席のQRコードを再発行扱いにし、印刷済みの古いQRコードを無効にします。
+func (s *Seat) RotateQR() {
+	s.QRVersion++
+	s.UpdatedAt = time.Now().UTC()
+}
+
+// StartVisit は空席の場合に新しい来店を開始し、現在の来店IDを返します。
+// 新しく開始した場合は started に true を返します。
+func (s *Seat) StartVisit() (visitID string, started bool) {
+	if s.CurrentVisitID != "" {
+		return s.CurrentVisitID, false
+	}
+	s.CurrentVisitID = GenerateUniqueID(VisitPrefix)
+	s.UpdatedAt = time.Now().UTC()
+	return s.CurrentVisitID, true
+}
+
+// EndVisit は会計・退席により来店を終了し、空席に戻します。
+func (s *Seat) EndVisit() {
+	s.CurrentVisitID = ""
+	s.UpdatedAt = time.Now().UTC()
+}
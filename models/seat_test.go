@@ -45,3 +45,32 @@ func TestNewSeat_EmptyName(t *testing.T) {
 	assert.Equal(t, "", seat.Name, "空の名前でも正しく設定されるべきです")
 	assert.True(t, strings.HasPrefix(seat.ID, UserSeatPrefix), "IDは空の名前でも生成されるべきです")
 }
+
+func TestSeat_RotateQR(t *testing.T) {
+	seat := NewSeat("Table 1")
+	initialUpdatedAt := seat.UpdatedAt
+	time.Sleep(10 * time.Millisecond)
+
+	seat.RotateQR()
+	assert.Equal(t, 1, seat.QRVersion, "再発行回数が加算される必要があります")
+	assert.True(t, seat.UpdatedAt.After(initialUpdatedAt))
+}
+
+func TestSeat_Visit(t *testing.T) {
+	seat := NewSeat("Table 1")
+
+	visitID, started := seat.StartVisit()
+	assert.True(t, started, "空席の場合は来店を開始する")
+	assert.True(t, strings.HasPrefix(visitID, VisitPrefix))
+
+	again, started := seat.StartVisit()
+	assert.False(t, started, "着座中は同じ来店を継続する")
+	assert.Equal(t, visitID, again)
+
+	seat.EndVisit()
+	assert.Equal(t, "", seat.CurrentVisitID)
+
+	next, started := seat.StartVisit()
+	assert.True(t, started)
+	assert.NotEqual(t, visitID, next, "退席後は新しい来店になる")
+}
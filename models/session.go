@@ -3,6 +3,8 @@ package models
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/rs/xid"
@@ -17,6 +19,10 @@ var (
 	ErrOrderExpired             = errors.New("注文の有効期限が切れています")
 	ErrOrderAlreadyFinal        = errors.New("注文はすでに最終状態のため更新できません")
 	ErrRefundAmountExceedsTotal = errors.New("返金額が合計金額を超えています")
+	ErrSessionStoreMismatch     = errors.New("注文が指定された店舗に属していません")
+	ErrOrderNotFound            = errors.New("注文が見つかりません")
+	ErrItemEditNotAllowed       = errors.New("店舗が注文を確認したため、商品の変更・削除はできません")
+	ErrDiningOptionLocked       = errors.New("支払い手続き中・支払い済み・最終状態の注文は店内飲食・持ち帰りの区分を変更できません")
 )
 
 // 動的なエラーを生成するためのカスタムエラー型
@@ -46,9 +52,44 @@ type Session struct {
 	StoreID     string
 	SeatID      string
 	Items       []Order
-	TotalAmount float64
+	TotalAmount Money
 	Status      Status
 
+	// 注文に適用するワークフローの名前（空の場合は既定のワークフロー）
+	// 作成時の店舗の設定を記録し、店舗の設定を変更しても進行中の注文の遷移は変わりません。
+	WorkflowName string
+
+	// 消費税の計算条件と税率ごとの内訳
+	// TotalAmount は税込の支払額で、Taxes の Gross の合計と一致します。
+	DiningOption DiningOption
+	TaxPolicy    TaxPolicy
+	Taxes        []TaxLine
+	TaxTotal     Money
+
+	// 適用中の割引（プロモーションコード、スタッフによる手動割引）
+	// 割引額は明細行に按分され、割引後の金額で税額を計算します。
+	Discounts []Discount
+
+	// 座席の来店IDと来店人数、店舗の設定から自動で適用したチャージ（お通し代、サービス料など）
+	// チャージは割引後の商品の金額とは別の明細として、税額と合計金額に含めます。
+	VisitID   string
+	PartySize int
+	Charges   []Charge
+
+	// 返金の記録（返金台帳）。返金済みの合計は支払額（TotalAmount）を超えることはできません。
+	Refunds []Refund
+
+	// ステータス（提供の進行）の遷移の履歴（作成時の記録を含む）
+	StatusHistory []StatusChange
+
+	// 支払いの状態と遷移の履歴。提供の進行（Status）とは独立して遷移します。
+	PaymentStatus  PaymentStatus
+	PaymentHistory []PaymentStatusChange
+
+	// スタッフによる確認が必要な注文（店舗ネットワーク外からの注文など）
+	NeedsReview  bool
+	ReviewReason string
+
 	ExpiresAt time.Time
 	IssuedAt  time.Time
 
@@ -66,78 +107,300 @@ func NewSession(storeID, seatID string, items []Order) (*Session, error) {
 		return nil, ErrNoItems
 	}
 
+	// 1つの注文内の商品は全て同じ通貨である必要がある
+	currency := items[0].Price.currency()
+	for _, item := range items {
+		if item.Price.currency() != currency {
+			return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, currency, item.Price.currency())
+		}
+	}
+
 	orderID := xid.New().String()
 	now := time.Now().UTC()
-	var totalAmount float64
 
 	// 各OrderItemに親である注文IDを設定し、合計金額を計算
 	for i := range items {
 		items[i].OrderID = orderID
-		totalAmount += items[i].Subtotal()
+		if !items[i].TaxCategory.IsValid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTaxCategory, items[i].TaxCategory)
+		}
+		if err := validateQuantity(items[i].Quantity); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Session{
-		ID:          orderID,
-		StoreID:     storeID,
-		SeatID:      seatID,
-		Items:       items,
-		TotalAmount: totalAmount,
-		Status:      StatusCreated,
-		ExpiresAt:   now.Add(15 * time.Minute), // 注文の有効期限は15分後
-		IssuedAt:    now,
-		CreatedAt:   now, // FirestoreのserverTimestampが使えない場合のフォールバック
-		UpdatedAt:   now,
-	}, nil
+	session := &Session{
+		ID:            orderID,
+		StoreID:       storeID,
+		SeatID:        seatID,
+		Items:         items,
+		TotalAmount:   Zero(currency),
+		Status:        StatusCreated,
+		PaymentStatus: PaymentStatusUnpaid,
+		DiningOption:  DiningEatIn,
+		TaxPolicy:     DefaultTaxPolicy(),
+		ExpiresAt:     now.Add(15 * time.Minute), // 注文の有効期限は15分後
+		IssuedAt:      now,
+		CreatedAt:     now, // FirestoreのserverTimestampが使えない場合のフォールバック
+		UpdatedAt:     now,
+	}
+	session.recordStatusChange("", StatusCreated, "", "", false)
+	session.PaymentHistory = []PaymentStatusChange{{To: PaymentStatusUnpaid, At: now}}
+	if err := session.RecalculateTotalAmount(); err != nil {
+		return nil, err
+	}
+	return session, nil
 }
 
 // AddItem は注文に新しいアイテムを追加します。
-// `Created`, `PendingPayment`, `PendingConfirmation`, `Confirmed`, `OnHold` ステータスでのみ追加可能です。
+// 注文のワークフローで追加を受け付けるステータスでのみ追加可能です（既定では `Created`, `PendingConfirmation`, `Confirmed`, `OnHold`）。
+// 支払い手続き中・支払い済みの注文には追加できません。
 func (s *Session) AddItem(newItem Order) error {
-	if !s.Status.CanAddItem() {
+	if !s.canChangeItems() {
 		return &CannotAddItemError{Status: s.Status}
 	}
 	if s.ExpiresAt.Before(time.Now().UTC()) {
 		return ErrOrderExpired
 	}
+	if !newItem.Price.SameCurrency(s.TotalAmount) {
+		return fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, s.Currency(), newItem.Price.currency())
+	}
+	if !newItem.TaxCategory.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidTaxCategory, newItem.TaxCategory)
+	}
+	if err := validateQuantity(newItem.Quantity); err != nil {
+		return err
+	}
+	if err := validateInstructions(newItem.Instructions); err != nil {
+		return err
+	}
 
 	newItem.OrderID = s.ID
 	s.Items = append(s.Items, newItem)
-	s.RecalculateTotalAmount() // アイテム追加後に合計金額を更新
+	if err := s.RecalculateTotalAmount(); err != nil { // アイテム追加後に合計金額を更新
+		s.Items = s.Items[:len(s.Items)-1]
+		return err
+	}
+	s.setUpdatedAt()
+	return nil
+}
+
+// canEditItems はお客様が注文した商品の数量・特別な指示の変更や削除ができるかどうかを判定します。
+// 商品を追加できる状態のうち、店舗が注文を確認する前（ワークフローの最初の状態・確認待ち）のみ変更できます。
+func (s *Session) canEditItems() bool {
+	if !s.canChangeItems() {
+		return false
+	}
+	return s.Status == s.Workflow().Initial() || s.Status == StatusPendingConfirmation
+}
+
+// editableItem は変更・削除の対象の商品のインデックスを返します。
+// 店舗の確認後・有効期限切れの注文や、調理が始まった・取り消した明細は変更できません。
+func (s *Session) editableItem(lineID string) (int, error) {
+	if !s.canEditItems() {
+		return -1, fmt.Errorf("%w: 現在のステータスは '%s'", ErrItemEditNotAllowed, s.Status)
+	}
+	if s.ExpiresAt.Before(time.Now().UTC()) {
+		return -1, ErrOrderExpired
+	}
+	i, err := s.findLine(lineID)
+	if err != nil {
+		return -1, err
+	}
+	if s.Items[i].LineStatus() != LineQueued {
+		return -1, fmt.Errorf("%w: %s", ErrLineAlreadyStarted, lineID)
+	}
+	return i, nil
+}
+
+// UpdateItem はお客様の操作で商品の数量と特別な指示を変更し、合計金額を再計算します。
+// 店舗が注文を確認する前のみ変更できます。数量を0にする場合は RemoveItem を使用します。
+func (s *Session) UpdateItem(lineID string, quantity int, instructions string) error {
+	if quantity <= 0 {
+		return ErrInvalidLineQuantity
+	}
+	if err := validateQuantity(quantity); err != nil {
+		return err
+	}
+	instructions = strings.TrimSpace(instructions)
+	if err := validateInstructions(instructions); err != nil {
+		return err
+	}
+	i, err := s.editableItem(lineID)
+	if err != nil {
+		return err
+	}
+
+	previous := s.Items[i]
+	s.Items[i].Quantity = quantity
+	s.Items[i].Instructions = instructions
+	s.Items[i].UpdatedAt = time.Now().UTC()
+	if err := s.RecalculateTotalAmount(); err != nil {
+		s.Items[i] = previous
+		return err
+	}
+	s.setUpdatedAt()
+	return nil
+}
+
+// RemoveItem はお客様の操作で商品を注文から削除し、合計金額を再計算します。
+// 店舗が注文を確認する前のみ削除できます。最後の商品は削除できないため、注文をキャンセルしてください。
+func (s *Session) RemoveItem(lineID string) error {
+	i, err := s.editableItem(lineID)
+	if err != nil {
+		return err
+	}
+	remaining := 0
+	for _, item := range s.Items {
+		if !item.IsVoided() {
+			remaining++
+		}
+	}
+	if remaining <= 1 {
+		return ErrNoItems
+	}
+
+	previous := s.Items
+	s.Items = slices.Delete(slices.Clone(s.Items), i, i+1)
+	if err := s.RecalculateTotalAmount(); err != nil {
+		s.Items = previous
+		return err
+	}
 	s.setUpdatedAt()
 	return nil
 }
 
+// SetTaxPolicy は店舗の税額計算の設定と、店内飲食・持ち帰りの区分を設定し、合計金額を再計算します。
+func (s *Session) SetTaxPolicy(policy TaxPolicy, dining DiningOption) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if !dining.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidDiningOption, dining)
+	}
+	if dining == "" {
+		dining = DiningEatIn
+	}
+
+	s.TaxPolicy = policy.WithDefaults()
+	s.DiningOption = dining
+	return s.RecalculateTotalAmount()
+}
+
+// ChangeDiningOption はスタッフがお客様に確認した店内飲食・持ち帰りの区分に変更し、税額を再計算します。
+// 税額が変わるため、支払い手続き中・支払い済みの注文と、最終状態の注文は変更できません。
+func (s *Session) ChangeDiningOption(dining DiningOption) error {
+	if !s.canChangeLines() {
+		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrDiningOptionLocked, s.Status)
+	}
+	return s.SetTaxPolicy(s.TaxPolicy, dining)
+}
+
 // UpdateStatus は注文のステータスを更新します。
 // 不正な状態遷移をチェックします。
 func (s *Session) UpdateStatus(newStatus Status) error {
-	if s.Status.IsFinal() {
+	return s.UpdateStatusBy(newStatus, "", "")
+}
+
+// UpdateStatusBy は操作者と理由を指定して注文のステータスを更新し、遷移を履歴に記録します。
+// 注文のワークフローに従って不正な状態遷移をチェックします。
+func (s *Session) UpdateStatusBy(newStatus Status, actor, reason string) error {
+	workflow := s.Workflow()
+	if workflow.IsFinal(s.Status) {
 		return fmt.Errorf("%w: 現在のステータスは '%s'", ErrOrderAlreadyFinal, s.Status)
 	}
-	if !s.Status.CanTransitionTo(newStatus) {
+	if !workflow.CanTransition(s.Status, newStatus) {
 		return &InvalidStatusTransitionError{From: s.Status, To: newStatus}
 	}
+	if workflow.RequiresPayment(newStatus) && !s.paymentStatus().IsPaid() {
+		return fmt.Errorf("%w: 支払い状態は '%s'", ErrPaymentRequired, s.paymentStatus())
+	}
 
+	from := s.Status
 	s.Status = newStatus
 	s.setUpdatedAt()
+	s.recordStatusChange(from, newStatus, actor, reason, false)
 	return nil
 }
 
-// ExceptionUpdateStatus は注文のステータスを更新します。
-// ステータスが最終状態でも更新可能です。
-func (s *Session) ExceptionUpdateStatus(newStatus Status) error {
+// exceptionUpdateStatus は遷移ルールによらず注文のステータスを例外的に更新し、遷移を履歴に記録します。
+// ステータスが最終状態でも更新可能です。レジでの一括精算など、システムが判断する例外と、
+// 責任者の承認による強制変更（OverrideStatus）からのみ使用します。
+func (s *Session) exceptionUpdateStatus(newStatus Status, actor, reason string) {
+	from := s.Status
 	s.Status = newStatus
 	s.setUpdatedAt()
-	return nil
+	s.recordStatusChange(from, newStatus, actor, reason, true)
 }
 
-// RecalculateTotalAmount は注文の合計金額を、現在のアイテムリストに基づいて再計算します。
-func (s *Session) RecalculateTotalAmount() {
-	var total float64
-	for _, item := range s.Items {
-		total += item.Subtotal()
+// FlagForReview は注文をスタッフの確認対象としてマークします。
+// 注文自体は受け付けられ、ステータスは変更されません。
+func (s *Session) FlagForReview(reason string) {
+	s.NeedsReview = true
+	s.ReviewReason = reason
+	s.setUpdatedAt()
+}
+
+// RecalculateTotalAmount は注文の合計金額と税率ごとの税額を、現在のアイテムリスト、割引、チャージに基づいて再計算します。
+// 税率は注文の発行日時（IssuedAt）時点のものを使用するため、過去の注文を再計算しても結果は変わりません。
+func (s *Session) RecalculateTotalAmount() error {
+	currency := s.Currency()
+	s.ensureLineIDs()
+	amounts, err := s.applyDiscounts()
+	if err != nil {
+		return err
+	}
+	items := make([]TaxItem, len(s.Items))
+	for i, item := range s.Items {
+		items[i] = TaxItem{Category: item.TaxCategory, Amount: amounts[i]}
+	}
+	charges, err := s.applyCharges(amounts)
+	if err != nil {
+		return err
 	}
+	items = append(items, charges...)
+
+	taxes, err := CalculateTax(currency, items, s.DiningOption, s.TaxPolicy, s.taxPoint())
+	if err != nil {
+		return err
+	}
+
+	total, tax := Zero(currency), Zero(currency)
+	for _, line := range taxes {
+		total.Amount += line.Gross.Amount
+		tax.Amount += line.Tax.Amount
+	}
+	s.Taxes = taxes
+	s.TaxTotal = tax
 	s.TotalAmount = total
 	s.setUpdatedAt()
+	return nil
+}
+
+// ensureLineIDs は明細行IDを持たないアイテム（明細行ID導入前の注文など）にIDを割り当てます。
+func (s *Session) ensureLineIDs() {
+	for i := range s.Items {
+		if s.Items[i].LineID == "" {
+			s.Items[i].LineID = GenerateUniqueID(OrderLinePrefix)
+		}
+	}
+}
+
+// taxPoint は税率の判定に使用する日時を返します。
+func (s *Session) taxPoint() time.Time {
+	switch {
+	case !s.IssuedAt.IsZero():
+		return s.IssuedAt
+	case !s.CreatedAt.IsZero():
+		return s.CreatedAt
+	default:
+		return time.Now().UTC()
+	}
+}
+
+// Currency は注文の通貨を返します。
+func (s *Session) Currency() Currency {
+	return s.TotalAmount.currency()
 }
 
 // setUpdatedAt は `updated_at` フィールドを現在時刻に設定します。
@@ -199,28 +462,12 @@ func (s *Session) MarkCancelOrder() error {
 }
 
 // MarkFailPayment は注文のお支払いが失敗したことを示します。
+// 提供の進行（Status）は変更しません。
 func (s *Session) MarkFailPayment() error {
-	return s.UpdateStatus(StatusPaymentFailed)
+	return s.UpdatePaymentStatus(PaymentStatusFailed, "", "")
 }
 
 // MarkAsOnHold は注文を一時保留にします。
 func (s *Session) MarkAsOnHold() error {
 	return s.UpdateStatus(StatusOnHold)
 }
-
-// MarkRefundFully は注文を全額返金済みにします。
-func (s *Session) MarkRefundFully() error {
-	return s.UpdateStatus(StatusRefunded)
-}
-
-// MarkRefundPartially は注文を部分返金済みにします。
-// ステータスは `PartiallyRefunded` に更新されます。
-// 完了状態でも返金処理が可能です。
-func (s *Session) MarkRefundPartially(amount float64) error {
-	if amount > s.TotalAmount {
-		return ErrRefundAmountExceedsTotal
-	}
-	// ここに実際の返金処理（金額の記録など）を実装
-	// 関数を通すと完了後のステータス変更ができないため、例外処理
-	return s.ExceptionUpdateStatus(StatusPartiallyRefunded)
-}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -13,8 +14,8 @@ import (
 // テスト用の基本的なSessionインスタンスを作成
 func newTestSession(t *testing.T) *Session {
 	items := []Order{
-		*NewOrder("prod_1", 2, 100),
-		*NewOrder("prod_2", 1, 50),
+		*NewOrder("prod_1", 2, Yen(100)),
+		*NewOrder("prod_2", 1, Yen(50)),
 	}
 	session, err := NewSession("store_123", "seat_456", items)
 	require.NoError(t, err)
@@ -27,8 +28,8 @@ func newTestSession(t *testing.T) *Session {
 func TestNewSession(t *testing.T) {
 	t.Run("正常なケース", func(t *testing.T) {
 		items := []Order{
-			*NewOrder("prod_A", 1, 150),
-			*NewOrder("prod_B", 3, 200),
+			*NewOrder("prod_A", 1, Yen(150)),
+			*NewOrder("prod_B", 3, Yen(200)),
 		}
 		storeID := "store_abc"
 		seatID := "seat_xyz"
@@ -41,7 +42,7 @@ func TestNewSession(t *testing.T) {
 		assert.Equal(t, storeID, session.StoreID)
 		assert.Equal(t, seatID, session.SeatID)
 		assert.Len(t, session.Items, 2)
-		assert.Equal(t, 150.0+600.0, session.TotalAmount)
+		assert.Equal(t, Yen(150+600), session.TotalAmount)
 		assert.Equal(t, StatusCreated, session.Status)
 		assert.NotEmpty(t, session.ID)
 
@@ -59,7 +60,7 @@ func TestNewSession(t *testing.T) {
 	})
 
 	t.Run("引数が不正なケース", func(t *testing.T) {
-		items := []Order{*NewOrder("p1", 1, 1)}
+		items := []Order{*NewOrder("p1", 1, Yen(1))}
 		_, err := NewSession("", "seat1", items)
 		assert.ErrorIs(t, err, ErrInvalidArgument)
 
@@ -67,6 +68,11 @@ func TestNewSession(t *testing.T) {
 		assert.ErrorIs(t, err, ErrInvalidArgument)
 	})
 
+	t.Run("数量が上限を超えるケース", func(t *testing.T) {
+		_, err := NewSession("store1", "seat1", []Order{*NewOrder("p1", MaxItemQuantity+1, Yen(1))})
+		assert.ErrorIs(t, err, ErrInvalidItemQuantity)
+	})
+
 	t.Run("アイテムが空のケース", func(t *testing.T) {
 		_, err := NewSession("store1", "seat1", []Order{})
 		assert.ErrorIs(t, err, ErrNoItems)
@@ -83,12 +89,12 @@ func TestSession_AddItem(t *testing.T) {
 		initialUpdatedAt := session.UpdatedAt
 		time.Sleep(10 * time.Millisecond) // 更新時刻が変わるように少し待つ
 
-		newItem := *NewOrder("prod_3", 1, 300)
+		newItem := *NewOrder("prod_3", 1, Yen(300))
 		err := session.AddItem(newItem)
 
 		assert.NoError(t, err)
 		assert.Len(t, session.Items, 3)
-		assert.Equal(t, initialTotal+300, session.TotalAmount)
+		assert.Equal(t, Yen(initialTotal.Amount+300), session.TotalAmount)
 		assert.Equal(t, session.ID, session.Items[2].OrderID)
 		assert.True(t, session.UpdatedAt.After(initialUpdatedAt))
 	})
@@ -97,7 +103,7 @@ func TestSession_AddItem(t *testing.T) {
 		session := newTestSession(t)
 		session.Status = StatusCompleted // 最終ステータスに設定
 
-		err := session.AddItem(*NewOrder("p", 1, 1))
+		err := session.AddItem(*NewOrder("p", 1, Yen(1)))
 		assert.Error(t, err)
 		var e *CannotAddItemError
 		assert.ErrorAs(t, err, &e)
@@ -108,11 +114,70 @@ func TestSession_AddItem(t *testing.T) {
 		session := newTestSession(t)
 		session.ExpiresAt = time.Now().UTC().Add(-time.Minute) // 期限切れに設定
 
-		err := session.AddItem(*NewOrder("p", 1, 1))
+		err := session.AddItem(*NewOrder("p", 1, Yen(1)))
 		assert.ErrorIs(t, err, ErrOrderExpired)
 	})
 }
 
+func TestSession_UpdateItem(t *testing.T) {
+	t.Run("数量と特別な指示を変更できるケース", func(t *testing.T) {
+		session := newTestSession(t)
+		err := session.UpdateItem(session.Items[0].LineID, 3, " ネギ抜き ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, session.Items[0].Quantity)
+		assert.Equal(t, "ネギ抜き", session.Items[0].Instructions)
+		assert.Equal(t, Yen(350), session.TotalAmount)
+	})
+
+	t.Run("店舗の確認後は変更できないケース", func(t *testing.T) {
+		session := newTestSession(t)
+		require.NoError(t, session.UpdateStatus(StatusConfirmed))
+
+		err := session.UpdateItem(session.Items[0].LineID, 3, "")
+		assert.ErrorIs(t, err, ErrItemEditNotAllowed)
+		assert.Equal(t, 2, session.Items[0].Quantity)
+	})
+
+	t.Run("不正な値のケース", func(t *testing.T) {
+		session := newTestSession(t)
+		assert.ErrorIs(t, session.UpdateItem(session.Items[0].LineID, 0, ""), ErrInvalidLineQuantity)
+		assert.ErrorIs(t, session.UpdateItem(session.Items[0].LineID, MaxItemQuantity+1, ""), ErrInvalidItemQuantity)
+		assert.ErrorIs(t, session.UpdateItem(session.Items[0].LineID, 1, strings.Repeat("あ", MaxItemInstructionsLength+1)), ErrItemInstructionsTooLong)
+		assert.ErrorIs(t, session.UpdateItem("line_unknown", 1, ""), ErrOrderLineNotFound)
+	})
+
+	t.Run("有効期限切れのケース", func(t *testing.T) {
+		session := newTestSession(t)
+		session.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+		assert.ErrorIs(t, session.UpdateItem(session.Items[0].LineID, 1, ""), ErrOrderExpired)
+	})
+}
+
+func TestSession_RemoveItem(t *testing.T) {
+	t.Run("正常に削除できるケース", func(t *testing.T) {
+		session := newTestSession(t)
+		removed := session.Items[1].LineID
+
+		assert.NoError(t, session.RemoveItem(removed))
+		assert.Len(t, session.Items, 1)
+		assert.Equal(t, Yen(200), session.TotalAmount)
+		assert.ErrorIs(t, session.RemoveItem(removed), ErrOrderLineNotFound)
+	})
+
+	t.Run("最後の商品は削除できないケース", func(t *testing.T) {
+		session := newTestSession(t)
+		require.NoError(t, session.RemoveItem(session.Items[0].LineID))
+		assert.ErrorIs(t, session.RemoveItem(session.Items[0].LineID), ErrNoItems)
+	})
+
+	t.Run("支払い手続き中は削除できないケース", func(t *testing.T) {
+		session := newTestSession(t)
+		require.NoError(t, session.UpdatePaymentStatus(PaymentStatusPending, "", ""))
+		assert.ErrorIs(t, session.RemoveItem(session.Items[0].LineID), ErrItemEditNotAllowed)
+	})
+}
+
 func TestSession_UpdateStatus(t *testing.T) {
 	t.Run("正常な状態遷移", func(t *testing.T) {
 		session := newTestSession(t)
@@ -145,12 +210,12 @@ func TestSession_UpdateStatus(t *testing.T) {
 
 func TestSession_RecalculateTotalAmount(t *testing.T) {
 	session := newTestSession(t)
-	session.Items = append(session.Items, *NewOrder("prod_4", 1, 1000))
+	session.Items = append(session.Items, *NewOrder("prod_4", 1, Yen(1000)))
 	// この時点ではTotalAmountは古いまま
-	assert.NotEqual(t, 250.0+1000.0, session.TotalAmount)
+	assert.NotEqual(t, Yen(250+1000), session.TotalAmount)
 
 	session.RecalculateTotalAmount()
-	assert.Equal(t, 250.0+1000.0, session.TotalAmount)
+	assert.Equal(t, Yen(250+1000), session.TotalAmount)
 }
 
 func TestSession_StatusHelpers(t *testing.T) {
@@ -182,15 +247,39 @@ func TestSession_MarkRefundPartially(t *testing.T) {
 	t.Run("正常な部分返金", func(t *testing.T) {
 		s := newTestSession(t)
 		s.Status = StatusCompleted // 返金は完了後などから行われる想定
-		err := s.MarkRefundPartially(100.0)
+		s.PaymentStatus = PaymentStatusPaid
+		_, err := s.MarkRefundPartially(RefundRequest{Amount: Yen(100), Reason: "提供遅れ"}, time.Now())
 		assert.NoError(t, err)
-		assert.Equal(t, StatusPartiallyRefunded, s.Status)
+		assert.Equal(t, PaymentStatusPartiallyRefunded, s.PaymentStatus)
+		assert.Equal(t, StatusCompleted, s.Status)
 	})
 
 	t.Run("返金額が合計を超えるケース", func(t *testing.T) {
 		s := newTestSession(t)
 		s.Status = StatusCompleted // 返金は完了後などから行われる想定
-		err := s.MarkRefundPartially(s.TotalAmount + 1)
+		s.PaymentStatus = PaymentStatusPaid
+		_, err := s.MarkRefundPartially(RefundRequest{Amount: Yen(s.TotalAmount.Amount + 1), Reason: "提供遅れ"}, time.Now())
 		assert.ErrorIs(t, err, ErrRefundAmountExceedsTotal)
 	})
 }
+
+func TestSession_Currency(t *testing.T) {
+	t.Run("通貨の異なる商品は同じ注文にできない", func(t *testing.T) {
+		items := []Order{*NewOrder("p1", 1, Yen(100)), *NewOrder("p2", 1, NewMoney(100, CurrencyUSD))}
+		_, err := NewSession("store_1", "seat_1", items)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+
+	t.Run("追加注文も同じ通貨である必要がある", func(t *testing.T) {
+		session := newTestSession(t)
+		err := session.AddItem(*NewOrder("p3", 1, NewMoney(100, CurrencyUSD)))
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+		assert.Equal(t, CurrencyJPY, session.Currency())
+	})
+
+	t.Run("負の返金額", func(t *testing.T) {
+		session := newTestSession(t)
+		_, err := session.MarkRefundPartially(RefundRequest{Amount: Yen(-1), Reason: "提供遅れ"}, time.Now())
+		assert.ErrorIs(t, err, ErrNegativeAmount)
+	})
+}
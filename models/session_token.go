@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SessionTokenPrefix はセッショントークン（JWTの jti）を生成する際のプレフィックスです。
+const SessionTokenPrefix = "stok_"
+
+// VisitPrefix は座席の来店（着座から会計・退席まで）を識別するIDのプレフィックスです。
+const VisitPrefix = "visit_"
+
+var (
+	ErrSessionTokenRevoked  = errors.New("セッションは終了しています。QRコードを読み込み直してください")
+	ErrSessionTokenNotFound = errors.New("セッションが見つかりません")
+)
+
+// SessionToken は StartSession で発行したセッションJWTの発行記録です。
+// ID はJWTの jti と一致し、座席の来店ごとに VisitID でまとめて失効させます。
+type SessionToken struct {
+	ID        string
+	StoreID   string
+	SeatID    string
+	VisitID   string
+	UserAgent string
+	IPAddress string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+// NewSessionToken は座席の現在の来店に紐づくセッショントークンを作成します。
+func NewSessionToken(seat *Seat, userAgent, ipAddress string, exp time.Time) *SessionToken {
+	return &SessionToken{
+		ID:        GenerateUniqueID(SessionTokenPrefix),
+		StoreID:   seat.StoreID,
+		SeatID:    seat.ID,
+		VisitID:   seat.CurrentVisitID,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		IssuedAt:  time.Now().UTC(),
+		ExpiresAt: exp,
+	}
+}
+
+// IsRevoked は失効済みかどうかを返します。
+func (t *SessionToken) IsRevoked() bool {
+	return !t.RevokedAt.IsZero()
+}
+
+// IsActive は now 時点で失効・期限切れになっていないかどうかを返します。
+func (t *SessionToken) IsActive(now time.Time) bool {
+	return !t.IsRevoked() && now.Before(t.ExpiresAt)
+}
+
+// Revoke はトークンを失効させます。既に失効済みの場合は失効日時を変更しません。
+func (t *SessionToken) Revoke(now time.Time) {
+	if t.IsRevoked() {
+		return
+	}
+	t.RevokedAt = now
+}
+
+// Verify はJWTのクレームがこのトークン記録に対して有効かどうかを確認します。
+func (t *SessionToken) Verify(claims *SessionClaims) error {
+	if t.IsRevoked() {
+		return ErrSessionTokenRevoked
+	}
+	if t.StoreID != claims.StoreID || t.SeatID != claims.SeatID || t.VisitID != claims.VisitID {
+		return ErrSessionTokenRevoked
+	}
+	return nil
+}
+
+// ToClaims はトークン記録からセッションJWTのクレームを作成します。
+func (t *SessionToken) ToClaims(seat *Seat) *SessionClaims {
+	claims := NewSessionClaims(&Store{ID: t.StoreID}, seat, t.ExpiresAt)
+	claims.ID = t.ID
+	claims.VisitID = t.VisitID
+	claims.IssuedAt = jwt.NewNumericDate(t.IssuedAt)
+	return claims
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionToken(t *testing.T) (*Seat, *SessionToken) {
+	t.Helper()
+	seat := NewSeat("Table 1")
+	seat.StoreID = "store_123"
+	seat.StartVisit()
+	return seat, NewSessionToken(seat, "Mozilla/5.0", "192.0.2.1", time.Now().Add(time.Hour))
+}
+
+func TestNewSessionToken(t *testing.T) {
+	seat, token := newTestSessionToken(t)
+
+	assert.True(t, strings.HasPrefix(token.ID, SessionTokenPrefix))
+	assert.Equal(t, seat.StoreID, token.StoreID)
+	assert.Equal(t, seat.ID, token.SeatID)
+	assert.Equal(t, seat.CurrentVisitID, token.VisitID)
+	assert.Equal(t, "Mozilla/5.0", token.UserAgent)
+	assert.Equal(t, "192.0.2.1", token.IPAddress)
+	assert.False(t, token.IsRevoked())
+}
+
+func TestSessionToken_Revoke(t *testing.T) {
+	_, token := newTestSessionToken(t)
+	now := time.Now().UTC()
+	assert.True(t, token.IsActive(now))
+
+	token.Revoke(now)
+	assert.True(t, token.IsRevoked())
+	assert.False(t, token.IsActive(now))
+
+	token.Revoke(now.Add(time.Minute))
+	assert.Equal(t, now, token.RevokedAt, "失効日時は最初の失効時のまま")
+
+	_, expired := newTestSessionToken(t)
+	assert.False(t, expired.IsActive(now.Add(2*time.Hour)), "期限切れのトークンは接続中として扱わない")
+}
+
+func TestSessionToken_Verify(t *testing.T) {
+	seat, token := newTestSessionToken(t)
+	claims := token.ToClaims(seat)
+
+	assert.NoError(t, token.Verify(claims))
+
+	other := *claims
+	other.VisitID = "visit_other"
+	assert.ErrorIs(t, token.Verify(&other), ErrSessionTokenRevoked, "別の来店のクレームは拒否する")
+
+	token.Revoke(time.Now().UTC())
+	assert.ErrorIs(t, token.Verify(claims), ErrSessionTokenRevoked)
+}
+
+func TestSessionToken_ToClaims(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_secret")
+	seat, token := newTestSessionToken(t)
+
+	signed, err := token.ToClaims(seat).ToJwtToken()
+	require.NoError(t, err)
+
+	parsed, err := jwt.ParseWithClaims(signed, &SessionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte("test_secret"), nil
+	})
+	require.NoError(t, err)
+
+	claims := parsed.Claims.(*SessionClaims)
+	assert.Equal(t, token.ID, claims.ID, "jti にトークンIDを埋め込む")
+	assert.Equal(t, token.VisitID, claims.VisitID)
+	assert.Equal(t, seat.StoreID, claims.StoreID)
+	assert.Equal(t, seat.ID, claims.SeatID)
+	assert.Equal(t, seat.Name, claims.Name)
+}
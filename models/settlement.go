@@ -0,0 +1,160 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SettlementPrefix はレジでの会計（精算）記録のIDのプレフィックスです。
+const SettlementPrefix = "settle_"
+
+// SettlementIDFor は来店の精算記録のIDを返します。
+// 1つの来店につき精算は1回のため、来店IDから決まるIDで作成し、同時に精算しても二重に記録しないようにします。
+func SettlementIDFor(visitID string) string {
+	return SettlementPrefix + visitID
+}
+
+// TenderMethod はレジで受け取った支払い方法です。
+type TenderMethod string
+
+const (
+	TenderCash     TenderMethod = "cash"
+	TenderCard     TenderMethod = "card"
+	TenderQRPay    TenderMethod = "qr_pay"
+	TenderGiftCard TenderMethod = "gift_card"
+)
+
+var (
+	ErrTendersRequired     = errors.New("預かり金額を指定してください")
+	ErrInvalidTenderMethod = errors.New("支払い方法が不正です")
+	ErrInvalidTenderAmount = errors.New("預かり金額は0より大きい値を指定してください")
+	ErrInsufficientTender  = errors.New("預かり金額の合計が会計の合計金額に足りません")
+	ErrNonCashOverTender   = errors.New("現金以外の支払いは会計の合計金額を超えて受け取れません")
+	ErrVisitAlreadySettled = errors.New("来店の会計はすでに精算済みです")
+	ErrSettlementNotFound  = errors.New("会計の精算記録が見つかりません")
+)
+
+// IsValid は支払い方法が有効かどうかを返します。
+func (m TenderMethod) IsValid() bool {
+	switch m {
+	case TenderCash, TenderCard, TenderQRPay, TenderGiftCard:
+		return true
+	default:
+		return false
+	}
+}
+
+// Tender はレジで受け取った1件の支払いです。
+// Reference はカードの承認番号やギフトカードの番号など、現金以外の支払いの控えです。
+type Tender struct {
+	Method    TenderMethod
+	Amount    Money
+	Reference string
+}
+
+// Settlement は座席の1回の来店の会計をレジで精算した記録です。
+// 複数の支払い方法を組み合わせて受け取れますが、お釣りは現金でのみ返します。
+type Settlement struct {
+	ID         string
+	StoreID    string
+	SeatID     string
+	VisitID    string
+	SessionIDs []string
+
+	Total    Money
+	Tenders  []Tender
+	Tendered Money
+	Change   Money
+
+	Actor     string
+	CreatedAt time.Time
+}
+
+// NewSettlement は会計と受け取った支払いから精算記録を作成し、お釣りを計算します。
+// 現金以外の支払いの合計は会計の合計金額を超えられず、超過分は現金の預かり金からお釣りとして返します。
+func NewSettlement(check *Check, tenders []Tender, actor string, now time.Time) (*Settlement, error) {
+	if len(tenders) == 0 {
+		return nil, ErrTendersRequired
+	}
+
+	// 呼び出し側の支払いの一覧は変更しない
+	tenders = slices.Clone(tenders)
+	currency := check.Total.currency()
+	tendered := Zero(currency)
+	nonCash := Zero(currency)
+	for i, tender := range tenders {
+		if !tender.Method.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTenderMethod, tender.Method)
+		}
+		if tender.Amount.Amount <= 0 {
+			return nil, ErrInvalidTenderAmount
+		}
+		if tender.Amount.currency() != currency {
+			return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, currency, tender.Amount.currency())
+		}
+		tenders[i].Amount = NewMoney(tender.Amount.Amount, currency)
+		tendered.Amount += tender.Amount.Amount
+		if tender.Method != TenderCash {
+			nonCash.Amount += tender.Amount.Amount
+		}
+	}
+	if nonCash.Amount > check.Total.Amount {
+		return nil, fmt.Errorf("%w: %s > %s", ErrNonCashOverTender, nonCash, check.Total)
+	}
+	if tendered.Amount < check.Total.Amount {
+		return nil, fmt.Errorf("%w: %s < %s", ErrInsufficientTender, tendered, check.Total)
+	}
+
+	return &Settlement{
+		ID:         SettlementIDFor(check.VisitID),
+		StoreID:    check.StoreID,
+		SeatID:     check.SeatID,
+		VisitID:    check.VisitID,
+		SessionIDs: check.SessionIDs,
+		Total:      check.Total,
+		Tenders:    tenders,
+		Tendered:   tendered,
+		Change:     NewMoney(tendered.Amount-check.Total.Amount, currency),
+		Actor:      actor,
+		CreatedAt:  now,
+	}, nil
+}
+
+// TenderedBy は支払い方法ごとの預かり金額の合計を返します。
+func (s *Settlement) TenderedBy(method TenderMethod) Money {
+	total := Zero(s.Total.currency())
+	for _, tender := range s.Tenders {
+		if tender.Method == method {
+			total.Amount += tender.Amount.Amount
+		}
+	}
+	return total
+}
+
+// CompleteBySettlement はレジでの精算により注文を支払い済み・完了にします。
+// 提供前の注文も含め、来店の会計の対象となる注文はまとめて完了にします。
+// オンライン決済などで支払い済みの注文は、支払い状態を変更せずに完了にします。
+// 会計の対象外の注文（キャンセル・返金済みなど）と完了済みの注文は変更せず false を返します。
+// オンライン決済の支払い手続き中の注文は、二重に支払いを受け取らないよう ErrPaymentInProgress を返します。
+func (s *Session) CompleteBySettlement(actor string) (bool, error) {
+	if !s.isBillable() || s.Status == StatusCompleted {
+		return false, nil
+	}
+	if s.paymentStatus() == PaymentStatusPending {
+		return false, ErrPaymentInProgress
+	}
+	const reason = "レジでの精算"
+	// 未払い・支払い失敗の注文は、レジでの支払いで支払い済みにできる
+	if !s.paymentStatus().IsPaid() {
+		if err := s.UpdatePaymentStatus(PaymentStatusPaid, actor, reason); err != nil {
+			return false, err
+		}
+	}
+	if err := s.UpdateStatusBy(StatusCompleted, actor, reason); err != nil {
+		// 提供前の注文は通常の遷移では完了にできないため、例外として更新する
+		s.exceptionUpdateStatus(StatusCompleted, actor, reason)
+	}
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSettlement(t *testing.T) {
+	check := &Check{StoreID: "store_1", SeatID: "seat_1", VisitID: "visit_1", SessionIDs: []string{"order_1"}, Total: Yen(3280)}
+	now := time.Now()
+
+	t.Run("成功: 現金のみでお釣りを計算する", func(t *testing.T) {
+		settlement, err := NewSettlement(check, []Tender{{Method: TenderCash, Amount: Yen(5000)}}, "manager:a@example.com", now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementIDFor("visit_1"), settlement.ID)
+		assert.Equal(t, Yen(5000), settlement.Tendered)
+		assert.Equal(t, Yen(1720), settlement.Change)
+		assert.Equal(t, "visit_1", settlement.VisitID)
+	})
+
+	t.Run("成功: カードと現金の併用ではお釣りを現金で返す", func(t *testing.T) {
+		settlement, err := NewSettlement(check, []Tender{
+			{Method: TenderCard, Amount: Yen(2000), Reference: "auth_123"},
+			{Method: TenderCash, Amount: Yen(2000)},
+		}, "", now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(720), settlement.Change)
+		assert.Equal(t, Yen(2000), settlement.TenderedBy(TenderCard))
+		assert.Equal(t, Yen(2000), settlement.TenderedBy(TenderCash))
+		assert.Equal(t, Yen(0), settlement.TenderedBy(TenderGiftCard))
+	})
+
+	t.Run("成功: 呼び出し側の支払いの一覧は変更しない", func(t *testing.T) {
+		tenders := []Tender{{Method: TenderCash, Amount: Money{Amount: 5000}}}
+		settlement, err := NewSettlement(check, tenders, "", now)
+		require.NoError(t, err)
+		assert.Equal(t, Yen(5000), settlement.Tenders[0].Amount)
+		assert.Equal(t, Money{Amount: 5000}, tenders[0].Amount)
+	})
+
+	t.Run("成功: ちょうどの金額ではお釣りは0", func(t *testing.T) {
+		settlement, err := NewSettlement(check, []Tender{
+			{Method: TenderGiftCard, Amount: Yen(1000)},
+			{Method: TenderQRPay, Amount: Yen(2280)},
+		}, "", now)
+		require.NoError(t, err)
+		assert.True(t, settlement.Change.IsZero())
+	})
+
+	t.Run("失敗: 預かり金額が不足している", func(t *testing.T) {
+		_, err := NewSettlement(check, []Tender{{Method: TenderCash, Amount: Yen(3000)}}, "", now)
+		assert.ErrorIs(t, err, ErrInsufficientTender)
+	})
+
+	t.Run("失敗: 現金以外の支払いが合計金額を超えている", func(t *testing.T) {
+		_, err := NewSettlement(check, []Tender{{Method: TenderCard, Amount: Yen(4000)}}, "", now)
+		assert.ErrorIs(t, err, ErrNonCashOverTender)
+	})
+
+	t.Run("失敗: 不正な支払い方法・金額・通貨", func(t *testing.T) {
+		_, err := NewSettlement(check, nil, "", now)
+		assert.ErrorIs(t, err, ErrTendersRequired)
+		_, err = NewSettlement(check, []Tender{{Method: "coupon", Amount: Yen(5000)}}, "", now)
+		assert.ErrorIs(t, err, ErrInvalidTenderMethod)
+		_, err = NewSettlement(check, []Tender{{Method: TenderCash, Amount: Yen(0)}}, "", now)
+		assert.ErrorIs(t, err, ErrInvalidTenderAmount)
+		_, err = NewSettlement(check, []Tender{{Method: TenderCash, Amount: NewMoney(5000, CurrencyUSD)}}, "", now)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+}
+
+func TestSession_CompleteBySettlement(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  Status
+		changed bool
+	}{
+		{"提供済みの注文は完了になる", StatusServed, true},
+		{"調理中の注文も完了になる", StatusPreparing, true},
+		{"支払い待ちの注文も完了になる", StatusPendingPayment, true},
+		{"完了済みの注文は変更しない", StatusCompleted, false},
+		{"キャンセル済みの注文は変更しない", StatusCancelled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := NewSession("store_1", "seat_1", []Order{*NewOrder("beer", 1, Yen(550))})
+			require.NoError(t, err)
+			session.Status = tt.status
+
+			changed, err := session.CompleteBySettlement("")
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+			if tt.changed {
+				assert.Equal(t, StatusCompleted, session.Status)
+			} else {
+				assert.Equal(t, tt.status, session.Status)
+			}
+		})
+	}
+
+	t.Run("オンライン決済の支払い手続き中の注文は完了にしない", func(t *testing.T) {
+		session, err := NewSession("store_1", "seat_1", []Order{*NewOrder("beer", 1, Yen(550))})
+		require.NoError(t, err)
+		require.NoError(t, session.StartPayment())
+
+		changed, err := session.CompleteBySettlement("")
+		assert.ErrorIs(t, err, ErrPaymentInProgress)
+		assert.False(t, changed)
+		assert.Equal(t, PaymentStatusPending, session.PaymentStatus)
+		assert.Equal(t, StatusCreated, session.Status)
+	})
+}
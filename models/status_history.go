@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// StatusChange は注文のステータスの遷移の記録（タイムラインの1行）です。
+// From が空の記録は注文の作成を表します。
+// ReasonCode はキャンセル・辞退・保留の理由コードで、Reason はその補足（未指定の場合は理由コードのラベル）です。
+// Exception は通常の遷移ルールによらない例外的な更新（レジでの一括精算、責任者による強制変更など）であることを示します。
+// ApprovedBy は責任者による強制変更を承認した責任者（またはその権限を持つ操作者）です。
+type StatusChange struct {
+	From       Status    `json:"from,omitempty"`
+	To         Status    `json:"to"`
+	At         time.Time `json:"at"`
+	Actor      string    `json:"actor,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	ReasonCode string    `json:"reason_code,omitempty"`
+	Exception  bool      `json:"exception,omitempty"`
+	ApprovedBy string    `json:"approved_by,omitempty"`
+}
+
+// StageDurations は注文の各段階の所要時間です。まだ到達していない段階は0です。
+type StageDurations struct {
+	// TimeToConfirm は注文の作成から店舗が確定するまでの時間です。
+	TimeToConfirm time.Duration
+	// PrepTime は調理の開始から提供の準備ができる（または提供する）までの時間です。
+	PrepTime time.Duration
+	// TimeToServe は注文の作成から提供・引き渡しまでの時間です。
+	TimeToServe time.Duration
+}
+
+// recordStatusChange はステータスの遷移を履歴に追加します。
+func (s *Session) recordStatusChange(from, to Status, actor, reason string, exception bool) {
+	s.StatusHistory = append(s.StatusHistory, StatusChange{
+		From:      from,
+		To:        to,
+		At:        s.UpdatedAt,
+		Actor:     actor,
+		Reason:    reason,
+		Exception: exception,
+	})
+}
+
+// Timeline は注文のステータスの遷移の履歴を古い順に返します。
+// 履歴の記録を始める前に作成された注文は、作成日時の記録のみを補います。
+func (s *Session) Timeline() []StatusChange {
+	if len(s.StatusHistory) > 0 && s.StatusHistory[0].From == "" {
+		return s.StatusHistory
+	}
+	return append([]StatusChange{{To: StatusCreated, At: s.CreatedAt}}, s.StatusHistory...)
+}
+
+// ReachedAt は注文が statuses のいずれかに初めて遷移した日時を返します。
+func (s *Session) ReachedAt(statuses ...Status) (time.Time, bool) {
+	for _, change := range s.Timeline() {
+		for _, status := range statuses {
+			if change.To == status {
+				return change.At, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// StageDurations は履歴から注文の各段階の所要時間を求めます。
+func (s *Session) StageDurations() StageDurations {
+	var d StageDurations
+	created := s.Timeline()[0].At
+
+	if at, ok := s.ReachedAt(StatusConfirmed); ok {
+		d.TimeToConfirm = at.Sub(created)
+	}
+	if served, ok := s.ReachedAt(StatusServed, StatusPickedUp, StatusDelivered); ok {
+		d.TimeToServe = served.Sub(created)
+	}
+	if started, ok := s.ReachedAt(StatusPreparing); ok {
+		// 調理の完了は提供準備の完了、準備の段階がない場合は提供・引き渡しとする
+		if ready, ok := s.ReachedAt(StatusReadyForPickup, StatusReadyForDelivery, StatusServed, StatusPickedUp, StatusDelivered); ok && ready.After(started) {
+			d.PrepTime = ready.Sub(started)
+		}
+	}
+	return d
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_StatusHistory(t *testing.T) {
+	session, err := NewSession("store_1", "seat_1", []Order{*NewOrder("ramen", 1, Yen(900))})
+	require.NoError(t, err)
+	require.Len(t, session.StatusHistory, 1)
+	assert.Equal(t, StatusChange{To: StatusCreated, At: session.CreatedAt}, session.StatusHistory[0])
+
+	require.NoError(t, session.UpdateStatusBy(StatusConfirmed, "manager:a@example.com", ""))
+	require.NoError(t, session.MarkPreparing())
+	assert.Error(t, session.UpdateStatusBy(StatusCompleted, "manager:a@example.com", ""), "不正な遷移は記録しない")
+	require.NoError(t, session.OverrideStatus(StatusCancelled, StatusOverride{
+		Actor: "apikey:key_1", ApprovedBy: "supervisor:佐藤", Reason: "提供遅れ",
+		ReasonCode: ReasonCode{Kind: ReasonCancel, Code: "long_wait", Label: "提供の遅れ", Active: true},
+	}))
+
+	timeline := session.Timeline()
+	require.Len(t, timeline, 4)
+	assert.Equal(t, StatusChange{From: StatusCreated, To: StatusConfirmed, At: timeline[1].At, Actor: "manager:a@example.com"}, timeline[1])
+	assert.Equal(t, StatusConfirmed, timeline[2].From)
+	assert.Equal(t, StatusPreparing, timeline[2].To)
+	assert.Equal(t, StatusChange{From: StatusPreparing, To: StatusCancelled, At: session.UpdatedAt, Actor: "apikey:key_1", Reason: "提供遅れ", ReasonCode: "long_wait", Exception: true, ApprovedBy: "supervisor:佐藤"}, timeline[3])
+}
+
+func TestSession_Timeline(t *testing.T) {
+	created := time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)
+
+	t.Run("履歴のない注文は作成日時を補う", func(t *testing.T) {
+		session := &Session{Status: StatusConfirmed, CreatedAt: created}
+		assert.Equal(t, []StatusChange{{To: StatusCreated, At: created}}, session.Timeline())
+	})
+
+	t.Run("作成の記録のない履歴にも作成日時を補う", func(t *testing.T) {
+		session := &Session{CreatedAt: created, StatusHistory: []StatusChange{{From: StatusCreated, To: StatusConfirmed, At: created.Add(time.Minute)}}}
+		timeline := session.Timeline()
+		require.Len(t, timeline, 2)
+		assert.Equal(t, created, timeline[0].At)
+	})
+}
+
+func TestSession_StageDurations(t *testing.T) {
+	created := time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return created.Add(time.Duration(minutes) * time.Minute) }
+
+	t.Run("店内飲食の全段階", func(t *testing.T) {
+		session := &Session{CreatedAt: created, StatusHistory: []StatusChange{
+			{To: StatusCreated, At: created},
+			{From: StatusCreated, To: StatusConfirmed, At: at(2)},
+			{From: StatusConfirmed, To: StatusPreparing, At: at(5)},
+			{From: StatusPreparing, To: StatusOnHold, At: at(8)},
+			{From: StatusOnHold, To: StatusPreparing, At: at(10)},
+			{From: StatusPreparing, To: StatusReadyForPickup, At: at(17)},
+			{From: StatusReadyForPickup, To: StatusServed, At: at(18)},
+		}}
+
+		assert.Equal(t, StageDurations{
+			TimeToConfirm: 2 * time.Minute,
+			PrepTime:      12 * time.Minute, // 最初の調理開始から提供準備の完了まで
+			TimeToServe:   18 * time.Minute,
+		}, session.StageDurations())
+	})
+
+	t.Run("準備の段階がない場合は提供までを調理時間とする", func(t *testing.T) {
+		session := &Session{CreatedAt: created, StatusHistory: []StatusChange{
+			{To: StatusCreated, At: created},
+			{From: StatusCreated, To: StatusPreparing, At: at(1)},
+			{From: StatusPreparing, To: StatusServed, At: at(9)},
+		}}
+
+		d := session.StageDurations()
+		assert.Zero(t, d.TimeToConfirm)
+		assert.Equal(t, 8*time.Minute, d.PrepTime)
+		assert.Equal(t, 9*time.Minute, d.TimeToServe)
+	})
+
+	t.Run("未到達の段階は0", func(t *testing.T) {
+		session := &Session{CreatedAt: created, StatusHistory: []StatusChange{{To: StatusCreated, At: created}}}
+		assert.Equal(t, StageDurations{}, session.StageDurations())
+	})
+}
@@ -20,9 +20,7 @@ func TestStatus_BooleanFlags(t *testing.T) {
 	}{
 		// --- 初期状態 ---
 		{StatusCreated, false, false, true, true, false, false},
-		{StatusPendingPayment, false, false, true, true, false, false},
-		// 支払いを受けた場合は、通常は次のステータスに進むため、キャンセル不可
-		{StatusPaymentReceived, false, false, false, false, false, false},
+		{StatusPendingConfirmation, false, false, true, true, false, false},
 
 		// --- 内部処理 ---
 		{StatusConfirmed, false, false, true, true, false, true},
@@ -42,13 +40,19 @@ func TestStatus_BooleanFlags(t *testing.T) {
 		{StatusDelivered, false, false, false, false, true, false},
 
 		// --- 最終状態 ---
-		// 完了、返金、失敗などの最終状態
+		// 完了、キャンセル、失敗などの最終状態
 		{StatusCompleted, true, false, false, false, false, false},
 		{StatusCancelled, true, false, false, false, false, false},
-		{StatusRefunded, true, false, false, false, false, false},
-		{StatusPartiallyRefunded, false, false, false, false, false, false}, // 部分返金は限定的な遷移が可能
-		{StatusPaymentFailed, true, false, false, false, false, false},
+		{StatusDeclined, true, false, false, false, false, false},
 		{StatusFailed, true, false, false, false, false, false},
+
+		// --- 支払い・返金（旧ステータス） ---
+		// 支払いの状態は PaymentStatus で管理するため、ワークフロー上はどの区分にも属さない
+		{StatusPendingPayment, false, false, false, false, false, false},
+		{StatusPaymentReceived, false, false, false, false, false, false},
+		{StatusPaymentFailed, false, false, false, false, false, false},
+		{StatusRefunded, false, false, false, false, false, false},
+		{StatusPartiallyRefunded, false, false, false, false, false, false},
 	}
 
 	for _, tc := range testCases {
@@ -71,24 +75,21 @@ func TestStatus_CanTransitionTo(t *testing.T) {
 		expected bool
 	}{
 		// --- 正常な遷移 ---
-		{StatusCreated, StatusPendingPayment, true},
+		{StatusCreated, StatusPendingConfirmation, true},
 		{StatusCreated, StatusConfirmed, true},
 		{StatusCreated, StatusCancelled, true},
 		{StatusConfirmed, StatusPreparing, true},
 		{StatusPreparing, StatusReadyForPickup, true},
+		{StatusPreparing, StatusServed, true},
 		{StatusReadyForPickup, StatusPickedUp, true},
 		{StatusPickedUp, StatusCompleted, true},
 		{StatusDelivered, StatusCompleted, true},
-		{StatusCompleted, StatusRefunded, false}, // 完了から返金は不可（モデル上）
-		// --- 返金関連の遷移 ---
-		// 部分返金からの許可された遷移
-		{StatusPartiallyRefunded, StatusRefunded, true},  // 残りの金額も返金
-		{StatusPartiallyRefunded, StatusCompleted, true}, // 返金後に完了扱い
-		{StatusPartiallyRefunded, StatusCancelled, true}, // 部分返金後にキャンセル
-		// 部分返金からの不許可遷移
-		{StatusPartiallyRefunded, StatusCreated, false},
-		{StatusPartiallyRefunded, StatusPreparing, false},
-		{StatusPartiallyRefunded, StatusConfirmed, false},
+		// --- 支払い・返金は PaymentStatus で管理する ---
+		{StatusCreated, StatusPendingPayment, false},
+		{StatusServed, StatusRefunded, false},
+		{StatusServed, StatusPartiallyRefunded, false},
+		{StatusCompleted, StatusRefunded, false},
+		{StatusPartiallyRefunded, StatusCompleted, false},
 
 		// --- 不正な遷移 ---
 		{StatusCreated, StatusCompleted, false},
@@ -103,19 +104,17 @@ func TestStatus_CanTransitionTo(t *testing.T) {
 		{StatusCompleted, StatusCompleted, false},
 
 		// --- その他エッジケース ---
-		{StatusPaymentFailed, StatusCancelled, true},
+		{StatusFailed, StatusCancelled, true},
 		{StatusOnHold, StatusPreparing, true},
 		{StatusOnHold, StatusCancelled, true},
 	}
 
 	// status.goで定義されているすべての遷移をテスト
 	allStatuses := []Status{
-		StatusCreated, StatusPendingPayment, StatusPaymentReceived, StatusPaymentFailed,
-		StatusPendingConfirmation, StatusConfirmed, StatusPreparing, StatusOnHold,
+		StatusCreated, StatusPendingConfirmation, StatusConfirmed, StatusPreparing, StatusOnHold,
 		StatusReadyForPickup, StatusReadyForDelivery, StatusOutForDelivery,
 		StatusDeliveryAttemptFailed, StatusDelivered, StatusPickedUp, StatusServed,
-		StatusCompleted, StatusCancelled, StatusDeclined, StatusRefunded,
-		StatusPartiallyRefunded, StatusFailed,
+		StatusCompleted, StatusCancelled, StatusDeclined, StatusFailed,
 	}
 
 	// CanTransitionTo の switch-case に基づいて動的にテストケースを生成
@@ -143,23 +142,17 @@ func TestStatus_CanTransitionTo(t *testing.T) {
 // これにより、実装とテストが一致していることを保証します。
 func isTransitionAllowedByLogic(s, newStatus Status) bool {
 	switch s {
-	case StatusCompleted, StatusCancelled, StatusDeclined, StatusRefunded:
+	case StatusCompleted, StatusCancelled, StatusDeclined:
 		return false // 最終状態からは遷移不可
-	case StatusPartiallyRefunded:
-		// 部分返金からは限定的な遷移を許可
-		return newStatus == StatusRefunded || newStatus == StatusCompleted || newStatus == StatusCancelled
 	case StatusCreated:
-		return newStatus == StatusPendingPayment || newStatus == StatusConfirmed || newStatus == StatusCancelled || newStatus == StatusDeclined
-	case StatusPendingPayment:
-		return newStatus == StatusPaymentReceived || newStatus == StatusPaymentFailed || newStatus == StatusCancelled || newStatus == StatusDeclined
-	case StatusPaymentReceived:
-		return newStatus == StatusPendingConfirmation || newStatus == StatusCancelled || newStatus == StatusDeclined
+		return newStatus == StatusPendingConfirmation || newStatus == StatusConfirmed || newStatus == StatusCancelled || newStatus == StatusDeclined
 	case StatusPendingConfirmation:
 		return newStatus == StatusConfirmed || newStatus == StatusCancelled || newStatus == StatusDeclined
 	case StatusConfirmed:
 		return newStatus == StatusPreparing || newStatus == StatusOnHold || newStatus == StatusCancelled
 	case StatusPreparing:
-		return newStatus == StatusReadyForPickup || newStatus == StatusReadyForDelivery || newStatus == StatusOnHold || newStatus == StatusCancelled
+		// 店内飲食は調理した料理をそのまま席に提供する
+		return newStatus == StatusReadyForPickup || newStatus == StatusReadyForDelivery || newStatus == StatusServed || newStatus == StatusOnHold || newStatus == StatusCancelled
 	case StatusOnHold:
 		return newStatus == StatusConfirmed || newStatus == StatusPreparing || newStatus == StatusReadyForPickup || newStatus == StatusReadyForDelivery || newStatus == StatusCancelled
 	case StatusReadyForPickup:
@@ -171,9 +164,7 @@ func isTransitionAllowedByLogic(s, newStatus Status) bool {
 	case StatusDeliveryAttemptFailed:
 		return newStatus == StatusOutForDelivery || newStatus == StatusDelivered || newStatus == StatusCancelled
 	case StatusDelivered, StatusPickedUp, StatusServed:
-		return newStatus == StatusCompleted || newStatus == StatusRefunded || newStatus == StatusPartiallyRefunded
-	case StatusPaymentFailed:
-		return newStatus == StatusCancelled
+		return newStatus == StatusCompleted
 	case StatusFailed:
 		return newStatus == StatusCancelled
 
@@ -10,12 +10,51 @@ const StorePrefix = "store_"
 
 // Store はストアエンティティを表します
 type Store struct {
-	ID        string
-	Name      string
-	Email     string
-	Password  string
-	Address   string
-	Phone     string
+	ID       string
+	Name     string
+	Email    string
+	Password string
+	Address  string
+	Phone    string
+
+	// 店舗が属する組織（店舗を登録したマネージャーのメールアドレス）。組織スコープのAPIキーの操作範囲の判定に使用する
+	OrganizationID string
+
+	// 店舗ネットワーク（Wi-Fi等）外からの注文を制限する設定
+	AllowedCIDRs       []string
+	NetworkRestriction NetworkRestriction
+
+	// 消費税の計算設定（税込・税抜、端数処理）と、お客様の注文に適用する店内飲食・持ち帰りの区分（空の場合は店内飲食）
+	TaxPriceMode PriceMode
+	TaxRounding  RoundingMode
+	DiningOption DiningOption
+
+	// 適格請求書発行事業者の登録番号（T + 13桁）
+	InvoiceRegistrationNumber string
+
+	// メニュー（商品カタログ）。注文の単価はこの設定から決定する
+	Products []Product
+
+	// 注文に自動で適用するチャージ（お通し代、席料、サービス料）の設定
+	ChargeRules []ChargeRule
+
+	// 注文のワークフローの名前（組み込みのワークフローから選択し、空の場合は既定のワークフロー）
+	Workflow string
+
+	// キャンセル・辞退・保留・明細の取り消しの理由コード（空の場合は既定の一覧）
+	ReasonCodes []ReasonCode
+
+	// 注文のステータスの強制変更を承認できる責任者（PINはハッシュ化して保存）
+	Supervisors []Supervisor
+
+	// 責任者のPINなしでステータスの強制変更を行えるマネージャーのメールアドレス
+	OverrideManagers []string
+
+	// 時間経過による自動のステータス遷移の設定（未設定の項目は既定値）
+	UnconfirmedAction ExpiryAction
+	AutoCompleteAfter time.Duration
+	VisitIdleTimeout  time.Duration
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -120,3 +159,8 @@ func getStringFromJSON(data map[string]interface{}, key string) string {
 	}
 	return ""
 }
+
+// TaxPolicy は店舗の消費税の計算設定を返します。未設定の項目は既定値（税込・切り捨て）です。
+func (s *Store) TaxPolicy() TaxPolicy {
+	return TaxPolicy{PriceMode: s.TaxPriceMode, Rounding: s.TaxRounding}.WithDefaults()
+}
@@ -0,0 +1,242 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TaxCategory は商品の消費税区分です。
+type TaxCategory string
+
+const (
+	// TaxCategoryStandard は標準税率の対象です（酒類、物販など）。未設定の場合もこの区分として扱います。
+	TaxCategoryStandard TaxCategory = "standard"
+	// TaxCategoryFood は軽減税率の対象となり得る飲食料品です。店内飲食の場合は標準税率になります。
+	TaxCategoryFood TaxCategory = "food"
+	// TaxCategoryExempt は非課税・不課税の商品です（商品券の販売など）。
+	TaxCategoryExempt TaxCategory = "exempt"
+)
+
+// DiningOption は店内飲食（イートイン）か持ち帰り（テイクアウト）かを表します。
+type DiningOption string
+
+const (
+	DiningEatIn   DiningOption = "eat_in"
+	DiningTakeout DiningOption = "takeout"
+)
+
+// PriceMode は商品の価格が税込か税抜かを表します。
+type PriceMode string
+
+const (
+	// PriceModeInclusive は税込価格です（総額表示）。
+	PriceModeInclusive PriceMode = "inclusive"
+	// PriceModeExclusive は税抜価格です。
+	PriceModeExclusive PriceMode = "exclusive"
+)
+
+// TaxRateCode は適用税率の種類です。
+type TaxRateCode string
+
+const (
+	TaxRateStandard TaxRateCode = "standard"
+	TaxRateReduced  TaxRateCode = "reduced"
+	TaxRateExempt   TaxRateCode = "exempt"
+)
+
+var (
+	ErrInvalidTaxCategory  = errors.New("消費税区分が不正です")
+	ErrInvalidDiningOption = errors.New("店内飲食・持ち帰りの指定が不正です")
+	ErrInvalidPriceMode    = errors.New("税込・税抜の指定が不正です")
+	ErrTaxRateNotFound     = errors.New("指定日時に適用される消費税率がありません")
+)
+
+// IsValid は定義済みの区分かどうかを返します。未設定は標準税率として有効です。
+func (c TaxCategory) IsValid() bool {
+	switch c {
+	case "", TaxCategoryStandard, TaxCategoryFood, TaxCategoryExempt:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid は定義済みの指定かどうかを返します。未設定は店内飲食として有効です。
+func (d DiningOption) IsValid() bool {
+	switch d {
+	case "", DiningEatIn, DiningTakeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid は定義済みの指定かどうかを返します。未設定は税込として有効です。
+func (m PriceMode) IsValid() bool {
+	switch m {
+	case "", PriceModeInclusive, PriceModeExclusive:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaxRate は適用される税率です。Percent は百分率の整数です。
+type TaxRate struct {
+	Code    TaxRateCode `json:"code"`
+	Percent int64       `json:"percent"`
+}
+
+// TaxRatePeriod は施行日から適用される標準税率と軽減税率の組です。
+type TaxRatePeriod struct {
+	EffectiveFrom time.Time
+	Standard      int64
+	Reduced       int64
+}
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// taxRateHistory は日本の消費税率の履歴です。
+// 過去の注文を再計算しても同じ結果になるよう、税率を変更する場合は行を追加し、既存の行は変更しないでください。
+var taxRateHistory = []TaxRatePeriod{
+	{EffectiveFrom: time.Date(1989, 4, 1, 0, 0, 0, 0, jst), Standard: 3, Reduced: 3},
+	{EffectiveFrom: time.Date(1997, 4, 1, 0, 0, 0, 0, jst), Standard: 5, Reduced: 5},
+	{EffectiveFrom: time.Date(2014, 4, 1, 0, 0, 0, 0, jst), Standard: 8, Reduced: 8},
+	{EffectiveFrom: time.Date(2019, 10, 1, 0, 0, 0, 0, jst), Standard: 10, Reduced: 8},
+}
+
+// TaxRatePeriodAt は at 時点で施行されている税率を返します。
+func TaxRatePeriodAt(at time.Time) (TaxRatePeriod, error) {
+	i := sort.Search(len(taxRateHistory), func(i int) bool {
+		return taxRateHistory[i].EffectiveFrom.After(at)
+	})
+	if i == 0 {
+		return TaxRatePeriod{}, fmt.Errorf("%w: %s", ErrTaxRateNotFound, at.Format(time.RFC3339))
+	}
+	return taxRateHistory[i-1], nil
+}
+
+// TaxRateFor は商品の区分と店内飲食・持ち帰りから、at 時点の適用税率を返します。
+// 飲食料品の持ち帰りのみ軽減税率となり、店内飲食は標準税率です。
+func TaxRateFor(category TaxCategory, dining DiningOption, at time.Time) (TaxRate, error) {
+	if category == TaxCategoryExempt {
+		return TaxRate{Code: TaxRateExempt, Percent: 0}, nil
+	}
+
+	period, err := TaxRatePeriodAt(at)
+	if err != nil {
+		return TaxRate{}, err
+	}
+	if category == TaxCategoryFood && dining == DiningTakeout {
+		return TaxRate{Code: TaxRateReduced, Percent: period.Reduced}, nil
+	}
+	return TaxRate{Code: TaxRateStandard, Percent: period.Standard}, nil
+}
+
+// TaxPolicy は店舗ごとの税額計算の設定です。
+type TaxPolicy struct {
+	PriceMode PriceMode
+	Rounding  RoundingMode
+}
+
+// DefaultTaxPolicy は税込価格・切り捨ての設定を返します。
+func DefaultTaxPolicy() TaxPolicy {
+	return TaxPolicy{PriceMode: PriceModeInclusive, Rounding: RoundDown}
+}
+
+// WithDefaults は未設定の項目を既定値で補った設定を返します。
+func (p TaxPolicy) WithDefaults() TaxPolicy {
+	defaults := DefaultTaxPolicy()
+	if p.PriceMode == "" {
+		p.PriceMode = defaults.PriceMode
+	}
+	if p.Rounding == "" {
+		p.Rounding = defaults.Rounding
+	}
+	return p
+}
+
+// Validate は設定を検証します。
+func (p TaxPolicy) Validate() error {
+	if !p.PriceMode.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidPriceMode, p.PriceMode)
+	}
+	if p.Rounding != "" && !p.Rounding.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidRoundingMode, p.Rounding)
+	}
+	return nil
+}
+
+// TaxLine は税率ごとの対象額と税額です。
+// Net は税抜の対象額、Gross は税込の対象額で、Gross = Net + Tax です。
+type TaxLine struct {
+	Rate  TaxRate `json:"rate"`
+	Net   Money   `json:"net"`
+	Tax   Money   `json:"tax"`
+	Gross Money   `json:"gross"`
+}
+
+// TaxItem は税額計算の対象となる金額と区分です。
+type TaxItem struct {
+	Category TaxCategory
+	Amount   Money
+}
+
+// CalculateTax は税率ごとに対象額を合計し、税率ごとに1回だけ端数処理して税額を計算します（適格請求書の計算方法）。
+// 戻り値は標準税率、軽減税率、非課税の順に並びます。
+func CalculateTax(currency Currency, items []TaxItem, dining DiningOption, policy TaxPolicy, at time.Time) ([]TaxLine, error) {
+	policy = policy.WithDefaults()
+
+	order := []TaxRateCode{TaxRateStandard, TaxRateReduced, TaxRateExempt}
+	rates := make(map[TaxRateCode]TaxRate, len(order))
+	sums := make(map[TaxRateCode]Money, len(order))
+	for _, item := range items {
+		rate, err := TaxRateFor(item.Category, dining, at)
+		if err != nil {
+			return nil, err
+		}
+		sum, ok := sums[rate.Code]
+		if !ok {
+			sum = Zero(currency)
+		}
+		if sum, err = sum.Add(item.Amount); err != nil {
+			return nil, err
+		}
+		rates[rate.Code] = rate
+		sums[rate.Code] = sum
+	}
+
+	lines := make([]TaxLine, 0, len(sums))
+	for _, code := range order {
+		sum, ok := sums[code]
+		if !ok {
+			continue
+		}
+		rate := rates[code]
+
+		line := TaxLine{Rate: rate}
+		if policy.PriceMode == PriceModeExclusive {
+			line.Net = sum
+			tax, err := sum.MulRatio(rate.Percent, 100, policy.Rounding)
+			if err != nil {
+				return nil, err
+			}
+			line.Tax = tax
+			if line.Gross, err = sum.Add(tax); err != nil {
+				return nil, err
+			}
+		} else {
+			line.Gross = sum
+			tax, err := sum.MulRatio(rate.Percent, 100+rate.Percent, policy.Rounding)
+			if err != nil {
+				return nil, err
+			}
+			line.Tax = tax
+			line.Net = NewMoney(sum.Amount-line.Tax.Amount, currency)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxRateFor(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	afterReform := time.Date(2024, 1, 1, 12, 0, 0, 0, jst)
+	beforeReform := time.Date(2019, 9, 30, 23, 59, 59, 0, jst)
+
+	testCases := []struct {
+		name     string
+		category TaxCategory
+		dining   DiningOption
+		at       time.Time
+		expected TaxRate
+	}{
+		{"標準税率", TaxCategoryStandard, DiningEatIn, afterReform, TaxRate{TaxRateStandard, 10}},
+		{"未設定は標準税率", "", DiningTakeout, afterReform, TaxRate{TaxRateStandard, 10}},
+		{"飲食料品の持ち帰りは軽減税率", TaxCategoryFood, DiningTakeout, afterReform, TaxRate{TaxRateReduced, 8}},
+		{"飲食料品の店内飲食は標準税率", TaxCategoryFood, DiningEatIn, afterReform, TaxRate{TaxRateStandard, 10}},
+		{"非課税", TaxCategoryExempt, DiningEatIn, afterReform, TaxRate{TaxRateExempt, 0}},
+		{"2019年10月1日より前は8%", TaxCategoryStandard, DiningEatIn, beforeReform, TaxRate{TaxRateStandard, 8}},
+		{"2019年10月1日より前は飲食料品の持ち帰りも8%", TaxCategoryFood, DiningTakeout, beforeReform, TaxRate{TaxRateReduced, 8}},
+		{"1997年は5%", TaxCategoryStandard, DiningEatIn, time.Date(2000, 1, 1, 0, 0, 0, 0, jst), TaxRate{TaxRateStandard, 5}},
+		{"施行日の0時(JST)から新税率", TaxCategoryStandard, DiningEatIn, time.Date(2019, 9, 30, 15, 0, 0, 0, time.UTC), TaxRate{TaxRateStandard, 10}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := TaxRateFor(tc.category, tc.dining, tc.at)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rate)
+		})
+	}
+
+	t.Run("消費税導入前はエラー", func(t *testing.T) {
+		_, err := TaxRateFor(TaxCategoryStandard, DiningEatIn, time.Date(1980, 1, 1, 0, 0, 0, 0, jst))
+		assert.ErrorIs(t, err, ErrTaxRateNotFound)
+	})
+}
+
+func TestCalculateTax(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		items    []TaxItem
+		dining   DiningOption
+		policy   TaxPolicy
+		expected []TaxLine
+	}{
+		{
+			name:   "税込・切り捨て",
+			items:  []TaxItem{{TaxCategoryStandard, Yen(1000)}},
+			dining: DiningEatIn,
+			policy: TaxPolicy{PriceMode: PriceModeInclusive, Rounding: RoundDown},
+			expected: []TaxLine{
+				{Rate: TaxRate{TaxRateStandard, 10}, Net: Yen(910), Tax: Yen(90), Gross: Yen(1000)},
+			},
+		},
+		{
+			name:   "税込・四捨五入",
+			items:  []TaxItem{{TaxCategoryStandard, Yen(1000)}},
+			dining: DiningEatIn,
+			policy: TaxPolicy{PriceMode: PriceModeInclusive, Rounding: RoundHalfUp},
+			expected: []TaxLine{
+				{Rate: TaxRate{TaxRateStandard, 10}, Net: Yen(909), Tax: Yen(91), Gross: Yen(1000)},
+			},
+		},
+		{
+			name:   "税抜・切り上げ",
+			items:  []TaxItem{{TaxCategoryStandard, Yen(999)}},
+			dining: DiningEatIn,
+			policy: TaxPolicy{PriceMode: PriceModeExclusive, Rounding: RoundUp},
+			expected: []TaxLine{
+				{Rate: TaxRate{TaxRateStandard, 10}, Net: Yen(999), Tax: Yen(100), Gross: Yen(1099)},
+			},
+		},
+		{
+			name: "税率ごとに合算してから端数処理",
+			items: []TaxItem{
+				{TaxCategoryFood, Yen(333)},
+				{TaxCategoryFood, Yen(333)},
+				{TaxCategoryStandard, Yen(550)},
+			},
+			dining: DiningTakeout,
+			policy: TaxPolicy{PriceMode: PriceModeExclusive, Rounding: RoundDown},
+			expected: []TaxLine{
+				{Rate: TaxRate{TaxRateStandard, 10}, Net: Yen(550), Tax: Yen(55), Gross: Yen(605)},
+				{Rate: TaxRate{TaxRateReduced, 8}, Net: Yen(666), Tax: Yen(53), Gross: Yen(719)},
+			},
+		},
+		{
+			name: "非課税を含む",
+			items: []TaxItem{
+				{TaxCategoryExempt, Yen(300)},
+				{TaxCategoryStandard, Yen(1100)},
+			},
+			dining: DiningEatIn,
+			policy: DefaultTaxPolicy(),
+			expected: []TaxLine{
+				{Rate: TaxRate{TaxRateStandard, 10}, Net: Yen(1000), Tax: Yen(100), Gross: Yen(1100)},
+				{Rate: TaxRate{TaxRateExempt, 0}, Net: Yen(300), Tax: Yen(0), Gross: Yen(300)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines, err := CalculateTax(CurrencyJPY, tc.items, tc.dining, tc.policy, at)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lines)
+		})
+	}
+
+	t.Run("通貨が異なる場合はエラー", func(t *testing.T) {
+		items := []TaxItem{{TaxCategoryStandard, NewMoney(100, CurrencyUSD)}}
+		_, err := CalculateTax(CurrencyJPY, items, DiningEatIn, DefaultTaxPolicy(), at)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+}
+
+func TestTaxPolicy_Validate(t *testing.T) {
+	assert.NoError(t, TaxPolicy{}.Validate())
+	assert.NoError(t, TaxPolicy{PriceMode: PriceModeExclusive, Rounding: RoundHalfUp}.Validate())
+	assert.ErrorIs(t, TaxPolicy{PriceMode: "gross"}.Validate(), ErrInvalidPriceMode)
+	assert.ErrorIs(t, TaxPolicy{Rounding: "nearest"}.Validate(), ErrInvalidRoundingMode)
+	assert.Equal(t, DefaultTaxPolicy(), TaxPolicy{}.WithDefaults())
+}
+
+func TestSession_SetTaxPolicy(t *testing.T) {
+	items := []Order{
+		*NewOrder("bento", 1, Yen(540)).WithTaxCategory(TaxCategoryFood),
+		*NewOrder("beer", 1, Yen(550)),
+	}
+
+	t.Run("既定は店内飲食・税込", func(t *testing.T) {
+		session, err := NewSession("store_1", "seat_1", items)
+		require.NoError(t, err)
+		assert.Equal(t, DiningEatIn, session.DiningOption)
+		assert.Equal(t, Yen(1090), session.TotalAmount)
+		assert.Equal(t, Yen(99), session.TaxTotal)
+		require.Len(t, session.Taxes, 1)
+	})
+
+	t.Run("持ち帰りは税率ごとに内訳を持つ", func(t *testing.T) {
+		session, err := NewSession("store_1", "seat_1", items)
+		require.NoError(t, err)
+		require.NoError(t, session.SetTaxPolicy(DefaultTaxPolicy(), DiningTakeout))
+
+		require.Len(t, session.Taxes, 2)
+		assert.Equal(t, TaxRate{TaxRateStandard, 10}, session.Taxes[0].Rate)
+		assert.Equal(t, Yen(50), session.Taxes[0].Tax)
+		assert.Equal(t, TaxRate{TaxRateReduced, 8}, session.Taxes[1].Rate)
+		assert.Equal(t, Yen(40), session.Taxes[1].Tax)
+		assert.Equal(t, Yen(90), session.TaxTotal)
+		assert.Equal(t, Yen(1090), session.TotalAmount)
+	})
+
+	t.Run("税抜は合計に税額を加算", func(t *testing.T) {
+		session, err := NewSession("store_1", "seat_1", items)
+		require.NoError(t, err)
+		require.NoError(t, session.SetTaxPolicy(TaxPolicy{PriceMode: PriceModeExclusive}, DiningTakeout))
+		assert.Equal(t, Yen(98), session.TaxTotal)
+		assert.Equal(t, Yen(1188), session.TotalAmount)
+	})
+
+	t.Run("不正な指定はエラー", func(t *testing.T) {
+		session, err := NewSession("store_1", "seat_1", items)
+		require.NoError(t, err)
+		assert.ErrorIs(t, session.SetTaxPolicy(DefaultTaxPolicy(), "delivery"), ErrInvalidDiningOption)
+		assert.ErrorIs(t, session.SetTaxPolicy(TaxPolicy{PriceMode: "gross"}, DiningEatIn), ErrInvalidPriceMode)
+	})
+
+	t.Run("不正な消費税区分は受け付けない", func(t *testing.T) {
+		_, err := NewSession("store_1", "seat_1", []Order{*NewOrder("x", 1, Yen(100)).WithTaxCategory("luxury")})
+		assert.ErrorIs(t, err, ErrInvalidTaxCategory)
+	})
+}
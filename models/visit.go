@@ -0,0 +1,159 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// VisitStatus は座席の来店（会計単位）の状態です。
+type VisitStatus string
+
+const (
+	// VisitOpen は着座中で、追加注文を受け付けている状態です。
+	VisitOpen VisitStatus = "open"
+	// VisitClosed は会計が済み、来店が終了した状態です。
+	VisitClosed VisitStatus = "closed"
+	// VisitAbandoned は未払いの注文がないまま操作がなく、自動で終了した状態です。
+	VisitAbandoned VisitStatus = "abandoned"
+)
+
+var (
+	ErrVisitNotFound = errors.New("来店が見つかりません")
+	ErrVisitClosed   = errors.New("来店の会計はすでに終了しています")
+)
+
+// Visit は座席の1回の来店（着座から会計まで）です。
+// 最初のお客様が座席のQRコードを読み込んだ時に開始し、会計の精算で終了します。
+// 追加注文のたびに作成される注文（Session）を、注文した順に1つの会計にまとめます。
+// ID は座席の来店ID（Seat.CurrentVisitID）と同じです。
+type Visit struct {
+	ID         string
+	StoreID    string
+	SeatID     string
+	Status     VisitStatus
+	PartySize  int
+	SessionIDs []string
+
+	// SettlementID はレジでの精算記録、または割り勘のIDです。
+	SettlementID string
+	OpenedAt     time.Time
+	ClosedAt     time.Time
+	UpdatedAt    time.Time
+}
+
+// NewVisit は座席の現在の来店を開始した記録を作成します。
+func NewVisit(seat *Seat, now time.Time) *Visit {
+	return &Visit{
+		ID:        seat.CurrentVisitID,
+		StoreID:   seat.StoreID,
+		SeatID:    seat.ID,
+		Status:    VisitOpen,
+		OpenedAt:  now.UTC(),
+		UpdatedAt: now.UTC(),
+	}
+}
+
+// IsOpen は会計前の来店かどうかを返します。
+func (v *Visit) IsOpen() bool {
+	return v.Status != VisitClosed && v.Status != VisitAbandoned
+}
+
+// AddRound は注文（追加注文の1回分）を来店に追加し、来店人数を更新します。
+// 同じ注文を重ねて追加した場合は変更しません。
+func (v *Visit) AddRound(session *Session) error {
+	if !v.IsOpen() {
+		return ErrVisitClosed
+	}
+	if !slices.Contains(v.SessionIDs, session.ID) {
+		v.SessionIDs = append(v.SessionIDs, session.ID)
+	}
+	v.PartySize = max(v.PartySize, session.PartySize)
+	v.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetPartySize はスタッフが確認した来店人数を設定します。
+// 人数分のチャージ（お通し代など）はこの人数で計算し、お客様の端末からは変更できません。
+func (v *Visit) SetPartySize(size int) error {
+	if size < 1 {
+		return ErrInvalidPartySize
+	}
+	if !v.IsOpen() {
+		return ErrVisitClosed
+	}
+	v.PartySize = size
+	v.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Close は会計の精算により来店を終了します。終了済みの場合は false を返します。
+func (v *Visit) Close(settlementID string, now time.Time) bool {
+	if !v.IsOpen() {
+		return false
+	}
+	v.Status = VisitClosed
+	v.SettlementID = settlementID
+	v.ClosedAt = now.UTC()
+	v.UpdatedAt = now.UTC()
+	return true
+}
+
+// --- 来店の会計（途中経過） ---
+
+// VisitCheck は来店の現在の会計です。追加注文ごとの注文と、会計の途中経過の合計を表します。
+// Total はキャンセル・返金済みを除いた注文の合計金額で、Paid はそのうちオンライン決済などで支払い済みの注文の金額、
+// Balance はレジで精算する残りの金額です。
+type VisitCheck struct {
+	Visit       *Visit
+	Rounds      []*Session
+	Charges     []Charge
+	ChargeTotal Money
+	Taxes       []TaxLine
+	Total       Money
+	Paid        Money
+	Balance     Money
+}
+
+// NewVisitCheck は来店と注文から現在の会計を作成します。注文は注文した順に並べます。
+func NewVisitCheck(visit *Visit, sessions []*Session) (*VisitCheck, error) {
+	rounds := slices.Clone(sessions)
+	slices.SortStableFunc(rounds, func(a, b *Session) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	currency := DefaultCurrency
+	if len(rounds) > 0 {
+		currency = rounds[0].Currency()
+	}
+	check := &VisitCheck{
+		Visit:       visit,
+		Rounds:      rounds,
+		Charges:     []Charge{},
+		ChargeTotal: Zero(currency),
+		Taxes:       []TaxLine{},
+		Total:       Zero(currency),
+		Paid:        Zero(currency),
+	}
+	for _, s := range rounds {
+		if !s.isBillable() {
+			continue
+		}
+		if s.Currency() != currency {
+			return nil, fmt.Errorf("%w: %s, %s", ErrCurrencyMismatch, currency, s.Currency())
+		}
+		check.Total.Amount += s.TotalAmount.Amount
+		check.Taxes = mergeTaxLines(check.Taxes, s.Taxes)
+		for _, c := range s.Charges {
+			if c.Waived {
+				continue
+			}
+			check.Charges = append(check.Charges, c)
+			check.ChargeTotal.Amount += c.Amount.Amount
+		}
+		if s.paymentStatus().IsPaid() {
+			check.Paid.Amount += s.TotalAmount.Amount
+		}
+	}
+	check.Balance = NewMoney(max(check.Total.Amount-check.Paid.Amount, 0), currency)
+	return check, nil
+}
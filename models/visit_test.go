@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisit_Rounds(t *testing.T) {
+	now := time.Now()
+	seat := NewSeat("Table 1")
+	seat.StoreID = "store_123"
+	seat.StartVisit()
+	visit := NewVisit(seat, now)
+	assert.Equal(t, seat.CurrentVisitID, visit.ID)
+	assert.True(t, visit.IsOpen())
+
+	first := newTestSession(t)
+	first.PartySize = 2
+	require.NoError(t, visit.AddRound(first))
+	require.NoError(t, visit.AddRound(first))
+	second := newTestSession(t)
+	require.NoError(t, visit.AddRound(second))
+	assert.Equal(t, []string{first.ID, second.ID}, visit.SessionIDs)
+	assert.Equal(t, 2, visit.PartySize, "人数を指定しない追加注文は人数を引き継ぐ")
+
+	assert.True(t, visit.Close("settle_123", now))
+	assert.False(t, visit.Close("settle_456", now))
+	assert.Equal(t, "settle_123", visit.SettlementID)
+	assert.ErrorIs(t, visit.AddRound(newTestSession(t)), ErrVisitClosed)
+}
+
+func TestVisit_SetPartySize(t *testing.T) {
+	visit := &Visit{ID: "visit_123", Status: VisitOpen, PartySize: 2}
+
+	assert.ErrorIs(t, visit.SetPartySize(0), ErrInvalidPartySize)
+	require.NoError(t, visit.SetPartySize(1))
+	assert.Equal(t, 1, visit.PartySize, "スタッフは人数を減らすこともできる")
+
+	visit.Close("settle_123", time.Now())
+	assert.ErrorIs(t, visit.SetPartySize(3), ErrVisitClosed)
+}
+
+func TestNewVisitCheck(t *testing.T) {
+	visit := &Visit{ID: "visit_123", Status: VisitOpen}
+
+	t.Run("来店直後は0円", func(t *testing.T) {
+		check, err := NewVisitCheck(visit, nil)
+		require.NoError(t, err)
+		assert.Empty(t, check.Rounds)
+		assert.True(t, check.Total.IsZero())
+		assert.True(t, check.Balance.IsZero())
+	})
+
+	t.Run("追加注文の合計と支払い済みの金額", func(t *testing.T) {
+		first := newTestSession(t)
+		second := newTestSession(t)
+		second.CreatedAt = first.CreatedAt.Add(time.Minute)
+		require.NoError(t, second.UpdatePaymentStatus(PaymentStatusPaid, "", "オンライン決済"))
+		cancelled := newTestSession(t)
+		cancelled.CreatedAt = first.CreatedAt.Add(2 * time.Minute)
+		require.NoError(t, cancelled.UpdateStatus(StatusCancelled))
+
+		check, err := NewVisitCheck(visit, []*Session{second, cancelled, first})
+		require.NoError(t, err)
+		assert.Equal(t, []*Session{first, second, cancelled}, check.Rounds, "注文した順に並べる")
+		assert.Equal(t, Yen(500), check.Total, "キャンセルした注文は含めない")
+		assert.Equal(t, Yen(250), check.Paid)
+		assert.Equal(t, Yen(250), check.Balance)
+	})
+}
@@ -0,0 +1,385 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// --- 注文のワークフロー（状態遷移の定義） ---
+//
+// ワークフローはコードに組み込まれた定義（標準・カウンター形式・先払い）のみで、店舗はその中から1つを選択します。
+// 店舗が独自の状態や遷移を定義することはできません。
+
+const (
+	// WorkflowDefault は従来の遷移表（店内飲食・持ち帰り・配達）に一致する既定のワークフローです。
+	WorkflowDefault = "default"
+	// WorkflowCounter はカウンター形式の店舗向けのワークフローです（作成→調理中→提供→完了）。
+	WorkflowCounter = "counter"
+	// WorkflowPaymentFirst は先に支払いを受けるカフェ形式の店舗向けのワークフローです。
+	WorkflowPaymentFirst = "payment_first"
+)
+
+var (
+	ErrWorkflowNotFound       = errors.New("ワークフローが見つかりません")
+	ErrInvalidWorkflow        = errors.New("ワークフローの定義が不正です")
+	ErrWorkflowAlreadyStarted = errors.New("ステータスの遷移が始まった注文のワークフローは変更できません")
+)
+
+// WorkflowDefinition は名前付きのワークフローの定義です。組み込みのワークフローの定義に使用します。
+// ワークフローは提供の進行のみを定義し、支払いの状態は PaymentStatus で管理します。
+// States に含まれない状態は、遷移元・遷移先・各区分のいずれにも指定できません。
+type WorkflowDefinition struct {
+	Name        string
+	Description string
+	Initial     Status
+	States      []Status
+	Transitions map[Status][]Status
+
+	// 最終状態（通常の遷移では更新できない状態）
+	Final []Status
+	// キャンセル可能な状態（いずれも `Cancelled` に遷移できる必要があります）
+	Cancellable []Status
+	// 商品の追加・割引・チャージの変更を受け付ける状態
+	AcceptsAdditions []Status
+	// 支払いが完了していなければ遷移できない状態（先払いの店舗など）
+	RequiresPayment []Status
+}
+
+// Workflow は検証済みのワークフローです。NewWorkflow で作成します。
+type Workflow struct {
+	def WorkflowDefinition
+
+	states      map[Status]bool
+	transitions map[Status]map[Status]bool
+	final       map[Status]bool
+	cancellable map[Status]bool
+	additions   map[Status]bool
+	payment     map[Status]bool
+}
+
+// NewWorkflow は定義を検証してワークフローを作成します。組み込みのワークフローは起動時にこの検証を通ります。
+// 最終状態以外の状態は、初期状態から到達でき、遷移先を1つ以上持つ必要があります。
+func NewWorkflow(def WorkflowDefinition) (*Workflow, error) {
+	if def.Name == "" {
+		return nil, fmt.Errorf("%w: 名前が指定されていません", ErrInvalidWorkflow)
+	}
+
+	w := &Workflow{
+		def:         def,
+		states:      make(map[Status]bool, len(def.States)),
+		transitions: make(map[Status]map[Status]bool, len(def.Transitions)),
+	}
+	for _, state := range def.States {
+		if state == "" || w.states[state] {
+			return nil, fmt.Errorf("%w: %s: 状態 %q が不正または重複しています", ErrInvalidWorkflow, def.Name, state)
+		}
+		if state.isLegacyPaymentStatus() {
+			return nil, fmt.Errorf("%w: %s: 支払いの状態 %q は PaymentStatus で管理します", ErrInvalidWorkflow, def.Name, state)
+		}
+		w.states[state] = true
+	}
+	if !w.states[def.Initial] {
+		return nil, fmt.Errorf("%w: %s: 初期状態 %q が定義されていません", ErrInvalidWorkflow, def.Name, def.Initial)
+	}
+
+	for from, targets := range def.Transitions {
+		if !w.states[from] {
+			return nil, fmt.Errorf("%w: %s: 遷移元 %q が定義されていません", ErrInvalidWorkflow, def.Name, from)
+		}
+		w.transitions[from] = make(map[Status]bool, len(targets))
+		for _, to := range targets {
+			if !w.states[to] {
+				return nil, fmt.Errorf("%w: %s: 遷移先 %q が定義されていません", ErrInvalidWorkflow, def.Name, to)
+			}
+			w.transitions[from][to] = true
+		}
+	}
+
+	var err error
+	if w.final, err = w.stateSet("最終状態", def.Final); err != nil {
+		return nil, err
+	}
+	if w.cancellable, err = w.stateSet("キャンセル可能な状態", def.Cancellable); err != nil {
+		return nil, err
+	}
+	if w.additions, err = w.stateSet("追加を受け付ける状態", def.AcceptsAdditions); err != nil {
+		return nil, err
+	}
+	if w.payment, err = w.stateSet("支払いが必要な状態", def.RequiresPayment); err != nil {
+		return nil, err
+	}
+
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// stateSet は定義済みの状態のみからなる集合を作成します。
+func (w *Workflow) stateSet(label string, states []Status) (map[Status]bool, error) {
+	set := make(map[Status]bool, len(states))
+	for _, state := range states {
+		if !w.states[state] {
+			return nil, fmt.Errorf("%w: %s: %sの %q が定義されていません", ErrInvalidWorkflow, w.def.Name, label, state)
+		}
+		set[state] = true
+	}
+	return set, nil
+}
+
+// validate は状態の区分と遷移の整合性を検証します。
+func (w *Workflow) validate() error {
+	name := w.def.Name
+	if w.final[w.def.Initial] {
+		return fmt.Errorf("%w: %s: 初期状態が最終状態です", ErrInvalidWorkflow, name)
+	}
+	for state := range w.cancellable {
+		if w.final[state] || !w.transitions[state][StatusCancelled] {
+			return fmt.Errorf("%w: %s: %q はキャンセル可能ですが `cancelled` に遷移できません", ErrInvalidWorkflow, name, state)
+		}
+	}
+	for state := range w.additions {
+		if w.final[state] {
+			return fmt.Errorf("%w: %s: 最終状態 %q で追加を受け付けることはできません", ErrInvalidWorkflow, name, state)
+		}
+	}
+
+	// 初期状態から到達できる状態を調べる
+	reached := map[Status]bool{w.def.Initial: true}
+	queue := []Status{w.def.Initial}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for to := range w.transitions[from] {
+			if !reached[to] {
+				reached[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	for _, state := range w.def.States {
+		if w.final[state] {
+			continue
+		}
+		if !reached[state] {
+			return fmt.Errorf("%w: %s: %q は初期状態から到達できません", ErrInvalidWorkflow, name, state)
+		}
+		if len(w.transitions[state]) == 0 {
+			return fmt.Errorf("%w: %s: %q は最終状態ではありませんが遷移先がありません", ErrInvalidWorkflow, name, state)
+		}
+	}
+	return nil
+}
+
+// Name はワークフローの名前を返します。
+func (w *Workflow) Name() string {
+	return w.def.Name
+}
+
+// Definition はワークフローの定義のコピーを返します。
+func (w *Workflow) Definition() WorkflowDefinition {
+	def := w.def
+	def.States = slices.Clone(def.States)
+	def.Final = slices.Clone(def.Final)
+	def.Cancellable = slices.Clone(def.Cancellable)
+	def.AcceptsAdditions = slices.Clone(def.AcceptsAdditions)
+	def.RequiresPayment = slices.Clone(def.RequiresPayment)
+	def.Transitions = make(map[Status][]Status, len(w.def.Transitions))
+	for from, targets := range w.def.Transitions {
+		def.Transitions[from] = slices.Clone(targets)
+	}
+	return def
+}
+
+// Initial は注文の作成時のステータスを返します。
+func (w *Workflow) Initial() Status {
+	return w.def.Initial
+}
+
+// HasState はワークフローに定義された状態かどうかを返します。
+func (w *Workflow) HasState(s Status) bool {
+	return w.states[s]
+}
+
+// IsFinal は最終状態かどうかを判定します。
+func (w *Workflow) IsFinal(s Status) bool {
+	return w.final[s]
+}
+
+// CanAddItem は商品の追加を受け付ける状態かどうかを判定します。
+func (w *Workflow) CanAddItem(s Status) bool {
+	return w.additions[s]
+}
+
+// CanCancel はキャンセル可能な状態かどうかを判定します。
+func (w *Workflow) CanCancel(s Status) bool {
+	return w.cancellable[s]
+}
+
+// RequiresPayment は支払いが完了していなければ遷移できない状態かどうかを判定します。
+func (w *Workflow) RequiresPayment(s Status) bool {
+	return w.payment[s]
+}
+
+// CanTransition は from から to への遷移が許可されているかどうかを判定します。
+func (w *Workflow) CanTransition(from, to Status) bool {
+	return w.transitions[from][to]
+}
+
+// NextStatuses は from から遷移できるステータスを、定義の順に返します。
+func (w *Workflow) NextStatuses(from Status) []Status {
+	return slices.Clone(w.def.Transitions[from])
+}
+
+// --- 組み込みのワークフロー ---
+
+var (
+	defaultWorkflow = mustNewWorkflow(WorkflowDefinition{
+		Name:        WorkflowDefault,
+		Description: "店内飲食・持ち帰り・配達に対応する標準の流れ",
+		Initial:     StatusCreated,
+		States: []Status{
+			StatusCreated, StatusPendingConfirmation, StatusConfirmed, StatusPreparing, StatusOnHold,
+			StatusReadyForPickup, StatusReadyForDelivery, StatusOutForDelivery, StatusDeliveryAttemptFailed,
+			StatusDelivered, StatusPickedUp, StatusServed,
+			StatusCompleted, StatusCancelled, StatusDeclined, StatusFailed,
+		},
+		Transitions: map[Status][]Status{
+			StatusCreated:             {StatusPendingConfirmation, StatusConfirmed, StatusCancelled, StatusDeclined},
+			StatusPendingConfirmation: {StatusConfirmed, StatusCancelled, StatusDeclined},
+			StatusConfirmed:           {StatusPreparing, StatusOnHold, StatusCancelled},
+			// 店内飲食は調理した料理をそのまま席に提供する
+			StatusPreparing:             {StatusReadyForPickup, StatusReadyForDelivery, StatusServed, StatusOnHold, StatusCancelled},
+			StatusOnHold:                {StatusConfirmed, StatusPreparing, StatusReadyForPickup, StatusReadyForDelivery, StatusCancelled},
+			StatusReadyForPickup:        {StatusPickedUp, StatusCancelled, StatusServed},
+			StatusReadyForDelivery:      {StatusOutForDelivery, StatusCancelled, StatusServed},
+			StatusOutForDelivery:        {StatusDelivered, StatusDeliveryAttemptFailed, StatusCancelled},
+			StatusDeliveryAttemptFailed: {StatusOutForDelivery, StatusDelivered, StatusCancelled},
+			StatusDelivered:             {StatusCompleted},
+			StatusPickedUp:              {StatusCompleted},
+			StatusServed:                {StatusCompleted},
+			// 処理失敗は最終状態だが、キャンセルとして締めることはできる
+			StatusFailed: {StatusCancelled},
+		},
+		Final: []Status{StatusCompleted, StatusCancelled, StatusDeclined, StatusFailed},
+		Cancellable: []Status{
+			StatusCreated, StatusPendingConfirmation, StatusPreparing, StatusConfirmed, StatusOnHold,
+			StatusReadyForPickup, StatusReadyForDelivery,
+		},
+		AcceptsAdditions: []Status{
+			StatusCreated, StatusPendingConfirmation, StatusConfirmed, StatusOnHold,
+		},
+	})
+
+	builtinWorkflows = map[string]*Workflow{
+		WorkflowDefault: defaultWorkflow,
+		WorkflowCounter: mustNewWorkflow(WorkflowDefinition{
+			Name:        WorkflowCounter,
+			Description: "カウンターで注文を受けてすぐに調理・提供する流れ",
+			Initial:     StatusCreated,
+			States: []Status{
+				StatusCreated, StatusPreparing, StatusServed,
+				StatusCompleted, StatusCancelled, StatusDeclined,
+			},
+			Transitions: map[Status][]Status{
+				StatusCreated:   {StatusPreparing, StatusCancelled, StatusDeclined},
+				StatusPreparing: {StatusServed, StatusCancelled},
+				StatusServed:    {StatusCompleted},
+			},
+			Final:            []Status{StatusCompleted, StatusCancelled, StatusDeclined},
+			Cancellable:      []Status{StatusCreated, StatusPreparing},
+			AcceptsAdditions: []Status{StatusCreated},
+		}),
+		WorkflowPaymentFirst: mustNewWorkflow(WorkflowDefinition{
+			Name:        WorkflowPaymentFirst,
+			Description: "先に支払いを受けてから調理し、受け取り口で引き渡す流れ",
+			Initial:     StatusCreated,
+			States: []Status{
+				StatusCreated, StatusPreparing, StatusReadyForPickup, StatusPickedUp,
+				StatusCompleted, StatusCancelled, StatusDeclined,
+			},
+			Transitions: map[Status][]Status{
+				StatusCreated:        {StatusPreparing, StatusCancelled, StatusDeclined},
+				StatusPreparing:      {StatusReadyForPickup},
+				StatusReadyForPickup: {StatusPickedUp},
+				StatusPickedUp:       {StatusCompleted},
+			},
+			Final:            []Status{StatusCompleted, StatusCancelled, StatusDeclined},
+			Cancellable:      []Status{StatusCreated},
+			AcceptsAdditions: []Status{StatusCreated},
+			// 支払いが完了するまで調理を始めない
+			RequiresPayment: []Status{StatusPreparing},
+		}),
+	}
+)
+
+// mustNewWorkflow は組み込みのワークフローを作成します。定義が不正な場合はパニックします。
+func mustNewWorkflow(def WorkflowDefinition) *Workflow {
+	w, err := NewWorkflow(def)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// DefaultWorkflow は既定のワークフローを返します。
+func DefaultWorkflow() *Workflow {
+	return defaultWorkflow
+}
+
+// LookupWorkflow は名前から組み込みのワークフローを返します。空の名前は既定のワークフローです。
+func LookupWorkflow(name string) (*Workflow, error) {
+	if name == "" {
+		return defaultWorkflow, nil
+	}
+	w, ok := builtinWorkflows[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrWorkflowNotFound, name)
+	}
+	return w, nil
+}
+
+// WorkflowNames は選択できる組み込みのワークフローの名前を昇順で返します。
+func WorkflowNames() []string {
+	names := make([]string, 0, len(builtinWorkflows))
+	for name := range builtinWorkflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// --- Session のワークフロー ---
+
+// Workflow は注文に適用されるワークフローを返します。
+// 未設定や、このバージョンに組み込まれていない名前の場合は既定のワークフローです。
+func (s *Session) Workflow() *Workflow {
+	w, err := LookupWorkflow(s.WorkflowName)
+	if err != nil {
+		return defaultWorkflow
+	}
+	return w
+}
+
+// SetWorkflow は注文に適用するワークフローを設定し、ステータスを初期状態にします。
+// ステータスの遷移が始まった注文には設定できません。
+func (s *Session) SetWorkflow(name string) error {
+	w, err := LookupWorkflow(name)
+	if err != nil {
+		return err
+	}
+	if len(s.StatusHistory) > 1 {
+		return ErrWorkflowAlreadyStarted
+	}
+
+	s.WorkflowName = w.Name()
+	if s.Status != w.Initial() {
+		s.Status = w.Initial()
+		if len(s.StatusHistory) == 1 {
+			s.StatusHistory[0].To = w.Initial()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// --- ワークフローの書き出し（図・JSON） ---
+
+// WorkflowFormat はワークフローを書き出す形式です。
+type WorkflowFormat string
+
+const (
+	// WorkflowFormatJSON はフロントエンドなどが読み込むJSON（WorkflowSchema）です。
+	WorkflowFormatJSON WorkflowFormat = "json"
+	// WorkflowFormatMermaid は Mermaid の状態遷移図（stateDiagram-v2）です。
+	WorkflowFormatMermaid WorkflowFormat = "mermaid"
+	// WorkflowFormatDOT は Graphviz の DOT 言語の有向グラフです。
+	WorkflowFormatDOT WorkflowFormat = "dot"
+)
+
+var ErrUnsupportedWorkflowFormat = errors.New("ワークフローの書き出し形式は json, mermaid, dot のいずれかです")
+
+// WorkflowStateSchema はワークフローの状態と、その状態で可能な操作です。
+type WorkflowStateSchema struct {
+	Status           Status   `json:"status"`
+	Initial          bool     `json:"initial"`
+	Final            bool     `json:"final"`
+	Cancellable      bool     `json:"cancellable"`
+	AcceptsAdditions bool     `json:"accepts_additions"`
+	RequiresPayment  bool     `json:"requires_payment"`
+	Next             []Status `json:"next"`
+}
+
+// WorkflowTransitionSchema はワークフローの遷移（遷移元から遷移先）です。
+type WorkflowTransitionSchema struct {
+	From Status `json:"from"`
+	To   Status `json:"to"`
+}
+
+// WorkflowSchema はワークフローの機械可読な表現です。
+// 状態は定義の順で、フロントエンドは各状態の next と区分から操作ボタンを表示できます。
+type WorkflowSchema struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Initial     Status                     `json:"initial"`
+	States      []WorkflowStateSchema      `json:"states"`
+	Transitions []WorkflowTransitionSchema `json:"transitions"`
+}
+
+// Schema はワークフローを機械可読な表現に変換します。
+func (w *Workflow) Schema() WorkflowSchema {
+	schema := WorkflowSchema{
+		Name:        w.def.Name,
+		Description: w.def.Description,
+		Initial:     w.def.Initial,
+		States:      make([]WorkflowStateSchema, len(w.def.States)),
+		Transitions: []WorkflowTransitionSchema{},
+	}
+	for i, state := range w.def.States {
+		next := w.NextStatuses(state)
+		if next == nil {
+			next = []Status{}
+		}
+		schema.States[i] = WorkflowStateSchema{
+			Status:           state,
+			Initial:          state == w.def.Initial,
+			Final:            w.IsFinal(state),
+			Cancellable:      w.CanCancel(state),
+			AcceptsAdditions: w.CanAddItem(state),
+			RequiresPayment:  w.RequiresPayment(state),
+			Next:             next,
+		}
+		for _, to := range next {
+			schema.Transitions = append(schema.Transitions, WorkflowTransitionSchema{From: state, To: to})
+		}
+	}
+	return schema
+}
+
+// stateTags は図に表示する状態の区分（最終・キャンセル可・追加可・要支払い）です。
+func (w *Workflow) stateTags(state Status) []string {
+	var tags []string
+	if w.IsFinal(state) {
+		tags = append(tags, "最終")
+	}
+	if w.CanCancel(state) {
+		tags = append(tags, "キャンセル可")
+	}
+	if w.CanAddItem(state) {
+		tags = append(tags, "追加可")
+	}
+	if w.RequiresPayment(state) {
+		tags = append(tags, "要支払い")
+	}
+	return tags
+}
+
+// stateLabel は図に表示する状態のラベル（ステータスと区分）です。
+func (w *Workflow) stateLabel(state Status) string {
+	tags := w.stateTags(state)
+	if len(tags) == 0 {
+		return string(state)
+	}
+	return fmt.Sprintf("%s（%s）", state, strings.Join(tags, "・"))
+}
+
+// Mermaid はワークフローを Mermaid の状態遷移図（stateDiagram-v2）で返します。
+// 最終状態は終了の記号に接続し、区分は状態の説明として表示します。
+func (w *Workflow) Mermaid() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: %s\n---\n", w.def.Name)
+	b.WriteString("stateDiagram-v2\n")
+	for _, state := range w.def.States {
+		fmt.Fprintf(&b, "    %s : %s\n", state, w.stateLabel(state))
+	}
+	fmt.Fprintf(&b, "    [*] --> %s\n", w.def.Initial)
+	for _, state := range w.def.States {
+		for _, to := range w.def.Transitions[state] {
+			fmt.Fprintf(&b, "    %s --> %s\n", state, to)
+		}
+	}
+	for _, state := range w.def.States {
+		if w.IsFinal(state) {
+			fmt.Fprintf(&b, "    %s --> [*]\n", state)
+		}
+	}
+	return b.String()
+}
+
+// DOT はワークフローを Graphviz の DOT 言語で返します。最終状態は二重丸で表示します。
+func (w *Workflow) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", w.def.Name)
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=box, style=rounded];\n")
+	b.WriteString("    __start [shape=point, label=\"\"];\n")
+	for _, state := range w.def.States {
+		shape := ""
+		if w.IsFinal(state) {
+			shape = ", shape=doublecircle"
+		}
+		fmt.Fprintf(&b, "    %q [label=%q%s];\n", state, w.stateLabel(state), shape)
+	}
+	fmt.Fprintf(&b, "    __start -> %q;\n", w.def.Initial)
+	for _, state := range w.def.States {
+		for _, to := range w.def.Transitions[state] {
+			fmt.Fprintf(&b, "    %q -> %q;\n", state, to)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Export はワークフローを指定した形式で書き出します。空の形式はJSONです。
+func (w *Workflow) Export(format WorkflowFormat) ([]byte, error) {
+	switch format {
+	case "", WorkflowFormatJSON:
+		return json.MarshalIndent(w.Schema(), "", "  ")
+	case WorkflowFormatMermaid:
+		return []byte(w.Mermaid()), nil
+	case WorkflowFormatDOT:
+		return []byte(w.DOT()), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedWorkflowFormat, format)
+	}
+}
+
+// OrderWorkflow は店舗が選択した注文のワークフローを返します。
+// 未設定や、このバージョンに組み込まれていない名前の場合は既定のワークフローです。
+func (s *Store) OrderWorkflow() *Workflow {
+	w, err := LookupWorkflow(s.Workflow)
+	if err != nil {
+		return defaultWorkflow
+	}
+	return w
+}
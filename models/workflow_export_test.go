@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_Schema(t *testing.T) {
+	w, err := LookupWorkflow(WorkflowPaymentFirst)
+	require.NoError(t, err)
+
+	schema := w.Schema()
+	assert.Equal(t, WorkflowPaymentFirst, schema.Name)
+	assert.Equal(t, StatusCreated, schema.Initial)
+	require.Len(t, schema.States, len(w.Definition().States))
+	assert.Equal(t, WorkflowStateSchema{
+		Status: StatusCreated, Initial: true, Cancellable: true, AcceptsAdditions: true,
+		Next: []Status{StatusPreparing, StatusCancelled, StatusDeclined},
+	}, schema.States[0])
+	assert.True(t, schema.States[1].RequiresPayment)
+
+	for _, state := range schema.States {
+		assert.NotNil(t, state.Next, "最終状態も空の配列")
+		for _, to := range state.Next {
+			assert.True(t, w.CanTransition(state.Status, to))
+		}
+	}
+	assert.Contains(t, schema.Transitions, WorkflowTransitionSchema{From: StatusPickedUp, To: StatusCompleted})
+}
+
+func TestWorkflow_Export(t *testing.T) {
+	w, err := LookupWorkflow(WorkflowCounter)
+	require.NoError(t, err)
+
+	body, err := w.Export(WorkflowFormatJSON)
+	require.NoError(t, err)
+	var schema WorkflowSchema
+	require.NoError(t, json.Unmarshal(body, &schema))
+	assert.Equal(t, w.Schema(), schema)
+
+	mermaid, err := w.Export(WorkflowFormatMermaid)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(mermaid), "stateDiagram-v2\n"))
+	assert.Contains(t, string(mermaid), "    [*] --> created\n")
+	assert.Contains(t, string(mermaid), "    preparing --> served\n")
+	assert.Contains(t, string(mermaid), "    created : created（キャンセル可・追加可）\n")
+	assert.Contains(t, string(mermaid), "    completed --> [*]\n")
+
+	dot, err := w.Export(WorkflowFormatDOT)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(dot), `digraph "counter" {`))
+	assert.Contains(t, string(dot), `"completed" [label="completed（最終）", shape=doublecircle];`)
+	assert.Contains(t, string(dot), `"served" -> "completed";`)
+
+	_, err = w.Export("svg")
+	assert.ErrorIs(t, err, ErrUnsupportedWorkflowFormat)
+}
+
+func TestStore_OrderWorkflow(t *testing.T) {
+	assert.Equal(t, WorkflowDefault, (&Store{}).OrderWorkflow().Name())
+	assert.Equal(t, WorkflowCounter, (&Store{Workflow: WorkflowCounter}).OrderWorkflow().Name())
+	assert.Equal(t, WorkflowDefault, (&Store{Workflow: "removed"}).OrderWorkflow().Name(), "削除されたワークフローは標準")
+}
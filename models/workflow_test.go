@@ -0,0 +1,138 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkflow_Validation(t *testing.T) {
+	valid := func() WorkflowDefinition {
+		return WorkflowDefinition{
+			Name:    "test",
+			Initial: StatusCreated,
+			States:  []Status{StatusCreated, StatusPreparing, StatusServed, StatusCompleted, StatusCancelled},
+			Transitions: map[Status][]Status{
+				StatusCreated:   {StatusPreparing, StatusCancelled},
+				StatusPreparing: {StatusServed},
+				StatusServed:    {StatusCompleted},
+			},
+			Final:            []Status{StatusCompleted, StatusCancelled},
+			Cancellable:      []Status{StatusCreated},
+			AcceptsAdditions: []Status{StatusCreated},
+		}
+	}
+
+	w, err := NewWorkflow(valid())
+	require.NoError(t, err)
+	assert.Equal(t, "test", w.Name())
+	assert.True(t, w.CanTransition(StatusCreated, StatusPreparing))
+	assert.False(t, w.CanTransition(StatusCreated, StatusServed))
+	assert.Equal(t, []Status{StatusPreparing, StatusCancelled}, w.NextStatuses(StatusCreated))
+
+	testCases := []struct {
+		name   string
+		modify func(*WorkflowDefinition)
+	}{
+		{"名前なし", func(d *WorkflowDefinition) { d.Name = "" }},
+		{"重複した状態", func(d *WorkflowDefinition) { d.States = append(d.States, StatusCreated) }},
+		{"未定義の初期状態", func(d *WorkflowDefinition) { d.Initial = StatusConfirmed }},
+		{"未定義の遷移先", func(d *WorkflowDefinition) { d.Transitions[StatusServed] = []Status{StatusDelivered} }},
+		{"未定義の最終状態", func(d *WorkflowDefinition) { d.Final = append(d.Final, StatusRefunded) }},
+		{"キャンセルできない状態をキャンセル可能に指定", func(d *WorkflowDefinition) { d.Cancellable = []Status{StatusPreparing} }},
+		{"最終状態で追加を受け付ける", func(d *WorkflowDefinition) { d.AcceptsAdditions = []Status{StatusCompleted} }},
+		{"到達できない状態", func(d *WorkflowDefinition) {
+			d.States = append(d.States, StatusOnHold)
+			d.Transitions[StatusOnHold] = []Status{StatusPreparing}
+		}},
+		{"行き止まりの状態", func(d *WorkflowDefinition) { delete(d.Transitions, StatusServed) }},
+		{"支払いの旧ステータス", func(d *WorkflowDefinition) { d.States = append(d.States, StatusPendingPayment) }},
+		{"未定義の支払いが必要な状態", func(d *WorkflowDefinition) { d.RequiresPayment = []Status{StatusReadyForPickup} }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			def := valid()
+			tc.modify(&def)
+			_, err := NewWorkflow(def)
+			assert.ErrorIs(t, err, ErrInvalidWorkflow)
+		})
+	}
+}
+
+func TestDefaultWorkflow_MatchesStatusHelpers(t *testing.T) {
+	w := DefaultWorkflow()
+	all := w.Definition().States
+	for _, from := range all {
+		assert.Equal(t, from.IsFinal(), w.IsFinal(from), from)
+		assert.Equal(t, from.CanAddItem(), w.CanAddItem(from), from)
+		assert.Equal(t, from.CanCancel(), w.CanCancel(from), from)
+		for _, to := range all {
+			assert.Equal(t, from.CanTransitionTo(to), w.CanTransition(from, to), "%s -> %s", from, to)
+		}
+	}
+}
+
+func TestLookupWorkflow(t *testing.T) {
+	w, err := LookupWorkflow("")
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowDefault, w.Name())
+
+	for _, name := range WorkflowNames() {
+		w, err := LookupWorkflow(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, w.Name())
+	}
+	assert.Equal(t, []string{WorkflowCounter, WorkflowDefault, WorkflowPaymentFirst}, WorkflowNames())
+
+	_, err = LookupWorkflow("unknown")
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+}
+
+func TestSession_CounterWorkflow(t *testing.T) {
+	session, err := NewSession("store_1", "seat_1", []Order{*NewOrder("ramen", 1, Yen(900))})
+	require.NoError(t, err)
+	require.NoError(t, session.SetWorkflow(WorkflowCounter))
+	assert.Equal(t, WorkflowCounter, session.WorkflowName)
+
+	// 既定のワークフローでは許可されない created → preparing が許可される
+	assert.ErrorAs(t, session.UpdateStatus(StatusConfirmed), new(*InvalidStatusTransitionError))
+	require.NoError(t, session.UpdateStatus(StatusPreparing))
+
+	// 調理中は追加を受け付けない
+	assert.ErrorAs(t, session.AddItem(*NewOrder("gyoza", 1, Yen(400))), new(*CannotAddItemError))
+
+	// 遷移が始まった注文のワークフローは変更できない
+	assert.ErrorIs(t, session.SetWorkflow(WorkflowDefault), ErrWorkflowAlreadyStarted)
+
+	require.NoError(t, session.UpdateStatus(StatusServed))
+	require.NoError(t, session.UpdateStatus(StatusCompleted))
+	assert.ErrorIs(t, session.UpdateStatus(StatusCancelled), ErrOrderAlreadyFinal)
+}
+
+func TestSession_PaymentFirstWorkflow(t *testing.T) {
+	session, err := NewSession("store_1", "seat_1", []Order{*NewOrder("latte", 1, Yen(500))})
+	require.NoError(t, err)
+	require.NoError(t, session.SetWorkflow(WorkflowPaymentFirst))
+
+	// 支払いが完了するまで調理を始められない
+	assert.ErrorIs(t, session.UpdateStatus(StatusPreparing), ErrPaymentRequired)
+
+	require.NoError(t, session.StartPayment())
+	_, err = session.ApplyPaymentStatus(PaymentIntentFailed)
+	require.NoError(t, err)
+	assert.ErrorIs(t, session.UpdateStatus(StatusPreparing), ErrPaymentRequired)
+
+	// 支払いに失敗しても再度支払いを受け付ける
+	require.NoError(t, session.StartPayment())
+	_, err = session.ApplyPaymentStatus(PaymentIntentSucceeded)
+	require.NoError(t, err)
+	require.NoError(t, session.UpdateStatus(StatusPreparing))
+	assert.False(t, session.Workflow().CanCancel(session.Status))
+}
+
+func TestSession_UnknownWorkflowFallsBackToDefault(t *testing.T) {
+	session := &Session{WorkflowName: "removed"}
+	assert.Equal(t, WorkflowDefault, session.Workflow().Name())
+	assert.ErrorIs(t, session.SetWorkflow("removed"), ErrWorkflowNotFound)
+}
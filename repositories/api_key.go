@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// APIKeyRepository は Firestore の api_keys コレクションを操作するためのリポジトリです。
+// キー本体は保存せず、ハッシュ値（hashed_key）で検索します。
+type APIKeyRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewAPIKeyRepository は新しい APIKeyRepository のインスタンスを生成します。
+func NewAPIKeyRepository(client *firestore.Client) Repository[models.APIKey] {
+	if client == nil {
+		return NewMockAPIKeyRepository()
+	}
+	return &APIKeyRepository{
+		client:     client,
+		collection: "api_keys",
+	}
+}
+
+type APIKey struct {
+	ID             string    `firestore:"id"`
+	Name           string    `firestore:"name"`
+	Prefix         string    `firestore:"prefix"`
+	HashedKey      string    `firestore:"hashed_key"`
+	Scope          string    `firestore:"scope"`
+	StoreID        string    `firestore:"store_id"`
+	OrganizationID string    `firestore:"organization_id"`
+	Permissions    []string  `firestore:"permissions"`
+	ExpiresAt      time.Time `firestore:"expires_at"`
+	LastUsedAt     time.Time `firestore:"last_used_at"`
+	RevokedAt      time.Time `firestore:"revoked_at"`
+	CreatedAt      time.Time `firestore:"created_at"`
+	UpdatedAt      time.Time `firestore:"updated_at"`
+}
+
+func ToSetAPIKey(key *models.APIKey) *APIKey {
+	permissions := make([]string, len(key.Permissions))
+	for i, permission := range key.Permissions {
+		permissions[i] = string(permission)
+	}
+
+	return &APIKey{
+		ID:             key.ID,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		HashedKey:      key.HashedKey,
+		Scope:          string(key.Scope),
+		StoreID:        key.StoreID,
+		OrganizationID: key.OrganizationID,
+		Permissions:    permissions,
+		ExpiresAt:      key.ExpiresAt,
+		LastUsedAt:     key.LastUsedAt,
+		RevokedAt:      key.RevokedAt,
+		CreatedAt:      key.CreatedAt,
+		UpdatedAt:      key.UpdatedAt,
+	}
+}
+
+func (k *APIKey) ToModel() *models.APIKey {
+	permissions := make([]models.Permission, len(k.Permissions))
+	for i, permission := range k.Permissions {
+		permissions[i] = models.Permission(permission)
+	}
+
+	return &models.APIKey{
+		ID:             k.ID,
+		Name:           k.Name,
+		Prefix:         k.Prefix,
+		HashedKey:      k.HashedKey,
+		Scope:          models.APIKeyScope(k.Scope),
+		StoreID:        k.StoreID,
+		OrganizationID: k.OrganizationID,
+		Permissions:    permissions,
+		ExpiresAt:      k.ExpiresAt,
+		LastUsedAt:     k.LastUsedAt,
+		RevokedAt:      k.RevokedAt,
+		CreatedAt:      k.CreatedAt,
+		UpdatedAt:      k.UpdatedAt,
+	}
+}
+
+// Create は新しいAPIキーを Firestore に作成します。
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(key.ID).Set(ctx, ToSetAPIKey(key))
+	return err
+}
+
+// Read はすべてのAPIキーを Firestore から読み取ります。
+func (r *APIKeyRepository) Read(ctx context.Context) ([]*models.APIKey, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*models.APIKey, len(docs))
+	for i, doc := range docs {
+		key := &APIKey{}
+		if err := doc.DataTo(key); err != nil {
+			return nil, err
+		}
+		keys[i] = key.ToModel()
+	}
+
+	return keys, nil
+}
+
+// FindByID は指定されたIDのAPIキーを Firestore から検索します。
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*models.APIKey, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &APIKey{}
+	if err := doc.DataTo(key); err != nil {
+		return nil, err
+	}
+
+	return key.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致するAPIキーを Firestore から検索します。
+func (r *APIKeyRepository) FindByField(ctx context.Context, field string, value any) ([]*models.APIKey, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*models.APIKey, len(docs))
+	for i, doc := range docs {
+		key := &APIKey{}
+		if err := doc.DataTo(key); err != nil {
+			return nil, err
+		}
+		keys[i] = key.ToModel()
+	}
+
+	return keys, nil
+}
+
+// UpdateByID は指定されたIDのAPIキーを Firestore で更新します。
+func (r *APIKeyRepository) UpdateByID(ctx context.Context, id string, key *models.APIKey) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetAPIKey(key))
+	return err
+}
+
+// DeleteByID は指定されたIDのAPIキーを Firestore から削除します。
+func (r *APIKeyRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されているAPIキーの総数を返します。
+func (r *APIKeyRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDのAPIキーが Firestore に存在するかどうかを確認します。
+func (r *APIKeyRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository - 実際のFirestoreの複雑な実装は不要
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func NewMockAPIKeyRepository() Repository[models.APIKey] {
+	return &MockAPIKeyRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Read(ctx context.Context) ([]*models.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.APIKey{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.APIKey), nil
+}
+
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id string) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), nil
+}
+
+func (m *MockAPIKeyRepository) FindByField(ctx context.Context, field string, value any) ([]*models.APIKey, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.APIKey{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.APIKey), nil
+}
+
+func (m *MockAPIKeyRepository) UpdateByID(ctx context.Context, id string, key *models.APIKey) error {
+	args := m.Called(ctx, id, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockAPIKeyRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewAPIKeyRepository tests the NewAPIKeyRepository function
+func TestNewAPIKeyRepository(t *testing.T) {
+	t.Run("NewAPIKeyRepository with nil client returns MockAPIKeyRepository", func(t *testing.T) {
+		repo := NewAPIKeyRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockAPIKeyRepository)
+		assert.True(t, ok, "Should return a MockAPIKeyRepository when client is nil")
+	})
+}
+
+// TestMockAPIKeyRepository tests the MockAPIKeyRepository implementation
+func TestMockAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	testKey := &models.APIKey{ID: "apikey_123", HashedKey: "hash", Scope: models.APIKeyScopeOrganization}
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockAPIKeyRepository{}
+		mockRepo.On("FindByField", mock.Anything, "hashed_key", "hash").Return([]*models.APIKey{testKey}, nil)
+
+		keys, err := mockRepo.FindByField(ctx, "hashed_key", "hash")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, testKey.ID, keys[0].ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		mockRepo := &MockAPIKeyRepository{}
+		mockRepo.On("Create", mock.Anything, testKey).Return(nil)
+
+		assert.NoError(t, mockRepo.Create(ctx, testKey))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestAPIKeyStruct tests the APIKey struct conversions
+func TestAPIKeyStruct(t *testing.T) {
+	now := time.Now()
+	testKey := &models.APIKey{
+		ID:             "apikey_123",
+		Name:           "Accounting export",
+		Prefix:         "posk_abcdefgh",
+		HashedKey:      "hash",
+		Scope:          models.APIKeyScopeStore,
+		StoreID:        "store_123",
+		OrganizationID: "manager@example.com",
+		Permissions:    []models.Permission{models.PermissionOrdersRead, models.PermissionStoresRead},
+		ExpiresAt:      now.Add(24 * time.Hour),
+		LastUsedAt:     now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	repoKey := ToSetAPIKey(testKey)
+	assert.Equal(t, []string{"orders:read", "stores:read"}, repoKey.Permissions)
+	assert.Equal(t, "store", repoKey.Scope)
+	assert.Equal(t, testKey, repoKey.ToModel())
+}
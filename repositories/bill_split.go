@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// BillSplitRepository は Firestore の bill_splits コレクションを操作するためのリポジトリです。
+type BillSplitRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewBillSplitRepository は新しい BillSplitRepository のインスタンスを生成します。
+func NewBillSplitRepository(client *firestore.Client) Repository[models.BillSplit] {
+	if client == nil {
+		return NewMockBillSplitRepository()
+	}
+	return &BillSplitRepository{
+		client:     client,
+		collection: "bill_splits",
+	}
+}
+
+type BillSplit struct {
+	ID         string   `firestore:"id"`
+	StoreID    string   `firestore:"store_id"`
+	SeatID     string   `firestore:"seat_id"`
+	VisitID    string   `firestore:"visit_id"`
+	SessionIDs []string `firestore:"session_ids"`
+	Method     string   `firestore:"method"`
+	Status     string   `firestore:"status"`
+
+	Currency string    `firestore:"currency"`
+	Total    int64     `firestore:"total"`
+	Taxes    []TaxLine `firestore:"taxes"`
+	SubBills []SubBill `firestore:"sub_bills"`
+
+	Remainder      int64     `firestore:"remainder"`
+	RemainderTaxes []TaxLine `firestore:"remainder_taxes"`
+
+	SettledAt time.Time `firestore:"settled_at"`
+	CreatedAt time.Time `firestore:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+type SubBill struct {
+	ID         string    `firestore:"id"`
+	Label      string    `firestore:"label"`
+	LineIDs    []string  `firestore:"line_ids"`
+	Amount     int64     `firestore:"amount"`
+	Taxes      []TaxLine `firestore:"taxes"`
+	Paid       bool      `firestore:"paid"`
+	Method     string    `firestore:"method"`
+	PaymentRef string    `firestore:"payment_ref"`
+	PaidAt     time.Time `firestore:"paid_at"`
+}
+
+func ToSetBillSplit(b *models.BillSplit) *BillSplit {
+	subBills := make([]SubBill, len(b.SubBills))
+	for i, s := range b.SubBills {
+		subBills[i] = SubBill{
+			ID:         s.ID,
+			Label:      s.Label,
+			LineIDs:    s.LineIDs,
+			Amount:     s.Amount.Amount,
+			Taxes:      ToSetTaxLines(s.Taxes),
+			Paid:       s.Paid,
+			Method:     string(s.Method),
+			PaymentRef: s.PaymentRef,
+			PaidAt:     s.PaidAt,
+		}
+	}
+
+	return &BillSplit{
+		ID:         b.ID,
+		StoreID:    b.StoreID,
+		SeatID:     b.SeatID,
+		VisitID:    b.VisitID,
+		SessionIDs: b.SessionIDs,
+		Method:     string(b.Method),
+		Status:     string(b.Status),
+
+		Currency: string(b.Total.Currency),
+		Total:    b.Total.Amount,
+		Taxes:    ToSetTaxLines(b.Taxes),
+		SubBills: subBills,
+
+		Remainder:      b.Remainder.Amount,
+		RemainderTaxes: ToSetTaxLines(b.RemainderTaxes),
+
+		SettledAt: b.SettledAt,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+func (b *BillSplit) ToModel() *models.BillSplit {
+	subBills := make([]models.SubBill, len(b.SubBills))
+	for i, s := range b.SubBills {
+		subBills[i] = models.SubBill{
+			ID:         s.ID,
+			Label:      s.Label,
+			LineIDs:    s.LineIDs,
+			Amount:     ToModelMoney(s.Amount, b.Currency),
+			Taxes:      ToModelTaxLines(s.Taxes, b.Currency),
+			Paid:       s.Paid,
+			Method:     models.TenderMethod(s.Method),
+			PaymentRef: s.PaymentRef,
+			PaidAt:     s.PaidAt,
+		}
+	}
+
+	return &models.BillSplit{
+		ID:         b.ID,
+		StoreID:    b.StoreID,
+		SeatID:     b.SeatID,
+		VisitID:    b.VisitID,
+		SessionIDs: b.SessionIDs,
+		Method:     models.SplitMethod(b.Method),
+		Status:     models.BillSplitStatus(b.Status),
+
+		Total:    ToModelMoney(b.Total, b.Currency),
+		Taxes:    ToModelTaxLines(b.Taxes, b.Currency),
+		SubBills: subBills,
+
+		Remainder:      ToModelMoney(b.Remainder, b.Currency),
+		RemainderTaxes: ToModelTaxLines(b.RemainderTaxes, b.Currency),
+
+		SettledAt: b.SettledAt,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+// Create は新しい割り勘を Firestore に作成します。
+func (r *BillSplitRepository) Create(ctx context.Context, billSplit *models.BillSplit) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(billSplit.ID).Set(ctx, ToSetBillSplit(billSplit))
+	return err
+}
+
+// Read はすべての割り勘を Firestore から読み取ります。
+func (r *BillSplitRepository) Read(ctx context.Context) ([]*models.BillSplit, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	billSplits := make([]*models.BillSplit, len(docs))
+	for i, doc := range docs {
+		billSplit := &BillSplit{}
+		if err := doc.DataTo(billSplit); err != nil {
+			return nil, err
+		}
+		billSplits[i] = billSplit.ToModel()
+	}
+
+	return billSplits, nil
+}
+
+// FindByID は指定されたIDの割り勘を Firestore から検索します。
+func (r *BillSplitRepository) FindByID(ctx context.Context, id string) (*models.BillSplit, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	billSplit := &BillSplit{}
+	if err := doc.DataTo(billSplit); err != nil {
+		return nil, err
+	}
+
+	return billSplit.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する割り勘を Firestore から検索します。
+func (r *BillSplitRepository) FindByField(ctx context.Context, field string, value any) ([]*models.BillSplit, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	billSplits := make([]*models.BillSplit, len(docs))
+	for i, doc := range docs {
+		billSplit := &BillSplit{}
+		if err := doc.DataTo(billSplit); err != nil {
+			return nil, err
+		}
+		billSplits[i] = billSplit.ToModel()
+	}
+
+	return billSplits, nil
+}
+
+// UpdateByID は指定されたIDの割り勘を Firestore で更新します。
+func (r *BillSplitRepository) UpdateByID(ctx context.Context, id string, billSplit *models.BillSplit) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetBillSplit(billSplit))
+	return err
+}
+
+// DeleteByID は指定されたIDの割り勘を Firestore から削除します。
+func (r *BillSplitRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている割り勘の総数を返します。
+func (r *BillSplitRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの割り勘が Firestore に存在するかどうかを確認します。
+func (r *BillSplitRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
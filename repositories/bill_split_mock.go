@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBillSplitRepository - 実際のFirestoreの複雑な実装は不要
+type MockBillSplitRepository struct {
+	mock.Mock
+}
+
+func NewMockBillSplitRepository() Repository[models.BillSplit] {
+	return &MockBillSplitRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockBillSplitRepository) Create(ctx context.Context, billSplit *models.BillSplit) error {
+	args := m.Called(ctx, billSplit)
+	return args.Error(0)
+}
+
+func (m *MockBillSplitRepository) Read(ctx context.Context) ([]*models.BillSplit, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.BillSplit{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.BillSplit), nil
+}
+
+func (m *MockBillSplitRepository) FindByID(ctx context.Context, id string) (*models.BillSplit, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BillSplit), nil
+}
+
+func (m *MockBillSplitRepository) FindByField(ctx context.Context, field string, value any) ([]*models.BillSplit, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.BillSplit{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.BillSplit), nil
+}
+
+func (m *MockBillSplitRepository) UpdateByID(ctx context.Context, id string, billSplit *models.BillSplit) error {
+	args := m.Called(ctx, id, billSplit)
+	return args.Error(0)
+}
+
+func (m *MockBillSplitRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBillSplitRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockBillSplitRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
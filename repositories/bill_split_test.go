@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewBillSplitRepository tests the NewBillSplitRepository function
+func TestNewBillSplitRepository(t *testing.T) {
+	t.Run("NewBillSplitRepository with nil client returns MockBillSplitRepository", func(t *testing.T) {
+		repo := NewBillSplitRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockBillSplitRepository)
+		assert.True(t, ok, "Should return a MockBillSplitRepository when client is nil")
+	})
+}
+
+// TestMockBillSplitRepository tests the MockBillSplitRepository implementation
+func TestMockBillSplitRepository(t *testing.T) {
+	ctx := context.Background()
+	testSplit := &models.BillSplit{ID: "split_123", StoreID: "store_123", VisitID: "visit_123", Status: models.BillSplitOpen}
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockBillSplitRepository{}
+		mockRepo.On("FindByField", mock.Anything, "visit_id", "visit_123").Return([]*models.BillSplit{testSplit}, nil)
+
+		splits, err := mockRepo.FindByField(ctx, "visit_id", "visit_123")
+		assert.NoError(t, err)
+		assert.Len(t, splits, 1)
+		assert.Equal(t, testSplit.ID, splits[0].ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateByID", func(t *testing.T) {
+		mockRepo := &MockBillSplitRepository{}
+		mockRepo.On("UpdateByID", mock.Anything, "split_123", testSplit).Return(nil)
+
+		assert.NoError(t, mockRepo.UpdateByID(ctx, "split_123", testSplit))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestBillSplitStruct tests the BillSplit struct conversions
+func TestBillSplitStruct(t *testing.T) {
+	now := time.Now().UTC()
+	standard := models.TaxRate{Code: models.TaxRateStandard, Percent: 10}
+	testSplit := &models.BillSplit{
+		ID:         "split_123",
+		StoreID:    "store_123",
+		SeatID:     "seat_123",
+		VisitID:    "visit_123",
+		SessionIDs: []string{"session_1", "session_2"},
+		Method:     models.SplitByAmounts,
+		Status:     models.BillSplitOpen,
+		Total:      models.Yen(3300),
+		Taxes: []models.TaxLine{
+			{Rate: standard, Net: models.Yen(3000), Tax: models.Yen(300), Gross: models.Yen(3300)},
+		},
+		SubBills: []models.SubBill{
+			{
+				ID:     "subbill_1",
+				Label:  "Aさん",
+				Amount: models.Yen(2200),
+				Taxes: []models.TaxLine{
+					{Rate: standard, Net: models.Yen(2000), Tax: models.Yen(200), Gross: models.Yen(2200)},
+				},
+				Paid:       true,
+				PaymentRef: "pay_1",
+				PaidAt:     now,
+			},
+		},
+		Remainder: models.Yen(1100),
+		RemainderTaxes: []models.TaxLine{
+			{Rate: standard, Net: models.Yen(1000), Tax: models.Yen(100), Gross: models.Yen(1100)},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	repoSplit := ToSetBillSplit(testSplit)
+	assert.Equal(t, "JPY", repoSplit.Currency)
+	assert.Equal(t, int64(1100), repoSplit.Remainder)
+	assert.Equal(t, testSplit, repoSplit.ToModel())
+}
@@ -0,0 +1,196 @@
+package repositories
+
+// bill_split_update.go は割り勘の読み取りから更新までを不可分に行う更新を実装します。
+// 同じ伝票の支払いを同時に記録しても二重に記録しないよう、割り勘と来店の注文を Firestore のトランザクションで読み取り、
+// 割り勘と注文の支払い状態をまとめて書き込みます。
+// 割り勘のやり直しは、以前の割り勘の無効化と新しい割り勘の作成を同じトランザクションで行います。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// BillSplitUpdater は割り勘を読み取ってから更新するまでを不可分に行うストアです。
+type BillSplitUpdater interface {
+	// Update は id の割り勘と、割り勘の来店の注文を読み取り、update で変更した割り勘と update が返した注文を保存します。
+	// update がエラーを返した場合は何も保存せずにそのエラーを返します。割り勘がない場合は codes.NotFound のエラーを返します。
+	// 他の更新と競合した場合、update は最新の割り勘と注文で再度呼び出されることがあります。
+	Update(ctx context.Context, id string, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error)
+
+	// UpdateOpen は Update と同じトランザクションで closeID のレジ締めを読み取り、レジ締め済みの場合は
+	// models.ErrBusinessDayClosed を返して保存しません。伝票の支払いなど営業日の売上に含まれる更新に使用します。
+	UpdateOpen(ctx context.Context, closeID, id string, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error)
+
+	// Replace は split.VisitID の来店の割り勘を読み取り、void が返した（無効にした）割り勘と新しい割り勘 split を保存します。
+	// void がエラーを返した場合は何も保存せずにそのエラーを返します。
+	Replace(ctx context.Context, split *models.BillSplit, void func([]*models.BillSplit) ([]*models.BillSplit, error)) error
+}
+
+// NewBillSplitUpdater は BillSplitUpdater を生成します。
+// client が nil の場合は splits と sessions をプロセス内の排他制御で更新するストアを返します。
+func NewBillSplitUpdater(client *firestore.Client, splits Repository[models.BillSplit], sessions Repository[models.Session], closes Repository[models.RegisterClose]) BillSplitUpdater {
+	if client == nil {
+		return NewMemoryBillSplitUpdater(splits, sessions, closes)
+	}
+	return &FirestoreBillSplitUpdater{
+		client:     client,
+		collection: "bill_splits",
+		sessions:   "sessions",
+	}
+}
+
+// FirestoreBillSplitUpdater は Firestore の "bill_splits" コレクションをトランザクションで更新する BillSplitUpdater です。
+// 来店の注文は "sessions" コレクションから読み取り、更新します。
+type FirestoreBillSplitUpdater struct {
+	client     *firestore.Client
+	collection string
+	sessions   string
+}
+
+// Update はトランザクション内で割り勘と来店の注文を読み取り、更新します。
+func (r *FirestoreBillSplitUpdater) Update(ctx context.Context, id string, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error) {
+	return r.UpdateOpen(ctx, "", id, update)
+}
+
+// UpdateOpen はトランザクション内でレジ締めと割り勘と来店の注文を読み取り、更新します。
+func (r *FirestoreBillSplitUpdater) UpdateOpen(ctx context.Context, closeID, id string, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(id)
+	sessions := r.client.Collection(GetCollectionName(r.sessions))
+
+	var split *models.BillSplit
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := ensureOpenInTx(tx, r.client, closeID); err != nil {
+			return err
+		}
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		stored := &BillSplit{}
+		if err := doc.DataTo(stored); err != nil {
+			return err
+		}
+		split = stored.ToModel()
+
+		orders, err := getAllInTx[Session, models.Session](tx, sessions.Where("visit_id", "==", split.VisitID))
+		if err != nil {
+			return err
+		}
+
+		changed, err := update(split, orders)
+		if err != nil {
+			return err
+		}
+		for _, order := range changed {
+			if err := tx.Set(sessions.Doc(order.ID), ToSetSession(order)); err != nil {
+				return err
+			}
+		}
+		return tx.Set(ref, ToSetBillSplit(split))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return split, nil
+}
+
+// Replace はトランザクション内で来店の割り勘を読み取り、無効にした割り勘の更新と新しい割り勘の作成を行います。
+func (r *FirestoreBillSplitUpdater) Replace(ctx context.Context, split *models.BillSplit, void func([]*models.BillSplit) ([]*models.BillSplit, error)) error {
+	splits := r.client.Collection(GetCollectionName(r.collection))
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		existing, err := getAllInTx[BillSplit, models.BillSplit](tx, splits.Where("visit_id", "==", split.VisitID))
+		if err != nil {
+			return err
+		}
+
+		voided, err := void(existing)
+		if err != nil {
+			return err
+		}
+		for _, e := range voided {
+			if err := tx.Set(splits.Doc(e.ID), ToSetBillSplit(e)); err != nil {
+				return err
+			}
+		}
+		return tx.Create(splits.Doc(split.ID), ToSetBillSplit(split))
+	})
+}
+
+// MemoryBillSplitUpdater はプロセス内の排他制御で割り勘を更新する BillSplitUpdater です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryBillSplitUpdater struct {
+	mu       sync.Mutex
+	splits   Repository[models.BillSplit]
+	sessions Repository[models.Session]
+	closes   Repository[models.RegisterClose]
+}
+
+func NewMemoryBillSplitUpdater(splits Repository[models.BillSplit], sessions Repository[models.Session], closes Repository[models.RegisterClose]) *MemoryBillSplitUpdater {
+	return &MemoryBillSplitUpdater{
+		splits:   splits,
+		sessions: sessions,
+		closes:   closes,
+	}
+}
+
+// Update は割り勘と来店の注文を読み取り、更新します。
+func (s *MemoryBillSplitUpdater) Update(ctx context.Context, id string, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error) {
+	return s.UpdateOpen(ctx, "", id, update)
+}
+
+// UpdateOpen はレジ締めと割り勘と来店の注文を読み取り、更新します。
+func (s *MemoryBillSplitUpdater) UpdateOpen(ctx context.Context, closeID, id string, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ensureOpen(ctx, s.closes, closeID); err != nil {
+		return nil, err
+	}
+	split, err := s.splits.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.sessions.FindByField(ctx, "visit_id", split.VisitID)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := update(split, orders)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range changed {
+		if err := s.sessions.UpdateByID(ctx, order.ID, order); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.splits.UpdateByID(ctx, id, split); err != nil {
+		return nil, err
+	}
+	return split, nil
+}
+
+// Replace は来店の割り勘を読み取り、無効にした割り勘の更新と新しい割り勘の作成を行います。
+func (s *MemoryBillSplitUpdater) Replace(ctx context.Context, split *models.BillSplit, void func([]*models.BillSplit) ([]*models.BillSplit, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.splits.FindByField(ctx, "visit_id", split.VisitID)
+	if err != nil {
+		return err
+	}
+	voided, err := void(existing)
+	if err != nil {
+		return err
+	}
+	for _, e := range voided {
+		if err := s.splits.UpdateByID(ctx, e.ID, e); err != nil {
+			return err
+		}
+	}
+	return s.splits.Create(ctx, split)
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewBillSplitUpdater tests the NewBillSplitUpdater function
+func TestNewBillSplitUpdater(t *testing.T) {
+	t.Run("nil client returns memory updater", func(t *testing.T) {
+		_, ok := NewBillSplitUpdater(nil, NewMockBillSplitRepository(), NewMockSessionRepository(), NewMockRegisterCloseRepository()).(*MemoryBillSplitUpdater)
+		assert.True(t, ok, "Should return a MemoryBillSplitUpdater when client is nil")
+	})
+}
+
+// TestMemoryBillSplitUpdater_Update tests that the split and the returned orders are saved together
+func TestMemoryBillSplitUpdater_Update(t *testing.T) {
+	ctx := context.Background()
+	split := &models.BillSplit{ID: "split_1", VisitID: "visit_1"}
+	orders := []*models.Session{{ID: "session_1", VisitID: "visit_1"}, {ID: "session_2", VisitID: "visit_1"}}
+
+	t.Run("save the split and changed orders", func(t *testing.T) {
+		splits := NewMockBillSplitRepository().(*MockBillSplitRepository)
+		splits.On("FindByID", ctx, "split_1").Return(split, nil)
+		splits.On("UpdateByID", ctx, "split_1", split).Return(nil)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		sessions.On("FindByField", ctx, "visit_id", "visit_1").Return(orders, nil)
+		sessions.On("UpdateByID", ctx, "session_1", orders[0]).Return(nil)
+
+		_, err := NewMemoryBillSplitUpdater(splits, sessions, NewMockRegisterCloseRepository()).Update(ctx, "split_1", func(_ *models.BillSplit, orders []*models.Session) ([]*models.Session, error) {
+			return orders[:1], nil
+		})
+		require.NoError(t, err)
+		sessions.AssertNotCalled(t, "UpdateByID", ctx, "session_2", mock.Anything)
+		splits.AssertExpectations(t)
+	})
+
+	t.Run("update error saves nothing", func(t *testing.T) {
+		splits := NewMockBillSplitRepository().(*MockBillSplitRepository)
+		splits.On("FindByID", ctx, "split_1").Return(split, nil)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		sessions.On("FindByField", ctx, "visit_id", "visit_1").Return(orders, nil)
+
+		_, err := NewMemoryBillSplitUpdater(splits, sessions, NewMockRegisterCloseRepository()).Update(ctx, "split_1", func(*models.BillSplit, []*models.Session) ([]*models.Session, error) {
+			return nil, models.ErrSubBillAlreadyPaid
+		})
+		assert.ErrorIs(t, err, models.ErrSubBillAlreadyPaid)
+		splits.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		sessions.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("closed business day saves nothing", func(t *testing.T) {
+		splits := NewMockBillSplitRepository().(*MockBillSplitRepository)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		closes := NewMockRegisterCloseRepository().(*MockRegisterCloseRepository)
+		closes.On("Exists", ctx, "store_1_2026-10-17").Return(true, nil)
+
+		_, err := NewMemoryBillSplitUpdater(splits, sessions, closes).UpdateOpen(ctx, "store_1_2026-10-17", "split_1", func(*models.BillSplit, []*models.Session) ([]*models.Session, error) {
+			return nil, nil
+		})
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+		splits.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestMemoryBillSplitUpdater_Replace tests that the new split is created only when the previous splits can be voided
+func TestMemoryBillSplitUpdater_Replace(t *testing.T) {
+	ctx := context.Background()
+	previous := &models.BillSplit{ID: "split_old", VisitID: "visit_1"}
+	split := &models.BillSplit{ID: "split_new", VisitID: "visit_1"}
+
+	t.Run("void and create", func(t *testing.T) {
+		splits := NewMockBillSplitRepository().(*MockBillSplitRepository)
+		splits.On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.BillSplit{previous}, nil)
+		splits.On("UpdateByID", ctx, "split_old", previous).Return(nil)
+		splits.On("Create", ctx, split).Return(nil)
+
+		err := NewMemoryBillSplitUpdater(splits, NewMockSessionRepository(), NewMockRegisterCloseRepository()).Replace(ctx, split, func(existing []*models.BillSplit) ([]*models.BillSplit, error) {
+			return existing, nil
+		})
+		require.NoError(t, err)
+		splits.AssertExpectations(t)
+	})
+
+	t.Run("void error creates nothing", func(t *testing.T) {
+		splits := NewMockBillSplitRepository().(*MockBillSplitRepository)
+		splits.On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.BillSplit{previous}, nil)
+
+		err := NewMemoryBillSplitUpdater(splits, NewMockSessionRepository(), NewMockRegisterCloseRepository()).Replace(ctx, split, func([]*models.BillSplit) ([]*models.BillSplit, error) {
+			return nil, errors.New("in progress")
+		})
+		assert.Error(t, err)
+		splits.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsNotFound は Firestore から返されたエラーがドキュメント未検出を示すかどうかを判定します。
+func IsNotFound(err error) bool {
+	return err != nil && status.Code(err) == codes.NotFound
+}
+
+// IsAlreadyExists は Firestore から返されたエラーが同じIDのドキュメントが作成済みであることを示すかどうかを判定します。
+func IsAlreadyExists(err error) bool {
+	return err != nil && status.Code(err) == codes.AlreadyExists
+}
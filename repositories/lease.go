@@ -0,0 +1,110 @@
+package repositories
+
+// lease.go は定期処理を複数インスタンスで重複して実行しないためのリース（期限付きのロック）を実装します。
+// Cloud Run で複数インスタンスが起動しても1つのインスタンスだけが処理するよう、Firestore のトランザクションで取得します。
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// LeaseStore は key ごとのリースを保持するストアです。
+type LeaseStore interface {
+	// Acquire は holder が key のリースを ttl の間取得します。
+	// 他の holder が期限内のリースを保持している場合は false を返します。保持中の holder は期限を延長できます。
+	Acquire(ctx context.Context, key, holder string, ttl time.Duration, now time.Time) (bool, error)
+}
+
+// NewLeaseStore は LeaseStore を生成します。
+// client が nil の場合はプロセス内のメモリで保持するストアを返します。
+func NewLeaseStore(client *firestore.Client) LeaseStore {
+	if client == nil {
+		return NewMemoryLeaseStore()
+	}
+	return &FirestoreLeaseStore{
+		client:     client,
+		collection: "leases",
+	}
+}
+
+// FirestoreLeaseStore は Firestore の "leases" コレクションを使用する LeaseStore です。
+type FirestoreLeaseStore struct {
+	client     *firestore.Client
+	collection string
+}
+
+type Lease struct {
+	Key       string    `firestore:"key"`
+	Holder    string    `firestore:"holder"`
+	ExpiresAt time.Time `firestore:"expires_at"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+// canAcquire は holder がリースを取得できるかどうかを返します。
+func (l *Lease) canAcquire(holder string, now time.Time) bool {
+	return l.Holder == "" || l.Holder == holder || !now.Before(l.ExpiresAt)
+}
+
+// Acquire はトランザクション内でリースを取得します。
+func (r *FirestoreLeaseStore) Acquire(ctx context.Context, key, holder string, ttl time.Duration, now time.Time) (bool, error) {
+	// key はユースケース側で組み立てる値（"expiry_sweeper" など）のため、そのままドキュメントIDに使用する
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(key)
+
+	acquired := false
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		acquired = false
+		lease := Lease{Key: key}
+		doc, err := tx.Get(ref)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&lease); err != nil {
+				return err
+			}
+		}
+		if !lease.canAcquire(holder, now) {
+			return nil
+		}
+
+		lease.Holder = holder
+		lease.ExpiresAt = now.Add(ttl).UTC()
+		lease.UpdatedAt = now.UTC()
+		acquired = true
+		return tx.Set(ref, &lease)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// MemoryLeaseStore はプロセス内のメモリでリースを保持する LeaseStore です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]Lease
+}
+
+func NewMemoryLeaseStore() *MemoryLeaseStore {
+	return &MemoryLeaseStore{
+		leases: make(map[string]Lease),
+	}
+}
+
+// Acquire は key のリースを取得します。
+func (s *MemoryLeaseStore) Acquire(ctx context.Context, key, holder string, ttl time.Duration, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lease := s.leases[key]
+	if !lease.canAcquire(holder, now) {
+		return false, nil
+	}
+	s.leases[key] = Lease{Key: key, Holder: holder, ExpiresAt: now.Add(ttl).UTC(), UpdatedAt: now.UTC()}
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewLeaseStore tests the NewLeaseStore function
+func TestNewLeaseStore(t *testing.T) {
+	t.Run("nil client returns memory store", func(t *testing.T) {
+		_, ok := NewLeaseStore(nil).(*MemoryLeaseStore)
+		assert.True(t, ok, "Should return a MemoryLeaseStore when client is nil")
+	})
+}
+
+// TestMemoryLeaseStore tests the in-memory lease
+func TestMemoryLeaseStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("only one holder within ttl", func(t *testing.T) {
+		store := NewMemoryLeaseStore()
+
+		ok, err := store.Acquire(ctx, "expiry_sweeper", "instance_a", time.Minute, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = store.Acquire(ctx, "expiry_sweeper", "instance_b", time.Minute, now.Add(30*time.Second))
+		require.NoError(t, err)
+		assert.False(t, ok, "期限内は他のインスタンスが取得できない")
+
+		ok, err = store.Acquire(ctx, "expiry_sweeper", "instance_a", time.Minute, now.Add(30*time.Second))
+		require.NoError(t, err)
+		assert.True(t, ok, "保持中のインスタンスは延長できる")
+	})
+
+	t.Run("expired lease can be taken over", func(t *testing.T) {
+		store := NewMemoryLeaseStore()
+
+		_, err := store.Acquire(ctx, "expiry_sweeper", "instance_a", time.Minute, now)
+		require.NoError(t, err)
+
+		ok, err := store.Acquire(ctx, "expiry_sweeper", "instance_b", time.Minute, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "backend/models"
+
+// ToModelMoney は Firestore に保存した補助単位の整数と通貨コードから金額を復元します。
+// 通貨コードが保存されていない既存のドキュメントは既定の通貨（JPY）として扱います。
+func ToModelMoney(amount int64, currency string) models.Money {
+	return models.NewMoney(amount, models.Currency(currency))
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// PaymentRepository は Firestore の payments コレクションを操作するためのリポジトリです。
+type PaymentRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewPaymentRepository は新しい PaymentRepository のインスタンスを生成します。
+func NewPaymentRepository(client *firestore.Client) Repository[models.Payment] {
+	if client == nil {
+		return NewMockPaymentRepository()
+	}
+	return &PaymentRepository{
+		client:     client,
+		collection: "payments",
+	}
+}
+
+type Payment struct {
+	ID            string `firestore:"id"`
+	StoreID       string `firestore:"store_id"`
+	SessionID     string `firestore:"session_id"`
+	Provider      string `firestore:"provider"`
+	IntentID      string `firestore:"intent_id"`
+	Currency      string `firestore:"currency"`
+	Amount        int64  `firestore:"amount"`
+	Status        string `firestore:"status"`
+	FailureReason string `firestore:"failure_reason"`
+
+	CreatedAt time.Time `firestore:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+func ToSetPayment(p *models.Payment) *Payment {
+	return &Payment{
+		ID:            p.ID,
+		StoreID:       p.StoreID,
+		SessionID:     p.SessionID,
+		Provider:      p.Provider,
+		IntentID:      p.IntentID,
+		Currency:      string(p.Amount.Currency),
+		Amount:        p.Amount.Amount,
+		Status:        string(p.Status),
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+func (p *Payment) ToModel() *models.Payment {
+	return &models.Payment{
+		ID:            p.ID,
+		StoreID:       p.StoreID,
+		SessionID:     p.SessionID,
+		Provider:      p.Provider,
+		IntentID:      p.IntentID,
+		Amount:        ToModelMoney(p.Amount, p.Currency),
+		Status:        models.PaymentIntentStatus(p.Status),
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// Create は新しい決済を Firestore に作成します。
+func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(payment.ID).Set(ctx, ToSetPayment(payment))
+	return err
+}
+
+// Read はすべての決済を Firestore から読み取ります。
+func (r *PaymentRepository) Read(ctx context.Context) ([]*models.Payment, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*models.Payment, len(docs))
+	for i, doc := range docs {
+		payment := &Payment{}
+		if err := doc.DataTo(payment); err != nil {
+			return nil, err
+		}
+		payments[i] = payment.ToModel()
+	}
+
+	return payments, nil
+}
+
+// FindByID は指定されたIDの決済を Firestore から検索します。
+func (r *PaymentRepository) FindByID(ctx context.Context, id string) (*models.Payment, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &Payment{}
+	if err := doc.DataTo(payment); err != nil {
+		return nil, err
+	}
+
+	return payment.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する決済を Firestore から検索します。
+func (r *PaymentRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Payment, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*models.Payment, len(docs))
+	for i, doc := range docs {
+		payment := &Payment{}
+		if err := doc.DataTo(payment); err != nil {
+			return nil, err
+		}
+		payments[i] = payment.ToModel()
+	}
+
+	return payments, nil
+}
+
+// UpdateByID は指定されたIDの決済を Firestore で更新します。
+func (r *PaymentRepository) UpdateByID(ctx context.Context, id string, payment *models.Payment) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetPayment(payment))
+	return err
+}
+
+// DeleteByID は指定されたIDの決済を Firestore から削除します。
+func (r *PaymentRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている決済の総数を返します。
+func (r *PaymentRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの決済が Firestore に存在するかどうかを確認します。
+func (r *PaymentRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
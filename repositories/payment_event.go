@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// PaymentEventRepository は Firestore の payment_events コレクションを操作するためのリポジトリです。
+// 処理済みの Webhook のイベントをイベントIDをドキュメントIDとして保存し、重複した配信の判定に使用します。
+type PaymentEventRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewPaymentEventRepository は新しい PaymentEventRepository のインスタンスを生成します。
+func NewPaymentEventRepository(client *firestore.Client) Repository[models.PaymentEvent] {
+	if client == nil {
+		return NewMockPaymentEventRepository()
+	}
+	return &PaymentEventRepository{
+		client:     client,
+		collection: "payment_events",
+	}
+}
+
+type PaymentEvent struct {
+	ID            string    `firestore:"id"`
+	Provider      string    `firestore:"provider"`
+	Type          string    `firestore:"type"`
+	IntentID      string    `firestore:"intent_id"`
+	Currency      string    `firestore:"currency"`
+	Amount        int64     `firestore:"amount"`
+	FailureReason string    `firestore:"failure_reason"`
+	ReceivedAt    time.Time `firestore:"received_at"`
+}
+
+func ToSetPaymentEvent(e *models.PaymentEvent) *PaymentEvent {
+	return &PaymentEvent{
+		ID:            e.ID,
+		Provider:      e.Provider,
+		Type:          string(e.Type),
+		IntentID:      e.IntentID,
+		Currency:      string(e.Amount.Currency),
+		Amount:        e.Amount.Amount,
+		FailureReason: e.FailureReason,
+		ReceivedAt:    e.ReceivedAt,
+	}
+}
+
+func (e *PaymentEvent) ToModel() *models.PaymentEvent {
+	return &models.PaymentEvent{
+		ID:            e.ID,
+		Provider:      e.Provider,
+		Type:          models.PaymentEventType(e.Type),
+		IntentID:      e.IntentID,
+		Amount:        ToModelMoney(e.Amount, e.Currency),
+		FailureReason: e.FailureReason,
+		ReceivedAt:    e.ReceivedAt,
+	}
+}
+
+// Create は新しい Webhook のイベントを Firestore に作成します。
+func (r *PaymentEventRepository) Create(ctx context.Context, event *models.PaymentEvent) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(event.ID).Set(ctx, ToSetPaymentEvent(event))
+	return err
+}
+
+// Read はすべての Webhook のイベントを Firestore から読み取ります。
+func (r *PaymentEventRepository) Read(ctx context.Context) ([]*models.PaymentEvent, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*models.PaymentEvent, len(docs))
+	for i, doc := range docs {
+		event := &PaymentEvent{}
+		if err := doc.DataTo(event); err != nil {
+			return nil, err
+		}
+		events[i] = event.ToModel()
+	}
+
+	return events, nil
+}
+
+// FindByID は指定されたIDの Webhook のイベントを Firestore から検索します。
+func (r *PaymentEventRepository) FindByID(ctx context.Context, id string) (*models.PaymentEvent, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &PaymentEvent{}
+	if err := doc.DataTo(event); err != nil {
+		return nil, err
+	}
+
+	return event.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する Webhook のイベントを Firestore から検索します。
+func (r *PaymentEventRepository) FindByField(ctx context.Context, field string, value any) ([]*models.PaymentEvent, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*models.PaymentEvent, len(docs))
+	for i, doc := range docs {
+		event := &PaymentEvent{}
+		if err := doc.DataTo(event); err != nil {
+			return nil, err
+		}
+		events[i] = event.ToModel()
+	}
+
+	return events, nil
+}
+
+// UpdateByID は指定されたIDの Webhook のイベントを Firestore で更新します。
+func (r *PaymentEventRepository) UpdateByID(ctx context.Context, id string, event *models.PaymentEvent) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetPaymentEvent(event))
+	return err
+}
+
+// DeleteByID は指定されたIDの Webhook のイベントを Firestore から削除します。
+func (r *PaymentEventRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている Webhook のイベントの総数を返します。
+func (r *PaymentEventRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの Webhook のイベントが Firestore に存在するかどうかを確認します。
+func (r *PaymentEventRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
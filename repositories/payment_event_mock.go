@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentEventRepository - 実際のFirestoreの複雑な実装は不要
+type MockPaymentEventRepository struct {
+	mock.Mock
+}
+
+func NewMockPaymentEventRepository() Repository[models.PaymentEvent] {
+	return &MockPaymentEventRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockPaymentEventRepository) Create(ctx context.Context, paymentEvent *models.PaymentEvent) error {
+	args := m.Called(ctx, paymentEvent)
+	return args.Error(0)
+}
+
+func (m *MockPaymentEventRepository) Read(ctx context.Context) ([]*models.PaymentEvent, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.PaymentEvent{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.PaymentEvent), nil
+}
+
+func (m *MockPaymentEventRepository) FindByID(ctx context.Context, id string) (*models.PaymentEvent, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaymentEvent), nil
+}
+
+func (m *MockPaymentEventRepository) FindByField(ctx context.Context, field string, value any) ([]*models.PaymentEvent, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.PaymentEvent{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.PaymentEvent), nil
+}
+
+func (m *MockPaymentEventRepository) UpdateByID(ctx context.Context, id string, paymentEvent *models.PaymentEvent) error {
+	args := m.Called(ctx, id, paymentEvent)
+	return args.Error(0)
+}
+
+func (m *MockPaymentEventRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPaymentEventRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockPaymentEventRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakePaymentProvider はプロセス内のメモリで支払いを管理する PaymentProvider です。
+// 開発環境やテストで使用し、Succeed、Fail などで Stripe と同じ形式の署名付き Webhook を生成できます。
+type FakePaymentProvider struct {
+	mu            sync.Mutex
+	webhookSecret string
+	intents       map[string]*fakeIntent
+	idempotency   map[string]string
+	refunds       map[string]*PaymentRefund
+}
+
+type fakeIntent struct {
+	intent        PaymentIntent
+	manualCapture bool
+	refunded      int64
+}
+
+// NewFakePaymentProvider は新しい FakePaymentProvider のインスタンスを生成します。
+// webhookSecret が空の場合はランダムな値を使用します。
+func NewFakePaymentProvider(webhookSecret string) *FakePaymentProvider {
+	if webhookSecret == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		webhookSecret = "whsec_" + hex.EncodeToString(b)
+	}
+	return &FakePaymentProvider{
+		webhookSecret: webhookSecret,
+		intents:       make(map[string]*fakeIntent),
+		idempotency:   make(map[string]string),
+		refunds:       make(map[string]*PaymentRefund),
+	}
+}
+
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+// CreateIntent は支払いを作成します。
+func (p *FakePaymentProvider) CreateIntent(ctx context.Context, req PaymentIntentRequest) (*PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.idempotency[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		intent := p.intents[id].intent
+		return &intent, nil
+	}
+	if req.Amount.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", models.ErrPaymentProviderFailed)
+	}
+
+	id := models.GenerateUniqueID("pi_fake_")
+	p.intents[id] = &fakeIntent{
+		intent: PaymentIntent{
+			ID:           id,
+			Status:       models.PaymentIntentRequiresPayment,
+			Amount:       req.Amount,
+			ClientSecret: id + "_secret",
+		},
+		manualCapture: req.ManualCapture,
+	}
+	if req.IdempotencyKey != "" {
+		p.idempotency[req.IdempotencyKey] = id
+	}
+	intent := p.intents[id].intent
+	return &intent, nil
+}
+
+// Capture はオーソリ済みの支払いの売上を確定します。
+func (p *FakePaymentProvider) Capture(ctx context.Context, intentID string) (*PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fi, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no such payment intent: %s", models.ErrPaymentProviderFailed, intentID)
+	}
+	if fi.intent.Status != models.PaymentIntentRequiresCapture {
+		return nil, fmt.Errorf("%w: payment intent is %s", models.ErrPaymentProviderFailed, fi.intent.Status)
+	}
+	fi.intent.Status = models.PaymentIntentSucceeded
+	intent := fi.intent
+	return &intent, nil
+}
+
+// Refund は完了した支払いを返金します。返金の合計は支払い金額を超えられません。
+func (p *FakePaymentProvider) Refund(ctx context.Context, intentID string, amount models.Money, idempotencyKey string) (*PaymentRefund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if refund, ok := p.refunds[idempotencyKey]; ok && idempotencyKey != "" {
+		return refund, nil
+	}
+	fi, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no such payment intent: %s", models.ErrPaymentProviderFailed, intentID)
+	}
+	if fi.intent.Status != models.PaymentIntentSucceeded {
+		return nil, fmt.Errorf("%w: payment intent is %s", models.ErrPaymentProviderFailed, fi.intent.Status)
+	}
+	if amount.Amount <= 0 || fi.refunded+amount.Amount > fi.intent.Amount.Amount {
+		return nil, fmt.Errorf("%w: refund amount exceeds the charge", models.ErrPaymentProviderFailed)
+	}
+	fi.refunded += amount.Amount
+
+	refund := &PaymentRefund{ID: models.GenerateUniqueID("re_fake_"), Amount: amount}
+	if idempotencyKey != "" {
+		p.refunds[idempotencyKey] = refund
+	}
+	return refund, nil
+}
+
+// VerifyWebhook は Stripe と同じ方式で署名を検証し、イベントを返します。
+func (p *FakePaymentProvider) VerifyWebhook(payload []byte, header http.Header, now time.Time) (*models.PaymentEvent, error) {
+	if err := verifyStripeSignature(payload, header.Get("Stripe-Signature"), p.webhookSecret, now); err != nil {
+		return nil, err
+	}
+	return parseStripeEvent(p.Name(), payload, now)
+}
+
+// Authorize はお客様がオーソリのみの支払い手続きを完了したことにし、その Webhook を返します。
+func (p *FakePaymentProvider) Authorize(intentID string, now time.Time) ([]byte, http.Header, error) {
+	return p.transition(intentID, models.PaymentIntentRequiresCapture, "", "payment_intent.amount_capturable_updated", now)
+}
+
+// Succeed はお客様が支払い手続きを完了したことにし、その Webhook を返します。
+// 売上の確定が必要な支払いの場合はオーソリ済みになります。
+func (p *FakePaymentProvider) Succeed(intentID string, now time.Time) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	fi, ok := p.intents[intentID]
+	manual := ok && fi.manualCapture
+	p.mu.Unlock()
+	if manual {
+		return p.Authorize(intentID, now)
+	}
+	return p.transition(intentID, models.PaymentIntentSucceeded, "", "payment_intent.succeeded", now)
+}
+
+// Fail は支払いが失敗したことにし、その Webhook を返します。
+func (p *FakePaymentProvider) Fail(intentID, reason string, now time.Time) ([]byte, http.Header, error) {
+	return p.transition(intentID, models.PaymentIntentFailed, reason, "payment_intent.payment_failed", now)
+}
+
+// transition は支払いの状態を更新し、Stripe と同じ形式の署名付き Webhook を生成します。
+func (p *FakePaymentProvider) transition(intentID string, status models.PaymentIntentStatus, reason, eventType string, now time.Time) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fi, ok := p.intents[intentID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no such payment intent: %s", models.ErrPaymentProviderFailed, intentID)
+	}
+	fi.intent.Status = status
+	fi.intent.FailureReason = reason
+
+	stripeStatus := map[models.PaymentIntentStatus]string{
+		models.PaymentIntentRequiresCapture: "requires_capture",
+		models.PaymentIntentSucceeded:       "succeeded",
+		models.PaymentIntentFailed:          "requires_payment_method",
+	}[status]
+	object := map[string]any{
+		"id":       fi.intent.ID,
+		"object":   "payment_intent",
+		"amount":   fi.intent.Amount.Amount,
+		"currency": strings.ToLower(string(fi.intent.Amount.Currency)),
+		"status":   stripeStatus,
+	}
+	if reason != "" {
+		object["last_payment_error"] = map[string]string{"message": reason}
+	}
+	payload, err := json.Marshal(map[string]any{
+		"id":   models.GenerateUniqueID("evt_fake_"),
+		"type": eventType,
+		"data": map[string]any{"object": object},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Stripe-Signature", fmt.Sprintf("t=%d,v1=%s", now.Unix(), signStripePayload(payload, p.webhookSecret, now.Unix())))
+	return payload, header, nil
+}
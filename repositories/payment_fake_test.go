@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakePaymentProvider(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	provider := NewFakePaymentProvider("")
+
+	intent, err := provider.CreateIntent(ctx, PaymentIntentRequest{Amount: models.Yen(1100), IdempotencyKey: "order_1"})
+	require.NoError(t, err)
+	again, err := provider.CreateIntent(ctx, PaymentIntentRequest{Amount: models.Yen(1100), IdempotencyKey: "order_1"})
+	require.NoError(t, err)
+	assert.Equal(t, intent.ID, again.ID, "同じ冪等キーでは同じ支払いを返す")
+
+	payload, header, err := provider.Succeed(intent.ID, now)
+	require.NoError(t, err)
+	event, err := provider.VerifyWebhook(payload, header, now)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentEventSucceeded, event.Type)
+	assert.Equal(t, intent.ID, event.IntentID)
+
+	refund, err := provider.Refund(ctx, intent.ID, models.Yen(600), "refund_1")
+	require.NoError(t, err)
+	_, err = provider.Refund(ctx, intent.ID, models.Yen(600), "refund_2")
+	assert.ErrorIs(t, err, models.ErrPaymentProviderFailed, "返金の合計は支払い金額を超えられない")
+	same, err := provider.Refund(ctx, intent.ID, models.Yen(600), "refund_1")
+	require.NoError(t, err)
+	assert.Equal(t, refund.ID, same.ID)
+
+	t.Run("manual capture", func(t *testing.T) {
+		intent, err := provider.CreateIntent(ctx, PaymentIntentRequest{Amount: models.Yen(500), ManualCapture: true})
+		require.NoError(t, err)
+		payload, header, err := provider.Succeed(intent.ID, now)
+		require.NoError(t, err)
+		event, err := provider.VerifyWebhook(payload, header, now)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentEventAuthorized, event.Type)
+
+		captured, err := provider.Capture(ctx, intent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentIntentSucceeded, captured.Status)
+	})
+}
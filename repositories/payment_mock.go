@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentRepository - 実際のFirestoreの複雑な実装は不要
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func NewMockPaymentRepository() Repository[models.Payment] {
+	return &MockPaymentRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockPaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) Read(ctx context.Context) ([]*models.Payment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.Payment{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Payment), nil
+}
+
+func (m *MockPaymentRepository) FindByID(ctx context.Context, id string) (*models.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), nil
+}
+
+func (m *MockPaymentRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Payment, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.Payment{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Payment), nil
+}
+
+func (m *MockPaymentRepository) UpdateByID(ctx context.Context, id string, payment *models.Payment) error {
+	args := m.Called(ctx, id, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockPaymentRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
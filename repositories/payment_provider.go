@@ -0,0 +1,50 @@
+package repositories
+
+// payment_provider.go は決済代行会社との連携を抽象化します。
+// Stripe 互換のアダプタ（StripePaymentProvider）と、テスト用のプロセス内のフェイク（FakePaymentProvider）を実装しています。
+
+import (
+	"backend/models"
+	"context"
+	"net/http"
+	"time"
+)
+
+// PaymentProvider は決済代行会社の API です。
+type PaymentProvider interface {
+	// Name は決済代行会社の名称（"stripe" など）を返します。
+	Name() string
+	// CreateIntent は支払いを作成します。同じ IdempotencyKey で呼び出した場合は同じ支払いを返します。
+	CreateIntent(ctx context.Context, req PaymentIntentRequest) (*PaymentIntent, error)
+	// Capture はオーソリ済みの支払いの売上を確定します。
+	Capture(ctx context.Context, intentID string) (*PaymentIntent, error)
+	// Refund は支払いの一部または全額を返金します。同じ idempotencyKey で呼び出した場合は同じ返金を返します。
+	Refund(ctx context.Context, intentID string, amount models.Money, idempotencyKey string) (*PaymentRefund, error)
+	// VerifyWebhook は Webhook の署名を検証し、イベントを返します。
+	VerifyWebhook(payload []byte, header http.Header, now time.Time) (*models.PaymentEvent, error)
+}
+
+// PaymentIntentRequest は支払いの作成の指定です。
+// ManualCapture が true の場合はオーソリのみを行い、Capture で売上を確定します。
+type PaymentIntentRequest struct {
+	Amount         models.Money
+	ManualCapture  bool
+	IdempotencyKey string
+	Metadata       map[string]string
+}
+
+// PaymentIntent は決済代行会社での支払いです。
+// ClientSecret はお客様のブラウザで支払い手続きを行うための値で、保存せずにレスポンスでのみ返します。
+type PaymentIntent struct {
+	ID            string
+	Status        models.PaymentIntentStatus
+	Amount        models.Money
+	ClientSecret  string
+	FailureReason string
+}
+
+// PaymentRefund は決済代行会社での返金です。
+type PaymentRefund struct {
+	ID     string
+	Amount models.Money
+}
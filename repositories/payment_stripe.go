@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeAPIBaseURL = "https://api.stripe.com"
+	// stripeSignatureTolerance は Webhook の署名のタイムスタンプとして許容する時刻のずれです（リプレイ攻撃対策）。
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripePaymentProvider は Stripe の PaymentIntents API を使用する PaymentProvider です。
+// SDK には依存せず、フォーム形式の HTTP リクエストで API を呼び出します。
+type StripePaymentProvider struct {
+	secretKey     string
+	webhookSecret string
+	baseURL       string
+	httpClient    *http.Client
+}
+
+// NewStripePaymentProvider は新しい StripePaymentProvider のインスタンスを生成します。
+// httpClient が nil の場合はタイムアウトを設定したクライアントを使用します。
+func NewStripePaymentProvider(secretKey, webhookSecret string, httpClient *http.Client) *StripePaymentProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &StripePaymentProvider{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		baseURL:       stripeAPIBaseURL,
+		httpClient:    httpClient,
+	}
+}
+
+func (p *StripePaymentProvider) Name() string {
+	return "stripe"
+}
+
+// stripePaymentIntent は Stripe の PaymentIntent オブジェクトのうち、使用する項目です。
+type stripePaymentIntent struct {
+	ID               string `json:"id"`
+	Object           string `json:"object"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	Status           string `json:"status"`
+	ClientSecret     string `json:"client_secret"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+func (pi *stripePaymentIntent) toIntent() *PaymentIntent {
+	intent := &PaymentIntent{
+		ID:           pi.ID,
+		Status:       stripeIntentStatus(pi.Status),
+		Amount:       ToModelMoney(pi.Amount, strings.ToUpper(pi.Currency)),
+		ClientSecret: pi.ClientSecret,
+	}
+	if pi.LastPaymentError != nil {
+		intent.FailureReason = pi.LastPaymentError.Message
+	}
+	return intent
+}
+
+// stripeIntentStatus は Stripe の PaymentIntent のステータスを変換します。
+func stripeIntentStatus(status string) models.PaymentIntentStatus {
+	switch status {
+	case "requires_capture":
+		return models.PaymentIntentRequiresCapture
+	case "succeeded":
+		return models.PaymentIntentSucceeded
+	case "canceled":
+		return models.PaymentIntentCanceled
+	default:
+		// requires_payment_method, requires_confirmation, requires_action, processing
+		return models.PaymentIntentRequiresPayment
+	}
+}
+
+// CreateIntent は PaymentIntent を作成します。
+// 金額は補助単位の整数で、Stripe の amount（最小通貨単位）と同じです。
+func (p *StripePaymentProvider) CreateIntent(ctx context.Context, req PaymentIntentRequest) (*PaymentIntent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount.Amount, 10))
+	form.Set("currency", strings.ToLower(string(req.Amount.Currency)))
+	form.Set("automatic_payment_methods[enabled]", "true")
+	if req.ManualCapture {
+		form.Set("capture_method", "manual")
+	}
+	for k, v := range req.Metadata {
+		form.Set("metadata["+k+"]", v)
+	}
+
+	pi := &stripePaymentIntent{}
+	if err := p.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, pi); err != nil {
+		return nil, err
+	}
+	return pi.toIntent(), nil
+}
+
+// Capture はオーソリ済みの PaymentIntent の売上を確定します。
+func (p *StripePaymentProvider) Capture(ctx context.Context, intentID string) (*PaymentIntent, error) {
+	pi := &stripePaymentIntent{}
+	if err := p.post(ctx, "/v1/payment_intents/"+url.PathEscape(intentID)+"/capture", url.Values{}, "", pi); err != nil {
+		return nil, err
+	}
+	return pi.toIntent(), nil
+}
+
+// Refund は PaymentIntent の返金を作成します。
+func (p *StripePaymentProvider) Refund(ctx context.Context, intentID string, amount models.Money, idempotencyKey string) (*PaymentRefund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", intentID)
+	form.Set("amount", strconv.FormatInt(amount.Amount, 10))
+
+	refund := &struct {
+		ID       string `json:"id"`
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	}{}
+	if err := p.post(ctx, "/v1/refunds", form, idempotencyKey, refund); err != nil {
+		return nil, err
+	}
+	return &PaymentRefund{ID: refund.ID, Amount: ToModelMoney(refund.Amount, strings.ToUpper(refund.Currency))}, nil
+}
+
+// post は Stripe の API を呼び出し、レスポンスを out に読み込みます。
+func (p *StripePaymentProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrPaymentProviderFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		body := &struct {
+			Error struct {
+				Type    string `json:"type"`
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}{}
+		_ = json.NewDecoder(res.Body).Decode(body)
+		return fmt.Errorf("%w: status=%d type=%s code=%s: %s", models.ErrPaymentProviderFailed, res.StatusCode, body.Error.Type, body.Error.Code, body.Error.Message)
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrPaymentProviderFailed, err)
+	}
+	return nil
+}
+
+// VerifyWebhook は Stripe-Signature ヘッダーを検証し、イベントを返します。
+func (p *StripePaymentProvider) VerifyWebhook(payload []byte, header http.Header, now time.Time) (*models.PaymentEvent, error) {
+	if err := verifyStripeSignature(payload, header.Get("Stripe-Signature"), p.webhookSecret, now); err != nil {
+		return nil, err
+	}
+	return parseStripeEvent(p.Name(), payload, now)
+}
+
+// signStripePayload は Stripe と同じ方式（"<timestamp>.<payload>" の HMAC-SHA256）で署名します。
+func signStripePayload(payload []byte, secret string, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyStripeSignature は "t=<timestamp>,v1=<signature>" 形式の署名を検証します。
+// 署名の更新中は v1 が複数含まれるため、いずれかが一致すれば有効とします。
+func verifyStripeSignature(payload []byte, signature, secret string, now time.Time) error {
+	if secret == "" || signature == "" {
+		return models.ErrInvalidWebhookSignature
+	}
+
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return models.ErrInvalidWebhookSignature
+			}
+			timestamp = t
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return models.ErrInvalidWebhookSignature
+	}
+
+	expected := signStripePayload(payload, secret, timestamp)
+	valid := false
+	for _, s := range signatures {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return models.ErrInvalidWebhookSignature
+	}
+
+	if diff := now.Sub(time.Unix(timestamp, 0)); diff > stripeSignatureTolerance || diff < -stripeSignatureTolerance {
+		return models.ErrWebhookTimestampExpired
+	}
+	return nil
+}
+
+// stripeEvent は Stripe の Event オブジェクトです。
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object stripePaymentIntent `json:"object"`
+	} `json:"data"`
+}
+
+// parseStripeEvent は Stripe の Event を models.PaymentEvent に変換します。
+// PaymentIntent 以外のイベントは PaymentEventIgnored として返します。
+func parseStripeEvent(provider string, payload []byte, now time.Time) (*models.PaymentEvent, error) {
+	event := &stripeEvent{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidWebhookPayload, err)
+	}
+	if event.ID == "" {
+		return nil, models.ErrInvalidWebhookPayload
+	}
+
+	pi := event.Data.Object
+	result := &models.PaymentEvent{
+		ID:         event.ID,
+		Provider:   provider,
+		Type:       models.PaymentEventIgnored,
+		ReceivedAt: now.UTC(),
+	}
+	if pi.Object != "payment_intent" {
+		return result, nil
+	}
+
+	intent := pi.toIntent()
+	result.IntentID = intent.ID
+	result.Amount = intent.Amount
+	result.FailureReason = intent.FailureReason
+	switch event.Type {
+	case "payment_intent.amount_capturable_updated":
+		result.Type = models.PaymentEventAuthorized
+	case "payment_intent.succeeded":
+		result.Type = models.PaymentEventSucceeded
+	case "payment_intent.payment_failed":
+		result.Type = models.PaymentEventFailed
+	case "payment_intent.canceled":
+		result.Type = models.PaymentEventCanceled
+	}
+	return result, nil
+}
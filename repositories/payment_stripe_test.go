@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStripeProvider(t *testing.T, handler http.HandlerFunc) *StripePaymentProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	provider := NewStripePaymentProvider("sk_test_123", "whsec_test", server.Client())
+	provider.baseURL = server.URL
+	return provider
+}
+
+func TestStripePaymentProvider_CreateIntent(t *testing.T) {
+	var form url.Values
+	var header http.Header
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		header = r.Header
+		assert.Equal(t, "/v1/payment_intents", r.URL.Path)
+		fmt.Fprint(w, `{"id":"pi_123","object":"payment_intent","amount":1100,"currency":"jpy","status":"requires_payment_method","client_secret":"pi_123_secret"}`)
+	})
+
+	intent, err := provider.CreateIntent(context.Background(), PaymentIntentRequest{
+		Amount:         models.Yen(1100),
+		ManualCapture:  true,
+		IdempotencyKey: "order_1",
+		Metadata:       map[string]string{"session_id": "order_1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pi_123", intent.ID)
+	assert.Equal(t, models.PaymentIntentRequiresPayment, intent.Status)
+	assert.Equal(t, models.Yen(1100), intent.Amount)
+
+	assert.Equal(t, "1100", form.Get("amount"))
+	assert.Equal(t, "jpy", form.Get("currency"))
+	assert.Equal(t, "manual", form.Get("capture_method"))
+	assert.Equal(t, "order_1", form.Get("metadata[session_id]"))
+	assert.Equal(t, "Bearer sk_test_123", header.Get("Authorization"))
+	assert.Equal(t, "order_1", header.Get("Idempotency-Key"))
+}
+
+func TestStripePaymentProvider_Error(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		fmt.Fprint(w, `{"error":{"type":"card_error","code":"card_declined","message":"Your card was declined."}}`)
+	})
+
+	_, err := provider.Capture(context.Background(), "pi_123")
+	assert.ErrorIs(t, err, models.ErrPaymentProviderFailed)
+	assert.Contains(t, err.Error(), "card_declined")
+}
+
+func TestStripePaymentProvider_VerifyWebhook(t *testing.T) {
+	provider := NewStripePaymentProvider("sk_test_123", "whsec_test", nil)
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.payment_failed","data":{"object":{"id":"pi_123","object":"payment_intent","amount":1100,"currency":"jpy","status":"requires_payment_method","last_payment_error":{"message":"Your card was declined."}}}}`)
+	sign := func(secret string, at time.Time) http.Header {
+		header := http.Header{}
+		header.Set("Stripe-Signature", fmt.Sprintf("t=%d,v1=%s", at.Unix(), signStripePayload(payload, secret, at.Unix())))
+		return header
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		event, err := provider.VerifyWebhook(payload, sign("whsec_test", now), now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, "evt_1", event.ID)
+		assert.Equal(t, models.PaymentEventFailed, event.Type)
+		assert.Equal(t, "pi_123", event.IntentID)
+		assert.Equal(t, "Your card was declined.", event.FailureReason)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		_, err := provider.VerifyWebhook(payload, sign("whsec_other", now), now)
+		assert.ErrorIs(t, err, models.ErrInvalidWebhookSignature)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		header := sign("whsec_test", now)
+		_, err := provider.VerifyWebhook(append(payload, ' '), header, now)
+		assert.ErrorIs(t, err, models.ErrInvalidWebhookSignature)
+	})
+
+	t.Run("replayed after tolerance", func(t *testing.T) {
+		_, err := provider.VerifyWebhook(payload, sign("whsec_test", now), now.Add(10*time.Minute))
+		assert.ErrorIs(t, err, models.ErrWebhookTimestampExpired)
+	})
+
+	t.Run("other objects are ignored", func(t *testing.T) {
+		other := []byte(`{"id":"evt_2","type":"customer.created","data":{"object":{"id":"cus_1","object":"customer"}}}`)
+		header := http.Header{}
+		header.Set("Stripe-Signature", fmt.Sprintf("t=%d,v1=%s", now.Unix(), signStripePayload(other, "whsec_test", now.Unix())))
+		event, err := provider.VerifyWebhook(other, header, now)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentEventIgnored, event.Type)
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewPaymentRepository tests the NewPaymentRepository function
+func TestNewPaymentRepository(t *testing.T) {
+	t.Run("NewPaymentRepository with nil client returns MockPaymentRepository", func(t *testing.T) {
+		repo := NewPaymentRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockPaymentRepository)
+		assert.True(t, ok, "Should return a MockPaymentRepository when client is nil")
+	})
+}
+
+// TestMockPaymentRepository tests the MockPaymentRepository implementation
+func TestMockPaymentRepository(t *testing.T) {
+	ctx := context.Background()
+	testPayment := &models.Payment{ID: "pay_123", StoreID: "store_123", SessionID: "session_123", IntentID: "pi_123"}
+
+	mockRepo := &MockPaymentRepository{}
+	mockRepo.On("FindByField", mock.Anything, "intent_id", "pi_123").Return([]*models.Payment{testPayment}, nil)
+
+	payments, err := mockRepo.FindByField(ctx, "intent_id", "pi_123")
+	assert.NoError(t, err)
+	assert.Len(t, payments, 1)
+	assert.Equal(t, testPayment.ID, payments[0].ID)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestPaymentStruct tests the Payment struct conversions
+func TestPaymentStruct(t *testing.T) {
+	now := time.Now().UTC()
+	testPayment := &models.Payment{
+		ID:            "pay_123",
+		StoreID:       "store_123",
+		SessionID:     "session_123",
+		Provider:      "stripe",
+		IntentID:      "pi_123",
+		Amount:        models.NewMoney(1250, models.CurrencyUSD),
+		Status:        models.PaymentIntentFailed,
+		FailureReason: "card_declined",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	repoPayment := ToSetPayment(testPayment)
+	assert.Equal(t, "USD", repoPayment.Currency)
+	assert.Equal(t, "failed", repoPayment.Status)
+	assert.Equal(t, testPayment, repoPayment.ToModel())
+}
+
+// TestPaymentEventStruct tests the PaymentEvent struct conversions
+func TestPaymentEventStruct(t *testing.T) {
+	assert.NotNil(t, NewPaymentEventRepository(nil).(*MockPaymentEventRepository))
+
+	testEvent := &models.PaymentEvent{
+		ID:         "evt_123",
+		Provider:   "stripe",
+		Type:       models.PaymentEventSucceeded,
+		IntentID:   "pi_123",
+		Amount:     models.Yen(1100),
+		ReceivedAt: time.Now().UTC(),
+	}
+	assert.Equal(t, testEvent, ToSetPaymentEvent(testEvent).ToModel())
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// PromotionRepository は Firestore の promotions コレクションを操作するためのリポジトリです。
+type PromotionRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewPromotionRepository は新しい PromotionRepository のインスタンスを生成します。
+func NewPromotionRepository(client *firestore.Client) Repository[models.Promotion] {
+	if client == nil {
+		return NewMockPromotionRepository()
+	}
+	return &PromotionRepository{
+		client:     client,
+		collection: "promotions",
+	}
+}
+
+type Promotion struct {
+	ID      string       `firestore:"id"`
+	StoreID string       `firestore:"store_id"`
+	Code    string       `firestore:"code"`
+	Name    string       `firestore:"name"`
+	Rule    DiscountRule `firestore:"rule"`
+
+	Currency string `firestore:"currency"`
+	MinSpend int64  `firestore:"min_spend"`
+
+	StartsAt time.Time `firestore:"starts_at"`
+	EndsAt   time.Time `firestore:"ends_at"`
+
+	MaxRedemptions int `firestore:"max_redemptions"`
+	MaxPerVisit    int `firestore:"max_per_visit"`
+
+	Active bool `firestore:"active"`
+
+	CreatedAt time.Time `firestore:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+func ToSetPromotion(p *models.Promotion) *Promotion {
+	return &Promotion{
+		ID:      p.ID,
+		StoreID: p.StoreID,
+		Code:    p.Code,
+		Name:    p.Name,
+		Rule:    ToSetDiscountRule(p.Rule),
+
+		Currency: string(p.Currency),
+		MinSpend: p.MinSpend.Amount,
+
+		StartsAt: p.StartsAt,
+		EndsAt:   p.EndsAt,
+
+		MaxRedemptions: p.MaxRedemptions,
+		MaxPerVisit:    p.MaxPerVisit,
+
+		Active: p.Active,
+
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func (p *Promotion) ToModel() *models.Promotion {
+	currency := models.Currency(p.Currency)
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	return &models.Promotion{
+		ID:      p.ID,
+		StoreID: p.StoreID,
+		Code:    p.Code,
+		Name:    p.Name,
+		Rule:    p.Rule.ToModel(),
+
+		Currency: currency,
+		MinSpend: ToModelMoney(p.MinSpend, p.Currency),
+
+		StartsAt: p.StartsAt,
+		EndsAt:   p.EndsAt,
+
+		MaxRedemptions: p.MaxRedemptions,
+		MaxPerVisit:    p.MaxPerVisit,
+
+		Active: p.Active,
+
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// Create は新しいプロモーションを Firestore に作成します。
+func (r *PromotionRepository) Create(ctx context.Context, promotion *models.Promotion) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(promotion.ID).Set(ctx, ToSetPromotion(promotion))
+	return err
+}
+
+// Read はすべてのプロモーションを Firestore から読み取ります。
+func (r *PromotionRepository) Read(ctx context.Context) ([]*models.Promotion, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := make([]*models.Promotion, len(docs))
+	for i, doc := range docs {
+		promotion := &Promotion{}
+		if err := doc.DataTo(promotion); err != nil {
+			return nil, err
+		}
+		promotions[i] = promotion.ToModel()
+	}
+
+	return promotions, nil
+}
+
+// FindByID は指定されたIDのプロモーションを Firestore から検索します。
+func (r *PromotionRepository) FindByID(ctx context.Context, id string) (*models.Promotion, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion := &Promotion{}
+	if err := doc.DataTo(promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致するプロモーションを Firestore から検索します。
+func (r *PromotionRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Promotion, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := make([]*models.Promotion, len(docs))
+	for i, doc := range docs {
+		promotion := &Promotion{}
+		if err := doc.DataTo(promotion); err != nil {
+			return nil, err
+		}
+		promotions[i] = promotion.ToModel()
+	}
+
+	return promotions, nil
+}
+
+// UpdateByID は指定されたIDのプロモーションを Firestore で更新します。
+func (r *PromotionRepository) UpdateByID(ctx context.Context, id string, promotion *models.Promotion) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetPromotion(promotion))
+	return err
+}
+
+// DeleteByID は指定されたIDのプロモーションを Firestore から削除します。
+func (r *PromotionRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されているプロモーションの総数を返します。
+func (r *PromotionRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDのプロモーションが Firestore に存在するかどうかを確認します。
+func (r *PromotionRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
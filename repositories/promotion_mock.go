@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPromotionRepository - 実際のFirestoreの複雑な実装は不要
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func NewMockPromotionRepository() Repository[models.Promotion] {
+	return &MockPromotionRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockPromotionRepository) Create(ctx context.Context, promotion *models.Promotion) error {
+	args := m.Called(ctx, promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) Read(ctx context.Context) ([]*models.Promotion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.Promotion{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Promotion), nil
+}
+
+func (m *MockPromotionRepository) FindByID(ctx context.Context, id string) (*models.Promotion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Promotion), nil
+}
+
+func (m *MockPromotionRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Promotion, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.Promotion{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Promotion), nil
+}
+
+func (m *MockPromotionRepository) UpdateByID(ctx context.Context, id string, promotion *models.Promotion) error {
+	args := m.Called(ctx, id, promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockPromotionRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
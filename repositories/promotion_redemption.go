@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// PromotionRedemptionRepository は Firestore の promotion_redemptions コレクションを操作するためのリポジトリです。
+// プロモーションコードの利用記録を保存し、利用回数の上限の判定に使用します。
+type PromotionRedemptionRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewPromotionRedemptionRepository は新しい PromotionRedemptionRepository のインスタンスを生成します。
+func NewPromotionRedemptionRepository(client *firestore.Client) Repository[models.PromotionRedemption] {
+	if client == nil {
+		return NewMockPromotionRedemptionRepository()
+	}
+	return &PromotionRedemptionRepository{
+		client:     client,
+		collection: "promotion_redemptions",
+	}
+}
+
+type PromotionRedemption struct {
+	ID          string    `firestore:"id"`
+	PromotionID string    `firestore:"promotion_id"`
+	Code        string    `firestore:"code"`
+	StoreID     string    `firestore:"store_id"`
+	SessionID   string    `firestore:"session_id"`
+	SeatID      string    `firestore:"seat_id"`
+	VisitID     string    `firestore:"visit_id"`
+	DiscountID  string    `firestore:"discount_id"`
+	Amount      int64     `firestore:"amount"`
+	Currency    string    `firestore:"currency"`
+	RedeemedAt  time.Time `firestore:"redeemed_at"`
+}
+
+func ToSetPromotionRedemption(r *models.PromotionRedemption) *PromotionRedemption {
+	return &PromotionRedemption{
+		ID:          r.ID,
+		PromotionID: r.PromotionID,
+		Code:        r.Code,
+		StoreID:     r.StoreID,
+		SessionID:   r.SessionID,
+		SeatID:      r.SeatID,
+		VisitID:     r.VisitID,
+		DiscountID:  r.DiscountID,
+		Amount:      r.Amount.Amount,
+		Currency:    string(r.Amount.Currency),
+		RedeemedAt:  r.RedeemedAt,
+	}
+}
+
+func (r *PromotionRedemption) ToModel() *models.PromotionRedemption {
+	return &models.PromotionRedemption{
+		ID:          r.ID,
+		PromotionID: r.PromotionID,
+		Code:        r.Code,
+		StoreID:     r.StoreID,
+		SessionID:   r.SessionID,
+		SeatID:      r.SeatID,
+		VisitID:     r.VisitID,
+		DiscountID:  r.DiscountID,
+		Amount:      ToModelMoney(r.Amount, r.Currency),
+		RedeemedAt:  r.RedeemedAt,
+	}
+}
+
+// Create は新しい利用記録を Firestore に作成します。
+func (r *PromotionRedemptionRepository) Create(ctx context.Context, redemption *models.PromotionRedemption) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(redemption.ID).Set(ctx, ToSetPromotionRedemption(redemption))
+	return err
+}
+
+// Read はすべての利用記録を Firestore から読み取ります。
+func (r *PromotionRedemptionRepository) Read(ctx context.Context) ([]*models.PromotionRedemption, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]*models.PromotionRedemption, len(docs))
+	for i, doc := range docs {
+		redemption := &PromotionRedemption{}
+		if err := doc.DataTo(redemption); err != nil {
+			return nil, err
+		}
+		redemptions[i] = redemption.ToModel()
+	}
+
+	return redemptions, nil
+}
+
+// FindByID は指定されたIDの利用記録を Firestore から検索します。
+func (r *PromotionRedemptionRepository) FindByID(ctx context.Context, id string) (*models.PromotionRedemption, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption := &PromotionRedemption{}
+	if err := doc.DataTo(redemption); err != nil {
+		return nil, err
+	}
+
+	return redemption.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する利用記録を Firestore から検索します。
+func (r *PromotionRedemptionRepository) FindByField(ctx context.Context, field string, value any) ([]*models.PromotionRedemption, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]*models.PromotionRedemption, len(docs))
+	for i, doc := range docs {
+		redemption := &PromotionRedemption{}
+		if err := doc.DataTo(redemption); err != nil {
+			return nil, err
+		}
+		redemptions[i] = redemption.ToModel()
+	}
+
+	return redemptions, nil
+}
+
+// UpdateByID は指定されたIDの利用記録を Firestore で更新します。
+func (r *PromotionRedemptionRepository) UpdateByID(ctx context.Context, id string, redemption *models.PromotionRedemption) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetPromotionRedemption(redemption))
+	return err
+}
+
+// DeleteByID は指定されたIDの利用記録を Firestore から削除します。
+func (r *PromotionRedemptionRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている利用記録の総数を返します。
+func (r *PromotionRedemptionRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの利用記録が Firestore に存在するかどうかを確認します。
+func (r *PromotionRedemptionRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPromotionRedemptionRepository - 実際のFirestoreの複雑な実装は不要
+type MockPromotionRedemptionRepository struct {
+	mock.Mock
+}
+
+func NewMockPromotionRedemptionRepository() Repository[models.PromotionRedemption] {
+	return &MockPromotionRedemptionRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockPromotionRedemptionRepository) Create(ctx context.Context, redemption *models.PromotionRedemption) error {
+	args := m.Called(ctx, redemption)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRedemptionRepository) Read(ctx context.Context) ([]*models.PromotionRedemption, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.PromotionRedemption{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.PromotionRedemption), nil
+}
+
+func (m *MockPromotionRedemptionRepository) FindByID(ctx context.Context, id string) (*models.PromotionRedemption, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PromotionRedemption), nil
+}
+
+func (m *MockPromotionRedemptionRepository) FindByField(ctx context.Context, field string, value any) ([]*models.PromotionRedemption, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.PromotionRedemption{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.PromotionRedemption), nil
+}
+
+func (m *MockPromotionRedemptionRepository) UpdateByID(ctx context.Context, id string, redemption *models.PromotionRedemption) error {
+	args := m.Called(ctx, id, redemption)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRedemptionRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRedemptionRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockPromotionRedemptionRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewPromotionRedemptionRepository tests the NewPromotionRedemptionRepository function
+func TestNewPromotionRedemptionRepository(t *testing.T) {
+	t.Run("NewPromotionRedemptionRepository with nil client returns MockPromotionRedemptionRepository", func(t *testing.T) {
+		repo := NewPromotionRedemptionRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockPromotionRedemptionRepository)
+		assert.True(t, ok, "Should return a MockPromotionRedemptionRepository when client is nil")
+	})
+}
+
+// TestMockPromotionRedemptionRepository tests the MockPromotionRedemptionRepository implementation
+func TestMockPromotionRedemptionRepository(t *testing.T) {
+	ctx := context.Background()
+	testRedemption := &models.PromotionRedemption{ID: "redeem_123", PromotionID: "promo_123", SessionID: "session_123"}
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockPromotionRedemptionRepository{}
+		mockRepo.On("FindByField", mock.Anything, "promotion_id", "promo_123").Return([]*models.PromotionRedemption{testRedemption}, nil)
+
+		redemptions, err := mockRepo.FindByField(ctx, "promotion_id", "promo_123")
+		assert.NoError(t, err)
+		assert.Len(t, redemptions, 1)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DeleteByID", func(t *testing.T) {
+		mockRepo := &MockPromotionRedemptionRepository{}
+		mockRepo.On("DeleteByID", mock.Anything, "redeem_123").Return(nil)
+
+		assert.NoError(t, mockRepo.DeleteByID(ctx, "redeem_123"))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestPromotionRedemptionStruct tests the PromotionRedemption struct conversions
+func TestPromotionRedemptionStruct(t *testing.T) {
+	testRedemption := &models.PromotionRedemption{
+		ID:          "redeem_123",
+		PromotionID: "promo_123",
+		Code:        "SPRING10",
+		StoreID:     "store_123",
+		SessionID:   "session_123",
+		SeatID:      "seat_123",
+		VisitID:     "visit_123",
+		DiscountID:  "disc_123",
+		Amount:      models.Yen(218),
+		RedeemedAt:  time.Now(),
+	}
+
+	repoRedemption := ToSetPromotionRedemption(testRedemption)
+	assert.Equal(t, "JPY", repoRedemption.Currency)
+	assert.Equal(t, testRedemption, repoRedemption.ToModel())
+}
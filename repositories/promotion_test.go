@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewPromotionRepository tests the NewPromotionRepository function
+func TestNewPromotionRepository(t *testing.T) {
+	t.Run("NewPromotionRepository with nil client returns MockPromotionRepository", func(t *testing.T) {
+		repo := NewPromotionRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockPromotionRepository)
+		assert.True(t, ok, "Should return a MockPromotionRepository when client is nil")
+	})
+}
+
+// TestMockPromotionRepository tests the MockPromotionRepository implementation
+func TestMockPromotionRepository(t *testing.T) {
+	ctx := context.Background()
+	testPromotion := &models.Promotion{ID: "promo_123", StoreID: "store_123", Code: "SPRING10"}
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockPromotionRepository{}
+		mockRepo.On("FindByField", mock.Anything, "code", "SPRING10").Return([]*models.Promotion{testPromotion}, nil)
+
+		promotions, err := mockRepo.FindByField(ctx, "code", "SPRING10")
+		assert.NoError(t, err)
+		assert.Len(t, promotions, 1)
+		assert.Equal(t, testPromotion.ID, promotions[0].ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestPromotionStruct tests the Promotion struct conversions
+func TestPromotionStruct(t *testing.T) {
+	now := time.Now()
+	testPromotion := &models.Promotion{
+		ID:             "promo_123",
+		StoreID:        "store_123",
+		Code:           "SPRING10",
+		Name:           "春の10%オフ",
+		Rule:           models.DiscountRule{Kind: models.DiscountPercentage, Value: 10, Scope: models.DiscountScopeItem, ProductIDs: []string{"beer"}},
+		Currency:       models.CurrencyJPY,
+		MinSpend:       models.Yen(1000),
+		StartsAt:       now,
+		EndsAt:         now.Add(24 * time.Hour),
+		MaxRedemptions: 100,
+		MaxPerVisit:    1,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	repoPromotion := ToSetPromotion(testPromotion)
+	assert.Equal(t, int64(1000), repoPromotion.MinSpend)
+	assert.Equal(t, testPromotion, repoPromotion.ToModel())
+
+	t.Run("通貨が未設定の場合はJPY", func(t *testing.T) {
+		assert.Equal(t, models.CurrencyJPY, (&Promotion{}).ToModel().Currency)
+	})
+}
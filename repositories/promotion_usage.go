@@ -0,0 +1,120 @@
+package repositories
+
+// promotion_usage.go はプロモーションコードの利用回数の判定と利用記録の作成を実装します。
+// 複数のお客様が同時に同じコードを入力しても利用回数の上限を超えないよう、Firestore のトランザクションで判定と作成を行います。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// PromotionUsageStore はプロモーションの利用記録を利用回数の上限の範囲内で作成するストアです。
+type PromotionUsageStore interface {
+	// Redeem はプロモーションの利用記録を確認し、上限に達していなければ redemption を作成します。
+	// 上限に達している場合は Promotion.CheckUsage のエラーを返します。
+	Redeem(ctx context.Context, promotion *models.Promotion, redemption *models.PromotionRedemption) error
+}
+
+// NewPromotionUsageStore は PromotionUsageStore を生成します。
+// client が nil の場合は redemptions をプロセス内の排他制御で更新するストアを返します。
+func NewPromotionUsageStore(client *firestore.Client, redemptions Repository[models.PromotionRedemption]) PromotionUsageStore {
+	if client == nil {
+		return NewMemoryPromotionUsageStore(redemptions)
+	}
+	return &FirestorePromotionUsageStore{
+		client:     client,
+		collection: "promotion_usages",
+		redemption: "promotion_redemptions",
+	}
+}
+
+// FirestorePromotionUsageStore は Firestore の "promotion_usages" コレクションを使用する PromotionUsageStore です。
+// 利用記録は "promotion_redemptions" コレクションに作成します。
+type FirestorePromotionUsageStore struct {
+	client     *firestore.Client
+	collection string
+	redemption string
+}
+
+// PromotionUsage はプロモーションごとの利用回数です。
+// 同じプロモーションの利用を同時に判定したトランザクションが必ず競合するよう、利用のたびに更新します。
+type PromotionUsage struct {
+	PromotionID string    `firestore:"promotion_id"`
+	Count       int       `firestore:"count"`
+	UpdatedAt   time.Time `firestore:"updated_at"`
+}
+
+// Redeem はトランザクション内で利用回数を判定し、利用記録を作成します。
+func (r *FirestorePromotionUsageStore) Redeem(ctx context.Context, promotion *models.Promotion, redemption *models.PromotionRedemption) error {
+	usageRef := r.client.Collection(GetCollectionName(r.collection)).Doc(promotion.ID)
+	redemptions := r.client.Collection(GetCollectionName(r.redemption))
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		usage := PromotionUsage{PromotionID: promotion.ID}
+		doc, err := tx.Get(usageRef)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&usage); err != nil {
+				return err
+			}
+		}
+
+		docs, err := tx.Documents(redemptions.Where("promotion_id", "==", promotion.ID)).GetAll()
+		if err != nil {
+			return err
+		}
+		existing := make([]*models.PromotionRedemption, len(docs))
+		for i, doc := range docs {
+			record := &PromotionRedemption{}
+			if err := doc.DataTo(record); err != nil {
+				return err
+			}
+			existing[i] = record.ToModel()
+		}
+		if err := promotion.CheckUsage(existing, redemption.SessionID, redemption.VisitID); err != nil {
+			return err
+		}
+
+		// 取り消された利用記録は削除されるため、回数は利用記録の件数から求め直す
+		usage.Count = len(existing) + 1
+		usage.UpdatedAt = time.Now().UTC()
+		if err := tx.Set(usageRef, &usage); err != nil {
+			return err
+		}
+		return tx.Create(redemptions.Doc(redemption.ID), ToSetPromotionRedemption(redemption))
+	})
+}
+
+// MemoryPromotionUsageStore はプロセス内の排他制御で利用回数を判定する PromotionUsageStore です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryPromotionUsageStore struct {
+	mu          sync.Mutex
+	redemptions Repository[models.PromotionRedemption]
+}
+
+func NewMemoryPromotionUsageStore(redemptions Repository[models.PromotionRedemption]) *MemoryPromotionUsageStore {
+	return &MemoryPromotionUsageStore{
+		redemptions: redemptions,
+	}
+}
+
+// Redeem は利用回数を判定し、利用記録を作成します。
+func (s *MemoryPromotionUsageStore) Redeem(ctx context.Context, promotion *models.Promotion, redemption *models.PromotionRedemption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.redemptions.FindByField(ctx, "promotion_id", promotion.ID)
+	if err != nil {
+		return err
+	}
+	if err := promotion.CheckUsage(existing, redemption.SessionID, redemption.VisitID); err != nil {
+		return err
+	}
+	return s.redemptions.Create(ctx, redemption)
+}
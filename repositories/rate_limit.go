@@ -0,0 +1,203 @@
+package repositories
+
+// rate_limit.go はレート制限カウンタとサインイン失敗履歴の永続化を実装します。
+// Cloud Run で複数インスタンスが起動しても制限値を共有できるよう、Firestore のトランザクションで更新します。
+
+import (
+	"backend/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// RateLimitStore は固定ウィンドウ方式のリクエストカウンタを保持するストアです。
+type RateLimitStore interface {
+	// Increment は key のカウンタを1つ進め、現在のウィンドウ内での回数とウィンドウの終了時刻を返します。
+	Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error)
+}
+
+// LoginAttemptStore はアカウント単位のサインイン失敗履歴を保持するストアです。
+type LoginAttemptStore interface {
+	// Get は key の失敗履歴を返します。履歴がない場合は空の LoginAttempt を返します。
+	Get(ctx context.Context, key string) (*models.LoginAttempt, error)
+	Save(ctx context.Context, attempt *models.LoginAttempt) error
+	// RegisterFailure は key の失敗を1回記録し、記録後の失敗履歴を返します。
+	// 同時に失敗したリクエストの回数を取りこぼさないよう、読み取りと書き込みを不可分に行います。
+	RegisterFailure(ctx context.Context, key string, now time.Time, policy models.LockoutPolicy) (*models.LoginAttempt, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewRateLimitStore は RateLimitStore を生成します。
+// client が nil の場合はプロセス内のメモリで保持するストアを返します。
+func NewRateLimitStore(client *firestore.Client) RateLimitStore {
+	if client == nil {
+		return NewMemoryRateLimitStore()
+	}
+	return &FirestoreRateLimitStore{
+		client:     client,
+		collection: "rate_limits",
+	}
+}
+
+// NewLoginAttemptStore は LoginAttemptStore を生成します。
+// client が nil の場合はプロセス内のメモリで保持するストアを返します。
+func NewLoginAttemptStore(client *firestore.Client) LoginAttemptStore {
+	if client == nil {
+		return NewMemoryLoginAttemptStore()
+	}
+	return &FirestoreLoginAttemptStore{
+		client:     client,
+		collection: "login_attempts",
+	}
+}
+
+// rateLimitDocID はキーをドキュメントIDとして安全な文字列に変換します。
+// IPアドレスやメールアドレスには Firestore のIDに使えない文字が含まれうるためハッシュ化します。
+func rateLimitDocID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// FirestoreRateLimitStore は Firestore の "rate_limits" コレクションを使用する RateLimitStore です。
+type FirestoreRateLimitStore struct {
+	client     *firestore.Client
+	collection string
+}
+
+// RateLimitBucket はウィンドウごとのカウンタです。
+// 終了したウィンドウのカウンタが残り続けないよう、reset_at に TTL ポリシーを設定します。
+type RateLimitBucket struct {
+	Key     string    `firestore:"key"`
+	Count   int       `firestore:"count"`
+	ResetAt time.Time `firestore:"reset_at"`
+}
+
+// Increment はトランザクション内でカウンタを更新します。
+func (r *FirestoreRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(rateLimitDocID(key))
+
+	var bucket RateLimitBucket
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		bucket = RateLimitBucket{Key: key}
+		doc, err := tx.Get(ref)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&bucket); err != nil {
+				return err
+			}
+		}
+
+		// ウィンドウが終了していれば新しいウィンドウを開始する
+		if bucket.ResetAt.IsZero() || !now.Before(bucket.ResetAt) {
+			bucket.Count = 0
+			bucket.ResetAt = now.Add(window)
+		}
+		bucket.Count++
+
+		return tx.Set(ref, &bucket)
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return bucket.Count, bucket.ResetAt, nil
+}
+
+// FirestoreLoginAttemptStore は Firestore の "login_attempts" コレクションを使用する LoginAttemptStore です。
+type FirestoreLoginAttemptStore struct {
+	client     *firestore.Client
+	collection string
+}
+
+// LoginAttempt は失敗履歴のドキュメントです。
+// 不要になった失敗履歴が残り続けないよう、expires_at に TTL ポリシーを設定します。
+type LoginAttempt struct {
+	Key          string    `firestore:"key"`
+	Failures     int       `firestore:"failures"`
+	LockedUntil  time.Time `firestore:"locked_until"`
+	LastFailedAt time.Time `firestore:"last_failed_at"`
+	ExpiresAt    time.Time `firestore:"expires_at"`
+}
+
+func ToSetLoginAttempt(a *models.LoginAttempt) *LoginAttempt {
+	return &LoginAttempt{
+		Key:          a.Key,
+		Failures:     a.Failures,
+		LockedUntil:  a.LockedUntil,
+		LastFailedAt: a.LastFailedAt,
+		ExpiresAt:    a.ExpiresAt,
+	}
+}
+
+func (a *LoginAttempt) ToModel() *models.LoginAttempt {
+	return &models.LoginAttempt{
+		Key:          a.Key,
+		Failures:     a.Failures,
+		LockedUntil:  a.LockedUntil,
+		LastFailedAt: a.LastFailedAt,
+		ExpiresAt:    a.ExpiresAt,
+	}
+}
+
+// Get は失敗履歴を取得します。
+func (r *FirestoreLoginAttemptStore) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(rateLimitDocID(key)).Get(ctx)
+	if IsNotFound(err) {
+		return models.NewLoginAttempt(key), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := &LoginAttempt{}
+	if err := doc.DataTo(attempt); err != nil {
+		return nil, err
+	}
+	return attempt.ToModel(), nil
+}
+
+// Save は失敗履歴を保存します。
+func (r *FirestoreLoginAttemptStore) Save(ctx context.Context, attempt *models.LoginAttempt) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(rateLimitDocID(attempt.Key)).Set(ctx, ToSetLoginAttempt(attempt))
+	return err
+}
+
+// RegisterFailure はトランザクション内で失敗回数を進め、ロック期限を更新します。
+func (r *FirestoreLoginAttemptStore) RegisterFailure(ctx context.Context, key string, now time.Time, policy models.LockoutPolicy) (*models.LoginAttempt, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(rateLimitDocID(key))
+
+	var attempt *models.LoginAttempt
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		attempt = models.NewLoginAttempt(key)
+		doc, err := tx.Get(ref)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			stored := &LoginAttempt{}
+			if err := doc.DataTo(stored); err != nil {
+				return err
+			}
+			attempt = stored.ToModel()
+		}
+
+		attempt.RegisterFailure(now, policy)
+		return tx.Set(ref, ToSetLoginAttempt(attempt))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// Delete は失敗履歴を削除します。サインイン成功時に呼び出されます。
+func (r *FirestoreLoginAttemptStore) Delete(ctx context.Context, key string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(rateLimitDocID(key)).Delete(ctx)
+	return err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryBucketSweepThreshold を超えるキーが溜まった場合に期限切れのカウンタを掃除します。
+const memoryBucketSweepThreshold = 10000
+
+// MemoryRateLimitStore はプロセス内のメモリでカウンタを保持する RateLimitStore です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*RateLimitBucket
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*RateLimitBucket),
+	}
+}
+
+// Increment は key のカウンタを1つ進めます。
+func (s *MemoryRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.buckets) > memoryBucketSweepThreshold {
+		for k, b := range s.buckets {
+			if !now.Before(b.ResetAt) {
+				delete(s.buckets, k)
+			}
+		}
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok || !now.Before(bucket.ResetAt) {
+		bucket = &RateLimitBucket{Key: key, ResetAt: now.Add(window)}
+		s.buckets[key] = bucket
+	}
+	bucket.Count++
+
+	return bucket.Count, bucket.ResetAt, nil
+}
+
+// MemoryLoginAttemptStore はプロセス内のメモリで失敗履歴を保持する LoginAttemptStore です。
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]models.LoginAttempt
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		attempts: make(map[string]models.LoginAttempt),
+	}
+}
+
+// Get は失敗履歴のコピーを返します。
+func (s *MemoryLoginAttemptStore) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return models.NewLoginAttempt(key), nil
+	}
+	return &attempt, nil
+}
+
+// Save は失敗履歴を保存します。
+func (s *MemoryLoginAttemptStore) Save(ctx context.Context, attempt *models.LoginAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts[attempt.Key] = *attempt
+	return nil
+}
+
+// RegisterFailure は key の失敗を1回記録し、記録後の失敗履歴のコピーを返します。
+func (s *MemoryLoginAttemptStore) RegisterFailure(ctx context.Context, key string, now time.Time, policy models.LockoutPolicy) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.attempts) > memoryBucketSweepThreshold {
+		for k, a := range s.attempts {
+			if !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt) {
+				delete(s.attempts, k)
+			}
+		}
+	}
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = *models.NewLoginAttempt(key)
+	}
+	attempt.RegisterFailure(now, policy)
+	s.attempts[key] = attempt
+	return &attempt, nil
+}
+
+// Delete は失敗履歴を削除します。
+func (s *MemoryLoginAttemptStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewRateLimitStore tests the NewRateLimitStore and NewLoginAttemptStore functions
+func TestNewRateLimitStore(t *testing.T) {
+	t.Run("nil client returns memory stores", func(t *testing.T) {
+		_, ok := NewRateLimitStore(nil).(*MemoryRateLimitStore)
+		assert.True(t, ok, "Should return a MemoryRateLimitStore when client is nil")
+
+		_, ok = NewLoginAttemptStore(nil).(*MemoryLoginAttemptStore)
+		assert.True(t, ok, "Should return a MemoryLoginAttemptStore when client is nil")
+	})
+}
+
+// TestMemoryRateLimitStore tests the fixed window counter
+func TestMemoryRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	t.Run("counts within window", func(t *testing.T) {
+		store := NewMemoryRateLimitStore()
+
+		count, resetAt, err := store.Increment(ctx, "ip:127.0.0.1", time.Minute, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, now.Add(time.Minute), resetAt)
+
+		count, resetAt2, err := store.Increment(ctx, "ip:127.0.0.1", time.Minute, now.Add(10*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, resetAt, resetAt2, "同じウィンドウでは終了時刻は変わらない")
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		store := NewMemoryRateLimitStore()
+
+		_, _, _ = store.Increment(ctx, "ip:127.0.0.1", time.Minute, now)
+		count, _, err := store.Increment(ctx, "ip:127.0.0.2", time.Minute, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("window resets", func(t *testing.T) {
+		store := NewMemoryRateLimitStore()
+
+		_, _, _ = store.Increment(ctx, "seat:store_1:seat_1", time.Minute, now)
+		count, resetAt, err := store.Increment(ctx, "seat:store_1:seat_1", time.Minute, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, now.Add(2*time.Minute), resetAt)
+	})
+}
+
+// TestMemoryLoginAttemptStore tests the login attempt CRUD operations
+func TestMemoryLoginAttemptStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore()
+
+	attempt, err := store.Get(ctx, "manager@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "manager@example.com", attempt.Key)
+	assert.Zero(t, attempt.Failures)
+
+	attempt.RegisterFailure(time.Now().UTC(), models.DefaultLockoutPolicy())
+	require.NoError(t, store.Save(ctx, attempt))
+
+	got, err := store.Get(ctx, "manager@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Failures)
+
+	require.NoError(t, store.Delete(ctx, "manager@example.com"))
+	got, err = store.Get(ctx, "manager@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, got.Failures)
+}
+
+// TestLoginAttemptConversions tests the Firestore struct conversions
+func TestLoginAttemptConversions(t *testing.T) {
+	now := time.Now().UTC()
+	attempt := &models.LoginAttempt{
+		Key:          "manager@example.com",
+		Failures:     3,
+		LockedUntil:  now.Add(time.Minute),
+		LastFailedAt: now,
+	}
+
+	assert.Equal(t, attempt, ToSetLoginAttempt(attempt).ToModel())
+	assert.Len(t, rateLimitDocID("ip:::1"), 64)
+}
+
+// TestMemoryLoginAttemptStore_RegisterFailure tests that concurrent failures are all counted
+func TestMemoryLoginAttemptStore_RegisterFailure(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore()
+	now := time.Now().UTC()
+	policy := models.LockoutPolicy{MaxFailures: 10, BaseDuration: time.Minute, MaxDuration: time.Hour}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.RegisterFailure(ctx, "manager@example.com", now, policy)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	got, err := store.Get(ctx, "manager@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 10, got.Failures)
+	assert.True(t, got.IsLocked(now), "記録後の回数でロックを判定する")
+}
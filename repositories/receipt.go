@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ReceiptRepository は Firestore の receipts コレクションを操作するためのリポジトリです。
+// 発行した領収書を保存し、再発行時は保存した内容から同じ領収書を印字します。
+type ReceiptRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewReceiptRepository は新しい ReceiptRepository のインスタンスを生成します。
+func NewReceiptRepository(client *firestore.Client) Repository[models.Receipt] {
+	if client == nil {
+		return NewMockReceiptRepository()
+	}
+	return &ReceiptRepository{
+		client:     client,
+		collection: "receipts",
+	}
+}
+
+type Receipt struct {
+	ID        string `firestore:"id"`
+	Number    int64  `firestore:"number"`
+	StoreID   string `firestore:"store_id"`
+	SessionID string `firestore:"session_id"`
+
+	IssuerName               string `firestore:"issuer_name"`
+	IssuerRegistrationNumber string `firestore:"issuer_registration_number"`
+	IssuerAddress            string `firestore:"issuer_address"`
+	IssuerPhone              string `firestore:"issuer_phone"`
+	RecipientName            string `firestore:"recipient_name"`
+
+	TransactionAt time.Time     `firestore:"transaction_at"`
+	DiningOption  string        `firestore:"dining_option"`
+	PriceMode     string        `firestore:"price_mode"`
+	Currency      string        `firestore:"currency"`
+	Lines         []ReceiptLine `firestore:"lines"`
+	Taxes         []TaxLine     `firestore:"taxes"`
+	TaxTotal      int64         `firestore:"tax_total"`
+	Total         int64         `firestore:"total"`
+
+	ReissueCount   int       `firestore:"reissue_count"`
+	LastReissuedAt time.Time `firestore:"last_reissued_at"`
+
+	IssuedAt  time.Time `firestore:"issued_at"`
+	CreatedAt time.Time `firestore:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+type ReceiptLine struct {
+	ProductID   string `firestore:"product_id"`
+	Name        string `firestore:"name"`
+	Quantity    int    `firestore:"quantity"`
+	UnitPrice   int64  `firestore:"unit_price"`
+	Amount      int64  `firestore:"amount"`
+	RateCode    string `firestore:"rate_code"`
+	RatePercent int64  `firestore:"rate_percent"`
+	Reduced     bool   `firestore:"reduced"`
+}
+
+func ToSetReceipt(r *models.Receipt) *Receipt {
+	lines := make([]ReceiptLine, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = ReceiptLine{
+			ProductID:   line.ProductID,
+			Name:        line.Name,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice.Amount,
+			Amount:      line.Amount.Amount,
+			RateCode:    string(line.TaxRate.Code),
+			RatePercent: line.TaxRate.Percent,
+			Reduced:     line.Reduced,
+		}
+	}
+
+	return &Receipt{
+		ID:        r.ID,
+		Number:    r.Number,
+		StoreID:   r.StoreID,
+		SessionID: r.SessionID,
+
+		IssuerName:               r.Issuer.Name,
+		IssuerRegistrationNumber: r.Issuer.RegistrationNumber,
+		IssuerAddress:            r.Issuer.Address,
+		IssuerPhone:              r.Issuer.Phone,
+		RecipientName:            r.RecipientName,
+
+		TransactionAt: r.TransactionAt,
+		DiningOption:  string(r.DiningOption),
+		PriceMode:     string(r.PriceMode),
+		Currency:      string(r.Total.Currency),
+		Lines:         lines,
+		Taxes:         ToSetTaxLines(r.Taxes),
+		TaxTotal:      r.TaxTotal.Amount,
+		Total:         r.Total.Amount,
+
+		ReissueCount:   r.ReissueCount,
+		LastReissuedAt: r.LastReissuedAt,
+
+		IssuedAt:  r.IssuedAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func (r *Receipt) ToModel() *models.Receipt {
+	lines := make([]models.ReceiptLine, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = models.ReceiptLine{
+			ProductID: line.ProductID,
+			Name:      line.Name,
+			Quantity:  line.Quantity,
+			UnitPrice: ToModelMoney(line.UnitPrice, r.Currency),
+			Amount:    ToModelMoney(line.Amount, r.Currency),
+			TaxRate:   models.TaxRate{Code: models.TaxRateCode(line.RateCode), Percent: line.RatePercent},
+			Reduced:   line.Reduced,
+		}
+	}
+
+	return &models.Receipt{
+		ID:        r.ID,
+		Number:    r.Number,
+		StoreID:   r.StoreID,
+		SessionID: r.SessionID,
+
+		Issuer: models.ReceiptIssuer{
+			StoreID:            r.StoreID,
+			Name:               r.IssuerName,
+			RegistrationNumber: r.IssuerRegistrationNumber,
+			Address:            r.IssuerAddress,
+			Phone:              r.IssuerPhone,
+		},
+		RecipientName: r.RecipientName,
+
+		TransactionAt: r.TransactionAt,
+		DiningOption:  models.DiningOption(r.DiningOption),
+		PriceMode:     models.PriceMode(r.PriceMode),
+		Lines:         lines,
+		Taxes:         ToModelTaxLines(r.Taxes, r.Currency),
+		TaxTotal:      ToModelMoney(r.TaxTotal, r.Currency),
+		Total:         ToModelMoney(r.Total, r.Currency),
+
+		ReissueCount:   r.ReissueCount,
+		LastReissuedAt: r.LastReissuedAt,
+
+		IssuedAt:  r.IssuedAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+// Create は新しい領収書を Firestore に作成します。
+// 同じ注文の領収書を二重に発行しないよう、同じIDの領収書が作成済みの場合は codes.AlreadyExists のエラーを返します。
+func (r *ReceiptRepository) Create(ctx context.Context, receipt *models.Receipt) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(receipt.ID).Create(ctx, ToSetReceipt(receipt))
+	return err
+}
+
+// Read はすべての領収書を Firestore から読み取ります。
+func (r *ReceiptRepository) Read(ctx context.Context) ([]*models.Receipt, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*models.Receipt, len(docs))
+	for i, doc := range docs {
+		receipt := &Receipt{}
+		if err := doc.DataTo(receipt); err != nil {
+			return nil, err
+		}
+		receipts[i] = receipt.ToModel()
+	}
+
+	return receipts, nil
+}
+
+// FindByID は指定されたIDの領収書を Firestore から検索します。
+func (r *ReceiptRepository) FindByID(ctx context.Context, id string) (*models.Receipt, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{}
+	if err := doc.DataTo(receipt); err != nil {
+		return nil, err
+	}
+
+	return receipt.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する領収書を Firestore から検索します。
+func (r *ReceiptRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Receipt, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*models.Receipt, len(docs))
+	for i, doc := range docs {
+		receipt := &Receipt{}
+		if err := doc.DataTo(receipt); err != nil {
+			return nil, err
+		}
+		receipts[i] = receipt.ToModel()
+	}
+
+	return receipts, nil
+}
+
+// UpdateByID は指定されたIDの領収書を Firestore で更新します。
+func (r *ReceiptRepository) UpdateByID(ctx context.Context, id string, receipt *models.Receipt) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetReceipt(receipt))
+	return err
+}
+
+// DeleteByID は指定されたIDの領収書を Firestore から削除します。
+func (r *ReceiptRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている領収書の総数を返します。
+func (r *ReceiptRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの領収書が Firestore に存在するかどうかを確認します。
+func (r *ReceiptRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+// receipt_issue.go は領収書番号の採番と領収書の作成を不可分に行う発行を実装します。
+// 採番した後に領収書の作成に失敗して番号が欠けることのないよう、Firestore のトランザクションで
+// 連番の更新と領収書の作成をまとめて行います。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ReceiptIssuer は領収書に連番を採番して作成するストアです。
+type ReceiptIssuer interface {
+	// Issue は key の連番を1つ進めて receipt.Number に設定し、領収書を作成します。
+	// 同じIDの領収書がすでにある場合は codes.AlreadyExists のエラーを返し、連番は進めません。
+	Issue(ctx context.Context, key string, receipt *models.Receipt) error
+}
+
+// NewReceiptIssuer は ReceiptIssuer を生成します。
+// client が nil の場合は連番をプロセス内のメモリで保持し、receipts に領収書を作成するストアを返します。
+func NewReceiptIssuer(client *firestore.Client, receipts Repository[models.Receipt]) ReceiptIssuer {
+	if client == nil {
+		return NewMemoryReceiptIssuer(receipts)
+	}
+	return &FirestoreReceiptIssuer{
+		client:     client,
+		collection: "receipts",
+		sequences:  "sequences",
+	}
+}
+
+// FirestoreReceiptIssuer は Firestore の "sequences" コレクションで採番し、"receipts" コレクションに領収書を作成する ReceiptIssuer です。
+type FirestoreReceiptIssuer struct {
+	client     *firestore.Client
+	collection string
+	sequences  string
+}
+
+// Issue はトランザクション内で連番を更新し、領収書を作成します。
+func (r *FirestoreReceiptIssuer) Issue(ctx context.Context, key string, receipt *models.Receipt) error {
+	seqRef := r.client.Collection(GetCollectionName(r.sequences)).Doc(key)
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(receipt.ID)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		seq := Sequence{Key: key}
+		doc, err := tx.Get(seqRef)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&seq); err != nil {
+				return err
+			}
+		}
+
+		seq.Value++
+		seq.UpdatedAt = time.Now().UTC()
+		receipt.Number = seq.Value
+		if err := tx.Create(ref, ToSetReceipt(receipt)); err != nil {
+			return err
+		}
+		return tx.Set(seqRef, &seq)
+	})
+}
+
+// MemoryReceiptIssuer はプロセス内の排他制御で採番し、領収書を作成する ReceiptIssuer です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryReceiptIssuer struct {
+	mu       sync.Mutex
+	values   map[string]int64
+	receipts Repository[models.Receipt]
+}
+
+func NewMemoryReceiptIssuer(receipts Repository[models.Receipt]) *MemoryReceiptIssuer {
+	return &MemoryReceiptIssuer{
+		values:   make(map[string]int64),
+		receipts: receipts,
+	}
+}
+
+// Issue は連番を採番して領収書を作成します。作成に失敗した場合は連番を進めません。
+func (s *MemoryReceiptIssuer) Issue(ctx context.Context, key string, receipt *models.Receipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipt.Number = s.values[key] + 1
+	if err := s.receipts.Create(ctx, receipt); err != nil {
+		receipt.Number = 0
+		return err
+	}
+	s.values[key] = receipt.Number
+	return nil
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewReceiptIssuer tests the NewReceiptIssuer function
+func TestNewReceiptIssuer(t *testing.T) {
+	t.Run("nil client returns memory issuer", func(t *testing.T) {
+		_, ok := NewReceiptIssuer(nil, NewMockReceiptRepository()).(*MemoryReceiptIssuer)
+		assert.True(t, ok, "Should return a MemoryReceiptIssuer when client is nil")
+	})
+}
+
+// TestMemoryReceiptIssuer_Issue tests that a number is consumed only when the receipt is created
+func TestMemoryReceiptIssuer_Issue(t *testing.T) {
+	ctx := context.Background()
+
+	receipts := NewMockReceiptRepository().(*MockReceiptRepository)
+	receipts.On("Create", ctx, mock.MatchedBy(func(r *models.Receipt) bool { return r.ID == "receipt_fail" })).Return(errors.New("unavailable"))
+	receipts.On("Create", ctx, mock.AnythingOfType("*models.Receipt")).Return(nil)
+	issuer := NewMemoryReceiptIssuer(receipts)
+
+	first := &models.Receipt{ID: "receipt_1"}
+	require.NoError(t, issuer.Issue(ctx, "receipt:store_1", first))
+	assert.Equal(t, int64(1), first.Number)
+
+	failed := &models.Receipt{ID: "receipt_fail"}
+	assert.Error(t, issuer.Issue(ctx, "receipt:store_1", failed))
+	assert.Zero(t, failed.Number)
+
+	second := &models.Receipt{ID: "receipt_2"}
+	require.NoError(t, issuer.Issue(ctx, "receipt:store_1", second))
+	assert.Equal(t, int64(2), second.Number, "作成に失敗した番号は欠番にしない")
+
+	other := &models.Receipt{ID: "receipt_3"}
+	require.NoError(t, issuer.Issue(ctx, "receipt:store_2", other))
+	assert.Equal(t, int64(1), other.Number, "キーごとに独立して採番する")
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReceiptRepository - 実際のFirestoreの複雑な実装は不要
+type MockReceiptRepository struct {
+	mock.Mock
+}
+
+func NewMockReceiptRepository() Repository[models.Receipt] {
+	return &MockReceiptRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockReceiptRepository) Create(ctx context.Context, receipt *models.Receipt) error {
+	args := m.Called(ctx, receipt)
+	return args.Error(0)
+}
+
+func (m *MockReceiptRepository) Read(ctx context.Context) ([]*models.Receipt, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.Receipt{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Receipt), nil
+}
+
+func (m *MockReceiptRepository) FindByID(ctx context.Context, id string) (*models.Receipt, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Receipt), nil
+}
+
+func (m *MockReceiptRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Receipt, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.Receipt{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Receipt), nil
+}
+
+func (m *MockReceiptRepository) UpdateByID(ctx context.Context, id string, receipt *models.Receipt) error {
+	args := m.Called(ctx, id, receipt)
+	return args.Error(0)
+}
+
+func (m *MockReceiptRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReceiptRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockReceiptRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewReceiptRepository tests the NewReceiptRepository function
+func TestNewReceiptRepository(t *testing.T) {
+	t.Run("NewReceiptRepository with nil client returns MockReceiptRepository", func(t *testing.T) {
+		repo := NewReceiptRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockReceiptRepository)
+		assert.True(t, ok, "Should return a MockReceiptRepository when client is nil")
+	})
+}
+
+// TestMockReceiptRepository tests the MockReceiptRepository implementation
+func TestMockReceiptRepository(t *testing.T) {
+	ctx := context.Background()
+	testReceipt := &models.Receipt{ID: "receipt_123", Number: 1, StoreID: "store_123", SessionID: "session_123"}
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockReceiptRepository{}
+		mockRepo.On("FindByField", mock.Anything, "session_id", "session_123").Return([]*models.Receipt{testReceipt}, nil)
+
+		receipts, err := mockRepo.FindByField(ctx, "session_id", "session_123")
+		assert.NoError(t, err)
+		assert.Len(t, receipts, 1)
+		assert.Equal(t, testReceipt.ID, receipts[0].ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateByID", func(t *testing.T) {
+		mockRepo := &MockReceiptRepository{}
+		mockRepo.On("UpdateByID", mock.Anything, "receipt_123", testReceipt).Return(nil)
+
+		assert.NoError(t, mockRepo.UpdateByID(ctx, "receipt_123", testReceipt))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestReceiptStruct tests the Receipt struct conversions
+func TestReceiptStruct(t *testing.T) {
+	now := time.Now().UTC()
+	testReceipt := &models.Receipt{
+		ID:        "receipt_123",
+		Number:    42,
+		StoreID:   "store_123",
+		SessionID: "session_123",
+		Issuer: models.ReceiptIssuer{
+			StoreID:            "store_123",
+			Name:               "テスト食堂",
+			RegistrationNumber: "T1234567890123",
+			Address:            "東京都千代田区1-1",
+			Phone:              "03-0000-0000",
+		},
+		RecipientName: "株式会社テスト",
+		TransactionAt: now,
+		DiningOption:  models.DiningTakeout,
+		PriceMode:     models.PriceModeInclusive,
+		Lines: []models.ReceiptLine{
+			{
+				ProductID: "bento",
+				Quantity:  2,
+				UnitPrice: models.Yen(540),
+				Amount:    models.Yen(1080),
+				TaxRate:   models.TaxRate{Code: models.TaxRateReduced, Percent: 8},
+				Reduced:   true,
+			},
+			{
+				Name:      "お通し",
+				Quantity:  2,
+				UnitPrice: models.Yen(300),
+				Amount:    models.Yen(600),
+				TaxRate:   models.TaxRate{Code: models.TaxRateStandard, Percent: 10},
+			},
+		},
+		Taxes: []models.TaxLine{
+			{Rate: models.TaxRate{Code: models.TaxRateReduced, Percent: 8}, Net: models.Yen(1000), Tax: models.Yen(80), Gross: models.Yen(1080)},
+		},
+		TaxTotal:       models.Yen(80),
+		Total:          models.Yen(1080),
+		ReissueCount:   1,
+		LastReissuedAt: now.Add(time.Hour),
+		IssuedAt:       now,
+		CreatedAt:      now,
+		UpdatedAt:      now.Add(time.Hour),
+	}
+
+	repoReceipt := ToSetReceipt(testReceipt)
+	assert.Equal(t, "JPY", repoReceipt.Currency)
+	assert.Equal(t, int64(1080), repoReceipt.Total)
+	assert.Equal(t, testReceipt, repoReceipt.ToModel())
+}
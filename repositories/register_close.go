@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// RegisterCloseRepository は Firestore の register_closes コレクションを操作するためのリポジトリです。
+// ドキュメントIDは店舗IDと営業日から決まり（models.RegisterCloseID）、営業日ごとに1件のみ作成します。
+type RegisterCloseRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewRegisterCloseRepository は新しい RegisterCloseRepository のインスタンスを生成します。
+func NewRegisterCloseRepository(client *firestore.Client) Repository[models.RegisterClose] {
+	if client == nil {
+		return NewMockRegisterCloseRepository()
+	}
+	return &RegisterCloseRepository{
+		client:     client,
+		collection: "register_closes",
+	}
+}
+
+type RegisterClose struct {
+	ID          string `firestore:"id"`
+	StoreID     string `firestore:"store_id"`
+	BusinessDay string `firestore:"business_day"`
+
+	Currency     string `firestore:"currency"`
+	OpeningFloat int64  `firestore:"opening_float"`
+	CashSales    int64  `firestore:"cash_sales"`
+	CashRefunds  int64  `firestore:"cash_refunds"`
+	ExpectedCash int64  `firestore:"expected_cash"`
+	CountedCash  int64  `firestore:"counted_cash"`
+	OverShort    int64  `firestore:"over_short"`
+
+	Report ZReport `firestore:"report"`
+	Note   string  `firestore:"note"`
+	Actor  string  `firestore:"actor"`
+
+	ClosedAt time.Time `firestore:"closed_at"`
+}
+
+type ZReport struct {
+	StoreName   string    `firestore:"store_name"`
+	PeriodStart time.Time `firestore:"period_start"`
+	PeriodEnd   time.Time `firestore:"period_end"`
+
+	OrderCount int `firestore:"order_count"`
+	GuestCount int `firestore:"guest_count"`
+
+	GrossSales int64     `firestore:"gross_sales"`
+	Taxes      []TaxLine `firestore:"taxes"`
+
+	DiscountCount int   `firestore:"discount_count"`
+	Discounts     int64 `firestore:"discounts"`
+	RefundCount   int   `firestore:"refund_count"`
+	Refunds       int64 `firestore:"refunds"`
+	CashRefunds   int64 `firestore:"cash_refunds"`
+	NetSales      int64 `firestore:"net_sales"`
+
+	VoidCount int   `firestore:"void_count"`
+	Voids     int64 `firestore:"voids"`
+
+	Payments []PaymentMethodTotal `firestore:"payments"`
+
+	OpenOrderCount int `firestore:"open_order_count"`
+}
+
+type PaymentMethodTotal struct {
+	Method string `firestore:"method"`
+	Count  int    `firestore:"count"`
+	Amount int64  `firestore:"amount"`
+}
+
+func ToSetRegisterClose(c *models.RegisterClose) *RegisterClose {
+	r := c.Report
+	payments := make([]PaymentMethodTotal, len(r.Payments))
+	for i, p := range r.Payments {
+		payments[i] = PaymentMethodTotal{Method: string(p.Method), Count: p.Count, Amount: p.Amount.Amount}
+	}
+
+	return &RegisterClose{
+		ID:          c.ID,
+		StoreID:     c.StoreID,
+		BusinessDay: c.BusinessDay,
+
+		Currency:     string(r.GrossSales.Currency),
+		OpeningFloat: c.OpeningFloat.Amount,
+		CashSales:    c.CashSales.Amount,
+		CashRefunds:  c.CashRefunds.Amount,
+		ExpectedCash: c.ExpectedCash.Amount,
+		CountedCash:  c.CountedCash.Amount,
+		OverShort:    c.OverShort.Amount,
+
+		Report: ZReport{
+			StoreName:   r.StoreName,
+			PeriodStart: r.PeriodStart,
+			PeriodEnd:   r.PeriodEnd,
+
+			OrderCount: r.OrderCount,
+			GuestCount: r.GuestCount,
+
+			GrossSales: r.GrossSales.Amount,
+			Taxes:      ToSetTaxLines(r.Taxes),
+
+			DiscountCount: r.DiscountCount,
+			Discounts:     r.Discounts.Amount,
+			RefundCount:   r.RefundCount,
+			Refunds:       r.Refunds.Amount,
+			CashRefunds:   r.CashRefunds.Amount,
+			NetSales:      r.NetSales.Amount,
+
+			VoidCount: r.VoidCount,
+			Voids:     r.Voids.Amount,
+
+			Payments: payments,
+
+			OpenOrderCount: r.OpenOrderCount,
+		},
+		Note:  c.Note,
+		Actor: c.Actor,
+
+		ClosedAt: c.ClosedAt,
+	}
+}
+
+func (c *RegisterClose) ToModel() *models.RegisterClose {
+	r := c.Report
+	var payments []models.PaymentMethodTotal
+	for _, p := range r.Payments {
+		payments = append(payments, models.PaymentMethodTotal{
+			Method: models.TenderMethod(p.Method),
+			Count:  p.Count,
+			Amount: ToModelMoney(p.Amount, c.Currency),
+		})
+	}
+
+	return &models.RegisterClose{
+		ID:          c.ID,
+		StoreID:     c.StoreID,
+		BusinessDay: c.BusinessDay,
+
+		OpeningFloat: ToModelMoney(c.OpeningFloat, c.Currency),
+		CashSales:    ToModelMoney(c.CashSales, c.Currency),
+		CashRefunds:  ToModelMoney(c.CashRefunds, c.Currency),
+		ExpectedCash: ToModelMoney(c.ExpectedCash, c.Currency),
+		CountedCash:  ToModelMoney(c.CountedCash, c.Currency),
+		OverShort:    ToModelMoney(c.OverShort, c.Currency),
+
+		Report: models.ZReport{
+			StoreID:     c.StoreID,
+			StoreName:   r.StoreName,
+			BusinessDay: c.BusinessDay,
+			PeriodStart: r.PeriodStart,
+			PeriodEnd:   r.PeriodEnd,
+
+			OrderCount: r.OrderCount,
+			GuestCount: r.GuestCount,
+
+			GrossSales: ToModelMoney(r.GrossSales, c.Currency),
+			Taxes:      ToModelTaxLines(r.Taxes, c.Currency),
+
+			DiscountCount: r.DiscountCount,
+			Discounts:     ToModelMoney(r.Discounts, c.Currency),
+			RefundCount:   r.RefundCount,
+			Refunds:       ToModelMoney(r.Refunds, c.Currency),
+			CashRefunds:   ToModelMoney(r.CashRefunds, c.Currency),
+			NetSales:      ToModelMoney(r.NetSales, c.Currency),
+
+			VoidCount: r.VoidCount,
+			Voids:     ToModelMoney(r.Voids, c.Currency),
+
+			Payments: payments,
+
+			OpenOrderCount: r.OpenOrderCount,
+		},
+		Note:  c.Note,
+		Actor: c.Actor,
+
+		ClosedAt: c.ClosedAt,
+	}
+}
+
+// Create は新しいレジ締めの記録を Firestore に作成します。
+// 同じ営業日の記録がすでにある場合は codes.AlreadyExists のエラーを返します。
+func (r *RegisterCloseRepository) Create(ctx context.Context, registerClose *models.RegisterClose) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(registerClose.ID).Create(ctx, ToSetRegisterClose(registerClose))
+	return err
+}
+
+// Read はすべてのレジ締めの記録を Firestore から読み取ります。
+func (r *RegisterCloseRepository) Read(ctx context.Context) ([]*models.RegisterClose, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	registerCloses := make([]*models.RegisterClose, len(docs))
+	for i, doc := range docs {
+		registerClose := &RegisterClose{}
+		if err := doc.DataTo(registerClose); err != nil {
+			return nil, err
+		}
+		registerCloses[i] = registerClose.ToModel()
+	}
+
+	return registerCloses, nil
+}
+
+// FindByID は指定されたIDのレジ締めの記録を Firestore から検索します。
+func (r *RegisterCloseRepository) FindByID(ctx context.Context, id string) (*models.RegisterClose, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registerClose := &RegisterClose{}
+	if err := doc.DataTo(registerClose); err != nil {
+		return nil, err
+	}
+
+	return registerClose.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致するレジ締めの記録を Firestore から検索します。
+func (r *RegisterCloseRepository) FindByField(ctx context.Context, field string, value any) ([]*models.RegisterClose, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	registerCloses := make([]*models.RegisterClose, len(docs))
+	for i, doc := range docs {
+		registerClose := &RegisterClose{}
+		if err := doc.DataTo(registerClose); err != nil {
+			return nil, err
+		}
+		registerCloses[i] = registerClose.ToModel()
+	}
+
+	return registerCloses, nil
+}
+
+// UpdateByID は指定されたIDのレジ締めの記録を Firestore で更新します。
+func (r *RegisterCloseRepository) UpdateByID(ctx context.Context, id string, registerClose *models.RegisterClose) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetRegisterClose(registerClose))
+	return err
+}
+
+// DeleteByID は指定されたIDのレジ締めの記録を Firestore から削除します。
+func (r *RegisterCloseRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されているレジ締めの記録の総数を返します。
+func (r *RegisterCloseRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDのレジ締めの記録が Firestore に存在するかどうかを確認します。
+func (r *RegisterCloseRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRegisterCloseRepository - 実際のFirestoreの複雑な実装は不要
+type MockRegisterCloseRepository struct {
+	mock.Mock
+}
+
+func NewMockRegisterCloseRepository() Repository[models.RegisterClose] {
+	return &MockRegisterCloseRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockRegisterCloseRepository) Create(ctx context.Context, registerClose *models.RegisterClose) error {
+	args := m.Called(ctx, registerClose)
+	return args.Error(0)
+}
+
+func (m *MockRegisterCloseRepository) Read(ctx context.Context) ([]*models.RegisterClose, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.RegisterClose{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.RegisterClose), nil
+}
+
+func (m *MockRegisterCloseRepository) FindByID(ctx context.Context, id string) (*models.RegisterClose, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RegisterClose), nil
+}
+
+func (m *MockRegisterCloseRepository) FindByField(ctx context.Context, field string, value any) ([]*models.RegisterClose, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.RegisterClose{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.RegisterClose), nil
+}
+
+func (m *MockRegisterCloseRepository) UpdateByID(ctx context.Context, id string, registerClose *models.RegisterClose) error {
+	args := m.Called(ctx, id, registerClose)
+	return args.Error(0)
+}
+
+func (m *MockRegisterCloseRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRegisterCloseRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockRegisterCloseRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
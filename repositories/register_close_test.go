@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewRegisterCloseRepository tests the NewRegisterCloseRepository function
+func TestNewRegisterCloseRepository(t *testing.T) {
+	t.Run("NewRegisterCloseRepository with nil client returns MockRegisterCloseRepository", func(t *testing.T) {
+		repo := NewRegisterCloseRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockRegisterCloseRepository)
+		assert.True(t, ok, "Should return a MockRegisterCloseRepository when client is nil")
+	})
+}
+
+// TestMockRegisterCloseRepository tests the MockRegisterCloseRepository implementation
+func TestMockRegisterCloseRepository(t *testing.T) {
+	ctx := context.Background()
+	testClose := &models.RegisterClose{ID: "store_123_2026-10-17", StoreID: "store_123", BusinessDay: "2026-10-17"}
+
+	t.Run("Exists", func(t *testing.T) {
+		mockRepo := &MockRegisterCloseRepository{}
+		mockRepo.On("Exists", mock.Anything, "store_123_2026-10-17").Return(true, nil)
+
+		exists, err := mockRepo.Exists(ctx, "store_123_2026-10-17")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("FindByID", func(t *testing.T) {
+		mockRepo := &MockRegisterCloseRepository{}
+		mockRepo.On("FindByID", mock.Anything, "store_123_2026-10-17").Return(testClose, nil)
+
+		closed, err := mockRepo.FindByID(ctx, "store_123_2026-10-17")
+		assert.NoError(t, err)
+		assert.Equal(t, testClose, closed)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestRegisterCloseStruct tests the RegisterClose struct conversions
+func TestRegisterCloseStruct(t *testing.T) {
+	now := time.Now().UTC()
+	standard := models.TaxRate{Code: models.TaxRateStandard, Percent: 10}
+	testClose := &models.RegisterClose{
+		ID:           "store_123_2026-10-17",
+		StoreID:      "store_123",
+		BusinessDay:  "2026-10-17",
+		OpeningFloat: models.Yen(10000),
+		CashSales:    models.Yen(2200),
+		CashRefunds:  models.Yen(0),
+		ExpectedCash: models.Yen(12200),
+		CountedCash:  models.Yen(12100),
+		OverShort:    models.Yen(-100),
+		Report: models.ZReport{
+			StoreID:     "store_123",
+			StoreName:   "居酒屋テスト",
+			BusinessDay: "2026-10-17",
+			PeriodStart: now.Add(-24 * time.Hour),
+			PeriodEnd:   now,
+			OrderCount:  2,
+			GuestCount:  3,
+			GrossSales:  models.Yen(3300),
+			Taxes: []models.TaxLine{
+				{Rate: standard, Net: models.Yen(3000), Tax: models.Yen(300), Gross: models.Yen(3300)},
+			},
+			DiscountCount: 1,
+			Discounts:     models.Yen(200),
+			Refunds:       models.Yen(0),
+			CashRefunds:   models.Yen(0),
+			NetSales:      models.Yen(3300),
+			VoidCount:     1,
+			Voids:         models.Yen(550),
+			Payments: []models.PaymentMethodTotal{
+				{Method: models.TenderCash, Count: 1, Amount: models.Yen(2200)},
+				{Method: models.TenderCard, Count: 1, Amount: models.Yen(1100)},
+			},
+			OpenOrderCount: 1,
+		},
+		Actor:    "manager:a@example.com",
+		ClosedAt: now,
+	}
+
+	repoClose := ToSetRegisterClose(testClose)
+	assert.Equal(t, "JPY", repoClose.Currency)
+	assert.Equal(t, int64(-100), repoClose.OverShort)
+	assert.Equal(t, "cash", repoClose.Report.Payments[0].Method)
+	assert.Equal(t, testClose, repoClose.ToModel())
+}
@@ -0,0 +1,202 @@
+package repositories
+
+// register_close_update.go はレジ締めと営業日の売上の記録を不可分に行う更新を実装します。
+// レジ締めの集計後に記録された精算や返金が Zレポートから漏れることのないよう、レジ締めは店舗の注文・精算・割り勘・決済を
+// Firestore のトランザクションで読み取ってから作成します。精算・返金・割り勘の支払いは、記録と同じトランザクションで
+// レジ締めのドキュメントを読み取り、レジ締め済みの営業日には記録しません。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// RegisterCloser は営業日の売上を読み取ってからレジ締めを作成するまでを不可分に行うストアです。
+type RegisterCloser interface {
+	// Close は店舗の注文・精算・割り勘・決済を読み取り、close が返したレジ締めを作成して返します。
+	// close がエラーを返した場合は作成せずにそのエラーを返します。同じIDのレジ締めがすでにある場合は codes.AlreadyExists のエラーを返します。
+	// 他の記録と競合した場合、close は最新の売上で再度呼び出されることがあります。
+	Close(ctx context.Context, storeID string, close func(models.ZReportInput) (*models.RegisterClose, error)) (*models.RegisterClose, error)
+}
+
+// NewRegisterCloser は RegisterCloser を生成します。
+// client が nil の場合は各リポジトリをプロセス内の排他制御で読み書きするストアを返します。
+func NewRegisterCloser(
+	client *firestore.Client,
+	closes Repository[models.RegisterClose],
+	sessions Repository[models.Session],
+	settlements Repository[models.Settlement],
+	splits Repository[models.BillSplit],
+	payments Repository[models.Payment],
+) RegisterCloser {
+	if client == nil {
+		return NewMemoryRegisterCloser(closes, sessions, settlements, splits, payments)
+	}
+	return &FirestoreRegisterCloser{
+		client:      client,
+		collection:  "register_closes",
+		sessions:    "sessions",
+		settlements: "settlements",
+		splits:      "bill_splits",
+		payments:    "payments",
+	}
+}
+
+// FirestoreRegisterCloser は Firestore の "register_closes" コレクションにトランザクションでレジ締めを作成する RegisterCloser です。
+// 売上は "sessions"・"settlements"・"bill_splits"・"payments" コレクションから読み取ります。
+type FirestoreRegisterCloser struct {
+	client      *firestore.Client
+	collection  string
+	sessions    string
+	settlements string
+	splits      string
+	payments    string
+}
+
+// Close はトランザクション内で店舗の売上を読み取り、レジ締めを作成します。
+func (r *FirestoreRegisterCloser) Close(ctx context.Context, storeID string, close func(models.ZReportInput) (*models.RegisterClose, error)) (*models.RegisterClose, error) {
+	query := func(collection string) firestore.Query {
+		return r.client.Collection(GetCollectionName(collection)).Where("store_id", "==", storeID)
+	}
+
+	var closed *models.RegisterClose
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var in models.ZReportInput
+		var err error
+		if in.Sessions, err = getAllInTx[Session, models.Session](tx, query(r.sessions)); err != nil {
+			return err
+		}
+		if in.Settlements, err = getAllInTx[Settlement, models.Settlement](tx, query(r.settlements)); err != nil {
+			return err
+		}
+		if in.BillSplits, err = getAllInTx[BillSplit, models.BillSplit](tx, query(r.splits)); err != nil {
+			return err
+		}
+		if in.Payments, err = getAllInTx[Payment, models.Payment](tx, query(r.payments)); err != nil {
+			return err
+		}
+
+		if closed, err = close(in); err != nil {
+			return err
+		}
+		return tx.Create(r.client.Collection(GetCollectionName(r.collection)).Doc(closed.ID), ToSetRegisterClose(closed))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+// MemoryRegisterCloser はプロセス内の排他制御でレジ締めを作成する RegisterCloser です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryRegisterCloser struct {
+	mu          sync.Mutex
+	closes      Repository[models.RegisterClose]
+	sessions    Repository[models.Session]
+	settlements Repository[models.Settlement]
+	splits      Repository[models.BillSplit]
+	payments    Repository[models.Payment]
+}
+
+func NewMemoryRegisterCloser(
+	closes Repository[models.RegisterClose],
+	sessions Repository[models.Session],
+	settlements Repository[models.Settlement],
+	splits Repository[models.BillSplit],
+	payments Repository[models.Payment],
+) *MemoryRegisterCloser {
+	return &MemoryRegisterCloser{
+		closes:      closes,
+		sessions:    sessions,
+		settlements: settlements,
+		splits:      splits,
+		payments:    payments,
+	}
+}
+
+// Close は店舗の売上を読み取り、レジ締めを作成します。
+func (s *MemoryRegisterCloser) Close(ctx context.Context, storeID string, close func(models.ZReportInput) (*models.RegisterClose, error)) (*models.RegisterClose, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var in models.ZReportInput
+	var err error
+	if in.Sessions, err = s.sessions.FindByField(ctx, "store_id", storeID); err != nil {
+		return nil, err
+	}
+	if in.Settlements, err = s.settlements.FindByField(ctx, "store_id", storeID); err != nil {
+		return nil, err
+	}
+	if in.BillSplits, err = s.splits.FindByField(ctx, "store_id", storeID); err != nil {
+		return nil, err
+	}
+	if in.Payments, err = s.payments.FindByField(ctx, "store_id", storeID); err != nil {
+		return nil, err
+	}
+
+	closed, err := close(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.closes.Create(ctx, closed); err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+
+// ensureOpenInTx はトランザクション内で closeID のレジ締めを読み取り、レジ締め済みの場合は models.ErrBusinessDayClosed を返します。
+// 読み取ったドキュメントはトランザクションの対象になるため、同時にレジ締めを作成した場合は一方が再試行されます。
+// closeID が空の場合は確認しません。
+func ensureOpenInTx(tx *firestore.Transaction, client *firestore.Client, closeID string) error {
+	if closeID == "" {
+		return nil
+	}
+	_, err := tx.Get(client.Collection(GetCollectionName("register_closes")).Doc(closeID))
+	switch {
+	case err == nil:
+		return models.ErrBusinessDayClosed
+	case IsNotFound(err):
+		return nil
+	default:
+		return err
+	}
+}
+
+// ensureOpen は closeID のレジ締めがない場合は nil を、レジ締め済みの場合は models.ErrBusinessDayClosed を返します。
+// プロセス内の排他制御で更新するストアで使用します。closeID が空の場合は確認しません。
+func ensureOpen(ctx context.Context, closes Repository[models.RegisterClose], closeID string) error {
+	if closeID == "" {
+		return nil
+	}
+	closed, err := closes.Exists(ctx, closeID)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return models.ErrBusinessDayClosed
+	}
+	return nil
+}
+
+// getAllInTx はトランザクション内でクエリに一致するドキュメントを読み取り、モデルに変換して返します。
+func getAllInTx[T any, M any, PT interface {
+	*T
+	ToModel() *M
+}](tx *firestore.Transaction, query firestore.Query) ([]*M, error) {
+	docs, err := tx.Documents(query).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*M, len(docs))
+	for i, doc := range docs {
+		var stored T
+		if err := doc.DataTo(&stored); err != nil {
+			return nil, err
+		}
+		result[i] = PT(&stored).ToModel()
+	}
+	return result, nil
+}
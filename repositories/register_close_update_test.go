@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewRegisterCloser tests the NewRegisterCloser function
+func TestNewRegisterCloser(t *testing.T) {
+	t.Run("nil client returns memory closer", func(t *testing.T) {
+		_, ok := NewRegisterCloser(nil, NewMockRegisterCloseRepository(), NewMockSessionRepository(), NewMockSettlementRepository(), NewMockBillSplitRepository(), NewMockPaymentRepository()).(*MemoryRegisterCloser)
+		assert.True(t, ok, "Should return a MemoryRegisterCloser when client is nil")
+	})
+}
+
+// TestMemoryRegisterCloser_Close tests that the close is created from the sales read in the same lock
+func TestMemoryRegisterCloser_Close(t *testing.T) {
+	ctx := context.Background()
+	orders := []*models.Session{{ID: "session_1", StoreID: "store_1"}}
+	settlements := []*models.Settlement{{ID: "settle_1", StoreID: "store_1"}}
+
+	setup := func() (*MockRegisterCloseRepository, *MemoryRegisterCloser) {
+		closes := NewMockRegisterCloseRepository().(*MockRegisterCloseRepository)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		sessions.On("FindByField", ctx, "store_id", "store_1").Return(orders, nil)
+		settlementRepo := NewMockSettlementRepository().(*MockSettlementRepository)
+		settlementRepo.On("FindByField", ctx, "store_id", "store_1").Return(settlements, nil)
+		splits := NewMockBillSplitRepository().(*MockBillSplitRepository)
+		splits.On("FindByField", ctx, "store_id", "store_1").Return([]*models.BillSplit{}, nil)
+		payments := NewMockPaymentRepository().(*MockPaymentRepository)
+		payments.On("FindByField", ctx, "store_id", "store_1").Return([]*models.Payment{}, nil)
+		return closes, NewMemoryRegisterCloser(closes, sessions, settlementRepo, splits, payments)
+	}
+
+	t.Run("create the close from the sales", func(t *testing.T) {
+		closes, closer := setup()
+		closed := &models.RegisterClose{ID: "store_1_2026-10-17", StoreID: "store_1"}
+		closes.On("Create", ctx, closed).Return(nil)
+
+		got, err := closer.Close(ctx, "store_1", func(in models.ZReportInput) (*models.RegisterClose, error) {
+			assert.Equal(t, orders, in.Sessions)
+			assert.Equal(t, settlements, in.Settlements)
+			return closed, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, closed, got)
+		closes.AssertExpectations(t)
+	})
+
+	t.Run("close error creates nothing", func(t *testing.T) {
+		closes, closer := setup()
+		failed := errors.New("invalid report")
+
+		_, err := closer.Close(ctx, "store_1", func(models.ZReportInput) (*models.RegisterClose, error) {
+			return nil, failed
+		})
+		assert.ErrorIs(t, err, failed)
+		closes.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -27,18 +27,24 @@ func NewSeatRepository(client *firestore.Client) Repository[models.Seat] {
 }
 
 type Seat struct {
-	ID        string    `firestore:"id"`
-	Name      string    `firestore:"name"`
-	CreatedAt time.Time `firestore:"created_at"`
-	UpdatedAt time.Time `firestore:"updated_at"`
+	ID             string    `firestore:"id"`
+	StoreID        string    `firestore:"store_id"`
+	Name           string    `firestore:"name"`
+	QRVersion      int       `firestore:"qr_version"`
+	CurrentVisitID string    `firestore:"current_visit_id"`
+	CreatedAt      time.Time `firestore:"created_at"`
+	UpdatedAt      time.Time `firestore:"updated_at"`
 }
 
 func ToSetSeat(seat *models.Seat) *Seat {
 	return &Seat{
-		ID:        seat.ID,
-		Name:      seat.Name,
-		CreatedAt: seat.CreatedAt,
-		UpdatedAt: seat.UpdatedAt,
+		ID:             seat.ID,
+		StoreID:        seat.StoreID,
+		Name:           seat.Name,
+		QRVersion:      seat.QRVersion,
+		CurrentVisitID: seat.CurrentVisitID,
+		CreatedAt:      seat.CreatedAt,
+		UpdatedAt:      seat.UpdatedAt,
 	}
 }
 
@@ -49,10 +55,13 @@ func (s *Seat) ToUpdate() *Seat {
 
 func (s *Seat) ToModel() *models.Seat {
 	return &models.Seat{
-		ID:        s.ID,
-		Name:      s.Name,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
+		ID:             s.ID,
+		StoreID:        s.StoreID,
+		Name:           s.Name,
+		QRVersion:      s.QRVersion,
+		CurrentVisitID: s.CurrentVisitID,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
 	}
 }
 
@@ -316,17 +316,23 @@ func TestSeatRepositoryMethods(t *testing.T) {
 func TestSeatStruct(t *testing.T) {
 	now := time.Now()
 	testSeat := &models.Seat{
-		ID:        "seat_123",
-		Name:      "Test Seat",
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             "seat_123",
+		StoreID:        "store_123",
+		Name:           "Test Seat",
+		QRVersion:      2,
+		CurrentVisitID: "visit_123",
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	t.Run("ToSetSeat conversion", func(t *testing.T) {
 		repoSeat := ToSetSeat(testSeat)
 		assert.NotNil(t, repoSeat)
 		assert.Equal(t, testSeat.ID, repoSeat.ID)
+		assert.Equal(t, testSeat.StoreID, repoSeat.StoreID)
 		assert.Equal(t, testSeat.Name, repoSeat.Name)
+		assert.Equal(t, testSeat.QRVersion, repoSeat.QRVersion)
+		assert.Equal(t, testSeat.CurrentVisitID, repoSeat.CurrentVisitID)
 		assert.Equal(t, testSeat.CreatedAt, repoSeat.CreatedAt)
 		assert.Equal(t, testSeat.UpdatedAt, repoSeat.UpdatedAt)
 	})
@@ -345,15 +351,21 @@ func TestSeatStruct(t *testing.T) {
 
 	t.Run("ToModel conversion", func(t *testing.T) {
 		repoSeat := &Seat{
-			ID:        "seat_456",
-			Name:      "Repository Seat",
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:             "seat_456",
+			StoreID:        "store_456",
+			Name:           "Repository Seat",
+			QRVersion:      1,
+			CurrentVisitID: "visit_456",
+			CreatedAt:      now,
+			UpdatedAt:      now,
 		}
 
 		modelSeat := repoSeat.ToModel()
 		assert.NotNil(t, modelSeat)
 		assert.Equal(t, repoSeat.ID, modelSeat.ID)
+		assert.Equal(t, repoSeat.StoreID, modelSeat.StoreID)
+		assert.Equal(t, repoSeat.QRVersion, modelSeat.QRVersion)
+		assert.Equal(t, repoSeat.CurrentVisitID, modelSeat.CurrentVisitID)
 		assert.Equal(t, repoSeat.Name, modelSeat.Name)
 		assert.Equal(t, repoSeat.CreatedAt, modelSeat.CreatedAt)
 		assert.Equal(t, repoSeat.UpdatedAt, modelSeat.UpdatedAt)
@@ -0,0 +1,181 @@
+package repositories
+
+// seat_update.go は座席の読み取りから更新までを不可分に行う更新を実装します。
+// 空席への来店の開始と退席（QRコードの再発行）などを同時に行っても互いの変更を上書きしないよう、
+// Firestore のトランザクションで読み取りと書き込みを行います。
+// セッショントークンの発行は来店の開始と同じトランザクションで行い、退席で失効させるトークンを取りこぼしません。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SeatUpdater は座席を読み取ってから更新するまでを不可分に行うストアです。
+type SeatUpdater interface {
+	// Update は id の座席を読み取り、update で変更した座席を保存して返します。
+	// update がエラーを返した場合は保存せずにそのエラーを返します。座席がない場合は codes.NotFound のエラーを返します。
+	// 他の更新と競合した場合、update は最新の座席で再度呼び出されることがあります。
+	Update(ctx context.Context, id string, update func(*models.Seat) error) (*models.Seat, error)
+
+	// IssueToken は id の座席を読み取り、issue で発行したセッショントークンを保存します。
+	// issue が来店を返した場合（空席に新しい来店を開始した場合）は、変更した座席と来店もあわせて保存します。
+	// 他の更新と競合した場合、issue は最新の座席で再度呼び出されることがあるため、呼び出しごとにトークンと来店を作成し直してください。
+	IssueToken(ctx context.Context, id string, issue func(*models.Seat) (*models.SessionToken, *models.Visit, error)) (*models.Seat, *models.SessionToken, error)
+}
+
+// NewSeatUpdater は SeatUpdater を生成します。
+// client が nil の場合は seats・visits・tokens をプロセス内の排他制御で更新するストアを返します。
+func NewSeatUpdater(client *firestore.Client, seats Repository[models.Seat], visits Repository[models.Visit], tokens Repository[models.SessionToken]) SeatUpdater {
+	if client == nil {
+		return NewMemorySeatUpdater(seats, visits, tokens)
+	}
+	return &FirestoreSeatUpdater{
+		client:     client,
+		collection: "seats",
+		visits:     "visits",
+		tokens:     "session_tokens",
+	}
+}
+
+// FirestoreSeatUpdater は Firestore の "seats" コレクションをトランザクションで更新する SeatUpdater です。
+// 来店は "visits"、セッショントークンは "session_tokens" コレクションに作成します。
+type FirestoreSeatUpdater struct {
+	client     *firestore.Client
+	collection string
+	visits     string
+	tokens     string
+}
+
+// readSeat はトランザクション内で座席を読み取ります。
+func (r *FirestoreSeatUpdater) readSeat(tx *firestore.Transaction, ref *firestore.DocumentRef) (*models.Seat, error) {
+	doc, err := tx.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	stored := &Seat{}
+	if err := doc.DataTo(stored); err != nil {
+		return nil, err
+	}
+	return stored.ToModel(), nil
+}
+
+// Update はトランザクション内で座席を読み取り、更新します。
+func (r *FirestoreSeatUpdater) Update(ctx context.Context, id string, update func(*models.Seat) error) (*models.Seat, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(id)
+
+	var seat *models.Seat
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var err error
+		if seat, err = r.readSeat(tx, ref); err != nil {
+			return err
+		}
+		if err := update(seat); err != nil {
+			return err
+		}
+		return tx.Set(ref, ToSetSeat(seat))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return seat, nil
+}
+
+// IssueToken はトランザクション内で座席を読み取り、セッショントークンの発行と来店の開始を行います。
+// 来店とトークンは Create で作成するため、同じIDの記録が重ねて作成されることはありません。
+func (r *FirestoreSeatUpdater) IssueToken(ctx context.Context, id string, issue func(*models.Seat) (*models.SessionToken, *models.Visit, error)) (*models.Seat, *models.SessionToken, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(id)
+
+	var seat *models.Seat
+	var token *models.SessionToken
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var err error
+		if seat, err = r.readSeat(tx, ref); err != nil {
+			return err
+		}
+
+		var visit *models.Visit
+		if token, visit, err = issue(seat); err != nil {
+			return err
+		}
+		if visit != nil {
+			if err := tx.Set(ref, ToSetSeat(seat)); err != nil {
+				return err
+			}
+			if err := tx.Create(r.client.Collection(GetCollectionName(r.visits)).Doc(visit.ID), ToSetVisit(visit)); err != nil {
+				return err
+			}
+		}
+		return tx.Create(r.client.Collection(GetCollectionName(r.tokens)).Doc(token.ID), ToSetSessionToken(token))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return seat, token, nil
+}
+
+// MemorySeatUpdater はプロセス内の排他制御で座席を更新する SeatUpdater です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemorySeatUpdater struct {
+	mu     sync.Mutex
+	seats  Repository[models.Seat]
+	visits Repository[models.Visit]
+	tokens Repository[models.SessionToken]
+}
+
+func NewMemorySeatUpdater(seats Repository[models.Seat], visits Repository[models.Visit], tokens Repository[models.SessionToken]) *MemorySeatUpdater {
+	return &MemorySeatUpdater{
+		seats:  seats,
+		visits: visits,
+		tokens: tokens,
+	}
+}
+
+// Update は座席を読み取り、更新します。
+func (s *MemorySeatUpdater) Update(ctx context.Context, id string, update func(*models.Seat) error) (*models.Seat, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seat, err := s.seats.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(seat); err != nil {
+		return nil, err
+	}
+	if err := s.seats.UpdateByID(ctx, id, seat); err != nil {
+		return nil, err
+	}
+	return seat, nil
+}
+
+// IssueToken は座席を読み取り、セッショントークンの発行と来店の開始を行います。
+func (s *MemorySeatUpdater) IssueToken(ctx context.Context, id string, issue func(*models.Seat) (*models.SessionToken, *models.Visit, error)) (*models.Seat, *models.SessionToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seat, err := s.seats.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	token, visit, err := issue(seat)
+	if err != nil {
+		return nil, nil, err
+	}
+	if visit != nil {
+		if err := s.seats.UpdateByID(ctx, id, seat); err != nil {
+			return nil, nil, err
+		}
+		if err := s.visits.Create(ctx, visit); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.tokens.Create(ctx, token); err != nil {
+		return nil, nil, err
+	}
+	return seat, token, nil
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewSeatUpdater tests the NewSeatUpdater function
+func TestNewSeatUpdater(t *testing.T) {
+	t.Run("nil client returns memory updater", func(t *testing.T) {
+		_, ok := NewSeatUpdater(nil, NewMockSeatRepository(), NewMockVisitRepository(), NewMockSessionTokenRepository()).(*MemorySeatUpdater)
+		assert.True(t, ok, "Should return a MemorySeatUpdater when client is nil")
+	})
+}
+
+// TestMemorySeatUpdater_IssueToken tests that a token and a new visit are saved together with the seat
+func TestMemorySeatUpdater_IssueToken(t *testing.T) {
+	ctx := context.Background()
+	exp := time.Now().Add(time.Hour)
+
+	issue := func(seat *models.Seat) (*models.SessionToken, *models.Visit, error) {
+		var visit *models.Visit
+		if _, started := seat.StartVisit(); started {
+			visit = models.NewVisit(seat, time.Now())
+		}
+		return models.NewSessionToken(seat, "", "", exp), visit, nil
+	}
+
+	t.Run("empty seat starts a visit", func(t *testing.T) {
+		seat := &models.Seat{ID: "seat_1", StoreID: "store_1"}
+		seats := NewMockSeatRepository().(*MockSeatRepository)
+		seats.On("FindByID", ctx, "seat_1").Return(seat, nil)
+		seats.On("UpdateByID", ctx, "seat_1", seat).Return(nil)
+		visits := NewMockVisitRepository().(*MockVisitRepository)
+		visits.On("Create", ctx, mock.AnythingOfType("*models.Visit")).Return(nil)
+		tokens := NewMockSessionTokenRepository().(*MockSessionTokenRepository)
+		tokens.On("Create", ctx, mock.AnythingOfType("*models.SessionToken")).Return(nil)
+
+		_, token, err := NewMemorySeatUpdater(seats, visits, tokens).IssueToken(ctx, "seat_1", issue)
+		require.NoError(t, err)
+		assert.Equal(t, seat.CurrentVisitID, token.VisitID)
+		assert.Equal(t, seat.CurrentVisitID, visits.Calls[0].Arguments.Get(1).(*models.Visit).ID)
+	})
+
+	t.Run("seated seat keeps the current visit", func(t *testing.T) {
+		seat := &models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}
+		seats := NewMockSeatRepository().(*MockSeatRepository)
+		seats.On("FindByID", ctx, "seat_1").Return(seat, nil)
+		visits := NewMockVisitRepository().(*MockVisitRepository)
+		tokens := NewMockSessionTokenRepository().(*MockSessionTokenRepository)
+		tokens.On("Create", ctx, mock.AnythingOfType("*models.SessionToken")).Return(nil)
+
+		_, token, err := NewMemorySeatUpdater(seats, visits, tokens).IssueToken(ctx, "seat_1", issue)
+		require.NoError(t, err)
+		assert.Equal(t, "visit_1", token.VisitID)
+		seats.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		visits.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("issue error saves nothing", func(t *testing.T) {
+		seats := NewMockSeatRepository().(*MockSeatRepository)
+		seats.On("FindByID", ctx, "seat_1").Return(&models.Seat{ID: "seat_1", StoreID: "store_1"}, nil)
+		tokens := NewMockSessionTokenRepository().(*MockSessionTokenRepository)
+
+		_, _, err := NewMemorySeatUpdater(seats, NewMockVisitRepository(), tokens).IssueToken(ctx, "seat_1", func(*models.Seat) (*models.SessionToken, *models.Visit, error) {
+			return nil, nil, errors.New("mismatch")
+		})
+		assert.Error(t, err)
+		seats.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		tokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,93 @@
+package repositories
+
+// sequence.go は領収書番号などの連番の採番を実装します。
+// 複数インスタンスから同時に採番しても番号が重複・欠番しないよう、Firestore のトランザクションで更新します。
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SequenceStore は key ごとに1から始まる連番を採番するストアです。
+type SequenceStore interface {
+	// Next は key の連番を1つ進め、採番した番号を返します。
+	Next(ctx context.Context, key string) (int64, error)
+}
+
+// NewSequenceStore は SequenceStore を生成します。
+// client が nil の場合はプロセス内のメモリで保持するストアを返します。
+func NewSequenceStore(client *firestore.Client) SequenceStore {
+	if client == nil {
+		return NewMemorySequenceStore()
+	}
+	return &FirestoreSequenceStore{
+		client:     client,
+		collection: "sequences",
+	}
+}
+
+// FirestoreSequenceStore は Firestore の "sequences" コレクションを使用する SequenceStore です。
+type FirestoreSequenceStore struct {
+	client     *firestore.Client
+	collection string
+}
+
+type Sequence struct {
+	Key       string    `firestore:"key"`
+	Value     int64     `firestore:"value"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+// Next はトランザクション内で連番を更新します。
+func (r *FirestoreSequenceStore) Next(ctx context.Context, key string) (int64, error) {
+	// key はユースケース側で組み立てる値（"receipt:store_xxx" など）のため、そのままドキュメントIDに使用する
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(key)
+
+	var seq Sequence
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		seq = Sequence{Key: key}
+		doc, err := tx.Get(ref)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&seq); err != nil {
+				return err
+			}
+		}
+
+		seq.Value++
+		seq.UpdatedAt = time.Now().UTC()
+		return tx.Set(ref, &seq)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return seq.Value, nil
+}
+
+// MemorySequenceStore はプロセス内のメモリで連番を保持する SequenceStore です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemorySequenceStore struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func NewMemorySequenceStore() *MemorySequenceStore {
+	return &MemorySequenceStore{
+		values: make(map[string]int64),
+	}
+}
+
+// Next は key の連番を1つ進めます。
+func (s *MemorySequenceStore) Next(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key]++
+	return s.values[key], nil
+}
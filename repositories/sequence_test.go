@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewSequenceStore tests the NewSequenceStore function
+func TestNewSequenceStore(t *testing.T) {
+	t.Run("nil client returns memory store", func(t *testing.T) {
+		_, ok := NewSequenceStore(nil).(*MemorySequenceStore)
+		assert.True(t, ok, "Should return a MemorySequenceStore when client is nil")
+	})
+}
+
+// TestMemorySequenceStore tests the in-memory sequence
+func TestMemorySequenceStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("numbers start at 1 per key", func(t *testing.T) {
+		store := NewMemorySequenceStore()
+
+		for want := int64(1); want <= 3; want++ {
+			got, err := store.Next(ctx, "receipt:store_1")
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+
+		got, err := store.Next(ctx, "receipt:store_2")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), got, "キーごとに独立して採番する")
+	})
+
+	t.Run("concurrent numbers are unique", func(t *testing.T) {
+		store := NewMemorySequenceStore()
+
+		const n = 100
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		seen := make(map[int64]bool, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := store.Next(ctx, "receipt:store_1")
+				assert.NoError(t, err)
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, seen, n)
+		for v := int64(1); v <= n; v++ {
+			assert.True(t, seen[v], "欠番がないこと: %d", v)
+		}
+	})
+}
@@ -30,9 +30,34 @@ type Session struct {
 	StoreID     string  `firestore:"store_id"`
 	SeatID      string  `firestore:"seat_id"`
 	Items       []Order `firestore:"items"`
-	TotalAmount float64 `firestore:"total_amount"`
+	TotalAmount int64   `firestore:"total_amount"`
+	Currency    string  `firestore:"currency"`
 	Status      Status  `firestore:"status"`
 
+	WorkflowName string `firestore:"workflow"`
+
+	DiningOption string    `firestore:"dining_option"`
+	TaxPriceMode string    `firestore:"tax_price_mode"`
+	TaxRounding  string    `firestore:"tax_rounding"`
+	Taxes        []TaxLine `firestore:"taxes"`
+	TaxTotal     int64     `firestore:"tax_total"`
+
+	Discounts []Discount `firestore:"discounts"`
+
+	VisitID   string   `firestore:"visit_id"`
+	PartySize int      `firestore:"party_size"`
+	Charges   []Charge `firestore:"charges"`
+
+	Refunds []Refund `firestore:"refunds"`
+
+	StatusHistory []StatusChange `firestore:"status_history"`
+
+	PaymentStatus  string                `firestore:"payment_status"`
+	PaymentHistory []PaymentStatusChange `firestore:"payment_history"`
+
+	NeedsReview  bool   `firestore:"needs_review"`
+	ReviewReason string `firestore:"review_reason"`
+
 	ExpiresAt time.Time `firestore:"expires_at"`
 	IssuedAt  time.Time `firestore:"issued_at"`
 
@@ -41,28 +66,413 @@ type Session struct {
 }
 
 type Order struct {
-	OrderID   string    `firestore:"order_id"`
-	ProductID string    `firestore:"product_id"`
-	Quantity  int       `firestore:"quantity"`
-	Price     float64   `firestore:"price"`
+	OrderID      string `firestore:"order_id"`
+	LineID       string `firestore:"line_id"`
+	ProductID    string `firestore:"product_id"`
+	Category     string `firestore:"category"`
+	Quantity     int    `firestore:"quantity"`
+	Price        int64  `firestore:"price"`
+	Currency     string `firestore:"currency"`
+	TaxCategory  string `firestore:"tax_category"`
+	Instructions string `firestore:"instructions"`
+
+	Status         string               `firestore:"status"`
+	Adjustments    []QuantityAdjustment `firestore:"adjustments"`
+	VoidReason     string               `firestore:"void_reason"`
+	VoidReasonCode string               `firestore:"void_reason_code"`
+	VoidedBy       string               `firestore:"voided_by"`
+	VoidedAt       time.Time            `firestore:"voided_at"`
+
 	CreatedAt time.Time `firestore:"created_at"`
 	UpdatedAt time.Time `firestore:"updated_at"`
 }
 
+// QuantityAdjustment は明細の数量の変更の記録です。
+type QuantityAdjustment struct {
+	From   int       `firestore:"from"`
+	To     int       `firestore:"to"`
+	Reason string    `firestore:"reason"`
+	Actor  string    `firestore:"actor"`
+	At     time.Time `firestore:"at"`
+}
+
+// TaxLine は税率ごとの内訳です。注文時点の税率を保存し、税率改定後も同じ金額を再現できるようにします。
+type TaxLine struct {
+	RateCode    string `firestore:"rate_code"`
+	RatePercent int64  `firestore:"rate_percent"`
+	Net         int64  `firestore:"net"`
+	Tax         int64  `firestore:"tax"`
+	Gross       int64  `firestore:"gross"`
+}
+
+// Discount は注文に適用した割引です。按分結果も保存し、返金時に明細行ごとの割引後の金額を参照できるようにします。
+type Discount struct {
+	ID          string               `firestore:"id"`
+	Source      string               `firestore:"source"`
+	PromotionID string               `firestore:"promotion_id"`
+	Code        string               `firestore:"code"`
+	Rule        DiscountRule         `firestore:"rule"`
+	Reason      string               `firestore:"reason"`
+	AppliedBy   string               `firestore:"applied_by"`
+	Amount      int64                `firestore:"amount"`
+	Allocations []DiscountAllocation `firestore:"allocations"`
+	AppliedAt   time.Time            `firestore:"applied_at"`
+}
+
+type DiscountRule struct {
+	Kind       string   `firestore:"kind"`
+	Value      int64    `firestore:"value"`
+	Scope      string   `firestore:"scope"`
+	ProductIDs []string `firestore:"product_ids"`
+	LineIDs    []string `firestore:"line_ids"`
+	Categories []string `firestore:"categories"`
+}
+
+type DiscountAllocation struct {
+	LineID string `firestore:"line_id"`
+	Amount int64  `firestore:"amount"`
+}
+
+// Charge は注文に適用したチャージです。免除した場合も理由と操作者を保存します。
+type Charge struct {
+	ID          string    `firestore:"id"`
+	RuleID      string    `firestore:"rule_id"`
+	Name        string    `firestore:"name"`
+	Kind        string    `firestore:"kind"`
+	Value       int64     `firestore:"value"`
+	Quantity    int       `firestore:"quantity"`
+	TaxCategory string    `firestore:"tax_category"`
+	Amount      int64     `firestore:"amount"`
+	Waived      bool      `firestore:"waived"`
+	WaiveReason string    `firestore:"waive_reason"`
+	WaivedBy    string    `firestore:"waived_by"`
+	WaivedAt    time.Time `firestore:"waived_at"`
+	AppliedAt   time.Time `firestore:"applied_at"`
+}
+
+// Refund は注文の返金記録です。
+type Refund struct {
+	ID         string    `firestore:"id"`
+	Amount     int64     `firestore:"amount"`
+	LineIDs    []string  `firestore:"line_ids"`
+	Reason     string    `firestore:"reason"`
+	Actor      string    `firestore:"actor"`
+	PaymentRef string    `firestore:"payment_ref"`
+	Pending    bool      `firestore:"pending"`
+	CreatedAt  time.Time `firestore:"created_at"`
+}
+
+// StatusChange は注文のステータスの遷移の記録です。
+type StatusChange struct {
+	From       string    `firestore:"from"`
+	To         string    `firestore:"to"`
+	At         time.Time `firestore:"at"`
+	Actor      string    `firestore:"actor"`
+	Reason     string    `firestore:"reason"`
+	ReasonCode string    `firestore:"reason_code"`
+	Exception  bool      `firestore:"exception"`
+	ApprovedBy string    `firestore:"approved_by"`
+}
+
+// PaymentStatusChange は注文の支払い状態の遷移の記録です。
+type PaymentStatusChange struct {
+	From   string    `firestore:"from"`
+	To     string    `firestore:"to"`
+	At     time.Time `firestore:"at"`
+	Actor  string    `firestore:"actor"`
+	Reason string    `firestore:"reason"`
+}
+
 type Status string
 
+func ToSetDiscountRule(rule models.DiscountRule) DiscountRule {
+	return DiscountRule{
+		Kind:       string(rule.Kind),
+		Value:      rule.Value,
+		Scope:      string(rule.Scope),
+		ProductIDs: rule.ProductIDs,
+		LineIDs:    rule.LineIDs,
+		Categories: rule.Categories,
+	}
+}
+
+func (r DiscountRule) ToModel() models.DiscountRule {
+	return models.DiscountRule{
+		Kind:       models.DiscountKind(r.Kind),
+		Value:      r.Value,
+		Scope:      models.DiscountScope(r.Scope),
+		ProductIDs: r.ProductIDs,
+		LineIDs:    r.LineIDs,
+		Categories: r.Categories,
+	}
+}
+
+func ToSetDiscounts(discounts []models.Discount) []Discount {
+	setDiscounts := make([]Discount, len(discounts))
+	for i, d := range discounts {
+		allocations := make([]DiscountAllocation, len(d.Allocations))
+		for j, a := range d.Allocations {
+			allocations[j] = DiscountAllocation{LineID: a.LineID, Amount: a.Amount.Amount}
+		}
+		setDiscounts[i] = Discount{
+			ID:          d.ID,
+			Source:      string(d.Source),
+			PromotionID: d.PromotionID,
+			Code:        d.Code,
+			Rule:        ToSetDiscountRule(d.Rule),
+			Reason:      d.Reason,
+			AppliedBy:   d.AppliedBy,
+			Amount:      d.Amount.Amount,
+			Allocations: allocations,
+			AppliedAt:   d.AppliedAt,
+		}
+	}
+	return setDiscounts
+}
+
+func ToModelDiscounts(discounts []Discount, currency string) []models.Discount {
+	if len(discounts) == 0 {
+		return nil
+	}
+	modelDiscounts := make([]models.Discount, len(discounts))
+	for i, d := range discounts {
+		allocations := make([]models.DiscountAllocation, len(d.Allocations))
+		for j, a := range d.Allocations {
+			allocations[j] = models.DiscountAllocation{LineID: a.LineID, Amount: ToModelMoney(a.Amount, currency)}
+		}
+		modelDiscounts[i] = models.Discount{
+			ID:          d.ID,
+			Source:      models.DiscountSource(d.Source),
+			PromotionID: d.PromotionID,
+			Code:        d.Code,
+			Rule:        d.Rule.ToModel(),
+			Reason:      d.Reason,
+			AppliedBy:   d.AppliedBy,
+			Amount:      ToModelMoney(d.Amount, currency),
+			Allocations: allocations,
+			AppliedAt:   d.AppliedAt,
+		}
+	}
+	return modelDiscounts
+}
+
+func ToSetCharges(charges []models.Charge) []Charge {
+	setCharges := make([]Charge, len(charges))
+	for i, c := range charges {
+		setCharges[i] = Charge{
+			ID:          c.ID,
+			RuleID:      c.RuleID,
+			Name:        c.Name,
+			Kind:        string(c.Kind),
+			Value:       c.Value,
+			Quantity:    c.Quantity,
+			TaxCategory: string(c.TaxCategory),
+			Amount:      c.Amount.Amount,
+			Waived:      c.Waived,
+			WaiveReason: c.WaiveReason,
+			WaivedBy:    c.WaivedBy,
+			WaivedAt:    c.WaivedAt,
+			AppliedAt:   c.AppliedAt,
+		}
+	}
+	return setCharges
+}
+
+func ToModelCharges(charges []Charge, currency string) []models.Charge {
+	if len(charges) == 0 {
+		return nil
+	}
+	modelCharges := make([]models.Charge, len(charges))
+	for i, c := range charges {
+		modelCharges[i] = models.Charge{
+			ID:          c.ID,
+			RuleID:      c.RuleID,
+			Name:        c.Name,
+			Kind:        models.ChargeKind(c.Kind),
+			Value:       c.Value,
+			Quantity:    c.Quantity,
+			TaxCategory: models.TaxCategory(c.TaxCategory),
+			Amount:      ToModelMoney(c.Amount, currency),
+			Waived:      c.Waived,
+			WaiveReason: c.WaiveReason,
+			WaivedBy:    c.WaivedBy,
+			WaivedAt:    c.WaivedAt,
+			AppliedAt:   c.AppliedAt,
+		}
+	}
+	return modelCharges
+}
+
+func ToSetRefunds(refunds []models.Refund) []Refund {
+	setRefunds := make([]Refund, len(refunds))
+	for i, r := range refunds {
+		setRefunds[i] = Refund{
+			ID:         r.ID,
+			Amount:     r.Amount.Amount,
+			LineIDs:    r.LineIDs,
+			Reason:     r.Reason,
+			Actor:      r.Actor,
+			PaymentRef: r.PaymentRef,
+			Pending:    r.Pending,
+			CreatedAt:  r.CreatedAt,
+		}
+	}
+	return setRefunds
+}
+
+func ToModelRefunds(refunds []Refund, currency string) []models.Refund {
+	if len(refunds) == 0 {
+		return nil
+	}
+	modelRefunds := make([]models.Refund, len(refunds))
+	for i, r := range refunds {
+		modelRefunds[i] = models.Refund{
+			ID:         r.ID,
+			Amount:     ToModelMoney(r.Amount, currency),
+			LineIDs:    r.LineIDs,
+			Reason:     r.Reason,
+			Actor:      r.Actor,
+			PaymentRef: r.PaymentRef,
+			Pending:    r.Pending,
+			CreatedAt:  r.CreatedAt,
+		}
+	}
+	return modelRefunds
+}
+
+func ToSetStatusHistory(history []models.StatusChange) []StatusChange {
+	setHistory := make([]StatusChange, len(history))
+	for i, h := range history {
+		setHistory[i] = StatusChange{
+			From:       string(h.From),
+			To:         string(h.To),
+			At:         h.At,
+			Actor:      h.Actor,
+			Reason:     h.Reason,
+			ReasonCode: h.ReasonCode,
+			Exception:  h.Exception,
+			ApprovedBy: h.ApprovedBy,
+		}
+	}
+	return setHistory
+}
+
+func ToModelStatusHistory(history []StatusChange) []models.StatusChange {
+	if len(history) == 0 {
+		return nil
+	}
+	modelHistory := make([]models.StatusChange, len(history))
+	for i, h := range history {
+		modelHistory[i] = models.StatusChange{
+			From:       models.Status(h.From),
+			To:         models.Status(h.To),
+			At:         h.At,
+			Actor:      h.Actor,
+			Reason:     h.Reason,
+			ReasonCode: h.ReasonCode,
+			Exception:  h.Exception,
+			ApprovedBy: h.ApprovedBy,
+		}
+	}
+	return modelHistory
+}
+
+func ToSetPaymentHistory(history []models.PaymentStatusChange) []PaymentStatusChange {
+	setHistory := make([]PaymentStatusChange, len(history))
+	for i, h := range history {
+		setHistory[i] = PaymentStatusChange{
+			From:   string(h.From),
+			To:     string(h.To),
+			At:     h.At,
+			Actor:  h.Actor,
+			Reason: h.Reason,
+		}
+	}
+	return setHistory
+}
+
+func ToModelPaymentHistory(history []PaymentStatusChange) []models.PaymentStatusChange {
+	if len(history) == 0 {
+		return nil
+	}
+	modelHistory := make([]models.PaymentStatusChange, len(history))
+	for i, h := range history {
+		modelHistory[i] = models.PaymentStatusChange{
+			From:   models.PaymentStatus(h.From),
+			To:     models.PaymentStatus(h.To),
+			At:     h.At,
+			Actor:  h.Actor,
+			Reason: h.Reason,
+		}
+	}
+	return modelHistory
+}
+
+func ToSetTaxLines(lines []models.TaxLine) []TaxLine {
+	setLines := make([]TaxLine, len(lines))
+	for i, line := range lines {
+		setLines[i] = TaxLine{
+			RateCode:    string(line.Rate.Code),
+			RatePercent: line.Rate.Percent,
+			Net:         line.Net.Amount,
+			Tax:         line.Tax.Amount,
+			Gross:       line.Gross.Amount,
+		}
+	}
+	return setLines
+}
+
+func ToModelTaxLines(lines []TaxLine, currency string) []models.TaxLine {
+	modelLines := make([]models.TaxLine, len(lines))
+	for i, line := range lines {
+		modelLines[i] = models.TaxLine{
+			Rate:  models.TaxRate{Code: models.TaxRateCode(line.RateCode), Percent: line.RatePercent},
+			Net:   ToModelMoney(line.Net, currency),
+			Tax:   ToModelMoney(line.Tax, currency),
+			Gross: ToModelMoney(line.Gross, currency),
+		}
+	}
+	return modelLines
+}
+
 func ToSetSession(s *models.Session) *Session {
 	return &Session{
 		ID:          s.ID,
 		StoreID:     s.StoreID,
 		SeatID:      s.SeatID,
 		Items:       ToSetOrders(s.Items),
-		TotalAmount: s.TotalAmount,
+		TotalAmount: s.TotalAmount.Amount,
+		Currency:    string(s.Currency()),
 		Status:      Status(s.Status),
-		ExpiresAt:   s.ExpiresAt,
-		IssuedAt:    s.IssuedAt,
-		CreatedAt:   s.CreatedAt,
-		UpdatedAt:   s.UpdatedAt,
+
+		WorkflowName: s.WorkflowName,
+
+		DiningOption: string(s.DiningOption),
+		TaxPriceMode: string(s.TaxPolicy.PriceMode),
+		TaxRounding:  string(s.TaxPolicy.Rounding),
+		Taxes:        ToSetTaxLines(s.Taxes),
+		TaxTotal:     s.TaxTotal.Amount,
+
+		Discounts: ToSetDiscounts(s.Discounts),
+
+		VisitID:   s.VisitID,
+		PartySize: s.PartySize,
+		Charges:   ToSetCharges(s.Charges),
+
+		Refunds: ToSetRefunds(s.Refunds),
+
+		StatusHistory: ToSetStatusHistory(s.StatusHistory),
+
+		PaymentStatus:  string(s.PaymentStatus),
+		PaymentHistory: ToSetPaymentHistory(s.PaymentHistory),
+
+		NeedsReview:  s.NeedsReview,
+		ReviewReason: s.ReviewReason,
+
+		ExpiresAt: s.ExpiresAt,
+		IssuedAt:  s.IssuedAt,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
 	}
 }
 
@@ -71,31 +481,74 @@ func (s *Session) ToUpdate() *Session {
 	return s
 }
 
+// ToModel は保存された注文をモデルに変換します。
+// 支払い状態が記録されていない既存の注文は、ステータスから提供の進行と支払い状態に分けて移行します。
+// この移行は保存された値を変更しないため、保存し直すには SessionStatusMigrator（cmd/migrate-status）を実行します。
 func (s *Session) ToModel() *models.Session {
-	return &models.Session{
+	session := &models.Session{
 		ID:          s.ID,
 		StoreID:     s.StoreID,
 		SeatID:      s.SeatID,
 		Items:       ToModelOrders(s.Items),
-		TotalAmount: s.TotalAmount,
+		TotalAmount: ToModelMoney(s.TotalAmount, s.Currency),
 		Status:      models.Status(s.Status),
-		ExpiresAt:   s.ExpiresAt,
-		IssuedAt:    s.IssuedAt,
-		CreatedAt:   s.CreatedAt,
-		UpdatedAt:   s.UpdatedAt,
+
+		WorkflowName: s.WorkflowName,
+
+		DiningOption: models.DiningOption(s.DiningOption),
+		TaxPolicy: models.TaxPolicy{
+			PriceMode: models.PriceMode(s.TaxPriceMode),
+			Rounding:  models.RoundingMode(s.TaxRounding),
+		}.WithDefaults(),
+		Taxes:    ToModelTaxLines(s.Taxes, s.Currency),
+		TaxTotal: ToModelMoney(s.TaxTotal, s.Currency),
+
+		Discounts: ToModelDiscounts(s.Discounts, s.Currency),
+
+		VisitID:   s.VisitID,
+		PartySize: s.PartySize,
+		Charges:   ToModelCharges(s.Charges, s.Currency),
+
+		Refunds: ToModelRefunds(s.Refunds, s.Currency),
+
+		StatusHistory: ToModelStatusHistory(s.StatusHistory),
+
+		PaymentStatus:  models.PaymentStatus(s.PaymentStatus),
+		PaymentHistory: ToModelPaymentHistory(s.PaymentHistory),
+
+		NeedsReview:  s.NeedsReview,
+		ReviewReason: s.ReviewReason,
+
+		ExpiresAt: s.ExpiresAt,
+		IssuedAt:  s.IssuedAt,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
 	}
+	session.MigrateLegacyStatus()
+	return session
 }
 
 func ToSetOrders(orders []models.Order) []Order {
 	setOrders := make([]Order, len(orders))
 	for i, o := range orders {
 		setOrders[i] = Order{
-			OrderID:   o.OrderID,
-			ProductID: o.ProductID,
-			Quantity:  o.Quantity,
-			Price:     o.Price,
-			CreatedAt: o.CreatedAt,
-			UpdatedAt: o.UpdatedAt,
+			OrderID:        o.OrderID,
+			LineID:         o.LineID,
+			ProductID:      o.ProductID,
+			Category:       o.Category,
+			Quantity:       o.Quantity,
+			Price:          o.Price.Amount,
+			Currency:       string(o.Price.Currency),
+			TaxCategory:    string(o.TaxCategory),
+			Instructions:   o.Instructions,
+			Status:         string(o.Status),
+			Adjustments:    ToSetQuantityAdjustments(o.Adjustments),
+			VoidReason:     o.VoidReason,
+			VoidReasonCode: o.VoidReasonCode,
+			VoidedBy:       o.VoidedBy,
+			VoidedAt:       o.VoidedAt,
+			CreatedAt:      o.CreatedAt,
+			UpdatedAt:      o.UpdatedAt,
 		}
 	}
 	return setOrders
@@ -105,17 +558,58 @@ func ToModelOrders(orders []Order) []models.Order {
 	modelOrders := make([]models.Order, len(orders))
 	for i, o := range orders {
 		modelOrders[i] = models.Order{
-			OrderID:   o.OrderID,
-			ProductID: o.ProductID,
-			Quantity:  o.Quantity,
-			Price:     o.Price,
-			CreatedAt: o.CreatedAt,
-			UpdatedAt: o.UpdatedAt,
+			OrderID:        o.OrderID,
+			LineID:         o.LineID,
+			ProductID:      o.ProductID,
+			Category:       o.Category,
+			Quantity:       o.Quantity,
+			Price:          ToModelMoney(o.Price, o.Currency),
+			TaxCategory:    models.TaxCategory(o.TaxCategory),
+			Instructions:   o.Instructions,
+			Status:         models.LineStatus(o.Status),
+			Adjustments:    ToModelQuantityAdjustments(o.Adjustments),
+			VoidReason:     o.VoidReason,
+			VoidReasonCode: o.VoidReasonCode,
+			VoidedBy:       o.VoidedBy,
+			VoidedAt:       o.VoidedAt,
+			CreatedAt:      o.CreatedAt,
+			UpdatedAt:      o.UpdatedAt,
 		}
 	}
 	return modelOrders
 }
 
+func ToSetQuantityAdjustments(adjustments []models.QuantityAdjustment) []QuantityAdjustment {
+	setAdjustments := make([]QuantityAdjustment, len(adjustments))
+	for i, a := range adjustments {
+		setAdjustments[i] = QuantityAdjustment{
+			From:   a.From,
+			To:     a.To,
+			Reason: a.Reason,
+			Actor:  a.Actor,
+			At:     a.At,
+		}
+	}
+	return setAdjustments
+}
+
+func ToModelQuantityAdjustments(adjustments []QuantityAdjustment) []models.QuantityAdjustment {
+	if len(adjustments) == 0 {
+		return nil
+	}
+	modelAdjustments := make([]models.QuantityAdjustment, len(adjustments))
+	for i, a := range adjustments {
+		modelAdjustments[i] = models.QuantityAdjustment{
+			From:   a.From,
+			To:     a.To,
+			Reason: a.Reason,
+			Actor:  a.Actor,
+			At:     a.At,
+		}
+	}
+	return modelAdjustments
+}
+
 // Create は新しいセッションをFirestoreに作成します。
 func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
 	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(session.ID).Set(ctx, ToSetSession(session))
@@ -185,7 +679,7 @@ func (r *SessionRepository) FindByField(ctx context.Context, field string, value
 
 // UpdateByID はIDを使用してセッションを更新します。
 func (r *SessionRepository) UpdateByID(ctx context.Context, id string, session *models.Session) error {
-	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, session)
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetSession(session))
 	return err
 }
 
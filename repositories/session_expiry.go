@@ -0,0 +1,86 @@
+package repositories
+
+// session_expiry.go は有効期限を過ぎた注文の検索を実装します。
+// 自動のステータス遷移で確認待ちの注文を全て読み取らないよう、有効期限で絞り込んで検索します。
+
+import (
+	"backend/models"
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ExpiredSessionFinder は有効期限を過ぎた注文を検索するストアです。
+type ExpiredSessionFinder interface {
+	// FindExpired は status の注文のうち、有効期限（ExpiresAt）が before より前の注文を返します。
+	FindExpired(ctx context.Context, status models.Status, before time.Time) ([]*models.Session, error)
+}
+
+// NewExpiredSessionFinder は ExpiredSessionFinder を生成します。
+// client が nil の場合は sessions の注文を絞り込むストアを返します。
+func NewExpiredSessionFinder(client *firestore.Client, sessions Repository[models.Session]) ExpiredSessionFinder {
+	if client == nil {
+		return NewMemoryExpiredSessionFinder(sessions)
+	}
+	return &FirestoreExpiredSessionFinder{
+		client:     client,
+		collection: "sessions",
+	}
+}
+
+// FirestoreExpiredSessionFinder は Firestore の "sessions" コレクションを検索する ExpiredSessionFinder です。
+// status と expires_at の複合インデックスが必要です。
+type FirestoreExpiredSessionFinder struct {
+	client     *firestore.Client
+	collection string
+}
+
+// FindExpired は status と expires_at の条件で注文を検索します。
+func (r *FirestoreExpiredSessionFinder) FindExpired(ctx context.Context, status models.Status, before time.Time) ([]*models.Session, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).
+		Where("status", "==", string(status)).
+		Where("expires_at", "<", before).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*models.Session, len(docs))
+	for i, doc := range docs {
+		session := &Session{}
+		if err := doc.DataTo(session); err != nil {
+			return nil, err
+		}
+		sessions[i] = session.ToModel()
+	}
+	return sessions, nil
+}
+
+// MemoryExpiredSessionFinder は Repository の注文を絞り込む ExpiredSessionFinder です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryExpiredSessionFinder struct {
+	sessions Repository[models.Session]
+}
+
+func NewMemoryExpiredSessionFinder(sessions Repository[models.Session]) *MemoryExpiredSessionFinder {
+	return &MemoryExpiredSessionFinder{
+		sessions: sessions,
+	}
+}
+
+// FindExpired は status の注文を読み取り、有効期限で絞り込みます。
+func (s *MemoryExpiredSessionFinder) FindExpired(ctx context.Context, status models.Status, before time.Time) ([]*models.Session, error) {
+	sessions, err := s.sessions.FindByField(ctx, "status", string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	expired := []*models.Session{}
+	for _, session := range sessions {
+		if session.ExpiresAt.Before(before) {
+			expired = append(expired, session)
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewExpiredSessionFinder tests the NewExpiredSessionFinder function
+func TestNewExpiredSessionFinder(t *testing.T) {
+	t.Run("nil client returns memory finder", func(t *testing.T) {
+		_, ok := NewExpiredSessionFinder(nil, NewMockSessionRepository()).(*MemoryExpiredSessionFinder)
+		assert.True(t, ok, "Should return a MemoryExpiredSessionFinder when client is nil")
+	})
+}
+
+// TestMemoryExpiredSessionFinder tests that only orders past their expiry are returned
+func TestMemoryExpiredSessionFinder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	expired := &models.Session{ID: "session_expired", Status: models.StatusCreated, ExpiresAt: now.Add(-time.Minute)}
+	fresh := &models.Session{ID: "session_fresh", Status: models.StatusCreated, ExpiresAt: now.Add(time.Minute)}
+
+	repo := NewMockSessionRepository().(*MockSessionRepository)
+	repo.On("FindByField", ctx, "status", "created").Return([]*models.Session{expired, fresh}, nil)
+
+	sessions, err := NewMemoryExpiredSessionFinder(repo).FindExpired(ctx, models.StatusCreated, now)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Session{expired}, sessions)
+}
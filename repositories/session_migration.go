@@ -0,0 +1,98 @@
+package repositories
+
+// session_migration.go は支払い状態と提供の進行を1つのステータスで管理していた注文を、新しい形式で保存し直す移行を実装します。
+// 読み取り時の移行（Session.ToModel）は保存された値を変更しないため、ステータスや支払い状態での検索は旧形式の注文を見落とします。
+// デプロイ後にこの移行を一度実行し、保存された値を新しい形式にそろえます。
+
+import (
+	"backend/models"
+	"context"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SessionStatusMigrator は旧形式のステータスの注文を新しい形式で保存し直すストアです。
+type SessionStatusMigrator interface {
+	// MigrateLegacyStatuses は支払い状態が記録されていない注文を Session.MigrateLegacyStatus で移行して保存し、移行した注文の数を返します。
+	// 移行済みの注文は変更しないため、繰り返し実行できます。
+	MigrateLegacyStatuses(ctx context.Context) (int, error)
+}
+
+// NewSessionStatusMigrator は SessionStatusMigrator を生成します。
+// client が nil の場合は sessions の注文を移行するストアを返します。
+func NewSessionStatusMigrator(client *firestore.Client, sessions Repository[models.Session]) SessionStatusMigrator {
+	if client == nil {
+		return NewMemorySessionStatusMigrator(sessions)
+	}
+	return &FirestoreSessionStatusMigrator{
+		client:     client,
+		collection: "sessions",
+		updater:    &FirestoreSessionUpdater{client: client, collection: "sessions"},
+	}
+}
+
+// FirestoreSessionStatusMigrator は Firestore の "sessions" コレクションの注文を移行する SessionStatusMigrator です。
+type FirestoreSessionStatusMigrator struct {
+	client     *firestore.Client
+	collection string
+	updater    SessionUpdater
+}
+
+// MigrateLegacyStatuses は全ての注文を読み取り、payment_status が保存されていない注文をトランザクションで保存し直します。
+// 支払い状態のない注文はフィールドが存在せず検索条件で絞り込めないため、全件を読み取って判定します。
+func (r *FirestoreSessionStatusMigrator) MigrateLegacyStatuses(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, doc := range docs {
+		stored := &Session{}
+		if err := doc.DataTo(stored); err != nil {
+			return migrated, err
+		}
+		if stored.PaymentStatus != "" {
+			continue
+		}
+
+		// 読み取り時に移行されるため、トランザクション内で読み取った注文をそのまま保存する
+		if _, err := r.updater.Update(ctx, doc.Ref.ID, func(*models.Session) error { return nil }); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// MemorySessionStatusMigrator は Repository の注文を移行する SessionStatusMigrator です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemorySessionStatusMigrator struct {
+	sessions Repository[models.Session]
+}
+
+func NewMemorySessionStatusMigrator(sessions Repository[models.Session]) *MemorySessionStatusMigrator {
+	return &MemorySessionStatusMigrator{
+		sessions: sessions,
+	}
+}
+
+// MigrateLegacyStatuses は支払い状態のない注文を移行して保存します。
+func (s *MemorySessionStatusMigrator) MigrateLegacyStatuses(ctx context.Context) (int, error) {
+	sessions, err := s.sessions.Read(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, session := range sessions {
+		if !session.MigrateLegacyStatus() {
+			continue
+		}
+		if err := s.sessions.UpdateByID(ctx, session.ID, session); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
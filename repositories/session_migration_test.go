@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewSessionStatusMigrator tests the NewSessionStatusMigrator function
+func TestNewSessionStatusMigrator(t *testing.T) {
+	t.Run("nil client returns memory migrator", func(t *testing.T) {
+		_, ok := NewSessionStatusMigrator(nil, NewMockSessionRepository()).(*MemorySessionStatusMigrator)
+		assert.True(t, ok, "Should return a MemorySessionStatusMigrator when client is nil")
+	})
+}
+
+// TestMemorySessionStatusMigrator tests that only legacy orders are written back
+func TestMemorySessionStatusMigrator(t *testing.T) {
+	ctx := context.Background()
+	legacy := &models.Session{ID: "session_legacy", Status: models.StatusPaymentReceived}
+	current := &models.Session{ID: "session_current", Status: models.StatusCreated, PaymentStatus: models.PaymentStatusUnpaid}
+
+	repo := NewMockSessionRepository().(*MockSessionRepository)
+	repo.On("Read", ctx).Return([]*models.Session{legacy, current}, nil)
+	repo.On("UpdateByID", ctx, legacy.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+
+	migrated, err := NewMemorySessionStatusMigrator(repo).MigrateLegacyStatuses(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+	assert.Equal(t, models.StatusPendingConfirmation, legacy.Status)
+	assert.Equal(t, models.PaymentStatusPaid, legacy.PaymentStatus)
+	repo.AssertNotCalled(t, "UpdateByID", ctx, current.ID, mock.Anything)
+}
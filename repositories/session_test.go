@@ -32,22 +32,23 @@ func TestMockSessionRepository(t *testing.T) {
 		OrderID:   "order_123",
 		ProductID: "product_456",
 		Quantity:  2,
-		Price:     1000.0,
+		Price:     models.Yen(1000),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
 	testSession := &models.Session{
-		ID:          "session_test123",
-		StoreID:     "store_456",
-		SeatID:      "seat_789",
-		Items:       []models.Order{testOrder},
-		TotalAmount: 2000.0,
-		Status:      models.StatusCreated,
-		ExpiresAt:   now.Add(15 * time.Minute),
-		IssuedAt:    now,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:           "session_test123",
+		StoreID:      "store_456",
+		SeatID:       "seat_789",
+		Items:        []models.Order{testOrder},
+		TotalAmount:  models.Yen(2000),
+		Status:       models.StatusCreated,
+		ExpiresAt:    now.Add(15 * time.Minute),
+		WorkflowName: models.WorkflowCounter,
+		IssuedAt:     now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 
 	testSessions := []*models.Session{testSession}
@@ -129,7 +130,7 @@ func TestMockSessionRepository(t *testing.T) {
 			StoreID:     "store_456",
 			SeatID:      "seat_789",
 			Items:       testSession.Items,
-			TotalAmount: 2500.0,
+			TotalAmount: models.Yen(2500),
 			Status:      models.StatusConfirmed,
 			ExpiresAt:   testSession.ExpiresAt,
 			IssuedAt:    testSession.IssuedAt,
@@ -278,7 +279,7 @@ func TestSessionStruct(t *testing.T) {
 		OrderID:   "order_123",
 		ProductID: "product_456",
 		Quantity:  2,
-		Price:     1000.0,
+		Price:     models.Yen(1000),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -288,7 +289,7 @@ func TestSessionStruct(t *testing.T) {
 		StoreID:     "store_456",
 		SeatID:      "seat_789",
 		Items:       []models.Order{testOrder},
-		TotalAmount: 2000.0,
+		TotalAmount: models.Yen(2000),
 		Status:      models.StatusCreated,
 		ExpiresAt:   now.Add(15 * time.Minute),
 		IssuedAt:    now,
@@ -302,8 +303,10 @@ func TestSessionStruct(t *testing.T) {
 		assert.Equal(t, testSession.ID, repoSession.ID)
 		assert.Equal(t, testSession.StoreID, repoSession.StoreID)
 		assert.Equal(t, testSession.SeatID, repoSession.SeatID)
-		assert.Equal(t, testSession.TotalAmount, repoSession.TotalAmount)
+		assert.Equal(t, testSession.TotalAmount.Amount, repoSession.TotalAmount)
+		assert.Equal(t, "JPY", repoSession.Currency)
 		assert.Equal(t, Status(testSession.Status), repoSession.Status)
+		assert.Equal(t, testSession.WorkflowName, repoSession.WorkflowName)
 		assert.Equal(t, testSession.ExpiresAt, repoSession.ExpiresAt)
 		assert.Equal(t, testSession.IssuedAt, repoSession.IssuedAt)
 		assert.Equal(t, testSession.CreatedAt, repoSession.CreatedAt)
@@ -325,16 +328,17 @@ func TestSessionStruct(t *testing.T) {
 
 	t.Run("ToModel conversion", func(t *testing.T) {
 		repoSession := &Session{
-			ID:          "session_456",
-			StoreID:     "store_789",
-			SeatID:      "seat_123",
-			Items:       []Order{{OrderID: "order_456", ProductID: "product_789", Quantity: 1, Price: 500.0, CreatedAt: now, UpdatedAt: now}},
-			TotalAmount: 500.0,
-			Status:      Status(models.StatusConfirmed),
-			ExpiresAt:   now.Add(10 * time.Minute),
-			IssuedAt:    now,
-			CreatedAt:   now,
-			UpdatedAt:   now,
+			ID:           "session_456",
+			StoreID:      "store_789",
+			SeatID:       "seat_123",
+			Items:        []Order{{OrderID: "order_456", ProductID: "product_789", Quantity: 1, Price: 500, CreatedAt: now, UpdatedAt: now}},
+			TotalAmount:  500,
+			Status:       Status(models.StatusConfirmed),
+			ExpiresAt:    now.Add(10 * time.Minute),
+			WorkflowName: models.WorkflowPaymentFirst,
+			IssuedAt:     now,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		}
 
 		modelSession := repoSession.ToModel()
@@ -342,8 +346,9 @@ func TestSessionStruct(t *testing.T) {
 		assert.Equal(t, repoSession.ID, modelSession.ID)
 		assert.Equal(t, repoSession.StoreID, modelSession.StoreID)
 		assert.Equal(t, repoSession.SeatID, modelSession.SeatID)
-		assert.Equal(t, repoSession.TotalAmount, modelSession.TotalAmount)
+		assert.Equal(t, models.Yen(repoSession.TotalAmount), modelSession.TotalAmount, "通貨のない既存データはJPYとして扱う")
 		assert.Equal(t, models.Status(repoSession.Status), modelSession.Status)
+		assert.Equal(t, repoSession.WorkflowName, modelSession.WorkflowName)
 		assert.Equal(t, repoSession.ExpiresAt, modelSession.ExpiresAt)
 		assert.Equal(t, repoSession.IssuedAt, modelSession.IssuedAt)
 		assert.Equal(t, repoSession.CreatedAt, modelSession.CreatedAt)
@@ -360,7 +365,7 @@ func TestOrderConversions(t *testing.T) {
 			OrderID:   "order_1",
 			ProductID: "product_1",
 			Quantity:  2,
-			Price:     1000.0,
+			Price:     models.Yen(1000),
 			CreatedAt: now,
 			UpdatedAt: now,
 		},
@@ -368,7 +373,7 @@ func TestOrderConversions(t *testing.T) {
 			OrderID:   "order_2",
 			ProductID: "product_2",
 			Quantity:  1,
-			Price:     500.0,
+			Price:     models.Yen(500),
 			CreatedAt: now,
 			UpdatedAt: now,
 		},
@@ -382,7 +387,8 @@ func TestOrderConversions(t *testing.T) {
 			assert.Equal(t, testOrders[i].OrderID, repoOrder.OrderID)
 			assert.Equal(t, testOrders[i].ProductID, repoOrder.ProductID)
 			assert.Equal(t, testOrders[i].Quantity, repoOrder.Quantity)
-			assert.Equal(t, testOrders[i].Price, repoOrder.Price)
+			assert.Equal(t, testOrders[i].Price.Amount, repoOrder.Price)
+			assert.Equal(t, string(testOrders[i].Price.Currency), repoOrder.Currency)
 			assert.Equal(t, testOrders[i].CreatedAt, repoOrder.CreatedAt)
 			assert.Equal(t, testOrders[i].UpdatedAt, repoOrder.UpdatedAt)
 		}
@@ -394,7 +400,7 @@ func TestOrderConversions(t *testing.T) {
 				OrderID:   "order_3",
 				ProductID: "product_3",
 				Quantity:  3,
-				Price:     750.0,
+				Price:     750,
 				CreatedAt: now,
 				UpdatedAt: now,
 			},
@@ -405,12 +411,160 @@ func TestOrderConversions(t *testing.T) {
 		assert.Equal(t, repoOrders[0].OrderID, modelOrders[0].OrderID)
 		assert.Equal(t, repoOrders[0].ProductID, modelOrders[0].ProductID)
 		assert.Equal(t, repoOrders[0].Quantity, modelOrders[0].Quantity)
-		assert.Equal(t, repoOrders[0].Price, modelOrders[0].Price)
+		assert.Equal(t, models.Yen(repoOrders[0].Price), modelOrders[0].Price)
 		assert.Equal(t, repoOrders[0].CreatedAt, modelOrders[0].CreatedAt)
 		assert.Equal(t, repoOrders[0].UpdatedAt, modelOrders[0].UpdatedAt)
 	})
 }
 
+// TestDiscountConversions tests the discount conversions
+func TestDiscountConversions(t *testing.T) {
+	now := time.Now()
+	discounts := []models.Discount{
+		{
+			ID:          "disc_1",
+			Source:      models.DiscountSourcePromotion,
+			PromotionID: "promo_1",
+			Code:        "SPRING10",
+			Rule:        models.DiscountRule{Kind: models.DiscountPercentage, Value: 10, Scope: models.DiscountScopeCategory, Categories: []string{"drink"}},
+			Amount:      models.Yen(110),
+			Allocations: []models.DiscountAllocation{{LineID: "line_1", Amount: models.Yen(110)}},
+			AppliedAt:   now,
+		},
+		{
+			ID:          "disc_2",
+			Source:      models.DiscountSourceManual,
+			Rule:        models.DiscountRule{Kind: models.DiscountFixed, Value: 100, Scope: models.DiscountScopeItem, LineIDs: []string{"line_2"}},
+			Reason:      "提供遅れ",
+			AppliedBy:   "manager:a@example.com",
+			Amount:      models.Yen(100),
+			Allocations: []models.DiscountAllocation{{LineID: "line_2", Amount: models.Yen(100)}},
+			AppliedAt:   now,
+		},
+	}
+
+	repoDiscounts := ToSetDiscounts(discounts)
+	assert.Equal(t, int64(110), repoDiscounts[0].Amount)
+	assert.Equal(t, "category", repoDiscounts[0].Rule.Scope)
+	assert.Equal(t, discounts, ToModelDiscounts(repoDiscounts, "JPY"))
+	assert.Nil(t, ToModelDiscounts(nil, "JPY"))
+}
+
+func TestChargeConversions(t *testing.T) {
+	now := time.Now()
+	charges := []models.Charge{
+		{
+			ID:        "charge_1",
+			RuleID:    "chrule_1",
+			Name:      "お通し",
+			Kind:      models.ChargePerPerson,
+			Value:     300,
+			Quantity:  2,
+			Amount:    models.Yen(600),
+			AppliedAt: now,
+		},
+		{
+			ID:          "charge_2",
+			RuleID:      "chrule_2",
+			Name:        "深夜料金",
+			Kind:        models.ChargePercentage,
+			Value:       10,
+			Quantity:    1,
+			TaxCategory: models.TaxCategoryStandard,
+			Amount:      models.Yen(150),
+			Waived:      true,
+			WaiveReason: "常連のお客様",
+			WaivedBy:    "manager:a@example.com",
+			WaivedAt:    now,
+			AppliedAt:   now,
+		},
+	}
+
+	repoCharges := ToSetCharges(charges)
+	assert.Equal(t, int64(600), repoCharges[0].Amount)
+	assert.Equal(t, "percentage", repoCharges[1].Kind)
+	assert.Equal(t, charges, ToModelCharges(repoCharges, "JPY"))
+	assert.Nil(t, ToModelCharges(nil, "JPY"))
+}
+
+func TestRefundConversions(t *testing.T) {
+	now := time.Now()
+	refunds := []models.Refund{
+		{ID: "refund_1", Amount: models.Yen(1000), Reason: "提供遅れ", Actor: "manager:a@example.com", PaymentRef: "re_1", CreatedAt: now},
+		{ID: "refund_2", Amount: models.Yen(1100), LineIDs: []string{"line_1"}, Reason: "品切れ", Pending: true, CreatedAt: now},
+	}
+
+	repoRefunds := ToSetRefunds(refunds)
+	assert.Equal(t, int64(1000), repoRefunds[0].Amount)
+	assert.Equal(t, refunds, ToModelRefunds(repoRefunds, "JPY"))
+	assert.Nil(t, ToModelRefunds(nil, "JPY"))
+}
+
+func TestStatusHistoryConversions(t *testing.T) {
+	now := time.Now()
+	history := []models.StatusChange{
+		{To: models.StatusCreated, At: now},
+		{From: models.StatusCreated, To: models.StatusConfirmed, At: now.Add(time.Minute), Actor: "manager:a@example.com"},
+		{From: models.StatusServed, To: models.StatusPartiallyRefunded, At: now.Add(time.Hour), Reason: "提供遅れ", Exception: true},
+	}
+
+	repoHistory := ToSetStatusHistory(history)
+	assert.Equal(t, "confirmed", repoHistory[1].To)
+	assert.Equal(t, history, ToModelStatusHistory(repoHistory))
+	assert.Nil(t, ToModelStatusHistory(nil))
+}
+
+func TestPaymentHistoryConversions(t *testing.T) {
+	now := time.Now()
+	history := []models.PaymentStatusChange{
+		{To: models.PaymentStatusUnpaid, At: now},
+		{From: models.PaymentStatusUnpaid, To: models.PaymentStatusPaid, At: now.Add(time.Minute), Actor: "manager:a@example.com", Reason: "レジでの精算"},
+	}
+
+	repoHistory := ToSetPaymentHistory(history)
+	assert.Equal(t, "paid", repoHistory[1].To)
+	assert.Equal(t, history, ToModelPaymentHistory(repoHistory))
+	assert.Nil(t, ToModelPaymentHistory(nil))
+}
+
+func TestOrderLineStatusConversions(t *testing.T) {
+	now := time.Now()
+	orders := []models.Order{
+		{
+			OrderID:      "order_1",
+			LineID:       "line_1",
+			Quantity:     1,
+			Price:        models.Yen(500),
+			Status:       models.LineVoided,
+			Instructions: "ネギ抜き",
+			Adjustments: []models.QuantityAdjustment{
+				{From: 3, To: 1, Reason: "注文間違い", Actor: "manager:a@example.com", At: now},
+			},
+			VoidReason: "品切れ",
+			VoidedBy:   "manager:a@example.com",
+			VoidedAt:   now.Add(time.Minute),
+		},
+	}
+
+	repoOrders := ToSetOrders(orders)
+	assert.Equal(t, "voided", repoOrders[0].Status)
+	assert.Equal(t, 3, repoOrders[0].Adjustments[0].From)
+	assert.Equal(t, orders, ToModelOrders(repoOrders))
+	assert.Nil(t, ToModelQuantityAdjustments(nil))
+}
+
+func TestSessionToModelMigratesLegacyStatus(t *testing.T) {
+	legacy := &Session{ID: "session_1", Status: Status(models.StatusPendingPayment)}
+	session := legacy.ToModel()
+	assert.Equal(t, models.StatusCreated, session.Status)
+	assert.Equal(t, models.PaymentStatusPending, session.PaymentStatus)
+
+	current := &Session{ID: "session_2", Status: Status(models.StatusServed), PaymentStatus: "paid"}
+	session = current.ToModel()
+	assert.Equal(t, models.StatusServed, session.Status)
+	assert.Equal(t, models.PaymentStatusPaid, session.PaymentStatus)
+}
+
 // TestSessionRepositoryBusinessLogic tests business logic scenarios
 func TestSessionRepositoryBusinessLogic(t *testing.T) {
 	ctx := context.Background()
@@ -495,8 +649,8 @@ func TestSessionRepositoryTransactionScenarios(t *testing.T) {
 			ID:          "session_new_123",
 			StoreID:     "store_456",
 			SeatID:      "seat_789",
-			Items:       []models.Order{{OrderID: "order_123", ProductID: "product_456", Quantity: 1, Price: 1000.0, CreatedAt: now, UpdatedAt: now}},
-			TotalAmount: 1000.0,
+			Items:       []models.Order{{OrderID: "order_123", ProductID: "product_456", Quantity: 1, Price: models.Yen(1000), CreatedAt: now, UpdatedAt: now}},
+			TotalAmount: models.Yen(1000),
 			Status:      models.StatusCreated,
 			ExpiresAt:   now.Add(15 * time.Minute),
 			IssuedAt:    now,
@@ -531,8 +685,8 @@ func TestSessionRepositoryTransactionScenarios(t *testing.T) {
 			ID:          "session_update_123",
 			StoreID:     "store_456",
 			SeatID:      "seat_789",
-			Items:       []models.Order{{OrderID: "order_123", ProductID: "product_456", Quantity: 1, Price: 1000.0, CreatedAt: now, UpdatedAt: now}},
-			TotalAmount: 1000.0,
+			Items:       []models.Order{{OrderID: "order_123", ProductID: "product_456", Quantity: 1, Price: models.Yen(1000), CreatedAt: now, UpdatedAt: now}},
+			TotalAmount: models.Yen(1000),
 			Status:      models.StatusCreated,
 			ExpiresAt:   now.Add(15 * time.Minute),
 			IssuedAt:    now,
@@ -652,7 +806,7 @@ func TestSessionRepositoryEdgeCases(t *testing.T) {
 			StoreID:     "store_123",
 			SeatID:      "seat_456",
 			Items:       []models.Order{},
-			TotalAmount: 0.0,
+			TotalAmount: models.Yen(0),
 			Status:      models.StatusCreated,
 			ExpiresAt:   now.Add(-1 * time.Hour), // Already expired
 			IssuedAt:    now.Add(-2 * time.Hour),
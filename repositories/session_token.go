@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SessionTokenRepository は Firestore の session_tokens コレクションを操作するためのリポジトリです。
+// StartSession で発行したセッションJWTを jti 単位で記録し、会計時の失効に使用します。
+type SessionTokenRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewSessionTokenRepository は新しい SessionTokenRepository のインスタンスを生成します。
+func NewSessionTokenRepository(client *firestore.Client) Repository[models.SessionToken] {
+	if client == nil {
+		return NewMockSessionTokenRepository()
+	}
+	return &SessionTokenRepository{
+		client:     client,
+		collection: "session_tokens",
+	}
+}
+
+type SessionToken struct {
+	ID        string    `firestore:"id"`
+	StoreID   string    `firestore:"store_id"`
+	SeatID    string    `firestore:"seat_id"`
+	VisitID   string    `firestore:"visit_id"`
+	UserAgent string    `firestore:"user_agent"`
+	IPAddress string    `firestore:"ip_address"`
+	IssuedAt  time.Time `firestore:"issued_at"`
+	ExpiresAt time.Time `firestore:"expires_at"`
+	RevokedAt time.Time `firestore:"revoked_at"`
+}
+
+func ToSetSessionToken(token *models.SessionToken) *SessionToken {
+	return &SessionToken{
+		ID:        token.ID,
+		StoreID:   token.StoreID,
+		SeatID:    token.SeatID,
+		VisitID:   token.VisitID,
+		UserAgent: token.UserAgent,
+		IPAddress: token.IPAddress,
+		IssuedAt:  token.IssuedAt,
+		ExpiresAt: token.ExpiresAt,
+		RevokedAt: token.RevokedAt,
+	}
+}
+
+func (s *SessionToken) ToModel() *models.SessionToken {
+	return &models.SessionToken{
+		ID:        s.ID,
+		StoreID:   s.StoreID,
+		SeatID:    s.SeatID,
+		VisitID:   s.VisitID,
+		UserAgent: s.UserAgent,
+		IPAddress: s.IPAddress,
+		IssuedAt:  s.IssuedAt,
+		ExpiresAt: s.ExpiresAt,
+		RevokedAt: s.RevokedAt,
+	}
+}
+
+// Create は新しいセッショントークンを Firestore に作成します。
+func (r *SessionTokenRepository) Create(ctx context.Context, token *models.SessionToken) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(token.ID).Set(ctx, ToSetSessionToken(token))
+	return err
+}
+
+// Read はすべてのセッショントークンを Firestore から読み取ります。
+func (r *SessionTokenRepository) Read(ctx context.Context) ([]*models.SessionToken, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*models.SessionToken, len(docs))
+	for i, doc := range docs {
+		token := &SessionToken{}
+		if err := doc.DataTo(token); err != nil {
+			return nil, err
+		}
+		tokens[i] = token.ToModel()
+	}
+
+	return tokens, nil
+}
+
+// FindByID は指定されたID（jti）のセッショントークンを Firestore から検索します。
+func (r *SessionTokenRepository) FindByID(ctx context.Context, id string) (*models.SessionToken, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &SessionToken{}
+	if err := doc.DataTo(token); err != nil {
+		return nil, err
+	}
+
+	return token.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致するセッショントークンを Firestore から検索します。
+func (r *SessionTokenRepository) FindByField(ctx context.Context, field string, value any) ([]*models.SessionToken, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*models.SessionToken, len(docs))
+	for i, doc := range docs {
+		token := &SessionToken{}
+		if err := doc.DataTo(token); err != nil {
+			return nil, err
+		}
+		tokens[i] = token.ToModel()
+	}
+
+	return tokens, nil
+}
+
+// UpdateByID は指定されたIDのセッショントークンを Firestore で更新します。
+func (r *SessionTokenRepository) UpdateByID(ctx context.Context, id string, token *models.SessionToken) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetSessionToken(token))
+	return err
+}
+
+// DeleteByID は指定されたIDのセッショントークンを Firestore から削除します。
+func (r *SessionTokenRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されているセッショントークンの総数を返します。
+func (r *SessionTokenRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDのセッショントークンが Firestore に存在するかどうかを確認します。
+func (r *SessionTokenRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
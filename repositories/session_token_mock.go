@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSessionTokenRepository - 実際のFirestoreの複雑な実装は不要
+type MockSessionTokenRepository struct {
+	mock.Mock
+}
+
+func NewMockSessionTokenRepository() Repository[models.SessionToken] {
+	return &MockSessionTokenRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockSessionTokenRepository) Create(ctx context.Context, token *models.SessionToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockSessionTokenRepository) Read(ctx context.Context) ([]*models.SessionToken, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.SessionToken{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.SessionToken), nil
+}
+
+func (m *MockSessionTokenRepository) FindByID(ctx context.Context, id string) (*models.SessionToken, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SessionToken), nil
+}
+
+func (m *MockSessionTokenRepository) FindByField(ctx context.Context, field string, value any) ([]*models.SessionToken, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.SessionToken{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.SessionToken), nil
+}
+
+func (m *MockSessionTokenRepository) UpdateByID(ctx context.Context, id string, token *models.SessionToken) error {
+	args := m.Called(ctx, id, token)
+	return args.Error(0)
+}
+
+func (m *MockSessionTokenRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionTokenRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockSessionTokenRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewSessionTokenRepository tests the NewSessionTokenRepository function
+func TestNewSessionTokenRepository(t *testing.T) {
+	t.Run("NewSessionTokenRepository with nil client returns MockSessionTokenRepository", func(t *testing.T) {
+		repo := NewSessionTokenRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockSessionTokenRepository)
+		assert.True(t, ok, "Should return a MockSessionTokenRepository when client is nil")
+	})
+}
+
+// TestMockSessionTokenRepository tests the MockSessionTokenRepository implementation
+func TestMockSessionTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	testToken := &models.SessionToken{ID: "stok_123", StoreID: "store_123", SeatID: "seat_123", VisitID: "visit_123"}
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockSessionTokenRepository{}
+		mockRepo.On("FindByField", mock.Anything, "visit_id", "visit_123").Return([]*models.SessionToken{testToken}, nil)
+
+		tokens, err := mockRepo.FindByField(ctx, "visit_id", "visit_123")
+		assert.NoError(t, err)
+		assert.Len(t, tokens, 1)
+		assert.Equal(t, testToken.ID, tokens[0].ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateByID", func(t *testing.T) {
+		mockRepo := &MockSessionTokenRepository{}
+		mockRepo.On("UpdateByID", mock.Anything, "stok_123", testToken).Return(nil)
+
+		assert.NoError(t, mockRepo.UpdateByID(ctx, "stok_123", testToken))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestSessionTokenStruct tests the SessionToken struct conversions
+func TestSessionTokenStruct(t *testing.T) {
+	now := time.Now()
+	testToken := &models.SessionToken{
+		ID:        "stok_123",
+		StoreID:   "store_123",
+		SeatID:    "seat_123",
+		VisitID:   "visit_123",
+		UserAgent: "Mozilla/5.0",
+		IPAddress: "192.0.2.1",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+		RevokedAt: now.Add(time.Minute),
+	}
+
+	repoToken := ToSetSessionToken(testToken)
+	assert.Equal(t, testToken, repoToken.ToModel())
+}
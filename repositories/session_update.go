@@ -0,0 +1,120 @@
+package repositories
+
+// session_update.go は注文の読み取りから更新までを不可分に行う更新を実装します。
+// 返金の残額の判定など、読み取った注文の内容に基づく更新を同時に行っても互いの変更を上書きしないよう、
+// Firestore のトランザクションで読み取りと書き込みを行います。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SessionUpdater は注文を読み取ってから更新するまでを不可分に行うストアです。
+type SessionUpdater interface {
+	// Update は id の注文を読み取り、update で変更した注文を保存して返します。
+	// update がエラーを返した場合は保存せずにそのエラーを返します。注文がない場合は codes.NotFound のエラーを返します。
+	// 他の更新と競合した場合、update は最新の注文で再度呼び出されることがあります。
+	Update(ctx context.Context, id string, update func(*models.Session) error) (*models.Session, error)
+
+	// UpdateOpen は Update と同じトランザクションで closeID のレジ締めを読み取り、レジ締め済みの場合は
+	// models.ErrBusinessDayClosed を返して保存しません。返金など営業日の売上に含まれる更新に使用します。
+	UpdateOpen(ctx context.Context, closeID, id string, update func(*models.Session) error) (*models.Session, error)
+}
+
+// NewSessionUpdater は SessionUpdater を生成します。
+// client が nil の場合は sessions をプロセス内の排他制御で更新するストアを返します。
+func NewSessionUpdater(client *firestore.Client, sessions Repository[models.Session], closes Repository[models.RegisterClose]) SessionUpdater {
+	if client == nil {
+		return NewMemorySessionUpdater(sessions, closes)
+	}
+	return &FirestoreSessionUpdater{
+		client:     client,
+		collection: "sessions",
+	}
+}
+
+// FirestoreSessionUpdater は Firestore の "sessions" コレクションをトランザクションで更新する SessionUpdater です。
+type FirestoreSessionUpdater struct {
+	client     *firestore.Client
+	collection string
+}
+
+// Update はトランザクション内で注文を読み取り、更新します。
+func (r *FirestoreSessionUpdater) Update(ctx context.Context, id string, update func(*models.Session) error) (*models.Session, error) {
+	return r.UpdateOpen(ctx, "", id, update)
+}
+
+// UpdateOpen はトランザクション内でレジ締めと注文を読み取り、更新します。
+func (r *FirestoreSessionUpdater) UpdateOpen(ctx context.Context, closeID, id string, update func(*models.Session) error) (*models.Session, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(id)
+
+	var session *models.Session
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := ensureOpenInTx(tx, r.client, closeID); err != nil {
+			return err
+		}
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		stored := &Session{}
+		if err := doc.DataTo(stored); err != nil {
+			return err
+		}
+
+		session = stored.ToModel()
+		if err := update(session); err != nil {
+			return err
+		}
+		return tx.Set(ref, ToSetSession(session))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// MemorySessionUpdater はプロセス内の排他制御で注文を更新する SessionUpdater です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemorySessionUpdater struct {
+	mu       sync.Mutex
+	sessions Repository[models.Session]
+	closes   Repository[models.RegisterClose]
+}
+
+func NewMemorySessionUpdater(sessions Repository[models.Session], closes Repository[models.RegisterClose]) *MemorySessionUpdater {
+	return &MemorySessionUpdater{
+		sessions: sessions,
+		closes:   closes,
+	}
+}
+
+// Update は注文を読み取り、更新します。
+func (s *MemorySessionUpdater) Update(ctx context.Context, id string, update func(*models.Session) error) (*models.Session, error) {
+	return s.UpdateOpen(ctx, "", id, update)
+}
+
+// UpdateOpen はレジ締めと注文を読み取り、更新します。
+func (s *MemorySessionUpdater) UpdateOpen(ctx context.Context, closeID, id string, update func(*models.Session) error) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ensureOpen(ctx, s.closes, closeID); err != nil {
+		return nil, err
+	}
+	session, err := s.sessions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(session); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.UpdateByID(ctx, id, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
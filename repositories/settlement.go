@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SettlementRepository は Firestore の settlements コレクションを操作するためのリポジトリです。
+type SettlementRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewSettlementRepository は新しい SettlementRepository のインスタンスを生成します。
+func NewSettlementRepository(client *firestore.Client) Repository[models.Settlement] {
+	if client == nil {
+		return NewMockSettlementRepository()
+	}
+	return &SettlementRepository{
+		client:     client,
+		collection: "settlements",
+	}
+}
+
+type Settlement struct {
+	ID         string   `firestore:"id"`
+	StoreID    string   `firestore:"store_id"`
+	SeatID     string   `firestore:"seat_id"`
+	VisitID    string   `firestore:"visit_id"`
+	SessionIDs []string `firestore:"session_ids"`
+
+	Currency string   `firestore:"currency"`
+	Total    int64    `firestore:"total"`
+	Tenders  []Tender `firestore:"tenders"`
+	Tendered int64    `firestore:"tendered"`
+	Change   int64    `firestore:"change"`
+
+	Actor     string    `firestore:"actor"`
+	CreatedAt time.Time `firestore:"created_at"`
+}
+
+type Tender struct {
+	Method    string `firestore:"method"`
+	Amount    int64  `firestore:"amount"`
+	Reference string `firestore:"reference"`
+}
+
+func ToSetSettlement(s *models.Settlement) *Settlement {
+	tenders := make([]Tender, len(s.Tenders))
+	for i, t := range s.Tenders {
+		tenders[i] = Tender{
+			Method:    string(t.Method),
+			Amount:    t.Amount.Amount,
+			Reference: t.Reference,
+		}
+	}
+
+	return &Settlement{
+		ID:         s.ID,
+		StoreID:    s.StoreID,
+		SeatID:     s.SeatID,
+		VisitID:    s.VisitID,
+		SessionIDs: s.SessionIDs,
+
+		Currency: string(s.Total.Currency),
+		Total:    s.Total.Amount,
+		Tenders:  tenders,
+		Tendered: s.Tendered.Amount,
+		Change:   s.Change.Amount,
+
+		Actor:     s.Actor,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func (s *Settlement) ToModel() *models.Settlement {
+	tenders := make([]models.Tender, len(s.Tenders))
+	for i, t := range s.Tenders {
+		tenders[i] = models.Tender{
+			Method:    models.TenderMethod(t.Method),
+			Amount:    ToModelMoney(t.Amount, s.Currency),
+			Reference: t.Reference,
+		}
+	}
+
+	return &models.Settlement{
+		ID:         s.ID,
+		StoreID:    s.StoreID,
+		SeatID:     s.SeatID,
+		VisitID:    s.VisitID,
+		SessionIDs: s.SessionIDs,
+
+		Total:    ToModelMoney(s.Total, s.Currency),
+		Tenders:  tenders,
+		Tendered: ToModelMoney(s.Tendered, s.Currency),
+		Change:   ToModelMoney(s.Change, s.Currency),
+
+		Actor:     s.Actor,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// Create は新しい会計の精算記録を Firestore に作成します。
+func (r *SettlementRepository) Create(ctx context.Context, settlement *models.Settlement) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(settlement.ID).Create(ctx, ToSetSettlement(settlement))
+	return err
+}
+
+// Read はすべての会計の精算記録を Firestore から読み取ります。
+func (r *SettlementRepository) Read(ctx context.Context) ([]*models.Settlement, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	settlements := make([]*models.Settlement, len(docs))
+	for i, doc := range docs {
+		settlement := &Settlement{}
+		if err := doc.DataTo(settlement); err != nil {
+			return nil, err
+		}
+		settlements[i] = settlement.ToModel()
+	}
+
+	return settlements, nil
+}
+
+// FindByID は指定されたIDの会計の精算記録を Firestore から検索します。
+func (r *SettlementRepository) FindByID(ctx context.Context, id string) (*models.Settlement, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &Settlement{}
+	if err := doc.DataTo(settlement); err != nil {
+		return nil, err
+	}
+
+	return settlement.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する会計の精算記録を Firestore から検索します。
+func (r *SettlementRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Settlement, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	settlements := make([]*models.Settlement, len(docs))
+	for i, doc := range docs {
+		settlement := &Settlement{}
+		if err := doc.DataTo(settlement); err != nil {
+			return nil, err
+		}
+		settlements[i] = settlement.ToModel()
+	}
+
+	return settlements, nil
+}
+
+// UpdateByID は指定されたIDの会計の精算記録を Firestore で更新します。
+func (r *SettlementRepository) UpdateByID(ctx context.Context, id string, settlement *models.Settlement) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetSettlement(settlement))
+	return err
+}
+
+// DeleteByID は指定されたIDの会計の精算記録を Firestore から削除します。
+func (r *SettlementRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている会計の精算記録の総数を返します。
+func (r *SettlementRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの会計の精算記録が Firestore に存在するかどうかを確認します。
+func (r *SettlementRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
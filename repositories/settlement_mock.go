@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSettlementRepository - 実際のFirestoreの複雑な実装は不要
+type MockSettlementRepository struct {
+	mock.Mock
+}
+
+func NewMockSettlementRepository() Repository[models.Settlement] {
+	return &MockSettlementRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockSettlementRepository) Create(ctx context.Context, settlement *models.Settlement) error {
+	args := m.Called(ctx, settlement)
+	return args.Error(0)
+}
+
+func (m *MockSettlementRepository) Read(ctx context.Context) ([]*models.Settlement, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.Settlement{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Settlement), nil
+}
+
+func (m *MockSettlementRepository) FindByID(ctx context.Context, id string) (*models.Settlement, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Settlement), nil
+}
+
+func (m *MockSettlementRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Settlement, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.Settlement{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Settlement), nil
+}
+
+func (m *MockSettlementRepository) UpdateByID(ctx context.Context, id string, settlement *models.Settlement) error {
+	args := m.Called(ctx, id, settlement)
+	return args.Error(0)
+}
+
+func (m *MockSettlementRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSettlementRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockSettlementRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+// settlement_record.go はレジでの精算の記録と来店の注文の完了を不可分に行う記録を実装します。
+// 精算記録と注文の支払い状態が食い違わず、レジ締め済みの営業日に精算を記録しないよう、
+// Firestore のトランザクションでレジ締めと来店の注文を読み取り、注文の更新と精算記録の作成をまとめて書き込みます。
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// SettlementRecorder は来店の注文を完了にして精算記録を作成するまでを不可分に行うストアです。
+type SettlementRecorder interface {
+	// Record は settlement.VisitID の来店の注文を読み取り、complete が返した注文を保存して精算記録を作成します。
+	// closeID のレジ締めがある場合は models.ErrBusinessDayClosed を、complete がエラーを返した場合はそのエラーを返し、何も保存しません。
+	// 同じIDの精算記録がすでにある場合は codes.AlreadyExists のエラーを返し、注文も保存しません。
+	// 他の更新と競合した場合、complete は最新の注文で再度呼び出されることがあります。
+	Record(ctx context.Context, closeID string, settlement *models.Settlement, complete func([]*models.Session) ([]*models.Session, error)) error
+}
+
+// NewSettlementRecorder は SettlementRecorder を生成します。
+// client が nil の場合は各リポジトリをプロセス内の排他制御で読み書きするストアを返します。
+func NewSettlementRecorder(client *firestore.Client, settlements Repository[models.Settlement], sessions Repository[models.Session], closes Repository[models.RegisterClose]) SettlementRecorder {
+	if client == nil {
+		return NewMemorySettlementRecorder(settlements, sessions, closes)
+	}
+	return &FirestoreSettlementRecorder{
+		client:     client,
+		collection: "settlements",
+		sessions:   "sessions",
+	}
+}
+
+// FirestoreSettlementRecorder は Firestore の "settlements" コレクションにトランザクションで精算記録を作成する SettlementRecorder です。
+// 来店の注文は "sessions" コレクションから読み取り、更新します。
+type FirestoreSettlementRecorder struct {
+	client     *firestore.Client
+	collection string
+	sessions   string
+}
+
+// Record はトランザクション内でレジ締めと来店の注文を読み取り、注文の更新と精算記録の作成を行います。
+func (r *FirestoreSettlementRecorder) Record(ctx context.Context, closeID string, settlement *models.Settlement, complete func([]*models.Session) ([]*models.Session, error)) error {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(settlement.ID)
+	sessions := r.client.Collection(GetCollectionName(r.sessions))
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := ensureOpenInTx(tx, r.client, closeID); err != nil {
+			return err
+		}
+		orders, err := getAllInTx[Session, models.Session](tx, sessions.Where("visit_id", "==", settlement.VisitID))
+		if err != nil {
+			return err
+		}
+
+		changed, err := complete(orders)
+		if err != nil {
+			return err
+		}
+		for _, order := range changed {
+			if err := tx.Set(sessions.Doc(order.ID), ToSetSession(order)); err != nil {
+				return err
+			}
+		}
+		return tx.Create(ref, ToSetSettlement(settlement))
+	})
+}
+
+// MemorySettlementRecorder はプロセス内の排他制御で精算記録を作成する SettlementRecorder です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemorySettlementRecorder struct {
+	mu          sync.Mutex
+	settlements Repository[models.Settlement]
+	sessions    Repository[models.Session]
+	closes      Repository[models.RegisterClose]
+}
+
+func NewMemorySettlementRecorder(settlements Repository[models.Settlement], sessions Repository[models.Session], closes Repository[models.RegisterClose]) *MemorySettlementRecorder {
+	return &MemorySettlementRecorder{
+		settlements: settlements,
+		sessions:    sessions,
+		closes:      closes,
+	}
+}
+
+// Record はレジ締めと来店の注文を読み取り、精算記録の作成と注文の更新を行います。
+// 精算記録を作成できなかった場合は注文を保存しません。
+func (s *MemorySettlementRecorder) Record(ctx context.Context, closeID string, settlement *models.Settlement, complete func([]*models.Session) ([]*models.Session, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ensureOpen(ctx, s.closes, closeID); err != nil {
+		return err
+	}
+	orders, err := s.sessions.FindByField(ctx, "visit_id", settlement.VisitID)
+	if err != nil {
+		return err
+	}
+	changed, err := complete(orders)
+	if err != nil {
+		return err
+	}
+	if err := s.settlements.Create(ctx, settlement); err != nil {
+		return err
+	}
+	for _, order := range changed {
+		if err := s.sessions.UpdateByID(ctx, order.ID, order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestNewSettlementRecorder tests the NewSettlementRecorder function
+func TestNewSettlementRecorder(t *testing.T) {
+	t.Run("nil client returns memory recorder", func(t *testing.T) {
+		_, ok := NewSettlementRecorder(nil, NewMockSettlementRepository(), NewMockSessionRepository(), NewMockRegisterCloseRepository()).(*MemorySettlementRecorder)
+		assert.True(t, ok, "Should return a MemorySettlementRecorder when client is nil")
+	})
+}
+
+// TestMemorySettlementRecorder_Record tests that the settlement and the completed orders are saved only while the business day is open
+func TestMemorySettlementRecorder_Record(t *testing.T) {
+	ctx := context.Background()
+	settlement := &models.Settlement{ID: "settle_visit_1", VisitID: "visit_1"}
+	orders := []*models.Session{{ID: "session_1", VisitID: "visit_1"}, {ID: "session_2", VisitID: "visit_1"}}
+	complete := func(orders []*models.Session) ([]*models.Session, error) {
+		return orders[:1], nil
+	}
+
+	setup := func(closed bool) (*MockSettlementRepository, *MockSessionRepository, *MemorySettlementRecorder) {
+		settlements := NewMockSettlementRepository().(*MockSettlementRepository)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		sessions.On("FindByField", ctx, "visit_id", "visit_1").Return(orders, nil)
+		closes := NewMockRegisterCloseRepository().(*MockRegisterCloseRepository)
+		closes.On("Exists", ctx, "store_1_2026-10-17").Return(closed, nil)
+		return settlements, sessions, NewMemorySettlementRecorder(settlements, sessions, closes)
+	}
+
+	t.Run("save the settlement and completed orders", func(t *testing.T) {
+		settlements, sessions, recorder := setup(false)
+		settlements.On("Create", ctx, settlement).Return(nil)
+		sessions.On("UpdateByID", ctx, "session_1", orders[0]).Return(nil)
+
+		require.NoError(t, recorder.Record(ctx, "store_1_2026-10-17", settlement, complete))
+		sessions.AssertNotCalled(t, "UpdateByID", ctx, "session_2", mock.Anything)
+		settlements.AssertExpectations(t)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("closed business day saves nothing", func(t *testing.T) {
+		settlements, sessions, recorder := setup(true)
+
+		err := recorder.Record(ctx, "store_1_2026-10-17", settlement, complete)
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+		settlements.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		sessions.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("existing settlement saves no orders", func(t *testing.T) {
+		settlements, sessions, recorder := setup(false)
+		settlements.On("Create", ctx, settlement).Return(status.Error(codes.AlreadyExists, "already exists"))
+
+		err := recorder.Record(ctx, "store_1_2026-10-17", settlement, complete)
+		assert.True(t, IsAlreadyExists(err))
+		sessions.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewSettlementRepository tests the NewSettlementRepository function
+func TestNewSettlementRepository(t *testing.T) {
+	t.Run("NewSettlementRepository with nil client returns MockSettlementRepository", func(t *testing.T) {
+		repo := NewSettlementRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockSettlementRepository)
+		assert.True(t, ok, "Should return a MockSettlementRepository when client is nil")
+	})
+}
+
+// TestMockSettlementRepository tests the MockSettlementRepository implementation
+func TestMockSettlementRepository(t *testing.T) {
+	ctx := context.Background()
+	testSettlement := &models.Settlement{ID: "settle_123", StoreID: "store_123", VisitID: "visit_123", Total: models.Yen(3300)}
+
+	t.Run("Create", func(t *testing.T) {
+		mockRepo := &MockSettlementRepository{}
+		mockRepo.On("Create", mock.Anything, testSettlement).Return(nil)
+
+		assert.NoError(t, mockRepo.Create(ctx, testSettlement))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("FindByField", func(t *testing.T) {
+		mockRepo := &MockSettlementRepository{}
+		mockRepo.On("FindByField", mock.Anything, "visit_id", "visit_123").Return([]*models.Settlement{testSettlement}, nil)
+
+		settlements, err := mockRepo.FindByField(ctx, "visit_id", "visit_123")
+		assert.NoError(t, err)
+		assert.Len(t, settlements, 1)
+		assert.Equal(t, testSettlement.ID, settlements[0].ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestSettlementStruct tests the Settlement struct conversions
+func TestSettlementStruct(t *testing.T) {
+	now := time.Now().UTC()
+	testSettlement := &models.Settlement{
+		ID:         "settle_123",
+		StoreID:    "store_123",
+		SeatID:     "seat_123",
+		VisitID:    "visit_123",
+		SessionIDs: []string{"session_1", "session_2"},
+		Total:      models.Yen(3300),
+		Tenders: []models.Tender{
+			{Method: models.TenderCard, Amount: models.Yen(2000), Reference: "auth_123"},
+			{Method: models.TenderCash, Amount: models.Yen(2000)},
+		},
+		Tendered:  models.Yen(4000),
+		Change:    models.Yen(700),
+		Actor:     "manager:a@example.com",
+		CreatedAt: now,
+	}
+
+	repoSettlement := ToSetSettlement(testSettlement)
+	assert.Equal(t, "JPY", repoSettlement.Currency)
+	assert.Equal(t, "card", repoSettlement.Tenders[0].Method)
+	assert.Equal(t, int64(700), repoSettlement.Change)
+	assert.Equal(t, testSettlement, repoSettlement.ToModel())
+}
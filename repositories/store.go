@@ -28,24 +28,258 @@ func NewStoreRepository(client *firestore.Client) Repository[models.Store] {
 }
 
 type Store struct {
-	ID        string    `firestore:"id"`
-	Name      string    `firestore:"name"`
-	Email     string    `firestore:"email"`
-	Password  string    `firestore:"password"`
-	Address   string    `firestore:"address"`
-	Phone     string    `firestore:"phone"`
+	ID       string `firestore:"id"`
+	Name     string `firestore:"name"`
+	Email    string `firestore:"email"`
+	Password string `firestore:"password"`
+	Address  string `firestore:"address"`
+	Phone    string `firestore:"phone"`
+
+	OrganizationID string `firestore:"organization_id"`
+
+	AllowedCIDRs       []string `firestore:"allowed_cidrs"`
+	NetworkRestriction string   `firestore:"network_restriction"`
+
+	TaxPriceMode string `firestore:"tax_price_mode"`
+	TaxRounding  string `firestore:"tax_rounding"`
+	DiningOption string `firestore:"dining_option"`
+
+	InvoiceRegistrationNumber string `firestore:"invoice_registration_number"`
+
+	Products []Product `firestore:"products"`
+
+	ChargeRules []ChargeRule `firestore:"charge_rules"`
+
+	Workflow string `firestore:"workflow"`
+
+	ReasonCodes []ReasonCode `firestore:"reason_codes"`
+
+	Supervisors []Supervisor `firestore:"supervisors"`
+
+	OverrideManagers []string `firestore:"override_managers"`
+
+	UnconfirmedAction        string `firestore:"unconfirmed_action"`
+	AutoCompleteAfterSeconds int64  `firestore:"auto_complete_after_seconds"`
+	VisitIdleTimeoutSeconds  int64  `firestore:"visit_idle_timeout_seconds"`
+
 	CreatedAt time.Time `firestore:"created_at"`
 	UpdatedAt time.Time `firestore:"updated_at"`
 }
 
+// Product は店舗のメニューの商品です。
+type Product struct {
+	ID          string `firestore:"id"`
+	Name        string `firestore:"name"`
+	Price       int64  `firestore:"price"`
+	Currency    string `firestore:"currency"`
+	Category    string `firestore:"category"`
+	TaxCategory string `firestore:"tax_category"`
+	Active      bool   `firestore:"active"`
+}
+
+func ToSetProducts(products []models.Product) []Product {
+	if products == nil {
+		return nil
+	}
+	setProducts := make([]Product, len(products))
+	for i, p := range products {
+		setProducts[i] = Product{
+			ID:          p.ID,
+			Name:        p.Name,
+			Price:       p.Price,
+			Currency:    string(p.Currency),
+			Category:    p.Category,
+			TaxCategory: string(p.TaxCategory),
+			Active:      p.Active,
+		}
+	}
+	return setProducts
+}
+
+func ToModelProducts(products []Product) []models.Product {
+	if len(products) == 0 {
+		return nil
+	}
+	modelProducts := make([]models.Product, len(products))
+	for i, p := range products {
+		modelProducts[i] = models.Product{
+			ID:          p.ID,
+			Name:        p.Name,
+			Price:       p.Price,
+			Currency:    models.Currency(p.Currency),
+			Category:    p.Category,
+			TaxCategory: models.TaxCategory(p.TaxCategory),
+			Active:      p.Active,
+		}
+	}
+	return modelProducts
+}
+
+// ChargeRule は店舗のチャージの設定です。
+type ChargeRule struct {
+	ID          string `firestore:"id"`
+	Name        string `firestore:"name"`
+	Kind        string `firestore:"kind"`
+	Value       int64  `firestore:"value"`
+	Currency    string `firestore:"currency"`
+	TaxCategory string `firestore:"tax_category"`
+	StartTime   string `firestore:"start_time"`
+	EndTime     string `firestore:"end_time"`
+	Active      bool   `firestore:"active"`
+}
+
+func ToSetChargeRules(rules []models.ChargeRule) []ChargeRule {
+	if rules == nil {
+		return nil
+	}
+	setRules := make([]ChargeRule, len(rules))
+	for i, r := range rules {
+		setRules[i] = ChargeRule{
+			ID:          r.ID,
+			Name:        r.Name,
+			Kind:        string(r.Kind),
+			Value:       r.Value,
+			Currency:    string(r.Currency),
+			TaxCategory: string(r.TaxCategory),
+			StartTime:   r.StartTime,
+			EndTime:     r.EndTime,
+			Active:      r.Active,
+		}
+	}
+	return setRules
+}
+
+func ToModelChargeRules(rules []ChargeRule) []models.ChargeRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	modelRules := make([]models.ChargeRule, len(rules))
+	for i, r := range rules {
+		modelRules[i] = models.ChargeRule{
+			ID:          r.ID,
+			Name:        r.Name,
+			Kind:        models.ChargeKind(r.Kind),
+			Value:       r.Value,
+			Currency:    models.Currency(r.Currency),
+			TaxCategory: models.TaxCategory(r.TaxCategory),
+			StartTime:   r.StartTime,
+			EndTime:     r.EndTime,
+			Active:      r.Active,
+		}
+	}
+	return modelRules
+}
+
+// ReasonCode は店舗の理由コードの設定です。
+type ReasonCode struct {
+	Kind   string `firestore:"kind"`
+	Code   string `firestore:"code"`
+	Label  string `firestore:"label"`
+	Active bool   `firestore:"active"`
+}
+
+func ToSetReasonCodes(codes []models.ReasonCode) []ReasonCode {
+	if codes == nil {
+		return nil
+	}
+	setCodes := make([]ReasonCode, len(codes))
+	for i, c := range codes {
+		setCodes[i] = ReasonCode{
+			Kind:   string(c.Kind),
+			Code:   c.Code,
+			Label:  c.Label,
+			Active: c.Active,
+		}
+	}
+	return setCodes
+}
+
+func ToModelReasonCodes(codes []ReasonCode) []models.ReasonCode {
+	if len(codes) == 0 {
+		return nil
+	}
+	modelCodes := make([]models.ReasonCode, len(codes))
+	for i, c := range codes {
+		modelCodes[i] = models.ReasonCode{
+			Kind:   models.ReasonKind(c.Kind),
+			Code:   c.Code,
+			Label:  c.Label,
+			Active: c.Active,
+		}
+	}
+	return modelCodes
+}
+
+// Supervisor は注文のステータスの強制変更を承認できる店舗の責任者です。PINはハッシュ値のみを保存します。
+type Supervisor struct {
+	Name    string `firestore:"name"`
+	PINHash string `firestore:"pin_hash"`
+}
+
+func ToSetSupervisors(supervisors []models.Supervisor) []Supervisor {
+	if supervisors == nil {
+		return nil
+	}
+	setSupervisors := make([]Supervisor, len(supervisors))
+	for i, s := range supervisors {
+		setSupervisors[i] = Supervisor{
+			Name:    s.Name,
+			PINHash: s.PINHash,
+		}
+	}
+	return setSupervisors
+}
+
+func ToModelSupervisors(supervisors []Supervisor) []models.Supervisor {
+	if len(supervisors) == 0 {
+		return nil
+	}
+	modelSupervisors := make([]models.Supervisor, len(supervisors))
+	for i, s := range supervisors {
+		modelSupervisors[i] = models.Supervisor{
+			Name:    s.Name,
+			PINHash: s.PINHash,
+		}
+	}
+	return modelSupervisors
+}
+
 func ToSetStore(store *models.Store) *Store {
 	return &Store{
-		ID:        store.ID,
-		Name:      store.Name,
-		Email:     store.Email,
-		Password:  store.Password,
-		Address:   store.Address,
-		Phone:     store.Phone,
+		ID:       store.ID,
+		Name:     store.Name,
+		Email:    store.Email,
+		Password: store.Password,
+		Address:  store.Address,
+		Phone:    store.Phone,
+
+		OrganizationID: store.OrganizationID,
+
+		AllowedCIDRs:       store.AllowedCIDRs,
+		NetworkRestriction: string(store.NetworkRestriction),
+
+		TaxPriceMode: string(store.TaxPriceMode),
+		TaxRounding:  string(store.TaxRounding),
+		DiningOption: string(store.DiningOption),
+
+		InvoiceRegistrationNumber: store.InvoiceRegistrationNumber,
+
+		Products: ToSetProducts(store.Products),
+
+		ChargeRules: ToSetChargeRules(store.ChargeRules),
+
+		Workflow: store.Workflow,
+
+		ReasonCodes: ToSetReasonCodes(store.ReasonCodes),
+
+		Supervisors: ToSetSupervisors(store.Supervisors),
+
+		OverrideManagers: store.OverrideManagers,
+
+		UnconfirmedAction:        string(store.UnconfirmedAction),
+		AutoCompleteAfterSeconds: int64(store.AutoCompleteAfter / time.Second),
+		VisitIdleTimeoutSeconds:  int64(store.VisitIdleTimeout / time.Second),
+
 		CreatedAt: store.CreatedAt,
 		UpdatedAt: store.UpdatedAt,
 	}
@@ -58,12 +292,40 @@ func (s *Store) ToUpdate() *Store {
 
 func (s *Store) ToModel() *models.Store {
 	return &models.Store{
-		ID:        s.ID,
-		Name:      s.Name,
-		Email:     s.Email,
-		Password:  s.Password,
-		Address:   s.Address,
-		Phone:     s.Phone,
+		ID:       s.ID,
+		Name:     s.Name,
+		Email:    s.Email,
+		Password: s.Password,
+		Address:  s.Address,
+		Phone:    s.Phone,
+
+		OrganizationID: s.OrganizationID,
+
+		AllowedCIDRs:       s.AllowedCIDRs,
+		NetworkRestriction: models.NetworkRestriction(s.NetworkRestriction),
+
+		TaxPriceMode: models.PriceMode(s.TaxPriceMode),
+		TaxRounding:  models.RoundingMode(s.TaxRounding),
+		DiningOption: models.DiningOption(s.DiningOption),
+
+		InvoiceRegistrationNumber: s.InvoiceRegistrationNumber,
+
+		Products: ToModelProducts(s.Products),
+
+		ChargeRules: ToModelChargeRules(s.ChargeRules),
+
+		Workflow: s.Workflow,
+
+		ReasonCodes: ToModelReasonCodes(s.ReasonCodes),
+
+		Supervisors: ToModelSupervisors(s.Supervisors),
+
+		OverrideManagers: s.OverrideManagers,
+
+		UnconfirmedAction: models.ExpiryAction(s.UnconfirmedAction),
+		AutoCompleteAfter: time.Duration(s.AutoCompleteAfterSeconds) * time.Second,
+		VisitIdleTimeout:  time.Duration(s.VisitIdleTimeoutSeconds) * time.Second,
+
 		CreatedAt: s.CreatedAt,
 		UpdatedAt: s.UpdatedAt,
 	}
@@ -149,6 +411,16 @@ func (r *StoreRepository) UpdateByID(ctx context.Context, id string, store *mode
 		"password": store.Password,
 		"address":  store.Address,
 		"phone":    store.Phone,
+
+		"tax_price_mode": string(store.TaxPriceMode),
+		"tax_rounding":   string(store.TaxRounding),
+		"dining_option":  string(store.DiningOption),
+
+		"invoice_registration_number": store.InvoiceRegistrationNumber,
+
+		"workflow": store.Workflow,
+
+		"unconfirmed_action": string(store.UnconfirmedAction),
 	}
 
 	for path, value := range updateFields {
@@ -157,6 +429,44 @@ func (r *StoreRepository) UpdateByID(ctx context.Context, id string, store *mode
 		}
 	}
 
+	// メニューは nil の場合は更新しない（空のスライスの場合は全て削除）
+	if store.Products != nil {
+		fields = append(fields, firestore.Update{Path: "products", Value: ToSetProducts(store.Products)})
+	}
+
+	// チャージの設定は nil の場合は更新しない（空のスライスの場合は全て削除）
+	if store.ChargeRules != nil {
+		fields = append(fields, firestore.Update{Path: "charge_rules", Value: ToSetChargeRules(store.ChargeRules)})
+	}
+
+	// 理由コードは nil の場合は更新しない（空のスライスの場合は既定の一覧に戻す）
+	if store.ReasonCodes != nil {
+		fields = append(fields, firestore.Update{Path: "reason_codes", Value: ToSetReasonCodes(store.ReasonCodes)})
+	}
+	if store.Supervisors != nil {
+		fields = append(fields, firestore.Update{Path: "supervisors", Value: ToSetSupervisors(store.Supervisors)})
+	}
+	if store.OverrideManagers != nil {
+		fields = append(fields, firestore.Update{Path: "override_managers", Value: store.OverrideManagers})
+	}
+
+	// 自動のステータス遷移までの時間は指定された場合のみ更新（0は既定値のため更新しない）
+	if store.AutoCompleteAfter > 0 {
+		fields = append(fields, firestore.Update{Path: "auto_complete_after_seconds", Value: int64(store.AutoCompleteAfter / time.Second)})
+	}
+	if store.VisitIdleTimeout > 0 {
+		fields = append(fields, firestore.Update{Path: "visit_idle_timeout_seconds", Value: int64(store.VisitIdleTimeout / time.Second)})
+	}
+
+	// ネットワーク制限はモードが指定された場合のみ、許可CIDRとあわせて更新
+	// 許可CIDRを空にする場合もあるため、モードの有無で判定する
+	if store.NetworkRestriction != "" {
+		fields = append(fields,
+			firestore.Update{Path: "network_restriction", Value: string(store.NetworkRestriction)},
+			firestore.Update{Path: "allowed_cidrs", Value: store.AllowedCIDRs},
+		)
+	}
+
 	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Update(ctx, fields)
 	return err
 }
@@ -330,6 +330,7 @@ func TestStoreStruct(t *testing.T) {
 		Password:  "hashedpassword",
 		Address:   "123 Test St",
 		Phone:     "123-456-7890",
+		Workflow:  models.WorkflowCounter,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -343,6 +344,7 @@ func TestStoreStruct(t *testing.T) {
 		assert.Equal(t, testStore.Password, repoStore.Password)
 		assert.Equal(t, testStore.Address, repoStore.Address)
 		assert.Equal(t, testStore.Phone, repoStore.Phone)
+		assert.Equal(t, testStore.Workflow, repoStore.Workflow)
 		assert.Equal(t, testStore.CreatedAt, repoStore.CreatedAt)
 		assert.Equal(t, testStore.UpdatedAt, repoStore.UpdatedAt)
 	})
@@ -367,6 +369,7 @@ func TestStoreStruct(t *testing.T) {
 			Password:  "repopassword",
 			Address:   "456 Repo St",
 			Phone:     "987-654-3210",
+			Workflow:  models.WorkflowPaymentFirst,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
@@ -379,11 +382,40 @@ func TestStoreStruct(t *testing.T) {
 		assert.Equal(t, repoStore.Password, modelStore.Password)
 		assert.Equal(t, repoStore.Address, modelStore.Address)
 		assert.Equal(t, repoStore.Phone, modelStore.Phone)
+		assert.Equal(t, repoStore.Workflow, modelStore.Workflow)
 		assert.Equal(t, repoStore.CreatedAt, modelStore.CreatedAt)
 		assert.Equal(t, repoStore.UpdatedAt, modelStore.UpdatedAt)
 	})
 }
 
+func TestChargeRuleConversions(t *testing.T) {
+	rules := []models.ChargeRule{
+		{ID: "chrule_1", Name: "お通し", Kind: models.ChargePerPerson, Value: 300, Currency: models.CurrencyJPY, StartTime: "17:00", EndTime: "02:00", Active: true},
+		{ID: "chrule_2", Name: "サービス料", Kind: models.ChargePercentage, Value: 10, TaxCategory: models.TaxCategoryStandard},
+	}
+
+	repoRules := ToSetChargeRules(rules)
+	assert.Equal(t, "per_person", repoRules[0].Kind)
+	assert.Equal(t, rules, ToModelChargeRules(repoRules))
+
+	// nil は更新しない、空のスライスは全て削除として区別する
+	assert.Nil(t, ToSetChargeRules(nil))
+	assert.NotNil(t, ToSetChargeRules([]models.ChargeRule{}))
+	assert.Nil(t, ToModelChargeRules(nil))
+}
+
+func TestProductConversions(t *testing.T) {
+	products := []models.Product{
+		{ID: "ramen", Name: "ラーメン", Price: 900, Category: "food", TaxCategory: models.TaxCategoryFood, Active: true},
+		{ID: "beer", Name: "ビール", Price: 600, Currency: models.CurrencyJPY},
+	}
+	assert.Equal(t, products, ToModelProducts(ToSetProducts(products)))
+
+	// nil は更新しない、空のスライスは全て削除として区別する
+	assert.Nil(t, ToSetProducts(nil))
+	assert.NotNil(t, ToSetProducts([]models.Product{}))
+}
+
 // TestStoreRepositoryReadMethod tests specific edge cases for the Read method
 func TestStoreRepositoryReadMethod(t *testing.T) {
 	ctx := context.Background()
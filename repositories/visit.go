@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// VisitRepository は Firestore の visits コレクションを操作するためのリポジトリです。
+type VisitRepository struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewVisitRepository は新しい VisitRepository のインスタンスを生成します。
+func NewVisitRepository(client *firestore.Client) Repository[models.Visit] {
+	if client == nil {
+		return NewMockVisitRepository()
+	}
+	return &VisitRepository{
+		client:     client,
+		collection: "visits",
+	}
+}
+
+type Visit struct {
+	ID         string   `firestore:"id"`
+	StoreID    string   `firestore:"store_id"`
+	SeatID     string   `firestore:"seat_id"`
+	Status     string   `firestore:"status"`
+	PartySize  int      `firestore:"party_size"`
+	SessionIDs []string `firestore:"session_ids"`
+
+	SettlementID string    `firestore:"settlement_id"`
+	OpenedAt     time.Time `firestore:"opened_at"`
+	ClosedAt     time.Time `firestore:"closed_at"`
+	UpdatedAt    time.Time `firestore:"updated_at"`
+}
+
+func ToSetVisit(v *models.Visit) *Visit {
+	return &Visit{
+		ID:         v.ID,
+		StoreID:    v.StoreID,
+		SeatID:     v.SeatID,
+		Status:     string(v.Status),
+		PartySize:  v.PartySize,
+		SessionIDs: v.SessionIDs,
+
+		SettlementID: v.SettlementID,
+		OpenedAt:     v.OpenedAt,
+		ClosedAt:     v.ClosedAt,
+		UpdatedAt:    v.UpdatedAt,
+	}
+}
+
+func (v *Visit) ToModel() *models.Visit {
+	return &models.Visit{
+		ID:         v.ID,
+		StoreID:    v.StoreID,
+		SeatID:     v.SeatID,
+		Status:     models.VisitStatus(v.Status),
+		PartySize:  v.PartySize,
+		SessionIDs: v.SessionIDs,
+
+		SettlementID: v.SettlementID,
+		OpenedAt:     v.OpenedAt,
+		ClosedAt:     v.ClosedAt,
+		UpdatedAt:    v.UpdatedAt,
+	}
+}
+
+// Create は新しい来店を Firestore に作成します。
+func (r *VisitRepository) Create(ctx context.Context, visit *models.Visit) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(visit.ID).Set(ctx, ToSetVisit(visit))
+	return err
+}
+
+// Read はすべての来店を Firestore から読み取ります。
+func (r *VisitRepository) Read(ctx context.Context) ([]*models.Visit, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	visits := make([]*models.Visit, len(docs))
+	for i, doc := range docs {
+		visit := &Visit{}
+		if err := doc.DataTo(visit); err != nil {
+			return nil, err
+		}
+		visits[i] = visit.ToModel()
+	}
+
+	return visits, nil
+}
+
+// FindByID は指定されたIDの来店を Firestore から検索します。
+func (r *VisitRepository) FindByID(ctx context.Context, id string) (*models.Visit, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	visit := &Visit{}
+	if err := doc.DataTo(visit); err != nil {
+		return nil, err
+	}
+
+	return visit.ToModel(), nil
+}
+
+// FindByField は指定されたフィールドと値に一致する来店を Firestore から検索します。
+func (r *VisitRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Visit, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	visits := make([]*models.Visit, len(docs))
+	for i, doc := range docs {
+		visit := &Visit{}
+		if err := doc.DataTo(visit); err != nil {
+			return nil, err
+		}
+		visits[i] = visit.ToModel()
+	}
+
+	return visits, nil
+}
+
+// UpdateByID は指定されたIDの来店を Firestore で更新します。
+func (r *VisitRepository) UpdateByID(ctx context.Context, id string, visit *models.Visit) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Set(ctx, ToSetVisit(visit))
+	return err
+}
+
+// DeleteByID は指定されたIDの来店を Firestore から削除します。
+func (r *VisitRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Delete(ctx)
+	return err
+}
+
+// Count は Firestore に保存されている来店の総数を返します。
+func (r *VisitRepository) Count(ctx context.Context) (int, error) {
+	docs, err := r.client.Collection(GetCollectionName(r.collection)).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// Exists は指定されたIDの来店が Firestore に存在するかどうかを確認します。
+func (r *VisitRepository) Exists(ctx context.Context, id string) (bool, error) {
+	doc, err := r.client.Collection(GetCollectionName(r.collection)).Doc(id).Get(ctx)
+	if err != nil {
+		// ドキュメントが存在しない場合もエラーが返るため、falseを返す
+		return false, nil
+	}
+	return doc.Exists(), nil
+}
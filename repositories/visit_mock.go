@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVisitRepository - 実際のFirestoreの複雑な実装は不要
+type MockVisitRepository struct {
+	mock.Mock
+}
+
+func NewMockVisitRepository() Repository[models.Visit] {
+	return &MockVisitRepository{}
+}
+
+// シンプルな抽象的実装
+func (m *MockVisitRepository) Create(ctx context.Context, visit *models.Visit) error {
+	args := m.Called(ctx, visit)
+	return args.Error(0)
+}
+
+func (m *MockVisitRepository) Read(ctx context.Context) ([]*models.Visit, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return []*models.Visit{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Visit), nil
+}
+
+func (m *MockVisitRepository) FindByID(ctx context.Context, id string) (*models.Visit, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Visit), nil
+}
+
+func (m *MockVisitRepository) FindByField(ctx context.Context, field string, value any) ([]*models.Visit, error) {
+	args := m.Called(ctx, field, value)
+	if args.Get(0) == nil {
+		return []*models.Visit{}, args.Error(1)
+	}
+	return args.Get(0).([]*models.Visit), nil
+}
+
+func (m *MockVisitRepository) UpdateByID(ctx context.Context, id string, visit *models.Visit) error {
+	args := m.Called(ctx, id, visit)
+	return args.Error(0)
+}
+
+func (m *MockVisitRepository) DeleteByID(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockVisitRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int), nil
+}
+
+func (m *MockVisitRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return false, args.Error(1)
+	}
+	return args.Get(0).(bool), nil
+}
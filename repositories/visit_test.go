@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewVisitRepository tests the NewVisitRepository function
+func TestNewVisitRepository(t *testing.T) {
+	t.Run("NewVisitRepository with nil client returns MockVisitRepository", func(t *testing.T) {
+		repo := NewVisitRepository(nil)
+		assert.NotNil(t, repo)
+
+		_, ok := repo.(*MockVisitRepository)
+		assert.True(t, ok, "Should return a MockVisitRepository when client is nil")
+	})
+}
+
+// TestMockVisitRepository tests the MockVisitRepository implementation
+func TestMockVisitRepository(t *testing.T) {
+	ctx := context.Background()
+	testVisit := &models.Visit{ID: "visit_123", StoreID: "store_123", SeatID: "seat_123", Status: models.VisitOpen}
+
+	t.Run("Create", func(t *testing.T) {
+		mockRepo := &MockVisitRepository{}
+		mockRepo.On("Create", mock.Anything, testVisit).Return(nil)
+
+		assert.NoError(t, mockRepo.Create(ctx, testVisit))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("FindByID", func(t *testing.T) {
+		mockRepo := &MockVisitRepository{}
+		mockRepo.On("FindByID", mock.Anything, "visit_123").Return(testVisit, nil)
+
+		visit, err := mockRepo.FindByID(ctx, "visit_123")
+		assert.NoError(t, err)
+		assert.Equal(t, testVisit.ID, visit.ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestVisitStruct tests the Visit struct conversions
+func TestVisitStruct(t *testing.T) {
+	now := time.Now().UTC()
+	testVisit := &models.Visit{
+		ID:           "visit_123",
+		StoreID:      "store_123",
+		SeatID:       "seat_123",
+		Status:       models.VisitClosed,
+		PartySize:    3,
+		SessionIDs:   []string{"session_1", "session_2"},
+		SettlementID: "settle_123",
+		OpenedAt:     now.Add(-time.Hour),
+		ClosedAt:     now,
+		UpdatedAt:    now,
+	}
+
+	repoVisit := ToSetVisit(testVisit)
+	assert.Equal(t, "closed", repoVisit.Status)
+	assert.Equal(t, 3, repoVisit.PartySize)
+	assert.Equal(t, testVisit, repoVisit.ToModel())
+}
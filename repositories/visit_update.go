@@ -0,0 +1,184 @@
+package repositories
+
+// visit_update.go は来店の読み取りから更新までを不可分に行う更新を実装します。
+// 同じ来店への追加注文と来店人数の登録などを同時に行っても互いの変更（注文の一覧や人数）を上書きしないよう、
+// Firestore のトランザクションで読み取りと書き込みを行います。
+// 追加注文は注文の作成と来店への追加を同じトランザクションで行い、片方だけが保存されることはありません。
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// VisitUpdater は来店を読み取ってから更新するまでを不可分に行うストアです。
+type VisitUpdater interface {
+	// Update は id の来店を読み取り、update で変更した来店を保存して返します。
+	// update がエラーを返した場合は保存せずにそのエラーを返します。来店がない場合は codes.NotFound のエラーを返します。
+	// 他の更新と競合した場合、update は最新の来店で再度呼び出されることがあります。
+	Update(ctx context.Context, id string, update func(*models.Visit) error) (*models.Visit, error)
+
+	// PlaceRound は visit.ID の来店を読み取り、place で作成した注文と、注文を追加した来店を保存します。
+	// 来店の記録がない場合は visit を新しく作成します。place がエラーを返した場合は何も保存しません。
+	// 他の更新と競合した場合、place は最新の来店で再度呼び出されることがあるため、呼び出しごとに注文を作成し直してください。
+	PlaceRound(ctx context.Context, visit *models.Visit, place func(*models.Visit) (*models.Session, error)) (*models.Session, error)
+}
+
+// NewVisitUpdater は VisitUpdater を生成します。
+// client が nil の場合は visits と sessions をプロセス内の排他制御で更新するストアを返します。
+func NewVisitUpdater(client *firestore.Client, visits Repository[models.Visit], sessions Repository[models.Session]) VisitUpdater {
+	if client == nil {
+		return NewMemoryVisitUpdater(visits, sessions)
+	}
+	return &FirestoreVisitUpdater{
+		client:     client,
+		collection: "visits",
+		sessions:   "sessions",
+	}
+}
+
+// FirestoreVisitUpdater は Firestore の "visits" コレクションをトランザクションで更新する VisitUpdater です。
+// 追加注文は "sessions" コレクションに作成します。
+type FirestoreVisitUpdater struct {
+	client     *firestore.Client
+	collection string
+	sessions   string
+}
+
+// Update はトランザクション内で来店を読み取り、更新します。
+func (r *FirestoreVisitUpdater) Update(ctx context.Context, id string, update func(*models.Visit) error) (*models.Visit, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(id)
+
+	var visit *models.Visit
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		stored := &Visit{}
+		if err := doc.DataTo(stored); err != nil {
+			return err
+		}
+
+		visit = stored.ToModel()
+		if err := update(visit); err != nil {
+			return err
+		}
+		return tx.Set(ref, ToSetVisit(visit))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return visit, nil
+}
+
+// PlaceRound はトランザクション内で来店を読み取り、注文の作成と来店の更新を行います。
+// 注文は Create で作成するため、同じIDの注文が重ねて作成されることはありません。
+func (r *FirestoreVisitUpdater) PlaceRound(ctx context.Context, visit *models.Visit, place func(*models.Visit) (*models.Session, error)) (*models.Session, error) {
+	ref := r.client.Collection(GetCollectionName(r.collection)).Doc(visit.ID)
+	sessions := r.client.Collection(GetCollectionName(r.sessions))
+
+	var session *models.Session
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// 再試行で前回の変更が残らないよう、呼び出しごとに来店を読み直す
+		initial := *visit
+		current := &initial
+		doc, err := tx.Get(ref)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			stored := &Visit{}
+			if err := doc.DataTo(stored); err != nil {
+				return err
+			}
+			current = stored.ToModel()
+		}
+
+		if session, err = place(current); err != nil {
+			return err
+		}
+		if err := tx.Create(sessions.Doc(session.ID), ToSetSession(session)); err != nil {
+			return err
+		}
+		return tx.Set(ref, ToSetVisit(current))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// MemoryVisitUpdater はプロセス内の排他制御で来店を更新する VisitUpdater です。
+// 単一インスタンスでの運用やテストで使用します。
+type MemoryVisitUpdater struct {
+	mu       sync.Mutex
+	visits   Repository[models.Visit]
+	sessions Repository[models.Session]
+}
+
+func NewMemoryVisitUpdater(visits Repository[models.Visit], sessions Repository[models.Session]) *MemoryVisitUpdater {
+	return &MemoryVisitUpdater{
+		visits:   visits,
+		sessions: sessions,
+	}
+}
+
+// Update は来店を読み取り、更新します。
+func (s *MemoryVisitUpdater) Update(ctx context.Context, id string, update func(*models.Visit) error) (*models.Visit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	visit, err := s.visits.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(visit); err != nil {
+		return nil, err
+	}
+	if err := s.visits.UpdateByID(ctx, id, visit); err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// PlaceRound は来店を読み取り、注文の作成と来店の更新を行います。
+// 来店を保存できなかった場合は、作成した注文を削除します。
+func (s *MemoryVisitUpdater) PlaceRound(ctx context.Context, visit *models.Visit, place func(*models.Visit) (*models.Session, error)) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.visits.FindByID(ctx, visit.ID)
+	created := false
+	if err != nil {
+		if !IsNotFound(err) {
+			return nil, err
+		}
+		current, created = visit, true
+	}
+
+	session, err := place(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	if created {
+		err = s.visits.Create(ctx, current)
+	} else {
+		err = s.visits.UpdateByID(ctx, current.ID, current)
+	}
+	if err != nil {
+		if delErr := s.sessions.DeleteByID(ctx, session.ID); delErr != nil {
+			return nil, fmt.Errorf("%w (failed to delete order: %v)", err, delErr)
+		}
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestNewVisitUpdater tests the NewVisitUpdater function
+func TestNewVisitUpdater(t *testing.T) {
+	t.Run("nil client returns memory updater", func(t *testing.T) {
+		_, ok := NewVisitUpdater(nil, NewMockVisitRepository(), NewMockSessionRepository()).(*MemoryVisitUpdater)
+		assert.True(t, ok, "Should return a MemoryVisitUpdater when client is nil")
+	})
+}
+
+// TestMemoryVisitUpdater_PlaceRound tests that an order and its visit are saved together
+func TestMemoryVisitUpdater_PlaceRound(t *testing.T) {
+	ctx := context.Background()
+	seat := &models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}
+
+	place := func(visit *models.Visit) (*models.Session, error) {
+		session := &models.Session{ID: "session_1", StoreID: "store_1", SeatID: "seat_1", VisitID: visit.ID}
+		return session, visit.AddRound(session)
+	}
+
+	t.Run("create the visit with the first round", func(t *testing.T) {
+		visits := NewMockVisitRepository().(*MockVisitRepository)
+		visits.On("FindByID", ctx, "visit_1").Return(nil, status.Error(codes.NotFound, "not found"))
+		visits.On("Create", ctx, mock.AnythingOfType("*models.Visit")).Return(nil)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		sessions.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+
+		session, err := NewMemoryVisitUpdater(visits, sessions).PlaceRound(ctx, models.NewVisit(seat, time.Now()), place)
+		require.NoError(t, err)
+		visit := visits.Calls[1].Arguments.Get(1).(*models.Visit)
+		assert.Equal(t, []string{session.ID}, visit.SessionIDs)
+	})
+
+	t.Run("closed visit saves nothing", func(t *testing.T) {
+		visits := NewMockVisitRepository().(*MockVisitRepository)
+		visits.On("FindByID", ctx, "visit_1").Return(&models.Visit{ID: "visit_1", Status: models.VisitClosed}, nil)
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+
+		_, err := NewMemoryVisitUpdater(visits, sessions).PlaceRound(ctx, models.NewVisit(seat, time.Now()), place)
+		assert.ErrorIs(t, err, models.ErrVisitClosed)
+		sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		visits.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("order deleted when the visit cannot be saved", func(t *testing.T) {
+		visits := NewMockVisitRepository().(*MockVisitRepository)
+		visits.On("FindByID", ctx, "visit_1").Return(&models.Visit{ID: "visit_1", Status: models.VisitOpen}, nil)
+		visits.On("UpdateByID", ctx, "visit_1", mock.AnythingOfType("*models.Visit")).Return(errors.New("unavailable"))
+		sessions := NewMockSessionRepository().(*MockSessionRepository)
+		sessions.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		sessions.On("DeleteByID", ctx, "session_1").Return(nil)
+
+		_, err := NewMemoryVisitUpdater(visits, sessions).PlaceRound(ctx, models.NewVisit(seat, time.Now()), place)
+		assert.Error(t, err)
+		sessions.AssertExpectations(t)
+	})
+}
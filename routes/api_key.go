@@ -0,0 +1,297 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// apiKeyContextKey は認証済みのAPIキーをコンテキストに保持するキーです。
+const apiKeyContextKey = "api_key"
+
+var ErrManagerRequired = errors.New("manager login is required")
+
+// bearerToken は Authorization ヘッダーからBearerトークンを取得します。
+func bearerToken(c echo.Context) string {
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// skipJWTForAPIKey はBearerトークンがAPIキーの場合にマネージャーJWTの検証を省略します。
+// echo-jwt の Skipper に指定し、APIキーの検証は apiKeyMiddleware で行います。
+func skipJWTForAPIKey(c echo.Context) bool {
+	return models.IsAPIKeyToken(bearerToken(c))
+}
+
+// apiKeyMiddleware は "Authorization: Bearer <APIキー>" を検証し、APIキーをコンテキストに設定するミドルウェアです。
+// BearerトークンがAPIキーでない場合は何もしません（マネージャーJWTとして検証済み）。
+func (p *Client) apiKeyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := bearerToken(c)
+			if !models.IsAPIKeyToken(token) {
+				return next(c)
+			}
+
+			key, err := p.uc.AuthenticateAPIKey(c.Request().Context(), token)
+			if err != nil {
+				if errors.Is(err, models.ErrInvalidAPIKey) || errors.Is(err, models.ErrAPIKeyExpired) || errors.Is(err, models.ErrAPIKeyRevoked) {
+					return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid API key")
+				}
+				return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to authenticate API key: %v", err)
+			}
+
+			c.Set(apiKeyContextKey, key)
+			return next(c)
+		}
+	}
+}
+
+// getAPIKey はAPIキーで認証されたリクエストの場合、そのAPIキーを返します。
+func getAPIKey(c echo.Context) *models.APIKey {
+	key, _ := c.Get(apiKeyContextKey).(*models.APIKey)
+	return key
+}
+
+// getManagerClaims はマネージャーJWT検証ミドルウェアが設定したクレームを取得します。
+func getManagerClaims(c echo.Context) (*models.Claims, error) {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return nil, ErrManagerRequired
+	}
+	claims, ok := token.Claims.(*models.Claims)
+	if !ok {
+		return nil, ErrManagerRequired
+	}
+	return claims, nil
+}
+
+// getActor は操作を行ったスタッフまたは連携先を "manager:<email>" または "apikey:<id>" の形式で返します。
+// 手動割引などの操作記録に使用します。
+func getActor(c echo.Context) string {
+	if key := getAPIKey(c); key != nil {
+		return "apikey:" + key.ID
+	}
+	if claims, err := getManagerClaims(c); err == nil {
+		return "manager:" + claims.Email
+	}
+	return ""
+}
+
+// requirePermission はルートの実行に必要な権限を確認するミドルウェアです。
+// APIキーの場合は付与された権限と、クエリパラメータ store_id が操作可能な店舗かどうかを確認します。
+func requirePermission(permission models.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if key := getAPIKey(c); key != nil {
+				if !key.HasPermission(permission) {
+					return responseHandler(c, http.StatusForbidden, nil, models.ErrPermissionDenied, "API key does not have %s permission", permission)
+				}
+				if storeID := c.QueryParam("store_id"); storeID != "" && !key.AllowsStore(storeID) {
+					return responseHandler(c, http.StatusForbidden, nil, models.ErrPermissionDenied, "API key is not allowed for store_id=%s", storeID)
+				}
+				return next(c)
+			}
+
+			if _, err := getManagerClaims(c); err != nil {
+				return responseHandler(c, http.StatusUnauthorized, nil, err, "Unauthorized")
+			}
+			if !models.HasPermission(models.ManagerPermissions(), permission) {
+				return responseHandler(c, http.StatusForbidden, nil, models.ErrPermissionDenied, "Manager does not have %s permission", permission)
+			}
+			return next(c)
+		}
+	}
+}
+
+// hasPermission は操作者（APIキーまたはマネージャー）が権限を持つかどうかを返します。
+// ルート自体の権限とは別に、リクエストの内容に応じて追加の権限を確認する場合に使用します。
+func hasPermission(c echo.Context, permission models.Permission) bool {
+	if key := getAPIKey(c); key != nil {
+		return key.HasPermission(permission)
+	}
+	if _, err := getManagerClaims(c); err != nil {
+		return false
+	}
+	return models.HasPermission(models.ManagerPermissions(), permission)
+}
+
+// requireManager はマネージャーJWTでログインしている場合のみ許可するミドルウェアです。
+// APIキーの発行・無効化など、APIキー自身には許可しない操作に使用します。
+func requireManager() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if getAPIKey(c) != nil {
+				return responseHandler(c, http.StatusForbidden, nil, ErrManagerRequired, "This operation requires manager login")
+			}
+			if _, err := getManagerClaims(c); err != nil {
+				return responseHandler(c, http.StatusUnauthorized, nil, err, "Unauthorized")
+			}
+			return next(c)
+		}
+	}
+}
+
+// authorizeStore はリクエストボディ等で指定された店舗を操作できるかどうかを確認します。
+// マネージャーJWTの場合は常に許可します。
+func authorizeStore(c echo.Context, storeID string) error {
+	if key := getAPIKey(c); key != nil && !key.AllowsStore(storeID) {
+		return models.ErrPermissionDenied
+	}
+	return nil
+}
+
+type RequestAPIKey struct {
+	Name        string             `json:"name"`
+	Scope       models.APIKeyScope `json:"scope"`
+	StoreID     string             `json:"store_id"`
+	Permissions []string           `json:"permissions"`
+	ExpiresAt   *time.Time         `json:"expires_at"`
+}
+
+type ResponseAPIKey struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Prefix      string              `json:"prefix"`
+	Scope       models.APIKeyScope  `json:"scope"`
+	StoreID     string              `json:"store_id,omitempty"`
+	Permissions []models.Permission `json:"permissions"`
+	ExpiresAt   *time.Time          `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time          `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time          `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	// Key は発行時のレスポンスにのみ含まれます。
+	Key string `json:"key,omitempty"`
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// NewResponseAPIKey は、models.APIKeyをResponseAPIKeyに変換します。
+// キーのハッシュ値は含まれません。
+func NewResponseAPIKey(key *models.APIKey) *ResponseAPIKey {
+	return &ResponseAPIKey{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scope:       key.Scope,
+		StoreID:     key.StoreID,
+		Permissions: key.Permissions,
+		ExpiresAt:   optionalTime(key.ExpiresAt),
+		LastUsedAt:  optionalTime(key.LastUsedAt),
+		RevokedAt:   optionalTime(key.RevokedAt),
+		CreatedAt:   key.CreatedAt,
+	}
+}
+
+// isAPIKeyValidationError はAPIキーの入力値に起因するエラーかどうかを返します。
+func isAPIKeyValidationError(err error) bool {
+	for _, target := range []error{
+		models.ErrAPIKeyNameRequired,
+		models.ErrInvalidAPIKeyScope,
+		models.ErrPermissionsRequired,
+		models.ErrInvalidPermission,
+		models.ErrStoreIDRequired,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKey は、外部連携用のAPIキーを発行するエンドポイントです。
+// キー本体はこのレスポンスでのみ返されるため、連携先に安全に保管してもらう必要があります。
+func (p *Client) CreateAPIKey(c echo.Context) error {
+	claims, err := getManagerClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Unauthorized")
+	}
+
+	req := &RequestAPIKey{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind api key data: %v", err)
+	}
+
+	permissions, err := models.ParsePermissions(req.Permissions)
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.UTC()
+		if !expiresAt.After(time.Now()) {
+			return responseHandler(c, http.StatusBadRequest, nil, models.ErrAPIKeyExpired, "expires_at must be in the future")
+		}
+	}
+
+	key, raw, err := p.uc.CreateAPIKey(c.Request().Context(), claims.Email, req.Name, req.Scope, req.StoreID, permissions, expiresAt)
+	if err != nil {
+		if isAPIKeyValidationError(err) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+		}
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to create api key: %v", err)
+	}
+
+	res := NewResponseAPIKey(key)
+	res.Key = raw
+	return responseHandler(c, http.StatusOK, res, nil, "API key created: %s", key.Prefix)
+}
+
+// ListAPIKeys は、ログイン中のマネージャーが発行したAPIキーの一覧を返すエンドポイントです。
+func (p *Client) ListAPIKeys(c echo.Context) error {
+	claims, err := getManagerClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Unauthorized")
+	}
+
+	keys, err := p.uc.ListAPIKeys(c.Request().Context(), claims.Email)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to list api keys: %v", err)
+	}
+
+	res := make([]*ResponseAPIKey, len(keys))
+	for i, key := range keys {
+		res[i] = NewResponseAPIKey(key)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "")
+}
+
+// RevokeAPIKey は、APIキーを無効化するエンドポイントです。
+func (p *Client) RevokeAPIKey(c echo.Context) error {
+	claims, err := getManagerClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Unauthorized")
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrAPIKeyIDRequired, "API key ID is required")
+	}
+
+	key, err := p.uc.RevokeAPIKey(c.Request().Context(), claims.Email, id)
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyNotFound) {
+			return responseHandler(c, http.StatusNotFound, nil, err, "API key not found: %s", id)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to revoke api key: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseAPIKey(key), nil, "API key revoked: %s", key.Prefix)
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"backend/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipJWTForAPIKey(t *testing.T) {
+	e := echo.New()
+	request := func(auth string) echo.Context {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/private/manager/store", nil)
+		req.Header.Set(echo.HeaderAuthorization, auth)
+		return e.NewContext(req, httptest.NewRecorder())
+	}
+
+	assert.True(t, skipJWTForAPIKey(request("Bearer "+models.APIKeyTokenPrefix+"abc")))
+	assert.False(t, skipJWTForAPIKey(request("Bearer eyJhbGciOiJIUzI1NiJ9.e30.sig")), "マネージャーJWTは検証する")
+	assert.False(t, skipJWTForAPIKey(request(models.APIKeyTokenPrefix+"abc")), "Bearerスキーム以外は対象外")
+}
+
+func TestRequirePermission(t *testing.T) {
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	handler := requirePermission(models.PermissionOrdersRead)(ok)
+
+	request := func(target string, setup func(c echo.Context)) int {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+		setup(c)
+		assert.NoError(t, handler(c))
+		return rec.Code
+	}
+
+	storeKey := &models.APIKey{Scope: models.APIKeyScopeStore, StoreID: "store_1", Permissions: []models.Permission{models.PermissionOrdersRead}}
+	writeOnlyKey := &models.APIKey{Scope: models.APIKeyScopeOrganization, Permissions: []models.Permission{models.PermissionOrdersWrite}}
+
+	assert.Equal(t, http.StatusOK, request("/?store_id=store_1", func(c echo.Context) { c.Set(apiKeyContextKey, storeKey) }))
+	assert.Equal(t, http.StatusForbidden, request("/?store_id=store_2", func(c echo.Context) { c.Set(apiKeyContextKey, storeKey) }), "他店舗は操作できない")
+	assert.Equal(t, http.StatusForbidden, request("/", func(c echo.Context) { c.Set(apiKeyContextKey, writeOnlyKey) }), "権限がない")
+	assert.Equal(t, http.StatusOK, request("/", func(c echo.Context) {
+		c.Set("user", &jwt.Token{Claims: &models.Claims{Email: "manager@example.com"}})
+	}), "マネージャーは全ての権限を持つ")
+	assert.Equal(t, http.StatusUnauthorized, request("/", func(c echo.Context) {}))
+}
+
+func TestRequireManager(t *testing.T) {
+	handler := requireManager()(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	c.Set(apiKeyContextKey, &models.APIKey{Scope: models.APIKeyScopeOrganization})
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code, "APIキーではAPIキーを管理できない")
+}
+
+func TestGetActor(t *testing.T) {
+	e := echo.New()
+	newContext := func() echo.Context {
+		return e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	}
+
+	c := newContext()
+	c.Set(apiKeyContextKey, &models.APIKey{ID: "key_1"})
+	assert.Equal(t, "apikey:key_1", getActor(c))
+
+	c = newContext()
+	c.Set("user", &jwt.Token{Claims: &models.Claims{Email: "manager@example.com"}})
+	assert.Equal(t, "manager:manager@example.com", getActor(c))
+
+	assert.Empty(t, getActor(newContext()))
+}
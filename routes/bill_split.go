@@ -0,0 +1,205 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestSplitPayer の amount は補助単位（円、セント等）の整数で、method が "amounts" の場合のみ使用します。
+// line_ids は method が "lines" の場合に、商品の明細行ID（line_id）またはチャージIDを指定します。
+type RequestSplitPayer struct {
+	Label   string   `json:"label"`
+	LineIDs []string `json:"line_ids"`
+	Amount  int64    `json:"amount"`
+}
+
+// RequestBillSplit の method は "even"（均等）、"lines"（明細ごと）、"amounts"（金額指定）のいずれかです。
+// "even" の場合は parts に人数を指定します。
+type RequestBillSplit struct {
+	StoreID  string              `json:"store_id"`
+	VisitID  string              `json:"visit_id"`
+	Method   models.SplitMethod  `json:"method"`
+	Parts    int                 `json:"parts"`
+	Currency string              `json:"currency"`
+	Payers   []RequestSplitPayer `json:"payers"`
+}
+
+// ToModel は、リクエストをmodels.SplitRequestに変換します。
+func (r *RequestBillSplit) ToModel() (models.SplitRequest, error) {
+	currency, err := models.ParseCurrency(r.Currency)
+	if err != nil {
+		return models.SplitRequest{}, err
+	}
+
+	req := models.SplitRequest{Method: r.Method, Parts: r.Parts}
+	for _, payer := range r.Payers {
+		req.Payers = append(req.Payers, models.SplitPayer{
+			Label:   payer.Label,
+			LineIDs: payer.LineIDs,
+			Amount:  models.NewMoney(payer.Amount, currency),
+		})
+	}
+	return req, nil
+}
+
+type RequestSplitRemainder struct {
+	StoreID string `json:"store_id"`
+	Label   string `json:"label"`
+}
+
+type RequestPaySubBill struct {
+	StoreID    string              `json:"store_id"`
+	Method     models.TenderMethod `json:"method"`
+	PaymentRef string              `json:"payment_ref"`
+}
+
+type ResponseBillSplit struct {
+	ID             string                 `json:"id"`
+	StoreID        string                 `json:"store_id"`
+	SeatID         string                 `json:"seat_id"`
+	VisitID        string                 `json:"visit_id"`
+	SessionIDs     []string               `json:"session_ids"`
+	Method         models.SplitMethod     `json:"method"`
+	Status         models.BillSplitStatus `json:"status"`
+	Total          models.Money           `json:"total"`
+	Taxes          []models.TaxLine       `json:"taxes"`
+	SubBills       []models.SubBill       `json:"sub_bills"`
+	Remainder      models.Money           `json:"remainder"`
+	RemainderTaxes []models.TaxLine       `json:"remainder_taxes"`
+	Outstanding    models.Money           `json:"outstanding"`
+	SettledAt      *time.Time             `json:"settled_at,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// NewResponseBillSplit は、models.BillSplitをResponseBillSplitに変換します。
+func NewResponseBillSplit(split *models.BillSplit) *ResponseBillSplit {
+	return &ResponseBillSplit{
+		ID:             split.ID,
+		StoreID:        split.StoreID,
+		SeatID:         split.SeatID,
+		VisitID:        split.VisitID,
+		SessionIDs:     split.SessionIDs,
+		Method:         split.Method,
+		Status:         split.Status,
+		Total:          split.Total,
+		Taxes:          split.Taxes,
+		SubBills:       split.SubBills,
+		Remainder:      split.Remainder,
+		RemainderTaxes: split.RemainderTaxes,
+		Outstanding:    split.Outstanding(),
+		SettledAt:      optionalTime(split.SettledAt),
+		CreatedAt:      split.CreatedAt,
+		UpdatedAt:      split.UpdatedAt,
+	}
+}
+
+// billSplitErrorStatus は割り勘の操作で発生したエラーに対応するHTTPステータスを返します。
+func billSplitErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrBillSplitNotFound), errors.Is(err, models.ErrSubBillNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrBillSplitInProgress), errors.Is(err, models.ErrBillSplitClosed),
+		errors.Is(err, models.ErrSubBillAlreadyPaid), errors.Is(err, models.ErrNoRemainder),
+		errors.Is(err, models.ErrBusinessDayClosed), errors.Is(err, models.ErrBillSplitStale):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrCheckEmpty), errors.Is(err, models.ErrInvalidSplitMethod),
+		errors.Is(err, models.ErrInvalidSplitParts), errors.Is(err, models.ErrSplitPayersRequired),
+		errors.Is(err, models.ErrSplitLineNotFound), errors.Is(err, models.ErrSplitLineAssigned),
+		errors.Is(err, models.ErrInvalidSplitAmount), errors.Is(err, models.ErrSplitAmountExceedsTotal),
+		errors.Is(err, models.ErrCurrencyMismatch), errors.Is(err, models.ErrUnsupportedCurrency),
+		errors.Is(err, models.ErrInvalidTenderMethod):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateBillSplit は、来店の会計を複数の伝票に分割するエンドポイントです。
+// 支払い前の割り勘がすでにある場合は、無効にして分割をやり直します。
+func (p *Client) CreateBillSplit(c echo.Context) error {
+	req := &RequestBillSplit{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind bill split data: %v", err)
+	}
+	if req.StoreID == "" || req.VisitID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and visit_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	splitReq, err := req.ToModel()
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+	split, err := p.uc.CreateBillSplit(c.Request().Context(), req.StoreID, req.VisitID, splitReq)
+	if err != nil {
+		return responseHandler(c, billSplitErrorStatus(err), nil, err, "Failed to split bill: %v", err)
+	}
+
+	return responseHandler(c, http.StatusCreated, NewResponseBillSplit(split), nil, "Bill split created successfully")
+}
+
+// GetBillSplit は、割り勘の伝票と支払い状況を取得するエンドポイントです。
+func (p *Client) GetBillSplit(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	split, err := p.uc.GetBillSplit(c.Request().Context(), storeID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, billSplitErrorStatus(err), nil, err, "Failed to get bill split: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseBillSplit(split), nil, "Bill split retrieved successfully")
+}
+
+// AssignSplitRemainder は、割り勘の未割り当ての残額を新しい伝票として割り当てるエンドポイントです。
+func (p *Client) AssignSplitRemainder(c echo.Context) error {
+	req := &RequestSplitRemainder{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind remainder data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	split, err := p.uc.AssignSplitRemainder(c.Request().Context(), req.StoreID, c.Param("id"), req.Label)
+	if err != nil {
+		return responseHandler(c, billSplitErrorStatus(err), nil, err, "Failed to assign remainder: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseBillSplit(split), nil, "Remainder assigned successfully")
+}
+
+// PaySubBill は、割り勘の伝票の支払いを記録するエンドポイントです。
+// 全ての伝票の支払いが完了すると、割り勘は精算済みになり、来店の注文は支払い済みになります。
+// 割り勘の作成後に追加注文などで会計が変わった場合は 409 を返します。
+func (p *Client) PaySubBill(c echo.Context) error {
+	req := &RequestPaySubBill{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind payment data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	split, err := p.uc.PaySubBill(c.Request().Context(), req.StoreID, c.Param("id"), c.Param("bill_id"), req.Method, req.PaymentRef, getActor(c))
+	if err != nil {
+		return responseHandler(c, billSplitErrorStatus(err), nil, err, "Failed to record payment: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseBillSplit(split), nil, "Payment recorded successfully")
+}
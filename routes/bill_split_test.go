@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBillSplitToModel(t *testing.T) {
+	req := &RequestBillSplit{
+		Method:   models.SplitByAmounts,
+		Currency: "usd",
+		Payers:   []RequestSplitPayer{{Label: "A", Amount: 1250}},
+	}
+	split, err := req.ToModel()
+	require.NoError(t, err)
+	assert.Equal(t, models.NewMoney(1250, models.CurrencyUSD), split.Payers[0].Amount)
+
+	req.Currency = "xxx"
+	_, err = req.ToModel()
+	assert.ErrorIs(t, err, models.ErrUnsupportedCurrency)
+}
+
+func TestBillSplitErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, billSplitErrorStatus(models.ErrSubBillNotFound))
+	assert.Equal(t, http.StatusConflict, billSplitErrorStatus(models.ErrBillSplitInProgress))
+	assert.Equal(t, http.StatusConflict, billSplitErrorStatus(models.ErrBillSplitStale))
+	assert.Equal(t, http.StatusBadRequest, billSplitErrorStatus(fmt.Errorf("%w: line_1", models.ErrSplitLineNotFound)))
+	assert.Equal(t, http.StatusBadRequest, billSplitErrorStatus(fmt.Errorf("%w: \"\"", models.ErrInvalidTenderMethod)))
+	assert.Equal(t, http.StatusInternalServerError, billSplitErrorStatus(errors.New("firestore unavailable")))
+}
@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestStoreCharges の rules は店舗のチャージ設定の全件で、既存の設定を置き換えます。空の配列の場合は全て削除します。
+// kind は "per_person"（人数分）、"per_seat"（座席ごと）、"percentage"（割合）のいずれかです。
+type RequestStoreCharges struct {
+	StoreID string              `json:"store_id"`
+	Rules   []models.ChargeRule `json:"rules"`
+}
+
+type RequestWaiveCharge struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+// chargeErrorStatus はチャージの設定・免除で発生したエラーに対応するHTTPステータスを返します。
+func chargeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrChargeNotFound), errors.Is(err, models.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrChargeAlreadyWaived), errors.Is(err, models.ErrChargeNotAllowed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrChargeNameRequired), errors.Is(err, models.ErrInvalidChargeKind),
+		errors.Is(err, models.ErrInvalidChargeValue), errors.Is(err, models.ErrInvalidChargeTime),
+		errors.Is(err, models.ErrInvalidTaxCategory), errors.Is(err, models.ErrUnsupportedCurrency),
+		errors.Is(err, models.ErrChargeWaiveReasonRequired):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UpdateStoreCharges は、店舗のチャージ（お通し代、席料、サービス料）の設定を更新するためのエンドポイントです。
+func (p *Client) UpdateStoreCharges(c echo.Context) error {
+	req := &RequestStoreCharges{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store charge data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreChargeRules(c.Request().Context(), req.StoreID, req.Rules)
+	if err != nil {
+		return responseHandler(c, chargeErrorStatus(err), nil, err, "Failed to update store charges: %v", err)
+	}
+
+	rules := store.ChargeRules
+	if rules == nil {
+		rules = []models.ChargeRule{}
+	}
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id": store.ID,
+		"rules":    rules,
+	}, nil, "Store charges updated successfully")
+}
+
+// WaiveCharge は、スタッフが理由を添えて注文のチャージを免除するためのエンドポイントです。
+// 免除したスタッフ（またはAPIキー）と理由はチャージとあわせて記録されます。
+func (p *Client) WaiveCharge(c echo.Context) error {
+	req := &RequestWaiveCharge{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind charge data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, err := p.uc.WaiveCharge(c.Request().Context(), req.StoreID, req.OrderID, c.Param("id"), req.Reason, getActor(c))
+	if err != nil {
+		return responseHandler(c, chargeErrorStatus(err), nil, err, "Failed to waive charge: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Charge waived successfully")
+}
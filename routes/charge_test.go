@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChargeErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, chargeErrorStatus(models.ErrChargeNotFound))
+	assert.Equal(t, http.StatusConflict, chargeErrorStatus(models.ErrChargeAlreadyWaived))
+	assert.Equal(t, http.StatusBadRequest, chargeErrorStatus(fmt.Errorf("%w (rules[0])", models.ErrInvalidChargeTime)))
+	assert.Equal(t, http.StatusBadRequest, chargeErrorStatus(models.ErrChargeWaiveReasonRequired))
+	assert.Equal(t, http.StatusInternalServerError, chargeErrorStatus(errors.New("firestore unavailable")))
+}
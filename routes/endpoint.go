@@ -2,9 +2,9 @@ package routes
 
 import (
 	"backend/models"
+	"backend/repositories"
 	"backend/usecases"
 	"context"
-	"fmt"
 	"net/http"
 	"os"
 
@@ -13,11 +13,13 @@ import (
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog/log"
 )
 
 type Client struct {
-	isTest bool
-	uc     *usecases.UseCase
+	isTest     bool
+	uc         *usecases.UseCase
+	rateLimits repositories.RateLimitStore
 }
 
 func NewClient(isTest bool) *Client {
@@ -28,9 +30,26 @@ func NewClient(isTest bool) *Client {
 		panic(err)
 	}
 
+	// 複数インスタンスで制限値を共有するため、既定ではFirestoreにカウンタを保持する
+	// RATE_LIMIT_BACKEND=memory の場合はインスタンスごとのメモリで保持する
+	rateLimitDB := db
+	if os.Getenv("RATE_LIMIT_BACKEND") == "memory" {
+		rateLimitDB = nil
+	}
+
+	// STRIPE_SECRET_KEY が設定されている場合は Stripe で決済する
+	// テストモードでは、未設定の場合にプロセス内のフェイクで決済する
+	uc := usecases.New(db)
+	if secretKey := os.Getenv("STRIPE_SECRET_KEY"); secretKey != "" {
+		uc.SetPaymentProvider(repositories.NewStripePaymentProvider(secretKey, os.Getenv("STRIPE_WEBHOOK_SECRET"), nil))
+	} else if isTest {
+		uc.SetPaymentProvider(repositories.NewFakePaymentProvider(os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET")))
+	}
+
 	return &Client{
-		isTest: isTest,
-		uc:     usecases.New(db),
+		isTest:     isTest,
+		uc:         uc,
+		rateLimits: repositories.NewRateLimitStore(rateLimitDB),
 	}
 }
 
@@ -73,7 +92,9 @@ func setmiddleware(isTest bool) echo.MiddlewareFunc {
 // Endpoint sets up the routes for the application.
 func Endpoint(e *echo.Echo, isTest bool) {
 	p := NewClient(isTest)
+	p.startExpirySweeper(context.Background())
 
+	configureIPExtractor(e)
 	e.Use(setmiddleware(isTest))
 
 	// version 1
@@ -81,9 +102,18 @@ func Endpoint(e *echo.Echo, isTest bool) {
 
 	// public routes
 	v1Public := v1.Group("/public")
-	v1Public.GET("/health", publicHealth)
-	v1Public.POST("/signup", p.Signup)
-	v1Public.POST("/signin", p.Signin)
+	publicRateLimit := loadRateLimitPolicy("RATE_LIMIT_PUBLIC", defaultPublicRateLimit)
+	publicByIP := rateLimitMiddleware(p.rateLimits, "public", publicRateLimit, rateLimitByIP)
+	v1Public.GET("/health", publicHealth, publicByIP)
+	v1Public.POST("/signup", p.Signup, publicByIP)
+	v1Public.POST("/signin", p.Signin, publicByIP)
+	// QRコード読み込み時にセッションを開始
+	// 店内の客は店舗のIPを共有するため、店舗ネットワーク（許可CIDR）からの読み込みはIP単位で制限しない
+	v1Public.GET("/session", p.StartSession, rateLimitMiddleware(p.rateLimits, "public", publicRateLimit, p.rateLimitByIPOutsideStoreNetwork))
+
+	// 決済代行会社からの Webhook（署名で検証するため認証・レート制限の対象外）
+	v1Webhook := v1.Group("/webhook")
+	v1Webhook.POST("/payment", p.PaymentWebhook)
 
 	v1Private := v1.Group("/private")
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -103,9 +133,10 @@ func publicHealth(c echo.Context) error {
 
 func privateHealth(c echo.Context) error {
 	// コンテキストからユーザ情報を取得
-	user := c.Get("user").(*jwt.Token)
-	claims := user.Claims.(*models.Claims)
-	fmt.Println(claims)
+	// APIキーで認証された場合はJWTのユーザ情報がない
+	if user, ok := c.Get("user").(*jwt.Token); ok {
+		log.Debug().Msgf("private health, claims=%v", user.Claims)
+	}
 
 	return responseHandler(c, http.StatusOK, echo.Map{"message": "OK"}, nil, "success, private health")
 }
@@ -120,17 +151,123 @@ func (p *Client) handleManager(private *echo.Group, key string) {
 			return new(models.Claims)
 		},
 		SigningKey: []byte(key),
+		// 外部連携のAPIキーは apiKeyMiddleware で検証する
+		Skipper: skipJWTForAPIKey,
 	}))
+	manager.Use(p.apiKeyMiddleware())
+	manager.Use(rateLimitMiddleware(p.rateLimits, "manager", loadRateLimitPolicy("RATE_LIMIT_MANAGER", defaultManagerRateLimit), rateLimitByIP, rateLimitByAccount))
 	// -H "Authorization: Bearer <token>"を付与してリクエスト
+	// 外部連携の場合は -H "Authorization: Bearer <APIキー>" を付与し、ルートごとの権限を確認する
 	manager.GET("/health", privateHealth)
 	// 店舗の追加
 	// - 登録店舗情報を取得
-	manager.GET("/store", p.GetAllStores)
+	manager.GET("/store", p.GetAllStores, requirePermission(models.PermissionStoresRead))
 	// - 店舗情報を登録
-	manager.POST("/store", p.RegisterStore)
+	manager.POST("/store", p.RegisterStore, requirePermission(models.PermissionStoresWrite))
+	// - 座席を登録
+	manager.POST("/store/seat", p.RegisterSeat, requirePermission(models.PermissionSeatsWrite))
 	// - QRコードを発行
-	manager.GET("/store/qr", p.IssueSeatQRForStore)
-
+	manager.GET("/store/qr", p.IssueSeatQRForStore, requirePermission(models.PermissionSeatsRead))
+	// - QRコードを再発行し、印刷済みのQRコードを無効化
+	manager.POST("/store/qr/rotate", p.RotateSeatQR, requirePermission(models.PermissionSeatsWrite))
+	// - 会計・退席した座席を閉じ、発行済みのセッションを失効
+	manager.POST("/store/seat/close", p.CloseSeat, requirePermission(models.PermissionSeatsWrite))
+	// - 座席に接続中の端末を取得
+	manager.GET("/store/seat/devices", p.ListSeatDevices, requirePermission(models.PermissionSeatsRead))
+	// - 店舗ネットワーク外からの注文制限を設定
+	manager.PUT("/store/network", p.UpdateStoreNetwork, requirePermission(models.PermissionStoresWrite))
+	// - 消費税の計算設定（税込・税抜、端数処理）を更新
+	manager.PUT("/store/tax", p.UpdateStoreTax, requirePermission(models.PermissionStoresWrite))
+	// - メニュー（商品カタログ）を設定
+	manager.PUT("/store/products", p.UpdateStoreProducts, requirePermission(models.PermissionStoresWrite))
+	// - お通し代・席料・サービス料などのチャージを設定
+	manager.PUT("/store/charges", p.UpdateStoreCharges, requirePermission(models.PermissionStoresWrite))
+	// - 注文のワークフロー（状態遷移の定義）を図（Mermaid・Graphviz）またはJSONで取得
+	manager.GET("/store/workflow", p.GetWorkflow, requirePermission(models.PermissionOrdersRead))
+	// - 注文のワークフロー（状態遷移の定義）を選択
+	manager.PUT("/store/workflow", p.UpdateStoreWorkflow, requirePermission(models.PermissionStoresWrite))
+	// - 期限切れの注文の自動キャンセル・提供済みの注文の自動完了・来店の自動終了を設定
+	manager.PUT("/store/expiry", p.UpdateStoreExpiry, requirePermission(models.PermissionStoresWrite))
+	// - ステータスの強制変更を承認できる責任者とPINを設定
+	manager.PUT("/store/supervisors", p.UpdateStoreSupervisors, requireManager())
+	// - キャンセル・辞退・保留・明細の取り消しの理由コードを取得
+	manager.GET("/store/reasons", p.ListReasonCodes, requirePermission(models.PermissionStoresRead))
+	// - キャンセル・辞退・保留・明細の取り消しの理由コードを設定
+	manager.PUT("/store/reasons", p.UpdateStoreReasonCodes, requirePermission(models.PermissionStoresWrite))
+	// - 適格請求書発行事業者の登録番号を設定
+	manager.PUT("/store/invoice", p.UpdateStoreInvoice, requirePermission(models.PermissionStoresWrite))
+	// - 会計済みの注文の領収書を発行（?format=json|text|pdf）
+	manager.POST("/store/receipt", p.IssueReceipt, requirePermission(models.PermissionOrdersWrite))
+	// - 発行済みの領収書を取得
+	manager.GET("/store/receipt/:id", p.GetReceipt, requirePermission(models.PermissionOrdersRead))
+	// - 領収書を再発行
+	manager.POST("/store/receipt/:id/reissue", p.ReissueReceipt, requirePermission(models.PermissionOrdersWrite))
+	// - プロモーションコードを登録
+	manager.POST("/store/promotion", p.CreatePromotion, requirePermission(models.PermissionStoresWrite))
+	// - プロモーションの一覧を取得
+	manager.GET("/store/promotion", p.ListPromotions, requirePermission(models.PermissionStoresRead))
+	// - プロモーションを停止
+	manager.DELETE("/store/promotion/:id", p.DeactivatePromotion, requirePermission(models.PermissionStoresWrite))
+	// - 注文をステータスの遷移のタイムラインとあわせて取得
+	manager.GET("/store/order", p.GetOrder, requirePermission(models.PermissionOrdersRead))
+	// - 注文のステータスを更新（操作者と理由を履歴に記録。キャンセル・辞退・保留は理由コードが必須）
+	manager.POST("/store/order/status", p.UpdateOrderStatus, requirePermission(models.PermissionOrdersWrite))
+	// - お客様に確認した店内飲食・持ち帰りの区分に注文を変更（税額を再計算）
+	manager.POST("/store/order/dining", p.ChangeOrderDiningOption, requirePermission(models.PermissionOrdersWrite))
+	// - 責任者の承認（強制変更の権限または責任者のPIN）で、遷移ルールによらず注文のステータスを強制変更
+	manager.POST("/store/order/override", p.OverrideOrderStatus, requirePermission(models.PermissionOrdersWrite))
+	// - ステータスの強制変更の記録を取得（監査用）
+	manager.GET("/store/order/override", p.ListStatusOverrides, requirePermission(models.PermissionOrdersRead))
+	// - 明細のステータスを更新（調理の開始・提供。注文のステータスは明細から導出）
+	manager.POST("/store/order/line/status", p.UpdateOrderLineStatus, requirePermission(models.PermissionOrdersWrite))
+	// - 理由コードを添えて調理前の明細を取り消し
+	manager.POST("/store/order/line/void", p.VoidOrderLine, requirePermission(models.PermissionOrdersWrite))
+	// - 理由を添えて調理前の明細の数量を変更
+	manager.POST("/store/order/line/quantity", p.AdjustOrderLineQuantity, requirePermission(models.PermissionOrdersWrite))
+	// - 理由を添えて注文に手動割引を適用
+	manager.POST("/store/order/discount", p.ApplyManualDiscount, requirePermission(models.PermissionOrdersWrite))
+	// - 注文に適用した割引を取り消し
+	manager.DELETE("/store/order/discount/:id", p.RemoveDiscount, requirePermission(models.PermissionOrdersWrite))
+	// - 理由を添えて注文のチャージを免除
+	manager.POST("/store/order/charge/:id/waive", p.WaiveCharge, requirePermission(models.PermissionOrdersWrite))
+	// - 注文のオンライン決済を開始
+	manager.POST("/store/order/payment", p.StartOrderPayment, requirePermission(models.PermissionOrdersWrite))
+	// - 決済の状態を取得
+	manager.GET("/store/order/payment/:id", p.GetPayment, requirePermission(models.PermissionOrdersRead))
+	// - オーソリ済みの決済の売上を確定
+	manager.POST("/store/order/payment/:id/capture", p.CapturePayment, requirePermission(models.PermissionOrdersWrite))
+	// - 注文の返金を記録
+	manager.POST("/store/order/refund", p.RefundOrder, requirePermission(models.PermissionOrdersWrite))
+	// - 注文の返金の履歴を取得
+	manager.GET("/store/order/refund", p.ListOrderRefunds, requirePermission(models.PermissionOrdersRead))
+	// - 営業日のレジ締め（Zレポートの作成と現金の過不足の記録）
+	manager.POST("/store/register/close", p.CloseRegister, requireManager())
+	// - 営業日のレジ締めの記録（Zレポート）を取得
+	manager.GET("/store/register/close", p.GetRegisterClose, requirePermission(models.PermissionOrdersRead))
+	// - 期間中のキャンセル・辞退・保留・明細の取り消しを理由コードごとに集計
+	manager.GET("/store/analytics/reasons", p.GetReasonReport, requirePermission(models.PermissionOrdersRead))
+	// - 来店の現在の会計（追加注文ごとの注文と合計金額の途中経過）を取得
+	manager.GET("/store/visit", p.GetVisitCheck, requirePermission(models.PermissionOrdersRead))
+	// - 来店人数を登録（人数分のチャージを再計算）
+	manager.POST("/store/visit/party", p.SetVisitPartySize, requirePermission(models.PermissionOrdersWrite))
+	// - 来店の会計をレジで精算（現金・カード・QR決済・ギフトカードの併用）
+	manager.POST("/store/visit/settle", p.SettleVisit, requirePermission(models.PermissionOrdersWrite))
+	// - 来店の精算記録を取得
+	manager.GET("/store/visit/settle", p.GetVisitSettlement, requirePermission(models.PermissionOrdersRead))
+	// - 来店の会計を分割（割り勘）
+	manager.POST("/store/visit/split", p.CreateBillSplit, requirePermission(models.PermissionOrdersWrite))
+	// - 割り勘の伝票と支払い状況を取得
+	manager.GET("/store/visit/split/:id", p.GetBillSplit, requirePermission(models.PermissionOrdersRead))
+	// - 割り勘の残額を伝票として割り当て
+	manager.POST("/store/visit/split/:id/remainder", p.AssignSplitRemainder, requirePermission(models.PermissionOrdersWrite))
+	// - 割り勘の伝票の支払いを記録
+	manager.POST("/store/visit/split/:id/bill/:bill_id/pay", p.PaySubBill, requirePermission(models.PermissionOrdersWrite))
+	// - スタッフ確認が必要な注文を取得
+	manager.GET("/store/order/review", p.ListOrdersForReview, requirePermission(models.PermissionOrdersRead))
+	// 外部連携用APIキーの管理（マネージャーのログインが必要）
+	manager.GET("/apikey", p.ListAPIKeys, requireManager())
+	manager.POST("/apikey", p.CreateAPIKey, requireManager())
+	manager.DELETE("/apikey/:id", p.RevokeAPIKey, requireManager())
 }
 
 // handleSession sets up the routes for the session endpoints.
@@ -144,6 +281,27 @@ func (p *Client) handleSession(private *echo.Group, key string) {
 		},
 		SigningKey: []byte(key),
 	}))
+	// 会計・退席により失効したセッションを拒否する
+	session.Use(p.sessionRevocationMiddleware())
+	// 注文の連投を防ぐため、座席単位で制限する
+	// 店内の客は店舗のIPを共有するため、IP単位では制限しない（1つの座席の連投で他の座席が429にならないように）
+	session.Use(rateLimitMiddleware(p.rateLimits, "session", loadRateLimitPolicy("RATE_LIMIT_SESSION", defaultSessionRateLimit), rateLimitBySeat))
+	// 店舗ネットワーク外からのアクセスを制限する
+	session.Use(p.networkRestrictionMiddleware())
 	// -H "Authorization: Bearer <session_jwt>"を付与してリクエスト
 	session.GET("/health", privateHealth)
+	// 提供中のメニュー（商品と単価）
+	session.GET("/menu", p.GetMenu)
+	// 注文
+	session.POST("/order", p.PlaceOrder)
+	// 店舗の確認前の注文の商品の数量・特別な指示を変更
+	session.PUT("/order/item/:id", p.UpdateOrderItem)
+	// 店舗の確認前の注文から商品を削除
+	session.DELETE("/order/item/:id", p.RemoveOrderItem)
+	// 来店の現在の会計（追加注文をまとめた合計）
+	session.GET("/visit", p.GetCurrentVisitCheck)
+	// プロモーションコードの適用
+	session.POST("/order/promo", p.RedeemPromotion)
+	// 注文のオンライン決済
+	session.POST("/order/pay", p.PayOrder)
 }
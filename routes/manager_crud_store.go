@@ -2,6 +2,7 @@ package routes
 
 import (
 	"backend/models"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -79,7 +80,19 @@ func (p *Client) RegisterStore(c echo.Context) error {
 		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
 	}
 
-	createStore, err := p.uc.RegisterStore(c.Request().Context(), store.Name, store.Email, store.Password, store.Address, store.Phone)
+	// 店舗スコープのAPIキーでは店舗を追加できない
+	// 追加した店舗は、登録したマネージャー（組織スコープのAPIキーの場合は発行した組織）に属する
+	organizationID := ""
+	if key := getAPIKey(c); key != nil {
+		if key.Scope != models.APIKeyScopeOrganization {
+			return responseHandler(c, http.StatusForbidden, nil, models.ErrPermissionDenied, "Store-scoped API key cannot register stores")
+		}
+		organizationID = key.OrganizationID
+	} else if claims, err := getManagerClaims(c); err == nil {
+		organizationID = claims.Email
+	}
+
+	createStore, err := p.uc.RegisterStore(c.Request().Context(), organizationID, store.Name, store.Email, store.Password, store.Address, store.Phone)
 	if err != nil {
 		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to register store: %v", err)
 	}
@@ -114,9 +127,13 @@ func (p *Client) GetAllStores(c echo.Context) error {
 	}
 
 	// 返り値を整形する
-	responseStores := make([]*ResponseStore, len(stores))
-	for i, store := range stores {
-		responseStores[i] = NewResponseStore(store)
+	// 店舗スコープのAPIキーの場合は、操作可能な店舗のみを返す
+	responseStores := make([]*ResponseStore, 0, len(stores))
+	for _, store := range stores {
+		if authorizeStore(c, store.ID) != nil {
+			continue
+		}
+		responseStores = append(responseStores, NewResponseStore(store))
 	}
 
 	return responseHandler(c, http.StatusOK, responseStores, nil, "")
@@ -157,3 +174,201 @@ func (p *Client) DeleteStore(c echo.Context) error {
 
 	return responseHandler(c, http.StatusOK, nil, nil, "Store deleted successfully")
 }
+
+type RequestStoreNetwork struct {
+	StoreID      string                    `json:"store_id"`
+	AllowedCIDRs []string                  `json:"allowed_cidrs"`
+	Mode         models.NetworkRestriction `json:"mode"`
+}
+
+// UpdateStoreNetwork は、店舗ネットワーク外からの注文制限を設定するためのエンドポイントです。
+// mode は "off"（制限なし）、"enforce"（拒否）、"soft"（スタッフ確認のフラグのみ）のいずれかです。
+func (p *Client) UpdateStoreNetwork(c echo.Context) error {
+	req := &RequestStoreNetwork{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store network data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreNetworkRestriction(c.Request().Context(), req.StoreID, req.AllowedCIDRs, req.Mode)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidCIDR) || errors.Is(err, models.ErrInvalidNetworkRestriction) || errors.Is(err, models.ErrAllowedCIDRsRequired) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to update store network: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id":      store.ID,
+		"allowed_cidrs": store.AllowedCIDRs,
+		"mode":          store.NetworkRestriction,
+	}, nil, "Store network updated successfully")
+}
+
+type RequestStoreTax struct {
+	StoreID   string              `json:"store_id"`
+	PriceMode models.PriceMode    `json:"price_mode"`
+	Rounding  models.RoundingMode `json:"rounding"`
+	// DiningOption はお客様の注文に適用する区分で、省略した場合は変更しません。
+	DiningOption models.DiningOption `json:"dining_option"`
+}
+
+// UpdateStoreTax は、店舗の消費税の計算設定を更新するためのエンドポイントです。
+// price_mode は "inclusive"（税込）または "exclusive"（税抜）、rounding は "down"（切り捨て）、"half_up"（四捨五入）、"up"（切り上げ）のいずれかです。
+// dining_option は "eat_in"（店内飲食）または "takeout"（持ち帰り）で、持ち帰り専門の店舗などで設定します。
+func (p *Client) UpdateStoreTax(c echo.Context) error {
+	req := &RequestStoreTax{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store tax data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreTaxPolicy(c.Request().Context(), req.StoreID, models.TaxPolicy{PriceMode: req.PriceMode, Rounding: req.Rounding}, req.DiningOption)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidPriceMode) || errors.Is(err, models.ErrInvalidRoundingMode) || errors.Is(err, models.ErrInvalidDiningOption) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to update store tax: %v", err)
+	}
+
+	policy := store.TaxPolicy()
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id":      store.ID,
+		"price_mode":    policy.PriceMode,
+		"rounding":      policy.Rounding,
+		"dining_option": store.DiningOption,
+	}, nil, "Store tax updated successfully")
+}
+
+type RequestStoreWorkflow struct {
+	StoreID  string `json:"store_id"`
+	Workflow string `json:"workflow"`
+}
+
+// UpdateStoreWorkflow は、店舗の注文のワークフローを選択するためのエンドポイントです。
+// workflow は組み込みの "default"（標準）、"counter"（カウンター形式）、"payment_first"（先払い）のいずれかで、空の場合は標準になります。
+// 店舗独自の状態や遷移は定義できません。
+func (p *Client) UpdateStoreWorkflow(c echo.Context) error {
+	req := &RequestStoreWorkflow{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store workflow data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreWorkflow(c.Request().Context(), req.StoreID, req.Workflow)
+	if err != nil {
+		if errors.Is(err, models.ErrWorkflowNotFound) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to update store workflow: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id": store.ID,
+		"workflow": store.Workflow,
+	}, nil, "Store workflow updated successfully")
+}
+
+// GetWorkflow は、注文のワークフロー（状態遷移の定義）を書き出すためのエンドポイントです。
+// store_id を指定した場合は店舗が選択したワークフロー、それ以外は name（空の場合は標準）の組み込みのワークフローを返します。
+// format は "json"（既定）、"mermaid"（Mermaid の状態遷移図）、"dot"（Graphviz）のいずれかです。
+func (p *Client) GetWorkflow(c echo.Context) error {
+	var workflow *models.Workflow
+	var err error
+	if storeID := c.QueryParam("store_id"); storeID != "" {
+		if err := authorizeStore(c, storeID); err != nil {
+			return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", storeID)
+		}
+		workflow, err = p.uc.GetStoreWorkflow(c.Request().Context(), storeID)
+	} else {
+		workflow, err = models.LookupWorkflow(c.QueryParam("name"))
+	}
+	if err != nil {
+		if errors.Is(err, models.ErrWorkflowNotFound) {
+			return responseHandler(c, http.StatusNotFound, nil, err, "Workflow not found: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to get workflow: %v", err)
+	}
+
+	format := models.WorkflowFormat(c.QueryParam("format"))
+	switch format {
+	case "", models.WorkflowFormatJSON:
+		return responseHandler(c, http.StatusOK, workflow.Schema(), nil, "Workflow retrieved successfully")
+	case models.WorkflowFormatMermaid, models.WorkflowFormatDOT:
+		body, err := workflow.Export(format)
+		if err != nil {
+			return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to export workflow: %v", err)
+		}
+		extension := "dot"
+		if format == models.WorkflowFormatMermaid {
+			extension = "mmd"
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="workflow-%s.%s"`, workflow.Name(), extension))
+		return c.Blob(http.StatusOK, "text/plain; charset=utf-8", body)
+	default:
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrUnsupportedWorkflowFormat, "Invalid format: %s", format)
+	}
+}
+
+type RequestStoreExpiry struct {
+	StoreID                  string              `json:"store_id"`
+	UnconfirmedAction        models.ExpiryAction `json:"unconfirmed_action"`
+	AutoCompleteAfterMinutes int                 `json:"auto_complete_after_minutes"`
+	VisitIdleTimeoutMinutes  int                 `json:"visit_idle_timeout_minutes"`
+}
+
+// ToModel はリクエストを自動のステータス遷移の設定に変換します。
+func (r *RequestStoreExpiry) ToModel() models.ExpiryPolicy {
+	return models.ExpiryPolicy{
+		UnconfirmedAction: r.UnconfirmedAction,
+		AutoCompleteAfter: time.Duration(r.AutoCompleteAfterMinutes) * time.Minute,
+		VisitIdleTimeout:  time.Duration(r.VisitIdleTimeoutMinutes) * time.Minute,
+	}
+}
+
+// UpdateStoreExpiry は、時間経過による自動のステータス遷移を設定するためのエンドポイントです。
+// unconfirmed_action は確認されないまま有効期限を過ぎた注文の扱いで、"cancel"（既定）、"decline"、"keep"（そのまま残す）のいずれかです。
+// 時間は分単位で、0の場合は既定値（自動完了は30分、来店の終了は180分）になります。
+func (p *Client) UpdateStoreExpiry(c echo.Context) error {
+	req := &RequestStoreExpiry{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store expiry data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreExpiryPolicy(c.Request().Context(), req.StoreID, req.ToModel())
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidExpiryPolicy) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to update store expiry: %v", err)
+	}
+
+	policy := store.ExpiryPolicy()
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id":                    store.ID,
+		"unconfirmed_action":          policy.UnconfirmedAction,
+		"auto_complete_after_minutes": int(policy.AutoCompleteAfter / time.Minute),
+		"visit_idle_timeout_minutes":  int(policy.VisitIdleTimeout / time.Minute),
+	}, nil, "Store expiry updated successfully")
+}
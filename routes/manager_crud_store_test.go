@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWorkflow(t *testing.T) {
+	p := &Client{}
+	request := func(target string) *httptest.ResponseRecorder {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+		assert.NoError(t, p.GetWorkflow(c))
+		return rec
+	}
+
+	rec := request("/?name=counter")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"accepts_additions":true`)
+
+	rec = request("/?format=mermaid")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "---\ntitle: default\n---\nstateDiagram-v2\n"))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), `filename="workflow-default.mmd"`)
+
+	rec = request("/?name=payment_first&format=dot")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `digraph "payment_first"`)
+
+	assert.Equal(t, http.StatusNotFound, request("/?name=unknown").Code)
+	assert.Equal(t, http.StatusBadRequest, request("/?format=svg").Code)
+}
@@ -0,0 +1,242 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ListOrdersForReview は、スタッフの確認が必要な注文（店舗ネットワーク外からの注文など）を取得するためのエンドポイントです。
+func (p *Client) ListOrdersForReview(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id is required")
+	}
+
+	sessions, err := p.uc.ListOrdersForReview(c.Request().Context(), storeID)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to get orders for review: %v", err)
+	}
+
+	res, err := NewResponseSessions(sessions)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "")
+}
+
+// RequestOrderStatus の reason_code は、キャンセル・辞退・保留への遷移で必須の店舗の理由コードです。
+// reason は任意の補足（自由記述）で、省略した場合は理由コードのラベルを履歴に記録します。
+type RequestOrderStatus struct {
+	StoreID    string        `json:"store_id"`
+	OrderID    string        `json:"order_id"`
+	Status     models.Status `json:"status"`
+	ReasonCode string        `json:"reason_code"`
+	Reason     string        `json:"reason"`
+}
+
+// orderStatusErrorStatus は注文のステータスの更新で発生したエラーに対応するHTTPステータスを返します。
+func orderStatusErrorStatus(err error) int {
+	var transitionErr *models.InvalidStatusTransitionError
+	switch {
+	case errors.Is(err, models.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrOrderAlreadyFinal), errors.Is(err, models.ErrPaymentRequired), errors.As(err, &transitionErr),
+		errors.Is(err, models.ErrDiningOptionLocked):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrReasonCodeRequired), errors.Is(err, models.ErrUnknownReasonCode), errors.Is(err, models.ErrInvalidDiningOption):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UpdateOrderStatus は、スタッフが注文のステータスを更新するエンドポイントです。
+// 操作者と理由は遷移の履歴（タイムライン）に記録します。
+func (p *Client) UpdateOrderStatus(c echo.Context) error {
+	req := &RequestOrderStatus{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order status data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" || req.Status == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id, order_id and status are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, err := p.uc.UpdateOrderStatus(c.Request().Context(), req.StoreID, req.OrderID, req.Status, getActor(c), req.ReasonCode, req.Reason)
+	if err != nil {
+		return responseHandler(c, orderStatusErrorStatus(err), nil, err, "Failed to update order status: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order status updated successfully")
+}
+
+// RequestOrderDining の dining_option は "eat_in"（店内飲食）または "takeout"（持ち帰り）です。
+type RequestOrderDining struct {
+	StoreID      string              `json:"store_id"`
+	OrderID      string              `json:"order_id"`
+	DiningOption models.DiningOption `json:"dining_option"`
+}
+
+// ChangeOrderDiningOption は、スタッフがお客様に確認した店内飲食・持ち帰りの区分に注文を変更するエンドポイントです。
+// 区分により飲食料品の税率が変わるため、お客様の端末からは変更できません。
+func (p *Client) ChangeOrderDiningOption(c echo.Context) error {
+	req := &RequestOrderDining{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order dining data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" || req.DiningOption == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id, order_id and dining_option are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, err := p.uc.ChangeOrderDiningOption(c.Request().Context(), req.StoreID, req.OrderID, req.DiningOption)
+	if err != nil {
+		return responseHandler(c, orderStatusErrorStatus(err), nil, err, "Failed to change order dining option: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order dining option changed successfully")
+}
+
+// GetOrder は、注文をステータスの遷移のタイムラインとあわせて取得するエンドポイントです。
+func (p *Client) GetOrder(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	orderID := c.QueryParam("order_id")
+	if storeID == "" || orderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+
+	session, err := p.uc.GetOrder(c.Request().Context(), storeID, orderID)
+	if err != nil {
+		return responseHandler(c, orderStatusErrorStatus(err), nil, err, "Failed to get order: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order retrieved successfully")
+}
+
+// RequestOrderLine の reason_code は明細の取り消しで必須の店舗の理由コードです。
+// 数量の変更では reason（自由記述）が必須で、取り消しでは任意の補足です。
+type RequestOrderLine struct {
+	StoreID    string            `json:"store_id"`
+	OrderID    string            `json:"order_id"`
+	LineID     string            `json:"line_id"`
+	Status     models.LineStatus `json:"status"`
+	Quantity   int               `json:"quantity"`
+	ReasonCode string            `json:"reason_code"`
+	Reason     string            `json:"reason"`
+}
+
+// orderLineErrorStatus は明細の操作で発生したエラーに対応するHTTPステータスを返します。
+func orderLineErrorStatus(err error) int {
+	var transitionErr *models.InvalidLineStatusTransitionError
+	switch {
+	case errors.Is(err, models.ErrOrderLineNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidLineStatus), errors.Is(err, models.ErrLineVoidReasonRequired),
+		errors.Is(err, models.ErrInvalidLineQuantity), errors.Is(err, models.ErrInvalidItemQuantity),
+		errors.Is(err, models.ErrLineQuantityUnchanged):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrLineAlreadyStarted), errors.Is(err, models.ErrLineChangeNotAllowed),
+		errors.Is(err, models.ErrLineQuantityIncreaseNotAllowed), errors.Is(err, models.ErrOrderNotConfirmed),
+		errors.As(err, &transitionErr):
+		return http.StatusConflict
+	default:
+		return orderStatusErrorStatus(err)
+	}
+}
+
+// UpdateOrderLineStatus は、厨房が明細ごとに調理の開始・提供を記録するエンドポイントです。
+// 注文全体のステータスは明細の状況から導出して更新します。
+func (p *Client) UpdateOrderLineStatus(c echo.Context) error {
+	req := &RequestOrderLine{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order line data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" || req.LineID == "" || req.Status == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id, order_id, line_id and status are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, err := p.uc.UpdateOrderLineStatus(c.Request().Context(), req.StoreID, req.OrderID, req.LineID, req.Status, getActor(c))
+	if err != nil {
+		return responseHandler(c, orderLineErrorStatus(err), nil, err, "Failed to update order line status: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order line status updated successfully")
+}
+
+// VoidOrderLine は、調理前の明細を理由コードを添えて取り消すエンドポイントです。
+func (p *Client) VoidOrderLine(c echo.Context) error {
+	req := &RequestOrderLine{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order line data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" || req.LineID == "" || req.ReasonCode == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id, order_id, line_id and reason_code are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, err := p.uc.VoidOrderLine(c.Request().Context(), req.StoreID, req.OrderID, req.LineID, req.ReasonCode, req.Reason, getActor(c))
+	if err != nil {
+		return responseHandler(c, orderLineErrorStatus(err), nil, err, "Failed to void order line: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order line voided successfully")
+}
+
+// AdjustOrderLineQuantity は、調理前の明細の数量を理由を添えて変更するエンドポイントです。
+func (p *Client) AdjustOrderLineQuantity(c echo.Context) error {
+	req := &RequestOrderLine{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order line data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" || req.LineID == "" || req.Reason == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id, order_id, line_id and reason are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, err := p.uc.AdjustOrderLineQuantity(c.Request().Context(), req.StoreID, req.OrderID, req.LineID, req.Quantity, req.Reason, getActor(c))
+	if err != nil {
+		return responseHandler(c, orderLineErrorStatus(err), nil, err, "Failed to adjust order line quantity: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order line quantity adjusted successfully")
+}
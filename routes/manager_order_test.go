@@ -0,0 +1,160 @@
+package routes
+
+import (
+	"backend/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStatusErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, orderStatusErrorStatus(models.ErrOrderNotFound))
+	assert.Equal(t, http.StatusForbidden, orderStatusErrorStatus(models.ErrSessionStoreMismatch))
+	assert.Equal(t, http.StatusConflict, orderStatusErrorStatus(&models.InvalidStatusTransitionError{From: models.StatusCreated, To: models.StatusServed}))
+	assert.Equal(t, http.StatusConflict, orderStatusErrorStatus(fmt.Errorf("%w: 現在のステータスは 'completed'", models.ErrOrderAlreadyFinal)))
+	assert.Equal(t, http.StatusBadRequest, orderStatusErrorStatus(fmt.Errorf("%w: cancel", models.ErrReasonCodeRequired)))
+	assert.Equal(t, http.StatusBadRequest, orderStatusErrorStatus(fmt.Errorf("%w: hold/out_of_stock", models.ErrUnknownReasonCode)))
+	assert.Equal(t, http.StatusInternalServerError, orderStatusErrorStatus(errors.New("firestore unavailable")))
+}
+
+func TestOrderLineErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, orderLineErrorStatus(fmt.Errorf("%w: line_1", models.ErrOrderLineNotFound)))
+	assert.Equal(t, http.StatusBadRequest, orderLineErrorStatus(models.ErrInvalidLineQuantity))
+	assert.Equal(t, http.StatusConflict, orderLineErrorStatus(fmt.Errorf("%w: line_1", models.ErrLineAlreadyStarted)))
+	assert.Equal(t, http.StatusConflict, orderLineErrorStatus(fmt.Errorf("%w: 現在のステータスは 'created'", models.ErrOrderNotConfirmed)))
+	assert.Equal(t, http.StatusConflict, orderLineErrorStatus(&models.InvalidLineStatusTransitionError{LineID: "line_1", From: models.LineServed, To: models.LineCooking}))
+	assert.Equal(t, http.StatusForbidden, orderLineErrorStatus(models.ErrSessionStoreMismatch))
+	assert.Equal(t, http.StatusBadRequest, orderLineErrorStatus(fmt.Errorf("%w: void/unknown", models.ErrUnknownReasonCode)))
+}
+
+func TestOrderItemErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, orderItemErrorStatus(fmt.Errorf("%w: line_1", models.ErrOrderLineNotFound)))
+	assert.Equal(t, http.StatusConflict, orderItemErrorStatus(fmt.Errorf("%w: 現在のステータスは 'confirmed'", models.ErrItemEditNotAllowed)))
+	assert.Equal(t, http.StatusConflict, orderItemErrorStatus(models.ErrOrderExpired))
+	assert.Equal(t, http.StatusBadRequest, orderItemErrorStatus(models.ErrItemInstructionsTooLong))
+}
+
+func TestRequestOrderInstructions(t *testing.T) {
+	order := &RequestOrder{Items: []RequestOrderItem{{ProductID: "ramen", Quantity: 1, Instructions: " ネギ抜き "}}}
+	require.NoError(t, order.IsValidate())
+	assert.Equal(t, "ネギ抜き", order.ToModels()[0].Instructions)
+
+	order.Items[0].Instructions = strings.Repeat("あ", models.MaxItemInstructionsLength+1)
+	assert.ErrorIs(t, order.IsValidate(), models.ErrItemInstructionsTooLong)
+}
+
+func TestRequestOrderQuantity(t *testing.T) {
+	order := &RequestOrder{Items: []RequestOrderItem{{ProductID: "ramen", Quantity: models.MaxItemQuantity}}}
+	require.NoError(t, order.IsValidate())
+
+	order.Items[0].Quantity = models.MaxItemQuantity + 1
+	assert.ErrorIs(t, order.IsValidate(), models.ErrInvalidItemQuantity)
+
+	order.Items[0].Quantity = 0
+	assert.Error(t, order.IsValidate())
+	assert.Equal(t, http.StatusBadRequest, orderItemErrorStatus(models.ErrInvalidItemQuantity))
+}
+
+func TestNewResponseSessionLineStatus(t *testing.T) {
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{
+		*models.NewOrder("ramen", 1, models.Yen(900)),
+		*models.NewOrder("gyoza", 1, models.Yen(400)),
+	})
+	require.NoError(t, err)
+	require.NoError(t, session.VoidLine(session.Items[1].LineID, "品切れ", "manager:a@example.com", session.CreatedAt))
+
+	res, err := NewResponseSession(session)
+	require.NoError(t, err)
+	assert.Equal(t, models.LineQueued, res.Items[0].Status)
+	assert.Nil(t, res.Items[0].VoidedAt)
+	assert.Equal(t, models.LineVoided, res.Items[1].Status)
+	assert.Equal(t, "品切れ", res.Items[1].VoidReason)
+	assert.NotNil(t, res.Items[1].VoidedAt)
+	assert.True(t, res.Items[1].Subtotal.IsZero())
+	assert.Equal(t, models.Yen(900), res.TotalAmount)
+}
+
+func TestNewResponseCustomerSession(t *testing.T) {
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{
+		*models.NewOrder("ramen", 1, models.Yen(900)),
+		*models.NewOrder("gyoza", 1, models.Yen(400)),
+	})
+	require.NoError(t, err)
+	require.NoError(t, session.VoidLine(session.Items[1].LineID, "品切れ", "manager:a@example.com", session.CreatedAt))
+	discount, err := models.NewManualDiscount(models.DiscountRule{Kind: models.DiscountFixed, Value: 100, Scope: models.DiscountScopeOrder}, "常連様", "manager:a@example.com", session.CreatedAt)
+	require.NoError(t, err)
+	require.NoError(t, session.ApplyDiscount(discount))
+	require.NoError(t, session.UpdateStatusBy(models.StatusConfirmed, "manager:a@example.com", "電話で確認"))
+	session.Charges = []models.Charge{{ID: "charge_1", Name: "お通し", Quantity: 2, Amount: models.Yen(600), Waived: true, WaiveReason: "待ち時間", WaivedBy: "manager:a@example.com"}}
+	session.Refunds = []models.Refund{{ID: "refund_1", Amount: models.Yen(100), Reason: "提供ミス", Actor: "manager:a@example.com"}}
+	session.NeedsReview, session.ReviewReason = true, "店舗ネットワーク外からの注文"
+
+	res, err := NewResponseCustomerSession(session)
+	require.NoError(t, err)
+	assert.Equal(t, models.LineVoided, res.Items[1].Status)
+	assert.Equal(t, models.Yen(100), res.DiscountTotal)
+	require.Len(t, res.Charges, 1)
+	assert.True(t, res.Charges[0].Waived)
+	assert.Equal(t, models.Yen(100), res.RefundedTotal)
+
+	body, err := json.Marshal(res)
+	require.NoError(t, err)
+	for _, staffOnly := range []string{"manager:a@example.com", "品切れ", "常連様", "電話で確認", "待ち時間", "提供ミス", "店舗ネットワーク外", "timeline"} {
+		assert.NotContains(t, string(body), staffOnly)
+	}
+}
+
+func TestNewResponseSessionTimeline(t *testing.T) {
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("ramen", 1, models.Yen(900))})
+	require.NoError(t, err)
+	require.NoError(t, session.UpdateStatusBy(models.StatusConfirmed, "manager:a@example.com", ""))
+	session.StatusHistory[1].At = session.CreatedAt.Add(90 * time.Second)
+
+	res, err := NewResponseSession(session)
+	require.NoError(t, err)
+	require.Len(t, res.Timeline, 2)
+	assert.Equal(t, models.StatusConfirmed, res.Timeline[1].To)
+	require.NotNil(t, res.Durations.TimeToConfirm)
+	assert.Equal(t, int64(90), *res.Durations.TimeToConfirm)
+	assert.Nil(t, res.Durations.PrepTime)
+	assert.Nil(t, res.Durations.TimeToServe)
+}
+
+func TestNewResponseSessionWorkflow(t *testing.T) {
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("ramen", 1, models.Yen(900))})
+	require.NoError(t, err)
+	require.NoError(t, session.SetWorkflow(models.WorkflowCounter))
+
+	res, err := NewResponseSession(session)
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowCounter, res.Workflow)
+	assert.Equal(t, []models.Status{models.StatusPreparing, models.StatusCancelled, models.StatusDeclined}, res.NextStatuses)
+
+	require.NoError(t, session.UpdateStatus(models.StatusDeclined))
+	res, err = NewResponseSession(session)
+	require.NoError(t, err)
+	assert.NotNil(t, res.NextStatuses)
+	assert.Empty(t, res.NextStatuses)
+}
+
+func TestNewResponseSessionPaymentStatus(t *testing.T) {
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("ramen", 1, models.Yen(900))})
+	require.NoError(t, err)
+	require.NoError(t, session.UpdatePaymentStatus(models.PaymentStatusPaid, "manager:a@example.com", "レジでの精算"))
+
+	res, err := NewResponseSession(session)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCreated, res.Status, "支払い済みで調理前の注文")
+	assert.Equal(t, models.PaymentStatusPaid, res.PaymentStatus)
+	require.Len(t, res.PaymentTimeline, 2)
+	assert.Equal(t, models.PaymentStatusUnpaid, res.PaymentTimeline[1].From)
+
+	assert.Equal(t, http.StatusConflict, orderStatusErrorStatus(models.ErrPaymentRequired))
+}
@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"backend/models"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RequestSeat struct {
+	StoreID string `json:"store_id"`
+	Name    string `json:"name"`
+}
+
+func (s *RequestSeat) IsValidate() error {
+	var missingFields []string
+
+	if s.StoreID == "" {
+		missingFields = append(missingFields, "store_id")
+	}
+	if s.Name == "" {
+		missingFields = append(missingFields, "name")
+	}
+
+	if len(missingFields) > 0 {
+		return fmt.Errorf("missing required fields: %s", missingFields)
+	}
+	return nil
+}
+
+type ResponseSeat struct {
+	ID        string    `json:"id"`
+	StoreID   string    `json:"store_id"`
+	Name      string    `json:"name"`
+	QRVersion int       `json:"qr_version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewResponseSeat は、models.SeatをResponseSeatに変換します。
+func NewResponseSeat(seat *models.Seat) *ResponseSeat {
+	return &ResponseSeat{
+		ID:        seat.ID,
+		StoreID:   seat.StoreID,
+		Name:      seat.Name,
+		QRVersion: seat.QRVersion,
+		CreatedAt: seat.CreatedAt,
+		UpdatedAt: seat.UpdatedAt,
+	}
+}
+
+// RegisterSeat は、店舗に座席を登録するためのエンドポイントです。
+// QRコードは登録済みの座席に対してのみ発行できます。
+func (p *Client) RegisterSeat(c echo.Context) error {
+	seat := &RequestSeat{}
+	if err := c.Bind(seat); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind seat data: %v", err)
+	}
+
+	if err := seat.IsValidate(); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+	if err := authorizeStore(c, seat.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", seat.StoreID)
+	}
+
+	created, err := p.uc.RegisterSeat(c.Request().Context(), seat.StoreID, seat.Name)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to register seat: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseSeat(created), nil, "Seat added successfully")
+}
+
+// RotateSeatQR は、座席のQRコードを再発行扱いにするエンドポイントです。
+// 実行後は印刷済みの古いQRコードからセッションを開始できなくなるため、新しいQRコードを発行し直してください。
+func (p *Client) RotateSeatQR(c echo.Context) error {
+	storeID, seatID, err := getStoreAndSeatID(c)
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid parameters: %v", err)
+	}
+
+	seat, err := p.uc.RotateSeatQR(c.Request().Context(), storeID, seatID)
+	if err != nil {
+		return responseHandler(c, http.StatusNotFound, nil, err, "Failed to rotate seat QR: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseSeat(seat), nil, "Seat QR rotated successfully")
+}
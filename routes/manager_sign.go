@@ -2,6 +2,7 @@ package routes
 
 import (
 	"backend/models"
+	"errors"
 	"net/http"
 	"time"
 
@@ -66,6 +67,7 @@ func (p *Client) Signup(c echo.Context) error {
 //   - リクエストボディからマネージャ情報（Email, Password）をバインドします。
 //   - Firestoreから該当マネージャ情報を取得し、パスワードを検証します。
 //   - パスワードが一致した場合、JWTトークンを生成して返却します。
+//   - 認証情報が誤っている場合は401、失敗が続きロック中の場合は429とRetry-Afterヘッダーを返します。
 //   - テストモードの場合はテスト用のマネージャ情報を使用します。
 //   - パスワードはレスポンスに含めません。
 func (p *Client) Signin(c echo.Context) error {
@@ -82,7 +84,16 @@ func (p *Client) Signin(c echo.Context) error {
 		// read database
 		// KeyはEmailを想定
 		if err := p.uc.ManagerSignIn(c.Request().Context(), manager.Email, manager.Password); err != nil {
-			return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to sign in manager")
+			var lockedErr *models.AccountLockedError
+			switch {
+			case errors.As(err, &lockedErr):
+				setRetryAfter(c, lockedErr.RetryAfter(time.Now().UTC()))
+				return responseHandler(c, http.StatusTooManyRequests, nil, err, "Account is temporarily locked")
+			case errors.Is(err, models.ErrInvalidCredentials):
+				return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid email or password")
+			default:
+				return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to sign in manager")
+			}
 		}
 
 		// [Important] パスワードは返さない
@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// networkReviewKey はソフトモードで店舗ネットワーク外と判定された注文の確認理由をコンテキストに保持するキーです。
+const networkReviewKey = "network_review_reason"
+
+var ErrOutsideStoreNetwork = errors.New("request is not from the store network")
+
+// configureIPExtractor はクライアントIPの取得方法を設定します。
+// Cloud Run ではロードバランサが X-Forwarded-For の末尾にクライアントIPを付与するため、
+// 信頼するプロキシ（既定ではループバック・リンクローカル・プライベートアドレス）を右から順に除いたアドレスを採用します。
+// 環境変数 TRUSTED_PROXIES にカンマ区切りのCIDRを指定すると、信頼するプロキシを追加できます。
+func configureIPExtractor(e *echo.Echo) {
+	var options []echo.TrustOption
+	for _, value := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			log.Warn().Err(err).Msgf("TRUSTED_PROXIES contains invalid CIDR: %s", value)
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	e.IPExtractor = echo.ExtractIPFromXFFHeader(options...)
+}
+
+// networkRestrictionMiddleware は店舗ネットワーク外からの注文を制限するミドルウェアです。
+// セッションJWT検証ミドルウェアの後に登録する必要があります。
+// 店舗の設定が "enforce" の場合は403を返し、"soft" の場合はリクエストを通した上で確認理由をコンテキストに設定します。
+func (p *Client) networkRestrictionMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := getSessionClaims(c)
+			if err != nil {
+				return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+			}
+
+			store, err := p.uc.GetStore(c.Request().Context(), claims.StoreID)
+			if err != nil {
+				return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to get store: %v", err)
+			}
+
+			allowed, err := store.IsAllowedIP(c.RealIP())
+			if err != nil {
+				return responseHandler(c, http.StatusInternalServerError, nil, err, "Invalid store network settings: %v", err)
+			}
+			if allowed {
+				return next(c)
+			}
+
+			if store.NetworkRestriction == models.NetworkRestrictionSoft {
+				log.Warn().Msgf("order from outside store network, store_id=%s, seat_id=%s, ip=%s", claims.StoreID, claims.SeatID, c.RealIP())
+				c.Set(networkReviewKey, models.ReviewReasonOutsideNetwork)
+				return next(c)
+			}
+			return responseHandler(c, http.StatusForbidden, nil, ErrOutsideStoreNetwork, "Ordering is only available from the store network")
+		}
+	}
+}
+
+// getNetworkReviewReason はネットワーク制限ミドルウェアが設定した確認理由を返します。
+func getNetworkReviewReason(c echo.Context) string {
+	reason, _ := c.Get(networkReviewKey).(string)
+	return reason
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureIPExtractor(t *testing.T) {
+	realIP := func(e *echo.Echo, remoteAddr, xff string) string {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/private/session/order", nil)
+		req.RemoteAddr = remoteAddr
+		if xff != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, xff)
+		}
+		return e.NewContext(req, httptest.NewRecorder()).RealIP()
+	}
+
+	t.Run("プライベートアドレスのプロキシ経由ではXFFのクライアントIPを採用する", func(t *testing.T) {
+		e := echo.New()
+		configureIPExtractor(e)
+		assert.Equal(t, "203.0.113.5", realIP(e, "10.0.0.1:1234", "198.51.100.9, 203.0.113.5"))
+	})
+
+	t.Run("信頼しないアドレスからのXFFは無視する", func(t *testing.T) {
+		e := echo.New()
+		configureIPExtractor(e)
+		assert.Equal(t, "198.51.100.1", realIP(e, "198.51.100.1:1234", "192.168.1.10"))
+	})
+
+	t.Run("TRUSTED_PROXIESで信頼するプロキシを追加できる", func(t *testing.T) {
+		t.Setenv("TRUSTED_PROXIES", "198.51.100.0/24, invalid")
+		e := echo.New()
+		configureIPExtractor(e)
+		assert.Equal(t, "192.168.1.10", realIP(e, "198.51.100.1:1234", "192.168.1.10"))
+	})
+}
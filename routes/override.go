@@ -0,0 +1,161 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// RequestOrderOverride の reason は必須です。キャンセル・辞退・保留への強制変更には店舗の理由コード（reason_code）も必要です。
+// 操作者が強制変更の権限（APIキーの orders:override、または店舗で許可されたマネージャー）を持たない場合は、
+// 責任者がスタッフの端末で supervisor_pin を入力します。
+type RequestOrderOverride struct {
+	StoreID       string        `json:"store_id"`
+	OrderID       string        `json:"order_id"`
+	Status        models.Status `json:"status"`
+	Reason        string        `json:"reason"`
+	ReasonCode    string        `json:"reason_code"`
+	SupervisorPIN string        `json:"supervisor_pin"`
+}
+
+// RequestStoreSupervisors の supervisors は責任者の全件で、既存の設定を置き換えます。PINは6〜12桁の数字です。
+// override_managers は責任者のPINなしで強制変更を行えるマネージャーのメールアドレスの全件で、同じく既存の設定を置き換えます。
+type RequestStoreSupervisors struct {
+	StoreID          string                 `json:"store_id"`
+	Supervisors      []models.SupervisorPIN `json:"supervisors"`
+	OverrideManagers []string               `json:"override_managers"`
+}
+
+// overrideGranted は操作者が責任者のPINなしにステータスの強制変更を行えるかどうかを返します。
+// APIキーは orders:override の権限、マネージャーは店舗での許可（override_managers）が必要です。
+func (p *Client) overrideGranted(c echo.Context, storeID string) (bool, error) {
+	if getAPIKey(c) != nil {
+		return hasPermission(c, models.PermissionOrdersOverride), nil
+	}
+	claims, err := getManagerClaims(c)
+	if err != nil {
+		return false, nil
+	}
+	return p.uc.AllowsManagerOverride(c.Request().Context(), storeID, claims.Email)
+}
+
+// overrideErrorStatus はステータスの強制変更で発生したエラーに対応するHTTPステータスを返します。
+func overrideErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrOverrideNotApproved), errors.Is(err, models.ErrInvalidSupervisorPIN):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrOverrideStatusUnchanged):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrOverrideReasonRequired), errors.Is(err, models.ErrInvalidOverrideStatus),
+		errors.Is(err, models.ErrInvalidSupervisor), errors.Is(err, models.ErrInvalidOverrideManager),
+		errors.Is(err, models.ErrInvalidBusinessDay):
+		return http.StatusBadRequest
+	default:
+		return orderStatusErrorStatus(err)
+	}
+}
+
+// OverrideOrderStatus は、責任者の承認のもとで遷移ルールによらず注文のステータスを強制変更するエンドポイントです。
+// 強制変更は操作者・承認者・理由とあわせて注文のタイムラインに記録し、承認の失敗とあわせてログに残します。
+func (p *Client) OverrideOrderStatus(c echo.Context) error {
+	req := &RequestOrderOverride{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order override data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" || req.Status == "" || req.Reason == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id, order_id, status and reason are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	ctx := c.Request().Context()
+	actor := getActor(c)
+	override := models.StatusOverride{Actor: actor, Reason: req.Reason}
+	if req.SupervisorPIN != "" {
+		supervisor, err := p.uc.VerifySupervisorPIN(ctx, req.StoreID, req.SupervisorPIN)
+		if err != nil {
+			log.Warn().Msgf("order override rejected, store_id=%s, order_id=%s, actor=%s: %v", req.StoreID, req.OrderID, actor, err)
+			var lockedErr *models.SupervisorPINLockedError
+			if errors.As(err, &lockedErr) {
+				setRetryAfter(c, lockedErr.RetryAfter(time.Now().UTC()))
+				return responseHandler(c, http.StatusTooManyRequests, nil, err, "Supervisor PIN is temporarily locked")
+			}
+			return responseHandler(c, overrideErrorStatus(err), nil, err, "Failed to verify supervisor PIN: %v", err)
+		}
+		override.ApprovedBy = supervisor.Approver()
+	} else {
+		granted, err := p.overrideGranted(c, req.StoreID)
+		if err != nil {
+			return responseHandler(c, overrideErrorStatus(err), nil, err, "Failed to check override permission: %v", err)
+		}
+		if !granted {
+			return responseHandler(c, http.StatusForbidden, nil, models.ErrOverrideNotApproved, "%s permission or supervisor_pin is required", models.PermissionOrdersOverride)
+		}
+		override.ApprovedBy = actor
+	}
+
+	session, err := p.uc.OverrideOrderStatus(ctx, req.StoreID, req.OrderID, req.Status, req.ReasonCode, override)
+	if err != nil {
+		return responseHandler(c, overrideErrorStatus(err), nil, err, "Failed to override order status: %v", err)
+	}
+	log.Info().Msgf("order status overridden, store_id=%s, order_id=%s, status=%s, actor=%s, approved_by=%s", req.StoreID, req.OrderID, req.Status, actor, override.ApprovedBy)
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order status overridden successfully")
+}
+
+// ListStatusOverrides は、営業日 from から to まで（両端を含む）のステータスの強制変更を監査のために取得するエンドポイントです。
+// to を省略した場合は from の1日分です。
+func (p *Client) ListStatusOverrides(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	from := c.QueryParam("from")
+	if storeID == "" || from == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and from are required")
+	}
+	to := c.QueryParam("to")
+	if to == "" {
+		to = from
+	}
+
+	records, err := p.uc.ListStatusOverrides(c.Request().Context(), storeID, from, to)
+	if err != nil {
+		return responseHandler(c, overrideErrorStatus(err), nil, err, "Failed to list status overrides: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, records, nil, "Status overrides retrieved successfully")
+}
+
+// UpdateStoreSupervisors は、ステータスの強制変更を承認できる責任者とPINを設定するためのエンドポイントです。
+// PINは返しません。
+func (p *Client) UpdateStoreSupervisors(c echo.Context) error {
+	req := &RequestStoreSupervisors{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store supervisor data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	store, err := p.uc.UpdateStoreSupervisors(c.Request().Context(), req.StoreID, req.Supervisors, req.OverrideManagers)
+	if err != nil {
+		return responseHandler(c, overrideErrorStatus(err), nil, err, "Failed to update store supervisors: %v", err)
+	}
+
+	names := make([]string, len(store.Supervisors))
+	for i, supervisor := range store.Supervisors {
+		names[i] = supervisor.Name
+	}
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id":          store.ID,
+		"supervisors":       names,
+		"override_managers": store.OverrideManagers,
+	}, nil, "Store supervisors updated successfully")
+}
@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"backend/models"
+	"backend/usecases"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrideErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusForbidden, overrideErrorStatus(models.ErrInvalidSupervisorPIN))
+	assert.Equal(t, http.StatusForbidden, overrideErrorStatus(models.ErrOverrideNotApproved))
+	assert.Equal(t, http.StatusConflict, overrideErrorStatus(fmt.Errorf("%w: \"served\"", models.ErrOverrideStatusUnchanged)))
+	assert.Equal(t, http.StatusBadRequest, overrideErrorStatus(fmt.Errorf("%w: \"unknown\"", models.ErrInvalidOverrideStatus)))
+	assert.Equal(t, http.StatusBadRequest, overrideErrorStatus(fmt.Errorf("%w: PINは6〜12桁の数字です (supervisors[0])", models.ErrInvalidSupervisor)))
+	assert.Equal(t, http.StatusBadRequest, overrideErrorStatus(fmt.Errorf("%w: cancel", models.ErrReasonCodeRequired)))
+	assert.Equal(t, http.StatusBadRequest, overrideErrorStatus(fmt.Errorf("%w: メールアドレスを指定してください (override_managers[0])", models.ErrInvalidOverrideManager)))
+	assert.Equal(t, http.StatusNotFound, overrideErrorStatus(models.ErrOrderNotFound))
+}
+
+func TestHasPermission(t *testing.T) {
+	e := echo.New()
+	newContext := func() echo.Context {
+		return e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	}
+
+	c := newContext()
+	c.Set(apiKeyContextKey, &models.APIKey{Permissions: []models.Permission{models.PermissionOrdersWrite}})
+	assert.False(t, hasPermission(c, models.PermissionOrdersOverride))
+
+	c = newContext()
+	c.Set(apiKeyContextKey, &models.APIKey{Permissions: []models.Permission{models.PermissionOrdersWrite, models.PermissionOrdersOverride}})
+	assert.True(t, hasPermission(c, models.PermissionOrdersOverride))
+
+	c = newContext()
+	c.Set("user", &jwt.Token{Claims: &models.Claims{Email: "manager@example.com"}})
+	assert.True(t, hasPermission(c, models.PermissionOrdersWrite))
+	assert.False(t, hasPermission(c, models.PermissionOrdersOverride), "マネージャーの強制変更は店舗ごとに許可する")
+
+	assert.False(t, hasPermission(newContext(), models.PermissionOrdersOverride))
+}
+
+func TestOverrideOrderStatusRequiresApproval(t *testing.T) {
+	p := &Client{uc: usecases.New(nil)}
+	request := func(body string) int {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(apiKeyContextKey, &models.APIKey{Scope: models.APIKeyScopeStore, StoreID: "store_1", Permissions: []models.Permission{models.PermissionOrdersWrite}})
+		assert.NoError(t, p.OverrideOrderStatus(c))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, request(`{"store_id":"store_1","order_id":"order_1","status":"served"}`), "理由は必須")
+	assert.Equal(t, http.StatusForbidden, request(`{"store_id":"store_1","order_id":"order_1","status":"served","reason":"提供済み"}`), "権限もPINもない")
+	assert.Equal(t, http.StatusForbidden, request(`{"store_id":"store_2","order_id":"order_1","status":"served","reason":"提供済み","supervisor_pin":"246810"}`), "他店舗は操作できない")
+}
@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxWebhookBodySize は Webhook のリクエストボディの上限です。
+const maxWebhookBodySize = 1 << 20
+
+type RequestStartPayment struct {
+	StoreID       string `json:"store_id"`
+	OrderID       string `json:"order_id"`
+	ManualCapture bool   `json:"manual_capture"`
+}
+
+type RequestCapturePayment struct {
+	StoreID string `json:"store_id"`
+}
+
+type ResponsePayment struct {
+	ID            string                     `json:"id"`
+	OrderID       string                     `json:"order_id"`
+	Provider      string                     `json:"provider"`
+	IntentID      string                     `json:"intent_id"`
+	Amount        models.Money               `json:"amount"`
+	Status        models.PaymentIntentStatus `json:"status"`
+	FailureReason string                     `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	UpdatedAt     time.Time                  `json:"updated_at"`
+	// ClientSecret は支払いの開始時のレスポンスにのみ含まれ、お客様のブラウザでの支払い手続きに使用します。
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// NewResponsePayment は、models.PaymentをResponsePaymentに変換します。
+func NewResponsePayment(payment *models.Payment) *ResponsePayment {
+	return &ResponsePayment{
+		ID:            payment.ID,
+		OrderID:       payment.SessionID,
+		Provider:      payment.Provider,
+		IntentID:      payment.IntentID,
+		Amount:        payment.Amount,
+		Status:        payment.Status,
+		FailureReason: payment.FailureReason,
+		CreatedAt:     payment.CreatedAt,
+		UpdatedAt:     payment.UpdatedAt,
+	}
+}
+
+// paymentErrorStatus は決済の操作で発生したエラーに対応するHTTPステータスを返します。
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrPaymentNotFound), errors.Is(err, models.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrPaymentNotAllowed), errors.Is(err, models.ErrPaymentNotCapturable),
+		errors.Is(err, models.ErrPaymentAmountRequired), errors.Is(err, models.ErrBusinessDayClosed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidWebhookSignature), errors.Is(err, models.ErrWebhookTimestampExpired),
+		errors.Is(err, models.ErrInvalidWebhookPayload):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrPaymentProviderFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, models.ErrPaymentProviderNotConfigured):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// StartOrderPayment は、スタッフが注文のオンライン決済を開始するエンドポイントです。
+// manual_capture が true の場合はオーソリのみを行い、CapturePayment で売上を確定します。
+func (p *Client) StartOrderPayment(c echo.Context) error {
+	req := &RequestStartPayment{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind payment data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	payment, secret, err := p.uc.StartOrderPayment(c.Request().Context(), req.StoreID, "", req.OrderID, req.ManualCapture)
+	if err != nil {
+		return responseHandler(c, paymentErrorStatus(err), nil, err, "Failed to start payment: %v", err)
+	}
+
+	res := NewResponsePayment(payment)
+	res.ClientSecret = secret
+	return responseHandler(c, http.StatusOK, res, nil, "Payment started successfully")
+}
+
+// GetPayment は、決済の状態を取得するエンドポイントです。
+func (p *Client) GetPayment(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	payment, err := p.uc.GetPayment(c.Request().Context(), storeID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, paymentErrorStatus(err), nil, err, "Failed to get payment: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponsePayment(payment), nil, "Payment retrieved successfully")
+}
+
+// CapturePayment は、オーソリ済みの決済の売上を確定するエンドポイントです。
+func (p *Client) CapturePayment(c echo.Context) error {
+	req := &RequestCapturePayment{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind payment data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	payment, err := p.uc.CapturePayment(c.Request().Context(), req.StoreID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, paymentErrorStatus(err), nil, err, "Failed to capture payment: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponsePayment(payment), nil, "Payment captured successfully")
+}
+
+// PayOrder は、お客様が注文のオンライン決済を開始するエンドポイントです。
+// 店舗と座席はセッションJWTのクレームから取得します。
+func (p *Client) PayOrder(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+
+	req := &RequestStartPayment{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind payment data: %v", err)
+	}
+	if req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "order_id is required")
+	}
+
+	payment, secret, err := p.uc.StartOrderPayment(c.Request().Context(), claims.StoreID, claims.SeatID, req.OrderID, false)
+	if err != nil {
+		return responseHandler(c, paymentErrorStatus(err), nil, err, "Failed to start payment: %v", err)
+	}
+
+	res := NewResponsePayment(payment)
+	res.ClientSecret = secret
+	return responseHandler(c, http.StatusOK, res, nil, "Payment started successfully")
+}
+
+// PaymentWebhook は、決済代行会社からの Webhook を受け付けるエンドポイントです。
+// 署名の検証に加工前のリクエストボディが必要なため、Bind せずに読み込みます。
+// 重複して配信されたイベントも 200 を返し、決済代行会社が再送を繰り返さないようにします。
+func (p *Client) PaymentWebhook(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBodySize))
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to read webhook payload: %v", err)
+	}
+
+	duplicate, err := p.uc.HandlePaymentWebhook(c.Request().Context(), payload, c.Request().Header)
+	if err != nil {
+		return responseHandler(c, paymentErrorStatus(err), nil, err, "Failed to handle payment webhook: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{"duplicate": duplicate}, nil, "Webhook handled successfully")
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResponsePayment(t *testing.T) {
+	now := time.Now()
+	payment := &models.Payment{
+		ID:        "pay_1",
+		StoreID:   "store_1",
+		SessionID: "order_1",
+		Provider:  "fake",
+		IntentID:  "pi_1",
+		Amount:    models.Yen(1100),
+		Status:    models.PaymentIntentRequiresPayment,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	res := NewResponsePayment(payment)
+	assert.Equal(t, "order_1", res.OrderID)
+	assert.Equal(t, "pi_1", res.IntentID)
+	assert.Equal(t, models.PaymentIntentRequiresPayment, res.Status)
+	assert.Empty(t, res.ClientSecret)
+}
+
+func TestPaymentErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, paymentErrorStatus(models.ErrPaymentNotFound))
+	assert.Equal(t, http.StatusConflict, paymentErrorStatus(models.ErrPaymentNotCapturable))
+	assert.Equal(t, http.StatusConflict, paymentErrorStatus(models.ErrBusinessDayClosed))
+	assert.Equal(t, http.StatusBadRequest, paymentErrorStatus(models.ErrInvalidWebhookSignature))
+	assert.Equal(t, http.StatusBadGateway, paymentErrorStatus(fmt.Errorf("%w: card_declined", models.ErrPaymentProviderFailed)))
+	assert.Equal(t, http.StatusServiceUnavailable, paymentErrorStatus(models.ErrPaymentProviderNotConfigured))
+	assert.Equal(t, http.StatusInternalServerError, paymentErrorStatus(errors.New("firestore unavailable")))
+}
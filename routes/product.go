@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestStoreProducts の products は店舗のメニューの全件で、既存の設定を置き換えます。空の配列の場合は全て削除します。
+// price は補助単位（円、セント等）の整数です。id を省略した商品にはIDを割り当てます。
+type RequestStoreProducts struct {
+	StoreID  string           `json:"store_id"`
+	Products []models.Product `json:"products"`
+}
+
+// productErrorStatus はメニューの設定で発生したエラーに対応するHTTPステータスを返します。
+func productErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidProduct), errors.Is(err, models.ErrUnsupportedCurrency):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UpdateStoreProducts は、店舗のメニュー（商品と単価）を設定するためのエンドポイントです。
+func (p *Client) UpdateStoreProducts(c echo.Context) error {
+	req := &RequestStoreProducts{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store product data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreProducts(c.Request().Context(), req.StoreID, req.Products)
+	if err != nil {
+		return responseHandler(c, productErrorStatus(err), nil, err, "Failed to update store products: %v", err)
+	}
+
+	products := store.Products
+	if products == nil {
+		products = []models.Product{}
+	}
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id": store.ID,
+		"products": products,
+	}, nil, "Store products updated successfully")
+}
+
+// GetMenu は、お客様の端末に店舗の提供中のメニューを表示するためのエンドポイントです。
+// 店舗はセッションJWTのクレームから取得します。
+func (p *Client) GetMenu(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+
+	products, err := p.uc.GetMenu(c.Request().Context(), claims.StoreID)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to get menu: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id": claims.StoreID,
+		"products": products,
+	}, nil, "Menu retrieved successfully")
+}
@@ -0,0 +1,251 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestPromotion の min_spend は補助単位（円、セント等）の整数で、0の場合は条件なしです。
+// starts_at、ends_at を省略した場合は期間の制限なし、max_redemptions、max_per_visit が0の場合は回数の制限なしです。
+type RequestPromotion struct {
+	StoreID        string              `json:"store_id"`
+	Code           string              `json:"code"`
+	Name           string              `json:"name"`
+	Rule           models.DiscountRule `json:"rule"`
+	Currency       string              `json:"currency"`
+	MinSpend       int64               `json:"min_spend"`
+	StartsAt       *time.Time          `json:"starts_at"`
+	EndsAt         *time.Time          `json:"ends_at"`
+	MaxRedemptions int                 `json:"max_redemptions"`
+	MaxPerVisit    int                 `json:"max_per_visit"`
+}
+
+// ToModel は、リクエストをmodels.Promotionに変換します。
+func (r *RequestPromotion) ToModel() (*models.Promotion, error) {
+	currency, err := models.ParseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion := models.NewPromotion(r.StoreID, r.Code, r.Name, r.Rule, currency)
+	promotion.MinSpend = models.NewMoney(r.MinSpend, currency)
+	if r.StartsAt != nil {
+		promotion.StartsAt = r.StartsAt.UTC()
+	}
+	if r.EndsAt != nil {
+		promotion.EndsAt = r.EndsAt.UTC()
+	}
+	promotion.MaxRedemptions = r.MaxRedemptions
+	promotion.MaxPerVisit = r.MaxPerVisit
+	return promotion, nil
+}
+
+type ResponsePromotion struct {
+	ID             string              `json:"id"`
+	StoreID        string              `json:"store_id"`
+	Code           string              `json:"code"`
+	Name           string              `json:"name"`
+	Rule           models.DiscountRule `json:"rule"`
+	MinSpend       models.Money        `json:"min_spend"`
+	StartsAt       *time.Time          `json:"starts_at,omitempty"`
+	EndsAt         *time.Time          `json:"ends_at,omitempty"`
+	MaxRedemptions int                 `json:"max_redemptions"`
+	MaxPerVisit    int                 `json:"max_per_visit"`
+	Active         bool                `json:"active"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// NewResponsePromotion は、models.PromotionをResponsePromotionに変換します。
+func NewResponsePromotion(promotion *models.Promotion) *ResponsePromotion {
+	return &ResponsePromotion{
+		ID:             promotion.ID,
+		StoreID:        promotion.StoreID,
+		Code:           promotion.Code,
+		Name:           promotion.Name,
+		Rule:           promotion.Rule,
+		MinSpend:       promotion.MinSpend,
+		StartsAt:       optionalTime(promotion.StartsAt),
+		EndsAt:         optionalTime(promotion.EndsAt),
+		MaxRedemptions: promotion.MaxRedemptions,
+		MaxPerVisit:    promotion.MaxPerVisit,
+		Active:         promotion.Active,
+		CreatedAt:      promotion.CreatedAt,
+		UpdatedAt:      promotion.UpdatedAt,
+	}
+}
+
+type RequestManualDiscount struct {
+	StoreID string              `json:"store_id"`
+	OrderID string              `json:"order_id"`
+	Rule    models.DiscountRule `json:"rule"`
+	Reason  string              `json:"reason"`
+}
+
+type RequestRedeemPromotion struct {
+	OrderID string `json:"order_id"`
+	Code    string `json:"code"`
+}
+
+// discountErrorStatus はプロモーション・割引の操作で発生したエラーに対応するHTTPステータスを返します。
+func discountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrPromotionNotFound), errors.Is(err, models.ErrDiscountNotFound), errors.Is(err, models.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrPromotionCodeExists), errors.Is(err, models.ErrPromotionAlreadyApplied),
+		errors.Is(err, models.ErrPromotionUsageLimitReached), errors.Is(err, models.ErrPromotionVisitLimitReached),
+		errors.Is(err, models.ErrDiscountNotAllowed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrPromotionCodeRequired), errors.Is(err, models.ErrPromotionNameRequired),
+		errors.Is(err, models.ErrInvalidPromotionPeriod), errors.Is(err, models.ErrInvalidPromotionLimit),
+		errors.Is(err, models.ErrPromotionInactive), errors.Is(err, models.ErrPromotionNotStarted),
+		errors.Is(err, models.ErrPromotionExpired), errors.Is(err, models.ErrPromotionMinSpendNotMet),
+		errors.Is(err, models.ErrInvalidDiscountKind), errors.Is(err, models.ErrInvalidDiscountValue),
+		errors.Is(err, models.ErrInvalidDiscountScope), errors.Is(err, models.ErrDiscountTargetRequired),
+		errors.Is(err, models.ErrDiscountReasonRequired), errors.Is(err, models.ErrDiscountNotApplicable),
+		errors.Is(err, models.ErrCurrencyMismatch), errors.Is(err, models.ErrUnsupportedCurrency),
+		errors.Is(err, models.ErrNegativeAmount), errors.Is(err, models.ErrStoreIDRequired):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreatePromotion は、店舗のプロモーションコードを登録するエンドポイントです。
+func (p *Client) CreatePromotion(c echo.Context) error {
+	req := &RequestPromotion{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind promotion data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	promotion, err := req.ToModel()
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+	promotion, err = p.uc.CreatePromotion(c.Request().Context(), promotion)
+	if err != nil {
+		return responseHandler(c, discountErrorStatus(err), nil, err, "Failed to create promotion: %v", err)
+	}
+
+	return responseHandler(c, http.StatusCreated, NewResponsePromotion(promotion), nil, "Promotion created successfully")
+}
+
+// ListPromotions は、店舗のプロモーションの一覧を取得するエンドポイントです。
+func (p *Client) ListPromotions(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	promotions, err := p.uc.ListPromotions(c.Request().Context(), storeID)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to list promotions: %v", err)
+	}
+
+	responses := make([]*ResponsePromotion, len(promotions))
+	for i, promotion := range promotions {
+		responses[i] = NewResponsePromotion(promotion)
+	}
+	return responseHandler(c, http.StatusOK, responses, nil, "Promotions retrieved successfully")
+}
+
+// DeactivatePromotion は、プロモーションを停止するエンドポイントです。適用済みの割引は取り消されません。
+func (p *Client) DeactivatePromotion(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	promotion, err := p.uc.DeactivatePromotion(c.Request().Context(), storeID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, discountErrorStatus(err), nil, err, "Failed to deactivate promotion: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponsePromotion(promotion), nil, "Promotion deactivated successfully")
+}
+
+// ApplyManualDiscount は、スタッフが理由を添えて注文に割引を適用するエンドポイントです。
+// 適用したスタッフ（またはAPIキー）は割引とあわせて記録されます。
+func (p *Client) ApplyManualDiscount(c echo.Context) error {
+	req := &RequestManualDiscount{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind discount data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	session, _, err := p.uc.ApplyManualDiscount(c.Request().Context(), req.StoreID, req.OrderID, req.Rule, req.Reason, getActor(c))
+	if err != nil {
+		return responseHandler(c, discountErrorStatus(err), nil, err, "Failed to apply discount: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Discount applied successfully")
+}
+
+// RemoveDiscount は、注文に適用した割引を取り消すエンドポイントです。
+func (p *Client) RemoveDiscount(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	orderID := c.QueryParam("order_id")
+	if storeID == "" || orderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+
+	session, err := p.uc.RemoveDiscount(c.Request().Context(), storeID, orderID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, discountErrorStatus(err), nil, err, "Failed to remove discount: %v", err)
+	}
+
+	res, err := NewResponseSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Discount removed successfully")
+}
+
+// RedeemPromotion は、お客様が入力したプロモーションコードを注文に適用するエンドポイントです。
+// 店舗と座席、来店IDはセッションJWTのクレームから取得します。
+func (p *Client) RedeemPromotion(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+
+	req := &RequestRedeemPromotion{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind promotion code: %v", err)
+	}
+	if req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "order_id is required")
+	}
+
+	session, _, err := p.uc.RedeemPromotion(c.Request().Context(), claims.StoreID, claims.SeatID, claims.VisitID, req.OrderID, req.Code)
+	if err != nil {
+		return responseHandler(c, discountErrorStatus(err), nil, err, "Failed to redeem promotion code: %v", err)
+	}
+
+	res, err := NewResponseCustomerSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Promotion code applied successfully")
+}
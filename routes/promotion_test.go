@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestPromotionToModel(t *testing.T) {
+	startsAt := time.Date(2026, 4, 1, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	req := &RequestPromotion{
+		StoreID:     "store_1",
+		Code:        " spring10 ",
+		Name:        "春の10%オフ",
+		Rule:        models.DiscountRule{Kind: models.DiscountPercentage, Value: 10, Scope: models.DiscountScopeOrder},
+		MinSpend:    3000,
+		StartsAt:    &startsAt,
+		MaxPerVisit: 1,
+	}
+
+	promotion, err := req.ToModel()
+	require.NoError(t, err)
+	assert.Equal(t, "SPRING10", promotion.Code)
+	assert.Equal(t, models.Yen(3000), promotion.MinSpend)
+	assert.Equal(t, startsAt.UTC(), promotion.StartsAt)
+	assert.True(t, promotion.EndsAt.IsZero())
+	assert.NoError(t, promotion.Validate())
+
+	req.Currency = "XXX"
+	_, err = req.ToModel()
+	assert.ErrorIs(t, err, models.ErrUnsupportedCurrency)
+}
+
+func TestDiscountErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, discountErrorStatus(models.ErrPromotionNotFound))
+	assert.Equal(t, http.StatusConflict, discountErrorStatus(models.ErrPromotionVisitLimitReached))
+	assert.Equal(t, http.StatusBadRequest, discountErrorStatus(models.ErrDiscountReasonRequired))
+	assert.Equal(t, http.StatusInternalServerError, discountErrorStatus(errors.New("firestore unavailable")))
+}
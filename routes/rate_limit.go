@@ -0,0 +1,147 @@
+package routes
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrTooManyRequests = errors.New("too many requests")
+
+// ルートグループごとの既定のレート制限
+// 環境変数 RATE_LIMIT_PUBLIC, RATE_LIMIT_MANAGER, RATE_LIMIT_SESSION で "回数/期間" 形式に上書きできます。
+const (
+	defaultPublicRateLimit  = "20/1m"
+	defaultManagerRateLimit = "300/1m"
+	defaultSessionRateLimit = "60/1m"
+)
+
+// RateLimitKeyFunc はリクエストからレート制限のキーを導出します。
+// 空文字を返した場合、そのキーでは制限しません。
+type RateLimitKeyFunc func(c echo.Context) string
+
+// rateLimitByIP はクライアントIPをキーにします。
+func rateLimitByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// rateLimitByAccount はマネージャーJWTのメールアドレス、またはAPIキーのIDをキーにします。
+// JWT・APIキー検証ミドルウェアの後に登録する必要があります。
+func rateLimitByAccount(c echo.Context) string {
+	if key := getAPIKey(c); key != nil {
+		return "apikey:" + key.ID
+	}
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	claims, ok := token.Claims.(*models.Claims)
+	if !ok || claims.Email == "" {
+		return ""
+	}
+	return "account:" + claims.Email
+}
+
+// rateLimitBySeat はセッションJWTの店舗IDと座席IDをキーにします。
+// JWT検証ミドルウェアの後に登録する必要があります。
+func rateLimitBySeat(c echo.Context) string {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("seat:%s:%s", claims.StoreID, claims.SeatID)
+}
+
+// rateLimitByIPOutsideStoreNetwork はクエリパラメータ store_id の店舗ネットワーク（許可CIDR）外からのリクエストのみ、クライアントIPをキーにします。
+// 店内の客は店舗のIPを共有するため、店舗ネットワークからのQRコードの読み込みはIP単位で制限しません。
+// 店舗を取得できない場合やネットワーク制限が無効な場合は rateLimitByIP と同じです。
+func (p *Client) rateLimitByIPOutsideStoreNetwork(c echo.Context) string {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return rateLimitByIP(c)
+	}
+	store, err := p.uc.GetStore(c.Request().Context(), storeID)
+	if err != nil || !store.NetworkRestriction.IsEnabled() {
+		return rateLimitByIP(c)
+	}
+	if allowed, err := store.IsAllowedIP(c.RealIP()); err == nil && allowed {
+		return ""
+	}
+	return rateLimitByIP(c)
+}
+
+// loadRateLimitPolicy は環境変数からレート制限のポリシーを読み込みます。
+// 値が不正な場合は既定値を使用します。
+func loadRateLimitPolicy(envKey, fallback string) models.RateLimitPolicy {
+	value, ok := os.LookupEnv(envKey)
+	if !ok {
+		value = fallback
+	}
+
+	policy, err := models.ParseRateLimitPolicy(value)
+	if err != nil {
+		log.Warn().Err(err).Msgf("%s is invalid, fallback to %s", envKey, fallback)
+		policy, _ = models.ParseRateLimitPolicy(fallback)
+	}
+	return policy
+}
+
+// rateLimitMiddleware はキーごとの固定ウィンドウ方式でリクエスト数を制限するミドルウェアを返します。
+// scope はルートグループを区別するためにキーへ付与されます。
+// いずれかのキーで上限を超えた場合は 429 Too Many Requests と Retry-After ヘッダーを返します。
+// ストアへのアクセスに失敗した場合はリクエストを通します（可用性を優先）。
+func rateLimitMiddleware(store repositories.RateLimitStore, scope string, policy models.RateLimitPolicy, keyFuncs ...RateLimitKeyFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !policy.IsEnabled() {
+				return next(c)
+			}
+
+			now := time.Now().UTC()
+			remaining := policy.Limit
+			for _, keyFunc := range keyFuncs {
+				key := keyFunc(c)
+				if key == "" {
+					continue
+				}
+
+				count, resetAt, err := store.Increment(c.Request().Context(), scope+":"+key, policy.Window, now)
+				if err != nil {
+					log.Error().Err(err).Msgf("failed to increment rate limit counter: %s", key)
+					continue
+				}
+
+				if count > policy.Limit {
+					setRetryAfter(c, resetAt.Sub(now))
+					c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(policy.Limit))
+					c.Response().Header().Set("X-RateLimit-Remaining", "0")
+					return responseHandler(c, http.StatusTooManyRequests, nil, ErrTooManyRequests, "Rate limit exceeded")
+				}
+				remaining = min(remaining, policy.Limit-count)
+			}
+
+			c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(policy.Limit))
+			c.Response().Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			return next(c)
+		}
+	}
+}
+
+// setRetryAfter は Retry-After ヘッダーを秒単位（切り上げ）で設定します。
+func setRetryAfter(c echo.Context, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(seconds))
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"backend/usecases"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+
+	t.Run("上限を超えると429とRetry-Afterを返す", func(t *testing.T) {
+		e := echo.New()
+		store := repositories.NewMemoryRateLimitStore()
+		policy := models.RateLimitPolicy{Limit: 2, Window: time.Minute}
+		handler := rateLimitMiddleware(store, "public", policy, rateLimitByIP)(ok)
+
+		codes := []int{}
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/public/signin", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			rec := httptest.NewRecorder()
+			assert.NoError(t, handler(e.NewContext(req, rec)))
+			codes = append(codes, rec.Code)
+
+			if i == 2 {
+				assert.NotEmpty(t, rec.Header().Get(echo.HeaderRetryAfter))
+				assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+			}
+		}
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	})
+
+	t.Run("座席ごとに独立して制限する", func(t *testing.T) {
+		e := echo.New()
+		store := repositories.NewMemoryRateLimitStore()
+		policy := models.RateLimitPolicy{Limit: 1, Window: time.Minute}
+		handler := rateLimitMiddleware(store, "session", policy, rateLimitBySeat)(ok)
+
+		request := func(seatID string) int {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/private/session/order", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", &jwt.Token{Claims: &models.SessionClaims{StoreID: "store_1", SeatID: seatID}})
+			assert.NoError(t, handler(c))
+			return rec.Code
+		}
+
+		assert.Equal(t, http.StatusOK, request("seat_1"))
+		assert.Equal(t, http.StatusTooManyRequests, request("seat_1"))
+		assert.Equal(t, http.StatusOK, request("seat_2"))
+	})
+
+	t.Run("無効なポリシーでは制限しない", func(t *testing.T) {
+		e := echo.New()
+		handler := rateLimitMiddleware(repositories.NewMemoryRateLimitStore(), "public", models.RateLimitPolicy{}, rateLimitByIP)(ok)
+
+		for i := 0; i < 5; i++ {
+			rec := httptest.NewRecorder()
+			assert.NoError(t, handler(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+}
+
+func TestRateLimitByIPOutsideStoreNetwork(t *testing.T) {
+	e := echo.New()
+	p := &Client{uc: usecases.New(nil)}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/public/session", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	c := e.NewContext(req, httptest.NewRecorder())
+	assert.Equal(t, "ip:192.0.2.1", p.rateLimitByIPOutsideStoreNetwork(c), "store_id がない場合はIP単位")
+}
+
+func TestRateLimitByAccount(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, "", rateLimitByAccount(c), "JWTがない場合は空文字")
+
+	c.Set("user", &jwt.Token{Claims: &models.Claims{Email: "manager@example.com"}})
+	assert.Equal(t, "account:manager@example.com", rateLimitByAccount(c))
+}
+
+func TestLoadRateLimitPolicy(t *testing.T) {
+	t.Setenv("RATE_LIMIT_TEST", "3/10s")
+	assert.Equal(t, models.RateLimitPolicy{Limit: 3, Window: 10 * time.Second}, loadRateLimitPolicy("RATE_LIMIT_TEST", "1/1m"))
+
+	t.Setenv("RATE_LIMIT_TEST", "invalid")
+	assert.Equal(t, models.RateLimitPolicy{Limit: 1, Window: time.Minute}, loadRateLimitPolicy("RATE_LIMIT_TEST", "1/1m"))
+}
@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestStoreReasonCodes の codes は店舗の理由コードの全件で、既存の設定を置き換えます。空の配列の場合は既定の一覧に戻します。
+// kind は "cancel"（キャンセル）、"decline"（辞退）、"hold"（保留）、"void"（明細の取り消し）のいずれかです。
+// 過去の記録を集計できるよう、使用しなくなったコードは削除せず active を false にしてください。
+type RequestStoreReasonCodes struct {
+	StoreID string              `json:"store_id"`
+	Codes   []models.ReasonCode `json:"codes"`
+}
+
+// reasonCodeErrorStatus は理由コードの設定・集計で発生したエラーに対応するHTTPステータスを返します。
+func reasonCodeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidReasonCode), errors.Is(err, models.ErrInvalidBusinessDay):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListReasonCodes は、店舗の理由コードの一覧を取得するためのエンドポイントです。
+func (p *Client) ListReasonCodes(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	codes, err := p.uc.ListReasonCodes(c.Request().Context(), storeID)
+	if err != nil {
+		return responseHandler(c, reasonCodeErrorStatus(err), nil, err, "Failed to list reason codes: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id": storeID,
+		"codes":    codes,
+	}, nil, "Reason codes retrieved successfully")
+}
+
+// UpdateStoreReasonCodes は、キャンセル・辞退・保留・明細の取り消しの理由コードを設定するためのエンドポイントです。
+func (p *Client) UpdateStoreReasonCodes(c echo.Context) error {
+	req := &RequestStoreReasonCodes{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind reason code data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreReasonCodes(c.Request().Context(), req.StoreID, req.Codes)
+	if err != nil {
+		return responseHandler(c, reasonCodeErrorStatus(err), nil, err, "Failed to update store reason codes: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id": store.ID,
+		"codes":    store.ReasonCodeCatalog(),
+	}, nil, "Store reason codes updated successfully")
+}
+
+// GetReasonReport は、営業日 from から to まで（両端を含む）のキャンセル・辞退・保留・明細の取り消しの理由を
+// 理由コードごとの件数と金額で集計するエンドポイントです。to を省略した場合は from の1日分です。
+func (p *Client) GetReasonReport(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	from := c.QueryParam("from")
+	if storeID == "" || from == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and from are required")
+	}
+	to := c.QueryParam("to")
+	if to == "" {
+		to = from
+	}
+
+	report, err := p.uc.GetReasonReport(c.Request().Context(), storeID, from, to)
+	if err != nil {
+		return responseHandler(c, reasonCodeErrorStatus(err), nil, err, "Failed to get reason report: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, report, nil, "Reason report retrieved successfully")
+}
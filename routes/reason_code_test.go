@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReasonCodeErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, reasonCodeErrorStatus(fmt.Errorf("%w: コード \"expired\" (codes[0])", models.ErrInvalidReasonCode)))
+	assert.Equal(t, http.StatusBadRequest, reasonCodeErrorStatus(models.ErrInvalidBusinessDay))
+	assert.Equal(t, http.StatusInternalServerError, reasonCodeErrorStatus(errors.New("firestore unavailable")))
+}
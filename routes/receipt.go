@@ -0,0 +1,161 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 領収書の出力形式
+const (
+	receiptFormatJSON = "json"
+	receiptFormatText = "text"
+	receiptFormatPDF  = "pdf"
+)
+
+var ErrInvalidReceiptFormat = errors.New("format must be one of json, text, pdf")
+
+type RequestReceipt struct {
+	StoreID       string `json:"store_id"`
+	SessionID     string `json:"session_id"`
+	RecipientName string `json:"recipient_name"`
+}
+
+type RequestReissueReceipt struct {
+	StoreID string `json:"store_id"`
+}
+
+// receiptErrorStatus は領収書の発行・取得で発生したエラーに対応するHTTPステータスを返します。
+func receiptErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrReceiptNotFound), errors.Is(err, models.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrRecipientNameRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrReceiptAlreadyIssued), errors.Is(err, models.ErrSessionNotCompleted),
+		errors.Is(err, models.ErrInvoiceRegistrationNumberRequired), errors.Is(err, models.ErrInvalidInvoiceRegistrationNumber):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// renderReceipt は format クエリパラメータに応じて、領収書をJSON、サーマルプリンタ向けテキスト、PDFのいずれかで返します。
+// テキストの場合は width クエリパラメータで1行の文字数（半角換算）を指定できます。
+func renderReceipt(c echo.Context, status int, receipt *models.Receipt, message string) error {
+	switch format := c.QueryParam("format"); format {
+	case "", receiptFormatJSON:
+		return responseHandler(c, status, receipt, nil, "%s", message)
+	case receiptFormatText:
+		width := models.ReceiptTextWidth
+		if v := c.QueryParam("width"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid width: %s", v)
+			}
+			width = n
+		}
+		return c.Blob(status, "text/plain; charset=utf-8", []byte(receipt.RenderText(width)))
+	case receiptFormatPDF:
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="receipt-%s.pdf"`, receipt.DisplayNumber()))
+		return c.Blob(status, "application/pdf", receipt.RenderPDF())
+	default:
+		return responseHandler(c, http.StatusBadRequest, nil, ErrInvalidReceiptFormat, "Invalid format: %s", format)
+	}
+}
+
+// IssueReceipt は、会計が完了した注文の適格請求書（領収書）を発行するエンドポイントです。
+// 1つの注文につき発行は1回のみで、2回目以降は再発行のエンドポイントを使用します。
+func (p *Client) IssueReceipt(c echo.Context) error {
+	req := &RequestReceipt{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind receipt data: %v", err)
+	}
+	if req.StoreID == "" || req.SessionID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and session_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	receipt, err := p.uc.IssueReceipt(c.Request().Context(), req.StoreID, req.SessionID, req.RecipientName)
+	if err != nil {
+		return responseHandler(c, receiptErrorStatus(err), nil, err, "Failed to issue receipt: %v", err)
+	}
+
+	return renderReceipt(c, http.StatusCreated, receipt, "Receipt issued successfully")
+}
+
+// GetReceipt は、発行済みの領収書を取得するエンドポイントです。
+func (p *Client) GetReceipt(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id is required")
+	}
+
+	receipt, err := p.uc.GetReceipt(c.Request().Context(), storeID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, receiptErrorStatus(err), nil, err, "Failed to get receipt: %v", err)
+	}
+
+	return renderReceipt(c, http.StatusOK, receipt, "Receipt retrieved successfully")
+}
+
+// ReissueReceipt は、発行済みの領収書を「再発行」として出力するエンドポイントです。
+func (p *Client) ReissueReceipt(c echo.Context) error {
+	req := &RequestReissueReceipt{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind receipt data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	receipt, err := p.uc.ReissueReceipt(c.Request().Context(), req.StoreID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, receiptErrorStatus(err), nil, err, "Failed to reissue receipt: %v", err)
+	}
+
+	return renderReceipt(c, http.StatusOK, receipt, "Receipt reissued successfully")
+}
+
+type RequestStoreInvoice struct {
+	StoreID            string `json:"store_id"`
+	RegistrationNumber string `json:"registration_number"`
+}
+
+// UpdateStoreInvoice は、店舗の適格請求書発行事業者の登録番号を設定するためのエンドポイントです。
+func (p *Client) UpdateStoreInvoice(c echo.Context) error {
+	req := &RequestStoreInvoice{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind store invoice data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	store, err := p.uc.UpdateStoreInvoiceRegistration(c.Request().Context(), req.StoreID, req.RegistrationNumber)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidInvoiceRegistrationNumber) || errors.Is(err, models.ErrInvoiceRegistrationNumberRequired) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to update store invoice: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{
+		"store_id":            store.ID,
+		"registration_number": store.InvoiceRegistrationNumber,
+	}, nil, "Store invoice registration updated successfully")
+}
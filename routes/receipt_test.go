@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"backend/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderReceipt(t *testing.T) {
+	receipt := &models.Receipt{
+		ID:            "receipt_1",
+		Number:        42,
+		Issuer:        models.ReceiptIssuer{Name: "テスト食堂", RegistrationNumber: "T1234567890123"},
+		RecipientName: "株式会社テスト",
+		Total:         models.Yen(1100),
+	}
+
+	render := func(query string) *httptest.ResponseRecorder {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/private/manager/store/receipt/receipt_1?"+query, nil), rec)
+		assert.NoError(t, renderReceipt(c, http.StatusOK, receipt, "ok"))
+		return rec
+	}
+
+	t.Run("既定はJSON", func(t *testing.T) {
+		rec := render("")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+		assert.Contains(t, rec.Body.String(), `"registration_number":"T1234567890123"`)
+	})
+
+	t.Run("サーマルプリンタ向けテキスト", func(t *testing.T) {
+		rec := render("format=text&width=48")
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "登録番号 T1234567890123")
+		assert.Contains(t, rec.Body.String(), strings.Repeat("-", 48))
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		rec := render("format=pdf")
+		assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "receipt-00000042.pdf")
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-"))
+	})
+
+	t.Run("不正な形式", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, render("format=xml").Code)
+		assert.Equal(t, http.StatusBadRequest, render("format=text&width=abc").Code)
+	})
+}
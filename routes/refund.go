@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestRefund の amount は補助単位（円、セント等）の整数です。
+// line_ids を指定した場合は明細行の金額を返金し、full が true の場合は返金可能な残りの金額を全て返金します。
+type RequestRefund struct {
+	StoreID    string   `json:"store_id"`
+	OrderID    string   `json:"order_id"`
+	Amount     int64    `json:"amount"`
+	Currency   string   `json:"currency"`
+	LineIDs    []string `json:"line_ids"`
+	Full       bool     `json:"full"`
+	Reason     string   `json:"reason"`
+	PaymentRef string   `json:"payment_ref"`
+}
+
+// ToModel は、リクエストをmodels.RefundRequestに変換します。
+func (r *RequestRefund) ToModel(actor string) (models.RefundRequest, error) {
+	currency, err := models.ParseCurrency(r.Currency)
+	if err != nil {
+		return models.RefundRequest{}, err
+	}
+	return models.RefundRequest{
+		Amount:     models.NewMoney(r.Amount, currency),
+		LineIDs:    r.LineIDs,
+		Reason:     r.Reason,
+		Actor:      actor,
+		PaymentRef: r.PaymentRef,
+	}, nil
+}
+
+type ResponseRefunds struct {
+	OrderID          string               `json:"order_id"`
+	Status           models.Status        `json:"status"`
+	PaymentStatus    models.PaymentStatus `json:"payment_status"`
+	TotalAmount      models.Money         `json:"total_amount"`
+	RefundedAmount   models.Money         `json:"refunded_amount"`
+	RefundableAmount models.Money         `json:"refundable_amount"`
+	Refunds          []models.Refund      `json:"refunds"`
+}
+
+// NewResponseRefunds は、注文の返金の履歴をResponseRefundsに変換します。
+func NewResponseRefunds(session *models.Session) *ResponseRefunds {
+	refunds := session.Refunds
+	if refunds == nil {
+		refunds = []models.Refund{}
+	}
+	return &ResponseRefunds{
+		OrderID:          session.ID,
+		Status:           session.Status,
+		PaymentStatus:    session.PaymentStatus,
+		TotalAmount:      session.TotalAmount,
+		RefundedAmount:   session.RefundedAmount(),
+		RefundableAmount: session.RefundableAmount(),
+		Refunds:          refunds,
+	}
+}
+
+// refundErrorStatus は返金の操作で発生したエラーに対応するHTTPステータスを返します。
+func refundErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrOrderNotFound), errors.Is(err, models.ErrOrderLineNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrRefundNotAllowed), errors.Is(err, models.ErrOrderAlreadyFullyRefunded),
+		errors.Is(err, models.ErrRefundLineAlreadyRefunded), errors.Is(err, models.ErrRefundAmountExceedsTotal),
+		errors.Is(err, models.ErrBusinessDayClosed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrRefundReasonRequired), errors.Is(err, models.ErrInvalidRefundAmount),
+		errors.Is(err, models.ErrRefundAmountLinesMismatch), errors.Is(err, models.ErrNegativeAmount),
+		errors.Is(err, models.ErrCurrencyMismatch), errors.Is(err, models.ErrUnsupportedCurrency):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RefundOrder は、注文の返金を記録するエンドポイントです。
+// 返金のたびに返金台帳に記録され、返金済みの合計が支払額に達すると注文は全額返金済みになります。
+func (p *Client) RefundOrder(c echo.Context) error {
+	req := &RequestRefund{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind refund data: %v", err)
+	}
+	if req.StoreID == "" || req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	refundReq, err := req.ToModel(getActor(c))
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+	session, _, err := p.uc.RefundOrder(c.Request().Context(), req.StoreID, req.OrderID, refundReq, req.Full)
+	if err != nil {
+		return responseHandler(c, refundErrorStatus(err), nil, err, "Failed to refund order: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseRefunds(session), nil, "Refund recorded successfully")
+}
+
+// ListOrderRefunds は、注文の返金の履歴を取得するエンドポイントです。
+func (p *Client) ListOrderRefunds(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	orderID := c.QueryParam("order_id")
+	if storeID == "" || orderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and order_id are required")
+	}
+
+	session, err := p.uc.GetOrderRefunds(c.Request().Context(), storeID, orderID)
+	if err != nil {
+		return responseHandler(c, refundErrorStatus(err), nil, err, "Failed to get refunds: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseRefunds(session), nil, "Refunds retrieved successfully")
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestRefundToModel(t *testing.T) {
+	req := &RequestRefund{Amount: 500, Reason: "提供遅れ", PaymentRef: "re_1"}
+	refund, err := req.ToModel("manager:a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, models.Yen(500), refund.Amount)
+	assert.Equal(t, "manager:a@example.com", refund.Actor)
+}
+
+func TestRefundErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusConflict, refundErrorStatus(fmt.Errorf("%w: 返金可能な残額は 0", models.ErrRefundAmountExceedsTotal)))
+	assert.Equal(t, http.StatusBadRequest, refundErrorStatus(models.ErrRefundReasonRequired))
+	assert.Equal(t, http.StatusNotFound, refundErrorStatus(models.ErrOrderLineNotFound))
+	assert.Equal(t, http.StatusInternalServerError, refundErrorStatus(errors.New("firestore unavailable")))
+}
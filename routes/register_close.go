@@ -0,0 +1,136 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ErrInvalidReportFormat = errors.New("format must be one of json, text")
+
+// RequestRegisterClose の金額は補助単位（円、セント等）の整数です。
+// business_day（YYYY-MM-DD）を省略した場合は現在の営業日を締めます。
+type RequestRegisterClose struct {
+	StoreID      string `json:"store_id"`
+	BusinessDay  string `json:"business_day"`
+	Currency     string `json:"currency"`
+	OpeningFloat int64  `json:"opening_float"`
+	CountedCash  int64  `json:"counted_cash"`
+	Note         string `json:"note"`
+}
+
+type ResponseRegisterClose struct {
+	ID           string          `json:"id"`
+	StoreID      string          `json:"store_id"`
+	BusinessDay  string          `json:"business_day"`
+	OpeningFloat models.Money    `json:"opening_float"`
+	CashSales    models.Money    `json:"cash_sales"`
+	CashRefunds  models.Money    `json:"cash_refunds"`
+	ExpectedCash models.Money    `json:"expected_cash"`
+	CountedCash  models.Money    `json:"counted_cash"`
+	OverShort    models.Money    `json:"over_short"`
+	Report       *models.ZReport `json:"report"`
+	Note         string          `json:"note,omitempty"`
+	Actor        string          `json:"actor,omitempty"`
+	ClosedAt     time.Time       `json:"closed_at"`
+}
+
+// NewResponseRegisterClose は、models.RegisterCloseをResponseRegisterCloseに変換します。
+func NewResponseRegisterClose(closed *models.RegisterClose) *ResponseRegisterClose {
+	return &ResponseRegisterClose{
+		ID:           closed.ID,
+		StoreID:      closed.StoreID,
+		BusinessDay:  closed.BusinessDay,
+		OpeningFloat: closed.OpeningFloat,
+		CashSales:    closed.CashSales,
+		CashRefunds:  closed.CashRefunds,
+		ExpectedCash: closed.ExpectedCash,
+		CountedCash:  closed.CountedCash,
+		OverShort:    closed.OverShort,
+		Report:       &closed.Report,
+		Note:         closed.Note,
+		Actor:        closed.Actor,
+		ClosedAt:     closed.ClosedAt,
+	}
+}
+
+// registerCloseErrorStatus はレジ締めで発生したエラーに対応するHTTPステータスを返します。
+func registerCloseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrRegisterCloseNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrBusinessDayClosed), errors.Is(err, models.ErrBusinessDayNotStarted):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidBusinessDay), errors.Is(err, models.ErrInvalidCountedCash),
+		errors.Is(err, models.ErrCurrencyMismatch), errors.Is(err, models.ErrUnsupportedCurrency):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// renderRegisterClose は format クエリパラメータに応じて、レジ締めの記録をJSONまたはサーマルプリンタ向けテキストのZレポートで返します。
+// テキストの場合は width クエリパラメータで1行の文字数（半角換算）を指定できます。
+func renderRegisterClose(c echo.Context, status int, closed *models.RegisterClose, message string) error {
+	switch format := c.QueryParam("format"); format {
+	case "", receiptFormatJSON:
+		return responseHandler(c, status, NewResponseRegisterClose(closed), nil, "%s", message)
+	case receiptFormatText:
+		width := models.ReceiptTextWidth
+		if v := c.QueryParam("width"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid width: %s", v)
+			}
+			width = n
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="z-report-%s.txt"`, closed.BusinessDay))
+		return c.Blob(status, "text/plain; charset=utf-8", []byte(closed.RenderText(width)))
+	default:
+		return responseHandler(c, http.StatusBadRequest, nil, ErrInvalidReportFormat, "Invalid format: %s", format)
+	}
+}
+
+// CloseRegister は、営業日のレジ締めを行うエンドポイントです。
+// 営業日の売上をZレポートに集計し、現金の実査額と理論在高の過不足を記録します。締めた営業日は精算・返金を受け付けません。
+func (p *Client) CloseRegister(c echo.Context) error {
+	req := &RequestRegisterClose{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind register close data: %v", err)
+	}
+	if req.StoreID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+	currency, err := models.ParseCurrency(req.Currency)
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+
+	closed, err := p.uc.CloseBusinessDay(c.Request().Context(), req.StoreID, req.BusinessDay,
+		models.NewMoney(req.OpeningFloat, currency), models.NewMoney(req.CountedCash, currency), getActor(c), req.Note)
+	if err != nil {
+		return responseHandler(c, registerCloseErrorStatus(err), nil, err, "Failed to close register: %v", err)
+	}
+
+	return renderRegisterClose(c, http.StatusCreated, closed, "Register closed successfully")
+}
+
+// GetRegisterClose は、営業日のレジ締めの記録（Zレポート）を取得するエンドポイントです。
+func (p *Client) GetRegisterClose(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	if storeID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, models.ErrStoreIDRequired, "Store ID is required")
+	}
+
+	closed, err := p.uc.GetRegisterClose(c.Request().Context(), storeID, c.QueryParam("business_day"))
+	if err != nil {
+		return responseHandler(c, registerCloseErrorStatus(err), nil, err, "Failed to get register close: %v", err)
+	}
+
+	return renderRegisterClose(c, http.StatusOK, closed, "Register close retrieved successfully")
+}
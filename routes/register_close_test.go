@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCloseErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, registerCloseErrorStatus(models.ErrRegisterCloseNotFound))
+	assert.Equal(t, http.StatusConflict, registerCloseErrorStatus(models.ErrBusinessDayClosed))
+	assert.Equal(t, http.StatusBadRequest, registerCloseErrorStatus(models.ErrInvalidBusinessDay))
+	assert.Equal(t, http.StatusInternalServerError, registerCloseErrorStatus(errors.New("firestore unavailable")))
+}
+
+func TestRenderRegisterClose(t *testing.T) {
+	report := &models.ZReport{StoreID: "store_1", StoreName: "テスト店", BusinessDay: "2026-10-17", GrossSales: models.Yen(2200)}
+	closed, err := models.NewRegisterClose(report, models.Yen(10000), models.Yen(10000), "manager:a@example.com", "", time.Now())
+	require.NoError(t, err)
+
+	render := func(query string) *httptest.ResponseRecorder {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), rec)
+		require.NoError(t, renderRegisterClose(c, http.StatusOK, closed, "ok"))
+		return rec
+	}
+
+	rec := render("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"business_day":"2026-10-17"`)
+
+	rec = render("format=text")
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/plain"))
+	assert.Contains(t, rec.Body.String(), "営業日 2026-10-17")
+
+	rec = render("format=pdf")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
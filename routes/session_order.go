@@ -0,0 +1,487 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestOrderItem の product_id は店舗のメニューの商品IDです。単価・通貨・カテゴリ・消費税区分はメニューから決定します。
+// instructions は厨房への特別な指示（「ネギ抜き」など）です。
+type RequestOrderItem struct {
+	ProductID    string `json:"product_id"`
+	Quantity     int    `json:"quantity"`
+	Instructions string `json:"instructions"`
+}
+
+// RequestOrder の店内飲食・持ち帰りの区分は店舗の設定に従い、お客様は指定できません。
+// 人数分のチャージ（お通し代など）は、スタッフが登録した来店人数で計算します。
+type RequestOrder struct {
+	Items []RequestOrderItem `json:"items"`
+}
+
+func (r *RequestOrder) IsValidate() error {
+	if len(r.Items) == 0 {
+		return models.ErrNoItems
+	}
+	for i, item := range r.Items {
+		if item.ProductID == "" || item.Quantity <= 0 {
+			return fmt.Errorf("invalid item at index %d", i)
+		}
+		if item.Quantity > models.MaxItemQuantity {
+			return fmt.Errorf("%w at index %d", models.ErrInvalidItemQuantity, i)
+		}
+		if utf8.RuneCountInString(strings.TrimSpace(item.Instructions)) > models.MaxItemInstructionsLength {
+			return fmt.Errorf("%w at index %d", models.ErrItemInstructionsTooLong, i)
+		}
+	}
+	return nil
+}
+
+// ToModels は、リクエストの商品をmodels.Orderに変換します。
+// 単価は未設定で、注文の作成時に店舗のメニューから設定します。
+func (r *RequestOrder) ToModels() []models.Order {
+	items := make([]models.Order, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = *models.NewOrder(item.ProductID, item.Quantity, models.Money{}).WithInstructions(item.Instructions)
+	}
+	return items
+}
+
+type ResponseOrder struct {
+	OrderID      string             `json:"order_id"`
+	LineID       string             `json:"line_id"`
+	ProductID    string             `json:"product_id"`
+	Category     string             `json:"category,omitempty"`
+	Quantity     int                `json:"quantity"`
+	Price        models.Money       `json:"price"`
+	Subtotal     models.Money       `json:"subtotal"`
+	TaxCategory  models.TaxCategory `json:"tax_category,omitempty"`
+	Instructions string             `json:"instructions,omitempty"`
+
+	Status         models.LineStatus           `json:"status"`
+	Adjustments    []models.QuantityAdjustment `json:"adjustments,omitempty"`
+	VoidReasonCode string                      `json:"void_reason_code,omitempty"`
+	VoidReason     string                      `json:"void_reason,omitempty"`
+	VoidedAt       *time.Time                  `json:"voided_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ResponseSession はスタッフ向けの注文です。操作したスタッフや取消・返金の理由を含むため、管理者のエンドポイントでのみ返します。
+// お客様には ResponseCustomerSession を返します。
+type ResponseSession struct {
+	ID              string                       `json:"id"`
+	StoreID         string                       `json:"store_id"`
+	SeatID          string                       `json:"seat_id"`
+	Items           []ResponseOrder              `json:"items"`
+	Subtotal        models.Money                 `json:"subtotal"`
+	Discounts       []models.Discount            `json:"discounts"`
+	DiscountTotal   models.Money                 `json:"discount_total"`
+	PartySize       int                          `json:"party_size,omitempty"`
+	Charges         []models.Charge              `json:"charges"`
+	ChargeTotal     models.Money                 `json:"charge_total"`
+	TotalAmount     models.Money                 `json:"total_amount"`
+	Refunds         []models.Refund              `json:"refunds"`
+	RefundedTotal   models.Money                 `json:"refunded_total"`
+	DiningOption    models.DiningOption          `json:"dining_option"`
+	PriceMode       models.PriceMode             `json:"price_mode"`
+	Taxes           []models.TaxLine             `json:"taxes"`
+	TaxTotal        models.Money                 `json:"tax_total"`
+	Status          models.Status                `json:"status"`
+	Workflow        string                       `json:"workflow"`
+	NextStatuses    []models.Status              `json:"next_statuses"`
+	Timeline        []models.StatusChange        `json:"timeline"`
+	PaymentStatus   models.PaymentStatus         `json:"payment_status"`
+	PaymentTimeline []models.PaymentStatusChange `json:"payment_timeline"`
+	Durations       ResponseStageDurations       `json:"durations"`
+	NeedsReview     bool                         `json:"needs_review"`
+	ReviewReason    string                       `json:"review_reason,omitempty"`
+	ExpiresAt       time.Time                    `json:"expires_at"`
+	CreatedAt       time.Time                    `json:"created_at"`
+	UpdatedAt       time.Time                    `json:"updated_at"`
+}
+
+// ResponseStageDurations は注文の各段階の所要時間（秒）です。まだ到達していない段階は省略します。
+type ResponseStageDurations struct {
+	TimeToConfirm *int64 `json:"time_to_confirm,omitempty"`
+	PrepTime      *int64 `json:"prep_time,omitempty"`
+	TimeToServe   *int64 `json:"time_to_serve,omitempty"`
+}
+
+// NewResponseStageDurations は、models.StageDurationsを秒単位のResponseStageDurationsに変換します。
+func NewResponseStageDurations(d models.StageDurations) ResponseStageDurations {
+	seconds := func(v time.Duration) *int64 {
+		if v <= 0 {
+			return nil
+		}
+		s := int64(v / time.Second)
+		return &s
+	}
+	return ResponseStageDurations{
+		TimeToConfirm: seconds(d.TimeToConfirm),
+		PrepTime:      seconds(d.PrepTime),
+		TimeToServe:   seconds(d.TimeToServe),
+	}
+}
+
+// NewResponseSession は、models.SessionをResponseSessionに変換します。
+func NewResponseSession(session *models.Session) (*ResponseSession, error) {
+	items := make([]ResponseOrder, len(session.Items))
+	for i, item := range session.Items {
+		subtotal, err := item.Subtotal()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = ResponseOrder{
+			OrderID:        item.OrderID,
+			LineID:         item.LineID,
+			ProductID:      item.ProductID,
+			Category:       item.Category,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Subtotal:       subtotal,
+			TaxCategory:    item.TaxCategory,
+			Instructions:   item.Instructions,
+			Status:         item.LineStatus(),
+			Adjustments:    item.Adjustments,
+			VoidReasonCode: item.VoidReasonCode,
+			VoidReason:     item.VoidReason,
+			VoidedAt:       optionalTime(item.VoidedAt),
+			CreatedAt:      item.CreatedAt,
+			UpdatedAt:      item.UpdatedAt,
+		}
+	}
+
+	subtotal, err := session.Subtotal()
+	if err != nil {
+		return nil, err
+	}
+
+	// 最終状態の注文は通常の遷移ができないため、遷移先を空にする
+	workflow := session.Workflow()
+	nextStatuses := []models.Status{}
+	if !workflow.IsFinal(session.Status) {
+		nextStatuses = append(nextStatuses, workflow.NextStatuses(session.Status)...)
+	}
+
+	return &ResponseSession{
+		ID:              session.ID,
+		StoreID:         session.StoreID,
+		SeatID:          session.SeatID,
+		Items:           items,
+		Subtotal:        subtotal,
+		Discounts:       session.Discounts,
+		DiscountTotal:   session.DiscountTotal(),
+		PartySize:       session.PartySize,
+		Charges:         session.Charges,
+		ChargeTotal:     session.ChargeTotal(),
+		Refunds:         session.Refunds,
+		RefundedTotal:   session.RefundedAmount(),
+		TotalAmount:     session.TotalAmount,
+		DiningOption:    session.DiningOption,
+		PriceMode:       session.TaxPolicy.PriceMode,
+		Taxes:           session.Taxes,
+		TaxTotal:        session.TaxTotal,
+		Status:          session.Status,
+		Workflow:        workflow.Name(),
+		NextStatuses:    nextStatuses,
+		Timeline:        session.Timeline(),
+		PaymentStatus:   session.PaymentStatus,
+		PaymentTimeline: session.PaymentTimeline(),
+		Durations:       NewResponseStageDurations(session.StageDurations()),
+		NeedsReview:     session.NeedsReview,
+		ReviewReason:    session.ReviewReason,
+		ExpiresAt:       session.ExpiresAt,
+		CreatedAt:       session.CreatedAt,
+		UpdatedAt:       session.UpdatedAt,
+	}, nil
+}
+
+// NewResponseSessions は、models.Sessionの一覧をResponseSessionの一覧に変換します。
+func NewResponseSessions(sessions []*models.Session) ([]*ResponseSession, error) {
+	responses := make([]*ResponseSession, len(sessions))
+	for i, session := range sessions {
+		res, err := NewResponseSession(session)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = res
+	}
+	return responses, nil
+}
+
+// ResponseCustomerOrder はお客様向けの注文の商品です。取消の理由や数量の調整の履歴は含めません。
+type ResponseCustomerOrder struct {
+	OrderID      string             `json:"order_id"`
+	LineID       string             `json:"line_id"`
+	ProductID    string             `json:"product_id"`
+	Category     string             `json:"category,omitempty"`
+	Quantity     int                `json:"quantity"`
+	Price        models.Money       `json:"price"`
+	Subtotal     models.Money       `json:"subtotal"`
+	TaxCategory  models.TaxCategory `json:"tax_category,omitempty"`
+	Instructions string             `json:"instructions,omitempty"`
+	Status       models.LineStatus  `json:"status"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// ResponseCustomerDiscount はお客様向けの割引です。手動割引の理由と適用したスタッフは含めません。
+type ResponseCustomerDiscount struct {
+	ID        string                `json:"id"`
+	Source    models.DiscountSource `json:"source"`
+	Code      string                `json:"code,omitempty"`
+	Amount    models.Money          `json:"amount"`
+	AppliedAt time.Time             `json:"applied_at"`
+}
+
+// ResponseCustomerCharge はお客様向けのチャージです。免除の理由と免除したスタッフは含めません。
+type ResponseCustomerCharge struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Kind     models.ChargeKind `json:"kind"`
+	Quantity int               `json:"quantity"`
+	Amount   models.Money      `json:"amount"`
+	Waived   bool              `json:"waived"`
+}
+
+// ResponseCustomerSession はお客様向けの注文です。
+// ステータスの履歴、操作したスタッフ、取消・返金・免除の理由、スタッフ確認の理由は含めません。
+type ResponseCustomerSession struct {
+	ID            string                     `json:"id"`
+	StoreID       string                     `json:"store_id"`
+	SeatID        string                     `json:"seat_id"`
+	Items         []ResponseCustomerOrder    `json:"items"`
+	Subtotal      models.Money               `json:"subtotal"`
+	Discounts     []ResponseCustomerDiscount `json:"discounts"`
+	DiscountTotal models.Money               `json:"discount_total"`
+	PartySize     int                        `json:"party_size,omitempty"`
+	Charges       []ResponseCustomerCharge   `json:"charges"`
+	ChargeTotal   models.Money               `json:"charge_total"`
+	TotalAmount   models.Money               `json:"total_amount"`
+	RefundedTotal models.Money               `json:"refunded_total"`
+	DiningOption  models.DiningOption        `json:"dining_option"`
+	PriceMode     models.PriceMode           `json:"price_mode"`
+	Taxes         []models.TaxLine           `json:"taxes"`
+	TaxTotal      models.Money               `json:"tax_total"`
+	Status        models.Status              `json:"status"`
+	PaymentStatus models.PaymentStatus       `json:"payment_status"`
+	ExpiresAt     time.Time                  `json:"expires_at"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	UpdatedAt     time.Time                  `json:"updated_at"`
+}
+
+// NewResponseCustomerCharges は、models.Chargeの一覧をResponseCustomerChargeの一覧に変換します。
+func NewResponseCustomerCharges(charges []models.Charge) []ResponseCustomerCharge {
+	responses := make([]ResponseCustomerCharge, len(charges))
+	for i, charge := range charges {
+		responses[i] = ResponseCustomerCharge{
+			ID:       charge.ID,
+			Name:     charge.Name,
+			Kind:     charge.Kind,
+			Quantity: charge.Quantity,
+			Amount:   charge.Amount,
+			Waived:   charge.Waived,
+		}
+	}
+	return responses
+}
+
+// NewResponseCustomerSession は、models.SessionをResponseCustomerSessionに変換します。
+func NewResponseCustomerSession(session *models.Session) (*ResponseCustomerSession, error) {
+	items := make([]ResponseCustomerOrder, len(session.Items))
+	for i, item := range session.Items {
+		subtotal, err := item.Subtotal()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = ResponseCustomerOrder{
+			OrderID:      item.OrderID,
+			LineID:       item.LineID,
+			ProductID:    item.ProductID,
+			Category:     item.Category,
+			Quantity:     item.Quantity,
+			Price:        item.Price,
+			Subtotal:     subtotal,
+			TaxCategory:  item.TaxCategory,
+			Instructions: item.Instructions,
+			Status:       item.LineStatus(),
+			CreatedAt:    item.CreatedAt,
+			UpdatedAt:    item.UpdatedAt,
+		}
+	}
+	subtotal, err := session.Subtotal()
+	if err != nil {
+		return nil, err
+	}
+	discounts := make([]ResponseCustomerDiscount, len(session.Discounts))
+	for i, discount := range session.Discounts {
+		discounts[i] = ResponseCustomerDiscount{
+			ID:        discount.ID,
+			Source:    discount.Source,
+			Code:      discount.Code,
+			Amount:    discount.Amount,
+			AppliedAt: discount.AppliedAt,
+		}
+	}
+
+	return &ResponseCustomerSession{
+		ID:            session.ID,
+		StoreID:       session.StoreID,
+		SeatID:        session.SeatID,
+		Items:         items,
+		Subtotal:      subtotal,
+		Discounts:     discounts,
+		DiscountTotal: session.DiscountTotal(),
+		PartySize:     session.PartySize,
+		Charges:       NewResponseCustomerCharges(session.Charges),
+		ChargeTotal:   session.ChargeTotal(),
+		TotalAmount:   session.TotalAmount,
+		RefundedTotal: session.RefundedAmount(),
+		DiningOption:  session.DiningOption,
+		PriceMode:     session.TaxPolicy.PriceMode,
+		Taxes:         session.Taxes,
+		TaxTotal:      session.TaxTotal,
+		Status:        session.Status,
+		PaymentStatus: session.PaymentStatus,
+		ExpiresAt:     session.ExpiresAt,
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
+	}, nil
+}
+
+// NewResponseCustomerSessions は、models.Sessionの一覧をResponseCustomerSessionの一覧に変換します。
+func NewResponseCustomerSessions(sessions []*models.Session) ([]*ResponseCustomerSession, error) {
+	responses := make([]*ResponseCustomerSession, len(sessions))
+	for i, session := range sessions {
+		res, err := NewResponseCustomerSession(session)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = res
+	}
+	return responses, nil
+}
+
+// PlaceOrder は、座席セッションから注文を行うためのエンドポイントです。
+// 店舗とセッションはセッションJWTのクレームから取得します。
+// 店舗ネットワーク外からの注文がソフトモードで許可された場合は、スタッフ確認のフラグが立った状態で作成されます。
+func (p *Client) PlaceOrder(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+
+	order := &RequestOrder{}
+	if err := c.Bind(order); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order data: %v", err)
+	}
+	if err := order.IsValidate(); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+
+	session, err := p.uc.PlaceOrder(c.Request().Context(), claims.StoreID, claims.SeatID, claims.VisitID, order.ToModels(), getNetworkReviewReason(c))
+	if err != nil {
+		// 会計が済んだ来店には追加注文できない
+		if errors.Is(err, models.ErrVisitClosed) {
+			return responseHandler(c, http.StatusConflict, nil, err, "Failed to place order: %v", err)
+		}
+		// メニューにない（提供を終了した）商品や上限を超える数量は注文できない
+		if errors.Is(err, models.ErrUnknownProduct) || errors.Is(err, models.ErrInvalidItemQuantity) {
+			return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to place order: %v", err)
+		}
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to place order: %v", err)
+	}
+
+	res, err := NewResponseCustomerSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order placed successfully")
+}
+
+// RequestOrderItemEdit は、お客様が注文した商品の数量と特別な指示を変更するリクエストです。
+// quantity は1以上で、商品を削除する場合は DELETE を使用します。
+type RequestOrderItemEdit struct {
+	OrderID      string `json:"order_id"`
+	Quantity     int    `json:"quantity"`
+	Instructions string `json:"instructions"`
+}
+
+// orderItemErrorStatus はお客様による商品の変更・削除で発生したエラーに対応するHTTPステータスを返します。
+func orderItemErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrOrderNotFound), errors.Is(err, models.ErrOrderLineNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSessionStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrItemEditNotAllowed), errors.Is(err, models.ErrLineAlreadyStarted),
+		errors.Is(err, models.ErrNoItems), errors.Is(err, models.ErrOrderExpired):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidLineQuantity), errors.Is(err, models.ErrInvalidItemQuantity),
+		errors.Is(err, models.ErrItemInstructionsTooLong):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UpdateOrderItem は、店舗の確認前の注文の商品の数量と特別な指示をお客様が変更するエンドポイントです。
+// 店舗と座席はセッションJWTのクレームから取得し、対象の明細行はパスで指定します。
+func (p *Client) UpdateOrderItem(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+
+	req := &RequestOrderItemEdit{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind order item data: %v", err)
+	}
+	if req.OrderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "order_id is required")
+	}
+
+	session, err := p.uc.UpdateOrderItem(c.Request().Context(), claims.StoreID, claims.SeatID, req.OrderID, c.Param("id"), req.Quantity, req.Instructions)
+	if err != nil {
+		return responseHandler(c, orderItemErrorStatus(err), nil, err, "Failed to update order item: %v", err)
+	}
+
+	res, err := NewResponseCustomerSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order item updated successfully")
+}
+
+// RemoveOrderItem は、店舗の確認前の注文からお客様が商品を削除するエンドポイントです。
+func (p *Client) RemoveOrderItem(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+
+	orderID := c.QueryParam("order_id")
+	if orderID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "order_id is required")
+	}
+
+	session, err := p.uc.RemoveOrderItem(c.Request().Context(), claims.StoreID, claims.SeatID, orderID, c.Param("id"))
+	if err != nil {
+		return responseHandler(c, orderItemErrorStatus(err), nil, err, "Failed to remove order item: %v", err)
+	}
+
+	res, err := NewResponseCustomerSession(session)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Order item removed successfully")
+}
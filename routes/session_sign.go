@@ -1,7 +1,7 @@
 package routes
 
 import (
-	"backend/usecases"
+	"backend/models"
 	"fmt"
 	"net/http"
 	"time"
@@ -10,8 +10,10 @@ import (
 )
 
 // StartSession は、席ユーザーのために新しいセッションを開始します。
-// QRコード読み込み時に呼び出され、store_idとseat_idをクエリパラメータから取得し、
-// セッション用JWTトークンを生成してCookieにセットします。
+// QRコード読み込み時に呼び出され、store_idとseat_id、QRコードに埋め込まれた署名付きトークン(qr)をクエリパラメータから取得し、
+// トークンを検証した上でセッション用JWTトークンを生成してCookieにセットします。
+// 発行したJWTは座席の来店に紐づけて記録され、スタッフが座席を閉じると失効します。
+// 署名が不正、有効期限切れ、または再発行により無効になったQRコードの場合は403を返します。
 // 有効期限はデフォルトで1時間後ですが、"exp"コンテキスト値が存在する場合はその値を使用します。
 //
 // 引数:
@@ -28,6 +30,15 @@ func (p *Client) StartSession(c echo.Context) error {
 		return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid parameters: %v", err)
 	}
 
+	// 店舗外からの注文を防ぐため、QRコードの署名と再発行回数を検証
+	qrToken, err := p.uc.VerifySeatQR(c.Request().Context(), c.QueryParam("qr"))
+	if err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Invalid QR code: %v", err)
+	}
+	if qrToken.StoreID != storeID || qrToken.SeatID != seatID {
+		return responseHandler(c, http.StatusForbidden, nil, models.ErrInvalidQRToken, "QR code does not match store_id=%s, seat_id=%s", storeID, seatID)
+	}
+
 	// クエリパラメータから有効期限を取得
 	// 設定がなければ、デフォルトで1時間後に設定
 	expiredAt := time.Now().UTC().Add(1 * time.Hour) // デフォルトの有効期限
@@ -37,10 +48,8 @@ func (p *Client) StartSession(c echo.Context) error {
 		}
 	}
 
-	// セッションJWT発行用の構造体を生成
-	// JWTを発行
-	session := usecases.NewSession(storeID, seatID, expiredAt)
-	token, err := session.CreateJWT()
+	// セッションJWTを発行し、会計時に失効できるよう座席の来店ごとに発行記録を残す
+	token, _, err := p.uc.IssueSessionToken(c.Request().Context(), storeID, seatID, c.Request().UserAgent(), c.RealIP(), expiredAt)
 	if err != nil {
 		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to create session JWT: %v", err)
 	}
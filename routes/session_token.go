@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// sessionRevocationMiddleware は会計・退席により失効したセッションJWTを拒否するミドルウェアです。
+// セッションJWT検証ミドルウェアの後に登録する必要があります。
+func (p *Client) sessionRevocationMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := getSessionClaims(c)
+			if err != nil {
+				return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+			}
+
+			if err := p.uc.ValidateSessionToken(c.Request().Context(), claims); err != nil {
+				if errors.Is(err, models.ErrSessionTokenRevoked) || errors.Is(err, models.ErrSessionTokenNotFound) {
+					// ブラウザに残ったCookieを削除し、QRコードの再読み込みを促す
+					c.SetCookie(&http.Cookie{
+						Name:     "session_jwt",
+						Value:    "",
+						Expires:  time.Unix(0, 0),
+						MaxAge:   -1,
+						HttpOnly: true,
+						Secure:   true,
+					})
+					return responseHandler(c, http.StatusUnauthorized, nil, err, "Session has been closed")
+				}
+				return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to validate session: %v", err)
+			}
+			return next(c)
+		}
+	}
+}
+
+type ResponseSeatDevice struct {
+	ID        string    `json:"id"`
+	VisitID   string    `json:"visit_id"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewResponseSeatDevices は、models.SessionTokenの一覧をResponseSeatDeviceの一覧に変換します。
+func NewResponseSeatDevices(tokens []*models.SessionToken) []*ResponseSeatDevice {
+	devices := make([]*ResponseSeatDevice, len(tokens))
+	for i, token := range tokens {
+		devices[i] = &ResponseSeatDevice{
+			ID:        token.ID,
+			VisitID:   token.VisitID,
+			UserAgent: token.UserAgent,
+			IPAddress: token.IPAddress,
+			IssuedAt:  token.IssuedAt,
+			ExpiresAt: token.ExpiresAt,
+		}
+	}
+	return devices
+}
+
+// CloseSeat は、会計・退席した座席を閉じるエンドポイントです。
+// 現在の来店で発行したセッションJWTを全て失効させ、座席のQRコードを再発行して、前の来店客が注文を続けられないようにします。
+// 座席に表示するQRコードは、閉じた後に新しいものへ差し替えてください。
+func (p *Client) CloseSeat(c echo.Context) error {
+	storeID, seatID, err := getStoreAndSeatID(c)
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid parameters: %v", err)
+	}
+
+	revoked, err := p.uc.CloseSeat(c.Request().Context(), storeID, seatID)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to close seat: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, echo.Map{"revoked": revoked}, nil, "Seat closed, revoked %d sessions", revoked)
+}
+
+// ListSeatDevices は、座席の現在の来店で接続中の端末一覧を返すエンドポイントです。
+func (p *Client) ListSeatDevices(c echo.Context) error {
+	storeID, seatID, err := getStoreAndSeatID(c)
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid parameters: %v", err)
+	}
+
+	tokens, err := p.uc.ListSeatDevices(c.Request().Context(), storeID, seatID)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to list seat devices: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseSeatDevices(tokens), nil, "Seat devices retrieved successfully")
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"backend/models"
+	"backend/usecases"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRevocationMiddleware(t *testing.T) {
+	p := &Client{uc: usecases.New(nil)}
+	handler := p.sessionRevocationMiddleware()(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("jtiのないセッションJWTは拒否してCookieを削除する", func(t *testing.T) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/private/session/order", nil), rec)
+		c.Set("user", &jwt.Token{Claims: &models.SessionClaims{StoreID: "store_1", SeatID: "seat_1"}})
+
+		assert.NoError(t, handler(c))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderSetCookie), "session_jwt=;")
+	})
+
+	t.Run("クレームがない場合は拒否する", func(t *testing.T) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/private/session/order", nil), rec)
+
+		assert.NoError(t, handler(c))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestTender の method は "cash"、"card"、"qr_pay"、"gift_card" のいずれかです。
+// amount は補助単位（円、セント等）の整数です。
+type RequestTender struct {
+	Method    models.TenderMethod `json:"method"`
+	Amount    int64               `json:"amount"`
+	Reference string              `json:"reference"`
+}
+
+// RequestSettleVisit の close_seat が true の場合は、精算後に座席の来店を終了します。
+type RequestSettleVisit struct {
+	StoreID   string          `json:"store_id"`
+	VisitID   string          `json:"visit_id"`
+	Currency  string          `json:"currency"`
+	Tenders   []RequestTender `json:"tenders"`
+	CloseSeat bool            `json:"close_seat"`
+}
+
+// ToModel は、リクエストの支払いをmodels.Tenderに変換します。
+func (r *RequestSettleVisit) ToModel() ([]models.Tender, error) {
+	currency, err := models.ParseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	tenders := make([]models.Tender, 0, len(r.Tenders))
+	for _, tender := range r.Tenders {
+		tenders = append(tenders, models.Tender{
+			Method:    tender.Method,
+			Amount:    models.NewMoney(tender.Amount, currency),
+			Reference: tender.Reference,
+		})
+	}
+	return tenders, nil
+}
+
+type ResponseTender struct {
+	Method    models.TenderMethod `json:"method"`
+	Amount    models.Money        `json:"amount"`
+	Reference string              `json:"reference,omitempty"`
+}
+
+type ResponseSettlement struct {
+	ID         string           `json:"id"`
+	StoreID    string           `json:"store_id"`
+	SeatID     string           `json:"seat_id"`
+	VisitID    string           `json:"visit_id"`
+	SessionIDs []string         `json:"session_ids"`
+	Total      models.Money     `json:"total"`
+	Tenders    []ResponseTender `json:"tenders"`
+	Tendered   models.Money     `json:"tendered"`
+	Change     models.Money     `json:"change"`
+	Actor      string           `json:"actor,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// NewResponseSettlement は、models.SettlementをResponseSettlementに変換します。
+func NewResponseSettlement(settlement *models.Settlement) *ResponseSettlement {
+	tenders := make([]ResponseTender, len(settlement.Tenders))
+	for i, tender := range settlement.Tenders {
+		tenders[i] = ResponseTender{Method: tender.Method, Amount: tender.Amount, Reference: tender.Reference}
+	}
+
+	return &ResponseSettlement{
+		ID:         settlement.ID,
+		StoreID:    settlement.StoreID,
+		SeatID:     settlement.SeatID,
+		VisitID:    settlement.VisitID,
+		SessionIDs: settlement.SessionIDs,
+		Total:      settlement.Total,
+		Tenders:    tenders,
+		Tendered:   settlement.Tendered,
+		Change:     settlement.Change,
+		Actor:      settlement.Actor,
+		CreatedAt:  settlement.CreatedAt,
+	}
+}
+
+// settlementErrorStatus はレジでの精算で発生したエラーに対応するHTTPステータスを返します。
+func settlementErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrSettlementNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSeatStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrVisitAlreadySettled), errors.Is(err, models.ErrBillSplitInProgress),
+		errors.Is(err, models.ErrPaymentInProgress), errors.Is(err, models.ErrBusinessDayClosed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrCheckEmpty), errors.Is(err, models.ErrTendersRequired),
+		errors.Is(err, models.ErrInvalidTenderMethod), errors.Is(err, models.ErrInvalidTenderAmount),
+		errors.Is(err, models.ErrInsufficientTender), errors.Is(err, models.ErrNonCashOverTender),
+		errors.Is(err, models.ErrCurrencyMismatch), errors.Is(err, models.ErrUnsupportedCurrency):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// SettleVisit は、来店の会計をレジで精算するエンドポイントです。
+// 複数の支払い方法を組み合わせて受け取り、お釣りを計算して来店の注文をまとめて完了にします。
+func (p *Client) SettleVisit(c echo.Context) error {
+	req := &RequestSettleVisit{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind settlement data: %v", err)
+	}
+	if req.StoreID == "" || req.VisitID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and visit_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	tenders, err := req.ToModel()
+	if err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Validation failed: %v", err)
+	}
+	settlement, err := p.uc.SettleVisit(c.Request().Context(), req.StoreID, req.VisitID, tenders, getActor(c), req.CloseSeat)
+	if err != nil {
+		return responseHandler(c, settlementErrorStatus(err), nil, err, "Failed to settle visit: %v", err)
+	}
+
+	return responseHandler(c, http.StatusCreated, NewResponseSettlement(settlement), nil, "Visit settled successfully")
+}
+
+// GetVisitSettlement は、来店の精算記録を取得するエンドポイントです。
+func (p *Client) GetVisitSettlement(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	visitID := c.QueryParam("visit_id")
+	if storeID == "" || visitID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and visit_id are required")
+	}
+
+	settlement, err := p.uc.GetVisitSettlement(c.Request().Context(), storeID, visitID)
+	if err != nil {
+		return responseHandler(c, settlementErrorStatus(err), nil, err, "Failed to get settlement: %v", err)
+	}
+
+	return responseHandler(c, http.StatusOK, NewResponseSettlement(settlement), nil, "Settlement retrieved successfully")
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSettleVisitToModel(t *testing.T) {
+	req := &RequestSettleVisit{
+		Currency: "jpy",
+		Tenders: []RequestTender{
+			{Method: models.TenderCard, Amount: 2000, Reference: "auth_1"},
+			{Method: models.TenderCash, Amount: 2000},
+		},
+	}
+	tenders, err := req.ToModel()
+	require.NoError(t, err)
+	require.Len(t, tenders, 2)
+	assert.Equal(t, models.Yen(2000), tenders[0].Amount)
+	assert.Equal(t, "auth_1", tenders[0].Reference)
+
+	req.Currency = "xxx"
+	_, err = req.ToModel()
+	assert.ErrorIs(t, err, models.ErrUnsupportedCurrency)
+}
+
+func TestSettlementErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, settlementErrorStatus(models.ErrSettlementNotFound))
+	assert.Equal(t, http.StatusConflict, settlementErrorStatus(models.ErrVisitAlreadySettled))
+	assert.Equal(t, http.StatusConflict, settlementErrorStatus(models.ErrPaymentInProgress))
+	assert.Equal(t, http.StatusBadRequest, settlementErrorStatus(fmt.Errorf("%w: 3000 JPY < 3300 JPY", models.ErrInsufficientTender)))
+	assert.Equal(t, http.StatusInternalServerError, settlementErrorStatus(errors.New("firestore unavailable")))
+}
@@ -7,28 +7,35 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/yeqown/go-qrcode/v2"
 	"github.com/yeqown/go-qrcode/writer/standard"
 )
 
+// 動的QRコードの既定の有効期間
+// 環境変数 DYNAMIC_QR_TTL（例: "2m"）で上書きできます。
+const defaultDynamicQRTTL = 2 * time.Minute
+
 // IssueSeatQRForStore
 // IssueSeatQRForStoreは、特定の店舗と座席のQRコード発行を処理します。
-// リクエストパラメータから店舗IDと座席IDを取得し、店舗と座席を指すURLをエンコードしたQRコードを生成します。
+// リクエストパラメータから店舗IDと座席IDを取得し、署名付きの座席QRトークンを付与したURLをエンコードしたQRコードを生成します。
 // QRコードはbase64エンコードされた文字列としてURLとともにレスポンスで返されます。
 // パラメータ抽出やQRコード生成時にエラーが発生した場合は、適切なエラーレスポンスを返します。
 //
 // 期待されるリクエストパラメータ:
 //   - storeID: 店舗ID
 //   - seatID: 座席ID
+//   - mode: "dynamic" の場合、タブレット表示用の短命なQRコードを発行（任意）
 //
 // レスポンス:
 //   - message: QRコードが発行された店舗と座席を示す成功メッセージ
 //   - url: QRコードにエンコードされたURL
 //   - qr_code: base64エンコードされたQRコード画像
+//   - expires_at: 動的QRコードの有効期限（動的QRコードの場合のみ）
 //
-// パラメータ不正の場合はHTTP 400、内部エラーの場合はHTTP 500を返します。
+// パラメータ不正の場合はHTTP 400、座席が見つからない場合はHTTP 404、内部エラーの場合はHTTP 500を返します。
 func (p *Client) IssueSeatQRForStore(c echo.Context) error {
 	// QRコード読み込み時にパラメータ有りウェブサイトにアクセス
 	storeID, seatID, err := getStoreAndSeatID(c)
@@ -36,9 +43,20 @@ func (p *Client) IssueSeatQRForStore(c echo.Context) error {
 		return responseHandler(c, http.StatusBadRequest, nil, err, "Invalid parameters: %v", err)
 	}
 
+	var dynamicTTL time.Duration
+	if c.QueryParam("mode") == "dynamic" {
+		dynamicTTL = loadDynamicQRTTL()
+	}
+
+	// 座席ごとの署名付きトークンを発行
+	token, qrToken, err := p.uc.IssueSeatQRToken(c.Request().Context(), storeID, seatID, dynamicTTL)
+	if err != nil {
+		return responseHandler(c, http.StatusNotFound, nil, err, "Failed to issue seat QR token: %v", err)
+	}
+
 	// QRコードの作成
 	domain := os.Getenv("FRONTEND_URL")
-	url := fmt.Sprintf("%s/store/%s/seat/%s", domain, storeID, seatID)
+	url := fmt.Sprintf("%s/store/%s/seat/%s?qr=%s", domain, storeID, seatID, token)
 	qrCodeOfURL, err := qrcode.New(url)
 	if err != nil {
 		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to create QR code: %v", err)
@@ -53,11 +71,24 @@ func (p *Client) IssueSeatQRForStore(c echo.Context) error {
 	}
 	defer wc.Close()
 
-	// レスポンスを返す
-	return responseHandler(c, http.StatusOK, map[string]string{
+	response := map[string]string{
 		"url":     url,
 		"qr_code": base64.StdEncoding.EncodeToString(buf.Bytes()),
-	}, nil, fmt.Sprintf("QR code for store %s and seat %s issued successfully", storeID, seatID))
+	}
+	if qrToken.IsDynamic() {
+		response["expires_at"] = time.Unix(qrToken.ExpiresAt, 0).UTC().Format(time.RFC3339)
+	}
+
+	// レスポンスを返す
+	return responseHandler(c, http.StatusOK, response, nil, fmt.Sprintf("QR code for store %s and seat %s issued successfully", storeID, seatID))
+}
+
+// loadDynamicQRTTL は動的QRコードの有効期間を環境変数から読み込みます。
+func loadDynamicQRTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("DYNAMIC_QR_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultDynamicQRTTL
 }
 
 type Closer struct {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/joho/godotenv"
@@ -130,7 +131,7 @@ func TestIssueSeatQRForStore(t *testing.T) {
 				}
 
 				domain := os.Getenv("FRONTEND_URL")
-				assert.Equal(t, data["url"], domain+"/store/"+tt.storeID+"/seat/"+tt.seatID)
+				assert.True(t, strings.HasPrefix(data["url"].(string), domain+"/store/"+tt.storeID+"/seat/"+tt.seatID+"?qr=v1."), "URLには署名付きQRトークンが含まれる必要があります")
 				t.Logf("QR code URL: %s/store/%s/seat/%s", domain, tt.storeID, tt.seatID)
 			}
 		})
@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"backend/models"
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultExpirySweepInterval は自動のステータス遷移を実行する既定の間隔です。
+const defaultExpirySweepInterval = time.Minute
+
+// loadExpirySweepInterval は環境変数 EXPIRY_SWEEP_INTERVAL（"1m" など）から実行間隔を読み込みます。
+// "0" または "off" の場合は0を返し、実行しません。値が不正な場合は既定値を使用します。
+func loadExpirySweepInterval() time.Duration {
+	value := strings.TrimSpace(os.Getenv("EXPIRY_SWEEP_INTERVAL"))
+	switch value {
+	case "":
+		return defaultExpirySweepInterval
+	case "0", "off":
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warn().Err(err).Msgf("EXPIRY_SWEEP_INTERVAL is invalid, fallback to %s", defaultExpirySweepInterval)
+		return defaultExpirySweepInterval
+	}
+	return interval
+}
+
+// startExpirySweeper は、期限切れの注文の自動キャンセルなど、時間経過による自動のステータス遷移を定期的に実行します。
+// 各インスタンスが実行を試みますが、リースを取得できた1つのインスタンスのみが処理します。
+// リースの期限は実行間隔と同じで、処理が実行間隔より長くかかる場合は処理中に延長します。
+// 処理していたインスタンスが停止した場合は、次の間隔で他のインスタンスが引き継ぎます。
+func (p *Client) startExpirySweeper(ctx context.Context) {
+	interval := loadExpirySweepInterval()
+	if interval <= 0 {
+		log.Info().Msg("expiry sweeper is disabled")
+		return
+	}
+	holder := models.GenerateUniqueID("sweeper_")
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				result, err := p.uc.SweepExpired(ctx, holder, interval, now.UTC())
+				if err != nil {
+					log.Error().Err(err).Msg("expiry sweeper failed")
+				}
+				if result.Expired > 0 || result.Completed > 0 || result.VisitsClosed > 0 {
+					log.Info().Msgf("expiry sweeper: expired=%d, completed=%d, visits_closed=%d", result.Expired, result.Completed, result.VisitsClosed)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadExpirySweepInterval(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", defaultExpirySweepInterval},
+		{"30s", 30 * time.Second},
+		{"off", 0},
+		{"0", 0},
+		{"-1m", defaultExpirySweepInterval},
+		{"every minute", defaultExpirySweepInterval},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv("EXPIRY_SWEEP_INTERVAL", tc.value)
+			assert.Equal(t, tc.expected, loadExpirySweepInterval())
+		})
+	}
+}
+
+func TestRequestStoreExpiryToModel(t *testing.T) {
+	req := &RequestStoreExpiry{UnconfirmedAction: models.ExpiryDecline, AutoCompleteAfterMinutes: 45}
+	policy := req.ToModel()
+	assert.Equal(t, models.ExpiryDecline, policy.UnconfirmedAction)
+	assert.Equal(t, 45*time.Minute, policy.AutoCompleteAfter)
+	assert.Equal(t, models.DefaultVisitIdleTimeout, policy.WithDefaults().VisitIdleTimeout)
+}
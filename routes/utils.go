@@ -1,8 +1,11 @@
 package routes
 
 import (
+	"backend/models"
+	"errors"
 	"fmt"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 )
@@ -23,3 +26,18 @@ func responseHandler(c echo.Context, status int, data any, err error, format str
 		"data":    data,
 	})
 }
+
+var ErrInvalidSessionClaims = errors.New("invalid session claims")
+
+// getSessionClaims はセッションJWT検証ミドルウェアが設定したクレームを取得します。
+func getSessionClaims(c echo.Context) (*models.SessionClaims, error) {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return nil, ErrInvalidSessionClaims
+	}
+	claims, ok := token.Claims.(*models.SessionClaims)
+	if !ok {
+		return nil, ErrInvalidSessionClaims
+	}
+	return claims, nil
+}
@@ -0,0 +1,198 @@
+package routes
+
+import (
+	"backend/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ResponseVisitCheck はスタッフ向けの来店の現在の会計です。rounds は追加注文ごとの注文で、注文した順に並びます。
+// total はキャンセル・返金済みを除いた合計金額、paid はオンライン決済などで支払い済みの金額、balance はレジで精算する残りの金額です。
+type ResponseVisitCheck struct {
+	VisitID      string             `json:"visit_id"`
+	StoreID      string             `json:"store_id"`
+	SeatID       string             `json:"seat_id"`
+	Status       models.VisitStatus `json:"status"`
+	PartySize    int                `json:"party_size,omitempty"`
+	Rounds       []*ResponseSession `json:"rounds"`
+	Charges      []models.Charge    `json:"charges"`
+	ChargeTotal  models.Money       `json:"charge_total"`
+	Taxes        []models.TaxLine   `json:"taxes"`
+	Total        models.Money       `json:"total"`
+	Paid         models.Money       `json:"paid"`
+	Balance      models.Money       `json:"balance"`
+	SettlementID string             `json:"settlement_id,omitempty"`
+	OpenedAt     time.Time          `json:"opened_at"`
+	ClosedAt     *time.Time         `json:"closed_at,omitempty"`
+}
+
+// NewResponseVisitCheck は、models.VisitCheckをResponseVisitCheckに変換します。
+func NewResponseVisitCheck(check *models.VisitCheck) (*ResponseVisitCheck, error) {
+	rounds, err := NewResponseSessions(check.Rounds)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseVisitCheck{
+		VisitID:      check.Visit.ID,
+		StoreID:      check.Visit.StoreID,
+		SeatID:       check.Visit.SeatID,
+		Status:       check.Visit.Status,
+		PartySize:    check.Visit.PartySize,
+		Rounds:       rounds,
+		Charges:      check.Charges,
+		ChargeTotal:  check.ChargeTotal,
+		Taxes:        check.Taxes,
+		Total:        check.Total,
+		Paid:         check.Paid,
+		Balance:      check.Balance,
+		SettlementID: check.Visit.SettlementID,
+		OpenedAt:     check.Visit.OpenedAt,
+		ClosedAt:     optionalTime(check.Visit.ClosedAt),
+	}, nil
+}
+
+// RequestVisitPartySize はスタッフが確認した来店人数です。
+type RequestVisitPartySize struct {
+	StoreID   string `json:"store_id"`
+	VisitID   string `json:"visit_id"`
+	PartySize int    `json:"party_size"`
+}
+
+// ResponseCustomerVisitCheck はお客様向けの来店の現在の会計です。
+// 追加注文ごとの注文とチャージは ResponseCustomerSession と同じく、スタッフの操作の記録を含めません。
+type ResponseCustomerVisitCheck struct {
+	VisitID     string                     `json:"visit_id"`
+	StoreID     string                     `json:"store_id"`
+	SeatID      string                     `json:"seat_id"`
+	Status      models.VisitStatus         `json:"status"`
+	PartySize   int                        `json:"party_size,omitempty"`
+	Rounds      []*ResponseCustomerSession `json:"rounds"`
+	Charges     []ResponseCustomerCharge   `json:"charges"`
+	ChargeTotal models.Money               `json:"charge_total"`
+	Taxes       []models.TaxLine           `json:"taxes"`
+	Total       models.Money               `json:"total"`
+	Paid        models.Money               `json:"paid"`
+	Balance     models.Money               `json:"balance"`
+	OpenedAt    time.Time                  `json:"opened_at"`
+	ClosedAt    *time.Time                 `json:"closed_at,omitempty"`
+}
+
+// NewResponseCustomerVisitCheck は、models.VisitCheckをResponseCustomerVisitCheckに変換します。
+func NewResponseCustomerVisitCheck(check *models.VisitCheck) (*ResponseCustomerVisitCheck, error) {
+	rounds, err := NewResponseCustomerSessions(check.Rounds)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseCustomerVisitCheck{
+		VisitID:     check.Visit.ID,
+		StoreID:     check.Visit.StoreID,
+		SeatID:      check.Visit.SeatID,
+		Status:      check.Visit.Status,
+		PartySize:   check.Visit.PartySize,
+		Rounds:      rounds,
+		Charges:     NewResponseCustomerCharges(check.Charges),
+		ChargeTotal: check.ChargeTotal,
+		Taxes:       check.Taxes,
+		Total:       check.Total,
+		Paid:        check.Paid,
+		Balance:     check.Balance,
+		OpenedAt:    check.Visit.OpenedAt,
+		ClosedAt:    optionalTime(check.Visit.ClosedAt),
+	}, nil
+}
+
+// visitErrorStatus は来店の会計の取得で発生したエラーに対応するHTTPステータスを返します。
+func visitErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrVisitNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrSeatStoreMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrVisitClosed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidPartySize):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetVisitCheck は、スタッフが来店の現在の会計（追加注文ごとの注文と合計金額の途中経過）を取得するエンドポイントです。
+// visit_id の代わりに seat_id を指定した場合は、座席の現在の来店の会計を返します。
+func (p *Client) GetVisitCheck(c echo.Context) error {
+	storeID := c.QueryParam("store_id")
+	visitID := c.QueryParam("visit_id")
+	seatID := c.QueryParam("seat_id")
+	if storeID == "" || (visitID == "" && seatID == "") {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and visit_id or seat_id are required")
+	}
+
+	var check *models.VisitCheck
+	var err error
+	if visitID != "" {
+		check, err = p.uc.GetVisitCheck(c.Request().Context(), storeID, visitID)
+	} else {
+		check, err = p.uc.GetSeatVisitCheck(c.Request().Context(), storeID, seatID)
+	}
+	if err != nil {
+		return responseHandler(c, visitErrorStatus(err), nil, err, "Failed to get visit check: %v", err)
+	}
+
+	res, err := NewResponseVisitCheck(check)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Visit check retrieved successfully")
+}
+
+// SetVisitPartySize は、スタッフが確認した来店人数を登録するエンドポイントです。
+// 人数分のチャージ（お通し代など）は、会計前の注文についてこの人数で再計算されます。
+func (p *Client) SetVisitPartySize(c echo.Context) error {
+	req := &RequestVisitPartySize{}
+	if err := c.Bind(req); err != nil {
+		return responseHandler(c, http.StatusBadRequest, nil, err, "Failed to bind visit data: %v", err)
+	}
+	if req.StoreID == "" || req.VisitID == "" {
+		return responseHandler(c, http.StatusBadRequest, nil, nil, "store_id and visit_id are required")
+	}
+	if err := authorizeStore(c, req.StoreID); err != nil {
+		return responseHandler(c, http.StatusForbidden, nil, err, "Not allowed for store_id=%s", req.StoreID)
+	}
+
+	check, err := p.uc.SetVisitPartySize(c.Request().Context(), req.StoreID, req.VisitID, req.PartySize)
+	if err != nil {
+		return responseHandler(c, visitErrorStatus(err), nil, err, "Failed to set party size: %v", err)
+	}
+
+	res, err := NewResponseVisitCheck(check)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Party size updated successfully")
+}
+
+// GetCurrentVisitCheck は、お客様が着座中の来店の現在の会計を取得するエンドポイントです。
+// 店舗と座席、来店IDはセッションJWTのクレームから取得します。
+func (p *Client) GetCurrentVisitCheck(c echo.Context) error {
+	claims, err := getSessionClaims(c)
+	if err != nil {
+		return responseHandler(c, http.StatusUnauthorized, nil, err, "Invalid session")
+	}
+	if claims.VisitID == "" {
+		return responseHandler(c, http.StatusNotFound, nil, models.ErrVisitNotFound, "Visit not found")
+	}
+
+	check, err := p.uc.GetCustomerVisitCheck(c.Request().Context(), claims.StoreID, claims.SeatID, claims.VisitID)
+	if err != nil {
+		return responseHandler(c, visitErrorStatus(err), nil, err, "Failed to get visit check: %v", err)
+	}
+
+	res, err := NewResponseCustomerVisitCheck(check)
+	if err != nil {
+		return responseHandler(c, http.StatusInternalServerError, nil, err, "Failed to build response: %v", err)
+	}
+	return responseHandler(c, http.StatusOK, res, nil, "Visit check retrieved successfully")
+}
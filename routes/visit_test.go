@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"backend/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResponseVisitCheck(t *testing.T) {
+	now := time.Now()
+	visit := models.NewVisit(&models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}, now)
+	session := &models.Session{ID: "session_1", StoreID: "store_1", SeatID: "seat_1", VisitID: "visit_1", TotalAmount: models.Yen(1000), CreatedAt: now}
+	require.NoError(t, visit.AddRound(session))
+
+	check, err := models.NewVisitCheck(visit, []*models.Session{session})
+	require.NoError(t, err)
+	res, err := NewResponseVisitCheck(check)
+	require.NoError(t, err)
+	assert.Equal(t, "visit_1", res.VisitID)
+	assert.Equal(t, models.VisitOpen, res.Status)
+	require.Len(t, res.Rounds, 1)
+	assert.Equal(t, models.Yen(1000), res.Balance)
+	assert.Nil(t, res.ClosedAt)
+
+	visit.Close("settlement_1", now)
+	res, err = NewResponseVisitCheck(check)
+	require.NoError(t, err)
+	assert.Equal(t, "settlement_1", res.SettlementID)
+	assert.NotNil(t, res.ClosedAt)
+}
+
+func TestNewResponseCustomerVisitCheck(t *testing.T) {
+	now := time.Now()
+	visit := models.NewVisit(&models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}, now)
+	session := &models.Session{ID: "session_1", StoreID: "store_1", SeatID: "seat_1", VisitID: "visit_1", TotalAmount: models.Yen(1000), CreatedAt: now}
+	require.NoError(t, visit.AddRound(session))
+
+	check, err := models.NewVisitCheck(visit, []*models.Session{session})
+	require.NoError(t, err)
+	check.Charges = []models.Charge{{ID: "charge_1", Name: "お通し", Amount: models.Yen(300), Waived: true, WaiveReason: "待ち時間", WaivedBy: "manager:a@example.com"}}
+	res, err := NewResponseCustomerVisitCheck(check)
+	require.NoError(t, err)
+	assert.Equal(t, "visit_1", res.VisitID)
+	require.Len(t, res.Rounds, 1)
+	assert.Equal(t, models.Yen(1000), res.Balance)
+
+	body, err := json.Marshal(res)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "manager:a@example.com")
+	assert.NotContains(t, string(body), "待ち時間")
+}
+
+func TestVisitErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, visitErrorStatus(models.ErrVisitNotFound))
+	assert.Equal(t, http.StatusForbidden, visitErrorStatus(fmt.Errorf("%w: store_2", models.ErrSeatStoreMismatch)))
+	assert.Equal(t, http.StatusConflict, visitErrorStatus(models.ErrVisitClosed))
+	assert.Equal(t, http.StatusBadRequest, visitErrorStatus(models.ErrInvalidPartySize))
+	assert.Equal(t, http.StatusInternalServerError, visitErrorStatus(errors.New("firestore unavailable")))
+}
@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// apiKeyLastUsedInterval は最終使用日時を更新する最小間隔です。
+// 連携ジョブはリクエスト数が多いため、毎回は書き込みません。
+const apiKeyLastUsedInterval = time.Minute
+
+// CreateAPIKey は外部連携用のAPIキーを発行します。
+// 店舗単位のキーは組織（organizationID）の店舗にのみ発行でき、他の組織の店舗には models.ErrPermissionDenied を返します。
+// 戻り値のキー本体は発行時にのみ返され、以降は参照できません。
+func (u *UseCase) CreateAPIKey(ctx context.Context, organizationID, name string, scope models.APIKeyScope, storeID string, permissions []models.Permission, expiresAt time.Time) (*models.APIKey, string, error) {
+	key, raw, err := models.NewAPIKey(name, scope, storeID, organizationID, permissions, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if key.Scope == models.APIKeyScopeStore {
+		store, err := u.storeRepo.FindByID(ctx, key.StoreID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to find store: %w", err)
+		}
+		if store.OrganizationID != organizationID {
+			return nil, "", fmt.Errorf("%w: store_id=%s", models.ErrPermissionDenied, key.StoreID)
+		}
+	}
+
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	return key, raw, nil
+}
+
+// ListAPIKeys は組織で発行したAPIキーの一覧を返します。
+func (u *UseCase) ListAPIKeys(ctx context.Context, organizationID string) ([]*models.APIKey, error) {
+	keys, err := u.apiKeyRepo.FindByField(ctx, "organization_id", organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey はAPIキーを無効化します。
+// 他の組織のAPIキーは見つからないものとして扱います。
+func (u *UseCase) RevokeAPIKey(ctx context.Context, organizationID, id string) (*models.APIKey, error) {
+	key, err := u.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrAPIKeyNotFound, err)
+	}
+	if key.OrganizationID != organizationID {
+		return nil, models.ErrAPIKeyNotFound
+	}
+
+	key.Revoke(time.Now().UTC())
+	if err := u.apiKeyRepo.UpdateByID(ctx, key.ID, key); err != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return key, nil
+}
+
+// AuthenticateAPIKey はBearerトークンとして送られたAPIキーを検証し、該当するAPIキーを返します。
+func (u *UseCase) AuthenticateAPIKey(ctx context.Context, raw string) (*models.APIKey, error) {
+	if !models.IsAPIKeyToken(raw) {
+		return nil, models.ErrInvalidAPIKey
+	}
+
+	keys, err := u.apiKeyRepo.FindByField(ctx, "hashed_key", models.HashAPIKey(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, models.ErrInvalidAPIKey
+	}
+
+	key := keys[0]
+	now := time.Now().UTC()
+	if err := key.Verify(now); err != nil {
+		return nil, err
+	}
+
+	// 組織スコープのキーは、組織に属する店舗のみ操作できる
+	if key.Scope == models.APIKeyScopeOrganization {
+		stores, err := u.storeRepo.FindByField(ctx, "organization_id", key.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find organization stores: %w", err)
+		}
+		key.OrganizationStoreIDs = make([]string, len(stores))
+		for i, store := range stores {
+			key.OrganizationStoreIDs[i] = store.ID
+		}
+	}
+
+	if key.MarkUsed(now, apiKeyLastUsedInterval) {
+		// 最終使用日時の記録は補助的な情報のため、失敗しても認証は成功させる
+		_ = u.apiKeyRepo.UpdateByID(ctx, key.ID, key)
+	}
+	return key, nil
+}
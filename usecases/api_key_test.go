@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateAPIKey tests the CreateAPIKey function
+func TestCreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	permissions := []models.Permission{models.PermissionOrdersRead}
+
+	t.Run("create store scoped key", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", OrganizationID: "manager@example.com"}, nil)
+		keyRepo := useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository)
+		keyRepo.On("Create", ctx, mock.AnythingOfType("*models.APIKey")).Return(nil)
+
+		key, raw, err := useCase.CreateAPIKey(ctx, "manager@example.com", "BI", models.APIKeyScopeStore, "store_1", permissions, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, "manager@example.com", key.OrganizationID)
+		assert.Equal(t, models.HashAPIKey(raw), key.HashedKey)
+		keyRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_x").Return(nil, assert.AnError)
+
+		_, _, err := useCase.CreateAPIKey(ctx, "manager@example.com", "BI", models.APIKeyScopeStore, "store_x", permissions, time.Time{})
+		assert.Error(t, err)
+		useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("store of another organization", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_2").Return(&models.Store{ID: "store_2", OrganizationID: "other@example.com"}, nil)
+
+		_, _, err := useCase.CreateAPIKey(ctx, "manager@example.com", "BI", models.APIKeyScopeStore, "store_2", permissions, time.Time{})
+		assert.ErrorIs(t, err, models.ErrPermissionDenied)
+		useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		useCase := New(nil)
+		_, _, err := useCase.CreateAPIKey(ctx, "manager@example.com", "BI", "global", "", permissions, time.Time{})
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKeyScope)
+	})
+}
+
+// TestAuthenticateAPIKey tests the AuthenticateAPIKey function
+func TestAuthenticateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	newKey := func(t *testing.T) (*models.APIKey, string) {
+		key, raw, err := models.NewAPIKey("BI", models.APIKeyScopeOrganization, "", "manager@example.com", []models.Permission{models.PermissionOrdersRead}, time.Time{})
+		require.NoError(t, err)
+		return key, raw
+	}
+
+	t.Run("valid key records last used", func(t *testing.T) {
+		useCase := New(nil)
+		key, raw := newKey(t)
+		keyRepo := useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository)
+		keyRepo.On("FindByField", ctx, "hashed_key", key.HashedKey).Return([]*models.APIKey{key}, nil)
+		keyRepo.On("UpdateByID", ctx, key.ID, key).Return(nil).Once()
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByField", ctx, "organization_id", "manager@example.com").Return([]*models.Store{{ID: "store_1"}}, nil)
+
+		authenticated, err := useCase.AuthenticateAPIKey(ctx, raw)
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, authenticated.ID)
+		assert.False(t, key.LastUsedAt.IsZero())
+		assert.True(t, authenticated.AllowsStore("store_1"))
+		assert.False(t, authenticated.AllowsStore("store_2"), "組織に属さない店舗は操作できない")
+
+		// 間隔内の再利用では書き込まない
+		_, err = useCase.AuthenticateAPIKey(ctx, raw)
+		require.NoError(t, err)
+		keyRepo.AssertNumberOfCalls(t, "UpdateByID", 1)
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		useCase := New(nil)
+		key, raw := newKey(t)
+		key.Revoke(time.Now().UTC())
+		useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository).On("FindByField", ctx, "hashed_key", key.HashedKey).Return([]*models.APIKey{key}, nil)
+
+		_, err := useCase.AuthenticateAPIKey(ctx, raw)
+		assert.ErrorIs(t, err, models.ErrAPIKeyRevoked)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository).On("FindByField", ctx, "hashed_key", mock.Anything).Return([]*models.APIKey{}, nil)
+
+		_, err := useCase.AuthenticateAPIKey(ctx, models.APIKeyTokenPrefix+"unknown")
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKey)
+
+		_, err = useCase.AuthenticateAPIKey(ctx, "not-an-api-key")
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	})
+}
+
+// TestRevokeAPIKey tests the RevokeAPIKey function
+func TestRevokeAPIKey(t *testing.T) {
+	ctx := context.Background()
+	key := &models.APIKey{ID: "apikey_1", OrganizationID: "manager@example.com"}
+
+	t.Run("revoke own key", func(t *testing.T) {
+		useCase := New(nil)
+		keyRepo := useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository)
+		keyRepo.On("FindByID", ctx, key.ID).Return(key, nil)
+		keyRepo.On("UpdateByID", ctx, key.ID, key).Return(nil)
+
+		revoked, err := useCase.RevokeAPIKey(ctx, "manager@example.com", key.ID)
+		require.NoError(t, err)
+		assert.True(t, revoked.IsRevoked())
+	})
+
+	t.Run("key of another organization", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.apiKeyRepo.(*repositories.MockAPIKeyRepository).On("FindByID", ctx, key.ID).Return(key, nil)
+
+		_, err := useCase.RevokeAPIKey(ctx, "other@example.com", key.ID)
+		assert.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+	})
+}
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// CreateBillSplit は来店の注文をまとめた会計を指定された方法で分割します。
+// 支払い前の割り勘がすでにある場合は無効にして分割をやり直します。
+// 支払い済みの伝票がある場合はやり直せません。
+func (u *UseCase) CreateBillSplit(ctx context.Context, storeID, visitID string, req models.SplitRequest) (*models.BillSplit, error) {
+	visitOrders, err := u.findVisitOrders(ctx, storeID, visitID)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := models.NewCheck(visitID, visitOrders)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	split, err := models.NewBillSplit(check, req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 以前の割り勘の無効化と新しい割り勘の作成は同じトランザクションで行い、支払い済みの伝票がある割り勘は無効にしない
+	err = u.billSplitUpdates.Replace(ctx, split, func(existing []*models.BillSplit) ([]*models.BillSplit, error) {
+		var voided []*models.BillSplit
+		for _, e := range existing {
+			if e.StoreID != storeID {
+				continue
+			}
+			switch e.Status {
+			case models.BillSplitSettled:
+				return nil, models.ErrBillSplitClosed
+			case models.BillSplitOpen:
+				if err := e.Void(now); err != nil {
+					return nil, err
+				}
+				voided = append(voided, e)
+			}
+		}
+		return voided, nil
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrBillSplitClosed) || errors.Is(err, models.ErrBillSplitInProgress) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create bill split: %w", err)
+	}
+	return split, nil
+}
+
+// findVisitOrders は店舗の来店の注文を返します。
+func (u *UseCase) findVisitOrders(ctx context.Context, storeID, visitID string) ([]*models.Session, error) {
+	sessions, err := u.sessionRepo.FindByField(ctx, "visit_id", visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit orders: %w", err)
+	}
+	return storeOrders(sessions, storeID), nil
+}
+
+// storeOrders は注文のうち店舗の注文を返します。
+func storeOrders(sessions []*models.Session, storeID string) []*models.Session {
+	orders := make([]*models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.StoreID == storeID {
+			orders = append(orders, session)
+		}
+	}
+	return orders
+}
+
+// GetBillSplit は店舗の割り勘を返します。
+func (u *UseCase) GetBillSplit(ctx context.Context, storeID, id string) (*models.BillSplit, error) {
+	split, err := u.billSplitRepo.FindByID(ctx, id)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrBillSplitNotFound
+		}
+		return nil, fmt.Errorf("failed to find bill split: %w", err)
+	}
+	// 他店舗の割り勘は存在しないものとして扱う
+	if split.StoreID != storeID {
+		return nil, models.ErrBillSplitNotFound
+	}
+	return split, nil
+}
+
+// updateCurrentBillSplit は店舗の割り勘と来店の注文を読み取り、update で変更した割り勘と update が返した注文を他の更新と競合しないように保存します。
+// update には割り勘の対象の来店の注文を渡します。
+// 割り勘の作成後に追加注文などで会計が変わった場合は、支払い前であれば割り勘を無効にし、ErrBillSplitStale を返します。
+// closeID を指定した場合は同じトランザクションでレジ締めを確認し、レジ締め済みであれば ErrBusinessDayClosed を返します。
+func (u *UseCase) updateCurrentBillSplit(ctx context.Context, storeID, id, closeID string, now time.Time, update func(*models.BillSplit, []*models.Session) ([]*models.Session, error)) (*models.BillSplit, error) {
+	stale := false
+	split, err := u.billSplitUpdates.UpdateOpen(ctx, closeID, id, func(split *models.BillSplit, sessions []*models.Session) ([]*models.Session, error) {
+		stale = false
+		// 他店舗の割り勘は存在しないものとして扱う
+		if split.StoreID != storeID {
+			return nil, models.ErrBillSplitNotFound
+		}
+		visitOrders := storeOrders(sessions, storeID)
+		check, err := models.NewCheck(split.VisitID, visitOrders)
+		if err != nil && !errors.Is(err, models.ErrCheckEmpty) {
+			return nil, err
+		}
+		if check != nil && split.Matches(check) {
+			return update(split, visitOrders)
+		}
+
+		// 支払い前の割り勘は無効にして保存する
+		if split.HasPayments() || split.Void(now) != nil {
+			return nil, models.ErrBillSplitStale
+		}
+		stale = true
+		return nil, nil
+	})
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrBillSplitNotFound
+		}
+		return nil, err
+	}
+	if stale {
+		return nil, models.ErrBillSplitStale
+	}
+	return split, nil
+}
+
+// AssignSplitRemainder は割り勘の未割り当ての残額を新しい伝票として割り当てます。
+func (u *UseCase) AssignSplitRemainder(ctx context.Context, storeID, id, label string) (*models.BillSplit, error) {
+	now := time.Now()
+	return u.updateCurrentBillSplit(ctx, storeID, id, "", now, func(split *models.BillSplit, _ []*models.Session) ([]*models.Session, error) {
+		_, err := split.AssignRemainder(label, now)
+		return nil, err
+	})
+}
+
+// PaySubBill は割り勘の伝票の支払いを記録します。
+// 全ての伝票の支払いが完了すると、割り勘は精算済みになり、来店の注文を支払い済みにして来店の会計を終了します。
+// method は伝票の支払い方法で、レジ締めの支払い方法別の集計と現金の理論在高に反映します。
+// actor は支払いを記録したスタッフで、注文の支払い状態の履歴に記録します。
+// 伝票の支払いと注文の支払い状態は同じトランザクションで保存し、同じ伝票の支払いを同時に記録した場合は ErrSubBillAlreadyPaid を返します。
+// 割り勘の作成後に会計が変わった場合は ErrBillSplitStale を返します。レジ締め済みの営業日には記録できません。
+func (u *UseCase) PaySubBill(ctx context.Context, storeID, id, subBillID string, method models.TenderMethod, paymentRef, actor string) (*models.BillSplit, error) {
+	now := time.Now()
+	split, err := u.updateCurrentBillSplit(ctx, storeID, id, businessDayCloseID(storeID, now), now, func(split *models.BillSplit, visitOrders []*models.Session) ([]*models.Session, error) {
+		if _, err := split.MarkSubBillPaid(subBillID, method, paymentRef, now); err != nil {
+			return nil, err
+		}
+		if split.Status != models.BillSplitSettled {
+			return nil, nil
+		}
+
+		var paid []*models.Session
+		for _, session := range visitOrders {
+			if !slices.Contains(split.SessionIDs, session.ID) {
+				continue
+			}
+			changed, err := session.PayByBillSplit(actor)
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				paid = append(paid, session)
+			}
+		}
+		return paid, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if split.Status == models.BillSplitSettled {
+		if err := u.closeVisit(ctx, storeID, split.VisitID, split.ID, now); err != nil {
+			return split, err
+		}
+	}
+	return split, nil
+}
@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newBillSplitTestSessions(t *testing.T) []*models.Session {
+	t.Helper()
+	first, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 2, models.Yen(1100))})
+	require.NoError(t, err)
+	second, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_2", 1, models.Yen(1100))})
+	require.NoError(t, err)
+	other, err := models.NewSession("store_2", "seat_9", []models.Order{*models.NewOrder("prod_3", 1, models.Yen(5000))})
+	require.NoError(t, err)
+	for _, s := range []*models.Session{first, second, other} {
+		s.VisitID = "visit_1"
+	}
+	return []*models.Session{first, second, other}
+}
+
+// TestCreateBillSplit tests the CreateBillSplit function
+func TestCreateBillSplit(t *testing.T) {
+	ctx := context.Background()
+	even := models.SplitRequest{Method: models.SplitEven, Parts: 3}
+
+	setup := func(t *testing.T, existing []*models.BillSplit) *UseCase {
+		useCase := New(nil)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByField", ctx, "visit_id", "visit_1").Return(newBillSplitTestSessions(t), nil)
+		splitRepo := useCase.billSplitRepo.(*repositories.MockBillSplitRepository)
+		splitRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(existing, nil)
+		splitRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.BillSplit")).Return(nil)
+		splitRepo.On("Create", ctx, mock.AnythingOfType("*models.BillSplit")).Return(nil)
+		return useCase
+	}
+
+	t.Run("split the visit orders of the store", func(t *testing.T) {
+		useCase := setup(t, nil)
+
+		split, err := useCase.CreateBillSplit(ctx, "store_1", "visit_1", even)
+		require.NoError(t, err)
+		assert.Len(t, split.SessionIDs, 2)
+		assert.Equal(t, models.Yen(3300), split.Total)
+		require.Len(t, split.SubBills, 3)
+		assert.Equal(t, models.Yen(1100), split.SubBills[0].Amount)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).AssertCalled(t, "Create", ctx, split)
+	})
+
+	t.Run("redo voids the unpaid split", func(t *testing.T) {
+		previous := &models.BillSplit{ID: "split_old", StoreID: "store_1", Status: models.BillSplitOpen, SubBills: []models.SubBill{{ID: "subbill_1"}}}
+		useCase := setup(t, []*models.BillSplit{previous})
+
+		_, err := useCase.CreateBillSplit(ctx, "store_1", "visit_1", even)
+		require.NoError(t, err)
+		assert.Equal(t, models.BillSplitVoided, previous.Status)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).AssertCalled(t, "UpdateByID", ctx, "split_old", previous)
+	})
+
+	t.Run("redo is not allowed after a payment", func(t *testing.T) {
+		previous := &models.BillSplit{ID: "split_old", StoreID: "store_1", Status: models.BillSplitOpen, SubBills: []models.SubBill{{ID: "subbill_1", Paid: true}}}
+		useCase := setup(t, []*models.BillSplit{previous})
+
+		_, err := useCase.CreateBillSplit(ctx, "store_1", "visit_1", even)
+		assert.ErrorIs(t, err, models.ErrBillSplitInProgress)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("visit without orders of the store", func(t *testing.T) {
+		useCase := setup(t, nil)
+
+		_, err := useCase.CreateBillSplit(ctx, "store_3", "visit_1", even)
+		assert.ErrorIs(t, err, models.ErrCheckEmpty)
+	})
+}
+
+// TestPaySubBill tests the AssignSplitRemainder and PaySubBill functions
+func TestPaySubBill(t *testing.T) {
+	ctx := context.Background()
+	sessions := newBillSplitTestSessions(t)
+	check, err := models.NewCheck("visit_1", sessions[:2])
+	require.NoError(t, err)
+	split, err := models.NewBillSplit(check, models.SplitRequest{
+		Method: models.SplitByAmounts,
+		Payers: []models.SplitPayer{{Label: "A", Amount: models.Yen(2000)}},
+	}, time.Now())
+	require.NoError(t, err)
+
+	useCase := New(nil)
+	useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+	sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+	sessionRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+	for _, session := range sessions {
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	}
+	sessionRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.Session")).Return(nil)
+	splitRepo := useCase.billSplitRepo.(*repositories.MockBillSplitRepository)
+	splitRepo.On("FindByID", ctx, split.ID).Return(split, nil)
+	splitRepo.On("UpdateByID", ctx, split.ID, split).Return(nil)
+	visit := &models.Visit{ID: "visit_1", StoreID: "store_1", Status: models.VisitOpen}
+	visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+	visitRepo.On("FindByID", ctx, "visit_1").Return(visit, nil)
+	visitRepo.On("UpdateByID", ctx, "visit_1", visit).Return(nil)
+
+	updated, err := useCase.PaySubBill(ctx, "store_1", split.ID, split.SubBills[0].ID, models.TenderCard, "pay_1", "manager:a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, models.BillSplitOpen, updated.Status)
+	assert.Equal(t, models.Yen(1300), updated.Outstanding())
+	assert.True(t, visit.IsOpen())
+	assert.Equal(t, models.PaymentStatusUnpaid, sessions[0].PaymentStatus, "支払いが残っている間は未払いのまま")
+
+	// 同じ伝票の支払いを重ねて記録した場合は、最新の割り勘で支払い済みと判定する
+	_, err = useCase.PaySubBill(ctx, "store_1", split.ID, split.SubBills[0].ID, models.TenderCash, "", "manager:a@example.com")
+	assert.ErrorIs(t, err, models.ErrSubBillAlreadyPaid)
+	assert.Equal(t, models.TenderCard, split.SubBills[0].Method)
+
+	updated, err = useCase.AssignSplitRemainder(ctx, "store_1", split.ID, "B")
+	require.NoError(t, err)
+	require.Len(t, updated.SubBills, 2)
+	assert.Equal(t, models.Yen(1300), updated.SubBills[1].Amount)
+
+	updated, err = useCase.PaySubBill(ctx, "store_1", split.ID, updated.SubBills[1].ID, models.TenderCard, "pay_2", "manager:a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, models.BillSplitSettled, updated.Status)
+	for _, session := range sessions[:2] {
+		assert.Equal(t, models.PaymentStatusPaid, session.PaymentStatus, "全ての伝票の支払いで注文を支払い済みにする")
+		sessionRepo.AssertCalled(t, "UpdateByID", ctx, session.ID, session)
+	}
+	assert.Equal(t, models.PaymentStatusUnpaid, sessions[2].PaymentStatus, "他店舗の注文は変更しない")
+	assert.Equal(t, models.VisitClosed, visit.Status, "全ての伝票の支払いで来店の会計を終了する")
+	assert.Equal(t, split.ID, visit.SettlementID)
+
+	t.Run("split of another store", func(t *testing.T) {
+		_, err := useCase.PaySubBill(ctx, "store_2", split.ID, split.SubBills[0].ID, models.TenderCard, "pay_3", "manager:a@example.com")
+		assert.ErrorIs(t, err, models.ErrBillSplitNotFound)
+	})
+}
+
+// TestPaySubBill_VisitChanged tests that PaySubBill rejects a split created before the visit check changed
+func TestPaySubBill_VisitChanged(t *testing.T) {
+	ctx := context.Background()
+	sessions := newBillSplitTestSessions(t)
+	check, err := models.NewCheck("visit_1", sessions[:1])
+	require.NoError(t, err)
+	split, err := models.NewBillSplit(check, models.SplitRequest{Method: models.SplitEven, Parts: 2}, time.Now())
+	require.NoError(t, err)
+
+	useCase := New(nil)
+	useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+	// 割り勘の作成後に追加注文があった
+	useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+	splitRepo := useCase.billSplitRepo.(*repositories.MockBillSplitRepository)
+	splitRepo.On("FindByID", ctx, split.ID).Return(split, nil)
+	splitRepo.On("UpdateByID", ctx, split.ID, split).Return(nil)
+
+	_, err = useCase.PaySubBill(ctx, "store_1", split.ID, split.SubBills[0].ID, models.TenderCard, "pay_1", "manager:a@example.com")
+	assert.ErrorIs(t, err, models.ErrBillSplitStale)
+	assert.Equal(t, models.BillSplitVoided, split.Status, "支払い前の割り勘は無効にする")
+	assert.False(t, split.HasPayments())
+}
@@ -0,0 +1,232 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// expirySweeperLease は自動のステータス遷移を実行するインスタンスを1つに限るためのリースのキーです。
+const expirySweeperLease = "expiry_sweeper"
+
+var (
+	// 店舗の確認（先払いの店舗では支払い）を待っている注文のステータス
+	awaitingConfirmationStatuses = []models.Status{models.StatusCreated, models.StatusPendingConfirmation}
+	// 提供・引き渡し済みで、完了を待っている注文のステータス
+	fulfilledStatuses = []models.Status{models.StatusServed, models.StatusDelivered, models.StatusPickedUp}
+)
+
+// expiryPolicies は店舗ごとの自動のステータス遷移の設定を、1回の実行の間キャッシュします。
+type expiryPolicies struct {
+	u        *UseCase
+	policies map[string]models.ExpiryPolicy
+}
+
+// get は店舗の設定を返します。店舗が見つからない場合は既定の設定です。
+func (p *expiryPolicies) get(ctx context.Context, storeID string) (models.ExpiryPolicy, error) {
+	if policy, ok := p.policies[storeID]; ok {
+		return policy, nil
+	}
+	policy := models.DefaultExpiryPolicy()
+	store, err := p.u.storeRepo.FindByID(ctx, storeID)
+	if err != nil && !repositories.IsNotFound(err) {
+		return policy, fmt.Errorf("failed to find store: %w", err)
+	}
+	if err == nil {
+		policy = store.ExpiryPolicy()
+	}
+	p.policies[storeID] = policy
+	return policy, nil
+}
+
+var (
+	// errNotChanged は自動のステータス遷移の対象外だった注文・来店を保存せずに済ませるためのエラーです。
+	errNotChanged = errors.New("変更はありません")
+	// errSweepLeaseLost は実行中にリースを他のインスタンスに取得され、処理を中断したことを表します。
+	errSweepLeaseLost = errors.New("expiry sweeper lease was taken over by another instance")
+)
+
+// sweepLease は自動のステータス遷移の実行中にリースを延長します。
+// 処理が実行間隔（リースの期限）より長くかかっても、他のインスタンスが同時に実行しないようにします。
+type sweepLease struct {
+	u       *UseCase
+	holder  string
+	ttl     time.Duration
+	now     time.Time
+	started time.Time
+	renewed time.Duration
+}
+
+// renew は前回の取得から期限の半分が経過していればリースを延長します。リースを失った場合は errSweepLeaseLost を返します。
+func (l *sweepLease) renew(ctx context.Context) error {
+	elapsed := time.Since(l.started)
+	if elapsed-l.renewed < l.ttl/2 {
+		return nil
+	}
+	acquired, err := l.u.leases.Acquire(ctx, expirySweeperLease, l.holder, l.ttl, l.now.Add(elapsed))
+	if err != nil {
+		return fmt.Errorf("failed to renew expiry sweeper lease: %w", err)
+	}
+	if !acquired {
+		return errSweepLeaseLost
+	}
+	l.renewed = elapsed
+	return nil
+}
+
+// SweepExpired は時間経過による自動のステータス遷移を実行します。
+//   - 店舗に確認されないまま有効期限を過ぎた注文をキャンセル・辞退にする
+//   - 提供済みで支払い済みの注文を、設定した時間の経過後に完了にする
+//   - 未払いの注文がなく操作のない来店を終了し、座席を空席に戻す
+//
+// 複数インスタンスで同時に実行しないよう、holder が ttl の間リースを取得できた場合のみ実行し、実行中はリースを延長します。
+// 注文・来店はそれぞれ不可分に読み取り直して判定してから更新し、スタッフの操作と同時に更新しても上書きしません。
+// 個別の注文・来店の更新に失敗しても残りの処理は続け、エラーはまとめて返します。
+func (u *UseCase) SweepExpired(ctx context.Context, holder string, ttl time.Duration, now time.Time) (*models.SweepResult, error) {
+	result := &models.SweepResult{}
+	acquired, err := u.leases.Acquire(ctx, expirySweeperLease, holder, ttl, now)
+	if err != nil {
+		return result, fmt.Errorf("failed to acquire expiry sweeper lease: %w", err)
+	}
+	if !acquired {
+		return result, nil
+	}
+	lease := &sweepLease{u: u, holder: holder, ttl: ttl, now: now, started: time.Now()}
+
+	policies := &expiryPolicies{u: u, policies: make(map[string]models.ExpiryPolicy)}
+	var errs []error
+
+	// sweep は due の対象の注文を1件ずつトランザクションで読み取り直し、update で変更した注文を保存します。
+	// 検索の後にスタッフが操作して対象外になった注文は、update が false を返すため保存しません。
+	sweep := func(sessions []*models.Session, due func(*models.Session, models.ExpiryPolicy) bool,
+		update func(*models.Session, models.ExpiryPolicy) (bool, error), count *int, action string) error {
+		for _, session := range sessions {
+			policy, err := policies.get(ctx, session.StoreID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !due(session, policy) {
+				continue
+			}
+			if err := lease.renew(ctx); err != nil {
+				return err
+			}
+
+			_, err = u.sessionUpdates.Update(ctx, session.ID, func(session *models.Session) error {
+				changed, err := update(session, policy)
+				if err == nil && !changed {
+					return errNotChanged
+				}
+				return err
+			})
+			if errors.Is(err, errNotChanged) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to %s order %s: %w", action, session.ID, err))
+				continue
+			}
+			*count++
+		}
+		return nil
+	}
+
+	// 確認待ちの注文は、有効期限を過ぎた注文のみを検索する
+	for _, status := range awaitingConfirmationStatuses {
+		sessions, err := u.expiredSessions.FindExpired(ctx, status, now)
+		if err != nil {
+			return result, fmt.Errorf("failed to find orders: %w", err)
+		}
+		expire := func(session *models.Session, policy models.ExpiryPolicy) (bool, error) {
+			return session.ExpireUnconfirmed(policy, now)
+		}
+		due := func(session *models.Session, policy models.ExpiryPolicy) bool {
+			return session.ShouldExpireUnconfirmed(policy, now)
+		}
+		if err := sweep(sessions, due, expire, &result.Expired, "expire"); err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+	}
+
+	for _, status := range fulfilledStatuses {
+		sessions, err := u.sessionRepo.FindByField(ctx, "status", string(status))
+		if err != nil {
+			return result, fmt.Errorf("failed to find orders: %w", err)
+		}
+		complete := func(session *models.Session, policy models.ExpiryPolicy) (bool, error) {
+			return session.AutoComplete(policy, now)
+		}
+		due := func(session *models.Session, policy models.ExpiryPolicy) bool {
+			return session.ShouldAutoComplete(policy, now)
+		}
+		if err := sweep(sessions, due, complete, &result.Completed, "complete"); err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+	}
+
+	visits, err := u.visitRepo.FindByField(ctx, "status", string(models.VisitOpen))
+	if err != nil {
+		return result, fmt.Errorf("failed to find visits: %w", err)
+	}
+	for _, visit := range visits {
+		if err := lease.renew(ctx); err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+		closed, err := u.abandonVisit(ctx, policies, visit, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close visit %s: %w", visit.ID, err))
+			continue
+		}
+		if closed {
+			result.VisitsClosed++
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// abandonVisit は操作のない来店を終了し、座席がまだその来店に使われている場合は空席に戻します。
+func (u *UseCase) abandonVisit(ctx context.Context, policies *expiryPolicies, visit *models.Visit, now time.Time) (bool, error) {
+	policy, err := policies.get(ctx, visit.StoreID)
+	if err != nil {
+		return false, err
+	}
+	visitOrders, err := u.findVisitOrders(ctx, visit.StoreID, visit.ID)
+	if err != nil {
+		return false, err
+	}
+	if !visit.IsAbandoned(visitOrders, policy, now) {
+		return false, nil
+	}
+	// 検索の後に追加注文などで来店が更新されていないよう、来店を読み取り直して判定する
+	visit, err = u.visitUpdates.Update(ctx, visit.ID, func(visit *models.Visit) error {
+		if !visit.IsAbandoned(visitOrders, policy, now) || !visit.Abandon(now) {
+			return errNotChanged
+		}
+		return nil
+	})
+	if errors.Is(err, errNotChanged) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	seat, err := u.findStoreSeat(ctx, visit.StoreID, visit.SeatID)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return true, nil
+		}
+		return true, err
+	}
+	if seat.CurrentVisitID == visit.ID {
+		if _, err := u.CloseSeat(ctx, visit.StoreID, seat.ID); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
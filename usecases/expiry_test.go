@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSweepExpired tests the SweepExpired function
+func TestSweepExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	newOrder := func(t *testing.T) *models.Session {
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(500))})
+		require.NoError(t, err)
+		return session
+	}
+
+	useCase := New(nil)
+	stale := newOrder(t)
+	stale.ExpiresAt = now.Add(-time.Minute)
+	fresh := newOrder(t)
+	served := newOrder(t)
+	require.NoError(t, served.UpdatePaymentStatus(models.PaymentStatusPaid, "", "オンライン決済"))
+	require.NoError(t, served.UpdateStatus(models.StatusConfirmed))
+	for _, line := range served.Items {
+		require.NoError(t, served.UpdateLineStatus(line.LineID, models.LineCooking, "", now.Add(-time.Hour)))
+		require.NoError(t, served.UpdateLineStatus(line.LineID, models.LineServed, "", now.Add(-time.Hour)))
+	}
+	served.StatusHistory[len(served.StatusHistory)-1].At = now.Add(-time.Hour)
+
+	seat := &models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}
+	idle := models.NewVisit(seat, now.Add(-4*time.Hour))
+	active := models.NewVisit(&models.Seat{ID: "seat_2", StoreID: "store_1", CurrentVisitID: "visit_2"}, now)
+
+	sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+	sessionRepo.On("FindByField", ctx, "status", "created").Return([]*models.Session{stale, fresh}, nil)
+	sessionRepo.On("FindByField", ctx, "status", "pending_confirmation").Return([]*models.Session{}, nil)
+	sessionRepo.On("FindByField", ctx, "status", "served").Return([]*models.Session{served}, nil)
+	sessionRepo.On("FindByField", ctx, "status", "delivered").Return([]*models.Session{}, nil)
+	sessionRepo.On("FindByField", ctx, "status", "picked_up").Return([]*models.Session{}, nil)
+	sessionRepo.On("FindByField", ctx, "visit_id", mock.Anything).Return([]*models.Session{}, nil)
+	sessionRepo.On("FindByID", ctx, stale.ID).Return(stale, nil)
+	sessionRepo.On("FindByID", ctx, served.ID).Return(served, nil)
+	sessionRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.Session")).Return(nil)
+	useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", UnconfirmedAction: models.ExpiryDecline}, nil)
+	visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+	visitRepo.On("FindByField", ctx, "status", "open").Return([]*models.Visit{idle, active}, nil)
+	visitRepo.On("FindByID", ctx, "visit_1").Return(idle, nil)
+	visitRepo.On("UpdateByID", ctx, "visit_1", idle).Return(nil)
+	seatRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+	seatRepo.On("FindByID", ctx, "seat_1").Return(seat, nil)
+	seatRepo.On("UpdateByID", ctx, "seat_1", seat).Return(nil)
+	useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.SessionToken{}, nil)
+
+	result, err := useCase.SweepExpired(ctx, "instance_a", time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, &models.SweepResult{Expired: 1, Completed: 1, VisitsClosed: 1}, result)
+	assert.Equal(t, models.StatusDeclined, stale.Status, "店舗の設定に従って辞退にする")
+	assert.Equal(t, models.StatusCreated, fresh.Status)
+	assert.Equal(t, models.StatusCompleted, served.Status)
+	assert.Equal(t, models.VisitAbandoned, idle.Status)
+	assert.True(t, active.IsOpen())
+	assert.Equal(t, "", seat.CurrentVisitID, "座席を空席に戻す")
+
+	t.Run("another instance holds the lease", func(t *testing.T) {
+		result, err := useCase.SweepExpired(ctx, "instance_b", time.Minute, now.Add(30*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, &models.SweepResult{}, result)
+		sessionRepo.AssertNumberOfCalls(t, "FindByField", 7)
+	})
+	sessionRepo.AssertNotCalled(t, "FindByID", ctx, fresh.ID)
+
+	t.Run("order confirmed after the search is not expired", func(t *testing.T) {
+		useCase := New(nil)
+		listed := newOrder(t)
+		listed.ExpiresAt = now.Add(-time.Minute)
+		// 検索の後に店舗が確認した
+		stored := *listed
+		require.NoError(t, stored.UpdateStatus(models.StatusConfirmed))
+
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByField", ctx, "status", "created").Return([]*models.Session{listed}, nil)
+		sessionRepo.On("FindByField", ctx, "status", mock.Anything).Return([]*models.Session{}, nil)
+		sessionRepo.On("FindByID", ctx, listed.ID).Return(&stored, nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+		useCase.visitRepo.(*repositories.MockVisitRepository).On("FindByField", ctx, "status", "open").Return([]*models.Visit{}, nil)
+
+		result, err := useCase.SweepExpired(ctx, "instance_a", time.Minute, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Expired)
+		assert.Equal(t, models.StatusConfirmed, stored.Status)
+		sessionRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stop when the lease is taken over", func(t *testing.T) {
+		useCase := New(nil)
+		leases := &handoverLeaseStore{}
+		useCase.leases = leases
+		first, second := newOrder(t), newOrder(t)
+		first.ExpiresAt = now.Add(-time.Minute)
+		second.ExpiresAt = now.Add(-time.Minute)
+
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByField", ctx, "status", "created").Return([]*models.Session{first, second}, nil)
+		sessionRepo.On("FindByID", ctx, first.ID).Return(first, nil)
+		sessionRepo.On("UpdateByID", ctx, first.ID, first).Return(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+
+		// 期限0のリースは処理のたびに延長し、2件目の前に他のインスタンスに取得される
+		result, err := useCase.SweepExpired(ctx, "instance_a", 0, now)
+		assert.ErrorIs(t, err, errSweepLeaseLost)
+		assert.Equal(t, 1, result.Expired)
+		assert.Equal(t, models.StatusCreated, second.Status)
+		sessionRepo.AssertNotCalled(t, "FindByID", ctx, second.ID)
+	})
+}
+
+// handoverLeaseStore は2回取得した後、他のインスタンスにリースを取得されたものとして扱う LeaseStore です。
+type handoverLeaseStore struct {
+	acquired int
+}
+
+func (s *handoverLeaseStore) Acquire(ctx context.Context, key, holder string, ttl time.Duration, now time.Time) (bool, error) {
+	s.acquired++
+	return s.acquired <= 2, nil
+}
@@ -2,7 +2,9 @@ package usecases
 
 import (
 	"backend/models"
+	"backend/repositories"
 	"context"
+	"time"
 )
 
 func (u *UseCase) ManagerSignUp(ctx context.Context, email, password string) error {
@@ -17,12 +19,41 @@ func (u *UseCase) ManagerSignUp(ctx context.Context, email, password string) err
 	return u.managerRepo.Create(ctx, manager)
 }
 
+// ManagerSignIn はマネージャーの認証情報を検証します。
+// 失敗が続いたアカウントは段階的にロックされ、ロック中は *models.AccountLockedError を返します。
+// メールアドレスが存在しない場合とパスワードが一致しない場合は、区別せず models.ErrInvalidCredentials を返します。
 func (u *UseCase) ManagerSignIn(ctx context.Context, email, password string) error {
-	gotUser, err := u.managerRepo.FindByID(ctx, email)
+	now := time.Now().UTC()
+	attempt, err := u.loginAttempts.Get(ctx, email)
 	if err != nil {
 		return err
 	}
+	if attempt.IsLocked(now) {
+		return &models.AccountLockedError{Until: attempt.LockedUntil}
+	}
+
+	gotUser, err := u.managerRepo.FindByID(ctx, email)
+	if err != nil && !repositories.IsNotFound(err) {
+		return err
+	}
 
 	// パスワードの検証
-	return gotUser.IsVerifyPassword(password)
+	if err != nil || gotUser.IsVerifyPassword(password) != nil {
+		return u.registerSignInFailure(ctx, email, now)
+	}
+
+	return u.loginAttempts.Delete(ctx, email)
+}
+
+// registerSignInFailure はサインイン失敗を記録し、呼び出し元に返すエラーを決定します。
+// 同時に失敗した場合も回数を取りこぼさないよう、ロックの判定には記録後の失敗履歴を使用します。
+func (u *UseCase) registerSignInFailure(ctx context.Context, email string, now time.Time) error {
+	attempt, err := u.loginAttempts.RegisterFailure(ctx, email, now, u.lockoutPolicy)
+	if err != nil {
+		return err
+	}
+	if attempt.IsLocked(now) {
+		return &models.AccountLockedError{Until: attempt.LockedUntil}
+	}
+	return models.ErrInvalidCredentials
 }
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -456,3 +457,56 @@ func TestInputValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestManagerSignInLockout tests the progressive lockout after repeated sign in failures
+func TestManagerSignInLockout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("wrong password returns invalid credentials", func(t *testing.T) {
+		useCase := New(nil)
+		manager := models.NewManager("lock@example.com", "correctpassword")
+		assert.NoError(t, manager.ToEncryptPassword())
+
+		mockRepo := useCase.managerRepo.(*repositories.MockManagerRepository)
+		mockRepo.On("FindByID", ctx, "lock@example.com").Return(manager, nil)
+
+		err := useCase.ManagerSignIn(ctx, "lock@example.com", "wrongpassword")
+		assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+	})
+
+	t.Run("account is locked after max failures", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.lockoutPolicy = models.LockoutPolicy{MaxFailures: 3, BaseDuration: time.Minute, MaxDuration: time.Hour}
+		manager := models.NewManager("lock@example.com", "correctpassword")
+		assert.NoError(t, manager.ToEncryptPassword())
+
+		mockRepo := useCase.managerRepo.(*repositories.MockManagerRepository)
+		mockRepo.On("FindByID", ctx, "lock@example.com").Return(manager, nil).Times(3)
+
+		for i := 0; i < 2; i++ {
+			assert.ErrorIs(t, useCase.ManagerSignIn(ctx, "lock@example.com", "wrongpassword"), models.ErrInvalidCredentials)
+		}
+
+		var lockedErr *models.AccountLockedError
+		assert.ErrorAs(t, useCase.ManagerSignIn(ctx, "lock@example.com", "wrongpassword"), &lockedErr)
+		assert.True(t, lockedErr.Until.After(time.Now().UTC()))
+
+		// ロック中は正しいパスワードでもリポジトリを参照せずに拒否する
+		assert.ErrorAs(t, useCase.ManagerSignIn(ctx, "lock@example.com", "correctpassword"), &lockedErr)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("successful sign in clears failures", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.lockoutPolicy = models.LockoutPolicy{MaxFailures: 2, BaseDuration: time.Minute, MaxDuration: time.Hour}
+		manager := models.NewManager("lock@example.com", "correctpassword")
+		assert.NoError(t, manager.ToEncryptPassword())
+
+		mockRepo := useCase.managerRepo.(*repositories.MockManagerRepository)
+		mockRepo.On("FindByID", ctx, "lock@example.com").Return(manager, nil)
+
+		assert.ErrorIs(t, useCase.ManagerSignIn(ctx, "lock@example.com", "wrongpassword"), models.ErrInvalidCredentials)
+		assert.NoError(t, useCase.ManagerSignIn(ctx, "lock@example.com", "correctpassword"))
+		assert.ErrorIs(t, useCase.ManagerSignIn(ctx, "lock@example.com", "wrongpassword"), models.ErrInvalidCredentials, "成功後は失敗回数がリセットされる")
+	})
+}
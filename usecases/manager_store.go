@@ -6,9 +6,11 @@ import (
 	"fmt"
 )
 
-func (u *UseCase) RegisterStore(ctx context.Context, name, email, password, address, phone string) (*models.Store, error) {
+// RegisterStore は店舗を登録します。organizationID は店舗が属する組織（登録したマネージャーのメールアドレス）です。
+func (u *UseCase) RegisterStore(ctx context.Context, organizationID, name, email, password, address, phone string) (*models.Store, error) {
 	// 入力値から店舗を作成
 	store := models.NewStore(name, email, password, address, phone)
+	store.OrganizationID = organizationID
 
 	if err := store.PasswordToHash(); err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -57,3 +59,268 @@ func (u *UseCase) Update(ctx context.Context, id, name, email, password, address
 func (u *UseCase) Delete(ctx context.Context, id string) error {
 	return u.storeRepo.DeleteByID(ctx, id)
 }
+
+// UpdateStoreNetworkRestriction は店舗ネットワーク外からの注文の扱いを設定します。
+// mode が "enforce" の場合は許可CIDR外からの注文を拒否し、"soft" の場合はスタッフ確認のフラグを立てます。
+func (u *UseCase) UpdateStoreNetworkRestriction(ctx context.Context, id string, allowedCIDRs []string, mode models.NetworkRestriction) (*models.Store, error) {
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+
+	store.AllowedCIDRs = allowedCIDRs
+	store.NetworkRestriction = mode
+	if mode == "" {
+		store.NetworkRestriction = models.NetworkRestrictionOff
+	}
+	if err := store.ValidateNetworkRestriction(); err != nil {
+		return nil, err
+	}
+
+	// パスワード等は空にして、ネットワーク制限のみを更新対象にする
+	update := &models.Store{
+		ID:                 store.ID,
+		AllowedCIDRs:       store.AllowedCIDRs,
+		NetworkRestriction: store.NetworkRestriction,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store network restriction: %w", err)
+	}
+	return store, nil
+}
+
+// UpdateStoreTaxPolicy は店舗の消費税の計算設定（税込・税抜、端数処理）と、お客様の注文に適用する店内飲食・持ち帰りの区分を更新します。
+// dining が空の場合は区分を変更しません。設定は以降の注文にのみ適用され、既存の注文の税額は変わりません。
+func (u *UseCase) UpdateStoreTaxPolicy(ctx context.Context, id string, policy models.TaxPolicy, dining models.DiningOption) (*models.Store, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if !dining.IsValid() {
+		return nil, fmt.Errorf("%w: %q", models.ErrInvalidDiningOption, dining)
+	}
+	policy = policy.WithDefaults()
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.TaxPriceMode = policy.PriceMode
+	store.TaxRounding = policy.Rounding
+	if dining != "" {
+		store.DiningOption = dining
+	}
+
+	// パスワード等は空にして、税設定のみを更新対象にする
+	update := &models.Store{
+		ID:           store.ID,
+		TaxPriceMode: store.TaxPriceMode,
+		TaxRounding:  store.TaxRounding,
+		DiningOption: dining,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store tax policy: %w", err)
+	}
+	return store, nil
+}
+
+// UpdateStoreInvoiceRegistration は店舗の適格請求書発行事業者の登録番号を設定します。
+// 登録番号はハイフンや空白を取り除いてから「T」と13桁の数字であることを検証します。
+func (u *UseCase) UpdateStoreInvoiceRegistration(ctx context.Context, id, number string) (*models.Store, error) {
+	number = models.NormalizeInvoiceRegistrationNumber(number)
+	if err := models.ValidateInvoiceRegistrationNumber(number); err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.InvoiceRegistrationNumber = number
+
+	// パスワード等は空にして、登録番号のみを更新対象にする
+	update := &models.Store{
+		ID:                        store.ID,
+		InvoiceRegistrationNumber: store.InvoiceRegistrationNumber,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store invoice registration: %w", err)
+	}
+	return store, nil
+}
+
+// UpdateStoreChargeRules は店舗のチャージ（お通し代、席料、サービス料）の設定を置き換えます。
+// 設定は以降の注文にのみ適用され、適用済みのチャージは変わりません。
+func (u *UseCase) UpdateStoreChargeRules(ctx context.Context, id string, rules []models.ChargeRule) (*models.Store, error) {
+	rules, err := models.NormalizeChargeRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.ChargeRules = rules
+
+	// パスワード等は空にして、チャージの設定のみを更新対象にする（空のスライスは全て削除）
+	update := &models.Store{
+		ID:          store.ID,
+		ChargeRules: rules,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store charge rules: %w", err)
+	}
+	return store, nil
+}
+
+// GetMenu はお客様の端末に表示する、店舗の提供中のメニューを返します。
+func (u *UseCase) GetMenu(ctx context.Context, storeID string) ([]models.Product, error) {
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	return store.ActiveProducts(), nil
+}
+
+// UpdateStoreProducts は店舗のメニュー（商品カタログ）を置き換えます。注文済みの商品の単価は変わりません。
+func (u *UseCase) UpdateStoreProducts(ctx context.Context, id string, products []models.Product) (*models.Store, error) {
+	products, err := models.NormalizeProducts(products)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.Products = products
+
+	// パスワード等は空にして、メニューのみを更新対象にする（空のスライスは全て削除）
+	update := &models.Store{
+		ID:       store.ID,
+		Products: products,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store products: %w", err)
+	}
+	return store, nil
+}
+
+// UpdateStoreReasonCodes は店舗のキャンセル・辞退・保留・明細の取り消しの理由コードの一覧を置き換えます。
+// 空の一覧を指定した場合は既定の一覧に戻します。記録済みの理由コードは変わりません。
+func (u *UseCase) UpdateStoreReasonCodes(ctx context.Context, id string, codes []models.ReasonCode) (*models.Store, error) {
+	codes, err := models.NormalizeReasonCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.ReasonCodes = codes
+
+	// パスワード等は空にして、理由コードのみを更新対象にする
+	update := &models.Store{
+		ID:          store.ID,
+		ReasonCodes: codes,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store reason codes: %w", err)
+	}
+	return store, nil
+}
+
+// UpdateStoreSupervisors は注文のステータスの強制変更を承認できる責任者と、
+// 責任者のPINなしで強制変更を行えるマネージャー（overrideManagers）を設定します。既存の設定は置き換えます。
+func (u *UseCase) UpdateStoreSupervisors(ctx context.Context, id string, entries []models.SupervisorPIN, overrideManagers []string) (*models.Store, error) {
+	supervisors, err := models.NewSupervisors(entries)
+	if err != nil {
+		return nil, err
+	}
+	managers, err := models.NewOverrideManagers(overrideManagers)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.Supervisors = supervisors
+	store.OverrideManagers = managers
+
+	// パスワード等は空にして、責任者と強制変更を許可するマネージャーのみを更新対象にする
+	update := &models.Store{
+		ID:               store.ID,
+		Supervisors:      supervisors,
+		OverrideManagers: managers,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store supervisors: %w", err)
+	}
+	return store, nil
+}
+
+// GetStoreWorkflow は店舗が選択した注文のワークフローを返します。
+func (u *UseCase) GetStoreWorkflow(ctx context.Context, id string) (*models.Workflow, error) {
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	return store.OrderWorkflow(), nil
+}
+
+// UpdateStoreWorkflow は店舗の注文のワークフローを選択します。
+// 設定は以降の注文にのみ適用され、進行中の注文は作成時のワークフローに従います。
+func (u *UseCase) UpdateStoreWorkflow(ctx context.Context, id, name string) (*models.Store, error) {
+	workflow, err := models.LookupWorkflow(name)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.Workflow = workflow.Name()
+
+	// パスワード等は空にして、ワークフローのみを更新対象にする
+	update := &models.Store{
+		ID:       store.ID,
+		Workflow: store.Workflow,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store workflow: %w", err)
+	}
+	return store, nil
+}
+
+// UpdateStoreExpiryPolicy は店舗の自動のステータス遷移の設定を置き換えます。
+// 未設定の項目は既定値として保存します。
+func (u *UseCase) UpdateStoreExpiryPolicy(ctx context.Context, id string, policy models.ExpiryPolicy) (*models.Store, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	policy = policy.WithDefaults()
+
+	store, err := u.storeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	store.UnconfirmedAction = policy.UnconfirmedAction
+	store.AutoCompleteAfter = policy.AutoCompleteAfter
+	store.VisitIdleTimeout = policy.VisitIdleTimeout
+
+	// パスワード等は空にして、自動のステータス遷移の設定のみを更新対象にする
+	update := &models.Store{
+		ID:                store.ID,
+		UnconfirmedAction: store.UnconfirmedAction,
+		AutoCompleteAfter: store.AutoCompleteAfter,
+		VisitIdleTimeout:  store.VisitIdleTimeout,
+	}
+	if err := u.storeRepo.UpdateByID(ctx, store.ID, update); err != nil {
+		return nil, fmt.Errorf("failed to update store expiry policy: %w", err)
+	}
+	return store, nil
+}
@@ -5,6 +5,7 @@ import (
 	"backend/repositories"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,7 +29,7 @@ func TestRegisterStore(t *testing.T) {
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Store")).Return(nil)
 
 		// Act
-		store, err := useCase.RegisterStore(ctx, "Test Store", "test@example.com", "password123", "123 Test St", "123-456-7890")
+		store, err := useCase.RegisterStore(ctx, "manager@example.com", "Test Store", "test@example.com", "password123", "123 Test St", "123-456-7890")
 
 		// Assert
 		assert.NoError(t, err)
@@ -38,6 +39,7 @@ func TestRegisterStore(t *testing.T) {
 		assert.NotEqual(t, "password123", store.Password) // Password should be hashed
 		assert.Equal(t, "123 Test St", store.Address)
 		assert.Equal(t, "123-456-7890", store.Phone)
+		assert.Equal(t, "manager@example.com", store.OrganizationID)
 		assert.NotEmpty(t, store.ID)
 		mockRepo.AssertExpectations(t)
 	})
@@ -51,7 +53,7 @@ func TestRegisterStore(t *testing.T) {
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Store")).Return(nil)
 
 		// Act
-		store, err := useCase.RegisterStore(ctx, "", "test@example.com", "password123", "123 Test St", "123-456-7890")
+		store, err := useCase.RegisterStore(ctx, "manager@example.com", "", "test@example.com", "password123", "123 Test St", "123-456-7890")
 
 		// Assert
 		assert.NoError(t, err)
@@ -69,7 +71,7 @@ func TestRegisterStore(t *testing.T) {
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Store")).Return(nil)
 
 		// Act
-		store, err := useCase.RegisterStore(ctx, "Test Store", "", "password123", "123 Test St", "123-456-7890")
+		store, err := useCase.RegisterStore(ctx, "manager@example.com", "Test Store", "", "password123", "123 Test St", "123-456-7890")
 
 		// Assert
 		assert.NoError(t, err)
@@ -85,7 +87,7 @@ func TestRegisterStore(t *testing.T) {
 		// No mock setup needed as this should fail before reaching the repository
 
 		// Act
-		store, err := useCase.RegisterStore(ctx, "Test Store", "test@example.com", "", "123 Test St", "123-456-7890")
+		store, err := useCase.RegisterStore(ctx, "manager@example.com", "Test Store", "test@example.com", "", "123 Test St", "123-456-7890")
 
 		// Assert
 		assert.Error(t, err)
@@ -352,3 +354,267 @@ func TestUpdateLogic(t *testing.T) {
 		assert.Equal(t, "", store.Password) // Empty password should remain empty
 	})
 }
+
+// TestUpdateStoreNetworkRestriction tests the UpdateStoreNetworkRestriction function
+func TestUpdateStoreNetworkRestriction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("enable soft mode", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.NetworkRestriction == models.NetworkRestrictionSoft && s.Password == "" && len(s.AllowedCIDRs) == 1
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreNetworkRestriction(ctx, "store_1", []string{"192.168.1.0/24"}, models.NetworkRestrictionSoft)
+		assert.NoError(t, err)
+		assert.Equal(t, models.NetworkRestrictionSoft, store.NetworkRestriction)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid CIDR is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+
+		_, err := useCase.UpdateStoreNetworkRestriction(ctx, "store_1", []string{"invalid"}, models.NetworkRestrictionEnforce)
+		assert.ErrorIs(t, err, models.ErrInvalidCIDR)
+		mockRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestUpdateStoreTaxPolicy tests the UpdateStoreTaxPolicy function
+func TestUpdateStoreTaxPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("update to exclusive", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.TaxPriceMode == models.PriceModeExclusive && s.TaxRounding == models.RoundHalfUp && s.DiningOption == models.DiningTakeout && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreTaxPolicy(ctx, "store_1", models.TaxPolicy{PriceMode: models.PriceModeExclusive, Rounding: models.RoundHalfUp}, models.DiningTakeout)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PriceModeExclusive, store.TaxPolicy().PriceMode)
+		assert.Equal(t, models.DiningTakeout, store.DiningOption)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid price mode is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreTaxPolicy(ctx, "store_1", models.TaxPolicy{PriceMode: "gross"}, "")
+		assert.ErrorIs(t, err, models.ErrInvalidPriceMode)
+		_, err = useCase.UpdateStoreTaxPolicy(ctx, "store_1", models.TaxPolicy{}, "delivery")
+		assert.ErrorIs(t, err, models.ErrInvalidDiningOption)
+		mockRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStoreChargeRules(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replace rules", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return len(s.ChargeRules) == 1 && s.ChargeRules[0].ID != "" && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreChargeRules(ctx, "store_1", []models.ChargeRule{
+			{Name: "お通し", Kind: models.ChargePerPerson, Value: 300, StartTime: "17:00", EndTime: "02:00", Active: true},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, store.ChargeRules, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("clear rules", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.ChargeRules != nil && len(s.ChargeRules) == 0
+		})).Return(nil)
+
+		_, err := useCase.UpdateStoreChargeRules(ctx, "store_1", nil)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid rule is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreChargeRules(ctx, "store_1", []models.ChargeRule{{Name: "席料", Kind: models.ChargePerSeat}})
+		assert.ErrorIs(t, err, models.ErrInvalidChargeValue)
+		mockRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStoreProducts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replace products", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return len(s.Products) == 1 && s.Products[0].ID != "" && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreProducts(ctx, "store_1", []models.Product{{Name: "ラーメン", Price: 900, Active: true}})
+		assert.NoError(t, err)
+		assert.Len(t, store.Products, 1)
+		mockRepo.AssertExpectations(t)
+
+		menu, err := useCase.GetMenu(ctx, "store_1")
+		assert.NoError(t, err)
+		assert.Len(t, menu, 1)
+	})
+
+	t.Run("invalid product is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreProducts(ctx, "store_1", []models.Product{{Name: "ラーメン", Price: -1}})
+		assert.ErrorIs(t, err, models.ErrInvalidProduct)
+		mockRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStoreWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("select workflow", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.Workflow == models.WorkflowCounter && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreWorkflow(ctx, "store_1", models.WorkflowCounter)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WorkflowCounter, store.Workflow)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty name selects default", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Workflow: models.WorkflowCounter}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.Workflow == models.WorkflowDefault
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreWorkflow(ctx, "store_1", "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.WorkflowDefault, store.Workflow)
+	})
+
+	t.Run("unknown workflow is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreWorkflow(ctx, "store_1", "drive_through")
+		assert.ErrorIs(t, err, models.ErrWorkflowNotFound)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStoreExpiryPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unset fields are saved as defaults", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.UnconfirmedAction == models.ExpiryDecline && s.AutoCompleteAfter == models.DefaultAutoCompleteAfter &&
+				s.VisitIdleTimeout == time.Hour && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreExpiryPolicy(ctx, "store_1", models.ExpiryPolicy{UnconfirmedAction: models.ExpiryDecline, VisitIdleTimeout: time.Hour})
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour, store.ExpiryPolicy().VisitIdleTimeout)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid policy is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreExpiryPolicy(ctx, "store_1", models.ExpiryPolicy{AutoCompleteAfter: time.Second})
+		assert.ErrorIs(t, err, models.ErrInvalidExpiryPolicy)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStoreReasonCodes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("codes replace the catalog", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return len(s.ReasonCodes) == 1 && s.ReasonCodes[0].Label == "閉店間際" && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreReasonCodes(ctx, "store_1", []models.ReasonCode{
+			{Kind: models.ReasonDecline, Code: "closing", Label: " 閉店間際 ", Active: true},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, store.ReasonCodeCatalog(), 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid codes are rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreReasonCodes(ctx, "store_1", []models.ReasonCode{
+			{Kind: models.ReasonCancel, Code: models.ReasonCodeExpired, Label: "期限切れ", Active: true},
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidReasonCode)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStoreSupervisors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("PINs are saved as hashes", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "Store"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return len(s.Supervisors) == 1 && s.Supervisors[0].Name == "佐藤" && s.Supervisors[0].PINHash != "246810" && s.Password == "" &&
+				assert.ObjectsAreEqual([]string{"a@example.com"}, s.OverrideManagers)
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreSupervisors(ctx, "store_1", []models.SupervisorPIN{{Name: "佐藤", PIN: "246810"}}, []string{"a@example.com", " a@example.com"})
+		assert.NoError(t, err)
+		_, err = store.VerifySupervisorPIN("246810")
+		assert.NoError(t, err)
+		assert.True(t, store.AllowsManagerOverride("a@example.com"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid PIN is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+
+		_, err := useCase.UpdateStoreSupervisors(ctx, "store_1", []models.SupervisorPIN{{Name: "佐藤", PIN: "1234"}}, nil)
+		assert.ErrorIs(t, err, models.ErrInvalidSupervisor)
+		_, err = useCase.UpdateStoreSupervisors(ctx, "store_1", nil, []string{""})
+		assert.ErrorIs(t, err, models.ErrInvalidOverrideManager)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,284 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+// PlaceOrder は座席セッションからの注文を作成します。
+// 商品の単価・通貨・カテゴリは店舗のメニューから決定し、メニューにない商品は models.ErrUnknownProduct を返します。
+// 店舗の消費税設定（税込・税抜、端数処理）と、店舗に設定した店内飲食・持ち帰りの区分から税額を計算します。
+// 区分はお客様が指定できず、異なる場合はスタッフが ChangeOrderDiningOption で変更します。
+// 注文には店舗で選択されたワークフローを記録し、以降の状態遷移はそのワークフローに従います。
+// 店舗のチャージ設定は来店（visitID）単位で適用します。人数はお客様が指定できず、スタッフが SetVisitPartySize で
+// 登録した来店人数を使用します（未登録の場合は同じ来店の注文の人数を引き継ぎます）。
+// reviewReason が指定された場合は、注文を受け付けた上でスタッフの確認対象としてマークします。
+// 注文は追加注文の1回分として来店の会計に追加します。会計が済んだ来店には注文できません。
+func (u *UseCase) PlaceOrder(ctx context.Context, storeID, seatID, visitID string, items []models.Order, reviewReason string) (*models.Session, error) {
+	if len(items) == 0 {
+		return nil, models.ErrNoItems
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	items, err = store.PriceItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	newOrder := func() (*models.Session, error) {
+		session, err := models.NewSession(storeID, seatID, items)
+		if err != nil {
+			return nil, err
+		}
+		session.VisitID = visitID
+
+		if err := session.SetWorkflow(store.Workflow); err != nil {
+			return nil, err
+		}
+		if err := session.SetTaxPolicy(store.TaxPolicy(), store.DiningOption); err != nil {
+			return nil, err
+		}
+		if reviewReason != "" {
+			session.FlagForReview(reviewReason)
+		}
+		return session, nil
+	}
+
+	if visitID == "" {
+		session, err := newOrder()
+		if err != nil {
+			return nil, err
+		}
+		if err := u.applyChargeRules(ctx, store, session); err != nil {
+			return nil, err
+		}
+		if err := u.sessionRepo.Create(ctx, session); err != nil {
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+		return session, nil
+	}
+
+	// 注文の作成と来店への追加は同時に保存し、片方だけが保存された注文を再送して重複させない
+	seat := &models.Seat{ID: seatID, StoreID: storeID, CurrentVisitID: visitID}
+	var orderErr error
+	session, err := u.visitUpdates.PlaceRound(ctx, models.NewVisit(seat, time.Now()), func(visit *models.Visit) (*models.Session, error) {
+		session, err := newOrder()
+		if err == nil {
+			err = addVisitRound(visit, session)
+		}
+		if err == nil {
+			err = u.applyChargeRules(ctx, store, session)
+		}
+		orderErr = err
+		return session, err
+	})
+	if orderErr != nil {
+		return nil, orderErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	return session, nil
+}
+
+// applyChargeRules は店舗のチャージ設定を注文に適用します。
+// 人数・座席ごとのチャージの重複を避けるため、同じ来店の注文を参照します。
+func (u *UseCase) applyChargeRules(ctx context.Context, store *models.Store, session *models.Session) error {
+	if len(store.ChargeRules) == 0 {
+		return nil
+	}
+
+	var visitOrders []*models.Session
+	if session.VisitID != "" {
+		orders, err := u.sessionRepo.FindByField(ctx, "visit_id", session.VisitID)
+		if err != nil {
+			return fmt.Errorf("failed to find visit orders: %w", err)
+		}
+		visitOrders = orders
+	}
+	if session.PartySize == 0 {
+		for _, order := range visitOrders {
+			session.PartySize = max(session.PartySize, order.PartySize)
+		}
+	}
+	return session.ApplyChargeRules(store.ChargeRules, visitOrders, session.IssuedAt)
+}
+
+// WaiveCharge はスタッフが理由を添えて注文のチャージを免除します。
+// actor は免除したスタッフ（"manager:<email>" など）で、理由とあわせて記録します。
+func (u *UseCase) WaiveCharge(ctx context.Context, storeID, orderID, chargeID, reason, actor string) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		_, err := session.WaiveCharge(chargeID, reason, actor, time.Now())
+		return err
+	})
+}
+
+// ListOrdersForReview は店舗の注文のうち、スタッフの確認が必要なものを返します。
+func (u *UseCase) ListOrdersForReview(ctx context.Context, storeID string) ([]*models.Session, error) {
+	if storeID == "" {
+		return nil, models.ErrStoreIDRequired
+	}
+
+	sessions, err := u.sessionRepo.FindByField(ctx, "store_id", storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+
+	flagged := make([]*models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.NeedsReview {
+			flagged = append(flagged, session)
+		}
+	}
+	return flagged, nil
+}
+
+// findStoreSession は注文を取得し、指定された店舗に属していることを確認します。
+func (u *UseCase) findStoreSession(ctx context.Context, storeID, sessionID string) (*models.Session, error) {
+	session, err := u.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if session.StoreID != storeID {
+		return nil, models.ErrSessionStoreMismatch
+	}
+	return session, nil
+}
+
+// updateStoreSession は店舗の注文を読み取り、update で変更した注文を他の更新と競合しないように保存します。
+// 他店舗の注文は変更せず ErrSessionStoreMismatch を返します。
+func (u *UseCase) updateStoreSession(ctx context.Context, storeID, sessionID string, update func(*models.Session) error) (*models.Session, error) {
+	return u.updateOpenStoreSession(ctx, storeID, sessionID, "", update)
+}
+
+// updateOpenStoreSession は updateStoreSession と同じトランザクションで closeID のレジ締めを確認し、
+// レジ締め済みの場合は ErrBusinessDayClosed を返して保存しません。closeID が空の場合は確認しません。
+func (u *UseCase) updateOpenStoreSession(ctx context.Context, storeID, sessionID, closeID string, update func(*models.Session) error) (*models.Session, error) {
+	session, err := u.sessionUpdates.UpdateOpen(ctx, closeID, sessionID, func(session *models.Session) error {
+		if session.StoreID != storeID {
+			return models.ErrSessionStoreMismatch
+		}
+		return update(session)
+	})
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// UpdateOrderStatus はスタッフの操作で注文のステータスを更新し、操作者と理由を遷移の履歴に記録します。
+// キャンセル・辞退・保留には店舗の理由コードが必要で、note は任意の補足です。
+func (u *UseCase) UpdateOrderStatus(ctx context.Context, storeID, orderID string, status models.Status, actor, reasonCode, note string) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		kind, ok := models.ReasonKindFor(status)
+		if !ok {
+			return session.UpdateStatusBy(status, actor, note)
+		}
+		reason, err := u.lookupReasonCode(ctx, storeID, kind, reasonCode)
+		if err != nil {
+			return err
+		}
+		return session.UpdateStatusWithReason(status, actor, reason, note)
+	})
+}
+
+// ChangeOrderDiningOption はスタッフがお客様に確認した店内飲食・持ち帰りの区分に注文を変更し、税額を再計算します。
+func (u *UseCase) ChangeOrderDiningOption(ctx context.Context, storeID, orderID string, dining models.DiningOption) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		return session.ChangeDiningOption(dining)
+	})
+}
+
+// UpdateOrderLineStatus は厨房の操作で明細のステータスを更新します。注文のステータスは明細の状況から導出します。
+func (u *UseCase) UpdateOrderLineStatus(ctx context.Context, storeID, orderID, lineID string, status models.LineStatus, actor string) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		return session.UpdateLineStatus(lineID, status, actor, time.Now())
+	})
+}
+
+// VoidOrderLine は調理前の明細を店舗の理由コードと任意の補足を添えて取り消し、合計金額を再計算します。
+func (u *UseCase) VoidOrderLine(ctx context.Context, storeID, orderID, lineID, reasonCode, note, actor string) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		reason, err := u.lookupReasonCode(ctx, storeID, models.ReasonVoid, reasonCode)
+		if err != nil {
+			return err
+		}
+		return session.VoidLineWithReason(lineID, reason, note, actor, time.Now())
+	})
+}
+
+// AdjustOrderLineQuantity は調理前の明細の数量を理由を添えて変更し、合計金額を再計算します。
+func (u *UseCase) AdjustOrderLineQuantity(ctx context.Context, storeID, orderID, lineID string, quantity int, reason, actor string) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		return session.AdjustLineQuantity(lineID, quantity, reason, actor, time.Now())
+	})
+}
+
+// UpdateOrderItem はお客様の操作で、店舗の確認前の注文の商品の数量と特別な指示を変更します。
+// 変更後に利用条件を満たさなくなったプロモーションの割引は取り消します。
+func (u *UseCase) UpdateOrderItem(ctx context.Context, storeID, seatID, orderID, lineID string, quantity int, instructions string) (*models.Session, error) {
+	return u.editSeatOrderItems(ctx, storeID, seatID, orderID, func(session *models.Session) error {
+		return session.UpdateItem(lineID, quantity, instructions)
+	})
+}
+
+// RemoveOrderItem はお客様の操作で、店舗の確認前の注文から商品を削除します。
+// 削除後に利用条件を満たさなくなったプロモーションの割引は取り消します。
+func (u *UseCase) RemoveOrderItem(ctx context.Context, storeID, seatID, orderID, lineID string) (*models.Session, error) {
+	return u.editSeatOrderItems(ctx, storeID, seatID, orderID, func(session *models.Session) error {
+		return session.RemoveItem(lineID)
+	})
+}
+
+// editSeatOrderItems はお客様の座席の注文の商品を edit で変更して保存します。
+// 店舗の確認と同時に変更しても確認済みの注文を変更しないよう、変更できるかどうかの判定と変更は同じトランザクションで行います。
+// 適用済みのプロモーションの割引は変更後の注文で判定し直し、利用記録を割引にあわせて更新します。
+func (u *UseCase) editSeatOrderItems(ctx context.Context, storeID, seatID, orderID string, edit func(*models.Session) error) (*models.Session, error) {
+	var removed []models.Discount
+	session, err := u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		// 他の座席の注文は存在しないものとして扱う
+		if session.SeatID != seatID {
+			return models.ErrOrderNotFound
+		}
+		if err := edit(session); err != nil {
+			return err
+		}
+		var err error
+		removed, err = u.revalidatePromotionDiscounts(ctx, session)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.syncPromotionRedemptions(ctx, session, removed); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetOrder は店舗の注文を返します。
+func (u *UseCase) GetOrder(ctx context.Context, storeID, orderID string) (*models.Session, error) {
+	return u.findStoreSession(ctx, storeID, orderID)
+}
+
+// MigrateLegacyOrderStatuses は支払い状態と提供の進行を1つのステータスで管理していた注文を、新しい形式で保存し直します。
+// ステータスや支払い状態での検索が旧形式の注文を見落とさないよう、デプロイ後に一度実行します。移行した注文の数を返します。
+func (u *UseCase) MigrateLegacyOrderStatuses(ctx context.Context) (int, error) {
+	migrated, err := u.statusMigrator.MigrateLegacyStatuses(ctx)
+	if err != nil {
+		return migrated, fmt.Errorf("failed to migrate order statuses: %w", err)
+	}
+	return migrated, nil
+}
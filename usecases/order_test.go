@@ -0,0 +1,523 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// expectNewVisit は来店の記録がない（来店の記録の導入前に開始した）来店への注文を想定したモックを設定します。
+func expectNewVisit(ctx context.Context, useCase *UseCase) {
+	visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+	visitRepo.On("FindByID", ctx, mock.Anything).Return(nil, status.Error(codes.NotFound, "not found"))
+	visitRepo.On("Create", ctx, mock.AnythingOfType("*models.Visit")).Return(nil)
+}
+
+// testMenu は注文のテストで使用する店舗のメニューです。
+var testMenu = []models.Product{
+	{ID: "prod_1", Name: "ラーメン", Price: 500, Category: "food", Active: true},
+	{ID: "prod_2", Name: "期間限定ラーメン", Price: 1200, Active: false},
+	{ID: "prod_3", Name: "おにぎり", Price: 500, TaxCategory: models.TaxCategoryFood, Active: true},
+}
+
+// TestPlaceOrder tests the PlaceOrder function
+func TestPlaceOrder(t *testing.T) {
+	ctx := context.Background()
+	items := func() []models.Order {
+		return []models.Order{*models.NewOrder("prod_1", 2, models.Money{})}
+	}
+
+	t.Run("place order prices items from the store menu", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		expectNewVisit(ctx, useCase)
+
+		// お客様の端末から送られた単価・カテゴリ・消費税区分は使用しない
+		tampered := []models.Order{*models.NewOrder("prod_1", 2, models.Yen(1)).WithCategory("drink").WithTaxCategory(models.TaxCategoryFood)}
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", tampered, "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.Yen(500), session.Items[0].Price)
+		assert.Equal(t, "food", session.Items[0].Category)
+		assert.Equal(t, models.TaxCategory(""), session.Items[0].TaxCategory)
+		assert.Equal(t, models.DiningEatIn, session.DiningOption)
+		assert.Equal(t, models.Yen(1000), session.TotalAmount)
+	})
+
+	t.Run("place order with a product not on the menu", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu}, nil)
+
+		for _, productID := range []string{"prod_unknown", "prod_2"} {
+			_, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", []models.Order{*models.NewOrder(productID, 1, models.Money{})}, "")
+			assert.ErrorIs(t, err, models.ErrUnknownProduct)
+		}
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("place order", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		expectNewVisit(ctx, useCase)
+
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", items(), "")
+		assert.NoError(t, err)
+		assert.False(t, session.NeedsReview)
+		assert.Equal(t, models.Yen(1000), session.TotalAmount)
+		assert.Equal(t, models.Yen(90), session.TaxTotal)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("place order with exclusive store tax policy", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu, TaxPriceMode: models.PriceModeExclusive, DiningOption: models.DiningTakeout}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		expectNewVisit(ctx, useCase)
+
+		food := []models.Order{*models.NewOrder("prod_3", 2, models.Money{})}
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", food, "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.DiningTakeout, session.DiningOption, "店舗の設定の区分")
+		assert.Equal(t, models.Yen(80), session.TaxTotal)
+		assert.Equal(t, models.Yen(1080), session.TotalAmount)
+	})
+
+	t.Run("place order with store workflow", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu, Workflow: models.WorkflowCounter}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		expectNewVisit(ctx, useCase)
+
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", items(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.WorkflowCounter, session.WorkflowName)
+		assert.NoError(t, session.UpdateStatus(models.StatusPreparing))
+	})
+
+	t.Run("place order flagged for review", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		expectNewVisit(ctx, useCase)
+
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", items(), models.ReviewReasonOutsideNetwork)
+		assert.NoError(t, err)
+		assert.True(t, session.NeedsReview)
+		assert.Equal(t, models.ReviewReasonOutsideNetwork, session.ReviewReason)
+	})
+
+	t.Run("place order with store charge rules", func(t *testing.T) {
+		rules := []models.ChargeRule{
+			{ID: "chrule_otoshi", Name: "お通し", Kind: models.ChargePerPerson, Value: 300, Active: true},
+			{ID: "chrule_service", Name: "サービス料", Kind: models.ChargePercentage, Value: 10, Active: true},
+		}
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu, ChargeRules: rules}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		// 来店人数はスタッフが登録した人数を使用する
+		visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+		visitRepo.On("FindByID", ctx, "visit_2").Return(&models.Visit{ID: "visit_2", StoreID: "store_1", SeatID: "seat_1", Status: models.VisitOpen, PartySize: 3}, nil)
+		visitRepo.On("UpdateByID", ctx, "visit_2", mock.AnythingOfType("*models.Visit")).Return(nil)
+		expectNewVisit(ctx, useCase)
+
+		// 同じ来店の最初の注文でお通し代を請求済み
+		first := &models.Session{ID: "order_first", VisitID: "visit_1", PartySize: 2, Charges: []models.Charge{{RuleID: "chrule_otoshi"}}}
+		mockRepo.On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.Session{first}, nil)
+		mockRepo.On("FindByField", ctx, "visit_id", "visit_2").Return([]*models.Session{}, nil)
+
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", items(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, session.PartySize, "同じ来店の人数を引き継ぐ")
+		assert.Len(t, session.Charges, 1)
+		assert.Equal(t, models.Yen(1100), session.TotalAmount)
+
+		session, err = useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_2", items(), "")
+		assert.NoError(t, err)
+		assert.Len(t, session.Charges, 2)
+		assert.Equal(t, models.Yen(900), session.Charges[0].Amount)
+		assert.Equal(t, models.Yen(2000), session.TotalAmount)
+	})
+
+	t.Run("place order adds a round to the visit", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu}, nil)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Session")).Return(nil)
+		visit := &models.Visit{ID: "visit_1", StoreID: "store_1", SeatID: "seat_1", Status: models.VisitOpen, PartySize: 2, SessionIDs: []string{"order_first"}}
+		visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+		visitRepo.On("FindByID", ctx, "visit_1").Return(visit, nil)
+		visitRepo.On("UpdateByID", ctx, "visit_1", visit).Return(nil)
+
+		session, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", items(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"order_first", session.ID}, visit.SessionIDs)
+		assert.Equal(t, 2, session.PartySize, "来店人数を引き継ぐ")
+	})
+
+	t.Run("place order to a closed visit", func(t *testing.T) {
+		useCase := New(nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Products: testMenu}, nil)
+		visit := &models.Visit{ID: "visit_1", StoreID: "store_1", SeatID: "seat_1", Status: models.VisitClosed}
+		useCase.visitRepo.(*repositories.MockVisitRepository).On("FindByID", ctx, "visit_1").Return(visit, nil)
+
+		_, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", items(), "")
+		assert.ErrorIs(t, err, models.ErrVisitClosed)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("place order without items", func(t *testing.T) {
+		useCase := New(nil)
+		_, err := useCase.PlaceOrder(ctx, "store_1", "seat_1", "visit_1", nil, "")
+		assert.ErrorIs(t, err, models.ErrNoItems)
+	})
+}
+
+// TestWaiveCharge tests the WaiveCharge function
+func TestWaiveCharge(t *testing.T) {
+	ctx := context.Background()
+
+	newSession := func(t *testing.T) *models.Session {
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(1000))})
+		assert.NoError(t, err)
+		rules := []models.ChargeRule{{ID: "chrule_otoshi", Name: "お通し", Kind: models.ChargePerPerson, Value: 300, Active: true}}
+		assert.NoError(t, session.ApplyChargeRules(rules, nil, session.IssuedAt))
+		return session
+	}
+
+	t.Run("waive", func(t *testing.T) {
+		useCase := New(nil)
+		session := newSession(t)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		mockRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+
+		updated, err := useCase.WaiveCharge(ctx, "store_1", session.ID, session.Charges[0].ID, "アレルギーのため", "manager:a@example.com")
+		assert.NoError(t, err)
+		assert.True(t, updated.Charges[0].Waived)
+		assert.Equal(t, "manager:a@example.com", updated.Charges[0].WaivedBy)
+		assert.Equal(t, models.Yen(1000), updated.TotalAmount)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		useCase := New(nil)
+		session := newSession(t)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+
+		_, err := useCase.WaiveCharge(ctx, "store_1", session.ID, session.Charges[0].ID, "", "manager:a@example.com")
+		assert.ErrorIs(t, err, models.ErrChargeWaiveReasonRequired)
+		mockRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		useCase := New(nil)
+		session := newSession(t)
+		mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		mockRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+
+		_, err := useCase.WaiveCharge(ctx, "store_2", session.ID, session.Charges[0].ID, "アレルギーのため", "manager:a@example.com")
+		assert.ErrorIs(t, err, models.ErrSessionStoreMismatch)
+	})
+}
+
+// TestListOrdersForReview tests the ListOrdersForReview function
+func TestListOrdersForReview(t *testing.T) {
+	ctx := context.Background()
+
+	useCase := New(nil)
+	flagged := &models.Session{ID: "s1", StoreID: "store_1", NeedsReview: true}
+	normal := &models.Session{ID: "s2", StoreID: "store_1"}
+	mockRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+	mockRepo.On("FindByField", ctx, "store_id", "store_1").Return([]*models.Session{flagged, normal}, nil)
+
+	sessions, err := useCase.ListOrdersForReview(ctx, "store_1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Session{flagged}, sessions)
+
+	_, err = useCase.ListOrdersForReview(ctx, "")
+	assert.ErrorIs(t, err, models.ErrStoreIDRequired)
+}
+
+// TestUpdateOrderStatus tests the UpdateOrderStatus function
+func TestUpdateOrderStatus(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(500))})
+		assert.NoError(t, err)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, session).Return(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+		return useCase, session
+	}
+
+	t.Run("records the actor and reason", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, err := useCase.UpdateOrderStatus(ctx, "store_1", session.ID, models.StatusConfirmed, "manager:a@example.com", "", "電話で確認済み")
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusConfirmed, updated.Status)
+		last := updated.StatusHistory[len(updated.StatusHistory)-1]
+		assert.Equal(t, "manager:a@example.com", last.Actor)
+		assert.Equal(t, "電話で確認済み", last.Reason)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.UpdateOrderStatus(ctx, "store_1", session.ID, models.StatusServed, "manager:a@example.com", "", "")
+		var transitionErr *models.InvalidStatusTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cancel records the reason code", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, err := useCase.UpdateOrderStatus(ctx, "store_1", session.ID, models.StatusCancelled, "manager:a@example.com", "customer_request", "")
+		assert.NoError(t, err)
+		last := updated.StatusHistory[len(updated.StatusHistory)-1]
+		assert.Equal(t, "customer_request", last.ReasonCode)
+		assert.Equal(t, "お客様の都合", last.Reason)
+	})
+
+	t.Run("cancel without a reason code", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.UpdateOrderStatus(ctx, "store_1", session.ID, models.StatusCancelled, "manager:a@example.com", "", "お客様の都合")
+		assert.ErrorIs(t, err, models.ErrReasonCodeRequired)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("hold with a code of another kind", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.UpdateOrderStatus(ctx, "store_1", session.ID, models.StatusOnHold, "manager:a@example.com", "out_of_stock", "")
+		assert.ErrorIs(t, err, models.ErrUnknownReasonCode)
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.UpdateOrderStatus(ctx, "store_2", session.ID, models.StatusConfirmed, "", "", "")
+		assert.ErrorIs(t, err, models.ErrSessionStoreMismatch)
+	})
+}
+
+// TestChangeOrderDiningOption tests the ChangeOrderDiningOption function
+func TestChangeOrderDiningOption(t *testing.T) {
+	ctx := context.Background()
+
+	useCase := New(nil)
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_3", 2, models.Yen(500)).WithTaxCategory(models.TaxCategoryFood)})
+	assert.NoError(t, err)
+	assert.NoError(t, session.SetTaxPolicy(models.TaxPolicy{PriceMode: models.PriceModeExclusive}, models.DiningEatIn))
+	sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("UpdateByID", ctx, session.ID, session).Return(nil)
+
+	updated, err := useCase.ChangeOrderDiningOption(ctx, "store_1", session.ID, models.DiningTakeout)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DiningTakeout, updated.DiningOption)
+	assert.Equal(t, models.Yen(80), updated.TaxTotal, "持ち帰りの飲食料品は軽減税率")
+
+	_, err = useCase.ChangeOrderDiningOption(ctx, "store_1", session.ID, "delivery")
+	assert.ErrorIs(t, err, models.ErrInvalidDiningOption)
+
+	assert.NoError(t, session.UpdatePaymentStatus(models.PaymentStatusPending, "", ""))
+	_, err = useCase.ChangeOrderDiningOption(ctx, "store_1", session.ID, models.DiningEatIn)
+	assert.ErrorIs(t, err, models.ErrDiningOptionLocked, "支払い手続き中は変更できない")
+	sessionRepo.AssertNumberOfCalls(t, "UpdateByID", 1)
+}
+
+// TestOrderLineOperations tests the per-line status, void and quantity operations
+func TestOrderLineOperations(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{
+			*models.NewOrder("prod_1", 2, models.Yen(500)),
+			*models.NewOrder("prod_2", 1, models.Yen(300)),
+		})
+		assert.NoError(t, err)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, session).Return(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+		return useCase, session
+	}
+
+	t.Run("cooking a line moves the order to preparing", func(t *testing.T) {
+		useCase, session := setup(t)
+		assert.NoError(t, session.UpdateStatus(models.StatusConfirmed))
+
+		updated, err := useCase.UpdateOrderLineStatus(ctx, "store_1", session.ID, session.Items[0].LineID, models.LineCooking, "manager:a@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, models.LineCooking, updated.Items[0].Status)
+		assert.Equal(t, models.StatusPreparing, updated.Status)
+	})
+
+	t.Run("cooking before the order is confirmed", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.UpdateOrderLineStatus(ctx, "store_1", session.ID, session.Items[0].LineID, models.LineCooking, "manager:a@example.com")
+		assert.ErrorIs(t, err, models.ErrOrderNotConfirmed)
+	})
+
+	t.Run("void recalculates the total", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, err := useCase.VoidOrderLine(ctx, "store_1", session.ID, session.Items[1].LineID, "out_of_stock", "", "manager:a@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, models.Yen(1000), updated.TotalAmount)
+		assert.Equal(t, "out_of_stock", updated.Items[1].VoidReasonCode)
+		assert.Equal(t, "品切れ", updated.Items[1].VoidReason)
+	})
+
+	t.Run("quantity adjustment", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, err := useCase.AdjustOrderLineQuantity(ctx, "store_1", session.ID, session.Items[0].LineID, 1, "注文間違い", "manager:a@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, models.Yen(800), updated.TotalAmount)
+		assert.Len(t, updated.Items[0].Adjustments, 1)
+	})
+
+	t.Run("line already started", func(t *testing.T) {
+		useCase, session := setup(t)
+		assert.NoError(t, session.UpdateStatus(models.StatusConfirmed))
+		assert.NoError(t, session.UpdateLineStatus(session.Items[0].LineID, models.LineCooking, "", session.CreatedAt))
+
+		_, err := useCase.VoidOrderLine(ctx, "store_1", session.ID, session.Items[0].LineID, "out_of_stock", "", "")
+		assert.ErrorIs(t, err, models.ErrLineAlreadyStarted)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.VoidOrderLine(ctx, "store_2", session.ID, session.Items[0].LineID, "out_of_stock", "", "")
+		assert.ErrorIs(t, err, models.ErrSessionStoreMismatch)
+	})
+}
+
+// TestCustomerOrderItemEdits tests the UpdateOrderItem and RemoveOrderItem functions
+func TestCustomerOrderItemEdits(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{
+			*models.NewOrder("prod_1", 2, models.Yen(500)),
+			*models.NewOrder("prod_2", 1, models.Yen(300)),
+		})
+		assert.NoError(t, err)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, session).Return(nil)
+		return useCase, session
+	}
+
+	t.Run("update quantity and instructions", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, err := useCase.UpdateOrderItem(ctx, "store_1", "seat_1", session.ID, session.Items[0].LineID, 1, "ネギ抜き")
+		assert.NoError(t, err)
+		assert.Equal(t, "ネギ抜き", updated.Items[0].Instructions)
+		assert.Equal(t, models.Yen(800), updated.TotalAmount)
+	})
+
+	t.Run("remove item", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, err := useCase.RemoveOrderItem(ctx, "store_1", "seat_1", session.ID, session.Items[1].LineID)
+		assert.NoError(t, err)
+		assert.Len(t, updated.Items, 1)
+		assert.Equal(t, models.Yen(1000), updated.TotalAmount)
+	})
+
+	t.Run("order of another seat", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.RemoveOrderItem(ctx, "store_1", "seat_2", session.ID, session.Items[1].LineID)
+		assert.ErrorIs(t, err, models.ErrOrderNotFound)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("locked after confirmation", func(t *testing.T) {
+		useCase, session := setup(t)
+		assert.NoError(t, session.UpdateStatus(models.StatusConfirmed))
+
+		_, err := useCase.UpdateOrderItem(ctx, "store_1", "seat_1", session.ID, session.Items[0].LineID, 3, "")
+		assert.ErrorIs(t, err, models.ErrItemEditNotAllowed)
+	})
+
+	// 最低利用金額1,000円の10%オフを小計1,300円の注文に適用済み
+	redeemed := func(t *testing.T) (*UseCase, *models.Session, *models.Discount) {
+		useCase, session := setup(t)
+		promotion := newTestPromotion()
+		promotion.MinSpend = models.Yen(1000)
+		discount, err := promotion.NewDiscount(session, session.CreatedAt)
+		assert.NoError(t, err)
+		assert.NoError(t, session.ApplyDiscount(discount))
+		useCase.promotionRepo.(*repositories.MockPromotionRepository).On("FindByID", ctx, promotion.ID).Return(promotion, nil)
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.On("FindByField", ctx, "discount_id", discount.ID).Return([]*models.PromotionRedemption{{ID: "redeem_1", DiscountID: discount.ID, Amount: discount.Amount}}, nil)
+		return useCase, session, discount
+	}
+
+	t.Run("promotion discount removed below the minimum spend", func(t *testing.T) {
+		useCase, session, _ := redeemed(t)
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.On("DeleteByID", ctx, "redeem_1").Return(nil)
+
+		updated, err := useCase.UpdateOrderItem(ctx, "store_1", "seat_1", session.ID, session.Items[0].LineID, 1, "")
+		assert.NoError(t, err)
+		assert.Empty(t, updated.Discounts)
+		assert.Equal(t, models.Yen(800), updated.TotalAmount)
+		redemptionRepo.AssertExpectations(t)
+	})
+
+	t.Run("promotion discount recomputed after removing an item", func(t *testing.T) {
+		useCase, session, discount := redeemed(t)
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.On("UpdateByID", ctx, "redeem_1", mock.AnythingOfType("*models.PromotionRedemption")).Return(nil)
+
+		updated, err := useCase.RemoveOrderItem(ctx, "store_1", "seat_1", session.ID, session.Items[1].LineID)
+		assert.NoError(t, err)
+		assert.Len(t, updated.Discounts, 1)
+		assert.Equal(t, discount.ID, updated.Discounts[0].ID)
+		assert.Equal(t, models.Yen(100), updated.Discounts[0].Amount)
+		assert.Equal(t, models.Yen(900), updated.TotalAmount)
+		redemptionRepo.AssertCalled(t, "UpdateByID", ctx, "redeem_1", mock.MatchedBy(func(r *models.PromotionRedemption) bool {
+			return r.Amount == models.Yen(100)
+		}))
+		redemptionRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// VerifySupervisorPIN はスタッフの端末で入力されたPINに一致する店舗の責任者を返します。
+// PINの総当たりを防ぐため、失敗が続いた店舗ではPINの入力を段階的にロックし、ロック中は *models.SupervisorPINLockedError を返します。
+func (u *UseCase) VerifySupervisorPIN(ctx context.Context, storeID, pin string) (models.Supervisor, error) {
+	now := time.Now().UTC()
+	key := models.SupervisorPINAttemptKey(storeID)
+	attempt, err := u.loginAttempts.Get(ctx, key)
+	if err != nil {
+		return models.Supervisor{}, err
+	}
+	if attempt.IsLocked(now) {
+		return models.Supervisor{}, &models.SupervisorPINLockedError{Until: attempt.LockedUntil}
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return models.Supervisor{}, fmt.Errorf("failed to find store: %w", err)
+	}
+	supervisor, err := store.VerifySupervisorPIN(pin)
+	if err != nil {
+		// 同時に失敗した場合も回数を取りこぼさないよう、ロックの判定には記録後の失敗履歴を使用する
+		attempt, regErr := u.loginAttempts.RegisterFailure(ctx, key, now, u.lockoutPolicy)
+		if regErr != nil {
+			return models.Supervisor{}, regErr
+		}
+		if attempt.IsLocked(now) {
+			return models.Supervisor{}, &models.SupervisorPINLockedError{Until: attempt.LockedUntil}
+		}
+		return models.Supervisor{}, err
+	}
+
+	if err := u.loginAttempts.Delete(ctx, key); err != nil {
+		return models.Supervisor{}, err
+	}
+	return supervisor, nil
+}
+
+// AllowsManagerOverride はマネージャー（email）が店舗で責任者のPINなしにステータスの強制変更を行えるかどうかを返します。
+func (u *UseCase) AllowsManagerOverride(ctx context.Context, storeID, email string) (bool, error) {
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find store: %w", err)
+	}
+	return store.AllowsManagerOverride(email), nil
+}
+
+// OverrideOrderStatus は責任者の承認のもとで、遷移ルールによらず注文のステータスを強制変更します。
+// 承認者の確認（強制変更の権限、または責任者のPIN）は呼び出し側で行います。
+// キャンセル・辞退・保留への強制変更には店舗の理由コード（reasonCode）が必要です。
+func (u *UseCase) OverrideOrderStatus(ctx context.Context, storeID, orderID string, status models.Status, reasonCode string, override models.StatusOverride) (*models.Session, error) {
+	return u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		if kind, ok := models.ReasonKindFor(status); ok {
+			var err error
+			if override.ReasonCode, err = u.lookupReasonCode(ctx, storeID, kind, reasonCode); err != nil {
+				return err
+			}
+		}
+		return session.OverrideStatus(status, override)
+	})
+}
+
+// ListStatusOverrides は営業日 from から to まで（両端を含む）のステータスの強制変更を新しい順に返します。
+func (u *UseCase) ListStatusOverrides(ctx context.Context, storeID, from, to string) ([]models.StatusOverrideRecord, error) {
+	start, _, err := models.BusinessDayRange(from)
+	if err != nil {
+		return nil, err
+	}
+	_, end, err := models.BusinessDayRange(to)
+	if err != nil {
+		return nil, err
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: %s - %s", models.ErrInvalidBusinessDay, from, to)
+	}
+
+	sessions, err := u.sessionRepo.FindByField(ctx, "store_id", storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+	return models.StatusOverrides(storeID, sessions, start, end), nil
+}
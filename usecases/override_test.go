@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOverrideOrderStatus tests the VerifySupervisorPIN and OverrideOrderStatus functions
+func TestOverrideOrderStatus(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(500))})
+		require.NoError(t, err)
+		require.NoError(t, session.UpdateStatusBy(models.StatusCancelled, "manager:a@example.com", "お客様の都合"))
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, session).Return(nil)
+		return useCase, session
+	}
+
+	t.Run("supervisor PIN approves the override", func(t *testing.T) {
+		useCase, session := setup(t)
+		supervisors, err := models.NewSupervisors([]models.SupervisorPIN{{Name: "佐藤", PIN: "246810"}})
+		require.NoError(t, err)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Supervisors: supervisors}, nil)
+
+		supervisor, err := useCase.VerifySupervisorPIN(ctx, "store_1", "246810")
+		require.NoError(t, err)
+		updated, err := useCase.OverrideOrderStatus(ctx, "store_1", session.ID, models.StatusServed, "", models.StatusOverride{
+			Actor: "apikey:key_1", ApprovedBy: supervisor.Approver(), Reason: "誤ってキャンセルしたため",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusServed, updated.Status)
+		last := updated.StatusHistory[len(updated.StatusHistory)-1]
+		assert.Equal(t, "supervisor:佐藤", last.ApprovedBy)
+		assert.True(t, last.Exception)
+	})
+
+	t.Run("wrong PIN", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+
+		_, err := useCase.VerifySupervisorPIN(ctx, "store_1", "246810")
+		assert.ErrorIs(t, err, models.ErrInvalidSupervisorPIN)
+	})
+
+	t.Run("repeated wrong PINs lock the store", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.lockoutPolicy = models.LockoutPolicy{MaxFailures: 2, BaseDuration: time.Minute, MaxDuration: time.Hour}
+		supervisors, err := models.NewSupervisors([]models.SupervisorPIN{{Name: "佐藤", PIN: "246810"}})
+		require.NoError(t, err)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Supervisors: supervisors}, nil)
+
+		_, err = useCase.VerifySupervisorPIN(ctx, "store_1", "111111")
+		assert.ErrorIs(t, err, models.ErrInvalidSupervisorPIN)
+		_, err = useCase.VerifySupervisorPIN(ctx, "store_1", "222222")
+		var lockedErr *models.SupervisorPINLockedError
+		require.ErrorAs(t, err, &lockedErr)
+
+		_, err = useCase.VerifySupervisorPIN(ctx, "store_1", "246810")
+		assert.ErrorAs(t, err, &lockedErr, "ロック中は正しいPINも受け付けない")
+		attempt, err := useCase.loginAttempts.Get(ctx, "supervisor_pin:store_1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempt.Failures)
+
+		attempt, err = useCase.loginAttempts.Get(ctx, "supervisor_pin:store_2")
+		require.NoError(t, err)
+		assert.False(t, attempt.IsLocked(time.Now()), "他店舗はロックしない")
+	})
+
+	t.Run("manager override is granted per store", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", OverrideManagers: []string{"a@example.com"}}, nil)
+
+		granted, err := useCase.AllowsManagerOverride(ctx, "store_1", "a@example.com")
+		require.NoError(t, err)
+		assert.True(t, granted)
+		granted, err = useCase.AllowsManagerOverride(ctx, "store_1", "b@example.com")
+		require.NoError(t, err)
+		assert.False(t, granted)
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.OverrideOrderStatus(ctx, "store_1", session.ID, models.StatusServed, "", models.StatusOverride{ApprovedBy: "manager:a@example.com"})
+		assert.ErrorIs(t, err, models.ErrOverrideReasonRequired)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cancel requires a reason code from the catalog", func(t *testing.T) {
+		useCase, session := setup(t)
+		require.NoError(t, session.OverrideStatus(models.StatusServed, models.StatusOverride{ApprovedBy: "manager:a@example.com", Reason: "提供済み"}))
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+		override := models.StatusOverride{ApprovedBy: "manager:a@example.com", Reason: "重複した注文のため"}
+
+		_, err := useCase.OverrideOrderStatus(ctx, "store_1", session.ID, models.StatusCancelled, "", override)
+		assert.ErrorIs(t, err, models.ErrReasonCodeRequired)
+		_, err = useCase.OverrideOrderStatus(ctx, "store_1", session.ID, models.StatusCancelled, "unknown", override)
+		assert.ErrorIs(t, err, models.ErrUnknownReasonCode)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+
+		updated, err := useCase.OverrideOrderStatus(ctx, "store_1", session.ID, models.StatusCancelled, "duplicate_order", override)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusCancelled, updated.Status)
+		assert.Equal(t, "duplicate_order", updated.StatusHistory[len(updated.StatusHistory)-1].ReasonCode)
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, err := useCase.OverrideOrderStatus(ctx, "store_2", session.ID, models.StatusServed, "", models.StatusOverride{ApprovedBy: "manager:a@example.com", Reason: "提供済み"})
+		assert.ErrorIs(t, err, models.ErrSessionStoreMismatch)
+	})
+}
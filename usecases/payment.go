@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SetPaymentProvider はオンライン決済に使用する決済代行会社を設定します。
+func (u *UseCase) SetPaymentProvider(provider repositories.PaymentProvider) {
+	u.paymentProvider = provider
+}
+
+// paymentIdempotencyKey は決済代行会社での支払いの作成に使用する冪等キーを返します。
+// 同じ注文・同じ金額であれば同じ支払いを返し、商品の追加などで金額が変わった場合は新しい支払いを作成します。
+func paymentIdempotencyKey(session *models.Session) string {
+	return fmt.Sprintf("%s:%d:%s", session.ID, session.TotalAmount.Amount, session.Currency())
+}
+
+// StartOrderPayment は注文のオンライン決済を開始し、決済とお客様の支払い手続きに使用する client secret を返します。
+// seatID を指定した場合は、その座席の注文のみ支払えます。
+// 注文の支払い状態は支払い手続き中（`Pending`）になり、Webhook の通知で支払い済み・支払い失敗に更新されます。
+func (u *UseCase) StartOrderPayment(ctx context.Context, storeID, seatID, orderID string, manualCapture bool) (*models.Payment, string, error) {
+	if u.paymentProvider == nil {
+		return nil, "", models.ErrPaymentProviderNotConfigured
+	}
+	session, err := u.findStoreSession(ctx, storeID, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+	// 他の座席の注文は存在しないものとして扱う
+	if seatID != "" && session.SeatID != seatID {
+		return nil, "", models.ErrOrderNotFound
+	}
+
+	payment, err := models.NewPayment(session, u.paymentProvider.Name(), "", time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	intent, err := u.paymentProvider.CreateIntent(ctx, repositories.PaymentIntentRequest{
+		Amount:         session.TotalAmount,
+		ManualCapture:  manualCapture,
+		IdempotencyKey: paymentIdempotencyKey(session),
+		Metadata:       map[string]string{"store_id": session.StoreID, "session_id": session.ID},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	payment.IntentID = intent.ID
+
+	// 決済の開始中に他の操作で更新された注文を上書きしないよう、最新の注文を支払い手続き中にする
+	if _, err := u.updateStoreSession(ctx, storeID, session.ID, func(session *models.Session) error {
+		return session.StartPayment()
+	}); err != nil {
+		return nil, "", err
+	}
+
+	// 再読み込みなどで同じ支払いを作成した場合は、記録済みの決済を返す
+	payments, err := u.paymentRepo.FindByField(ctx, "session_id", session.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find payments: %w", err)
+	}
+	for _, p := range payments {
+		if p.IntentID == intent.ID {
+			return p, intent.ClientSecret, nil
+		}
+	}
+	if err := u.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, "", fmt.Errorf("failed to create payment: %w", err)
+	}
+	return payment, intent.ClientSecret, nil
+}
+
+// GetPayment は店舗の決済を返します。
+func (u *UseCase) GetPayment(ctx context.Context, storeID, paymentID string) (*models.Payment, error) {
+	payment, err := u.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to find payment: %w", err)
+	}
+	// 他店舗の決済は存在しないものとして扱う
+	if payment.StoreID != storeID {
+		return nil, models.ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+// CapturePayment はオーソリ済みの決済の売上を確定します。
+func (u *UseCase) CapturePayment(ctx context.Context, storeID, paymentID string) (*models.Payment, error) {
+	if u.paymentProvider == nil {
+		return nil, models.ErrPaymentProviderNotConfigured
+	}
+	payment, err := u.GetPayment(ctx, storeID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != models.PaymentIntentRequiresCapture {
+		return nil, models.ErrPaymentNotCapturable
+	}
+
+	intent, err := u.paymentProvider.Capture(ctx, payment.IntentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.applyPaymentStatus(ctx, payment, intent.Status, intent.FailureReason); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// HandlePaymentWebhook は決済代行会社からの Webhook を検証し、決済と注文のステータスに反映します。
+// 処理済みのイベントはイベントIDで記録し、重複して配信された場合は何もせずに duplicate を true で返します。
+// レジ締め済みの営業日に決済の成功が通知された場合は ErrBusinessDayClosed を返し、イベントは記録しません。
+func (u *UseCase) HandlePaymentWebhook(ctx context.Context, payload []byte, header http.Header) (duplicate bool, err error) {
+	if u.paymentProvider == nil {
+		return false, models.ErrPaymentProviderNotConfigured
+	}
+	event, err := u.paymentProvider.VerifyWebhook(payload, header, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	processed, err := u.paymentEventRepo.Exists(ctx, event.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check payment event: %w", err)
+	}
+	if processed {
+		return true, nil
+	}
+
+	if status, ok := event.IntentStatus(); ok {
+		payments, err := u.paymentRepo.FindByField(ctx, "intent_id", event.IntentID)
+		if err != nil {
+			return false, fmt.Errorf("failed to find payment: %w", err)
+		}
+		// このシステム以外で作成された支払いの通知は記録のみ行う
+		for _, payment := range payments {
+			if payment.Provider != event.Provider {
+				continue
+			}
+			if err := u.applyPaymentStatus(ctx, payment, status, event.FailureReason); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// 処理に失敗した場合は再送で処理できるよう、反映が完了してから記録する
+	if err := u.paymentEventRepo.Create(ctx, event); err != nil {
+		return false, fmt.Errorf("failed to record payment event: %w", err)
+	}
+	return false, nil
+}
+
+// applyPaymentStatus は決済の状態を更新し、注文のステータスに反映します。
+// 支払い開始後に商品の追加などで注文の金額が変わった場合、注文は支払い待ちのままにします。
+// レジ締め済みの営業日には売上を計上できないため、決済の成功は ErrBusinessDayClosed を返して反映しません。
+func (u *UseCase) applyPaymentStatus(ctx context.Context, payment *models.Payment, status models.PaymentIntentStatus, failureReason string) error {
+	now := time.Now()
+	if status == models.PaymentIntentSucceeded && payment.Status != models.PaymentIntentSucceeded {
+		if err := u.ensureBusinessDayOpen(ctx, payment.StoreID, now); err != nil {
+			return err
+		}
+	}
+	if !payment.ApplyStatus(status, failureReason, now) {
+		return nil
+	}
+	if err := u.paymentRepo.UpdateByID(ctx, payment.ID, payment); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	if _, err := u.sessionUpdates.Update(ctx, payment.SessionID, func(session *models.Session) error {
+		if session.TotalAmount != payment.Amount {
+			return nil
+		}
+		_, err := session.ApplyPaymentStatus(status)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	return nil
+}
+
+// findRefundablePayment は決済代行会社で返金する注文のオンライン決済を返します。
+// オンライン決済のない注文（現金など）の場合は nil を返します。
+func (u *UseCase) findRefundablePayment(ctx context.Context, sessionID string) (*models.Payment, error) {
+	if u.paymentProvider == nil {
+		return nil, nil
+	}
+	payments, err := u.paymentRepo.FindByField(ctx, "session_id", sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payments: %w", err)
+	}
+	for _, payment := range payments {
+		if payment.Status == models.PaymentIntentSucceeded && payment.Provider == u.paymentProvider.Name() {
+			return payment, nil
+		}
+	}
+	return nil, nil
+}
+
+// refundPayment は注文のオンライン決済を決済代行会社で返金し、返金のIDを返します。
+func (u *UseCase) refundPayment(ctx context.Context, payment *models.Payment, refund *models.Refund) (string, error) {
+	// 返金台帳に記録済みの返金のIDを冪等キーとし、再試行で二重に返金しないようにする
+	providerRefund, err := u.paymentProvider.Refund(ctx, payment.IntentID, refund.Amount, refund.ID)
+	if err != nil {
+		return "", err
+	}
+	return providerRefund.ID, nil
+}
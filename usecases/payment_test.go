@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPaymentTestUseCase(t *testing.T) (*UseCase, *repositories.FakePaymentProvider, *models.Session) {
+	t.Helper()
+	ctx := context.Background()
+	useCase := New(nil)
+	provider := repositories.NewFakePaymentProvider("")
+	useCase.SetPaymentProvider(provider)
+	useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(1100))})
+	require.NoError(t, err)
+	sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+	paymentRepo := useCase.paymentRepo.(*repositories.MockPaymentRepository)
+	paymentRepo.On("Create", ctx, mock.AnythingOfType("*models.Payment")).Return(nil)
+	paymentRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.Payment")).Return(nil)
+	return useCase, provider, session
+}
+
+// TestStartOrderPayment tests the StartOrderPayment function
+func TestStartOrderPayment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("start payment", func(t *testing.T) {
+		useCase, _, session := newPaymentTestUseCase(t)
+		useCase.paymentRepo.(*repositories.MockPaymentRepository).On("FindByField", ctx, "session_id", session.ID).Return([]*models.Payment{}, nil)
+
+		payment, secret, err := useCase.StartOrderPayment(ctx, "store_1", "seat_1", session.ID, false)
+		require.NoError(t, err)
+		assert.NotEmpty(t, secret)
+		assert.Equal(t, "fake", payment.Provider)
+		assert.Equal(t, models.Yen(1100), payment.Amount)
+		assert.Equal(t, models.PaymentStatusPending, session.PaymentStatus)
+	})
+
+	t.Run("retry returns the recorded payment", func(t *testing.T) {
+		useCase, _, session := newPaymentTestUseCase(t)
+		paymentRepo := useCase.paymentRepo.(*repositories.MockPaymentRepository)
+		paymentRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Payment{}, nil).Once()
+
+		first, _, err := useCase.StartOrderPayment(ctx, "store_1", "", session.ID, false)
+		require.NoError(t, err)
+		paymentRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Payment{first}, nil)
+
+		second, _, err := useCase.StartOrderPayment(ctx, "store_1", "", session.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		paymentRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("order of another seat", func(t *testing.T) {
+		useCase, _, session := newPaymentTestUseCase(t)
+
+		_, _, err := useCase.StartOrderPayment(ctx, "store_1", "seat_2", session.ID, false)
+		assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	})
+
+	t.Run("provider is not configured", func(t *testing.T) {
+		_, _, err := New(nil).StartOrderPayment(ctx, "store_1", "seat_1", "order_1", false)
+		assert.ErrorIs(t, err, models.ErrPaymentProviderNotConfigured)
+	})
+}
+
+// TestHandlePaymentWebhook tests the HandlePaymentWebhook function
+func TestHandlePaymentWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, *repositories.FakePaymentProvider, *models.Session, *models.Payment) {
+		useCase, provider, session := newPaymentTestUseCase(t)
+		paymentRepo := useCase.paymentRepo.(*repositories.MockPaymentRepository)
+		paymentRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Payment{}, nil)
+		payment, _, err := useCase.StartOrderPayment(ctx, "store_1", "seat_1", session.ID, false)
+		require.NoError(t, err)
+		paymentRepo.On("FindByField", ctx, "intent_id", payment.IntentID).Return([]*models.Payment{payment}, nil)
+		return useCase, provider, session, payment
+	}
+
+	t.Run("succeeded and duplicate delivery", func(t *testing.T) {
+		useCase, provider, session, payment := setup(t)
+		eventRepo := useCase.paymentEventRepo.(*repositories.MockPaymentEventRepository)
+		eventRepo.On("Exists", ctx, mock.Anything).Return(false, nil).Once()
+		eventRepo.On("Create", ctx, mock.AnythingOfType("*models.PaymentEvent")).Return(nil)
+
+		payload, header, err := provider.Succeed(payment.IntentID, time.Now())
+		require.NoError(t, err)
+		duplicate, err := useCase.HandlePaymentWebhook(ctx, payload, header)
+		require.NoError(t, err)
+		assert.False(t, duplicate)
+		assert.Equal(t, models.PaymentIntentSucceeded, payment.Status)
+		assert.Equal(t, models.PaymentStatusPaid, session.PaymentStatus)
+
+		eventRepo.On("Exists", ctx, mock.Anything).Return(true, nil)
+		duplicate, err = useCase.HandlePaymentWebhook(ctx, payload, header)
+		require.NoError(t, err)
+		assert.True(t, duplicate)
+		eventRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		useCase, provider, session, payment := setup(t)
+		eventRepo := useCase.paymentEventRepo.(*repositories.MockPaymentEventRepository)
+		eventRepo.On("Exists", ctx, mock.Anything).Return(false, nil)
+		eventRepo.On("Create", ctx, mock.AnythingOfType("*models.PaymentEvent")).Return(nil)
+
+		payload, header, err := provider.Fail(payment.IntentID, "Your card was declined.", time.Now())
+		require.NoError(t, err)
+		_, err = useCase.HandlePaymentWebhook(ctx, payload, header)
+		require.NoError(t, err)
+		assert.Equal(t, "Your card was declined.", payment.FailureReason)
+		assert.Equal(t, models.PaymentStatusFailed, session.PaymentStatus)
+	})
+
+	t.Run("succeeded after the register close", func(t *testing.T) {
+		useCase, provider, session, payment := setup(t)
+		closeRepo := useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository)
+		closeRepo.On("Exists", ctx, mock.Anything).Unset()
+		closeRepo.On("Exists", ctx, mock.Anything).Return(true, nil)
+		eventRepo := useCase.paymentEventRepo.(*repositories.MockPaymentEventRepository)
+		eventRepo.On("Exists", ctx, mock.Anything).Return(false, nil)
+
+		payload, header, err := provider.Succeed(payment.IntentID, time.Now())
+		require.NoError(t, err)
+		_, err = useCase.HandlePaymentWebhook(ctx, payload, header)
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+		assert.NotEqual(t, models.PaymentIntentSucceeded, payment.Status)
+		assert.NotEqual(t, models.PaymentStatusPaid, session.PaymentStatus)
+		eventRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		useCase, provider, _, payment := setup(t)
+
+		payload, header, err := provider.Succeed(payment.IntentID, time.Now())
+		require.NoError(t, err)
+		header.Set("Stripe-Signature", "t=1,v1=00")
+		_, err = useCase.HandlePaymentWebhook(ctx, payload, header)
+		assert.ErrorIs(t, err, models.ErrInvalidWebhookSignature)
+	})
+}
+
+// TestCapturePayment tests the CapturePayment function
+func TestCapturePayment(t *testing.T) {
+	ctx := context.Background()
+	useCase, provider, session := newPaymentTestUseCase(t)
+	paymentRepo := useCase.paymentRepo.(*repositories.MockPaymentRepository)
+	paymentRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Payment{}, nil)
+
+	payment, _, err := useCase.StartOrderPayment(ctx, "store_1", "", session.ID, true)
+	require.NoError(t, err)
+	paymentRepo.On("FindByID", ctx, payment.ID).Return(payment, nil)
+
+	_, err = useCase.CapturePayment(ctx, "store_1", payment.ID)
+	assert.ErrorIs(t, err, models.ErrPaymentNotCapturable, "オーソリ前は売上を確定できない")
+
+	_, _, err = provider.Authorize(payment.IntentID, time.Now())
+	require.NoError(t, err)
+	payment.Status = models.PaymentIntentRequiresCapture
+
+	captured, err := useCase.CapturePayment(ctx, "store_1", payment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentSucceeded, captured.Status)
+	assert.Equal(t, models.PaymentStatusPaid, session.PaymentStatus)
+
+	t.Run("refund goes through the provider", func(t *testing.T) {
+		session.Status = models.StatusCompleted
+		paymentRepo.On("FindByField", ctx, "session_id", session.ID).Unset()
+		paymentRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Payment{payment}, nil)
+
+		_, refund, err := useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Amount: models.Yen(500), Reason: "提供遅れ"}, false)
+		require.NoError(t, err)
+		assert.Contains(t, refund.PaymentRef, "re_fake_")
+		assert.Equal(t, refund.PaymentRef, session.Refunds[0].PaymentRef)
+		assert.False(t, session.Refunds[0].Pending, "決済代行会社での返金の完了を記録する")
+	})
+
+	t.Run("failed provider refund is retried with the same key", func(t *testing.T) {
+		intentID := payment.IntentID
+		payment.IntentID = "pi_unknown"
+		_, _, err := useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Amount: models.Yen(300), Reason: "品切れ"}, false)
+		assert.ErrorIs(t, err, models.ErrPaymentProviderFailed)
+		require.Len(t, session.Refunds, 2)
+		pending := session.Refunds[1]
+		assert.True(t, pending.Pending, "決済代行会社での返金の前に返金待ちとして記録する")
+
+		payment.IntentID = intentID
+		_, refund, err := useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Amount: models.Yen(300), Reason: "品切れ"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, pending.ID, refund.ID, "返金待ちの記録を再試行する")
+		assert.Contains(t, refund.PaymentRef, "re_fake_")
+		assert.Len(t, session.Refunds, 2, "再試行で返金を二重に記録しない")
+		assert.Equal(t, models.Yen(800), session.RefundedAmount())
+	})
+}
@@ -0,0 +1,243 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// CreatePromotion は店舗のプロモーションコードを登録します。
+// 同じ店舗で有効なプロモーションとコードが重複する場合は登録できません。
+func (u *UseCase) CreatePromotion(ctx context.Context, promotion *models.Promotion) (*models.Promotion, error) {
+	if err := promotion.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.findActivePromotion(ctx, promotion.StoreID, promotion.Code); err == nil {
+		return nil, models.ErrPromotionCodeExists
+	} else if !errors.Is(err, models.ErrPromotionNotFound) {
+		return nil, err
+	}
+
+	if err := u.promotionRepo.Create(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return promotion, nil
+}
+
+// ListPromotions は店舗のプロモーションの一覧を返します。
+func (u *UseCase) ListPromotions(ctx context.Context, storeID string) ([]*models.Promotion, error) {
+	if storeID == "" {
+		return nil, models.ErrStoreIDRequired
+	}
+
+	promotions, err := u.promotionRepo.FindByField(ctx, "store_id", storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+// DeactivatePromotion はプロモーションを停止します。適用済みの割引は取り消されません。
+func (u *UseCase) DeactivatePromotion(ctx context.Context, storeID, id string) (*models.Promotion, error) {
+	promotion, err := u.promotionRepo.FindByID(ctx, id)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("failed to find promotion: %w", err)
+	}
+	if promotion.StoreID != storeID {
+		return nil, models.ErrPromotionNotFound
+	}
+
+	promotion.Deactivate()
+	if err := u.promotionRepo.UpdateByID(ctx, promotion.ID, promotion); err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+	return promotion, nil
+}
+
+// findActivePromotion は店舗の有効なプロモーションをコードで検索します。
+func (u *UseCase) findActivePromotion(ctx context.Context, storeID, code string) (*models.Promotion, error) {
+	code = models.NormalizePromotionCode(code)
+	if code == "" {
+		return nil, models.ErrPromotionCodeRequired
+	}
+
+	promotions, err := u.promotionRepo.FindByField(ctx, "code", code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find promotion: %w", err)
+	}
+	for _, promotion := range promotions {
+		if promotion.StoreID == storeID && promotion.Active {
+			return promotion, nil
+		}
+	}
+	return nil, models.ErrPromotionNotFound
+}
+
+// RedeemPromotion はお客様が入力したプロモーションコードを座席の注文に適用します。
+// visitID は座席の来店IDで、来店ごとの利用回数の上限の判定に使用します。
+// 利用回数の判定と利用記録の作成は同時に行い、同じコードを同時に入力しても上限を超えて適用しません。
+// 割引は注文に適用してから利用記録を作成し、上限に達していた場合は適用した割引を取り消します。
+func (u *UseCase) RedeemPromotion(ctx context.Context, storeID, seatID, visitID, orderID, code string) (*models.Session, *models.Discount, error) {
+	session, err := u.findStoreSession(ctx, storeID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 他の座席の注文は存在しないものとして扱う
+	if session.SeatID != seatID {
+		return nil, nil, models.ErrOrderNotFound
+	}
+
+	promotion, err := u.findActivePromotion(ctx, storeID, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 他の操作で更新された注文を上書きしないよう、最新の注文で利用条件を判定して割引を適用する
+	now := time.Now()
+	var discount *models.Discount
+	updated, err := u.updateStoreSession(ctx, storeID, session.ID, func(session *models.Session) error {
+		var err error
+		if discount, err = promotion.NewDiscount(session, now); err != nil {
+			return err
+		}
+		return session.ApplyDiscount(discount)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redemption := models.NewPromotionRedemption(updated, discount, visitID, now)
+	if err := u.promotionUsages.Redeem(ctx, promotion, redemption); err != nil {
+		// 利用回数の上限などで利用記録を作成できなかったため、適用した割引を取り消す
+		if _, rmErr := u.updateStoreSession(ctx, storeID, updated.ID, func(session *models.Session) error {
+			_, err := session.RemoveDiscount(discount.ID)
+			return err
+		}); rmErr != nil {
+			return nil, nil, fmt.Errorf("failed to redeem promotion: %w (failed to remove discount: %v)", err, rmErr)
+		}
+		if errors.Is(err, models.ErrPromotionAlreadyApplied) || errors.Is(err, models.ErrPromotionUsageLimitReached) ||
+			errors.Is(err, models.ErrPromotionVisitLimitReached) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to redeem promotion: %w", err)
+	}
+	return updated, discount, nil
+}
+
+// ApplyManualDiscount はスタッフが理由を添えて注文に割引を適用します。
+// appliedBy は適用したスタッフ（"manager:<email>" など）で、割引とあわせて記録します。
+func (u *UseCase) ApplyManualDiscount(ctx context.Context, storeID, orderID string, rule models.DiscountRule, reason, appliedBy string) (*models.Session, *models.Discount, error) {
+	discount, err := models.NewManualDiscount(rule, reason, appliedBy, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, err := u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		return session.ApplyDiscount(discount)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, discount, nil
+}
+
+// RemoveDiscount は注文に適用した割引を取り消します。
+// プロモーションの割引の場合は利用記録も削除し、利用回数を戻します。
+func (u *UseCase) RemoveDiscount(ctx context.Context, storeID, orderID, discountID string) (*models.Session, error) {
+	var removed *models.Discount
+	session, err := u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		var err error
+		removed, err = session.RemoveDiscount(discountID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if removed.Source == models.DiscountSourcePromotion {
+		if err := u.deletePromotionRedemptions(ctx, removed.ID); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// deletePromotionRedemptions は取り消したプロモーションの割引の利用記録を削除し、利用回数を戻します。
+func (u *UseCase) deletePromotionRedemptions(ctx context.Context, discountID string) error {
+	redemptions, err := u.redemptionRepo.FindByField(ctx, "discount_id", discountID)
+	if err != nil {
+		return fmt.Errorf("failed to find promotion redemptions: %w", err)
+	}
+	for _, redemption := range redemptions {
+		if err := u.redemptionRepo.DeleteByID(ctx, redemption.ID); err != nil {
+			return fmt.Errorf("failed to delete promotion redemption: %w", err)
+		}
+	}
+	return nil
+}
+
+// revalidatePromotionDiscounts はお客様が商品を変更した注文の、プロモーションの割引を利用条件で判定し直します。
+// 最低利用金額を下回った割引と、対象の商品がなくなり割引額が0になった割引を取り消し、取り消した割引を返します。
+// 残した割引の金額は、変更後の商品に対して再計算されています。
+func (u *UseCase) revalidatePromotionDiscounts(ctx context.Context, session *models.Session) ([]models.Discount, error) {
+	var removed []models.Discount
+	for _, discount := range slices.Clone(session.Discounts) {
+		if discount.Source != models.DiscountSourcePromotion {
+			continue
+		}
+		if !discount.Amount.IsZero() {
+			promotion, err := u.promotionRepo.FindByID(ctx, discount.PromotionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find promotion: %w", err)
+			}
+			if err := promotion.CheckConditions(session); err == nil {
+				continue
+			}
+		}
+
+		d, err := session.RemoveDiscount(discount.ID)
+		if err != nil {
+			return nil, err
+		}
+		removed = append(removed, *d)
+	}
+	return removed, nil
+}
+
+// syncPromotionRedemptions は商品の変更にあわせてプロモーションの利用記録を更新します。
+// 取り消した割引の利用記録は削除し、残した割引の利用記録は再計算後の割引額を記録します。
+func (u *UseCase) syncPromotionRedemptions(ctx context.Context, session *models.Session, removed []models.Discount) error {
+	for _, discount := range removed {
+		if err := u.deletePromotionRedemptions(ctx, discount.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, discount := range session.Discounts {
+		if discount.Source != models.DiscountSourcePromotion {
+			continue
+		}
+		redemptions, err := u.redemptionRepo.FindByField(ctx, "discount_id", discount.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find promotion redemptions: %w", err)
+		}
+		for _, redemption := range redemptions {
+			if redemption.Amount.Amount == discount.Amount.Amount {
+				continue
+			}
+			redemption.Amount = discount.Amount
+			if err := u.redemptionRepo.UpdateByID(ctx, redemption.ID, redemption); err != nil {
+				return fmt.Errorf("failed to update promotion redemption: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPromotionTestSession(t *testing.T) *models.Session {
+	t.Helper()
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 2, models.Yen(1000))})
+	require.NoError(t, err)
+	return session
+}
+
+func newTestPromotion() *models.Promotion {
+	return models.NewPromotion("store_1", "spring10", "春の10%オフ", models.DiscountRule{Kind: models.DiscountPercentage, Value: 10, Scope: models.DiscountScopeOrder}, models.CurrencyJPY)
+}
+
+// TestCreatePromotion tests the CreatePromotion function
+func TestCreatePromotion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.promotionRepo.(*repositories.MockPromotionRepository)
+		mockRepo.On("FindByField", ctx, "code", "SPRING10").Return([]*models.Promotion{}, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Promotion")).Return(nil)
+
+		promotion, err := useCase.CreatePromotion(ctx, newTestPromotion())
+		require.NoError(t, err)
+		assert.Equal(t, "SPRING10", promotion.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate code in the same store", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.promotionRepo.(*repositories.MockPromotionRepository)
+		mockRepo.On("FindByField", ctx, "code", "SPRING10").Return([]*models.Promotion{newTestPromotion()}, nil)
+
+		_, err := useCase.CreatePromotion(ctx, newTestPromotion())
+		assert.ErrorIs(t, err, models.ErrPromotionCodeExists)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		useCase := New(nil)
+		promotion := newTestPromotion()
+		promotion.Rule.Value = 0
+
+		_, err := useCase.CreatePromotion(ctx, promotion)
+		assert.ErrorIs(t, err, models.ErrInvalidDiscountValue)
+	})
+}
+
+// TestRedeemPromotion tests the RedeemPromotion function
+func TestRedeemPromotion(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, promotion *models.Promotion, redemptions []*models.PromotionRedemption) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		session := newPromotionTestSession(t)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+		useCase.promotionRepo.(*repositories.MockPromotionRepository).On("FindByField", ctx, "code", "SPRING10").Return([]*models.Promotion{promotion}, nil)
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.On("FindByField", ctx, "promotion_id", promotion.ID).Return(redemptions, nil)
+		redemptionRepo.On("Create", ctx, mock.AnythingOfType("*models.PromotionRedemption")).Return(nil)
+		return useCase, session
+	}
+
+	t.Run("redeem", func(t *testing.T) {
+		useCase, session := setup(t, newTestPromotion(), nil)
+
+		updated, discount, err := useCase.RedeemPromotion(ctx, "store_1", "seat_1", "visit_1", session.ID, " spring10 ")
+		require.NoError(t, err)
+		assert.Equal(t, models.Yen(200), discount.Amount)
+		assert.Equal(t, models.Yen(1800), updated.TotalAmount)
+
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(r *models.PromotionRedemption) bool {
+			return r.VisitID == "visit_1" && r.SessionID == session.ID && r.DiscountID == discount.ID
+		}))
+	})
+
+	t.Run("visit limit reached", func(t *testing.T) {
+		promotion := newTestPromotion()
+		promotion.MaxPerVisit = 1
+		useCase, session := setup(t, promotion, []*models.PromotionRedemption{{SessionID: "other", VisitID: "visit_1"}})
+
+		_, _, err := useCase.RedeemPromotion(ctx, "store_1", "seat_1", "visit_1", session.ID, "SPRING10")
+		assert.ErrorIs(t, err, models.ErrPromotionVisitLimitReached)
+		assert.Empty(t, session.Discounts, "利用記録を作成できなかった割引は取り消す")
+		assert.Equal(t, models.Yen(2000), session.TotalAmount)
+	})
+
+	t.Run("concurrent redemptions do not exceed the limit", func(t *testing.T) {
+		promotion := newTestPromotion()
+		promotion.MaxRedemptions = 1
+		useCase := New(nil)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		useCase.promotionRepo.(*repositories.MockPromotionRepository).On("FindByField", ctx, "code", "SPRING10").Return([]*models.Promotion{promotion}, nil)
+
+		// 利用回数の判定は排他的に行われるため、最初の判定以降は先に作成された利用記録が見える
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.On("FindByField", ctx, "promotion_id", promotion.ID).Return([]*models.PromotionRedemption{}, nil).Once()
+		redemptionRepo.On("FindByField", ctx, "promotion_id", promotion.ID).Return([]*models.PromotionRedemption{{SessionID: "first"}}, nil)
+		redemptionRepo.On("Create", ctx, mock.AnythingOfType("*models.PromotionRedemption")).Return(nil)
+
+		sessions := make([]*models.Session, 5)
+		for i := range sessions {
+			sessions[i] = newPromotionTestSession(t)
+			sessionRepo.On("FindByID", ctx, sessions[i].ID).Return(sessions[i], nil)
+			sessionRepo.On("UpdateByID", ctx, sessions[i].ID, mock.AnythingOfType("*models.Session")).Return(nil)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(sessions))
+		for i, session := range sessions {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, errs[i] = useCase.RedeemPromotion(ctx, "store_1", "seat_1", "visit_"+session.ID, session.ID, "SPRING10")
+			}()
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, models.ErrPromotionUsageLimitReached)
+		}
+		assert.Equal(t, 1, succeeded, "上限の1回のみ適用される")
+		redemptionRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("order update failed", func(t *testing.T) {
+		useCase := New(nil)
+		promotion := newTestPromotion()
+		session := newPromotionTestSession(t)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(errors.New("unavailable"))
+		useCase.promotionRepo.(*repositories.MockPromotionRepository).On("FindByField", ctx, "code", "SPRING10").Return([]*models.Promotion{promotion}, nil)
+		redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+		redemptionRepo.On("FindByField", ctx, "promotion_id", promotion.ID).Return([]*models.PromotionRedemption{}, nil)
+		redemptionRepo.On("Create", ctx, mock.AnythingOfType("*models.PromotionRedemption")).Return(nil)
+		redemptionRepo.On("DeleteByID", ctx, mock.AnythingOfType("string")).Return(nil)
+
+		_, _, err := useCase.RedeemPromotion(ctx, "store_1", "seat_1", "visit_1", session.ID, "SPRING10")
+		assert.Error(t, err)
+		redemptionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("code of another store", func(t *testing.T) {
+		promotion := newTestPromotion()
+		promotion.StoreID = "store_2"
+		useCase, session := setup(t, promotion, nil)
+
+		_, _, err := useCase.RedeemPromotion(ctx, "store_1", "seat_1", "visit_1", session.ID, "SPRING10")
+		assert.ErrorIs(t, err, models.ErrPromotionNotFound)
+	})
+
+	t.Run("order of another seat", func(t *testing.T) {
+		useCase, session := setup(t, newTestPromotion(), nil)
+
+		_, _, err := useCase.RedeemPromotion(ctx, "store_1", "seat_2", "visit_1", session.ID, "SPRING10")
+		assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	})
+}
+
+// TestApplyManualDiscount tests the ApplyManualDiscount function
+func TestApplyManualDiscount(t *testing.T) {
+	ctx := context.Background()
+	rule := models.DiscountRule{Kind: models.DiscountFixed, Value: 300, Scope: models.DiscountScopeOrder}
+
+	t.Run("apply", func(t *testing.T) {
+		useCase := New(nil)
+		session := newPromotionTestSession(t)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+
+		updated, discount, err := useCase.ApplyManualDiscount(ctx, "store_1", session.ID, rule, "提供遅れのお詫び", "manager:a@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, models.DiscountSourceManual, discount.Source)
+		assert.Equal(t, "manager:a@example.com", discount.AppliedBy)
+		assert.Equal(t, models.Yen(1700), updated.TotalAmount)
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		useCase := New(nil)
+		_, _, err := useCase.ApplyManualDiscount(ctx, "store_1", "order_1", rule, "", "manager:a@example.com")
+		assert.ErrorIs(t, err, models.ErrDiscountReasonRequired)
+	})
+}
+
+// TestRemoveDiscount tests the RemoveDiscount function
+func TestRemoveDiscount(t *testing.T) {
+	ctx := context.Background()
+
+	useCase := New(nil)
+	session := newPromotionTestSession(t)
+	discount, err := newTestPromotion().NewDiscount(session, session.CreatedAt)
+	require.NoError(t, err)
+	require.NoError(t, session.ApplyDiscount(discount))
+
+	sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+	redemptionRepo := useCase.redemptionRepo.(*repositories.MockPromotionRedemptionRepository)
+	redemptionRepo.On("FindByField", ctx, "discount_id", discount.ID).Return([]*models.PromotionRedemption{{ID: "redeem_1"}}, nil)
+	redemptionRepo.On("DeleteByID", ctx, "redeem_1").Return(nil)
+
+	updated, err := useCase.RemoveDiscount(ctx, "store_1", session.ID, discount.ID)
+	require.NoError(t, err)
+	assert.Empty(t, updated.Discounts)
+	assert.Equal(t, models.Yen(2000), updated.TotalAmount)
+	redemptionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+)
+
+// lookupReasonCode は店舗の理由コードの一覧から、種類とコードに一致する有効な理由コードを返します。
+func (u *UseCase) lookupReasonCode(ctx context.Context, storeID string, kind models.ReasonKind, code string) (models.ReasonCode, error) {
+	if code == "" {
+		return models.ReasonCode{}, fmt.Errorf("%w: %s", models.ErrReasonCodeRequired, kind)
+	}
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return models.ReasonCode{}, fmt.Errorf("failed to find store: %w", err)
+	}
+	return store.LookupReasonCode(kind, code)
+}
+
+// ListReasonCodes は店舗の理由コードの一覧を返します。未設定の場合は既定の一覧です。
+func (u *UseCase) ListReasonCodes(ctx context.Context, storeID string) ([]models.ReasonCode, error) {
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	return store.ReasonCodeCatalog(), nil
+}
+
+// GetReasonReport は営業日 from から to まで（両端を含む）のキャンセル・辞退・保留・明細の取り消しの理由を集計します。
+func (u *UseCase) GetReasonReport(ctx context.Context, storeID, from, to string) (*models.ReasonReport, error) {
+	start, _, err := models.BusinessDayRange(from)
+	if err != nil {
+		return nil, err
+	}
+	_, end, err := models.BusinessDayRange(to)
+	if err != nil {
+		return nil, err
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: %s - %s", models.ErrInvalidBusinessDay, from, to)
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	sessions, err := u.sessionRepo.FindByField(ctx, "store_id", storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+	return models.NewReasonReport(store, sessions, start, end)
+}
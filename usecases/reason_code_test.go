@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetReasonReport tests the GetReasonReport function
+func TestGetReasonReport(t *testing.T) {
+	ctx := context.Background()
+	start, _, err := models.BusinessDayRange("2025-04-01")
+	require.NoError(t, err)
+
+	t.Run("aggregates the reasons of the period", func(t *testing.T) {
+		useCase := New(nil)
+		store := &models.Store{ID: "store_1"}
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(500))})
+		require.NoError(t, err)
+		reason, err := store.LookupReasonCode(models.ReasonDecline, "kitchen_busy")
+		require.NoError(t, err)
+		require.NoError(t, session.UpdateStatusWithReason(models.StatusDeclined, "manager:a@example.com", reason, ""))
+		session.StatusHistory[len(session.StatusHistory)-1].At = start.AddDate(0, 0, 1)
+
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(store, nil)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByField", ctx, "store_id", "store_1").Return([]*models.Session{session}, nil)
+
+		report, err := useCase.GetReasonReport(ctx, "store_1", "2025-04-01", "2025-04-02")
+		require.NoError(t, err)
+		require.Len(t, report.Totals, 1)
+		assert.Equal(t, models.ReasonDecline, report.Totals[0].Kind)
+		assert.Equal(t, "kitchen_busy", report.Totals[0].Code)
+		assert.Equal(t, models.Yen(500), report.Totals[0].Amount)
+	})
+
+	t.Run("reversed period", func(t *testing.T) {
+		useCase := New(nil)
+
+		_, err := useCase.GetReasonReport(ctx, "store_1", "2025-04-02", "2025-04-01")
+		assert.ErrorIs(t, err, models.ErrInvalidBusinessDay)
+	})
+}
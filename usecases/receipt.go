@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+// receiptSequenceKey は領収書番号を店舗ごとに採番するためのキーを返します。
+func receiptSequenceKey(storeID string) string {
+	return "receipt:" + storeID
+}
+
+// IssueReceipt は会計が完了した注文の適格請求書（領収書）を発行します。
+// 1つの注文につき発行は1回のみで、2回目以降は ReissueReceipt で再発行します。
+// 領収書は注文IDから決まるIDで作成するため、同時に発行した場合も1件のみ作成されます。
+func (u *UseCase) IssueReceipt(ctx context.Context, storeID, sessionID, recipientName string) (*models.Receipt, error) {
+	session, err := u.findStoreSession(ctx, storeID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := u.receiptRepo.FindByField(ctx, "session_id", session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipts: %w", err)
+	}
+	if len(issued) > 0 {
+		return nil, models.ErrReceiptAlreadyIssued
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+
+	// 検証に失敗した場合に欠番が生じないよう、領収書を作成してから採番する
+	receipt, err := models.NewReceipt(store, session, 0, recipientName, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 採番と作成は同じトランザクションで行い、作成に失敗した場合は番号を消費しない
+	if err := u.receiptIssuer.Issue(ctx, receiptSequenceKey(storeID), receipt); err != nil {
+		if repositories.IsAlreadyExists(err) {
+			return nil, models.ErrReceiptAlreadyIssued
+		}
+		return nil, fmt.Errorf("failed to create receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// ReissueReceipt は発行済みの領収書を再発行します。
+// 内容と番号は最初の発行時のままで、再発行の回数と日時のみを記録します。
+func (u *UseCase) ReissueReceipt(ctx context.Context, storeID, receiptID string) (*models.Receipt, error) {
+	receipt, err := u.GetReceipt(ctx, storeID, receiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.Reissue(time.Now())
+	if err := u.receiptRepo.UpdateByID(ctx, receipt.ID, receipt); err != nil {
+		return nil, fmt.Errorf("failed to update receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// GetReceipt は店舗の発行済み領収書を返します。
+func (u *UseCase) GetReceipt(ctx context.Context, storeID, receiptID string) (*models.Receipt, error) {
+	receipt, err := u.receiptRepo.FindByID(ctx, receiptID)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrReceiptNotFound
+		}
+		return nil, fmt.Errorf("failed to find receipt: %w", err)
+	}
+	// 他店舗の領収書は存在しないものとして扱う
+	if receipt.StoreID != storeID {
+		return nil, models.ErrReceiptNotFound
+	}
+	return receipt, nil
+}
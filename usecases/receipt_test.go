@@ -0,0 +1,195 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newReceiptTestSession(t *testing.T) *models.Session {
+	t.Helper()
+	session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 1, models.Yen(1100))})
+	require.NoError(t, err)
+	session.Status = models.StatusCompleted
+	session.PaymentStatus = models.PaymentStatusPaid
+	return session
+}
+
+func newReceiptTestStore() *models.Store {
+	return &models.Store{ID: "store_1", Name: "Store", InvoiceRegistrationNumber: "T1234567890123"}
+}
+
+// TestIssueReceipt tests the IssueReceipt function
+func TestIssueReceipt(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("issue receipts with sequential numbers", func(t *testing.T) {
+		useCase := New(nil)
+		first, second := newReceiptTestSession(t), newReceiptTestSession(t)
+
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, first.ID).Return(first, nil)
+		sessionRepo.On("FindByID", ctx, second.ID).Return(second, nil)
+		storeRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		storeRepo.On("FindByID", ctx, "store_1").Return(newReceiptTestStore(), nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByField", ctx, "session_id", mock.Anything).Return([]*models.Receipt{}, nil)
+		receiptRepo.On("Create", ctx, mock.AnythingOfType("*models.Receipt")).Return(nil)
+
+		receipt, err := useCase.IssueReceipt(ctx, "store_1", first.ID, "株式会社テスト")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), receipt.Number)
+		assert.Equal(t, "T1234567890123", receipt.Issuer.RegistrationNumber)
+		assert.Equal(t, models.Yen(100), receipt.TaxTotal)
+
+		receipt, err = useCase.IssueReceipt(ctx, "store_1", second.ID, "株式会社テスト")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), receipt.Number)
+		receiptRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("issued concurrently", func(t *testing.T) {
+		useCase := New(nil)
+		session := newReceiptTestSession(t)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByID", ctx, session.ID).Return(session, nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(newReceiptTestStore(), nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Receipt{}, nil)
+		receiptRepo.On("Create", ctx, mock.MatchedBy(func(r *models.Receipt) bool {
+			return r.ID == models.ReceiptIDFor(session.ID)
+		})).Return(status.Error(codes.AlreadyExists, "already exists"))
+
+		_, err := useCase.IssueReceipt(ctx, "store_1", session.ID, "株式会社テスト")
+		assert.ErrorIs(t, err, models.ErrReceiptAlreadyIssued, "他のリクエストが先に発行した")
+	})
+
+	t.Run("already issued", func(t *testing.T) {
+		useCase := New(nil)
+		session := newReceiptTestSession(t)
+
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByID", ctx, session.ID).Return(session, nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Receipt{{ID: "receipt_1"}}, nil)
+
+		_, err := useCase.IssueReceipt(ctx, "store_1", session.ID, "株式会社テスト")
+		assert.ErrorIs(t, err, models.ErrReceiptAlreadyIssued)
+		receiptRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("validation failure does not consume a number", func(t *testing.T) {
+		useCase := New(nil)
+		session := newReceiptTestSession(t)
+
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByID", ctx, session.ID).Return(session, nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(newReceiptTestStore(), nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Receipt{}, nil)
+		receiptRepo.On("Create", ctx, mock.AnythingOfType("*models.Receipt")).Return(nil)
+
+		_, err := useCase.IssueReceipt(ctx, "store_1", session.ID, "")
+		assert.ErrorIs(t, err, models.ErrRecipientNameRequired)
+
+		receipt, err := useCase.IssueReceipt(ctx, "store_1", session.ID, "株式会社テスト")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), receipt.Number)
+	})
+
+	t.Run("create failure does not consume a number", func(t *testing.T) {
+		useCase := New(nil)
+		session := newReceiptTestSession(t)
+
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByID", ctx, session.ID).Return(session, nil)
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(newReceiptTestStore(), nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByField", ctx, "session_id", session.ID).Return([]*models.Receipt{}, nil)
+		receiptRepo.On("Create", ctx, mock.AnythingOfType("*models.Receipt")).Return(errors.New("firestore unavailable")).Once()
+		receiptRepo.On("Create", ctx, mock.AnythingOfType("*models.Receipt")).Return(nil)
+
+		_, err := useCase.IssueReceipt(ctx, "store_1", session.ID, "株式会社テスト")
+		assert.Error(t, err)
+
+		receipt, err := useCase.IssueReceipt(ctx, "store_1", session.ID, "株式会社テスト")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), receipt.Number)
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		useCase := New(nil)
+		session := newReceiptTestSession(t)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByID", ctx, session.ID).Return(session, nil)
+
+		_, err := useCase.IssueReceipt(ctx, "store_2", session.ID, "株式会社テスト")
+		assert.ErrorIs(t, err, models.ErrSessionStoreMismatch)
+	})
+}
+
+// TestReissueReceipt tests the ReissueReceipt function
+func TestReissueReceipt(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reissue", func(t *testing.T) {
+		useCase := New(nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByID", ctx, "receipt_1").Return(&models.Receipt{ID: "receipt_1", StoreID: "store_1", Number: 3}, nil)
+		receiptRepo.On("UpdateByID", ctx, "receipt_1", mock.MatchedBy(func(r *models.Receipt) bool {
+			return r.ReissueCount == 1 && r.Number == 3
+		})).Return(nil)
+
+		receipt, err := useCase.ReissueReceipt(ctx, "store_1", "receipt_1")
+		require.NoError(t, err)
+		assert.True(t, receipt.IsReissue())
+		receiptRepo.AssertExpectations(t)
+	})
+
+	t.Run("receipt of another store", func(t *testing.T) {
+		useCase := New(nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByID", ctx, "receipt_1").Return(&models.Receipt{ID: "receipt_1", StoreID: "store_2"}, nil)
+
+		_, err := useCase.ReissueReceipt(ctx, "store_1", "receipt_1")
+		assert.ErrorIs(t, err, models.ErrReceiptNotFound)
+		receiptRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		useCase := New(nil)
+		receiptRepo := useCase.receiptRepo.(*repositories.MockReceiptRepository)
+		receiptRepo.On("FindByID", ctx, "receipt_x").Return(nil, status.Error(codes.NotFound, "not found"))
+
+		_, err := useCase.ReissueReceipt(ctx, "store_1", "receipt_x")
+		assert.ErrorIs(t, err, models.ErrReceiptNotFound)
+	})
+}
+
+// TestUpdateStoreInvoiceRegistration tests the UpdateStoreInvoiceRegistration function
+func TestUpdateStoreInvoiceRegistration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("normalized number is saved", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.storeRepo.(*repositories.MockStoreRepository)
+		mockRepo.On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1"}, nil)
+		mockRepo.On("UpdateByID", ctx, "store_1", mock.MatchedBy(func(s *models.Store) bool {
+			return s.InvoiceRegistrationNumber == "T1234567890123" && s.Password == ""
+		})).Return(nil)
+
+		store, err := useCase.UpdateStoreInvoiceRegistration(ctx, "store_1", "t1234-5678-90123")
+		require.NoError(t, err)
+		assert.Equal(t, "T1234567890123", store.InvoiceRegistrationNumber)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		useCase := New(nil)
+		_, err := useCase.UpdateStoreInvoiceRegistration(ctx, "store_1", "T123")
+		assert.ErrorIs(t, err, models.ErrInvalidInvoiceRegistrationNumber)
+	})
+}
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// RefundOrder は注文の返金を返金台帳に記録します。
+// full が true の場合は返金可能な残りの金額を全て返金します。
+// 返金可能な残額の判定と返金台帳への記録は1つのトランザクションで行い、同時に返金しても支払額を超えて返金しません。
+// オンライン決済の注文で PaymentRef を指定しない場合は、返金を決済代行会社での返金待ちとして記録してから、
+// 決済代行会社でも返金し、返金のIDを記録します。決済代行会社での返金に失敗した返金待ちの記録がある場合は、
+// 新しい返金を記録せずに、同じ返金を同じ冪等キーで再試行して返します。
+// レジ締め済みの営業日には返金できません。
+func (u *UseCase) RefundOrder(ctx context.Context, storeID, orderID string, req models.RefundRequest, full bool) (*models.Session, *models.Refund, error) {
+	now := time.Now()
+	var payment *models.Payment
+	if req.PaymentRef == "" {
+		var err error
+		if payment, err = u.findRefundablePayment(ctx, orderID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 返金台帳への記録は同じトランザクションでレジ締めを確認し、締めた営業日の集計後に返金を記録しない
+	var refund *models.Refund
+	session, err := u.updateOpenStoreSession(ctx, storeID, orderID, businessDayCloseID(storeID, now), func(session *models.Session) error {
+		if payment != nil {
+			if refund = session.PendingRefund(); refund != nil {
+				return nil
+			}
+		}
+		var err error
+		if full {
+			refund, err = session.MarkRefundFully(req, now)
+		} else {
+			refund, err = session.MarkRefundPartially(req, now)
+		}
+		if err != nil {
+			return err
+		}
+		if payment != nil {
+			refund, err = session.MarkRefundPending(refund.ID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment == nil {
+		return session, refund, nil
+	}
+
+	// オンライン決済の注文は決済代行会社で返金し、返金のIDを記録する
+	ref, err := u.refundPayment(ctx, payment, refund)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err = u.updateStoreSession(ctx, storeID, orderID, func(session *models.Session) error {
+		var err error
+		refund, err = session.CompleteRefund(refund.ID, ref)
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record refund: %w", err)
+	}
+	return session, refund, nil
+}
+
+// GetOrderRefunds は店舗の注文を返金の履歴とあわせて返します。
+func (u *UseCase) GetOrderRefunds(ctx context.Context, storeID, orderID string) (*models.Session, error) {
+	return u.findStoreSession(ctx, storeID, orderID)
+}
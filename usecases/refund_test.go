@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRefundOrder tests the RefundOrder function
+func TestRefundOrder(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+		session := newReceiptTestSession(t)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		sessionRepo.On("UpdateByID", ctx, session.ID, mock.AnythingOfType("*models.Session")).Return(nil)
+		return useCase, session
+	}
+
+	t.Run("partial then full", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		updated, refund, err := useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Amount: models.Yen(100), Reason: "提供遅れ", Actor: "manager:a@example.com"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, models.Yen(100), refund.Amount)
+		assert.Equal(t, models.PaymentStatusPartiallyRefunded, updated.PaymentStatus)
+		assert.Equal(t, models.StatusCompleted, updated.Status)
+
+		updated, refund, err = useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Reason: "注文の取り消し"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, models.Yen(1000), refund.Amount)
+		assert.Equal(t, models.PaymentStatusRefunded, updated.PaymentStatus)
+		assert.Len(t, updated.Refunds, 2)
+	})
+
+	t.Run("exceeding the paid amount is not saved", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, _, err := useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Amount: models.Yen(1101), Reason: "提供遅れ"}, false)
+		assert.ErrorIs(t, err, models.ErrRefundAmountExceedsTotal)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent refunds do not exceed the paid amount", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 3)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, errs[i] = useCase.RefundOrder(ctx, "store_1", session.ID, models.RefundRequest{Amount: models.Yen(500), Reason: "提供遅れ"}, false)
+			}()
+		}
+		wg.Wait()
+
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, models.ErrRefundAmountExceedsTotal)
+				failed++
+			}
+		}
+		assert.Equal(t, 1, failed, "支払額の1100円を超える3回目の返金は記録しない")
+		assert.Equal(t, models.Yen(1000), session.RefundedAmount())
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		useCase, session := setup(t)
+
+		_, _, err := useCase.RefundOrder(ctx, "store_2", session.ID, models.RefundRequest{Amount: models.Yen(100), Reason: "提供遅れ"}, false)
+		assert.ErrorIs(t, err, models.ErrSessionStoreMismatch)
+	})
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+// CloseBusinessDay は店舗の営業日のレジ締めを行います。
+// 営業日の売上をZレポートに集計し、現金の理論在高と実査額の過不足を記録します。
+// day が空の場合は現在の営業日を締めます。締めた営業日は、以降の精算・返金を受け付けません。
+// 売上の読み取りとレジ締めの作成は1つのトランザクションで行い、集計と同時に記録された精算・返金・伝票の支払いは
+// 集計に含めるか、レジ締め済みとして記録を拒否します。
+func (u *UseCase) CloseBusinessDay(ctx context.Context, storeID, day string, openingFloat, countedCash models.Money, actor, note string) (*models.RegisterClose, error) {
+	now := time.Now()
+	if day == "" {
+		day = models.BusinessDayOf(now)
+	}
+	start, _, err := models.BusinessDayRange(day)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(now) {
+		return nil, models.ErrBusinessDayNotStarted
+	}
+	if err := u.ensureNotClosed(ctx, storeID, day); err != nil {
+		return nil, err
+	}
+
+	store, err := u.storeRepo.FindByID(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find store: %w", err)
+	}
+	var invalid bool
+	closed, err := u.registerCloses.Close(ctx, storeID, func(in models.ZReportInput) (*models.RegisterClose, error) {
+		invalid = false
+		report, err := models.NewZReport(store, day, in)
+		if err != nil {
+			invalid = true
+			return nil, err
+		}
+		closed, err := models.NewRegisterClose(report, openingFloat, countedCash, actor, note, now)
+		if err != nil {
+			invalid = true
+			return nil, err
+		}
+		return closed, nil
+	})
+	if err != nil {
+		if invalid {
+			return nil, err
+		}
+		// 同じ営業日を同時に締めた場合も、IDが同じため2件目の作成は失敗する
+		if repositories.IsAlreadyExists(err) {
+			return nil, models.ErrBusinessDayClosed
+		}
+		return nil, fmt.Errorf("failed to close business day: %w", err)
+	}
+	return closed, nil
+}
+
+// GetRegisterClose は店舗の営業日のレジ締めの記録（Zレポート）を返します。
+func (u *UseCase) GetRegisterClose(ctx context.Context, storeID, day string) (*models.RegisterClose, error) {
+	if _, _, err := models.BusinessDayRange(day); err != nil {
+		return nil, err
+	}
+	closed, err := u.registerCloseRepo.FindByID(ctx, models.RegisterCloseID(storeID, day))
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrRegisterCloseNotFound
+		}
+		return nil, fmt.Errorf("failed to find register close: %w", err)
+	}
+	return closed, nil
+}
+
+// businessDayCloseID は現在の営業日のレジ締めのIDを返します。
+// 精算・返金・伝票の支払いは、記録と同じトランザクションでこのレジ締めがないことを確認します。
+func businessDayCloseID(storeID string, now time.Time) string {
+	return models.RegisterCloseID(storeID, models.BusinessDayOf(now))
+}
+
+// ensureBusinessDayOpen は現在の営業日がレジ締め済みでないことを確認します。
+func (u *UseCase) ensureBusinessDayOpen(ctx context.Context, storeID string, now time.Time) error {
+	return u.ensureNotClosed(ctx, storeID, models.BusinessDayOf(now))
+}
+
+// ensureNotClosed は営業日がレジ締め済みでないことを確認します。
+func (u *UseCase) ensureNotClosed(ctx context.Context, storeID, day string) error {
+	closed, err := u.registerCloseRepo.Exists(ctx, models.RegisterCloseID(storeID, day))
+	if err != nil {
+		return fmt.Errorf("failed to check register close: %w", err)
+	}
+	if closed {
+		return models.ErrBusinessDayClosed
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestCloseBusinessDay tests the CloseBusinessDay function
+func TestCloseBusinessDay(t *testing.T) {
+	ctx := context.Background()
+	today := models.BusinessDayOf(time.Now())
+
+	setup := func(t *testing.T, closed bool, createErr error) (*UseCase, *models.Session) {
+		useCase := New(nil)
+		session, err := models.NewSession("store_1", "seat_1", []models.Order{*models.NewOrder("prod_1", 2, models.Yen(1100))})
+		require.NoError(t, err)
+		settlement := &models.Settlement{
+			ID: "settle_1", StoreID: "store_1", SessionIDs: []string{session.ID}, Total: session.TotalAmount,
+			Tenders: []models.Tender{{Method: models.TenderCash, Amount: models.Yen(5000)}},
+			Change:  models.NewMoney(5000-session.TotalAmount.Amount, session.Currency()), CreatedAt: time.Now(),
+		}
+
+		useCase.storeRepo.(*repositories.MockStoreRepository).On("FindByID", ctx, "store_1").Return(&models.Store{ID: "store_1", Name: "テスト店"}, nil)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByField", ctx, "store_id", "store_1").Return([]*models.Session{session}, nil)
+		useCase.settlementRepo.(*repositories.MockSettlementRepository).On("FindByField", ctx, "store_id", "store_1").Return([]*models.Settlement{settlement}, nil)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).On("FindByField", ctx, "store_id", "store_1").Return([]*models.BillSplit{}, nil)
+		useCase.paymentRepo.(*repositories.MockPaymentRepository).On("FindByField", ctx, "store_id", "store_1").Return([]*models.Payment{}, nil)
+		closeRepo := useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository)
+		closeRepo.On("Exists", ctx, models.RegisterCloseID("store_1", today)).Return(closed, nil)
+		closeRepo.On("Create", ctx, mock.AnythingOfType("*models.RegisterClose")).Return(createErr)
+		return useCase, session
+	}
+
+	t.Run("close the current business day", func(t *testing.T) {
+		useCase, session := setup(t, false, nil)
+
+		closed, err := useCase.CloseBusinessDay(ctx, "store_1", "", models.Yen(10000), models.NewMoney(10000+session.TotalAmount.Amount, session.Currency()), "manager:a@example.com", "")
+		require.NoError(t, err)
+		assert.Equal(t, today, closed.BusinessDay)
+		assert.Equal(t, 1, closed.Report.OrderCount)
+		assert.Equal(t, session.TotalAmount, closed.CashSales)
+		assert.True(t, closed.OverShort.IsZero())
+		useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).AssertCalled(t, "Create", ctx, closed)
+	})
+
+	t.Run("a business day is closed only once", func(t *testing.T) {
+		useCase, _ := setup(t, true, nil)
+
+		_, err := useCase.CloseBusinessDay(ctx, "store_1", today, models.Yen(0), models.Yen(0), "", "")
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+	})
+
+	t.Run("a business day closed concurrently", func(t *testing.T) {
+		useCase, _ := setup(t, false, status.Error(codes.AlreadyExists, "already exists"))
+
+		_, err := useCase.CloseBusinessDay(ctx, "store_1", today, models.Yen(0), models.Yen(0), "", "")
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+	})
+
+	t.Run("a future business day cannot be closed", func(t *testing.T) {
+		useCase, _ := setup(t, false, nil)
+
+		tomorrow := time.Now().AddDate(0, 0, 2).Format(models.BusinessDayLayout)
+		_, err := useCase.CloseBusinessDay(ctx, "store_1", tomorrow, models.Yen(0), models.Yen(0), "", "")
+		assert.ErrorIs(t, err, models.ErrBusinessDayNotStarted)
+	})
+
+	t.Run("settlements are locked after the close", func(t *testing.T) {
+		useCase, _ := setup(t, true, nil)
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", []models.Tender{{Method: models.TenderCash, Amount: models.Yen(5000)}}, "", false)
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+	})
+
+	t.Run("refunds and sub-bill payments are locked after the close", func(t *testing.T) {
+		useCase, _ := setup(t, true, nil)
+
+		_, _, err := useCase.RefundOrder(ctx, "store_1", "session_1", models.RefundRequest{Amount: models.Yen(100), Reason: "返金"}, false)
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+		_, err = useCase.PaySubBill(ctx, "store_1", "split_1", "sub_1", models.TenderCash, "", "")
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestGetRegisterClose tests the GetRegisterClose function
+func TestGetRegisterClose(t *testing.T) {
+	ctx := context.Background()
+	useCase := New(nil)
+	closeRepo := useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository)
+	closeRepo.On("FindByID", ctx, "store_1_2026-10-17").Return(&models.RegisterClose{ID: "store_1_2026-10-17", StoreID: "store_1"}, nil)
+	closeRepo.On("FindByID", ctx, "store_1_2026-10-16").Return(nil, status.Error(codes.NotFound, "not found"))
+
+	closed, err := useCase.GetRegisterClose(ctx, "store_1", "2026-10-17")
+	require.NoError(t, err)
+	assert.Equal(t, "store_1", closed.StoreID)
+
+	_, err = useCase.GetRegisterClose(ctx, "store_1", "2026-10-16")
+	assert.ErrorIs(t, err, models.ErrRegisterCloseNotFound)
+
+	_, err = useCase.GetRegisterClose(ctx, "store_1", "10/17")
+	assert.ErrorIs(t, err, models.ErrInvalidBusinessDay)
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RegisterSeat は店舗に座席を登録します。
+func (u *UseCase) RegisterSeat(ctx context.Context, storeID, name string) (*models.Seat, error) {
+	if storeID == "" {
+		return nil, models.ErrStoreIDRequired
+	}
+
+	seat := models.NewSeat(name)
+	seat.StoreID = storeID
+
+	if err := u.seatRepo.Create(ctx, seat); err != nil {
+		return nil, fmt.Errorf("failed to create seat: %w", err)
+	}
+	return seat, nil
+}
+
+// findStoreSeat は座席を取得し、指定店舗に属していることを確認します。
+func (u *UseCase) findStoreSeat(ctx context.Context, storeID, seatID string) (*models.Seat, error) {
+	seat, err := u.seatRepo.FindByID(ctx, seatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find seat: %w", err)
+	}
+	if seat.StoreID != storeID {
+		return nil, models.ErrSeatStoreMismatch
+	}
+	return seat, nil
+}
+
+// updateStoreSeat は店舗の座席を読み取り、update で変更して保存します。他店舗の座席は変更せず ErrSeatStoreMismatch を返します。
+// 来店の開始や退席と同時に更新しても互いの変更（来店やQRコードの再発行）を上書きしないよう、読み取りと保存は不可分に行います。
+func (u *UseCase) updateStoreSeat(ctx context.Context, storeID, seatID string, update func(*models.Seat) error) (*models.Seat, error) {
+	seat, err := u.seatUpdates.Update(ctx, seatID, func(seat *models.Seat) error {
+		if seat.StoreID != storeID {
+			return models.ErrSeatStoreMismatch
+		}
+		return update(seat)
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrSeatStoreMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update seat: %w", err)
+	}
+	return seat, nil
+}
+
+// IssueSeatQRToken は座席QRコードに埋め込む署名付きトークンを発行します。
+// dynamicTTL が0より大きい場合は、その期間だけ有効な動的QRトークンを発行します。
+func (u *UseCase) IssueSeatQRToken(ctx context.Context, storeID, seatID string, dynamicTTL time.Duration) (string, *models.SeatQRToken, error) {
+	seat, err := u.findStoreSeat(ctx, storeID, seatID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := models.NewSeatQRToken(seat)
+	if dynamicTTL > 0 {
+		token = models.NewDynamicSeatQRToken(seat, dynamicTTL)
+	}
+
+	signed, err := token.Sign()
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, token, nil
+}
+
+// RotateSeatQR は座席のQRコードを再発行扱いにし、既存の印刷物を無効にします。
+func (u *UseCase) RotateSeatQR(ctx context.Context, storeID, seatID string) (*models.Seat, error) {
+	return u.updateStoreSeat(ctx, storeID, seatID, func(seat *models.Seat) error {
+		seat.RotateQR()
+		return nil
+	})
+}
+
+// VerifySeatQR はQRコードから読み取ったトークンを検証し、有効なトークンを返します。
+// 署名・有効期限に加えて、座席の再発行回数と一致することを確認します。
+func (u *UseCase) VerifySeatQR(ctx context.Context, token string) (*models.SeatQRToken, error) {
+	parsed, err := models.ParseSeatQRToken(token, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	seat, err := u.findStoreSeat(ctx, parsed.StoreID, parsed.SeatID)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsed.VerifySeat(seat); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStoreSeat() *models.Seat {
+	seat := models.NewSeat("Table 1")
+	seat.StoreID = "store_123"
+	return seat
+}
+
+// TestRegisterSeat tests the RegisterSeat function
+func TestRegisterSeat(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("register seat to store", func(t *testing.T) {
+		useCase := New(nil)
+		mockRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.Seat")).Return(nil)
+
+		seat, err := useCase.RegisterSeat(ctx, "store_123", "Table 1")
+		assert.NoError(t, err)
+		assert.Equal(t, "store_123", seat.StoreID)
+		assert.Equal(t, "Table 1", seat.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("register seat without store ID", func(t *testing.T) {
+		useCase := New(nil)
+		_, err := useCase.RegisterSeat(ctx, "", "Table 1")
+		assert.ErrorIs(t, err, models.ErrStoreIDRequired)
+	})
+}
+
+// TestSeatQR tests issuing, rotating and verifying seat QR tokens
+func TestSeatQR(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("QR_SECRET", "test_qr_secret")
+
+	t.Run("issued token is verified", func(t *testing.T) {
+		useCase := New(nil)
+		seat := newTestStoreSeat()
+		mockRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		mockRepo.On("FindByID", ctx, seat.ID).Return(seat, nil)
+
+		signed, token, err := useCase.IssueSeatQRToken(ctx, seat.StoreID, seat.ID, 0)
+		require.NoError(t, err)
+		assert.False(t, token.IsDynamic())
+
+		verified, err := useCase.VerifySeatQR(ctx, signed)
+		assert.NoError(t, err)
+		assert.Equal(t, seat.ID, verified.SeatID)
+	})
+
+	t.Run("dynamic token has expiry", func(t *testing.T) {
+		useCase := New(nil)
+		seat := newTestStoreSeat()
+		mockRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		mockRepo.On("FindByID", ctx, seat.ID).Return(seat, nil)
+
+		_, token, err := useCase.IssueSeatQRToken(ctx, seat.StoreID, seat.ID, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, token.IsDynamic())
+	})
+
+	t.Run("seat of another store is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		seat := newTestStoreSeat()
+		mockRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		mockRepo.On("FindByID", ctx, seat.ID).Return(seat, nil)
+
+		_, _, err := useCase.IssueSeatQRToken(ctx, "store_other", seat.ID, 0)
+		assert.ErrorIs(t, err, models.ErrSeatStoreMismatch)
+	})
+
+	t.Run("rotation invalidates printed token", func(t *testing.T) {
+		useCase := New(nil)
+		seat := newTestStoreSeat()
+		mockRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		mockRepo.On("FindByID", ctx, seat.ID).Return(seat, nil)
+		mockRepo.On("UpdateByID", ctx, seat.ID, seat).Return(nil)
+
+		signed, _, err := useCase.IssueSeatQRToken(ctx, seat.StoreID, seat.ID, 0)
+		require.NoError(t, err)
+
+		rotated, err := useCase.RotateSeatQR(ctx, seat.StoreID, seat.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rotated.QRVersion)
+
+		_, err = useCase.VerifySeatQR(ctx, signed)
+		assert.ErrorIs(t, err, models.ErrQRTokenRotated)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("tampered token is rejected", func(t *testing.T) {
+		useCase := New(nil)
+		_, err := useCase.VerifySeatQR(ctx, "v1.e30.invalid")
+		assert.ErrorIs(t, err, models.ErrInvalidQRToken)
+	})
+}
@@ -8,6 +8,7 @@ import (
 type Session struct {
 	Store     *models.Store
 	Seat      *models.Seat
+	Token     *models.SessionToken
 	ExpiredAt time.Time
 }
 
@@ -28,7 +29,11 @@ func NewSession(storeID, seatID string, expiredAt time.Time) *Session {
 }
 
 // CreateJWT はセッション情報からJWTトークンを生成します。
+// Token が設定されている場合は、そのIDを jti として埋め込みます。
 // 戻り値: JWTトークン文字列、エラー
 func (r *Session) CreateJWT() (string, error) {
+	if r.Token != nil {
+		return r.Token.ToClaims(r.Seat).ToJwtToken()
+	}
 	return models.NewSessionClaims(r.Store, r.Seat, r.ExpiredAt).ToJwtToken()
 }
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IssueSessionToken は座席の現在の来店に紐づくセッションJWTを発行し、発行記録を保存します。
+// 空席の場合は新しい来店を開始し、来店の会計をまとめる記録を作成します。
+// 同じ空席を同時に読み込んでも来店が1つになるよう、来店の開始とトークンの発行は座席の読み取りと不可分に行います。
+func (u *UseCase) IssueSessionToken(ctx context.Context, storeID, seatID, userAgent, ipAddress string, exp time.Time) (string, *models.SessionToken, error) {
+	seat, token, err := u.seatUpdates.IssueToken(ctx, seatID, func(seat *models.Seat) (*models.SessionToken, *models.Visit, error) {
+		if seat.StoreID != storeID {
+			return nil, nil, models.ErrSeatStoreMismatch
+		}
+		var visit *models.Visit
+		if _, started := seat.StartVisit(); started {
+			visit = models.NewVisit(seat, time.Now())
+		}
+		return models.NewSessionToken(seat, userAgent, ipAddress, exp), visit, nil
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrSeatStoreMismatch) {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("failed to issue session token: %w", err)
+	}
+
+	session := NewSession(storeID, seatID, exp)
+	session.Seat = seat
+	session.Token = token
+	signed, err := session.CreateJWT()
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, token, nil
+}
+
+// ValidateSessionToken はセッションJWTが失効していないことを確認します。
+// jti を持たないトークンや発行記録のないトークンは、会計後に失効できないため拒否します。
+func (u *UseCase) ValidateSessionToken(ctx context.Context, claims *models.SessionClaims) error {
+	if claims.ID == "" {
+		return models.ErrSessionTokenNotFound
+	}
+
+	token, err := u.sessionTokenRepo.FindByID(ctx, claims.ID)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return models.ErrSessionTokenNotFound
+		}
+		return fmt.Errorf("failed to find session token: %w", err)
+	}
+	return token.Verify(claims)
+}
+
+// CloseSeat は会計・退席した座席の来店を終了し、その来店で発行したセッショントークンを失効させます。
+// 前の来店のお客様が読み取ったQRコードで新しい来店を開始できないよう、座席のQRコードも再発行します。
+// 失効させたトークンの数を返します。
+func (u *UseCase) CloseSeat(ctx context.Context, storeID, seatID string) (int, error) {
+	// 来店の終了を先に保存し、以降に発行されるトークンが閉じた来店に紐づかないようにする
+	var visitID string
+	if _, err := u.updateStoreSeat(ctx, storeID, seatID, func(seat *models.Seat) error {
+		visitID = seat.CurrentVisitID
+		seat.EndVisit()
+		seat.RotateQR()
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	// 過去の来店のトークンは閉じた時点で失効済みのため、閉じた来店のトークンのみを対象にする
+	if visitID == "" {
+		return 0, nil
+	}
+	tokens, err := u.sessionTokenRepo.FindByField(ctx, "visit_id", visitID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find session tokens: %w", err)
+	}
+
+	now := time.Now().UTC()
+	revoked := 0
+	for _, token := range tokens {
+		if token.IsRevoked() {
+			continue
+		}
+		token.Revoke(now)
+		if err := u.sessionTokenRepo.UpdateByID(ctx, token.ID, token); err != nil {
+			return revoked, fmt.Errorf("failed to revoke session token: %w", err)
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// ListSeatDevices は座席の現在の来店で有効なセッショントークン（接続中の端末）を返します。
+func (u *UseCase) ListSeatDevices(ctx context.Context, storeID, seatID string) ([]*models.SessionToken, error) {
+	seat, err := u.findStoreSeat(ctx, storeID, seatID)
+	if err != nil {
+		return nil, err
+	}
+	if seat.CurrentVisitID == "" {
+		return []*models.SessionToken{}, nil
+	}
+
+	tokens, err := u.sessionTokenRepo.FindByField(ctx, "visit_id", seat.CurrentVisitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session tokens: %w", err)
+	}
+
+	now := time.Now().UTC()
+	active := make([]*models.SessionToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.IsActive(now) {
+			active = append(active, token)
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,142 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestIssueSessionToken tests the IssueSessionToken function
+func TestIssueSessionToken(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("JWT_SECRET", "test_secret")
+
+	t.Run("first scan starts a visit", func(t *testing.T) {
+		useCase := New(nil)
+		seat := newTestStoreSeat()
+		seatRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		seatRepo.On("FindByID", ctx, seat.ID).Return(seat, nil)
+		seatRepo.On("UpdateByID", ctx, seat.ID, seat).Return(nil).Once()
+		tokenRepo := useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository)
+		tokenRepo.On("Create", ctx, mock.AnythingOfType("*models.SessionToken")).Return(nil)
+		visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+		visitRepo.On("Create", ctx, mock.AnythingOfType("*models.Visit")).Return(nil).Once()
+
+		signed, token, err := useCase.IssueSessionToken(ctx, seat.StoreID, seat.ID, "Mozilla/5.0", "192.0.2.1", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.NotEmpty(t, signed)
+		assert.NotEmpty(t, seat.CurrentVisitID)
+		assert.Equal(t, seat.CurrentVisitID, token.VisitID)
+		visit := visitRepo.Calls[0].Arguments.Get(1).(*models.Visit)
+		assert.Equal(t, seat.CurrentVisitID, visit.ID)
+		assert.Equal(t, models.VisitOpen, visit.Status)
+
+		// 同じ来店中の2台目の端末は来店を引き継ぐ
+		_, second, err := useCase.IssueSessionToken(ctx, seat.StoreID, seat.ID, "Mozilla/5.0", "192.0.2.2", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, token.VisitID, second.VisitID)
+		seatRepo.AssertNumberOfCalls(t, "UpdateByID", 1)
+		visitRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("seat of another store", func(t *testing.T) {
+		useCase := New(nil)
+		seat := newTestStoreSeat()
+		useCase.seatRepo.(*repositories.MockSeatRepository).On("FindByID", ctx, seat.ID).Return(seat, nil)
+
+		_, _, err := useCase.IssueSessionToken(ctx, "store_other", seat.ID, "", "", time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, models.ErrSeatStoreMismatch)
+	})
+}
+
+// TestValidateSessionToken tests the ValidateSessionToken function
+func TestValidateSessionToken(t *testing.T) {
+	ctx := context.Background()
+	seat := newTestStoreSeat()
+	seat.StartVisit()
+
+	t.Run("active token", func(t *testing.T) {
+		useCase := New(nil)
+		token := models.NewSessionToken(seat, "", "", time.Now().Add(time.Hour))
+		useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository).On("FindByID", ctx, token.ID).Return(token, nil)
+
+		assert.NoError(t, useCase.ValidateSessionToken(ctx, token.ToClaims(seat)))
+	})
+
+	t.Run("revoked token", func(t *testing.T) {
+		useCase := New(nil)
+		token := models.NewSessionToken(seat, "", "", time.Now().Add(time.Hour))
+		token.Revoke(time.Now().UTC())
+		useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository).On("FindByID", ctx, token.ID).Return(token, nil)
+
+		assert.ErrorIs(t, useCase.ValidateSessionToken(ctx, token.ToClaims(seat)), models.ErrSessionTokenRevoked)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository).
+			On("FindByID", ctx, "stok_unknown").Return(nil, status.Error(codes.NotFound, "not found"))
+
+		claims := &models.SessionClaims{StoreID: seat.StoreID, SeatID: seat.ID}
+		claims.ID = "stok_unknown"
+		assert.ErrorIs(t, useCase.ValidateSessionToken(ctx, claims), models.ErrSessionTokenNotFound)
+	})
+
+	t.Run("token without jti", func(t *testing.T) {
+		useCase := New(nil)
+		claims := &models.SessionClaims{StoreID: seat.StoreID, SeatID: seat.ID}
+		assert.ErrorIs(t, useCase.ValidateSessionToken(ctx, claims), models.ErrSessionTokenNotFound)
+	})
+}
+
+// TestCloseSeat tests the CloseSeat and ListSeatDevices functions
+func TestCloseSeat(t *testing.T) {
+	ctx := context.Background()
+
+	useCase := New(nil)
+	seat := newTestStoreSeat()
+	visitID, _ := seat.StartVisit()
+	active := models.NewSessionToken(seat, "phone", "192.0.2.1", time.Now().Add(time.Hour))
+	expired := models.NewSessionToken(seat, "tablet", "192.0.2.2", time.Now().Add(-time.Minute))
+	revoked := models.NewSessionToken(seat, "old", "192.0.2.3", time.Now().Add(time.Hour))
+	revoked.Revoke(time.Now().UTC())
+
+	seatRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+	seatRepo.On("FindByID", ctx, seat.ID).Return(seat, nil)
+	seatRepo.On("UpdateByID", ctx, seat.ID, seat).Return(nil)
+	tokenRepo := useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository)
+	tokenRepo.On("FindByField", ctx, "visit_id", visitID).Return([]*models.SessionToken{active, expired, revoked}, nil)
+	tokenRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.SessionToken")).Return(nil)
+
+	devices, err := useCase.ListSeatDevices(ctx, seat.StoreID, seat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.SessionToken{active}, devices)
+
+	qrVersion := seat.QRVersion
+	count, err := useCase.CloseSeat(ctx, seat.StoreID, seat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "失効済みのトークンは数えない")
+	assert.True(t, active.IsRevoked())
+	assert.True(t, expired.IsRevoked())
+	assert.Equal(t, "", seat.CurrentVisitID)
+	assert.Equal(t, qrVersion+1, seat.QRVersion, "前の来店で読み取ったQRコードを無効にする")
+	tokenRepo.AssertNotCalled(t, "FindByField", ctx, "seat_id", seat.ID)
+
+	// 来店中でない座席を閉じてもトークンを検索しない
+	count, err = useCase.CloseSeat(ctx, seat.StoreID, seat.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	tokenRepo.AssertNumberOfCalls(t, "FindByField", 2)
+
+	devices, err = useCase.ListSeatDevices(ctx, seat.StoreID, seat.ID)
+	require.NoError(t, err)
+	assert.Empty(t, devices, "閉じた座席には接続中の端末がない")
+}
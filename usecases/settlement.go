@@ -0,0 +1,180 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SettleVisit は座席の来店の会計をレジで精算します。
+// 受け取った支払い（現金・カード・QR決済・ギフトカードの併用が可能）からお釣りを計算して精算記録を作成し、
+// 来店の注文をまとめて完了にし、来店の会計を終了します。オンライン決済で支払い済みの注文は会計に含めず、完了にのみします。
+// closeSeat が true の場合は座席の来店も終了し、座席のセッショントークンを失効させます。
+// 精算記録は来店IDから決まるIDで作成するため、同時に精算した場合も1件のみ記録され、他方は ErrVisitAlreadySettled を返します。
+// オンライン決済の支払い手続き中の注文がある場合は、決済の完了か取り消しを待つため ErrPaymentInProgress を返します。
+// レジ締め済みの営業日には精算できません。
+func (u *UseCase) SettleVisit(ctx context.Context, storeID, visitID string, tenders []models.Tender, actor string, closeSeat bool) (*models.Settlement, error) {
+	now := time.Now()
+	if err := u.ensureBusinessDayOpen(ctx, storeID, now); err != nil {
+		return nil, err
+	}
+
+	settlements, err := u.settlementRepo.FindByField(ctx, "visit_id", visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find settlements: %w", err)
+	}
+	for _, s := range settlements {
+		if s.StoreID == storeID {
+			return nil, models.ErrVisitAlreadySettled
+		}
+	}
+
+	splits, err := u.billSplitRepo.FindByField(ctx, "visit_id", visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bill splits: %w", err)
+	}
+	// 割り勘で精算済みの来店は二重に会計しない。支払い前の割り勘はレジでの一括精算に切り替えるため無効にする
+	var open []*models.BillSplit
+	for _, split := range splits {
+		if split.StoreID != storeID {
+			continue
+		}
+		switch split.Status {
+		case models.BillSplitSettled:
+			return nil, models.ErrVisitAlreadySettled
+		case models.BillSplitOpen:
+			if split.HasPayments() {
+				return nil, models.ErrBillSplitInProgress
+			}
+			open = append(open, split)
+		}
+	}
+
+	visitOrders, err := u.findVisitOrders(ctx, storeID, visitID)
+	if err != nil {
+		return nil, err
+	}
+	unpaid := make([]*models.Session, 0, len(visitOrders))
+	for _, session := range visitOrders {
+		paid, err := u.isPaidOnline(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+		if paid {
+			continue
+		}
+		if session.PaymentStatus == models.PaymentStatusPending {
+			return nil, models.ErrPaymentInProgress
+		}
+		unpaid = append(unpaid, session)
+	}
+
+	check, err := models.NewCheck(visitID, unpaid)
+	if err != nil {
+		return nil, err
+	}
+	settlement, err := models.NewSettlement(check, tenders, actor, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 割り勘の無効化は伝票の支払いと同じトランザクションで判定し、同時に記録された支払いを取り消さない
+	for _, split := range open {
+		if _, err := u.billSplitUpdates.Update(ctx, split.ID, func(split *models.BillSplit, _ []*models.Session) ([]*models.Session, error) {
+			switch split.Status {
+			case models.BillSplitSettled:
+				return nil, models.ErrVisitAlreadySettled
+			case models.BillSplitOpen:
+				return nil, split.Void(now)
+			}
+			return nil, nil
+		}); err != nil {
+			if errors.Is(err, models.ErrVisitAlreadySettled) || errors.Is(err, models.ErrBillSplitInProgress) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to void bill split: %w", err)
+		}
+	}
+
+	// 注文の完了と精算記録の作成は、レジ締めの確認と同じトランザクションで行う
+	orderIDs := make([]string, len(visitOrders))
+	for i, session := range visitOrders {
+		orderIDs[i] = session.ID
+	}
+	if err := u.settlements.Record(ctx, businessDayCloseID(storeID, now), settlement, func(sessions []*models.Session) ([]*models.Session, error) {
+		var completed []*models.Session
+		for _, session := range sessions {
+			if !slices.Contains(orderIDs, session.ID) {
+				continue
+			}
+			changed, err := session.CompleteBySettlement(actor)
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				completed = append(completed, session)
+			}
+		}
+		return completed, nil
+	}); err != nil {
+		if repositories.IsAlreadyExists(err) {
+			return nil, models.ErrVisitAlreadySettled
+		}
+		if errors.Is(err, models.ErrPaymentInProgress) || errors.Is(err, models.ErrBusinessDayClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record settlement: %w", err)
+	}
+	if err := u.closeVisit(ctx, storeID, visitID, settlement.ID, now); err != nil {
+		return settlement, err
+	}
+
+	if closeSeat {
+		seat, err := u.findStoreSeat(ctx, storeID, settlement.SeatID)
+		if err != nil {
+			return settlement, err
+		}
+		// 座席がすでに次の来店に使われている場合は閉じない
+		if seat.CurrentVisitID == visitID {
+			if _, err := u.CloseSeat(ctx, storeID, seat.ID); err != nil {
+				return settlement, err
+			}
+		}
+	}
+	return settlement, nil
+}
+
+// GetVisitSettlement は店舗の来店の精算記録を返します。
+func (u *UseCase) GetVisitSettlement(ctx context.Context, storeID, visitID string) (*models.Settlement, error) {
+	settlements, err := u.settlementRepo.FindByField(ctx, "visit_id", visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find settlements: %w", err)
+	}
+	for _, s := range settlements {
+		if s.StoreID == storeID {
+			return s, nil
+		}
+	}
+	return nil, models.ErrSettlementNotFound
+}
+
+// isPaidOnline は注文がオンライン決済で支払い済みかどうかを返します。
+func (u *UseCase) isPaidOnline(ctx context.Context, session *models.Session) (bool, error) {
+	if u.paymentProvider == nil {
+		return false, nil
+	}
+	payments, err := u.paymentRepo.FindByField(ctx, "session_id", session.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find payments: %w", err)
+	}
+	for _, payment := range payments {
+		if payment.Status == models.PaymentIntentSucceeded {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestSettleVisit tests the SettleVisit function
+func TestSettleVisit(t *testing.T) {
+	ctx := context.Background()
+	mixed := []models.Tender{
+		{Method: models.TenderCard, Amount: models.Yen(2000), Reference: "auth_1"},
+		{Method: models.TenderCash, Amount: models.Yen(2000)},
+	}
+
+	setup := func(t *testing.T, sessions []*models.Session, settlements []*models.Settlement) *UseCase {
+		useCase := New(nil)
+		useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+		for _, session := range sessions {
+			sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		}
+		sessionRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.Session")).Return(nil)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.BillSplit{}, nil)
+		settlementRepo := useCase.settlementRepo.(*repositories.MockSettlementRepository)
+		settlementRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(settlements, nil)
+		settlementRepo.On("Create", ctx, mock.AnythingOfType("*models.Settlement")).Return(nil)
+		visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+		visitRepo.On("FindByID", ctx, "visit_1").Return(&models.Visit{ID: "visit_1", StoreID: "store_1", Status: models.VisitOpen}, nil)
+		visitRepo.On("UpdateByID", ctx, "visit_1", mock.AnythingOfType("*models.Visit")).Return(nil)
+		return useCase
+	}
+
+	t.Run("mixed tenders complete every order of the visit", func(t *testing.T) {
+		sessions := newBillSplitTestSessions(t)
+		sessions[0].Status = models.StatusServed
+		useCase := setup(t, sessions, nil)
+
+		settlement, err := useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "manager:a@example.com", false)
+		require.NoError(t, err)
+		assert.Equal(t, models.Yen(3300), settlement.Total)
+		assert.Equal(t, models.Yen(700), settlement.Change)
+		assert.Equal(t, models.StatusCompleted, sessions[0].Status)
+		assert.Equal(t, models.StatusCompleted, sessions[1].Status)
+		assert.Equal(t, models.PaymentStatusPaid, sessions[0].PaymentStatus)
+		assert.Equal(t, models.PaymentStatusPaid, sessions[1].PaymentStatus)
+		assert.Equal(t, models.StatusCreated, sessions[2].Status, "他店舗の注文は変更しない")
+		useCase.settlementRepo.(*repositories.MockSettlementRepository).AssertCalled(t, "Create", ctx, settlement)
+		visit := useCase.visitRepo.(*repositories.MockVisitRepository).Calls[1].Arguments.Get(2).(*models.Visit)
+		assert.Equal(t, models.VisitClosed, visit.Status, "来店の会計を終了する")
+		assert.Equal(t, settlement.ID, visit.SettlementID)
+	})
+
+	t.Run("orders paid online are excluded from the check", func(t *testing.T) {
+		sessions := newBillSplitTestSessions(t)
+		useCase := setup(t, sessions, nil)
+		useCase.SetPaymentProvider(repositories.NewFakePaymentProvider(""))
+		paymentRepo := useCase.paymentRepo.(*repositories.MockPaymentRepository)
+		paymentRepo.On("FindByField", ctx, "session_id", sessions[0].ID).Return([]*models.Payment{{ID: "pay_1", Status: models.PaymentIntentSucceeded}}, nil)
+		paymentRepo.On("FindByField", ctx, "session_id", mock.Anything).Return([]*models.Payment{}, nil)
+
+		settlement, err := useCase.SettleVisit(ctx, "store_1", "visit_1", []models.Tender{{Method: models.TenderCash, Amount: models.Yen(1100)}}, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{sessions[1].ID}, settlement.SessionIDs)
+		assert.True(t, settlement.Change.IsZero())
+		assert.Equal(t, models.StatusCompleted, sessions[0].Status, "支払い済みの注文も完了にする")
+	})
+
+	t.Run("orders in online payment are not settled", func(t *testing.T) {
+		sessions := newBillSplitTestSessions(t)
+		require.NoError(t, sessions[0].StartPayment())
+		useCase := setup(t, sessions, nil)
+		useCase.SetPaymentProvider(repositories.NewFakePaymentProvider(""))
+		useCase.paymentRepo.(*repositories.MockPaymentRepository).On("FindByField", ctx, "session_id", mock.Anything).Return([]*models.Payment{{ID: "pay_1", Status: models.PaymentIntentRequiresPayment}}, nil)
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "", false)
+		assert.ErrorIs(t, err, models.ErrPaymentInProgress)
+		assert.Equal(t, models.PaymentStatusPending, sessions[0].PaymentStatus)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		useCase.settlementRepo.(*repositories.MockSettlementRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("close the seat of the visit", func(t *testing.T) {
+		sessions := newBillSplitTestSessions(t)
+		useCase := setup(t, sessions, nil)
+		seat := &models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}
+		seatRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		seatRepo.On("FindByID", ctx, "seat_1").Return(seat, nil)
+		seatRepo.On("UpdateByID", ctx, "seat_1", seat).Return(nil)
+		useCase.sessionTokenRepo.(*repositories.MockSessionTokenRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.SessionToken{}, nil)
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "", true)
+		require.NoError(t, err)
+		assert.Equal(t, "", seat.CurrentVisitID)
+	})
+
+	t.Run("insufficient tender changes nothing", func(t *testing.T) {
+		sessions := newBillSplitTestSessions(t)
+		useCase := setup(t, sessions, nil)
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", []models.Tender{{Method: models.TenderCash, Amount: models.Yen(3000)}}, "", false)
+		assert.ErrorIs(t, err, models.ErrInsufficientTender)
+		assert.Equal(t, models.StatusCreated, sessions[0].Status)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("visit settled concurrently", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessions := newBillSplitTestSessions(t)
+		sessionRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+		for _, session := range sessions {
+			sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		}
+		sessionRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.Session")).Return(nil)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.BillSplit{}, nil)
+		settlementRepo := useCase.settlementRepo.(*repositories.MockSettlementRepository)
+		settlementRepo.On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.Settlement{}, nil)
+		settlementRepo.On("Create", ctx, mock.MatchedBy(func(s *models.Settlement) bool {
+			return s.ID == models.SettlementIDFor("visit_1")
+		})).Return(status.Error(codes.AlreadyExists, "already exists"))
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "", false)
+		assert.ErrorIs(t, err, models.ErrVisitAlreadySettled, "他のリクエストが先に精算した")
+		useCase.visitRepo.(*repositories.MockVisitRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("bill split paid concurrently rejects the settlement", func(t *testing.T) {
+		useCase := New(nil)
+		useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository).On("Exists", ctx, mock.Anything).Return(false, nil)
+		sessions := newBillSplitTestSessions(t)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+		check, err := models.NewCheck("visit_1", sessions[:2])
+		require.NoError(t, err)
+		split, err := models.NewBillSplit(check, models.SplitRequest{Method: models.SplitEven, Parts: 2}, time.Now())
+		require.NoError(t, err)
+		// 精算の読み取り後に割り勘の支払いが記録された
+		paid := *split
+		paid.SubBills = append([]models.SubBill(nil), split.SubBills...)
+		_, err = paid.MarkSubBillPaid(paid.SubBills[0].ID, models.TenderCard, "pay_1", time.Now())
+		require.NoError(t, err)
+		splitRepo := useCase.billSplitRepo.(*repositories.MockBillSplitRepository)
+		splitRepo.On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.BillSplit{split}, nil)
+		splitRepo.On("FindByID", ctx, split.ID).Return(&paid, nil)
+		useCase.settlementRepo.(*repositories.MockSettlementRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.Settlement{}, nil)
+
+		_, err = useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "", false)
+		assert.ErrorIs(t, err, models.ErrBillSplitInProgress)
+		assert.Equal(t, models.BillSplitOpen, paid.Status, "記録された支払いを取り消さない")
+		assert.True(t, paid.HasPayments())
+		splitRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("business day closed during the settlement", func(t *testing.T) {
+		useCase := New(nil)
+		// 精算の開始時はレジ締め前で、精算の記録までにレジ締めが作成された
+		closeRepo := useCase.registerCloseRepo.(*repositories.MockRegisterCloseRepository)
+		closeRepo.On("Exists", ctx, mock.Anything).Return(false, nil).Once()
+		closeRepo.On("Exists", ctx, mock.Anything).Return(true, nil)
+		sessions := newBillSplitTestSessions(t)
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+		useCase.billSplitRepo.(*repositories.MockBillSplitRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.BillSplit{}, nil)
+		useCase.settlementRepo.(*repositories.MockSettlementRepository).On("FindByField", ctx, "visit_id", "visit_1").Return([]*models.Settlement{}, nil)
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "", false)
+		assert.ErrorIs(t, err, models.ErrBusinessDayClosed)
+		assert.Equal(t, models.StatusCreated, sessions[0].Status)
+		sessionRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		useCase.settlementRepo.(*repositories.MockSettlementRepository).AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("visit already settled", func(t *testing.T) {
+		useCase := setup(t, newBillSplitTestSessions(t), []*models.Settlement{{ID: "settle_1", StoreID: "store_1", VisitID: "visit_1"}})
+
+		_, err := useCase.SettleVisit(ctx, "store_1", "visit_1", mixed, "", false)
+		assert.ErrorIs(t, err, models.ErrVisitAlreadySettled)
+	})
+}
@@ -12,13 +12,82 @@ type UseCase struct {
 	sessionRepo repositories.Repository[models.Session]
 	seatRepo    repositories.Repository[models.Seat]
 	storeRepo   repositories.Repository[models.Store]
+
+	sessionTokenRepo  repositories.Repository[models.SessionToken]
+	apiKeyRepo        repositories.Repository[models.APIKey]
+	receiptRepo       repositories.Repository[models.Receipt]
+	promotionRepo     repositories.Repository[models.Promotion]
+	redemptionRepo    repositories.Repository[models.PromotionRedemption]
+	billSplitRepo     repositories.Repository[models.BillSplit]
+	paymentRepo       repositories.Repository[models.Payment]
+	paymentEventRepo  repositories.Repository[models.PaymentEvent]
+	settlementRepo    repositories.Repository[models.Settlement]
+	registerCloseRepo repositories.Repository[models.RegisterClose]
+	visitRepo         repositories.Repository[models.Visit]
+
+	// 決済代行会社。設定されていない場合はオンライン決済を利用できません。
+	paymentProvider repositories.PaymentProvider
+
+	loginAttempts repositories.LoginAttemptStore
+	lockoutPolicy models.LockoutPolicy
+
+	receiptIssuer repositories.ReceiptIssuer
+	leases        repositories.LeaseStore
+
+	promotionUsages  repositories.PromotionUsageStore
+	sessionUpdates   repositories.SessionUpdater
+	visitUpdates     repositories.VisitUpdater
+	seatUpdates      repositories.SeatUpdater
+	billSplitUpdates repositories.BillSplitUpdater
+	settlements      repositories.SettlementRecorder
+	registerCloses   repositories.RegisterCloser
+	expiredSessions  repositories.ExpiredSessionFinder
+	statusMigrator   repositories.SessionStatusMigrator
 }
 
 func New(db *firestore.Client) *UseCase {
+	sessionRepo := repositories.NewSessionRepository(db)
+	redemptionRepo := repositories.NewPromotionRedemptionRepository(db)
+	visitRepo := repositories.NewVisitRepository(db)
+	seatRepo := repositories.NewSeatRepository(db)
+	sessionTokenRepo := repositories.NewSessionTokenRepository(db)
+	receiptRepo := repositories.NewReceiptRepository(db)
+	billSplitRepo := repositories.NewBillSplitRepository(db)
+	paymentRepo := repositories.NewPaymentRepository(db)
+	settlementRepo := repositories.NewSettlementRepository(db)
+	registerCloseRepo := repositories.NewRegisterCloseRepository(db)
 	return &UseCase{
 		managerRepo: repositories.NewManagerRepository(db),
-		sessionRepo: repositories.NewSessionRepository(db),
-		seatRepo:    repositories.NewSeatRepository(db),
+		sessionRepo: sessionRepo,
+		seatRepo:    seatRepo,
 		storeRepo:   repositories.NewStoreRepository(db),
+
+		sessionTokenRepo:  sessionTokenRepo,
+		apiKeyRepo:        repositories.NewAPIKeyRepository(db),
+		receiptRepo:       receiptRepo,
+		promotionRepo:     repositories.NewPromotionRepository(db),
+		redemptionRepo:    redemptionRepo,
+		billSplitRepo:     billSplitRepo,
+		paymentRepo:       paymentRepo,
+		paymentEventRepo:  repositories.NewPaymentEventRepository(db),
+		settlementRepo:    settlementRepo,
+		registerCloseRepo: registerCloseRepo,
+		visitRepo:         visitRepo,
+
+		loginAttempts: repositories.NewLoginAttemptStore(db),
+		lockoutPolicy: models.DefaultLockoutPolicy(),
+
+		receiptIssuer: repositories.NewReceiptIssuer(db, receiptRepo),
+		leases:        repositories.NewLeaseStore(db),
+
+		promotionUsages:  repositories.NewPromotionUsageStore(db, redemptionRepo),
+		sessionUpdates:   repositories.NewSessionUpdater(db, sessionRepo, registerCloseRepo),
+		visitUpdates:     repositories.NewVisitUpdater(db, visitRepo, sessionRepo),
+		seatUpdates:      repositories.NewSeatUpdater(db, seatRepo, visitRepo, sessionTokenRepo),
+		billSplitUpdates: repositories.NewBillSplitUpdater(db, billSplitRepo, sessionRepo, registerCloseRepo),
+		settlements:      repositories.NewSettlementRecorder(db, settlementRepo, sessionRepo, registerCloseRepo),
+		registerCloses:   repositories.NewRegisterCloser(db, registerCloseRepo, sessionRepo, settlementRepo, billSplitRepo, paymentRepo),
+		expiredSessions:  repositories.NewExpiredSessionFinder(db, sessionRepo),
+		statusMigrator:   repositories.NewSessionStatusMigrator(db, sessionRepo),
 	}
 }
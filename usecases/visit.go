@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// findVisit は店舗の来店を返します。他店舗の来店は存在しないものとして扱います。
+func (u *UseCase) findVisit(ctx context.Context, storeID, visitID string) (*models.Visit, error) {
+	visit, err := u.visitRepo.FindByID(ctx, visitID)
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrVisitNotFound
+		}
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit.StoreID != storeID {
+		return nil, models.ErrVisitNotFound
+	}
+	return visit, nil
+}
+
+// addVisitRound は注文（追加注文の1回分）を来店に追加し、注文の人数に来店人数を設定します。
+// 会計が済んだ来店には追加できません。
+func addVisitRound(visit *models.Visit, session *models.Session) error {
+	session.PartySize = visit.PartySize
+	return visit.AddRound(session)
+}
+
+// updateStoreVisit は店舗の来店を読み取り、update で変更して保存します。他店舗の来店は存在しないものとして扱います。
+// 追加注文や来店人数の登録と同時に更新しても互いの変更を上書きしないよう、読み取りと保存は不可分に行います。
+func (u *UseCase) updateStoreVisit(ctx context.Context, storeID, visitID string, update func(*models.Visit) error) (*models.Visit, error) {
+	visit, err := u.visitUpdates.Update(ctx, visitID, func(visit *models.Visit) error {
+		if visit.StoreID != storeID {
+			return models.ErrVisitNotFound
+		}
+		return update(visit)
+	})
+	if err != nil {
+		if repositories.IsNotFound(err) {
+			return nil, models.ErrVisitNotFound
+		}
+		return nil, err
+	}
+	return visit, nil
+}
+
+// closeVisit は会計の精算により来店を終了します。来店の記録がない場合は何もしません。
+func (u *UseCase) closeVisit(ctx context.Context, storeID, visitID, settlementID string, now time.Time) error {
+	_, err := u.updateStoreVisit(ctx, storeID, visitID, func(visit *models.Visit) error {
+		visit.Close(settlementID, now)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrVisitNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to close visit: %w", err)
+	}
+	return nil
+}
+
+// GetVisitCheck は店舗の来店の現在の会計（追加注文ごとの注文と合計金額の途中経過）を返します。
+func (u *UseCase) GetVisitCheck(ctx context.Context, storeID, visitID string) (*models.VisitCheck, error) {
+	visit, err := u.findVisit(ctx, storeID, visitID)
+	if err != nil {
+		return nil, err
+	}
+	visitOrders, err := u.findVisitOrders(ctx, storeID, visit.ID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewVisitCheck(visit, visitOrders)
+}
+
+// SetVisitPartySize はスタッフが確認した来店人数を登録し、来店の注文の人数分のチャージを再計算します。
+// 支払い手続き中・支払い済みの注文の金額は変更しません。
+func (u *UseCase) SetVisitPartySize(ctx context.Context, storeID, visitID string, size int) (*models.VisitCheck, error) {
+	visit, err := u.updateStoreVisit(ctx, storeID, visitID, func(visit *models.Visit) error {
+		return visit.SetPartySize(size)
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrVisitNotFound) || errors.Is(err, models.ErrInvalidPartySize) || errors.Is(err, models.ErrVisitClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update visit: %w", err)
+	}
+	visitOrders, err := u.findVisitOrders(ctx, storeID, visit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, order := range visitOrders {
+		updated, err := u.updateStoreSession(ctx, storeID, order.ID, func(order *models.Session) error {
+			return order.SetPartySize(size)
+		})
+		if err != nil {
+			if errors.Is(err, models.ErrChargeNotAllowed) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to update order: %w", err)
+		}
+		visitOrders[i] = updated
+	}
+	return models.NewVisitCheck(visit, visitOrders)
+}
+
+// GetSeatVisitCheck は座席の現在の来店の会計を返します。着座中でない場合は ErrVisitNotFound を返します。
+func (u *UseCase) GetSeatVisitCheck(ctx context.Context, storeID, seatID string) (*models.VisitCheck, error) {
+	seat, err := u.findStoreSeat(ctx, storeID, seatID)
+	if err != nil {
+		return nil, err
+	}
+	if seat.CurrentVisitID == "" {
+		return nil, models.ErrVisitNotFound
+	}
+	return u.GetVisitCheck(ctx, storeID, seat.CurrentVisitID)
+}
+
+// GetCustomerVisitCheck はお客様の来店の会計を返します。他の座席の来店は存在しないものとして扱います。
+func (u *UseCase) GetCustomerVisitCheck(ctx context.Context, storeID, seatID, visitID string) (*models.VisitCheck, error) {
+	check, err := u.GetVisitCheck(ctx, storeID, visitID)
+	if err != nil {
+		return nil, err
+	}
+	if check.Visit.SeatID != seatID {
+		return nil, models.ErrVisitNotFound
+	}
+	return check, nil
+}
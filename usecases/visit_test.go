@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"backend/models"
+	"backend/repositories"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestGetVisitCheck tests the GetVisitCheck, GetSeatVisitCheck and GetCustomerVisitCheck functions
+func TestGetVisitCheck(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*UseCase, []*models.Session) {
+		useCase := New(nil)
+		sessions := newBillSplitTestSessions(t)
+		useCase.sessionRepo.(*repositories.MockSessionRepository).On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+		visit := &models.Visit{ID: "visit_1", StoreID: "store_1", SeatID: "seat_1", Status: models.VisitOpen, PartySize: 2}
+		visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+		visitRepo.On("FindByID", ctx, "visit_1").Return(visit, nil)
+		visitRepo.On("FindByID", ctx, "visit_unknown").Return(nil, status.Error(codes.NotFound, "not found"))
+		return useCase, sessions
+	}
+
+	t.Run("running total of every round", func(t *testing.T) {
+		useCase, sessions := setup(t)
+
+		check, err := useCase.GetVisitCheck(ctx, "store_1", "visit_1")
+		require.NoError(t, err)
+		require.Len(t, check.Rounds, 2, "他店舗の注文は含めない")
+		assert.Equal(t, sessions[0].ID, check.Rounds[0].ID)
+		assert.Equal(t, models.Yen(3300), check.Total)
+		assert.Equal(t, models.Yen(3300), check.Balance)
+		assert.Equal(t, 2, check.Visit.PartySize)
+	})
+
+	t.Run("visit of another store", func(t *testing.T) {
+		useCase, _ := setup(t)
+
+		_, err := useCase.GetVisitCheck(ctx, "store_2", "visit_1")
+		assert.ErrorIs(t, err, models.ErrVisitNotFound)
+		_, err = useCase.GetVisitCheck(ctx, "store_1", "visit_unknown")
+		assert.ErrorIs(t, err, models.ErrVisitNotFound)
+	})
+
+	t.Run("current visit of the seat", func(t *testing.T) {
+		useCase, _ := setup(t)
+		seatRepo := useCase.seatRepo.(*repositories.MockSeatRepository)
+		seatRepo.On("FindByID", ctx, "seat_1").Return(&models.Seat{ID: "seat_1", StoreID: "store_1", CurrentVisitID: "visit_1"}, nil)
+		seatRepo.On("FindByID", ctx, "seat_2").Return(&models.Seat{ID: "seat_2", StoreID: "store_1"}, nil)
+
+		check, err := useCase.GetSeatVisitCheck(ctx, "store_1", "seat_1")
+		require.NoError(t, err)
+		assert.Equal(t, "visit_1", check.Visit.ID)
+
+		_, err = useCase.GetSeatVisitCheck(ctx, "store_1", "seat_2")
+		assert.ErrorIs(t, err, models.ErrVisitNotFound, "空席")
+	})
+
+	t.Run("customer of another seat", func(t *testing.T) {
+		useCase, _ := setup(t)
+
+		_, err := useCase.GetCustomerVisitCheck(ctx, "store_1", "seat_1", "visit_1")
+		assert.NoError(t, err)
+		_, err = useCase.GetCustomerVisitCheck(ctx, "store_1", "seat_9", "visit_1")
+		assert.ErrorIs(t, err, models.ErrVisitNotFound)
+	})
+}
+
+// TestSetVisitPartySize tests the SetVisitPartySize function
+func TestSetVisitPartySize(t *testing.T) {
+	ctx := context.Background()
+	rules := []models.ChargeRule{{ID: "chrule_otoshi", Name: "お通し", Kind: models.ChargePerPerson, Value: 300, Active: true}}
+
+	setup := func(t *testing.T) (*UseCase, []*models.Session) {
+		useCase := New(nil)
+		sessions := newBillSplitTestSessions(t)
+		require.NoError(t, sessions[0].ApplyChargeRules(rules, nil, sessions[0].IssuedAt))
+		require.NoError(t, sessions[1].UpdatePaymentStatus(models.PaymentStatusPaid, "", "オンライン決済"))
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.On("FindByField", ctx, "visit_id", "visit_1").Return(sessions, nil)
+		for _, session := range sessions {
+			sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+		}
+		sessionRepo.On("UpdateByID", ctx, mock.Anything, mock.AnythingOfType("*models.Session")).Return(nil)
+		visitRepo := useCase.visitRepo.(*repositories.MockVisitRepository)
+		visitRepo.On("FindByID", ctx, "visit_1").Return(&models.Visit{ID: "visit_1", StoreID: "store_1", SeatID: "seat_1", Status: models.VisitOpen}, nil)
+		visitRepo.On("UpdateByID", ctx, "visit_1", mock.AnythingOfType("*models.Visit")).Return(nil)
+		return useCase, sessions
+	}
+
+	t.Run("recalculate per person charges", func(t *testing.T) {
+		useCase, sessions := setup(t)
+
+		check, err := useCase.SetVisitPartySize(ctx, "store_1", "visit_1", 3)
+		require.NoError(t, err)
+		assert.Equal(t, 3, check.Visit.PartySize)
+		assert.Equal(t, models.Yen(900), sessions[0].ChargeTotal())
+
+		sessionRepo := useCase.sessionRepo.(*repositories.MockSessionRepository)
+		sessionRepo.AssertCalled(t, "UpdateByID", ctx, sessions[0].ID, sessions[0])
+		sessionRepo.AssertNotCalled(t, "UpdateByID", ctx, sessions[1].ID, mock.Anything)
+	})
+
+	t.Run("invalid party size", func(t *testing.T) {
+		useCase, _ := setup(t)
+
+		_, err := useCase.SetVisitPartySize(ctx, "store_1", "visit_1", 0)
+		assert.ErrorIs(t, err, models.ErrInvalidPartySize)
+		useCase.visitRepo.(*repositories.MockVisitRepository).AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}